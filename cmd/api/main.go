@@ -6,23 +6,41 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/airquality/luchtmeetnet"
 	"github.com/breatheroute/breatheroute/internal/api"
+	"github.com/breatheroute/breatheroute/internal/api/handler"
 	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/audit"
 	"github.com/breatheroute/breatheroute/internal/auth"
 	"github.com/breatheroute/breatheroute/internal/commute"
 	"github.com/breatheroute/breatheroute/internal/database"
 	"github.com/breatheroute/breatheroute/internal/device"
+	"github.com/breatheroute/breatheroute/internal/exposure"
 	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/pollen"
+	"github.com/breatheroute/breatheroute/internal/pollen/ambee"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
 	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+	"github.com/breatheroute/breatheroute/internal/routestore"
 	"github.com/breatheroute/breatheroute/internal/routing"
 	"github.com/breatheroute/breatheroute/internal/routing/openrouteservice"
 	"github.com/breatheroute/breatheroute/internal/telemetry"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/transit/ns"
 	"github.com/breatheroute/breatheroute/internal/user"
+	"github.com/breatheroute/breatheroute/internal/weather"
+	"github.com/breatheroute/breatheroute/internal/weather/openweathermap"
+	"github.com/breatheroute/breatheroute/internal/webhook"
 )
 
 // Version and BuildTime are set at compile time via ldflags.
@@ -99,20 +117,32 @@ func main() {
 
 	// Connect to database
 	dbConfig := database.ConfigFromEnv()
-	pool, err := database.Connect(ctx, dbConfig)
+	db, err := database.Open(ctx, dbConfig)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to connect to database")
 	}
-	defer pool.Close()
+	defer db.Close()
+	pool := db.Pool()
 	log.Info().
 		Str("host", dbConfig.Host).
 		Int("port", dbConfig.Port).
 		Str("database", dbConfig.Database).
+		Int("max_conns", dbConfig.MaxConns).
+		Int("min_conns", dbConfig.MinConns).
+		Str("read_host", dbConfig.ReadHost).
 		Msg("database connected")
 
+	poolMetrics, err := database.RegisterPoolMetrics(pool)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to register database pool metrics")
+	} else {
+		defer func() { _ = poolMetrics.Unregister() }()
+	}
+
 	// Initialize auth repositories and service
 	authUserRepo := auth.NewPostgresUserRepository(pool)
 	authRefreshRepo := auth.NewPostgresRefreshTokenRepository(pool)
+	authIdentityRepo := auth.NewPostgresIdentityRepository(pool)
 
 	// Initialize JWT service (get signing key from environment)
 	jwtSigningKey := os.Getenv("JWT_SIGNING_KEY")
@@ -122,8 +152,17 @@ func main() {
 	}
 
 	jwtService := auth.NewJWTService(auth.JWTConfig{
-		SigningKey: jwtSigningKey,
+		SigningKey:      jwtSigningKey,
+		SigningKeyID:    os.Getenv("JWT_SIGNING_KEY_ID"),
+		PreviousKeys:    parseKeySet(os.Getenv("JWT_PREVIOUS_KEYS")),
+		AccessTokenTTL:  envDuration(log, "JWT_ACCESS_TOKEN_TTL"),
+		RefreshTokenTTL: envDuration(log, "JWT_REFRESH_TOKEN_TTL"),
 	})
+	log.Info().
+		Str("signing_key_id", jwtService.SigningKeyID()).
+		Dur("access_token_ttl", jwtService.AccessTokenTTL()).
+		Dur("refresh_token_ttl", jwtService.RefreshTokenTTL()).
+		Msg("JWT service initialized")
 
 	// Initialize SIWA verifier (may be nil if not configured)
 	var siwaVerifier *auth.SIWAVerifier
@@ -138,11 +177,13 @@ func main() {
 	}
 
 	authService := auth.NewService(auth.ServiceConfig{
-		SIWAVerifier:  siwaVerifier,
-		JWTService:    jwtService,
-		UserRepo:      authUserRepo,
-		RefreshRepo:   authRefreshRepo,
-		DefaultLocale: "nl-NL",
+		SIWAVerifier:   siwaVerifier,
+		JWTService:     jwtService,
+		UserRepo:       authUserRepo,
+		RefreshRepo:    authRefreshRepo,
+		IdentityRepo:   authIdentityRepo,
+		DefaultLocale:  "nl-NL",
+		AdminAppleSubs: parseList(os.Getenv("ADMIN_APPLE_SUBS")),
 	})
 	log.Info().Msg("auth service initialized")
 
@@ -152,7 +193,7 @@ func main() {
 	log.Info().Msg("user service initialized")
 
 	// Initialize commute repository and service
-	commuteRepo := commute.NewPostgresRepository(pool)
+	commuteRepo := commute.NewPostgresRepository(pool).WithReader(db.ReadPool())
 	commuteService := commute.NewService(commuteRepo)
 	log.Info().Msg("commute service initialized")
 
@@ -172,6 +213,12 @@ func main() {
 
 	// Initialize provider registry for health tracking
 	providerRegistry := resilience.GlobalRegistry
+	providerMetrics, err := resilience.RegisterMetrics(providerRegistry)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to register provider metrics")
+	} else {
+		defer func() { _ = providerMetrics.Unregister() }()
+	}
 
 	// Initialize routing provider (OpenRouteService)
 	orsAPIKey := os.Getenv("OPENROUTESERVICE_API_KEY")
@@ -186,20 +233,188 @@ func main() {
 	})
 	log.Info().Msg("OpenRouteService client initialized")
 
+	// Services listed here serve expired cache entries while refreshing them
+	revalidate := parseList(os.Getenv("CACHE_STALE_WHILE_REVALIDATE"))
+
 	// Initialize routing service with caching
 	routingService := routing.NewService(routing.ServiceConfig{
-		Provider: orsClient,
-		Logger:   log,
+		Provider:             orsClient,
+		Logger:               log,
+		FeatureFlags:         ffService,
+		StaleWhileRevalidate: slices.Contains(revalidate, "routing"),
 		// Using defaults: 5min cache TTL, 15min stale-if-error, 0.01° grid
 	})
 	log.Info().Msg("routing service initialized")
 
+	// Provider names by data domain, reported by /v1/ops/health and status
+	providerNames := map[string]string{"routing": openrouteservice.ProviderName}
+
+	// Initialize air quality service (Luchtmeetnet requires no API key)
+	interpolationMetrics, err := airquality.NewInterpolationMetrics()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize interpolation metrics")
+	}
+	// Persist provider snapshots across restarts (optional)
+	snapshotStore := newSnapshotStore(log, os.Getenv("CACHE_SNAPSHOT_DIR"))
+
+	// Initialize weather service (optional)
+	var weatherService *weather.Service
+	if owmAPIKey := os.Getenv("OPENWEATHERMAP_API_KEY"); owmAPIKey != "" {
+		weatherService = weather.NewService(weather.ServiceConfig{
+			Provider: openweathermap.NewClient(openweathermap.ClientConfig{
+				APIKey:   owmAPIKey,
+				Registry: providerRegistry,
+				Logger:   log,
+			}),
+			Logger:               log,
+			FeatureFlags:         ffService,
+			StaleWhileRevalidate: slices.Contains(revalidate, "weather"),
+		})
+		providerNames["weather"] = openweathermap.ProviderName
+		log.Info().Msg("weather service initialized")
+	} else {
+		log.Warn().Msg("OPENWEATHERMAP_API_KEY not set - weather data disabled")
+	}
+
+	// Archive hourly air quality snapshots next to them, so interpolated
+	// values can report their trend over the last hour
+	var aqHistory *airquality.History
+	if _, persisted := snapshotStore.(*cache.FileStore); persisted {
+		aqHistory = airquality.NewHistory(snapshotStore)
+	}
+
+	aqStaleTTL := envDuration(log, "AIRQUALITY_STALE_TTL")
+	aqProvider, aqProviderName := newAirQualityProvider(log, providerRegistry, aqStaleTTL)
+	aqService := airquality.NewService(airquality.ServiceConfig{
+		Provider:        aqProvider,
+		Logger:          log,
+		StaleIfErrorTTL: aqStaleTTL,
+		Interpolation: airquality.InterpolationConfig{
+			Metrics: interpolationMetrics,
+		},
+		Store:                snapshotStore,
+		History:              aqHistory,
+		Weather:              weatherService,
+		StaleWhileRevalidate: slices.Contains(revalidate, "airquality"),
+	})
+	providerNames["airquality"] = aqProviderName
+	log.Info().Msg("air quality service initialized")
+
+	// Initialize pollen service (optional)
+	var pollenService *pollen.Service
+	if ambeeAPIKey := os.Getenv("AMBEE_API_KEY"); ambeeAPIKey != "" {
+		exposureFactors, err := pollen.ParseExposureFactors(os.Getenv("POLLEN_EXPOSURE_FACTORS"))
+		if err != nil {
+			log.Warn().Err(err).Msg("invalid POLLEN_EXPOSURE_FACTORS, using defaults")
+			exposureFactors = nil
+		}
+		pollenService = pollen.NewService(pollen.ServiceConfig{
+			Provider: ambee.NewClient(ambee.ClientConfig{
+				APIKey:   ambeeAPIKey,
+				Registry: providerRegistry,
+				Logger:   log,
+			}),
+			FeatureFlags:         ffService,
+			Logger:               log,
+			StaleWhileRevalidate: slices.Contains(revalidate, "pollen"),
+			ExposureFactors:      exposureFactors,
+		})
+		providerNames["pollen"] = ambee.ProviderName
+		log.Info().Msg("pollen service initialized")
+	} else {
+		log.Warn().Msg("AMBEE_API_KEY not set - pollen data disabled")
+	}
+
+	// Initialize webhook service for disruption change notifications
+	webhookService := webhook.NewService(webhook.ServiceConfig{
+		Repository: webhook.NewPostgresRepository(pool),
+		Logger:     log,
+	})
+	log.Info().Msg("webhook service initialized")
+
+	// Initialize audit service for changes to user resources
+	auditService := audit.NewService(audit.ServiceConfig{
+		Repository: audit.NewPostgresRepository(pool),
+		Logger:     log,
+	})
+	log.Info().Msg("audit service initialized")
+
+	// Initialize exposure service; estimates are written by the worker
+	exposureService := exposure.NewService(exposure.ServiceConfig{
+		Repository: exposure.NewPostgresRepository(pool),
+	})
+	log.Info().Msg("exposure service initialized")
+
+	// Initialize route store so computed routes can be fetched and exported
+	routeStore := routestore.NewService(routestore.ServiceConfig{
+		Repository: routestore.NewPostgresRepository(pool),
+	})
+	log.Info().Msg("route store initialized")
+
+	// Initialize transit service (optional)
+	var transitService *transit.Service
+	if nsAPIKey := os.Getenv("NS_API_KEY"); nsAPIKey != "" {
+		transitService = transit.NewService(transit.ServiceConfig{
+			Provider: ns.NewClient(ns.ClientConfig{
+				APIKey:   nsAPIKey,
+				BaseURL:  os.Getenv("NS_API_URL"),
+				Registry: providerRegistry,
+				Logger:   log,
+			}),
+			Logger:   log,
+			Notifier: webhookService,
+			Store:    snapshotStore,
+		})
+		providerNames["transit"] = ns.ProviderName
+		log.Info().Msg("transit service initialized")
+
+		// Refresh disruptions in the background while anyone listens, so SSE
+		// subscribers and webhooks hear about changes without requests
+		// driving the cache
+		go transitService.WatchChanges(ctx)
+	} else {
+		log.Warn().Msg("NS_API_KEY not set - transit disruptions disabled")
+	}
+
+	// Restore persisted snapshots before serving, then warm in the background
+	// so startup is not delayed
+	restoreSnapshots(ctx, log, aqService, transitService)
+	if os.Getenv("APP_WARM_CACHE") == "true" {
+		go warmCaches(ctx, log, aqService, weatherService, pollenService)
+	}
+
 	// Check for development mode (enables /auth/dev endpoint)
 	devMode := os.Getenv("AUTH_DEV_MODE") == "true"
 	if devMode {
 		log.Warn().Msg("AUTH_DEV_MODE is enabled - /v1/auth/dev endpoint active - DO NOT USE IN PRODUCTION")
 	}
 
+	// Subsystems that gate /v1/ops/ready
+	readinessChecks := selectReadinessChecks(log, os.Getenv("READINESS_CHECKS"), map[string]handler.ReadinessCheckFunc{
+		"database":   pool.Ping,
+		"airquality": aqService.CheckReady,
+	})
+
+	// Routes whose exposure estimate relies on distant stations are flagged,
+	// and optionally ranked last
+	exposureConfidence := handler.ExposureConfidenceConfig{
+		DemoteLow: os.Getenv("ROUTE_DEMOTE_LOW_CONFIDENCE") == "true",
+	}
+
+	// How old each cache's newest data may get before the system status
+	// reports it as stale
+	staleThresholds := parseDurations(log, os.Getenv("CACHE_STALE_THRESHOLDS"))
+
+	// Straight-line estimates keep the app usable while the routing
+	// provider is down
+	estimateRoutes := os.Getenv("ROUTE_ESTIMATE_WHEN_UNAVAILABLE") == "true"
+
+	// Browser origins allowed to call the API; none by default
+	cors := middleware.CORSConfig{
+		AllowedOrigins:   parseList(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		AllowCredentials: os.Getenv("CORS_ALLOW_CREDENTIALS") == "true",
+	}
+
 	// Create router with configuration
 	router := api.NewRouter(api.RouterConfig{
 		Version:            Version,
@@ -213,8 +428,24 @@ func main() {
 		CommuteService:     commuteService,
 		DeviceService:      deviceService,
 		RoutingService:     routingService,
+		TransitService:     transitService,
+		AirQualityService:  aqService,
+		WeatherService:     weatherService,
+		PollenService:      pollenService,
+		WebhookService:     webhookService,
 		ProviderRegistry:   providerRegistry,
+		ProviderNames:      providerNames,
+		AuditService:       auditService,
+		ExposureService:    exposureService,
+		RouteStore:         routeStore,
+		ExposureConfidence: exposureConfidence,
+		ReadinessChecks:    readinessChecks,
+		DatabasePoolStats:  poolStats(pool),
+		CORS:               cors,
 		DevMode:            devMode,
+
+		CacheStaleThresholds:          staleThresholds,
+		EstimateRoutesWhenUnavailable: estimateRoutes,
 	})
 
 	// Create HTTP server
@@ -255,3 +486,119 @@ func main() {
 
 	log.Info().Msg("server stopped")
 }
+
+// newAirQualityProvider returns the Luchtmeetnet client and its name. When
+// AIRQUALITY_FALLBACK_API_URL is set, the client is chained to a secondary
+// Luchtmeetnet-compatible feed at that URL, used when Luchtmeetnet fails or
+// serves data older than staleTTL, the service's stale-if-error TTL.
+func newAirQualityProvider(log zerolog.Logger, registry *resilience.Registry, staleTTL time.Duration) (airquality.Provider, string) {
+	primary := luchtmeetnet.NewClient(luchtmeetnet.ClientConfig{
+		BaseURL:  os.Getenv("LUCHTMEETNET_API_URL"),
+		Registry: registry,
+	})
+	fallbackURL := os.Getenv("AIRQUALITY_FALLBACK_API_URL")
+	if fallbackURL == "" {
+		return primary, luchtmeetnet.ProviderName
+	}
+
+	secondary := luchtmeetnet.NewClient(luchtmeetnet.ClientConfig{
+		BaseURL:  fallbackURL,
+		Name:     luchtmeetnet.FallbackProviderName,
+		Registry: registry,
+	})
+	log.Info().Msg("air quality fallback provider enabled")
+	return airquality.NewChainProvider(primary, secondary).WithStaleThreshold(staleTTL).WithLogger(log),
+		luchtmeetnet.ProviderName + "+" + luchtmeetnet.FallbackProviderName
+}
+
+// envDuration parses a duration environment variable such as "15m".
+// Unset or invalid values return 0 so the service default applies.
+func envDuration(log zerolog.Logger, name string) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Warn().Str("variable", name).Str("value", raw).Msg("invalid duration, using default")
+		return 0
+	}
+	return d
+}
+
+// poolStats reports the connection pool's usage for the system status.
+func poolStats(pool *pgxpool.Pool) handler.PoolStatsFunc {
+	return func() models.DatabasePool {
+		stat := pool.Stat()
+		return models.DatabasePool{
+			AcquiredConns: stat.AcquiredConns(),
+			IdleConns:     stat.IdleConns(),
+			TotalConns:    stat.TotalConns(),
+			MaxConns:      stat.MaxConns(),
+		}
+	}
+}
+
+// selectReadinessChecks returns the available checks named in raw, a
+// comma-separated list such as "database,airquality". Unset enables every
+// check and "none" disables them all. Unknown names are logged and ignored.
+func selectReadinessChecks(log zerolog.Logger, raw string, available map[string]handler.ReadinessCheckFunc) map[string]handler.ReadinessCheckFunc {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return available
+	}
+
+	selected := make(map[string]handler.ReadinessCheckFunc)
+	if raw == "none" {
+		return selected
+	}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		check, ok := available[name]
+		if !ok {
+			log.Warn().Str("check", name).Msg("unknown readiness check, ignoring")
+			continue
+		}
+		selected[name] = check
+	}
+	return selected
+}
+
+// parseKeySet parses "kid=key,kid=key" into a map of verification keys by ID.
+func parseKeySet(raw string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		kid, key, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && kid != "" && key != "" {
+			keys[kid] = key
+		}
+	}
+	return keys
+}
+
+// parseDurations parses "name=duration,name=duration" into durations by
+// name, logging and skipping entries that are not valid durations.
+func parseDurations(log zerolog.Logger, raw string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, pair := range parseList(raw) {
+		name, value, _ := strings.Cut(pair, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			log.Warn().Str("entry", pair).Msg("ignoring invalid duration")
+			continue
+		}
+		durations[strings.TrimSpace(name)] = d
+	}
+	return durations
+}
+
+// parseList parses a comma-separated list, dropping empty items.
+func parseList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/pollen"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/weather"
+	"github.com/breatheroute/breatheroute/internal/worker"
+)
+
+// cacheWarmTimeout bounds how long startup cache warming may run.
+const cacheWarmTimeout = 2 * time.Minute
+
+// warmCaches pre-fetches provider data for the default refresh targets so the
+// first users after a deploy hit warm caches. Services may be nil if not configured.
+// Failures are logged and never fatal; the caches fill on demand instead.
+func warmCaches(
+	ctx context.Context,
+	log zerolog.Logger,
+	aqService *airquality.Service,
+	weatherService *weather.Service,
+	pollenService *pollen.Service,
+) {
+	ctx, cancel := context.WithTimeout(ctx, cacheWarmTimeout)
+	defer cancel()
+
+	start := time.Now()
+	targets := worker.DefaultRefreshConfig().AllPoints()
+	points := make([]struct{ Lat, Lon float64 }, len(targets))
+	for i, p := range targets {
+		points[i] = struct{ Lat, Lon float64 }{Lat: p.Lat, Lon: p.Lon}
+	}
+
+	log.Info().Int("points", len(points)).Msg("warming provider caches")
+
+	if aqService != nil {
+		if err := aqService.WarmCache(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to warm air quality cache")
+		}
+	}
+	if weatherService != nil {
+		if err := weatherService.WarmCache(ctx, points); err != nil {
+			log.Warn().Err(err).Msg("failed to warm weather cache for some points")
+		}
+	}
+	if pollenService != nil {
+		if err := pollenService.WarmCache(ctx, points); err != nil {
+			log.Warn().Err(err).Msg("failed to warm pollen cache for some points")
+		}
+	}
+
+	log.Info().
+		Dur("duration", time.Since(start)).
+		Msg("provider cache warming complete")
+}
+
+// newSnapshotStore returns a file store in dir, or a no-op store if dir is
+// empty or cannot be created.
+func newSnapshotStore(log zerolog.Logger, dir string) cache.SnapshotStore {
+	if dir == "" {
+		return cache.NopStore{}
+	}
+	store, err := cache.NewFileStore(dir)
+	if err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("snapshot store unavailable - provider caches start cold")
+		return cache.NopStore{}
+	}
+	log.Info().Str("dir", dir).Msg("provider snapshots persisted to disk")
+	return store
+}
+
+// restoreSnapshots loads provider data persisted by the previous process.
+// Services may be nil if not configured. Missing or stale snapshots are
+// expected and only logged.
+func restoreSnapshots(ctx context.Context, log zerolog.Logger, aqService *airquality.Service, transitService *transit.Service) {
+	logRestore := func(name string, err error) {
+		switch {
+		case err == nil:
+		case errors.Is(err, cache.ErrSnapshotNotFound), errors.Is(err, cache.ErrSnapshotStale):
+			log.Debug().Err(err).Str("snapshot", name).Msg("no usable snapshot to restore")
+		default:
+			log.Warn().Err(err).Str("snapshot", name).Msg("failed to restore snapshot")
+		}
+	}
+
+	if aqService != nil {
+		logRestore("airquality", aqService.RestoreSnapshot(ctx))
+	}
+	if transitService != nil {
+		logRestore("transit-stations", transitService.RestoreStations(ctx))
+	}
+}
@@ -7,10 +7,35 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/airquality/luchtmeetnet"
+	"github.com/breatheroute/breatheroute/internal/auth"
+	"github.com/breatheroute/breatheroute/internal/commute"
+	"github.com/breatheroute/breatheroute/internal/database"
+	"github.com/breatheroute/breatheroute/internal/exposure"
+	"github.com/breatheroute/breatheroute/internal/pollen"
+	"github.com/breatheroute/breatheroute/internal/pollen/ambee"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/transit/ns"
+	"github.com/breatheroute/breatheroute/internal/weather"
+	"github.com/breatheroute/breatheroute/internal/weather/openweathermap"
+	"github.com/breatheroute/breatheroute/internal/worker"
 )
 
+// defaultDrainTimeout bounds how long shutdown waits for in-flight jobs and
+// refreshes. With the cancel grace it stays under the 10 seconds Cloud Run
+// allows between SIGTERM and SIGKILL.
+const defaultDrainTimeout = 8 * time.Second
+
 // Version and BuildTime are set at compile time via ldflags.
 var (
 	Version   = "dev"
@@ -56,36 +81,74 @@ func main() {
 		}
 	}()
 
-	// Start worker loop
+	log := zerolog.New(os.Stdout).
+		With().
+		Timestamp().
+		Str("service", "breatheroute-worker").
+		Str("version", Version).
+		Logger()
+
+	// Commutes drive the exposure job and the commute refresh target
+	pool := connectDatabase(ctx, log)
+	if pool != nil {
+		defer pool.Close()
+	}
+
+	// Archive hourly air quality for the exposure job
+	aqHistory := newAirQualityHistory(log, os.Getenv("CACHE_SNAPSHOT_DIR"))
+	exposureJob := newExposureJob(log, aqHistory, pool)
+
+	// Start refresh scheduler. With Pub/Sub configured it is the fallback
+	// that keeps caches warm when no refresh messages arrive.
+	refreshJob := newRefreshJob(log, aqHistory, pool)
+	scheduler := worker.NewScheduler(worker.SchedulerConfig{
+		Job:      refreshJob,
+		Exposure: exposureJob,
+		Logger:   log,
+	})
+	schedulerDone := make(chan struct{})
 	go func() {
-		fmt.Println("Worker started, waiting for messages...")
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				fmt.Println("Worker context canceled")
-				return
-			case <-ticker.C:
-				fmt.Println("Worker heartbeat...")
-				// TODO: Process Pub/Sub messages
-				// TODO: Handle provider refresh jobs
-				// TODO: Handle alert evaluation jobs
-			}
-		}
+		defer close(schedulerDone)
+		scheduler.Run(ctx)
 	}()
 
+	// Consume job messages. GDPR deletions need the database.
+	var accounts worker.AccountDeleter
+	if pool != nil {
+		accounts = auth.NewPostgresUserRepository(pool)
+	}
+	consumersDone, cancelJobs := startJobConsumers(ctx, log, refreshJob, accounts)
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	fmt.Println("Shutting down worker...")
+
+	// Stop scheduling and receiving messages, then let in-flight jobs and
+	// refreshes finish so their results and snapshots are written before
+	// exiting. Both share one drain deadline.
+	drainTimeout := envDuration(log, "WORKER_DRAIN_TIMEOUT")
+	if drainTimeout == 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+	drainDeadline := time.Now().Add(drainTimeout)
 	cancel()
+	<-schedulerDone
+	select {
+	case <-consumersDone:
+	case <-time.After(drainTimeout):
+		fmt.Println("Job drain timed out; in-flight jobs were canceled")
+		cancelJobs()
+		<-consumersDone
+	}
+	if !scheduler.WaitForCompletion(time.Until(drainDeadline)) {
+		fmt.Println("Refresh drain timed out; in-flight refreshes were canceled")
+	}
 
 	// Graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
@@ -94,3 +157,270 @@ func main() {
 
 	fmt.Println("Worker stopped")
 }
+
+// startJobConsumers dispatches job messages from each subscription in
+// PUBSUB_SUBSCRIPTIONS until ctx is canceled. Messages that fail
+// repeatedly, are not a known job, or are a known job without a handler
+// here, such as alert evaluation and GDPR export, are published to
+// PUBSUB_DEAD_LETTER_TOPIC if set.
+// Canceling ctx stops receiving messages; jobs in flight keep running. The
+// returned channel is closed once every consumer and its jobs have stopped,
+// and the returned function cancels the jobs still in flight.
+func startJobConsumers(ctx context.Context, log zerolog.Logger, refreshJob *worker.RefreshJob, accounts worker.AccountDeleter) (<-chan struct{}, func()) {
+	done := make(chan struct{})
+	var dispatchers []*worker.Dispatcher
+	cancelJobs := func() {
+		for _, dispatcher := range dispatchers {
+			dispatcher.CancelRuns()
+		}
+	}
+	projectID := os.Getenv("PUBSUB_PROJECT")
+	subscriptions := parseList(os.Getenv("PUBSUB_SUBSCRIPTIONS"))
+	if projectID == "" || len(subscriptions) == 0 {
+		log.Info().Msg("PUBSUB_PROJECT or PUBSUB_SUBSCRIPTIONS not set - running scheduled refreshes only")
+		close(done)
+		return done, cancelJobs
+	}
+	deadLetterTopic := os.Getenv("PUBSUB_DEAD_LETTER_TOPIC")
+
+	var wg sync.WaitGroup
+	for _, subscription := range subscriptions {
+		subLog := log.With().Str("subscription", subscription).Logger()
+
+		source, err := worker.NewPubSubSource(ctx, worker.PubSubConfig{
+			ProjectID:        projectID,
+			SubscriptionName: subscription,
+			Logger:           subLog,
+		})
+		if err != nil {
+			subLog.Error().Err(err).Msg("pubsub source unavailable - subscription not consumed")
+			continue
+		}
+
+		cfg := worker.DispatcherConfig{Logger: subLog}
+		var deadLetter *worker.PubSubDeadLetter
+		if deadLetterTopic != "" {
+			deadLetter, err = worker.NewPubSubDeadLetter(ctx, projectID, deadLetterTopic, subscription)
+			if err != nil {
+				subLog.Warn().Err(err).Msg("dead letter topic unavailable - failed messages will be logged and dropped")
+			} else {
+				cfg.DeadLetter = deadLetter
+			}
+		}
+
+		dispatcher := worker.NewDispatcher(cfg).
+			Handle(worker.JobTypeProviderRefresh, worker.ProviderRefreshHandler(refreshJob, subLog)).
+			Handle(worker.JobTypeHealthCheck, worker.HealthCheckHandler(refreshJob, subLog))
+		if accounts != nil {
+			dispatcher.Handle(worker.JobTypeGDPRDeletion, worker.GDPRDeletionHandler(accounts, subLog))
+		}
+		subLog.Info().Strs("job_types", dispatcher.Handled()).Msg("consuming job messages")
+		dispatchers = append(dispatchers, dispatcher)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { _ = source.Close() }()
+			if deadLetter != nil {
+				defer func() { _ = deadLetter.Close() }()
+			}
+			if err := dispatcher.Run(ctx, source); err != nil {
+				subLog.Error().Err(err).Msg("pubsub consumer stopped")
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done, cancelJobs
+}
+
+// envDuration parses a duration environment variable such as "15s".
+// Unset or invalid values return 0 so the default applies.
+func envDuration(log zerolog.Logger, name string) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Warn().Str("variable", name).Str("value", raw).Msg("invalid duration, using default")
+		return 0
+	}
+	return d
+}
+
+// loadRefreshTargets returns the refresh targets from REFRESH_TARGETS_FILE
+// (JSON or YAML) or REFRESH_TARGETS (JSON), or nil to use the defaults when
+// neither is set or the targets are invalid.
+func loadRefreshTargets(log zerolog.Logger) []worker.RefreshTarget {
+	var targets []worker.RefreshTarget
+	var err error
+	source := "REFRESH_TARGETS_FILE"
+	if path := os.Getenv("REFRESH_TARGETS_FILE"); path != "" {
+		targets, err = worker.LoadRefreshTargets(path)
+	} else if raw := os.Getenv("REFRESH_TARGETS"); raw != "" {
+		source = "REFRESH_TARGETS"
+		targets, err = worker.ParseRefreshTargets([]byte(raw))
+	} else {
+		return nil
+	}
+
+	if err != nil {
+		log.Error().Err(err).Str("source", source).Msg("refresh targets not loaded - using default targets")
+		return nil
+	}
+	log.Info().Str("source", source).Int("targets", len(targets)).Msg("loaded refresh targets")
+	return targets
+}
+
+// parseList parses a comma-separated list, dropping empty items.
+func parseList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// newAirQualityProvider returns the Luchtmeetnet client, chained to a
+// secondary Luchtmeetnet-compatible feed when AIRQUALITY_FALLBACK_API_URL is
+// set. The chain treats data older than staleTTL, the service's
+// stale-if-error TTL, as stale.
+func newAirQualityProvider(log zerolog.Logger, staleTTL time.Duration) airquality.Provider {
+	primary := luchtmeetnet.NewClient(luchtmeetnet.ClientConfig{
+		BaseURL: os.Getenv("LUCHTMEETNET_API_URL"),
+	})
+	fallbackURL := os.Getenv("AIRQUALITY_FALLBACK_API_URL")
+	if fallbackURL == "" {
+		return primary
+	}
+
+	secondary := luchtmeetnet.NewClient(luchtmeetnet.ClientConfig{
+		BaseURL: fallbackURL,
+		Name:    luchtmeetnet.FallbackProviderName,
+	})
+	log.Info().Msg("air quality fallback provider enabled")
+	return airquality.NewChainProvider(primary, secondary).WithStaleThreshold(staleTTL).WithLogger(log)
+}
+
+// newAirQualityHistory returns an air quality archive in dir, or nil if dir is
+// empty or cannot be created.
+func newAirQualityHistory(log zerolog.Logger, dir string) *airquality.History {
+	if dir == "" {
+		log.Warn().Msg("CACHE_SNAPSHOT_DIR not set - exposure estimates disabled")
+		return nil
+	}
+	store, err := cache.NewFileStore(dir)
+	if err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("air quality history unavailable - exposure estimates disabled")
+		return nil
+	}
+	return airquality.NewHistory(store)
+}
+
+// connectDatabase connects to the database, or returns nil if it is
+// unreachable.
+func connectDatabase(ctx context.Context, log zerolog.Logger) *pgxpool.Pool {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	pool, err := database.Connect(connectCtx, database.ConfigFromEnv())
+	if err != nil {
+		log.Warn().Err(err).Msg("database unavailable - exposure estimates and commute refresh points disabled")
+		return nil
+	}
+	return pool
+}
+
+// newExposureJob creates the commute exposure job, or returns nil if there is
+// no air quality history or database.
+func newExposureJob(log zerolog.Logger, history *airquality.History, pool *pgxpool.Pool) *worker.ExposureJob {
+	if history == nil || pool == nil {
+		return nil
+	}
+
+	return worker.NewExposureJob(worker.ExposureJobConfig{
+		Commutes: commute.NewPostgresRepository(pool),
+		Trips:    exposure.NewPostgresRepository(pool),
+		History:  history,
+		Logger:   log,
+	})
+}
+
+// newRefreshJob creates the provider refresh job from environment configuration.
+// Providers without an API key are left unconfigured and skipped during refresh.
+// Refreshed air quality snapshots are archived in history, if not nil. With a
+// database, the points along users' commutes are refreshed as well.
+func newRefreshJob(log zerolog.Logger, history *airquality.History, pool *pgxpool.Pool) *worker.RefreshJob {
+	refreshConfig := worker.DefaultRefreshConfig()
+	if targets := loadRefreshTargets(log); targets != nil {
+		refreshConfig.Targets = targets
+	}
+	if os.Getenv("REFRESH_DRY_RUN") == "true" {
+		refreshConfig.DryRun = true
+		log.Warn().Msg("REFRESH_DRY_RUN is enabled - providers will not be called")
+	}
+
+	aqStaleTTL := envDuration(log, "AIRQUALITY_STALE_TTL")
+	cfg := worker.RefreshJobConfig{
+		Config: refreshConfig,
+		Logger: log,
+		// Luchtmeetnet requires no API key
+		AirQualityService: airquality.NewService(airquality.ServiceConfig{
+			Provider:        newAirQualityProvider(log, aqStaleTTL),
+			Logger:          log,
+			History:         history,
+			StaleIfErrorTTL: aqStaleTTL,
+		}),
+	}
+
+	if pool != nil {
+		cfg.CommutePoints = worker.NewCommutePoints(worker.CommutePointsConfig{
+			Commutes: commute.NewPostgresRepository(pool),
+			Logger:   log,
+		})
+	}
+
+	if apiKey := os.Getenv("OPENWEATHERMAP_API_KEY"); apiKey != "" {
+		cfg.WeatherService = weather.NewService(weather.ServiceConfig{
+			Provider: openweathermap.NewClient(openweathermap.ClientConfig{
+				APIKey: apiKey,
+				Logger: log,
+			}),
+			Logger: log,
+		})
+	} else {
+		log.Warn().Msg("OPENWEATHERMAP_API_KEY not set - weather refresh disabled")
+	}
+
+	if apiKey := os.Getenv("AMBEE_API_KEY"); apiKey != "" {
+		cfg.PollenService = pollen.NewService(pollen.ServiceConfig{
+			Provider: ambee.NewClient(ambee.ClientConfig{
+				APIKey: apiKey,
+				Logger: log,
+			}),
+			Logger: log,
+		})
+	} else {
+		log.Warn().Msg("AMBEE_API_KEY not set - pollen refresh disabled")
+	}
+
+	if apiKey := os.Getenv("NS_API_KEY"); apiKey != "" {
+		cfg.TransitService = transit.NewService(transit.ServiceConfig{
+			Provider: ns.NewClient(ns.ClientConfig{
+				APIKey:  apiKey,
+				BaseURL: os.Getenv("NS_API_URL"),
+				Logger:  log,
+			}),
+			Logger: log,
+		})
+	} else {
+		log.Warn().Msg("NS_API_KEY not set - transit refresh disabled")
+	}
+
+	return worker.NewRefreshJob(cfg)
+}
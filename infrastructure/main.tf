@@ -286,6 +286,11 @@ module "cloud_run_worker" {
     DB_PORT        = "5432"
     DB_NAME        = module.cloud_sql.database_name
     PUBSUB_PROJECT = var.project_id
+
+    # Alert evaluation and GDPR export have no worker handlers yet, so
+    # their subscriptions are not consumed
+    PUBSUB_SUBSCRIPTIONS     = "${module.pubsub.provider_refresh_subscription_name},${module.pubsub.gdpr_deletion_subscription_name}"
+    PUBSUB_DEAD_LETTER_TOPIC = module.pubsub.dead_letter_topic_name
   }
 
   secret_env_vars = {
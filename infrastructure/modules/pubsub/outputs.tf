@@ -42,3 +42,18 @@ output "dead_letter_topic_id" {
   description = "Dead letter topic ID"
   value       = google_pubsub_topic.dead_letter.id
 }
+
+output "dead_letter_topic_name" {
+  description = "Dead letter topic name"
+  value       = google_pubsub_topic.dead_letter.name
+}
+
+output "provider_refresh_subscription_name" {
+  description = "Provider refresh subscription name"
+  value       = google_pubsub_subscription.provider_refresh.name
+}
+
+output "gdpr_deletion_subscription_name" {
+  description = "GDPR deletion subscription name"
+  value       = google_pubsub_subscription.gdpr_deletion.name
+}
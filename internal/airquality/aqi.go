@@ -0,0 +1,88 @@
+package airquality
+
+// AQICategory is a European Air Quality Index (EAQI) band.
+type AQICategory string
+
+const (
+	AQIGood          AQICategory = "GOOD"
+	AQIFair          AQICategory = "FAIR"
+	AQIModerate      AQICategory = "MODERATE"
+	AQIPoor          AQICategory = "POOR"
+	AQIVeryPoor      AQICategory = "VERY_POOR"
+	AQIExtremelyPoor AQICategory = "EXTREMELY_POOR"
+	AQIUnknown       AQICategory = "UNKNOWN"
+)
+
+// European Air Quality Index breakpoints in µg/m³ (EEA, hourly concentrations).
+// Each constant is the inclusive upper bound of its band: a value exactly at a
+// breakpoint belongs to the lower (better) band. Values above the Very Poor
+// bound are Extremely Poor.
+const (
+	EAQINO2GoodMax     = 40.0
+	EAQINO2FairMax     = 90.0
+	EAQINO2ModerateMax = 120.0
+	EAQINO2PoorMax     = 230.0
+	EAQINO2VeryPoorMax = 340.0
+
+	EAQIPM25GoodMax     = 10.0
+	EAQIPM25FairMax     = 20.0
+	EAQIPM25ModerateMax = 25.0
+	EAQIPM25PoorMax     = 50.0
+	EAQIPM25VeryPoorMax = 75.0
+
+	EAQIPM10GoodMax     = 20.0
+	EAQIPM10FairMax     = 40.0
+	EAQIPM10ModerateMax = 50.0
+	EAQIPM10PoorMax     = 100.0
+	EAQIPM10VeryPoorMax = 150.0
+
+	EAQIO3GoodMax     = 50.0
+	EAQIO3FairMax     = 100.0
+	EAQIO3ModerateMax = 130.0
+	EAQIO3PoorMax     = 240.0
+	EAQIO3VeryPoorMax = 380.0
+)
+
+// aqiBands lists categories from best to worst, matching the breakpoint order.
+var aqiBands = []AQICategory{AQIGood, AQIFair, AQIModerate, AQIPoor, AQIVeryPoor}
+
+// aqiBreakpoints maps each pollutant to its band upper bounds.
+var aqiBreakpoints = map[Pollutant][]float64{
+	PollutantNO2:  {EAQINO2GoodMax, EAQINO2FairMax, EAQINO2ModerateMax, EAQINO2PoorMax, EAQINO2VeryPoorMax},
+	PollutantPM25: {EAQIPM25GoodMax, EAQIPM25FairMax, EAQIPM25ModerateMax, EAQIPM25PoorMax, EAQIPM25VeryPoorMax},
+	PollutantPM10: {EAQIPM10GoodMax, EAQIPM10FairMax, EAQIPM10ModerateMax, EAQIPM10PoorMax, EAQIPM10VeryPoorMax},
+	PollutantO3:   {EAQIO3GoodMax, EAQIO3FairMax, EAQIO3ModerateMax, EAQIO3PoorMax, EAQIO3VeryPoorMax},
+}
+
+// CategoryFor returns the EAQI category for a pollutant concentration in µg/m³.
+// Returns AQIUnknown for pollutants without EAQI breakpoints.
+func CategoryFor(pollutant Pollutant, value float64) AQICategory {
+	breakpoints, ok := aqiBreakpoints[pollutant]
+	if !ok {
+		return AQIUnknown
+	}
+	for idx, upper := range breakpoints {
+		if value <= upper {
+			return aqiBands[idx]
+		}
+	}
+	return AQIExtremelyPoor
+}
+
+// severity returns the rank of a category (higher is worse, -1 for unknown).
+func (c AQICategory) severity() int {
+	if c == AQIExtremelyPoor {
+		return len(aqiBands)
+	}
+	for idx, band := range aqiBands {
+		if band == c {
+			return idx
+		}
+	}
+	return -1
+}
+
+// WorseThan reports whether c is a worse category than other.
+func (c AQICategory) WorseThan(other AQICategory) bool {
+	return c.severity() > other.severity()
+}
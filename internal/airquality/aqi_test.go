@@ -0,0 +1,78 @@
+package airquality_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+func TestCategoryFor_Boundaries(t *testing.T) {
+	tests := []struct {
+		name      string
+		pollutant airquality.Pollutant
+		value     float64
+		want      airquality.AQICategory
+	}{
+		{"NO2 zero", airquality.PollutantNO2, 0, airquality.AQIGood},
+		{"NO2 at good bound", airquality.PollutantNO2, airquality.EAQINO2GoodMax, airquality.AQIGood},
+		{"NO2 just above good bound", airquality.PollutantNO2, airquality.EAQINO2GoodMax + 0.01, airquality.AQIFair},
+		{"NO2 at fair bound", airquality.PollutantNO2, airquality.EAQINO2FairMax, airquality.AQIFair},
+		{"NO2 at moderate bound", airquality.PollutantNO2, airquality.EAQINO2ModerateMax, airquality.AQIModerate},
+		{"NO2 at poor bound", airquality.PollutantNO2, airquality.EAQINO2PoorMax, airquality.AQIPoor},
+		{"NO2 at very poor bound", airquality.PollutantNO2, airquality.EAQINO2VeryPoorMax, airquality.AQIVeryPoor},
+		{"NO2 above very poor bound", airquality.PollutantNO2, airquality.EAQINO2VeryPoorMax + 0.01, airquality.AQIExtremelyPoor},
+
+		{"PM25 at good bound", airquality.PollutantPM25, airquality.EAQIPM25GoodMax, airquality.AQIGood},
+		{"PM25 just above moderate bound", airquality.PollutantPM25, airquality.EAQIPM25ModerateMax + 0.01, airquality.AQIPoor},
+		{"PM25 at very poor bound", airquality.PollutantPM25, airquality.EAQIPM25VeryPoorMax, airquality.AQIVeryPoor},
+
+		{"PM10 at fair bound", airquality.PollutantPM10, airquality.EAQIPM10FairMax, airquality.AQIFair},
+		{"PM10 just above poor bound", airquality.PollutantPM10, airquality.EAQIPM10PoorMax + 0.01, airquality.AQIVeryPoor},
+
+		{"O3 at moderate bound", airquality.PollutantO3, airquality.EAQIO3ModerateMax, airquality.AQIModerate},
+		{"O3 far above scale", airquality.PollutantO3, 1000, airquality.AQIExtremelyPoor},
+
+		{"unknown pollutant", airquality.Pollutant("SO2"), 10, airquality.AQIUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, airquality.CategoryFor(tt.pollutant, tt.value))
+		})
+	}
+}
+
+func TestAQICategory_WorseThan(t *testing.T) {
+	assert.True(t, airquality.AQIPoor.WorseThan(airquality.AQIFair))
+	assert.True(t, airquality.AQIExtremelyPoor.WorseThan(airquality.AQIVeryPoor))
+	assert.True(t, airquality.AQIGood.WorseThan(airquality.AQIUnknown))
+	assert.False(t, airquality.AQIGood.WorseThan(airquality.AQIGood))
+}
+
+func TestInterpolate_AQICategory(t *testing.T) {
+	snapshot := airquality.NewAQSnapshot("test")
+	snapshot.Stations["NL10001"] = &airquality.Station{
+		ID:         "NL10001",
+		Lat:        52.370216,
+		Lon:        4.895168,
+		Pollutants: []airquality.Pollutant{airquality.PollutantNO2, airquality.PollutantPM25},
+	}
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID: "NL10001", Pollutant: airquality.PollutantNO2, Value: 35, MeasuredAt: time.Now(),
+	})
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID: "NL10001", Pollutant: airquality.PollutantPM25, Value: 22, MeasuredAt: time.Now(),
+	})
+
+	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+	result, err := interpolator.Interpolate(52.370216, 4.895168, snapshot)
+	require.NoError(t, err)
+
+	assert.Equal(t, airquality.AQIGood, result.Values[airquality.PollutantNO2].AQICategory)
+	assert.Equal(t, airquality.AQIModerate, result.Values[airquality.PollutantPM25].AQICategory)
+	assert.Equal(t, airquality.AQIModerate, result.AQICategory, "overall category should be the worst pollutant")
+}
@@ -0,0 +1,178 @@
+package airquality
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultChainStaleThreshold is how old the primary provider's newest measurement
+// may be before the secondary provider is consulted.
+const DefaultChainStaleThreshold = 30 * time.Minute
+
+// ChainProvider is a Provider that falls back to a secondary provider when the
+// primary fails or returns stale data. When both providers return data, stations
+// are merged by ID and the fresher measurement wins.
+type ChainProvider struct {
+	primary        Provider
+	secondary      Provider
+	staleThreshold time.Duration
+	logger         zerolog.Logger
+}
+
+// NewChainProvider creates a provider chain trying primary first, then secondary.
+func NewChainProvider(primary, secondary Provider) *ChainProvider {
+	return &ChainProvider{
+		primary:        primary,
+		secondary:      secondary,
+		staleThreshold: DefaultChainStaleThreshold,
+		logger:         zerolog.Nop(),
+	}
+}
+
+// WithStaleThreshold sets how old primary data may be before the secondary is consulted.
+// This should normally match the service's StaleIfErrorTTL.
+func (c *ChainProvider) WithStaleThreshold(d time.Duration) *ChainProvider {
+	if d > 0 {
+		c.staleThreshold = d
+	}
+	return c
+}
+
+// WithLogger sets the logger used to report which provider served the data.
+func (c *ChainProvider) WithLogger(logger zerolog.Logger) *ChainProvider {
+	c.logger = logger
+	return c
+}
+
+// FetchSnapshot fetches a snapshot from the primary provider, consulting the
+// secondary when the primary fails or its newest measurement is stale.
+func (c *ChainProvider) FetchSnapshot(ctx context.Context) (*AQSnapshot, error) {
+	primary, primaryErr := c.primary.FetchSnapshot(ctx)
+	if primaryErr == nil && !c.isStale(primary) {
+		c.logServed(primary)
+		return primary, nil
+	}
+
+	if primaryErr != nil {
+		c.logger.Warn().Err(primaryErr).Msg("primary air quality provider failed, trying secondary")
+	} else {
+		c.logger.Warn().
+			Str("provider", primary.Provider).
+			Time("latest_measurement", primary.LatestMeasurementAt()).
+			Msg("primary air quality data is stale, trying secondary")
+	}
+
+	secondary, secondaryErr := c.secondary.FetchSnapshot(ctx)
+	switch {
+	case secondaryErr != nil && primaryErr != nil:
+		return nil, fmt.Errorf("all air quality providers failed: primary: %v, secondary: %w", primaryErr, secondaryErr)
+	case secondaryErr != nil:
+		// Stale primary data is better than nothing; the service decides whether to serve it.
+		c.logger.Warn().Err(secondaryErr).Msg("secondary air quality provider failed, using stale primary data")
+		c.logServed(primary)
+		return primary, nil
+	case primaryErr != nil:
+		secondary.Fallback = true
+		c.logServed(secondary)
+		return secondary, nil
+	}
+
+	merged := mergeSnapshots(primary, secondary)
+	c.logServed(merged)
+	return merged, nil
+}
+
+// FetchStations fetches stations from the primary provider, falling back to the secondary on error.
+func (c *ChainProvider) FetchStations(ctx context.Context) ([]*Station, error) {
+	stations, err := c.primary.FetchStations(ctx)
+	if err == nil {
+		return stations, nil
+	}
+	c.logger.Warn().Err(err).Msg("primary air quality provider failed to fetch stations, trying secondary")
+	return c.secondary.FetchStations(ctx)
+}
+
+// FetchLatestMeasurements fetches measurements from the primary provider,
+// falling back to the secondary on error.
+func (c *ChainProvider) FetchLatestMeasurements(ctx context.Context) ([]*Measurement, error) {
+	measurements, err := c.primary.FetchLatestMeasurements(ctx)
+	if err == nil {
+		return measurements, nil
+	}
+	c.logger.Warn().Err(err).Msg("primary air quality provider failed to fetch measurements, trying secondary")
+	return c.secondary.FetchLatestMeasurements(ctx)
+}
+
+// isStale reports whether the snapshot's newest measurement is older than the stale threshold.
+func (c *ChainProvider) isStale(snapshot *AQSnapshot) bool {
+	latest := snapshot.LatestMeasurementAt()
+	if latest.IsZero() {
+		return snapshot.MeasurementCount() == 0
+	}
+	return time.Since(latest) > c.staleThreshold
+}
+
+// logServed logs which provider(s) served a snapshot.
+func (c *ChainProvider) logServed(snapshot *AQSnapshot) {
+	c.logger.Info().
+		Str("provider", snapshot.Provider).
+		Bool("fallback", snapshot.Fallback).
+		Int("stations", snapshot.StationCount()).
+		Msg("air quality snapshot served")
+}
+
+// mergeSnapshots combines two snapshots. Stations are merged by ID (primary
+// metadata wins) and for each station/pollutant the fresher measurement is kept.
+// The result is marked as a fallback, and named after both providers, only if
+// at least one of the secondary's measurements was kept.
+func mergeSnapshots(primary, secondary *AQSnapshot) *AQSnapshot {
+	merged := NewAQSnapshot(primary.Provider)
+
+	for id, station := range secondary.Stations {
+		merged.Stations[id] = station
+	}
+	for id, station := range primary.Stations {
+		if other, ok := merged.Stations[id]; ok {
+			station = mergeStation(station, other)
+		}
+		merged.Stations[id] = station
+	}
+
+	for key, m := range primary.Measurements {
+		merged.Measurements[key] = m
+	}
+	for key, m := range secondary.Measurements {
+		if existing, ok := merged.Measurements[key]; !ok || m.MeasuredAt.After(existing.MeasuredAt) {
+			merged.Measurements[key] = m
+			merged.Fallback = true
+		}
+	}
+	if merged.Fallback {
+		merged.Provider = primary.Provider + "+" + secondary.Provider
+	}
+
+	return merged
+}
+
+// mergeStation returns a copy of primary whose pollutant list also includes
+// any pollutants only reported by the secondary provider.
+func mergeStation(primary, secondary *Station) *Station {
+	merged := *primary
+	merged.Pollutants = append([]Pollutant(nil), primary.Pollutants...)
+	for _, p := range secondary.Pollutants {
+		found := false
+		for _, existing := range merged.Pollutants {
+			if existing == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Pollutants = append(merged.Pollutants, p)
+		}
+	}
+	return &merged
+}
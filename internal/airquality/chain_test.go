@@ -0,0 +1,127 @@
+package airquality_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+// chainSnapshot creates a single-station snapshot with an NO2 measurement at the given time.
+func chainSnapshot(provider, stationID string, value float64, measuredAt time.Time) *airquality.AQSnapshot {
+	snapshot := airquality.NewAQSnapshot(provider)
+	snapshot.Stations[stationID] = &airquality.Station{
+		ID:         stationID,
+		Lat:        52.37,
+		Lon:        4.89,
+		Pollutants: []airquality.Pollutant{airquality.PollutantNO2},
+	}
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID:  stationID,
+		Pollutant:  airquality.PollutantNO2,
+		Value:      value,
+		MeasuredAt: measuredAt,
+	})
+	return snapshot
+}
+
+func TestChainProvider_PrimaryFresh(t *testing.T) {
+	primary := &mockProvider{snapshot: chainSnapshot("primary", "NL1", 20, time.Now())}
+	secondary := &mockProvider{snapshot: chainSnapshot("secondary", "EU1", 30, time.Now())}
+
+	snapshot, err := airquality.NewChainProvider(primary, secondary).FetchSnapshot(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "primary", snapshot.Provider)
+	assert.False(t, snapshot.Fallback)
+	assert.Equal(t, int32(0), secondary.fetchCount.Load(), "secondary should not be called")
+}
+
+func TestChainProvider_PrimaryError(t *testing.T) {
+	primary := &mockProvider{err: errors.New("primary down")}
+	secondary := &mockProvider{snapshot: chainSnapshot("secondary", "EU1", 30, time.Now())}
+
+	snapshot, err := airquality.NewChainProvider(primary, secondary).FetchSnapshot(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "secondary", snapshot.Provider)
+	assert.True(t, snapshot.Fallback)
+}
+
+func TestChainProvider_PrimaryStale_MergesPreferringFresher(t *testing.T) {
+	stale := time.Now().Add(-2 * time.Hour)
+	fresh := time.Now().Add(-5 * time.Minute)
+
+	primarySnap := chainSnapshot("primary", "NL1", 20, stale)
+	primarySnap.Stations["NL2"] = &airquality.Station{ID: "NL2", Pollutants: []airquality.Pollutant{airquality.PollutantNO2}}
+	primarySnap.SetMeasurement(&airquality.Measurement{
+		StationID: "NL2", Pollutant: airquality.PollutantNO2, Value: 11, MeasuredAt: stale,
+	})
+
+	secondarySnap := chainSnapshot("secondary", "NL1", 25, fresh)
+	secondarySnap.Stations["EU1"] = &airquality.Station{ID: "EU1", Pollutants: []airquality.Pollutant{airquality.PollutantNO2}}
+
+	primary := &mockProvider{snapshot: primarySnap}
+	secondary := &mockProvider{snapshot: secondarySnap}
+
+	chain := airquality.NewChainProvider(primary, secondary).WithStaleThreshold(30 * time.Minute)
+	snapshot, err := chain.FetchSnapshot(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "primary+secondary", snapshot.Provider)
+	assert.True(t, snapshot.Fallback)
+	assert.Len(t, snapshot.Stations, 3, "stations should be merged by ID")
+
+	// The shared station takes the fresher secondary measurement
+	m := snapshot.GetMeasurement("NL1", airquality.PollutantNO2)
+	require.NotNil(t, m)
+	assert.Equal(t, 25.0, m.Value)
+
+	// Primary-only measurements are kept
+	m = snapshot.GetMeasurement("NL2", airquality.PollutantNO2)
+	require.NotNil(t, m)
+	assert.Equal(t, 11.0, m.Value)
+}
+
+func TestChainProvider_PrimaryStale_SecondaryNotFresher(t *testing.T) {
+	stale := time.Now().Add(-2 * time.Hour)
+
+	primary := &mockProvider{snapshot: chainSnapshot("primary", "NL1", 20, stale)}
+	secondary := &mockProvider{snapshot: chainSnapshot("secondary", "NL1", 25, stale.Add(-time.Hour))}
+
+	snapshot, err := airquality.NewChainProvider(primary, secondary).FetchSnapshot(context.Background())
+	require.NoError(t, err)
+
+	// Every measurement came from the primary, so this is not a fallback
+	assert.Equal(t, "primary", snapshot.Provider)
+	assert.False(t, snapshot.Fallback)
+	assert.Equal(t, 20.0, snapshot.GetMeasurement("NL1", airquality.PollutantNO2).Value)
+}
+
+func TestChainProvider_AllFail(t *testing.T) {
+	primary := &mockProvider{err: errors.New("primary down")}
+	secondary := &mockProvider{err: errors.New("secondary down")}
+
+	_, err := airquality.NewChainProvider(primary, secondary).FetchSnapshot(context.Background())
+	require.Error(t, err)
+}
+
+func TestService_CacheStatus_ReportsFallbackProvider(t *testing.T) {
+	primary := &mockProvider{err: errors.New("primary down")}
+	secondary := &mockProvider{snapshot: chainSnapshot("secondary", "EU1", 30, time.Now())}
+
+	svc := airquality.NewService(airquality.ServiceConfig{
+		Provider: airquality.NewChainProvider(primary, secondary),
+	})
+	_, err := svc.GetSnapshot(context.Background())
+	require.NoError(t, err)
+
+	status := svc.CacheStatus()
+	assert.Equal(t, "secondary", status.Provider)
+	assert.True(t, status.Fallback)
+}
@@ -0,0 +1,214 @@
+package airquality
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/weather"
+)
+
+// ErrForecastHorizon is returned when a forecast is requested too far ahead.
+var ErrForecastHorizon = errors.New("forecast time beyond maximum horizon")
+
+const (
+	// DefaultForecastHorizon is the furthest ahead a forecast may be requested.
+	DefaultForecastHorizon = 24 * time.Hour
+
+	// Forecasts further ahead than these horizons are capped at MEDIUM and LOW confidence.
+	forecastMediumConfidenceHorizon = 3 * time.Hour
+	forecastLowConfidenceHorizon    = 12 * time.Hour
+)
+
+// DiurnalModel describes the typical daily cycle of each pollutant, used to
+// shift current measurements to a future time.
+//
+// The model is deliberately simple: each pollutant has a baseline of 1.0 plus
+// Gaussian peaks at fixed local hours. A forecast scales the current value by
+// factor(at) / factor(measured), so a 30 µg/m³ NO2 reading at 05:00 becomes
+// higher at the 08:00 rush hour and falls back by midday. Traffic drives the
+// NO2 and PM rush-hour peaks; sunlight drives the afternoon O3 peak.
+//
+// When a weather forecast is available, dispersed pollutants are further
+// scaled by the wind's dispersion factor at the forecast time relative to
+// the measurement time, so a calm evening raises NO2 and a windy one lowers it.
+type DiurnalModel struct {
+	// Profiles holds the daily cycle for each pollutant.
+	// Pollutants without a profile are assumed constant.
+	Profiles map[Pollutant]DiurnalProfile
+
+	// MaxHorizon is the furthest ahead of the latest measurement a forecast
+	// may be requested. Default: 24 hours.
+	MaxHorizon time.Duration
+
+	// Location is the time zone of the peak hours. Measurement and forecast
+	// times are converted to it before the hour of day is taken.
+	// Default: Europe/Amsterdam.
+	Location *time.Location
+}
+
+// DiurnalProfile is the daily cycle of a single pollutant.
+type DiurnalProfile struct {
+	Peaks []DiurnalPeak
+
+	// Dispersed marks pollutants emitted near the ground, whose
+	// concentration follows the wind's dispersion factor. O3 forms
+	// photochemically and is not dispersed this way.
+	Dispersed bool
+}
+
+// DiurnalPeak is a Gaussian bump on the baseline concentration.
+type DiurnalPeak struct {
+	// Hour is the local time of the peak (0-24, fractional hours allowed).
+	Hour float64
+
+	// Amplitude is the relative increase at the peak (0.5 = 50% above baseline).
+	Amplitude float64
+
+	// WidthHours is the standard deviation of the peak in hours.
+	WidthHours float64
+}
+
+// DefaultDiurnalModel returns coefficients fitted to typical Dutch urban
+// background stations.
+func DefaultDiurnalModel() DiurnalModel {
+	return DiurnalModel{
+		Profiles: map[Pollutant]DiurnalProfile{
+			PollutantNO2: {Dispersed: true, Peaks: []DiurnalPeak{
+				{Hour: 8, Amplitude: 0.6, WidthHours: 1.5},    // Morning rush hour
+				{Hour: 17.5, Amplitude: 0.5, WidthHours: 2.0}, // Evening rush hour
+			}},
+			PollutantPM25: {Dispersed: true, Peaks: []DiurnalPeak{
+				{Hour: 8.5, Amplitude: 0.15, WidthHours: 2.0}, // Morning traffic
+				{Hour: 21, Amplitude: 0.2, WidthHours: 3.0},   // Evening heating, shallow boundary layer
+			}},
+			PollutantPM10: {Dispersed: true, Peaks: []DiurnalPeak{
+				{Hour: 8, Amplitude: 0.2, WidthHours: 2.0},
+				{Hour: 17.5, Amplitude: 0.15, WidthHours: 2.0},
+			}},
+			PollutantO3: {Peaks: []DiurnalPeak{
+				{Hour: 15, Amplitude: 0.6, WidthHours: 3.0}, // Photochemical afternoon peak
+			}},
+		},
+		MaxHorizon: DefaultForecastHorizon,
+		Location:   defaultForecastLocation(),
+	}
+}
+
+// defaultForecastLocation returns Europe/Amsterdam, or a fixed CET offset if
+// the time zone database is unavailable.
+func defaultForecastLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		return time.FixedZone("CET", 60*60)
+	}
+	return loc
+}
+
+// factor returns the relative concentration of a pollutant at the given time of day.
+func (m DiurnalModel) factor(pollutant Pollutant, t time.Time) float64 {
+	profile, ok := m.Profiles[pollutant]
+	if !ok {
+		return 1
+	}
+
+	if m.Location != nil {
+		t = t.In(m.Location)
+	}
+	hour := float64(t.Hour()) + float64(t.Minute())/60
+	factor := 1.0
+	for _, peak := range profile.Peaks {
+		if peak.WidthHours <= 0 {
+			continue
+		}
+		// Circular distance so a 23:00 peak also raises 01:00
+		d := math.Abs(hour - peak.Hour)
+		if d > 12 {
+			d = 24 - d
+		}
+		factor += peak.Amplitude * math.Exp(-d*d/(2*peak.WidthHours*peak.WidthHours))
+	}
+	return factor
+}
+
+// dispersion returns the relative concentration of a pollutant at the
+// forecast wind at at compared with the wind at measuredAt, or 1 if the
+// pollutant is not dispersed or the forecast does not cover both times.
+func (m DiurnalModel) dispersion(pollutant Pollutant, forecast *weather.Forecast, measuredAt, at time.Time) float64 {
+	if forecast == nil || !m.Profiles[pollutant].Dispersed {
+		return 1
+	}
+	measured, future := forecast.NearestHour(measuredAt), forecast.NearestHour(at)
+	if measured == nil || future == nil {
+		return 1
+	}
+	return future.DispersionFactor() / measured.DispersionFactor()
+}
+
+// InterpolateForecast estimates air quality at the given location and time.
+// Current values are interpolated from the snapshot and scaled by the diurnal
+// model from the time of the latest measurement to at. Confidence is reduced
+// for forecasts further ahead.
+func (i *Interpolator) InterpolateForecast(lat, lon float64, snapshot *AQSnapshot, at time.Time) (*InterpolatedPoint, error) {
+	return i.InterpolateWeatherForecast(lat, lon, snapshot, at, nil)
+}
+
+// InterpolateWeatherForecast is InterpolateForecast with a weather forecast
+// for the location as an additional input: dispersed pollutants are scaled
+// by the change in wind dispersion between the measurement and at. A nil
+// forecast, or one not covering both times, leaves the diurnal model alone.
+func (i *Interpolator) InterpolateWeatherForecast(lat, lon float64, snapshot *AQSnapshot, at time.Time, forecast *weather.Forecast) (*InterpolatedPoint, error) {
+	if snapshot == nil {
+		return nil, ErrNoStationsInRange
+	}
+
+	measuredAt := snapshot.LatestMeasurementAt()
+	if measuredAt.IsZero() {
+		measuredAt = snapshot.FetchedAt
+	}
+
+	horizon := at.Sub(measuredAt)
+	if horizon > i.config.Diurnal.MaxHorizon {
+		return nil, ErrForecastHorizon
+	}
+
+	current, err := i.Interpolate(lat, lon, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InterpolatedPoint{
+		Lat:         lat,
+		Lon:         lon,
+		Values:      make(map[Pollutant]*InterpolatedValue, len(current.Values)),
+		AQICategory: AQIUnknown,
+	}
+
+	for pollutant, value := range current.Values {
+		model := i.config.Diurnal
+		shifted := *value
+		shifted.Value = value.Value *
+			model.factor(pollutant, at) / model.factor(pollutant, measuredAt) *
+			model.dispersion(pollutant, forecast, measuredAt, at)
+		shifted.Confidence = forecastConfidence(value.Confidence, horizon)
+		shifted.AQICategory = CategoryFor(pollutant, shifted.Value)
+		if shifted.AQICategory.WorseThan(result.AQICategory) {
+			result.AQICategory = shifted.AQICategory
+		}
+		result.Values[pollutant] = &shifted
+	}
+
+	return result, nil
+}
+
+// forecastConfidence caps the interpolation confidence by forecast horizon.
+func forecastConfidence(confidence Confidence, horizon time.Duration) Confidence {
+	switch {
+	case horizon > forecastLowConfidenceHorizon:
+		return ConfidenceLow
+	case horizon > forecastMediumConfidenceHorizon && confidence == ConfidenceHigh:
+		return ConfidenceMedium
+	default:
+		return confidence
+	}
+}
@@ -0,0 +1,231 @@
+package airquality_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/weather"
+)
+
+// amsterdam returns the given hour on 10 March 2026 in Europe/Amsterdam,
+// the time zone of the diurnal model's peak hours.
+func amsterdam(t *testing.T, hour int) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	return time.Date(2026, 3, 10, hour, 0, 0, 0, loc)
+}
+
+// forecastSnapshot creates a two-station snapshot measured at the given time.
+func forecastSnapshot(measuredAt time.Time) *airquality.AQSnapshot {
+	snapshot := airquality.NewAQSnapshot("test")
+	for id, lon := range map[string]float64{"NL10001": 4.895, "NL10002": 4.90} {
+		snapshot.Stations[id] = &airquality.Station{
+			ID:         id,
+			Lat:        52.370,
+			Lon:        lon,
+			Pollutants: []airquality.Pollutant{airquality.PollutantNO2, airquality.PollutantO3},
+		}
+		snapshot.SetMeasurement(&airquality.Measurement{
+			StationID: id, Pollutant: airquality.PollutantNO2, Value: 30, MeasuredAt: measuredAt,
+		})
+		snapshot.SetMeasurement(&airquality.Measurement{
+			StationID: id, Pollutant: airquality.PollutantO3, Value: 40, MeasuredAt: measuredAt,
+		})
+	}
+	return snapshot
+}
+
+func TestInterpolateForecast_DiurnalPattern(t *testing.T) {
+	measuredAt := amsterdam(t, 5)
+	snapshot := forecastSnapshot(measuredAt)
+	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+
+	now, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt)
+	require.NoError(t, err)
+	rushHour, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(3*time.Hour))
+	require.NoError(t, err)
+	midday, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(7*time.Hour))
+	require.NoError(t, err)
+	afternoon, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(10*time.Hour))
+	require.NoError(t, err)
+
+	no2 := func(p *airquality.InterpolatedPoint) float64 { return p.Values[airquality.PollutantNO2].Value }
+	o3 := func(p *airquality.InterpolatedPoint) float64 { return p.Values[airquality.PollutantO3].Value }
+
+	// Zero horizon leaves the value unchanged
+	assert.InDelta(t, 30, no2(now), 0.01)
+
+	// NO2 peaks in the morning rush hour and falls back by midday
+	assert.Greater(t, no2(rushHour), no2(now))
+	assert.Less(t, no2(midday), no2(rushHour))
+
+	// O3 peaks in the afternoon
+	assert.Greater(t, o3(afternoon), o3(now))
+	assert.Greater(t, o3(afternoon), o3(rushHour))
+}
+
+func TestInterpolateForecast_ConfidenceDecreasesWithHorizon(t *testing.T) {
+	measuredAt := amsterdam(t, 5)
+	snapshot := forecastSnapshot(measuredAt)
+	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+
+	near, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(time.Hour))
+	require.NoError(t, err)
+	mid, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(6*time.Hour))
+	require.NoError(t, err)
+	far, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(18*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, airquality.ConfidenceHigh, near.Values[airquality.PollutantNO2].Confidence)
+	assert.Equal(t, airquality.ConfidenceMedium, mid.Values[airquality.PollutantNO2].Confidence)
+	assert.Equal(t, airquality.ConfidenceLow, far.Values[airquality.PollutantNO2].Confidence)
+}
+
+func TestInterpolateForecast_BeyondHorizon(t *testing.T) {
+	measuredAt := time.Now()
+	snapshot := forecastSnapshot(measuredAt)
+	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+
+	_, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(25*time.Hour))
+	assert.ErrorIs(t, err, airquality.ErrForecastHorizon)
+}
+
+func TestInterpolateForecast_ConfigurableModel(t *testing.T) {
+	measuredAt := amsterdam(t, 5)
+	snapshot := forecastSnapshot(measuredAt)
+
+	cfg := airquality.DefaultInterpolationConfig()
+	cfg.Diurnal = airquality.DiurnalModel{
+		Profiles: map[airquality.Pollutant]airquality.DiurnalProfile{
+			airquality.PollutantNO2: {Peaks: []airquality.DiurnalPeak{{Hour: 8, Amplitude: 1.0, WidthHours: 0.5}}},
+		},
+		MaxHorizon: 6 * time.Hour,
+	}
+	interpolator := airquality.NewInterpolator(cfg)
+
+	result, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(3*time.Hour))
+	require.NoError(t, err)
+
+	// Peak doubles the baseline; 05:00 is far enough from the peak to be ~baseline
+	assert.InDelta(t, 60, result.Values[airquality.PollutantNO2].Value, 0.1)
+	// No O3 profile configured: held constant
+	assert.InDelta(t, 40, result.Values[airquality.PollutantO3].Value, 0.01)
+
+	_, err = interpolator.InterpolateForecast(52.370, 4.897, snapshot, measuredAt.Add(7*time.Hour))
+	assert.ErrorIs(t, err, airquality.ErrForecastHorizon)
+}
+
+func TestInterpolateForecast_UsesAmsterdamHours(t *testing.T) {
+	cfg := airquality.DefaultInterpolationConfig()
+	cfg.Diurnal.Profiles = map[airquality.Pollutant]airquality.DiurnalProfile{
+		airquality.PollutantNO2: {Peaks: []airquality.DiurnalPeak{{Hour: 8, Amplitude: 1.0, WidthHours: 0.5}}},
+	}
+	interpolator := airquality.NewInterpolator(cfg)
+
+	// 07:00 UTC is the 08:00 peak in Amsterdam, whatever zone at is in
+	measuredAt := amsterdam(t, 5).UTC()
+	snapshot := forecastSnapshot(measuredAt)
+	at := time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC)
+
+	result, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, at)
+	require.NoError(t, err)
+	assert.InDelta(t, 60, result.Values[airquality.PollutantNO2].Value, 0.1)
+}
+
+// windForecast returns a forecast with the given wind speed at each time.
+func windForecast(winds map[time.Time]float64) *weather.Forecast {
+	forecast := &weather.Forecast{}
+	for at, speed := range winds {
+		forecast.Hourly = append(forecast.Hourly, weather.HourlyForecast{Time: at, WindSpeed: speed})
+	}
+	return forecast
+}
+
+func TestInterpolateWeatherForecast_WindDispersion(t *testing.T) {
+	measuredAt := amsterdam(t, 5)
+	at := amsterdam(t, 8)
+	snapshot := forecastSnapshot(measuredAt)
+	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+
+	diurnal, err := interpolator.InterpolateForecast(52.370, 4.897, snapshot, at)
+	require.NoError(t, err)
+
+	// Moderate wind now, calm at departure: NO2 accumulates
+	calm, err := interpolator.InterpolateWeatherForecast(52.370, 4.897, snapshot, at,
+		windForecast(map[time.Time]float64{measuredAt: 5, at: 0.5}))
+	require.NoError(t, err)
+	assert.InDelta(t, diurnal.Values[airquality.PollutantNO2].Value*1.3/0.9, calm.Values[airquality.PollutantNO2].Value, 0.01)
+	// O3 is not dispersed by wind
+	assert.InDelta(t, diurnal.Values[airquality.PollutantO3].Value, calm.Values[airquality.PollutantO3].Value, 0.01)
+
+	// A forecast that does not reach the departure leaves the diurnal model alone
+	partial, err := interpolator.InterpolateWeatherForecast(52.370, 4.897, snapshot, at,
+		windForecast(map[time.Time]float64{measuredAt: 5}))
+	require.NoError(t, err)
+	assert.InDelta(t, diurnal.Values[airquality.PollutantNO2].Value, partial.Values[airquality.PollutantNO2].Value, 0.01)
+}
+
+func TestService_ForecastAt_FeatureFlag(t *testing.T) {
+	measuredAt := time.Now()
+	provider := &mockProvider{snapshot: forecastSnapshot(measuredAt)}
+
+	repo := featureflags.NewInMemoryRepositoryWithFlags(map[string]*featureflags.Flag{
+		featureflags.FlagEnableTimeShift: {Key: featureflags.FlagEnableTimeShift, Value: false},
+	})
+	flags := featureflags.NewService(featureflags.ServiceConfig{Repository: repo, Logger: zerolog.Nop()})
+
+	svc := airquality.NewService(airquality.ServiceConfig{Provider: provider, FeatureFlags: flags})
+	_, err := svc.ForecastAt(context.Background(), 52.370, 4.897, measuredAt.Add(time.Hour))
+	assert.ErrorIs(t, err, airquality.ErrTimeShiftDisabled)
+
+	require.NoError(t, repo.SetFlag(context.Background(), &featureflags.Flag{Key: featureflags.FlagEnableTimeShift, Value: true}))
+	result, err := svc.ForecastAt(context.Background(), 52.370, 4.897, measuredAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Values)
+}
+
+// mockWeatherProvider serves a fixed forecast.
+type mockWeatherProvider struct {
+	forecast *weather.Forecast
+}
+
+func (p *mockWeatherProvider) GetCurrentWeather(context.Context, float64, float64) (*weather.Observation, error) {
+	return nil, weather.ErrNoDataForLocation
+}
+
+func (p *mockWeatherProvider) GetForecast(context.Context, float64, float64) (*weather.Forecast, error) {
+	return p.forecast, nil
+}
+
+func (p *mockWeatherProvider) Name() string {
+	return "mock"
+}
+
+func TestService_ForecastAt_UsesWeatherForecast(t *testing.T) {
+	measuredAt := time.Now().Truncate(time.Hour)
+	at := measuredAt.Add(2 * time.Hour)
+	provider := &mockProvider{snapshot: forecastSnapshot(measuredAt)}
+
+	without := airquality.NewService(airquality.ServiceConfig{Provider: provider})
+	diurnal, err := without.ForecastAt(context.Background(), 52.370, 4.897, at)
+	require.NoError(t, err)
+
+	weatherService := weather.NewService(weather.ServiceConfig{
+		Provider: &mockWeatherProvider{forecast: windForecast(map[time.Time]float64{measuredAt: 10, at: 0.5})},
+		Logger:   zerolog.Nop(),
+	})
+	with := airquality.NewService(airquality.ServiceConfig{Provider: provider, Weather: weatherService})
+	result, err := with.ForecastAt(context.Background(), 52.370, 4.897, at)
+	require.NoError(t, err)
+
+	// Strong wind now, calm later
+	assert.InDelta(t, diurnal.Values[airquality.PollutantNO2].Value*1.3/0.7, result.Values[airquality.PollutantNO2].Value, 0.01)
+}
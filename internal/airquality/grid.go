@@ -0,0 +1,152 @@
+package airquality
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// DefaultMaxGridCells is the default cap on cells in a single grid request.
+const DefaultMaxGridCells = 2500
+
+// Grid errors.
+var (
+	ErrInvalidGrid  = errors.New("invalid grid bounds or resolution")
+	ErrGridTooLarge = errors.New("grid exceeds maximum cell count")
+)
+
+// GridRequest describes a bounding box sampled at a fixed resolution.
+type GridRequest struct {
+	MinLat     float64
+	MinLon     float64
+	MaxLat     float64
+	MaxLon     float64
+	Resolution float64 // cell size in degrees
+}
+
+// Dimensions returns the number of rows (latitude) and columns (longitude)
+// needed to cover the box. Partial cells at the edges count as whole cells.
+func (r GridRequest) Dimensions() (rows, cols int) {
+	rows = cellsAlong(r.MaxLat-r.MinLat, r.Resolution)
+	cols = cellsAlong(r.MaxLon-r.MinLon, r.Resolution)
+	return rows, cols
+}
+
+// gridEpsilon absorbs floating point error so a span that is an exact
+// multiple of the resolution (e.g. 1.2 / 0.2) does not gain an extra cell.
+const gridEpsilon = 1e-9
+
+// cellsAlong returns the number of cells of the given size covering span.
+func cellsAlong(span, size float64) int {
+	return int(math.Ceil(span/size - gridEpsilon))
+}
+
+// validate checks the box is well formed and within the cell cap.
+func (r GridRequest) validate(maxCells int) error {
+	if r.Resolution <= 0 || r.MinLat >= r.MaxLat || r.MinLon >= r.MaxLon {
+		return ErrInvalidGrid
+	}
+	if r.MinLat < -90 || r.MaxLat > 90 || r.MinLon < -180 || r.MaxLon > 180 {
+		return ErrInvalidGrid
+	}
+	// Check each side before multiplying so huge boxes cannot overflow
+	rows, cols := r.Dimensions()
+	if rows > maxCells || cols > maxCells || rows*cols > maxCells {
+		return ErrGridTooLarge
+	}
+	return nil
+}
+
+// Grid holds interpolated values at the center of each cell of a GridRequest.
+type Grid struct {
+	Rows       int
+	Cols       int
+	Resolution float64
+
+	// SnapshotAt is when the underlying measurements were taken.
+	SnapshotAt time.Time
+
+	// Cells is in row-major order starting at the south-west corner. A nil
+	// cell has no stations within range.
+	Cells []*InterpolatedPoint
+}
+
+// Cell returns the cell at row and col.
+func (g *Grid) Cell(row, col int) *InterpolatedPoint {
+	return g.Cells[row*g.Cols+col]
+}
+
+// InterpolateGrid interpolates air quality at the center of every cell in
+// the box, for rendering map overlays. Returns ErrGridTooLarge if the request
+// exceeds the configured MaxGridCells.
+func (s *Service) InterpolateGrid(ctx context.Context, req GridRequest) (*Grid, error) {
+	if err := req.validate(s.maxGridCells); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.GetSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, cols := req.Dimensions()
+	points := make([]struct{ Lat, Lon float64 }, 0, rows*cols)
+	for row := 0; row < rows; row++ {
+		lat := req.MinLat + (float64(row)+0.5)*req.Resolution
+		for col := 0; col < cols; col++ {
+			lon := req.MinLon + (float64(col)+0.5)*req.Resolution
+			points = append(points, struct{ Lat, Lon float64 }{lat, lon})
+		}
+	}
+
+	cells, err := s.interpolator.InterpolateMultiple(ctx, points, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	s.applyTrends(ctx, snapshot, cells)
+
+	snapshotAt := snapshot.LatestMeasurementAt()
+	if snapshotAt.IsZero() {
+		snapshotAt = snapshot.FetchedAt
+	}
+
+	return &Grid{
+		Rows:       rows,
+		Cols:       cols,
+		Resolution: req.Resolution,
+		SnapshotAt: snapshotAt,
+		Cells:      cells,
+	}, nil
+}
+
+// MaxGridCells returns the cap on cells in a single InterpolateGrid request.
+func (s *Service) MaxGridCells() int {
+	return s.maxGridCells
+}
+
+// Confidence returns the lowest confidence across the point's pollutant values.
+func (p *InterpolatedPoint) Confidence() Confidence {
+	if len(p.Values) == 0 {
+		return ConfidenceLow
+	}
+	lowest := ConfidenceHigh
+	for _, v := range p.Values {
+		if confidenceRank(v.Confidence) < confidenceRank(lowest) {
+			lowest = v.Confidence
+		}
+	}
+	return lowest
+}
+
+// confidenceRank orders confidence levels from low to high.
+func confidenceRank(c Confidence) int {
+	switch c {
+	case ConfidenceHigh:
+		return 2
+	case ConfidenceMedium:
+		return 1
+	default:
+		return 0
+	}
+}
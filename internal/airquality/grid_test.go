@@ -0,0 +1,105 @@
+package airquality_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+func TestService_InterpolateGrid(t *testing.T) {
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: testSnapshot()},
+		Logger:   zerolog.Nop(),
+	})
+
+	// Spans Amsterdam (covered) to Groningen province (no station within 50km)
+	grid, err := service.InterpolateGrid(context.Background(), airquality.GridRequest{
+		MinLat:     52.3,
+		MinLon:     4.8,
+		MaxLat:     53.3,
+		MaxLon:     6.8,
+		Resolution: 0.5,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, grid.Rows)
+	assert.Equal(t, 4, grid.Cols)
+	require.Len(t, grid.Cells, 8)
+	assert.False(t, grid.SnapshotAt.IsZero())
+
+	covered := grid.Cell(0, 0)
+	require.NotNil(t, covered, "cell near Amsterdam should be interpolated")
+	assert.InDelta(t, 52.55, covered.Lat, 0.0001)
+	assert.InDelta(t, 5.05, covered.Lon, 0.0001)
+	assert.Contains(t, covered.Values, airquality.PollutantNO2)
+	assert.Equal(t, airquality.ConfidenceLow, covered.Confidence())
+
+	assert.Nil(t, grid.Cell(1, 3), "cell near Groningen has no stations in range")
+}
+
+func TestService_InterpolateGrid_Cancelled(t *testing.T) {
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: testSnapshot()},
+		Logger:   zerolog.Nop(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	grid, err := service.InterpolateGrid(ctx, airquality.GridRequest{
+		MinLat:     52.3,
+		MinLon:     4.8,
+		MaxLat:     53.3,
+		MaxLon:     6.8,
+		Resolution: 0.5,
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, grid)
+}
+
+func TestService_InterpolateGrid_Limits(t *testing.T) {
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider:     &mockProvider{snapshot: testSnapshot()},
+		Logger:       zerolog.Nop(),
+		MaxGridCells: 100,
+	})
+
+	tests := []struct {
+		name string
+		req  airquality.GridRequest
+		err  error
+	}{
+		{
+			name: "too many cells",
+			req:  airquality.GridRequest{MinLat: 50, MinLon: 3, MaxLat: 54, MaxLon: 8, Resolution: 0.1},
+			err:  airquality.ErrGridTooLarge,
+		},
+		{
+			name: "tiny resolution on a huge box",
+			req:  airquality.GridRequest{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180, Resolution: 1e-12},
+			err:  airquality.ErrGridTooLarge,
+		},
+		{
+			name: "inverted box",
+			req:  airquality.GridRequest{MinLat: 53, MinLon: 4, MaxLat: 52, MaxLon: 5, Resolution: 0.1},
+			err:  airquality.ErrInvalidGrid,
+		},
+		{
+			name: "zero resolution",
+			req:  airquality.GridRequest{MinLat: 52, MinLon: 4, MaxLat: 53, MaxLon: 5},
+			err:  airquality.ErrInvalidGrid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.InterpolateGrid(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
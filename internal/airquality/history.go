@@ -0,0 +1,71 @@
+package airquality
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+)
+
+// ErrNoHistory is returned by History.At when no snapshot was archived for
+// the requested hour.
+var ErrNoHistory = errors.New("no air quality history for that hour")
+
+// HistoryRetention is how long History keeps an archived hour. Hours are
+// stored in one slot per weekday and hour, so each slot is overwritten a
+// week later and the archive never grows beyond 168 snapshots.
+const HistoryRetention = 7 * 24 * time.Hour
+
+// History archives hourly air quality snapshots in a SnapshotStore, so that
+// past conditions can be looked up after the live snapshot has moved on.
+type History struct {
+	store cache.SnapshotStore
+}
+
+// NewHistory creates a History backed by store.
+func NewHistory(store cache.SnapshotStore) *History {
+	return &History{store: store}
+}
+
+// Record archives snapshot under the hour of its latest measurement,
+// replacing any snapshot recorded earlier in the same hour.
+func (h *History) Record(ctx context.Context, snapshot *AQSnapshot) error {
+	return h.store.Save(ctx, historyKey(snapshotHour(snapshot)), snapshot)
+}
+
+// At returns the snapshot archived for the hour containing t. It returns
+// ErrNoHistory if that hour was not recorded or has been overwritten.
+func (h *History) At(ctx context.Context, t time.Time) (*AQSnapshot, error) {
+	hour := t.UTC().Truncate(time.Hour)
+
+	var snapshot AQSnapshot
+	err := h.store.Load(ctx, historyKey(hour), &snapshot)
+	if errors.Is(err, cache.ErrSnapshotNotFound) {
+		return nil, ErrNoHistory
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The slot holds the same hour of an earlier week
+	if !snapshotHour(&snapshot).Equal(hour) {
+		return nil, ErrNoHistory
+	}
+	return &snapshot, nil
+}
+
+// snapshotHour returns the UTC hour a snapshot describes.
+func snapshotHour(snapshot *AQSnapshot) time.Time {
+	at := snapshot.LatestMeasurementAt()
+	if at.IsZero() {
+		at = snapshot.FetchedAt
+	}
+	return at.UTC().Truncate(time.Hour)
+}
+
+// historyKey returns the store key of the slot for hour.
+func historyKey(hour time.Time) string {
+	return fmt.Sprintf("airquality-history-%d-%02d", hour.Weekday(), hour.Hour())
+}
@@ -0,0 +1,89 @@
+package airquality_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+)
+
+// snapshotMeasuredAt returns a snapshot with a single NO2 measurement at t.
+func snapshotMeasuredAt(t time.Time, no2 float64) *airquality.AQSnapshot {
+	snapshot := airquality.NewAQSnapshot("test")
+	snapshot.Stations["NL10001"] = &airquality.Station{ID: "NL10001", Lat: 52.37, Lon: 4.89}
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID:  "NL10001",
+		Pollutant:  airquality.PollutantNO2,
+		Value:      no2,
+		MeasuredAt: t,
+	})
+	return snapshot
+}
+
+func newTestHistory(t *testing.T) *airquality.History {
+	t.Helper()
+	store, err := cache.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	return airquality.NewHistory(store)
+}
+
+func TestHistory_RecordAndAt(t *testing.T) {
+	ctx := context.Background()
+	history := newTestHistory(t)
+	hour := time.Date(2026, 10, 12, 7, 0, 0, 0, time.UTC)
+
+	require.NoError(t, history.Record(ctx, snapshotMeasuredAt(hour, 30)))
+	// A later refresh in the same hour replaces the first
+	require.NoError(t, history.Record(ctx, snapshotMeasuredAt(hour, 32)))
+
+	snapshot, err := history.At(ctx, hour.Add(45*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 32.0, snapshot.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+
+	// Local times resolve to the same UTC hour
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	_, err = history.At(ctx, hour.In(amsterdam))
+	require.NoError(t, err)
+
+	_, err = history.At(ctx, hour.Add(time.Hour))
+	assert.ErrorIs(t, err, airquality.ErrNoHistory)
+}
+
+func TestHistory_OverwrittenAfterRetention(t *testing.T) {
+	ctx := context.Background()
+	history := newTestHistory(t)
+	hour := time.Date(2026, 10, 12, 7, 0, 0, 0, time.UTC)
+
+	require.NoError(t, history.Record(ctx, snapshotMeasuredAt(hour, 30)))
+	require.NoError(t, history.Record(ctx, snapshotMeasuredAt(hour.Add(airquality.HistoryRetention), 40)))
+
+	_, err := history.At(ctx, hour)
+	assert.ErrorIs(t, err, airquality.ErrNoHistory)
+
+	snapshot, err := history.At(ctx, hour.Add(airquality.HistoryRetention))
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, snapshot.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+}
+
+func TestService_ArchivesRefreshedSnapshots(t *testing.T) {
+	history := newTestHistory(t)
+	measuredAt := time.Now().Add(-10 * time.Minute)
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: snapshotMeasuredAt(measuredAt, 25)},
+		Logger:   zerolog.Nop(),
+		History:  history,
+	})
+
+	require.NoError(t, service.RefreshSnapshot(context.Background()))
+
+	snapshot, err := history.At(context.Background(), measuredAt)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, snapshot.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+}
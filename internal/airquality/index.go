@@ -0,0 +1,140 @@
+package airquality
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// indexCellSize is the size of a spatial index cell in degrees (~11km of latitude).
+	indexCellSize = 0.1
+
+	// metersPerDegreeLat is the approximate length of one degree of latitude.
+	metersPerDegreeLat = 111320.0
+)
+
+// cellKey identifies a cell in the station grid index.
+type cellKey struct {
+	row int
+	col int
+}
+
+// stationIndex is a grid-bucket spatial index over snapshot stations.
+// Stations are bucketed into fixed-size lat/lon cells so radius queries
+// only need to inspect the cells overlapping the query bounding box.
+type stationIndex struct {
+	cells map[cellKey][]*Station
+
+	// fetchedAt and stationCount identify the snapshot contents the index
+	// was built from.
+	fetchedAt    time.Time
+	stationCount int
+}
+
+// newStationIndex builds a grid index for the given stations of a snapshot
+// fetched at fetchedAt.
+func newStationIndex(stations map[string]*Station, fetchedAt time.Time) *stationIndex {
+	idx := &stationIndex{
+		cells:        make(map[cellKey][]*Station),
+		fetchedAt:    fetchedAt,
+		stationCount: len(stations),
+	}
+	for _, station := range stations {
+		key := cellFor(station.Lat, station.Lon)
+		idx.cells[key] = append(idx.cells[key], station)
+	}
+	return idx
+}
+
+// cellFor returns the grid cell containing the given point.
+func cellFor(lat, lon float64) cellKey {
+	return cellKey{
+		row: int(math.Floor(lat / indexCellSize)),
+		col: int(math.Floor(lon / indexCellSize)),
+	}
+}
+
+// candidates returns all stations in cells overlapping the bounding box of
+// the circle around (lat, lon). Results may include stations outside the radius;
+// callers must apply an exact distance check.
+func (idx *stationIndex) candidates(lat, lon, radiusMeters float64) []*Station {
+	latDelta := radiusMeters / metersPerDegreeLat
+
+	// Longitude degrees shrink towards the poles; clamp to avoid blowing up near them.
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if cosLat < 0.01 {
+		cosLat = 0.01
+	}
+	lonDelta := radiusMeters / (metersPerDegreeLat * cosLat)
+
+	minCell := cellFor(lat-latDelta, lon-lonDelta)
+	maxCell := cellFor(lat+latDelta, lon+lonDelta)
+
+	var result []*Station
+	for row := minCell.row; row <= maxCell.row; row++ {
+		for col := minCell.col; col <= maxCell.col; col++ {
+			result = append(result, idx.cells[cellKey{row: row, col: col}]...)
+		}
+	}
+	return result
+}
+
+// NearbyStations returns all stations within radiusMeters of the given point.
+// The spatial index is built lazily on first use and rebuilt when the
+// snapshot's FetchedAt changes, a station is set with SetStation, or the
+// number of stations changes; call InvalidateIndex after moving stations in
+// place.
+func (s *AQSnapshot) NearbyStations(lat, lon, radiusMeters float64) []*Station {
+	var nearby []*Station
+	for _, station := range s.stationCandidates(lat, lon, radiusMeters) {
+		if haversineDistance(lat, lon, station.Lat, station.Lon) <= radiusMeters {
+			nearby = append(nearby, station)
+		}
+	}
+	return nearby
+}
+
+// NearestStation returns the station closest to the given point and its
+// distance in meters, or nil if no station is within radiusMeters.
+func (s *AQSnapshot) NearestStation(lat, lon, radiusMeters float64) (*Station, float64) {
+	var nearest *Station
+	nearestDistance := math.Inf(1)
+	for _, station := range s.stationCandidates(lat, lon, radiusMeters) {
+		dist := haversineDistance(lat, lon, station.Lat, station.Lon)
+		// Break ties by ID so results do not depend on map order
+		if dist > radiusMeters || dist > nearestDistance ||
+			(dist == nearestDistance && station.ID > nearest.ID) {
+			continue
+		}
+		nearest = station
+		nearestDistance = dist
+	}
+	if nearest == nil {
+		return nil, 0
+	}
+	return nearest, nearestDistance
+}
+
+// InvalidateIndex discards the spatial index so it is rebuilt on the next query.
+func (s *AQSnapshot) InvalidateIndex() {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	s.index = nil
+}
+
+// stationCandidates returns the stations from the index cells covering the
+// query radius, building the index if needed. The index is rebuilt when it
+// was built for another fetch of the snapshot; the station count is also
+// compared, so stations written straight into the map are picked up.
+func (s *AQSnapshot) stationCandidates(lat, lon, radiusMeters float64) []*Station {
+	s.indexMu.Lock()
+	s.mu.RLock()
+	if s.index == nil || !s.index.fetchedAt.Equal(s.FetchedAt) || s.index.stationCount != len(s.Stations) {
+		s.index = newStationIndex(s.Stations, s.FetchedAt)
+	}
+	s.mu.RUnlock()
+	idx := s.index
+	s.indexMu.Unlock()
+
+	return idx.candidates(lat, lon, radiusMeters)
+}
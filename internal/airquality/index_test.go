@@ -0,0 +1,142 @@
+package airquality
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/pkg/polyline"
+)
+
+// benchmarkSnapshot creates a snapshot with n stations spread across the Netherlands.
+func benchmarkSnapshot(n int) *AQSnapshot {
+	rng := rand.New(rand.NewSource(42))
+	snapshot := NewAQSnapshot("bench")
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("NL%05d", i)
+		snapshot.Stations[id] = &Station{
+			ID:         id,
+			Lat:        50.75 + rng.Float64()*2.75,
+			Lon:        3.35 + rng.Float64()*3.85,
+			Pollutants: []Pollutant{PollutantNO2, PollutantPM25},
+		}
+		snapshot.SetMeasurement(&Measurement{StationID: id, Pollutant: PollutantNO2, Value: 10 + rng.Float64()*40})
+		snapshot.SetMeasurement(&Measurement{StationID: id, Pollutant: PollutantPM25, Value: 5 + rng.Float64()*20})
+	}
+	return snapshot
+}
+
+// benchmarkRoutePoints samples an Amsterdam-Utrecht route every 100 meters.
+func benchmarkRoutePoints() []polyline.Coordinate {
+	route := []polyline.Coordinate{
+		{Lat: 52.3676, Lon: 4.9041},
+		{Lat: 52.3200, Lon: 4.9500},
+		{Lat: 52.2600, Lon: 4.9900},
+		{Lat: 52.1800, Lon: 5.0400},
+		{Lat: 52.0907, Lon: 5.1214},
+	}
+	return polyline.Sample(route, 100)
+}
+
+func TestAQSnapshot_NearbyStations_MatchesBruteForce(t *testing.T) {
+	snapshot := benchmarkSnapshot(500)
+	interpolator := NewInterpolator(DefaultInterpolationConfig())
+
+	for _, p := range benchmarkRoutePoints() {
+		indexed := interpolator.stationsInRange(p.Lat, p.Lon, snapshot.stationCandidates(p.Lat, p.Lon, interpolator.config.MaxDistance))
+		bruteForce := interpolator.stationsInRange(p.Lat, p.Lon, snapshot.StationList())
+		require.Len(t, indexed, len(bruteForce), "point (%f, %f): indexed and brute force station counts differ", p.Lat, p.Lon)
+
+		nearby := snapshot.NearbyStations(p.Lat, p.Lon, interpolator.config.MaxDistance)
+		require.Len(t, nearby, len(bruteForce), "point (%f, %f): NearbyStations count differs from brute force", p.Lat, p.Lon)
+	}
+}
+
+func TestAQSnapshot_NearbyStations_RebuildsOnStationChange(t *testing.T) {
+	snapshot := NewAQSnapshot("test")
+	snapshot.Stations["a"] = &Station{ID: "a", Lat: 52.37, Lon: 4.89}
+
+	require.Len(t, snapshot.NearbyStations(52.37, 4.89, 1000), 1)
+
+	snapshot.Stations["b"] = &Station{ID: "b", Lat: 52.371, Lon: 4.891}
+	assert.Len(t, snapshot.NearbyStations(52.37, 4.89, 1000), 2, "adding a station should rebuild the index")
+}
+
+func TestAQSnapshot_NearbyStations_RebuildsOnRefetch(t *testing.T) {
+	snapshot := NewAQSnapshot("test")
+	snapshot.FetchedAt = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	snapshot.Stations["a"] = &Station{ID: "a", Lat: 52.37, Lon: 4.89}
+	require.Len(t, snapshot.NearbyStations(52.37, 4.89, 1000), 1)
+
+	// Same station count, new contents: the station moved to Utrecht
+	snapshot.Stations["a"] = &Station{ID: "a", Lat: 52.09, Lon: 5.12}
+	snapshot.FetchedAt = snapshot.FetchedAt.Add(15 * time.Minute)
+	assert.Empty(t, snapshot.NearbyStations(52.37, 4.89, 1000), "a new fetch should rebuild the index")
+	assert.Len(t, snapshot.NearbyStations(52.09, 5.12, 1000), 1)
+}
+
+func TestAQSnapshot_NearbyStations_RebuildsOnSetStation(t *testing.T) {
+	snapshot := NewAQSnapshot("test")
+	snapshot.SetStation(&Station{ID: "a", Lat: 52.37, Lon: 4.89})
+	require.Len(t, snapshot.NearbyStations(52.37, 4.89, 1000), 1)
+
+	snapshot.SetStation(&Station{ID: "a", Lat: 52.09, Lon: 5.12})
+	assert.Empty(t, snapshot.NearbyStations(52.37, 4.89, 1000), "a replaced station should be indexed at its new location")
+	assert.Len(t, snapshot.NearbyStations(52.09, 5.12, 1000), 1)
+}
+
+func TestAQSnapshot_NearestStation_MatchesBruteForce(t *testing.T) {
+	snapshot := benchmarkSnapshot(500)
+	radius := DefaultInterpolationConfig().MaxDistance
+
+	for _, p := range benchmarkRoutePoints() {
+		var want *Station
+		wantDistance := math.Inf(1)
+		for _, station := range snapshot.StationList() {
+			if d := haversineDistance(p.Lat, p.Lon, station.Lat, station.Lon); d < wantDistance {
+				want, wantDistance = station, d
+			}
+		}
+
+		got, distance := snapshot.NearestStation(p.Lat, p.Lon, radius)
+		require.NotNil(t, got, "point (%f, %f)", p.Lat, p.Lon)
+		require.Equal(t, want.ID, got.ID, "point (%f, %f): nearest station", p.Lat, p.Lon)
+		require.Equal(t, wantDistance, distance, "point (%f, %f): distance", p.Lat, p.Lon)
+	}
+}
+
+func TestAQSnapshot_NearestStation_OutOfRange(t *testing.T) {
+	snapshot := NewAQSnapshot("test")
+	snapshot.Stations["a"] = &Station{ID: "a", Lat: 52.37, Lon: 4.89}
+
+	got, _ := snapshot.NearestStation(53.22, 6.57, 50000)
+	assert.Nil(t, got, "expected no station within range")
+}
+
+func BenchmarkStationsInRange(b *testing.B) {
+	snapshot := benchmarkSnapshot(500)
+	interpolator := NewInterpolator(DefaultInterpolationConfig())
+	points := benchmarkRoutePoints()
+	radius := interpolator.config.MaxDistance
+
+	b.Run("BruteForce", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, p := range points {
+				interpolator.stationsInRange(p.Lat, p.Lon, snapshot.StationList())
+			}
+		}
+	})
+
+	b.Run("Indexed", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, p := range points {
+				interpolator.stationsInRange(p.Lat, p.Lon, snapshot.stationCandidates(p.Lat, p.Lon, radius))
+			}
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package airquality
 
 import (
+	"context"
 	"errors"
 	"math"
 	"sort"
+	"time"
 )
 
 // Interpolation errors.
@@ -46,6 +48,18 @@ type InterpolationConfig struct {
 	// MediumConfidenceMaxDistance is the max distance for MEDIUM confidence.
 	// Default: 15000 (15km).
 	MediumConfidenceMaxDistance float64
+
+	// Method selects the interpolation algorithm. Default: MethodIDW.
+	// MethodKriging falls back to IDW when fewer than three stations contribute.
+	Method InterpolationMethod
+
+	// Diurnal is the daily cycle model used by InterpolateForecast.
+	// Default: DefaultDiurnalModel().
+	Diurnal DiurnalModel
+
+	// Metrics receives latency, station count and outcome of every
+	// Interpolate call (optional). See NewInterpolationMetrics.
+	Metrics InterpolationRecorder
 }
 
 // DefaultInterpolationConfig returns the default configuration.
@@ -57,6 +71,8 @@ func DefaultInterpolationConfig() InterpolationConfig {
 		Power:                       2.0,
 		HighConfidenceMaxDistance:   5000,  // 5km
 		MediumConfidenceMaxDistance: 15000, // 15km
+		Method:                      MethodIDW,
+		Diurnal:                     DefaultDiurnalModel(),
 	}
 }
 
@@ -71,6 +87,9 @@ type InterpolatedValue struct {
 	// Confidence indicates the data quality.
 	Confidence Confidence
 
+	// AQICategory is the European Air Quality Index band for this value.
+	AQICategory AQICategory
+
 	// StationsUsed is the number of stations used in interpolation.
 	StationsUsed int
 
@@ -79,6 +98,15 @@ type InterpolatedValue struct {
 
 	// ContributingStations lists the stations that contributed to this value.
 	ContributingStations []StationContribution
+
+	// Trend is the direction of the value over the last hour, from the
+	// contributing stations' archived measurements. It is TrendUnknown when
+	// no history is available.
+	Trend Trend
+
+	// Delta is the change in µg/m³ over the last hour, or nil if the trend
+	// is unknown.
+	Delta *float64
 }
 
 // StationContribution describes a station's contribution to an interpolated value.
@@ -94,6 +122,9 @@ type InterpolatedPoint struct {
 	Lat    float64
 	Lon    float64
 	Values map[Pollutant]*InterpolatedValue
+
+	// AQICategory is the worst category across all interpolated pollutants.
+	AQICategory AQICategory
 }
 
 // stationDistance pairs a station with its distance from the query point.
@@ -127,30 +158,47 @@ func NewInterpolator(config InterpolationConfig) *Interpolator {
 	if config.MediumConfidenceMaxDistance <= 0 {
 		config.MediumConfidenceMaxDistance = DefaultInterpolationConfig().MediumConfidenceMaxDistance
 	}
+	if config.Method == "" {
+		config.Method = DefaultInterpolationConfig().Method
+	}
+	if config.Diurnal.Profiles == nil {
+		config.Diurnal.Profiles = DefaultDiurnalModel().Profiles
+	}
+	if config.Diurnal.MaxHorizon <= 0 {
+		config.Diurnal.MaxHorizon = DefaultDiurnalModel().MaxHorizon
+	}
+	if config.Diurnal.Location == nil {
+		config.Diurnal.Location = defaultForecastLocation()
+	}
 	return &Interpolator{config: config}
 }
 
 // Interpolate estimates air quality values at the given location.
 func (i *Interpolator) Interpolate(lat, lon float64, snapshot *AQSnapshot) (*InterpolatedPoint, error) {
-	if snapshot == nil || len(snapshot.Stations) == 0 {
-		return nil, ErrNoStationsInRange
+	if i.config.Metrics == nil {
+		result, _, err := i.interpolate(lat, lon, snapshot)
+		return result, err
 	}
 
-	// Calculate distances to all stations
-	var stationDistances []stationDistance
+	start := time.Now()
+	result, stationsUsed, err := i.interpolate(lat, lon, snapshot)
+	i.config.Metrics.RecordInterpolation(time.Since(start), stationsUsed, err)
+	return result, err
+}
 
-	for _, station := range snapshot.Stations {
-		dist := haversineDistance(lat, lon, station.Lat, station.Lon)
-		if dist <= i.config.MaxDistance {
-			stationDistances = append(stationDistances, stationDistance{
-				station:  station,
-				distance: dist,
-			})
-		}
+// interpolate performs the interpolation and also returns the number of
+// stations within range that were considered.
+func (i *Interpolator) interpolate(lat, lon float64, snapshot *AQSnapshot) (*InterpolatedPoint, int, error) {
+	if snapshot == nil || snapshot.StationCount() == 0 {
+		return nil, 0, ErrNoStationsInRange
 	}
 
+	// Pre-filter candidates using the spatial index, then compute exact distances
+	candidates := snapshot.stationCandidates(lat, lon, i.config.MaxDistance)
+	stationDistances := i.stationsInRange(lat, lon, candidates)
+
 	if len(stationDistances) < i.config.MinStations {
-		return nil, ErrNoStationsInRange
+		return nil, len(stationDistances), ErrNoStationsInRange
 	}
 
 	// Sort by distance
@@ -165,9 +213,10 @@ func (i *Interpolator) Interpolate(lat, lon float64, snapshot *AQSnapshot) (*Int
 
 	// Interpolate each pollutant
 	result := &InterpolatedPoint{
-		Lat:    lat,
-		Lon:    lon,
-		Values: make(map[Pollutant]*InterpolatedValue),
+		Lat:         lat,
+		Lon:         lon,
+		Values:      make(map[Pollutant]*InterpolatedValue),
+		AQICategory: AQIUnknown,
 	}
 
 	for _, pollutant := range []Pollutant{PollutantNO2, PollutantPM25, PollutantPM10, PollutantO3} {
@@ -176,21 +225,31 @@ func (i *Interpolator) Interpolate(lat, lon float64, snapshot *AQSnapshot) (*Int
 			// Skip pollutants with no data
 			continue
 		}
+		value.AQICategory = CategoryFor(pollutant, value.Value)
+		if value.AQICategory.WorseThan(result.AQICategory) {
+			result.AQICategory = value.AQICategory
+		}
 		result.Values[pollutant] = value
 	}
 
 	if len(result.Values) == 0 {
-		return nil, ErrInsufficientData
+		return nil, len(stationDistances), ErrInsufficientData
 	}
 
-	return result, nil
+	return result, len(stationDistances), nil
 }
 
-// InterpolateMultiple estimates air quality values at multiple points.
-func (i *Interpolator) InterpolateMultiple(points []struct{ Lat, Lon float64 }, snapshot *AQSnapshot) ([]*InterpolatedPoint, error) {
+// InterpolateMultiple estimates air quality values at multiple points. ctx is
+// checked between points: if it ends early, the results so far are returned
+// with the context's error.
+func (i *Interpolator) InterpolateMultiple(ctx context.Context, points []struct{ Lat, Lon float64 }, snapshot *AQSnapshot) ([]*InterpolatedPoint, error) {
 	results := make([]*InterpolatedPoint, 0, len(points))
 
 	for _, p := range points {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
 		result, err := i.Interpolate(p.Lat, p.Lon, snapshot)
 		if err != nil {
 			// Include nil for failed interpolations
@@ -203,13 +262,30 @@ func (i *Interpolator) InterpolateMultiple(points []struct{ Lat, Lon float64 },
 	return results, nil
 }
 
-// interpolatePollutant performs IDW interpolation for a single pollutant.
+// stationsInRange computes the exact distance to each station and keeps those
+// within MaxDistance.
+func (i *Interpolator) stationsInRange(lat, lon float64, stations []*Station) []stationDistance {
+	var stationDistances []stationDistance
+	for _, station := range stations {
+		dist := haversineDistance(lat, lon, station.Lat, station.Lon)
+		if dist <= i.config.MaxDistance {
+			stationDistances = append(stationDistances, stationDistance{
+				station:  station,
+				distance: dist,
+			})
+		}
+	}
+	return stationDistances
+}
+
+// interpolatePollutant interpolates a single pollutant using the configured method.
 func (i *Interpolator) interpolatePollutant(
 	pollutant Pollutant,
 	stationDistances []stationDistance,
 	snapshot *AQSnapshot,
 ) (*InterpolatedValue, error) {
 	contributions := make([]StationContribution, 0, len(stationDistances))
+	stations := make([]*Station, 0, len(stationDistances))
 	var totalWeight float64
 
 	for _, sd := range stationDistances {
@@ -246,6 +322,7 @@ func (i *Interpolator) interpolatePollutant(
 			Value:     m.Value,
 			Weight:    weight,
 		})
+		stations = append(stations, sd.station)
 		totalWeight += weight
 	}
 
@@ -253,6 +330,13 @@ func (i *Interpolator) interpolatePollutant(
 		return nil, ErrInsufficientData
 	}
 
+	if i.config.Method == MethodKriging && len(contributions) >= minKrigingStations {
+		if value, err := i.krige(pollutant, contributions, stations); err == nil {
+			return value, nil
+		}
+		// Variogram could not be fitted (e.g., identical values); fall back to IDW
+	}
+
 	// Normalize weights and calculate weighted average
 	var interpolatedValue float64
 	for idx := range contributions {
@@ -271,6 +355,7 @@ func (i *Interpolator) interpolatePollutant(
 		StationsUsed:           len(contributions),
 		NearestStationDistance: nearestDistance,
 		ContributingStations:   contributions,
+		Trend:                  TrendUnknown,
 	}, nil
 }
 
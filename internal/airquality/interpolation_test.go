@@ -1,6 +1,7 @@
 package airquality_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -284,7 +285,7 @@ func TestInterpolator_InterpolateMultiple(t *testing.T) {
 		{52.365, 4.94}, // Near Amsterdam-Oost
 	}
 
-	results, err := interpolator.InterpolateMultiple(points, snapshot)
+	results, err := interpolator.InterpolateMultiple(context.Background(), points, snapshot)
 	require.NoError(t, err)
 	assert.Len(t, results, 4)
 
@@ -302,6 +303,43 @@ func TestInterpolator_InterpolateMultiple(t *testing.T) {
 	assert.NotNil(t, results[3])
 }
 
+// cancelAfter is a context that reports cancellation after n Err checks.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestInterpolator_InterpolateMultiple_Cancelled(t *testing.T) {
+	snapshot := createTestSnapshot()
+	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+
+	points := make([]struct{ Lat, Lon float64 }, 10)
+	for i := range points {
+		points[i] = struct{ Lat, Lon float64 }{52.370, 4.89}
+	}
+
+	// Cancelled after the third point
+	ctx := &cancelAfter{Context: context.Background(), n: 3}
+	results, err := interpolator.InterpolateMultiple(ctx, points, snapshot)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, results, 3)
+
+	// Already cancelled: no work is done
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = interpolator.InterpolateMultiple(cancelled, points, snapshot)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results)
+}
+
 func TestInterpolator_StationContributions(t *testing.T) {
 	snapshot := createTestSnapshot()
 	interpolator := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
@@ -0,0 +1,223 @@
+package airquality
+
+import (
+	"errors"
+	"math"
+)
+
+// InterpolationMethod selects the spatial interpolation algorithm.
+type InterpolationMethod string
+
+const (
+	// MethodIDW uses inverse distance weighting (default).
+	MethodIDW InterpolationMethod = "IDW"
+	// MethodKriging uses ordinary kriging with an exponential variogram.
+	MethodKriging InterpolationMethod = "KRIGING"
+)
+
+const (
+	// minKrigingStations is the minimum number of stations needed to fit a variogram.
+	// With fewer stations, interpolation falls back to IDW.
+	minKrigingStations = 3
+
+	// krigingNuggetRatio is the nugget (measurement noise) as a fraction of the sill.
+	// A non-zero nugget smooths the field between stations and keeps the system well-conditioned.
+	krigingNuggetRatio = 0.1
+
+	// Kriging variance thresholds, as a fraction of the total sill, for confidence levels.
+	krigingHighConfidenceVariance   = 0.35
+	krigingMediumConfidenceVariance = 0.75
+)
+
+// errSingularSystem indicates the kriging system could not be solved.
+var errSingularSystem = errors.New("singular kriging system")
+
+// variogram is an exponential semivariogram model:
+// gamma(h) = nugget + partialSill * (1 - exp(-3h / range)) for h > 0, and 0 at h = 0.
+type variogram struct {
+	nugget      float64
+	partialSill float64
+	rangeMeters float64
+}
+
+// gamma evaluates the variogram at distance h (meters).
+func (v variogram) gamma(h float64) float64 {
+	if h < 1 {
+		return 0
+	}
+	return v.nugget + v.partialSill*(1-math.Exp(-3*h/v.rangeMeters))
+}
+
+// sill returns the total sill (nugget + partial sill).
+func (v variogram) sill() float64 {
+	return v.nugget + v.partialSill
+}
+
+// fitVariogram fits an exponential variogram to the contributing stations.
+// The sill is taken from the sample variance and the range is chosen by a
+// least-squares search over fractions of the maximum station separation.
+// Returns false when the data has no spatial variance to model.
+func fitVariogram(contributions []StationContribution, stations []*Station) (variogram, bool) {
+	n := len(contributions)
+
+	var mean float64
+	for _, c := range contributions {
+		mean += c.Value
+	}
+	mean /= float64(n)
+
+	var variance float64
+	for _, c := range contributions {
+		variance += (c.Value - mean) * (c.Value - mean)
+	}
+	variance /= float64(n - 1)
+	if variance < 1e-9 {
+		return variogram{}, false
+	}
+
+	// Empirical semivariances for every station pair
+	type pair struct{ distance, semivariance float64 }
+	pairs := make([]pair, 0, n*(n-1)/2)
+	var maxDistance float64
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			d := haversineDistance(stations[a].Lat, stations[a].Lon, stations[b].Lat, stations[b].Lon)
+			diff := contributions[a].Value - contributions[b].Value
+			pairs = append(pairs, pair{distance: d, semivariance: 0.5 * diff * diff})
+			if d > maxDistance {
+				maxDistance = d
+			}
+		}
+	}
+	if maxDistance < 1 {
+		return variogram{}, false
+	}
+
+	nugget := krigingNuggetRatio * variance
+	best := variogram{nugget: nugget, partialSill: variance - nugget, rangeMeters: maxDistance}
+	bestErr := math.Inf(1)
+	for _, fraction := range []float64{0.25, 0.5, 0.75, 1, 1.5, 2, 3} {
+		candidate := variogram{nugget: nugget, partialSill: variance - nugget, rangeMeters: fraction * maxDistance}
+		var sqErr float64
+		for _, p := range pairs {
+			diff := candidate.gamma(p.distance) - p.semivariance
+			sqErr += diff * diff
+		}
+		if sqErr < bestErr {
+			best, bestErr = candidate, sqErr
+		}
+	}
+
+	return best, true
+}
+
+// krige performs ordinary kriging for a single pollutant at the query point.
+// The contribution weights are replaced with the kriging weights, which sum to 1
+// but may be slightly negative for screened stations.
+func (i *Interpolator) krige(
+	pollutant Pollutant,
+	contributions []StationContribution,
+	stations []*Station,
+) (*InterpolatedValue, error) {
+	model, ok := fitVariogram(contributions, stations)
+	if !ok {
+		return nil, errSingularSystem
+	}
+
+	// Build the ordinary kriging system:
+	// [ gamma(h_ij)  1 ] [w ]   [gamma(h_i0)]
+	// [ 1            0 ] [mu] = [1          ]
+	n := len(contributions)
+	size := n + 1
+	matrix := make([][]float64, size)
+	rhs := make([]float64, size)
+	targetGamma := make([]float64, n)
+	for a := 0; a < n; a++ {
+		matrix[a] = make([]float64, size)
+		for b := 0; b < n; b++ {
+			d := haversineDistance(stations[a].Lat, stations[a].Lon, stations[b].Lat, stations[b].Lon)
+			matrix[a][b] = model.gamma(d)
+		}
+		matrix[a][n] = 1
+		targetGamma[a] = model.gamma(contributions[a].Distance)
+		rhs[a] = targetGamma[a]
+	}
+	matrix[n] = make([]float64, size)
+	for b := 0; b < n; b++ {
+		matrix[n][b] = 1
+	}
+	rhs[n] = 1
+
+	solution, err := solveLinearSystem(matrix, rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	var value, variance float64
+	for idx := range contributions {
+		contributions[idx].Weight = solution[idx]
+		value += solution[idx] * contributions[idx].Value
+		variance += solution[idx] * targetGamma[idx]
+	}
+	variance += solution[n] // Lagrange multiplier
+
+	return &InterpolatedValue{
+		Pollutant:              pollutant,
+		Value:                  value,
+		Confidence:             krigingConfidence(variance, model.sill()),
+		StationsUsed:           n,
+		NearestStationDistance: contributions[0].Distance,
+		ContributingStations:   contributions,
+		Trend:                  TrendUnknown,
+	}, nil
+}
+
+// krigingConfidence maps the kriging variance, relative to the sill, to a confidence level.
+func krigingConfidence(variance, sill float64) Confidence {
+	ratio := variance / sill
+	switch {
+	case ratio <= krigingHighConfidenceVariance:
+		return ConfidenceHigh
+	case ratio <= krigingMediumConfidenceVariance:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// solveLinearSystem solves A·x = b using Gaussian elimination with partial pivoting.
+// The inputs are modified in place.
+func solveLinearSystem(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errSingularSystem
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, nil
+}
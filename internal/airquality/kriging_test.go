@@ -0,0 +1,94 @@
+package airquality_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+// transectRoughness interpolates NO2 along a west-east transect through the
+// Amsterdam test stations and returns the sum of squared second differences,
+// a measure of how "bumpy" the interpolated field is.
+func transectRoughness(t *testing.T, interpolator *airquality.Interpolator, snapshot *airquality.AQSnapshot) float64 {
+	t.Helper()
+
+	var values []float64
+	for lon := 4.82; lon <= 4.97; lon += 0.005 {
+		result, err := interpolator.Interpolate(52.368, lon, snapshot)
+		require.NoError(t, err)
+		no2 := result.Values[airquality.PollutantNO2]
+		require.NotNil(t, no2)
+		values = append(values, no2.Value)
+	}
+
+	var roughness float64
+	for k := 1; k < len(values)-1; k++ {
+		d2 := values[k+1] - 2*values[k] + values[k-1]
+		roughness += d2 * d2
+	}
+	return roughness
+}
+
+func TestInterpolator_Kriging_SmootherThanIDW(t *testing.T) {
+	snapshot := createTestSnapshot()
+
+	idw := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+
+	krigingCfg := airquality.DefaultInterpolationConfig()
+	krigingCfg.Method = airquality.MethodKriging
+	kriging := airquality.NewInterpolator(krigingCfg)
+
+	idwRoughness := transectRoughness(t, idw, snapshot)
+	krigingRoughness := transectRoughness(t, kriging, snapshot)
+
+	assert.Less(t, krigingRoughness, idwRoughness,
+		"kriging field should be smoother than IDW (kriging=%f, idw=%f)", krigingRoughness, idwRoughness)
+}
+
+func TestInterpolator_Kriging_WeightsAndRange(t *testing.T) {
+	snapshot := createTestSnapshot()
+	cfg := airquality.DefaultInterpolationConfig()
+	cfg.Method = airquality.MethodKriging
+	interpolator := airquality.NewInterpolator(cfg)
+
+	result, err := interpolator.Interpolate(52.370, 4.89, snapshot)
+	require.NoError(t, err)
+
+	no2 := result.Values[airquality.PollutantNO2]
+	require.NotNil(t, no2)
+	assert.Equal(t, 3, no2.StationsUsed)
+	assert.True(t, no2.Value > 25 && no2.Value < 35, "NO2 should lie within station values: got %f", no2.Value)
+
+	var totalWeight float64
+	for _, c := range no2.ContributingStations {
+		totalWeight += c.Weight
+	}
+	assert.InDelta(t, 1.0, totalWeight, 1e-9, "kriging weights should sum to 1")
+}
+
+func TestInterpolator_Kriging_FallsBackToIDWWithFewStations(t *testing.T) {
+	snapshot := createTestSnapshot()
+
+	idw := airquality.NewInterpolator(airquality.DefaultInterpolationConfig())
+	cfg := airquality.DefaultInterpolationConfig()
+	cfg.Method = airquality.MethodKriging
+	kriging := airquality.NewInterpolator(cfg)
+
+	// PM25 is only measured at two Amsterdam stations, so kriging must fall back to IDW
+	idwResult, err := idw.Interpolate(52.370, 4.89, snapshot)
+	require.NoError(t, err)
+	krigingResult, err := kriging.Interpolate(52.370, 4.89, snapshot)
+	require.NoError(t, err)
+
+	idwPM25 := idwResult.Values[airquality.PollutantPM25]
+	krigingPM25 := krigingResult.Values[airquality.PollutantPM25]
+	require.NotNil(t, idwPM25)
+	require.NotNil(t, krigingPM25)
+	assert.Equal(t, 2, krigingPM25.StationsUsed)
+	assert.True(t, math.Abs(idwPM25.Value-krigingPM25.Value) < 1e-9, "expected IDW fallback value")
+	assert.Equal(t, idwPM25.Confidence, krigingPM25.Confidence)
+}
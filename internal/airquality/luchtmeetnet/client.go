@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +21,10 @@ const (
 
 	// ProviderName identifies this provider.
 	ProviderName = "luchtmeetnet"
+
+	// FallbackProviderName identifies a secondary Luchtmeetnet-compatible
+	// feed used when the primary API fails.
+	FallbackProviderName = "luchtmeetnet-fallback"
 )
 
 // ClientConfig holds configuration for the Luchtmeetnet client.
@@ -26,12 +32,20 @@ type ClientConfig struct {
 	// BaseURL is the API base URL (defaults to DefaultBaseURL).
 	BaseURL string
 
+	// Name identifies the client in snapshots and health tracking
+	// (defaults to ProviderName).
+	Name string
+
 	// HTTPClient is the HTTP client to use (must implement HTTPDoer).
 	// If nil, a default resilient client will be created.
 	HTTPClient HTTPDoer
 
 	// Timeout for individual API requests (default: 10s).
 	Timeout time.Duration
+
+	// Registry is the provider registry the default client registers with
+	// for health tracking (optional).
+	Registry *resilience.Registry
 }
 
 // HTTPDoer abstracts HTTP request execution.
@@ -41,6 +55,7 @@ type HTTPDoer interface {
 
 // Client is a Luchtmeetnet API client.
 type Client struct {
+	name       string
 	baseURL    string
 	httpClient HTTPDoer
 }
@@ -52,6 +67,11 @@ func NewClient(cfg ClientConfig) *Client {
 		baseURL = DefaultBaseURL
 	}
 
+	name := cfg.Name
+	if name == "" {
+		name = ProviderName
+	}
+
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
 		timeout := cfg.Timeout
@@ -59,15 +79,17 @@ func NewClient(cfg ClientConfig) *Client {
 			timeout = 10 * time.Second
 		}
 		httpClient = resilience.NewClient(resilience.ClientConfig{
-			Name:            "luchtmeetnet",
+			Name:            name,
 			Timeout:         timeout,
 			MaxRetries:      3,
 			InitialInterval: 200 * time.Millisecond,
 			MaxInterval:     5 * time.Second,
+			Registry:        cfg.Registry,
 		})
 	}
 
 	return &Client{
+		name:       name,
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 		httpClient: httpClient,
 	}
@@ -147,7 +169,7 @@ func (c *Client) fetchStationsPage(ctx context.Context, page int) ([]*airquality
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, 0, fmt.Errorf("unexpected status %d from stations endpoint", resp.StatusCode)
+		return nil, 0, fmt.Errorf("stations endpoint: %w", &resilience.StatusError{StatusCode: resp.StatusCode})
 	}
 
 	var result stationsResponse
@@ -166,11 +188,25 @@ func (c *Client) fetchStationsPage(ctx context.Context, page int) ([]*airquality
 
 // FetchLatestMeasurements retrieves the latest measurements for all stations.
 func (c *Client) FetchLatestMeasurements(ctx context.Context) ([]*airquality.Measurement, error) {
+	return c.fetchMeasurements(ctx, url.Values{})
+}
+
+// FetchMeasurementsSince retrieves the measurements for all stations taken
+// at or after since, so a cached snapshot can be updated incrementally.
+func (c *Client) FetchMeasurementsSince(ctx context.Context, since time.Time) ([]*airquality.Measurement, error) {
+	return c.fetchMeasurements(ctx, url.Values{
+		"start": {since.UTC().Format(time.RFC3339)},
+		"end":   {time.Now().UTC().Format(time.RFC3339)},
+	})
+}
+
+// fetchMeasurements fetches every page of measurements matching query.
+func (c *Client) fetchMeasurements(ctx context.Context, query url.Values) ([]*airquality.Measurement, error) {
 	var allMeasurements []*airquality.Measurement
 	page := 1
 
 	for {
-		measurements, lastPage, err := c.fetchMeasurementsPage(ctx, page)
+		measurements, lastPage, err := c.fetchMeasurementsPage(ctx, query, page)
 		if err != nil {
 			return nil, err
 		}
@@ -186,9 +222,13 @@ func (c *Client) FetchLatestMeasurements(ctx context.Context) ([]*airquality.Mea
 }
 
 // fetchMeasurementsPage fetches a single page of measurements.
-func (c *Client) fetchMeasurementsPage(ctx context.Context, page int) ([]*airquality.Measurement, int, error) {
-	url := fmt.Sprintf("%s/measurements?page=%d", c.baseURL, page)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+func (c *Client) fetchMeasurementsPage(ctx context.Context, query url.Values, page int) ([]*airquality.Measurement, int, error) {
+	params := url.Values{"page": {strconv.Itoa(page)}}
+	for key, values := range query {
+		params[key] = values
+	}
+	reqURL := c.baseURL + "/measurements?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 	if err != nil {
 		return nil, 0, fmt.Errorf("create request: %w", err)
 	}
@@ -200,7 +240,7 @@ func (c *Client) fetchMeasurementsPage(ctx context.Context, page int) ([]*airqua
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, 0, fmt.Errorf("unexpected status %d from measurements endpoint", resp.StatusCode)
+		return nil, 0, fmt.Errorf("measurements endpoint: %w", &resilience.StatusError{StatusCode: resp.StatusCode})
 	}
 
 	var result measurementsResponse
@@ -231,7 +271,7 @@ func (c *Client) FetchSnapshot(ctx context.Context) (*airquality.AQSnapshot, err
 		return nil, fmt.Errorf("fetch measurements: %w", err)
 	}
 
-	snapshot := airquality.NewAQSnapshot(ProviderName)
+	snapshot := airquality.NewAQSnapshot(c.name)
 	snapshot.FetchedAt = time.Now()
 
 	for _, s := range stations {
@@ -277,7 +317,7 @@ func (c *Client) toMeasurement(m *measurementData) *airquality.Measurement {
 		StationID:  m.StationNumber,
 		Pollutant:  pollutant,
 		Value:      m.Value,
-		Unit:       "µg/m³",
+		Unit:       airquality.UnitMicrogramsPerCubicMeter,
 		MeasuredAt: measuredAt,
 	}
 }
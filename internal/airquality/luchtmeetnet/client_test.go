@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -188,6 +189,42 @@ func TestClient_FetchLatestMeasurements(t *testing.T) {
 	assert.Equal(t, "µg/m³", measurements[0].Unit)
 }
 
+func TestClient_FetchMeasurementsSince(t *testing.T) {
+	since := time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/measurements", r.URL.Path)
+		assert.Equal(t, "2024-01-15T13:00:00Z", r.URL.Query().Get("start"))
+		assert.NotEmpty(t, r.URL.Query().Get("end"))
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+
+		response := map[string]interface{}{
+			"pagination": map[string]int{"current_page": 1, "last_page": 1},
+			"data": []map[string]interface{}{
+				{
+					"station_number":     "NL10938",
+					"formula":            "NO2",
+					"value":              34.1,
+					"timestamp_measured": "2024-01-15T15:00:00+01:00",
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := luchtmeetnet.NewClient(luchtmeetnet.ClientConfig{
+		BaseURL:    server.URL,
+		HTTPClient: http.DefaultClient,
+	})
+
+	measurements, err := client.FetchMeasurementsSince(context.Background(), since)
+	require.NoError(t, err)
+	require.Len(t, measurements, 1)
+	assert.Equal(t, 34.1, measurements[0].Value)
+}
+
 func TestClient_FetchLatestMeasurements_SkipsUnknownPollutants(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := map[string]interface{}{
@@ -297,6 +334,24 @@ func TestClient_FetchSnapshot(t *testing.T) {
 	assert.Equal(t, 2, callCount) // Stations + Measurements
 }
 
+func TestClient_FetchSnapshot_Name(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"pagination": {"current_page": 1, "last_page": 1}, "data": []}`))
+	}))
+	defer server.Close()
+
+	client := luchtmeetnet.NewClient(luchtmeetnet.ClientConfig{
+		BaseURL:    server.URL,
+		Name:       luchtmeetnet.FallbackProviderName,
+		HTTPClient: http.DefaultClient,
+	})
+
+	snapshot, err := client.FetchSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, luchtmeetnet.FallbackProviderName, snapshot.Provider)
+}
+
 func TestClient_FetchStations_ServerError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -0,0 +1,100 @@
+package airquality
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/breatheroute/breatheroute/internal/airquality"
+
+// Interpolation outcomes recorded by InterpolationMetrics.
+const (
+	OutcomeOK               = "ok"
+	OutcomeNoStations       = "no_stations_in_range"
+	OutcomeInsufficientData = "insufficient_data"
+)
+
+// InterpolationRecorder receives one observation per Interpolate call.
+// stationsUsed is the number of stations within range that were considered
+// (after the MaxStations cap); it is zero when none were in range.
+type InterpolationRecorder interface {
+	RecordInterpolation(duration time.Duration, stationsUsed int, err error)
+}
+
+// InterpolationMetrics records interpolation latency, station counts and
+// outcomes as OpenTelemetry instruments on the global meter provider.
+type InterpolationMetrics struct {
+	duration     metric.Float64Histogram
+	stationsUsed metric.Int64Histogram
+	total        metric.Int64Counter
+}
+
+// Ensure InterpolationMetrics implements InterpolationRecorder interface.
+var _ InterpolationRecorder = (*InterpolationMetrics)(nil)
+
+// NewInterpolationMetrics creates interpolation metrics instruments.
+func NewInterpolationMetrics() (*InterpolationMetrics, error) {
+	meter := otel.Meter(meterName)
+
+	duration, err := meter.Float64Histogram(
+		"airquality.interpolation.duration",
+		metric.WithDescription("Duration of air quality interpolations in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stationsUsed, err := meter.Int64Histogram(
+		"airquality.interpolation.stations_used",
+		metric.WithDescription("Number of stations within range per interpolation"),
+		metric.WithUnit("{station}"),
+		metric.WithExplicitBucketBoundaries(0, 1, 2, 3, 4, 5, 7, 10, 15, 20),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := meter.Int64Counter(
+		"airquality.interpolation.total",
+		metric.WithDescription("Total number of air quality interpolations by outcome"),
+		metric.WithUnit("{interpolation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InterpolationMetrics{
+		duration:     duration,
+		stationsUsed: stationsUsed,
+		total:        total,
+	}, nil
+}
+
+// RecordInterpolation records metrics for a single interpolation.
+func (m *InterpolationMetrics) RecordInterpolation(duration time.Duration, stationsUsed int, err error) {
+	attrs := metric.WithAttributes(attribute.String("outcome", interpolationOutcome(err)))
+
+	// Use background context for metrics to avoid context cancellation issues
+	ctx := context.TODO()
+	m.duration.Record(ctx, duration.Seconds(), attrs)
+	m.stationsUsed.Record(ctx, int64(stationsUsed), attrs)
+	m.total.Add(ctx, 1, attrs)
+}
+
+// interpolationOutcome maps an interpolation error to its outcome label.
+func interpolationOutcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.Is(err, ErrNoStationsInRange):
+		return OutcomeNoStations
+	default:
+		return OutcomeInsufficientData
+	}
+}
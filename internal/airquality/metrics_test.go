@@ -0,0 +1,104 @@
+package airquality_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+// recordedInterpolation is a single observation captured by fakeRecorder.
+type recordedInterpolation struct {
+	duration     time.Duration
+	stationsUsed int
+	err          error
+}
+
+// fakeRecorder captures interpolation observations.
+type fakeRecorder struct {
+	mu           sync.Mutex
+	observations []recordedInterpolation
+}
+
+func (f *fakeRecorder) RecordInterpolation(duration time.Duration, stationsUsed int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.observations = append(f.observations, recordedInterpolation{duration, stationsUsed, err})
+}
+
+func TestInterpolator_RecordsMetrics(t *testing.T) {
+	recorder := &fakeRecorder{}
+	cfg := airquality.DefaultInterpolationConfig()
+	cfg.Metrics = recorder
+	interpolator := airquality.NewInterpolator(cfg)
+	snapshot := createTestSnapshot()
+
+	// Amsterdam: three nearby stations within range
+	_, err := interpolator.Interpolate(52.37, 4.90, snapshot)
+	require.NoError(t, err)
+
+	// Groningen: no stations within 50km
+	_, err = interpolator.Interpolate(53.2194, 6.5665, snapshot)
+	require.ErrorIs(t, err, airquality.ErrNoStationsInRange)
+
+	require.Len(t, recorder.observations, 2)
+
+	assert.NoError(t, recorder.observations[0].err)
+	assert.Equal(t, 3, recorder.observations[0].stationsUsed)
+	assert.Positive(t, recorder.observations[0].duration)
+
+	assert.ErrorIs(t, recorder.observations[1].err, airquality.ErrNoStationsInRange)
+	assert.Zero(t, recorder.observations[1].stationsUsed)
+}
+
+func TestInterpolationMetrics_RecordsInstruments(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	metrics, err := airquality.NewInterpolationMetrics()
+	require.NoError(t, err)
+
+	metrics.RecordInterpolation(2*time.Millisecond, 3, nil)
+	metrics.RecordInterpolation(time.Millisecond, 0, airquality.ErrNoStationsInRange)
+	metrics.RecordInterpolation(time.Millisecond, 0, airquality.ErrNoStationsInRange)
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	totals := map[string]int64{}
+	var stationCount uint64
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch m.Name {
+			case "airquality.interpolation.total":
+				sum, ok := m.Data.(metricdata.Sum[int64])
+				require.True(t, ok)
+				for _, point := range sum.DataPoints {
+					outcome, _ := point.Attributes.Value("outcome")
+					totals[outcome.AsString()] += point.Value
+				}
+			case "airquality.interpolation.stations_used":
+				hist, ok := m.Data.(metricdata.Histogram[int64])
+				require.True(t, ok)
+				for _, point := range hist.DataPoints {
+					stationCount += point.Count
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, int64(1), totals[airquality.OutcomeOK])
+	assert.Equal(t, int64(2), totals[airquality.OutcomeNoStations])
+	assert.Equal(t, uint64(3), stationCount)
+}
@@ -3,6 +3,7 @@ package airquality
 
 import (
 	"errors"
+	"sync"
 	"time"
 )
 
@@ -38,12 +39,16 @@ type Measurement struct {
 	StationID  string
 	Pollutant  Pollutant
 	Value      float64
-	Unit       string
+	Unit       string // UnitMicrogramsPerCubicMeter once normalized by the service
 	MeasuredAt time.Time
 }
 
 // AQSnapshot represents a point-in-time snapshot of air quality data.
 // This is the internal normalized format for all air quality data.
+//
+// The methods are safe for concurrent use. Providers build snapshots by
+// writing the maps directly; once a snapshot is shared, use SetStation and
+// SetMeasurement, or Clone it, rather than writing the maps.
 type AQSnapshot struct {
 	// Stations is a map of station ID to station metadata.
 	Stations map[string]*Station
@@ -55,8 +60,19 @@ type AQSnapshot struct {
 	// FetchedAt is when this snapshot was retrieved from the provider.
 	FetchedAt time.Time
 
-	// Provider identifies the data source.
+	// Provider identifies the data source. Snapshots merged by a ChainProvider
+	// list both sources (e.g., "luchtmeetnet+eea").
 	Provider string
+
+	// Fallback is true when the data was served fully or partly by a secondary provider.
+	Fallback bool
+
+	// mu guards Stations and Measurements.
+	mu sync.RWMutex
+
+	// indexMu guards index, which is built lazily by NearbyStations.
+	indexMu sync.Mutex
+	index   *stationIndex
 }
 
 // NewAQSnapshot creates a new empty snapshot.
@@ -69,20 +85,123 @@ func NewAQSnapshot(provider string) *AQSnapshot {
 	}
 }
 
+// Clone returns a deep copy of the snapshot, which can be modified without
+// affecting readers of the original.
+func (s *AQSnapshot) Clone() *AQSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clone := &AQSnapshot{
+		Stations:     make(map[string]*Station, len(s.Stations)),
+		Measurements: make(map[string]*Measurement, len(s.Measurements)),
+		FetchedAt:    s.FetchedAt,
+		Provider:     s.Provider,
+		Fallback:     s.Fallback,
+	}
+	for id, station := range s.Stations {
+		copied := *station
+		copied.Pollutants = append([]Pollutant(nil), station.Pollutants...)
+		clone.Stations[id] = &copied
+	}
+	for key, m := range s.Measurements {
+		copied := *m
+		clone.Measurements[key] = &copied
+	}
+	return clone
+}
+
+// Station returns the station with the given ID, or nil if there is none.
+func (s *AQSnapshot) Station(id string) *Station {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Stations[id]
+}
+
+// SetStation adds or replaces a station in the snapshot, discarding the
+// spatial index so a replaced station is found at its new location.
+func (s *AQSnapshot) SetStation(station *Station) {
+	s.mu.Lock()
+	s.Stations[station.ID] = station
+	s.mu.Unlock()
+	s.InvalidateIndex()
+}
+
+// StationCount returns the number of stations in the snapshot.
+func (s *AQSnapshot) StationCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.Stations)
+}
+
+// MeasurementCount returns the number of measurements in the snapshot.
+func (s *AQSnapshot) MeasurementCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.Measurements)
+}
+
 // GetMeasurement retrieves a measurement for a station and pollutant.
 func (s *AQSnapshot) GetMeasurement(stationID string, pollutant Pollutant) *Measurement {
-	key := stationID + ":" + string(pollutant)
-	return s.Measurements[key]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.measurement(stationID, pollutant)
+}
+
+// measurement looks up a measurement. Caller must hold s.mu.
+func (s *AQSnapshot) measurement(stationID string, pollutant Pollutant) *Measurement {
+	return s.Measurements[stationID+":"+string(pollutant)]
 }
 
 // SetMeasurement adds or updates a measurement in the snapshot.
 func (s *AQSnapshot) SetMeasurement(m *Measurement) {
-	key := m.StationID + ":" + string(m.Pollutant)
-	s.Measurements[key] = m
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setMeasurement(m)
+}
+
+// setMeasurement stores a measurement. Caller must hold s.mu for writing.
+func (s *AQSnapshot) setMeasurement(m *Measurement) {
+	s.Measurements[m.StationID+":"+string(m.Pollutant)] = m
+}
+
+// ApplyMeasurements returns a copy of the snapshot with measurements
+// applied, and how many of them were applied. A measurement replaces the
+// snapshot's reading for its station and pollutant unless it is older. The
+// snapshot itself is not modified, so readers holding it stay consistent.
+func (s *AQSnapshot) ApplyMeasurements(measurements []*Measurement) (*AQSnapshot, int) {
+	s.mu.RLock()
+	updated := &AQSnapshot{
+		Stations:     make(map[string]*Station, len(s.Stations)),
+		Measurements: make(map[string]*Measurement, len(s.Measurements)),
+		FetchedAt:    time.Now(),
+		Provider:     s.Provider,
+		Fallback:     s.Fallback,
+	}
+	for id, station := range s.Stations {
+		updated.Stations[id] = station
+	}
+	for key, m := range s.Measurements {
+		updated.Measurements[key] = m
+	}
+	s.mu.RUnlock()
+
+	// updated is not shared yet, so needs no locking
+	applied := 0
+	for _, m := range measurements {
+		if existing := updated.measurement(m.StationID, m.Pollutant); existing != nil && m.MeasuredAt.Before(existing.MeasuredAt) {
+			continue
+		}
+		updated.setMeasurement(m)
+		applied++
+	}
+	return updated, applied
 }
 
 // StationList returns all stations as a slice.
 func (s *AQSnapshot) StationList() []*Station {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	stations := make([]*Station, 0, len(s.Stations))
 	for _, station := range s.Stations {
 		stations = append(stations, station)
@@ -92,11 +211,29 @@ func (s *AQSnapshot) StationList() []*Station {
 
 // GetStationMeasurements returns all measurements for a given station.
 func (s *AQSnapshot) GetStationMeasurements(stationID string) []*Measurement {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	var measurements []*Measurement
 	for _, pollutant := range []Pollutant{PollutantNO2, PollutantPM25, PollutantPM10, PollutantO3} {
-		if m := s.GetMeasurement(stationID, pollutant); m != nil {
+		if m := s.measurement(stationID, pollutant); m != nil {
 			measurements = append(measurements, m)
 		}
 	}
 	return measurements
 }
+
+// LatestMeasurementAt returns the timestamp of the newest measurement in the snapshot,
+// or the zero time if there are no timestamped measurements.
+func (s *AQSnapshot) LatestMeasurementAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest time.Time
+	for _, m := range s.Measurements {
+		if m.MeasuredAt.After(latest) {
+			latest = m.MeasuredAt
+		}
+	}
+	return latest
+}
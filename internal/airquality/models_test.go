@@ -0,0 +1,80 @@
+package airquality_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+// TestAQSnapshot_ConcurrentAccess is meant to be run with -race.
+func TestAQSnapshot_ConcurrentAccess(t *testing.T) {
+	snapshot := testSnapshot()
+	interpolator := airquality.NewInterpolator(airquality.InterpolationConfig{MinStations: 1})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			snapshot.SetMeasurement(&airquality.Measurement{
+				StationID:  "NL10001",
+				Pollutant:  airquality.PollutantNO2,
+				Value:      float64(i),
+				Unit:       airquality.UnitMicrogramsPerCubicMeter,
+				MeasuredAt: time.Now(),
+			})
+			snapshot.SetStation(&airquality.Station{
+				ID:         fmt.Sprintf("NL2%04d", i),
+				Lat:        52.3 + float64(i)*0.001,
+				Lon:        4.9,
+				Pollutants: []airquality.Pollutant{airquality.PollutantNO2},
+			})
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				snapshot.GetMeasurement("NL10001", airquality.PollutantNO2)
+				snapshot.GetStationMeasurements("NL10001")
+				snapshot.StationList()
+				snapshot.LatestMeasurementAt()
+				snapshot.NearbyStations(52.37, 4.89, 5000)
+				_, _ = interpolator.Interpolate(52.37, 4.89, snapshot)
+				snapshot.Clone()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 202, snapshot.StationCount())
+	assert.Equal(t, 199.0, snapshot.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+}
+
+func TestAQSnapshot_Clone(t *testing.T) {
+	original := testSnapshot()
+	clone := original.Clone()
+
+	clone.SetMeasurement(&airquality.Measurement{
+		StationID: "NL10001",
+		Pollutant: airquality.PollutantNO2,
+		Value:     99,
+	})
+	clone.Station("NL10002").Pollutants[0] = airquality.PollutantO3
+	clone.GetMeasurement("NL10001", airquality.PollutantPM25).Value = 1
+
+	require.NotNil(t, original.GetMeasurement("NL10001", airquality.PollutantNO2))
+	assert.Equal(t, 32.5, original.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+	assert.Equal(t, 12.3, original.GetMeasurement("NL10001", airquality.PollutantPM25).Value)
+	assert.Equal(t, airquality.PollutantNO2, original.Station("NL10002").Pollutants[0])
+	assert.Equal(t, original.FetchedAt, clone.FetchedAt)
+	assert.Equal(t, original.Provider, clone.Provider)
+}
@@ -2,12 +2,30 @@ package airquality
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+	"github.com/breatheroute/breatheroute/internal/weather"
 )
 
+// cacheProviderName identifies the snapshot cache in trace annotations.
+const cacheProviderName = "airquality"
+
+// snapshotStoreKey is the key of the persisted snapshot in a SnapshotStore.
+const snapshotStoreKey = "airquality-snapshot"
+
+// ErrTimeShiftDisabled is returned by ForecastAt when time-shifted forecasting
+// is disabled via feature flag.
+var ErrTimeShiftDisabled = errors.New("time-shifted air quality forecasting is disabled")
+
 // Provider defines the interface for air quality data providers.
 type Provider interface {
 	// FetchSnapshot fetches a complete snapshot of stations and measurements.
@@ -20,6 +38,14 @@ type Provider interface {
 	FetchLatestMeasurements(ctx context.Context) ([]*Measurement, error)
 }
 
+// IncrementalProvider is implemented by providers that can fetch only the
+// measurements updated since a given time. The service then refreshes its
+// snapshot incrementally, with a full fetch every FullRefreshInterval.
+type IncrementalProvider interface {
+	// FetchMeasurementsSince fetches the measurements taken at or after since.
+	FetchMeasurementsSince(ctx context.Context, since time.Time) ([]*Measurement, error)
+}
+
 // ServiceConfig holds configuration for the air quality service.
 type ServiceConfig struct {
 	// Provider is the air quality data provider.
@@ -33,6 +59,41 @@ type ServiceConfig struct {
 
 	// StaleIfErrorTTL allows serving stale data on provider errors (default: 30 minutes).
 	StaleIfErrorTTL time.Duration
+
+	// StaleWhileRevalidate serves an expired snapshot still within
+	// StaleIfErrorTTL at once and refreshes it in the background, instead of
+	// making the caller wait for the provider.
+	StaleWhileRevalidate bool
+
+	// FullRefreshInterval is how often the full snapshot, including station
+	// metadata, is fetched when the provider implements IncrementalProvider;
+	// other refreshes only fetch updated measurements (default: 1 hour).
+	FullRefreshInterval time.Duration
+
+	// Interpolation configures the interpolator used by ForecastAt and
+	// InterpolateGrid (default: DefaultInterpolationConfig).
+	Interpolation InterpolationConfig
+
+	// MaxGridCells caps the cells in a single InterpolateGrid request
+	// (default: DefaultMaxGridCells).
+	MaxGridCells int
+
+	// FeatureFlags is the feature flag service (optional).
+	// If provided, ForecastAt can be disabled via the enable_time_shift flag.
+	FeatureFlags *featureflags.Service
+
+	// Weather is the weather service (optional). If provided, ForecastAt
+	// adjusts for the forecast wind; otherwise only the diurnal model is used.
+	Weather *weather.Service
+
+	// Store persists each refreshed snapshot so RestoreSnapshot can load it
+	// after a restart (optional; default: nothing is persisted).
+	Store cache.SnapshotStore
+
+	// History archives each refreshed snapshot by hour, for jobs that need
+	// past conditions and for the trend of interpolated values (optional;
+	// default: nothing is archived and trends are TrendUnknown).
+	History *History
 }
 
 // Service provides air quality data with caching.
@@ -41,10 +102,50 @@ type Service struct {
 	logger          zerolog.Logger
 	cacheTTL        time.Duration
 	staleIfErrorTTL time.Duration
+	revalidate      bool
+	fullRefresh     time.Duration
+	interpolator    *Interpolator
+	featureFlags    *featureflags.Service
+	weather         *weather.Service
+	maxGridCells    int
+	store           cache.SnapshotStore
+	history         *History
+
+	// refreshing is set while a provider fetch is in flight. restored is the
+	// snapshot loaded by RestoreSnapshot until a live fetch replaces it.
+	refreshing atomic.Bool
+	restored   atomic.Pointer[AQSnapshot]
+
+	// fetches coalesces concurrent refreshes into one provider call
+	fetches cache.Coalescer[*AQSnapshot]
+
+	// current is the published snapshot. Refreshes build a new snapshot and
+	// swap it in, so readers never take a lock or see a partial update.
+	current atomic.Pointer[published]
+}
 
-	mu          sync.RWMutex
-	snapshot    *AQSnapshot
-	cacheExpiry time.Time
+// published is a snapshot served by the service with its cache state. It is
+// never modified once stored in Service.current.
+type published struct {
+	snapshot  *AQSnapshot
+	expiresAt time.Time
+
+	// fullFetchAt is when the snapshot's last full fetch completed, or zero
+	// for a restored snapshot.
+	fullFetchAt time.Time
+}
+
+// fresh reports whether p holds a snapshot that has not expired.
+func (p *published) fresh() bool {
+	return p != nil && time.Now().Before(p.expiresAt)
+}
+
+// currentSnapshot returns the published snapshot, or nil if there is none.
+func (s *Service) currentSnapshot() *AQSnapshot {
+	if p := s.current.Load(); p != nil {
+		return p.snapshot
+	}
+	return nil
 }
 
 // NewService creates a new air quality service.
@@ -59,25 +160,64 @@ func NewService(cfg ServiceConfig) *Service {
 		staleIfErrorTTL = 30 * time.Minute
 	}
 
+	fullRefresh := cfg.FullRefreshInterval
+	if fullRefresh == 0 {
+		fullRefresh = time.Hour
+	}
+
+	maxGridCells := cfg.MaxGridCells
+	if maxGridCells == 0 {
+		maxGridCells = DefaultMaxGridCells
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = cache.NopStore{}
+	}
+
 	return &Service{
 		provider:        cfg.Provider,
 		logger:          cfg.Logger,
 		cacheTTL:        cacheTTL,
 		staleIfErrorTTL: staleIfErrorTTL,
+		revalidate:      cfg.StaleWhileRevalidate,
+		fullRefresh:     fullRefresh,
+		interpolator:    NewInterpolator(cfg.Interpolation),
+		featureFlags:    cfg.FeatureFlags,
+		weather:         cfg.Weather,
+		maxGridCells:    maxGridCells,
+		store:           store,
+		history:         cfg.History,
 	}
 }
 
 // GetSnapshot returns the current air quality snapshot.
-// It uses a cached version if available and not expired.
+// It uses a cached version if available and not expired. A snapshot restored
+// by RestoreSnapshot is also served, even if expired, while the first live
+// fetch is in flight.
 func (s *Service) GetSnapshot(ctx context.Context) (*AQSnapshot, error) {
+	// Serve a restored snapshot rather than queue behind the first live fetch
+	if s.refreshing.Load() {
+		if snapshot := s.restored.Load(); s.usable(snapshot) {
+			resilience.RecordCacheResult(ctx, cacheProviderName, true)
+			return snapshot, nil
+		}
+	}
+
 	// Check for fresh cache
-	s.mu.RLock()
-	if s.snapshot != nil && time.Now().Before(s.cacheExpiry) {
-		snapshot := s.snapshot
-		s.mu.RUnlock()
+	current := s.current.Load()
+	if current.fresh() {
+		resilience.RecordCacheResult(ctx, cacheProviderName, true)
+		return current.snapshot, nil
+	}
+
+	// Serve the expired snapshot while a background refresh replaces it
+	if snapshot := s.currentSnapshot(); s.revalidate && s.usable(snapshot) {
+		resilience.RecordCacheResult(ctx, cacheProviderName, true)
+		s.fetches.Go(ctx, snapshotStoreKey, s.fetchSnapshot)
 		return snapshot, nil
 	}
-	s.mu.RUnlock()
+	resilience.RecordCacheResult(ctx, cacheProviderName, false)
 
 	// Need to refresh
 	return s.refreshSnapshot(ctx)
@@ -113,7 +253,7 @@ func (s *Service) GetStationMeasurements(ctx context.Context, stationID string)
 		return nil, err
 	}
 
-	if _, ok := snapshot.Stations[stationID]; !ok {
+	if snapshot.Station(stationID) == nil {
 		return nil, ErrStationNotFound
 	}
 
@@ -126,34 +266,176 @@ func (s *Service) RefreshSnapshot(ctx context.Context) error {
 	return err
 }
 
+// ForecastAt predicts air quality at a location for a future departure time,
+// for use when evaluating alerts. The weather forecast for the location is
+// used when available. Returns ErrTimeShiftDisabled if the enable_time_shift
+// flag is off; callers should fall back to current conditions.
+func (s *Service) ForecastAt(ctx context.Context, lat, lon float64, at time.Time) (*InterpolatedPoint, error) {
+	if !s.featureFlags.IsTimeShiftEnabled(ctx) {
+		return nil, ErrTimeShiftDisabled
+	}
+
+	snapshot, err := s.GetSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.interpolator.InterpolateWeatherForecast(lat, lon, snapshot, at, s.weatherForecast(ctx, lat, lon))
+}
+
+// weatherForecast returns the weather forecast for a location, or nil if no
+// weather service is configured or the forecast is unavailable.
+func (s *Service) weatherForecast(ctx context.Context, lat, lon float64) *weather.Forecast {
+	if s.weather == nil {
+		return nil
+	}
+	forecast, err := s.weather.GetForecast(ctx, lat, lon)
+	if err != nil {
+		telemetry.Logger(ctx, s.logger).Debug().Err(err).Msg("weather forecast unavailable, forecasting air quality without wind")
+		return nil
+	}
+	return forecast
+}
+
+// InterpolatePoints estimates current air quality at each point. Points that
+// cannot be estimated, such as those with no station in range, are nil in the
+// result.
+func (s *Service) InterpolatePoints(ctx context.Context, points []struct{ Lat, Lon float64 }) ([]*InterpolatedPoint, error) {
+	snapshot, err := s.GetSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.interpolator.InterpolateMultiple(ctx, points, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	s.applyTrends(ctx, snapshot, results)
+	return results, nil
+}
+
+// NearestStationResult is the monitoring station closest to a point.
+type NearestStationResult struct {
+	Station *Station
+
+	// Distance from the query point in meters.
+	Distance float64
+
+	// Measurements are the station's latest readings, one per pollutant.
+	Measurements []*Measurement
+
+	// Trends holds the change in each measurement over the last hour.
+	Trends map[Pollutant]StationTrend
+}
+
+// NearestStation returns the monitoring station closest to a location, so
+// users can see where their reading comes from. Returns ErrNoStationsInRange
+// if no station is within the interpolation MaxDistance.
+func (s *Service) NearestStation(ctx context.Context, lat, lon float64) (*NearestStationResult, error) {
+	snapshot, err := s.GetSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	station, distance := snapshot.NearestStation(lat, lon, s.interpolator.config.MaxDistance)
+	if station == nil {
+		return nil, ErrNoStationsInRange
+	}
+
+	measurements := snapshot.GetStationMeasurements(station.ID)
+	past := s.trendBaseline(ctx, snapshot)
+	trends := make(map[Pollutant]StationTrend, len(measurements))
+	for _, m := range measurements {
+		trends[m.Pollutant] = stationTrend(m, past)
+	}
+
+	return &NearestStationResult{
+		Station:      station,
+		Distance:     distance,
+		Measurements: measurements,
+		Trends:       trends,
+	}, nil
+}
+
+// WarmCache pre-fetches the national snapshot so the first requests after
+// startup are served from cache. The snapshot covers all stations, so no
+// points are needed.
+func (s *Service) WarmCache(ctx context.Context) error {
+	_, err := s.GetSnapshot(ctx)
+	return err
+}
+
+// CheckReady returns nil if the service holds a snapshot within the
+// stale-if-error window, fetching one if it does not. It is intended for
+// readiness probes, so traffic is not routed before air quality data is usable.
+func (s *Service) CheckReady(ctx context.Context) error {
+	if status := s.CacheStatus(); status.HasData && !status.IsStale {
+		return nil
+	}
+	_, err := s.GetSnapshot(ctx)
+	return err
+}
+
+// RestoreSnapshot loads the snapshot persisted by a previous process, so the
+// first requests after a restart need not wait for the provider. A snapshot
+// older than StaleIfErrorTTL is not restored and cache.ErrSnapshotStale
+// is returned; if nothing is stored, cache.ErrSnapshotNotFound is returned. A
+// snapshot already fetched by this process is never replaced.
+func (s *Service) RestoreSnapshot(ctx context.Context) error {
+	var snapshot AQSnapshot
+	if err := s.store.Load(ctx, snapshotStoreKey, &snapshot); err != nil {
+		return err
+	}
+	if !s.usable(&snapshot) {
+		return cache.ErrSnapshotStale
+	}
+
+	restored := &published{snapshot: &snapshot, expiresAt: snapshot.FetchedAt.Add(s.cacheTTL)}
+	if !s.current.CompareAndSwap(nil, restored) {
+		return nil
+	}
+	s.restored.Store(&snapshot)
+
+	telemetry.Logger(ctx, s.logger).Info().
+		Str("provider", snapshot.Provider).
+		Int("stations", snapshot.StationCount()).
+		Time("fetched_at", snapshot.FetchedAt).
+		Msg("air quality snapshot restored")
+
+	return nil
+}
+
+// usable reports whether snapshot is within the stale-if-error window.
+func (s *Service) usable(snapshot *AQSnapshot) bool {
+	return snapshot != nil && time.Now().Before(snapshot.FetchedAt.Add(s.staleIfErrorTTL))
+}
+
 // InvalidateCache clears the cached snapshot.
 func (s *Service) InvalidateCache() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.snapshot = nil
-	s.cacheExpiry = time.Time{}
+	s.current.Store(nil)
+	s.restored.Store(nil)
 }
 
 // CacheStatus returns information about the current cache state.
 func (s *Service) CacheStatus() CacheStatus {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.snapshot == nil {
+	current := s.current.Load()
+	if current == nil {
 		return CacheStatus{
 			HasData: false,
 		}
 	}
 
 	now := time.Now()
+	snapshot := current.snapshot
 	return CacheStatus{
 		HasData:      true,
-		FetchedAt:    s.snapshot.FetchedAt,
-		ExpiresAt:    s.cacheExpiry,
-		IsExpired:    now.After(s.cacheExpiry),
-		IsStale:      now.After(s.snapshot.FetchedAt.Add(s.staleIfErrorTTL)),
-		StationCount: len(s.snapshot.Stations),
-		Provider:     s.snapshot.Provider,
+		FetchedAt:    snapshot.FetchedAt,
+		ExpiresAt:    current.expiresAt,
+		IsExpired:    now.After(current.expiresAt),
+		IsStale:      now.After(snapshot.FetchedAt.Add(s.staleIfErrorTTL)),
+		StationCount: snapshot.StationCount(),
+		Provider:     snapshot.Provider,
+		Fallback:     snapshot.Fallback,
 	}
 }
 
@@ -165,44 +447,123 @@ type CacheStatus struct {
 	IsExpired    bool
 	IsStale      bool
 	StationCount int
-	Provider     string
+	Provider     string // Provider(s) that served the cached snapshot
+	Fallback     bool   // True when a secondary provider served some or all data
 }
 
-// refreshSnapshot fetches fresh data from the provider.
+// refreshSnapshot fetches fresh data from the provider. Concurrent refreshes
+// share a single provider call.
 func (s *Service) refreshSnapshot(ctx context.Context) (*AQSnapshot, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	snapshot, _, err := s.fetches.Do(ctx, snapshotStoreKey, s.fetchSnapshot)
+	return snapshot, err
+}
 
+// fetchSnapshot fetches a snapshot from the provider, unless a fetch that
+// just finished has cached one, and caches and persists it.
+func (s *Service) fetchSnapshot(ctx context.Context) (*AQSnapshot, error) {
 	// Double-check: another goroutine might have refreshed while we waited
-	if s.snapshot != nil && time.Now().Before(s.cacheExpiry) {
-		return s.snapshot, nil
+	previous := s.current.Load()
+	if previous.fresh() {
+		return previous.snapshot, nil
 	}
 
-	s.logger.Debug().Msg("refreshing air quality snapshot")
+	telemetry.Logger(ctx, s.logger).Debug().Msg("refreshing air quality snapshot")
 
-	snapshot, err := s.provider.FetchSnapshot(ctx)
+	s.refreshing.Store(true)
+	snapshot, incremental, err := s.fetchFromProvider(ctx, previous)
+	s.refreshing.Store(false)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to fetch air quality snapshot")
+		telemetry.Logger(ctx, s.logger).Error().Err(err).Msg("failed to fetch air quality snapshot")
 
 		// If we have stale data that's not too old, return it
-		if s.snapshot != nil && time.Now().Before(s.snapshot.FetchedAt.Add(s.staleIfErrorTTL)) {
-			s.logger.Warn().
-				Time("fetched_at", s.snapshot.FetchedAt).
+		if stale := s.currentSnapshot(); stale != nil && time.Now().Before(stale.FetchedAt.Add(s.staleIfErrorTTL)) {
+			telemetry.Logger(ctx, s.logger).Warn().
+				Time("fetched_at", stale.FetchedAt).
 				Msg("serving stale air quality data due to provider error")
-			return s.snapshot, nil
+			return stale, nil
 		}
 
-		return nil, ErrProviderUnavailable
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
 	}
 
-	s.snapshot = snapshot
-	s.cacheExpiry = time.Now().Add(s.cacheTTL)
+	next := &published{
+		snapshot:    snapshot,
+		expiresAt:   time.Now().Add(s.cacheTTL),
+		fullFetchAt: time.Now(),
+	}
+	if incremental {
+		next.fullFetchAt = previous.fullFetchAt
+	}
+	s.current.Store(next)
+	s.restored.Store(nil)
 
-	s.logger.Info().
-		Int("stations", len(snapshot.Stations)).
-		Int("measurements", len(snapshot.Measurements)).
-		Time("expires_at", s.cacheExpiry).
+	if err := s.store.Save(ctx, snapshotStoreKey, snapshot); err != nil {
+		telemetry.Logger(ctx, s.logger).Warn().Err(err).Msg("failed to persist air quality snapshot")
+	}
+	if s.history != nil {
+		if err := s.history.Record(ctx, snapshot); err != nil {
+			telemetry.Logger(ctx, s.logger).Warn().Err(err).Msg("failed to archive air quality snapshot")
+		}
+	}
+
+	telemetry.Logger(ctx, s.logger).Info().
+		Str("provider", snapshot.Provider).
+		Bool("fallback", snapshot.Fallback).
+		Bool("incremental", incremental).
+		Int("stations", snapshot.StationCount()).
+		Int("measurements", snapshot.MeasurementCount()).
+		Time("expires_at", next.expiresAt).
 		Msg("air quality snapshot refreshed")
 
 	return snapshot, nil
 }
+
+// fetchFromProvider fetches a snapshot with units normalized to µg/m³. If
+// the provider implements IncrementalProvider and the last full fetch is
+// within FullRefreshInterval, only the measurements updated since the
+// previous snapshot are fetched and applied to a copy of it; the full
+// snapshot is fetched otherwise, or if the incremental fetch fails. Reports
+// whether the snapshot was updated incrementally.
+func (s *Service) fetchFromProvider(ctx context.Context, previous *published) (*AQSnapshot, bool, error) {
+	incremental, ok := s.provider.(IncrementalProvider)
+	if ok && previous != nil && !previous.fullFetchAt.IsZero() && time.Since(previous.fullFetchAt) < s.fullRefresh {
+		current := previous.snapshot
+		if since := current.LatestMeasurementAt(); !since.IsZero() {
+			measurements, err := incremental.FetchMeasurementsSince(ctx, since)
+			if err == nil {
+				return s.applyUpdates(current, measurements), true, nil
+			}
+			telemetry.Logger(ctx, s.logger).Warn().Err(err).Msg("incremental air quality refresh failed, fetching full snapshot")
+		}
+	}
+
+	snapshot, err := s.provider.FetchSnapshot(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Convert provider units to µg/m³ before the snapshot is used for interpolation
+	for _, err := range snapshot.NormalizeUnits() {
+		telemetry.Logger(ctx, s.logger).Warn().Err(err).Msg("dropping air quality measurement with unsupported unit")
+	}
+	return snapshot, false, nil
+}
+
+// applyUpdates normalizes measurements and applies them to a copy of current.
+func (s *Service) applyUpdates(current *AQSnapshot, measurements []*Measurement) *AQSnapshot {
+	normalized := make([]*Measurement, 0, len(measurements))
+	for _, m := range measurements {
+		if err := NormalizeMeasurement(m); err != nil {
+			s.logger.Warn().Err(err).Msg("dropping air quality measurement with unsupported unit")
+			continue
+		}
+		normalized = append(normalized, m)
+	}
+
+	snapshot, applied := current.ApplyMeasurements(normalized)
+	s.logger.Debug().
+		Int("fetched", len(measurements)).
+		Int("applied", applied).
+		Msg("applied incremental air quality measurements")
+	return snapshot
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -13,6 +14,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
 )
 
 // mockProvider is a test provider that returns configurable data.
@@ -56,6 +59,22 @@ func (m *mockProvider) FetchLatestMeasurements(_ context.Context) ([]*airquality
 	return measurements, nil
 }
 
+// incrementalProvider is a mockProvider that also serves measurement updates.
+type incrementalProvider struct {
+	mockProvider
+	updates    []*airquality.Measurement
+	updateErr  error
+	sinceCount atomic.Int32
+}
+
+func (m *incrementalProvider) FetchMeasurementsSince(_ context.Context, _ time.Time) ([]*airquality.Measurement, error) {
+	m.sinceCount.Add(1)
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	return m.updates, nil
+}
+
 func testSnapshot() *airquality.AQSnapshot {
 	snapshot := airquality.NewAQSnapshot("test")
 	snapshot.Stations["NL10001"] = &airquality.Station{
@@ -121,6 +140,92 @@ func TestService_GetSnapshot(t *testing.T) {
 	assert.Equal(t, int32(1), provider.fetchCount.Load()) // Still 1
 }
 
+func TestService_GetSnapshot_ConcurrentRequests(t *testing.T) {
+	provider := &mockProvider{snapshot: testSnapshot(), fetchDelay: 50 * time.Millisecond}
+	svc := airquality.NewService(airquality.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.New(io.Discard),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.GetSnapshot(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Concurrent refreshes share a single provider call
+	assert.Equal(t, int32(1), provider.fetchCount.Load())
+}
+
+func TestService_GetSnapshot_StaleWhileRevalidate(t *testing.T) {
+	first := testSnapshot()
+	provider := &mockProvider{snapshot: first}
+	svc := airquality.NewService(airquality.ServiceConfig{
+		Provider:             provider,
+		Logger:               zerolog.New(io.Discard),
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Hour,
+		StaleWhileRevalidate: true,
+	})
+
+	ctx := context.Background()
+	_, err := svc.GetSnapshot(ctx)
+	require.NoError(t, err)
+
+	time.Sleep(70 * time.Millisecond)
+	provider.fetchDelay = 100 * time.Millisecond
+	provider.snapshot = testSnapshot()
+
+	// The expired snapshot is served at once while one refresh runs
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		snapshot, err := svc.GetSnapshot(ctx)
+		require.NoError(t, err)
+		assert.Same(t, first, snapshot)
+	}
+	assert.Less(t, time.Since(start), provider.fetchDelay)
+
+	assert.Eventually(t, func() bool {
+		snapshot, err := svc.GetSnapshot(ctx)
+		return err == nil && snapshot != first
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), provider.fetchCount.Load())
+}
+
+func TestService_GetSnapshot_FailedRevalidationKeepsStale(t *testing.T) {
+	first := testSnapshot()
+	provider := &mockProvider{snapshot: first}
+	svc := airquality.NewService(airquality.ServiceConfig{
+		Provider:             provider,
+		Logger:               zerolog.New(io.Discard),
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Hour,
+		StaleWhileRevalidate: true,
+	})
+
+	ctx := context.Background()
+	_, err := svc.GetSnapshot(ctx)
+	require.NoError(t, err)
+
+	time.Sleep(70 * time.Millisecond)
+	provider.err = errors.New("provider down")
+
+	snapshot, err := svc.GetSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Same(t, first, snapshot)
+	assert.Eventually(t, func() bool { return provider.fetchCount.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	// The failed refresh left the stale snapshot in place
+	snapshot, err = svc.GetSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Same(t, first, snapshot)
+}
+
 func TestService_GetSnapshot_CacheExpiry(t *testing.T) {
 	provider := &mockProvider{snapshot: testSnapshot()}
 	svc := airquality.NewService(airquality.ServiceConfig{
@@ -145,6 +250,125 @@ func TestService_GetSnapshot_CacheExpiry(t *testing.T) {
 	assert.Equal(t, int32(2), provider.fetchCount.Load())
 }
 
+func TestService_GetSnapshot_Incremental(t *testing.T) {
+	provider := &incrementalProvider{mockProvider: mockProvider{snapshot: testSnapshot()}}
+	svc := airquality.NewService(airquality.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.New(io.Discard),
+		CacheTTL: 20 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	first, err := svc.GetSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), provider.fetchCount.Load())
+	assert.Equal(t, int32(0), provider.sinceCount.Load())
+
+	provider.updates = []*airquality.Measurement{
+		{StationID: "NL10001", Pollutant: airquality.PollutantNO2, Value: 40.2, Unit: "µg/m³", MeasuredAt: time.Now()},
+		// Older than the cached reading, so ignored
+		{StationID: "NL10002", Pollutant: airquality.PollutantNO2, Value: 99, Unit: "µg/m³", MeasuredAt: time.Now().Add(-time.Hour)},
+		// Converted to µg/m³ like a full snapshot
+		{StationID: "NL10002", Pollutant: airquality.PollutantPM10, Value: 0.02, Unit: "mg/m³", MeasuredAt: time.Now()},
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	updated, err := svc.GetSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), provider.fetchCount.Load(), "no full fetch within the full refresh interval")
+	assert.Equal(t, int32(1), provider.sinceCount.Load())
+
+	assert.Len(t, updated.Stations, 2)
+	assert.Equal(t, 40.2, updated.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+	assert.Equal(t, 12.3, updated.GetMeasurement("NL10001", airquality.PollutantPM25).Value)
+	assert.Equal(t, 28.1, updated.GetMeasurement("NL10002", airquality.PollutantNO2).Value)
+	assert.InDelta(t, 20, updated.GetMeasurement("NL10002", airquality.PollutantPM10).Value, 1e-9)
+
+	// Readers of the previous snapshot are unaffected
+	assert.Equal(t, 32.5, first.GetMeasurement("NL10001", airquality.PollutantNO2).Value)
+	assert.Nil(t, first.GetMeasurement("NL10002", airquality.PollutantPM10))
+}
+
+// TestService_ConcurrentReadAndRefresh is meant to be run with -race.
+func TestService_ConcurrentReadAndRefresh(t *testing.T) {
+	provider := &incrementalProvider{
+		mockProvider: mockProvider{snapshot: testSnapshot()},
+		updates: []*airquality.Measurement{
+			{StationID: "NL10001", Pollutant: airquality.PollutantNO2, Value: 40, Unit: "µg/m³", MeasuredAt: time.Now()},
+		},
+	}
+	svc := airquality.NewService(airquality.ServiceConfig{
+		Provider:      provider,
+		Logger:        zerolog.New(io.Discard),
+		CacheTTL:      time.Millisecond,
+		Interpolation: airquality.InterpolationConfig{MinStations: 1},
+	})
+	ctx := context.Background()
+	points := []struct{ Lat, Lon float64 }{{52.37, 4.89}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, svc.RefreshSnapshot(ctx))
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, err := svc.InterpolatePoints(ctx, points)
+				assert.NoError(t, err)
+				_, err = svc.NearestStation(ctx, 52.37, 4.89)
+				assert.NoError(t, err)
+				svc.CacheStatus()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Positive(t, provider.sinceCount.Load())
+}
+
+func TestService_GetSnapshot_IncrementalFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		updateErr error
+		since     int32
+	}{
+		{name: "incremental fetch fails", interval: time.Hour, updateErr: errors.New("timeout"), since: 1},
+		{name: "full refresh due", interval: 10 * time.Millisecond, since: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &incrementalProvider{
+				mockProvider: mockProvider{snapshot: testSnapshot()},
+				updateErr:    tt.updateErr,
+			}
+			svc := airquality.NewService(airquality.ServiceConfig{
+				Provider:            provider,
+				Logger:              zerolog.New(io.Discard),
+				CacheTTL:            20 * time.Millisecond,
+				FullRefreshInterval: tt.interval,
+			})
+			ctx := context.Background()
+
+			_, err := svc.GetSnapshot(ctx)
+			require.NoError(t, err)
+			time.Sleep(30 * time.Millisecond)
+
+			_, err = svc.GetSnapshot(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int32(2), provider.fetchCount.Load())
+			assert.Equal(t, tt.since, provider.sinceCount.Load())
+		})
+	}
+}
+
 func TestService_GetSnapshot_ProviderError_StaleData(t *testing.T) {
 	snapshot := testSnapshot()
 	provider := &mockProvider{snapshot: snapshot}
@@ -287,3 +511,187 @@ func TestService_CacheStatus(t *testing.T) {
 	assert.Equal(t, "test", status.Provider)
 	assert.False(t, status.IsExpired)
 }
+
+func TestService_WarmCache(t *testing.T) {
+	provider := &mockProvider{snapshot: testSnapshot()}
+	svc := airquality.NewService(airquality.ServiceConfig{Provider: provider})
+
+	require.NoError(t, svc.WarmCache(context.Background()))
+	assert.Equal(t, int32(1), provider.fetchCount.Load())
+
+	// Subsequent requests are served from cache
+	_, err := svc.GetSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), provider.fetchCount.Load())
+}
+
+func TestService_NearestStation(t *testing.T) {
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: testSnapshot()},
+		Logger:   zerolog.New(io.Discard),
+	})
+
+	// Amsterdam Zuid is closest to the Amsterdam-Centrum station
+	result, err := service.NearestStation(context.Background(), 52.339, 4.873)
+	require.NoError(t, err)
+
+	assert.Equal(t, "NL10001", result.Station.ID)
+	assert.InDelta(t, 3800, result.Distance, 200)
+	require.Len(t, result.Measurements, 2)
+	assert.Equal(t, airquality.PollutantNO2, result.Measurements[0].Pollutant)
+	assert.Equal(t, airquality.PollutantPM25, result.Measurements[1].Pollutant)
+}
+
+func TestService_NearestStation_NoneInRange(t *testing.T) {
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: testSnapshot()},
+		Logger:   zerolog.New(io.Discard),
+	})
+
+	// Groningen is more than 50km from both stations
+	_, err := service.NearestStation(context.Background(), 53.2194, 6.5665)
+	assert.ErrorIs(t, err, airquality.ErrNoStationsInRange)
+}
+
+// persistedSnapshot saves a snapshot fetched at fetchedAt to a new file store
+// by refreshing a service, as the previous process would have.
+func persistedSnapshot(t *testing.T, fetchedAt time.Time) cache.SnapshotStore {
+	t.Helper()
+
+	store, err := cache.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	snapshot := testSnapshot()
+	snapshot.FetchedAt = fetchedAt
+	snapshot.Provider = "previous"
+	previous := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: snapshot},
+		Logger:   zerolog.Nop(),
+		Store:    store,
+	})
+	require.NoError(t, previous.RefreshSnapshot(context.Background()))
+
+	return store
+}
+
+func TestService_RestoreSnapshot(t *testing.T) {
+	store := persistedSnapshot(t, time.Now().Add(-time.Minute))
+	provider := &mockProvider{err: errors.New("provider down")}
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		Store:    store,
+	})
+
+	require.NoError(t, service.RestoreSnapshot(context.Background()))
+
+	// Still fresh, so served without a provider call
+	snapshot, err := service.GetSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "previous", snapshot.Provider)
+	assert.Len(t, snapshot.Stations, 2)
+	assert.NotNil(t, snapshot.GetMeasurement("NL10001", airquality.PollutantNO2))
+	assert.Equal(t, int32(0), provider.fetchCount.Load())
+
+	// Interpolation works on the restored snapshot
+	_, err = service.NearestStation(context.Background(), 52.37, 4.89)
+	assert.NoError(t, err)
+}
+
+func TestService_RestoreSnapshot_Unusable(t *testing.T) {
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: testSnapshot()},
+		Logger:   zerolog.Nop(),
+		Store:    persistedSnapshot(t, time.Now().Add(-time.Hour)),
+	})
+	assert.ErrorIs(t, service.RestoreSnapshot(context.Background()), cache.ErrSnapshotStale)
+	assert.False(t, service.CacheStatus().HasData)
+
+	// The default store holds nothing
+	service = airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: testSnapshot()},
+		Logger:   zerolog.Nop(),
+	})
+	assert.ErrorIs(t, service.RestoreSnapshot(context.Background()), cache.ErrSnapshotNotFound)
+}
+
+func TestService_RestoreSnapshot_ServedDuringFirstFetch(t *testing.T) {
+	// Expired but within the 30 minute stale-if-error window
+	store := persistedSnapshot(t, time.Now().Add(-10*time.Minute))
+	live := testSnapshot()
+	live.Provider = "live"
+	provider := &mockProvider{snapshot: live, fetchDelay: 200 * time.Millisecond}
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		Store:    store,
+	})
+	require.NoError(t, service.RestoreSnapshot(context.Background()))
+
+	fetched := make(chan *airquality.AQSnapshot, 1)
+	go func() {
+		snapshot, _ := service.GetSnapshot(context.Background())
+		fetched <- snapshot
+	}()
+	require.Eventually(t, func() bool { return provider.fetchCount.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	// A concurrent caller gets the restored snapshot instead of waiting
+	start := time.Now()
+	snapshot, err := service.GetSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "previous", snapshot.Provider)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	assert.Equal(t, "live", (<-fetched).Provider)
+
+	// Once the live fetch lands, the restored snapshot is no longer served
+	snapshot, err = service.GetSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "live", snapshot.Provider)
+	assert.Equal(t, int32(1), provider.fetchCount.Load())
+}
+
+// lockedSnapshot serves a snapshot behind a read-write mutex, as the service
+// did before snapshots were published by atomic swap.
+type lockedSnapshot struct {
+	mu          sync.RWMutex
+	snapshot    *airquality.AQSnapshot
+	cacheExpiry time.Time
+}
+
+func (l *lockedSnapshot) get(ctx context.Context) *airquality.AQSnapshot {
+	l.mu.RLock()
+	snapshot, fresh := l.snapshot, time.Now().Before(l.cacheExpiry)
+	l.mu.RUnlock()
+	resilience.RecordCacheResult(ctx, "airquality", fresh)
+	return snapshot
+}
+
+func BenchmarkService_GetSnapshot(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("Mutex", func(b *testing.B) {
+		locked := &lockedSnapshot{snapshot: testSnapshot(), cacheExpiry: time.Now().Add(time.Hour)}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				locked.get(ctx)
+			}
+		})
+	})
+
+	b.Run("AtomicSwap", func(b *testing.B) {
+		svc := airquality.NewService(airquality.ServiceConfig{
+			Provider: &mockProvider{snapshot: testSnapshot()},
+			Logger:   zerolog.New(io.Discard),
+			CacheTTL: time.Hour,
+		})
+		if _, err := svc.GetSnapshot(ctx); err != nil {
+			b.Fatal(err)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = svc.GetSnapshot(ctx)
+			}
+		})
+	})
+}
@@ -0,0 +1,131 @@
+package airquality
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// Trend is the short-term direction of a pollutant concentration.
+type Trend string
+
+const (
+	TrendRising  Trend = "RISING"
+	TrendSteady  Trend = "STEADY"
+	TrendFalling Trend = "FALLING"
+
+	// TrendUnknown is reported when no measurements from an hour earlier
+	// are available to compare against.
+	TrendUnknown Trend = "UNKNOWN"
+)
+
+const (
+	// trendWindow is how far back the comparison snapshot is taken.
+	trendWindow = time.Hour
+
+	// trendSteadyDelta is the change in µg/m³ below which a concentration
+	// is steady, however low it is.
+	trendSteadyDelta = 1.0
+
+	// trendSteadyRatio is the change, relative to the current value, below
+	// which a concentration is steady.
+	trendSteadyRatio = 0.05
+
+	// trendMinCoverage is the share of interpolation weight that must come
+	// from stations with an earlier measurement before a trend is reported.
+	trendMinCoverage = 0.5
+)
+
+// classifyTrend maps a change over trendWindow to a Trend.
+func classifyTrend(current, delta float64) Trend {
+	if math.Abs(delta) < math.Max(trendSteadyDelta, trendSteadyRatio*math.Abs(current)) {
+		return TrendSteady
+	}
+	if delta > 0 {
+		return TrendRising
+	}
+	return TrendFalling
+}
+
+// applyTrend sets the value's Trend and Delta from the contributing
+// stations' measurements in past, weighting each station's change like its
+// contribution to the value. Stations missing from past are left out; if
+// they carry too much of the weight, the trend stays unknown.
+func (v *InterpolatedValue) applyTrend(past *AQSnapshot) {
+	v.Trend = TrendUnknown
+	v.Delta = nil
+	if past == nil {
+		return
+	}
+
+	var delta, coverage float64
+	for _, c := range v.ContributingStations {
+		previous := past.GetMeasurement(c.StationID, v.Pollutant)
+		if previous == nil {
+			continue
+		}
+		delta += c.Weight * (c.Value - previous.Value)
+		coverage += c.Weight
+	}
+	if coverage < trendMinCoverage {
+		return
+	}
+
+	delta /= coverage
+	v.Delta = &delta
+	v.Trend = classifyTrend(v.Value, delta)
+}
+
+// StationTrend is the change in one station's reading over the last hour.
+type StationTrend struct {
+	Trend Trend
+
+	// Delta is the change in µg/m³, or nil if the trend is unknown.
+	Delta *float64
+}
+
+// stationTrend compares a station's current measurement with past.
+func stationTrend(m *Measurement, past *AQSnapshot) StationTrend {
+	if past == nil {
+		return StationTrend{Trend: TrendUnknown}
+	}
+	previous := past.GetMeasurement(m.StationID, m.Pollutant)
+	if previous == nil {
+		return StationTrend{Trend: TrendUnknown}
+	}
+
+	delta := m.Value - previous.Value
+	return StationTrend{Trend: classifyTrend(m.Value, delta), Delta: &delta}
+}
+
+// trendBaseline returns the archived snapshot an hour before snapshot, or
+// nil if the service keeps no history or that hour was not recorded.
+func (s *Service) trendBaseline(ctx context.Context, snapshot *AQSnapshot) *AQSnapshot {
+	if s.history == nil {
+		return nil
+	}
+
+	past, err := s.history.At(ctx, snapshotHour(snapshot).Add(-trendWindow))
+	if err != nil {
+		if !errors.Is(err, ErrNoHistory) {
+			s.logger.Warn().Err(err).Msg("failed to load air quality history for trend")
+		}
+		return nil
+	}
+	return past
+}
+
+// applyTrends sets the trend of every value in points against the hour
+// before snapshot.
+func (s *Service) applyTrends(ctx context.Context, snapshot *AQSnapshot, points []*InterpolatedPoint) {
+	past := s.trendBaseline(ctx, snapshot)
+	for _, point := range points {
+		if point == nil {
+			continue
+		}
+		for _, value := range point.Values {
+			value.applyTrend(past)
+		}
+	}
+}
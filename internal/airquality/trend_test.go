@@ -0,0 +1,114 @@
+package airquality_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+// singleStation interpolates from the one station in snapshotMeasuredAt.
+var singleStation = airquality.InterpolationConfig{MinStations: 1}
+
+// currentSnapshot returns a snapshot with a single NO2 reading at t that can
+// be interpolated.
+func currentSnapshot(t time.Time, no2 float64) *airquality.AQSnapshot {
+	snapshot := snapshotMeasuredAt(t, no2)
+	snapshot.Stations["NL10001"].Pollutants = []airquality.Pollutant{airquality.PollutantNO2}
+	return snapshot
+}
+
+// trendService returns a service serving current, with history holding the
+// given hourly NO2 readings for the hours before it, oldest first.
+func trendService(t *testing.T, current *airquality.AQSnapshot, earlier ...float64) *airquality.Service {
+	t.Helper()
+	ctx := context.Background()
+
+	history := newTestHistory(t)
+	hour := current.LatestMeasurementAt().Truncate(time.Hour)
+	for i, no2 := range earlier {
+		at := hour.Add(-time.Duration(len(earlier)-i) * time.Hour)
+		require.NoError(t, history.Record(ctx, snapshotMeasuredAt(at, no2)))
+	}
+
+	return airquality.NewService(airquality.ServiceConfig{
+		Provider:      &mockProvider{snapshot: current},
+		Logger:        zerolog.New(io.Discard),
+		Interpolation: singleStation,
+		History:       history,
+	})
+}
+
+func TestService_InterpolatePoints_Trend(t *testing.T) {
+	now := time.Date(2026, 10, 12, 8, 20, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		earlier []float64
+		current float64
+		trend   airquality.Trend
+		delta   float64
+	}{
+		{name: "rising", earlier: []float64{18, 22, 26}, current: 34, trend: airquality.TrendRising, delta: 8},
+		{name: "falling", earlier: []float64{40, 34}, current: 28, trend: airquality.TrendFalling, delta: -6},
+		{name: "steady", earlier: []float64{30}, current: 30.5, trend: airquality.TrendSteady, delta: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := trendService(t, currentSnapshot(now, tt.current), tt.earlier...)
+
+			points, err := service.InterpolatePoints(context.Background(), []struct{ Lat, Lon float64 }{{52.36, 4.88}})
+			require.NoError(t, err)
+			require.NotNil(t, points[0])
+
+			value := points[0].Values[airquality.PollutantNO2]
+			require.NotNil(t, value)
+			assert.Equal(t, tt.trend, value.Trend)
+			require.NotNil(t, value.Delta)
+			assert.InDelta(t, tt.delta, *value.Delta, 1e-9)
+		})
+	}
+}
+
+func TestService_InterpolatePoints_TrendUnknown(t *testing.T) {
+	now := time.Date(2026, 10, 12, 8, 20, 0, 0, time.UTC)
+	points := []struct{ Lat, Lon float64 }{{52.36, 4.88}}
+
+	// No history configured
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider:      &mockProvider{snapshot: currentSnapshot(now, 30)},
+		Logger:        zerolog.New(io.Discard),
+		Interpolation: singleStation,
+	})
+	results, err := service.InterpolatePoints(context.Background(), points)
+	require.NoError(t, err)
+	value := results[0].Values[airquality.PollutantNO2]
+	assert.Equal(t, airquality.TrendUnknown, value.Trend)
+	assert.Nil(t, value.Delta)
+
+	// History without the previous hour
+	service = trendService(t, currentSnapshot(now, 30))
+	results, err = service.InterpolatePoints(context.Background(), points)
+	require.NoError(t, err)
+	assert.Equal(t, airquality.TrendUnknown, results[0].Values[airquality.PollutantNO2].Trend)
+}
+
+func TestService_NearestStation_Trend(t *testing.T) {
+	now := time.Date(2026, 10, 12, 8, 20, 0, 0, time.UTC)
+	service := trendService(t, currentSnapshot(now, 34), 22, 26)
+
+	result, err := service.NearestStation(context.Background(), 52.36, 4.88)
+	require.NoError(t, err)
+
+	trend := result.Trends[airquality.PollutantNO2]
+	assert.Equal(t, airquality.TrendRising, trend.Trend)
+	require.NotNil(t, trend.Delta)
+	assert.InDelta(t, 8, *trend.Delta, 1e-9)
+}
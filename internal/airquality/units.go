@@ -0,0 +1,108 @@
+package airquality
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Measurement units. Interpolation, forecasting and AQI banding all work in
+// UnitMicrogramsPerCubicMeter; other units are converted on ingestion.
+const (
+	UnitMicrogramsPerCubicMeter = "µg/m³"
+	UnitMilligramsPerCubicMeter = "mg/m³"
+	UnitPartsPerBillion         = "ppb"
+	UnitPartsPerMillion         = "ppm"
+)
+
+// ErrUnknownUnit is returned when a measurement's unit cannot be converted
+// to µg/m³.
+var ErrUnknownUnit = errors.New("unknown measurement unit")
+
+// molarVolume is the volume of one mole of ideal gas in liters at the EU
+// air quality reference conditions (293.15 K, 101.325 kPa).
+const molarVolume = 24.055
+
+// molecularWeights are in g/mol. Particulate matter has no molecular weight,
+// so PM readings can only be converted between mass concentrations.
+var molecularWeights = map[Pollutant]float64{
+	PollutantNO2: 46.0055,
+	PollutantO3:  47.9982,
+}
+
+// unitAliases maps the spellings providers use to the canonical unit names.
+var unitAliases = map[string]string{
+	"µg/m³": UnitMicrogramsPerCubicMeter,
+	"µg/m3": UnitMicrogramsPerCubicMeter,
+	"ug/m³": UnitMicrogramsPerCubicMeter,
+	"ug/m3": UnitMicrogramsPerCubicMeter,
+	"mg/m³": UnitMilligramsPerCubicMeter,
+	"mg/m3": UnitMilligramsPerCubicMeter,
+	"ppb":   UnitPartsPerBillion,
+	"ppm":   UnitPartsPerMillion,
+}
+
+// canonicalUnit returns the canonical name for a unit, accepting case,
+// whitespace and micro sign variants.
+func canonicalUnit(unit string) (string, bool) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	// Greek small mu (U+03BC) is often used in place of the micro sign (U+00B5)
+	unit = strings.ReplaceAll(unit, "μ", "µ")
+	canonical, ok := unitAliases[unit]
+	return canonical, ok
+}
+
+// NormalizeMeasurement converts m to µg/m³ in place. A measurement without a
+// unit is assumed to already be in µg/m³. Returns ErrUnknownUnit if the unit
+// is not recognized or cannot be converted for the pollutant.
+func NormalizeMeasurement(m *Measurement) error {
+	switch m.Unit {
+	case UnitMicrogramsPerCubicMeter:
+		return nil
+	case "":
+		m.Unit = UnitMicrogramsPerCubicMeter
+		return nil
+	}
+
+	unit, ok := canonicalUnit(m.Unit)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownUnit, m.Unit)
+	}
+
+	switch unit {
+	case UnitMicrogramsPerCubicMeter:
+		// Alternate spelling; only the unit name changes
+	case UnitMilligramsPerCubicMeter:
+		m.Value *= 1000
+	case UnitPartsPerBillion, UnitPartsPerMillion:
+		weight, ok := molecularWeights[m.Pollutant]
+		if !ok {
+			return fmt.Errorf("%w: %q for %s", ErrUnknownUnit, m.Unit, m.Pollutant)
+		}
+		ppb := m.Value
+		if unit == UnitPartsPerMillion {
+			ppb *= 1000
+		}
+		m.Value = ppb * weight / molarVolume
+	}
+
+	m.Unit = UnitMicrogramsPerCubicMeter
+	return nil
+}
+
+// NormalizeUnits converts every measurement in the snapshot to µg/m³ and
+// removes those that cannot be converted. The removed measurements' errors
+// are returned so callers can log them.
+func (s *AQSnapshot) NormalizeUnits() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for key, m := range s.Measurements {
+		if err := NormalizeMeasurement(m); err != nil {
+			delete(s.Measurements, key)
+			errs = append(errs, fmt.Errorf("station %s: %w", m.StationID, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,88 @@
+package airquality_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+)
+
+func TestNormalizeMeasurement(t *testing.T) {
+	tests := []struct {
+		name      string
+		pollutant airquality.Pollutant
+		value     float64
+		unit      string
+		expected  float64
+	}{
+		{"canonical", airquality.PollutantPM25, 12.5, "µg/m³", 12.5},
+		{"missing unit", airquality.PollutantPM10, 20, "", 20},
+		{"ascii spelling", airquality.PollutantPM10, 20, "ug/m3", 20},
+		{"greek mu", airquality.PollutantNO2, 30, "μg/m³", 30},
+		{"milligrams", airquality.PollutantPM25, 0.015, "mg/m3", 15},
+		{"NO2 ppb", airquality.PollutantNO2, 10, "ppb", 19.125},
+		{"O3 ppb", airquality.PollutantO3, 40, "PPB", 79.814},
+		{"O3 ppm", airquality.PollutantO3, 0.04, "ppm", 79.814},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &airquality.Measurement{Pollutant: tt.pollutant, Value: tt.value, Unit: tt.unit}
+			require.NoError(t, airquality.NormalizeMeasurement(m))
+			assert.InDelta(t, tt.expected, m.Value, 0.001)
+			assert.Equal(t, airquality.UnitMicrogramsPerCubicMeter, m.Unit)
+		})
+	}
+}
+
+func TestNormalizeMeasurement_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		pollutant airquality.Pollutant
+		unit      string
+	}{
+		{"unknown unit", airquality.PollutantNO2, "furlongs"},
+		{"ppb for particulates", airquality.PollutantPM25, "ppb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &airquality.Measurement{Pollutant: tt.pollutant, Value: 10, Unit: tt.unit}
+			err := airquality.NormalizeMeasurement(m)
+			assert.ErrorIs(t, err, airquality.ErrUnknownUnit)
+			assert.InDelta(t, 10, m.Value, 0.0001, "rejected measurement should be left unchanged")
+		})
+	}
+}
+
+func TestService_GetSnapshot_NormalizesUnits(t *testing.T) {
+	snapshot := airquality.NewAQSnapshot("mixed")
+	snapshot.Stations["EEA1"] = &airquality.Station{ID: "EEA1", Lat: 52.37, Lon: 4.89}
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID: "EEA1", Pollutant: airquality.PollutantNO2, Value: 10, Unit: "ppb", MeasuredAt: time.Now(),
+	})
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID: "EEA1", Pollutant: airquality.PollutantPM10, Value: 3, Unit: "grains", MeasuredAt: time.Now(),
+	})
+
+	service := airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockProvider{snapshot: snapshot},
+		Logger:   zerolog.New(io.Discard),
+	})
+
+	result, err := service.GetSnapshot(context.Background())
+	require.NoError(t, err)
+
+	no2 := result.GetMeasurement("EEA1", airquality.PollutantNO2)
+	require.NotNil(t, no2)
+	assert.InDelta(t, 19.125, no2.Value, 0.001)
+	assert.Equal(t, airquality.UnitMicrogramsPerCubicMeter, no2.Unit)
+
+	assert.Nil(t, result.GetMeasurement("EEA1", airquality.PollutantPM10), "unknown unit should be dropped")
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+)
+
+// AirQualityHandler handles air quality endpoints.
+type AirQualityHandler struct {
+	aqService *airquality.Service
+}
+
+// NewAirQualityHandler creates a new AirQualityHandler.
+// aqService may be nil when no air quality provider is configured.
+func NewAirQualityHandler(aqService *airquality.Service) *AirQualityHandler {
+	return &AirQualityHandler{aqService: aqService}
+}
+
+// GetGrid handles POST /v1/air-quality/grid - interpolated values over a
+// bounding box for heatmap overlays.
+func (h *AirQualityHandler) GetGrid(w http.ResponseWriter, r *http.Request) {
+	var input models.AirQualityGridRequest
+	if err := middleware.DecodeJSON(r, &input); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+
+	if h.aqService == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeAirQualityUnavailable, "air quality data is not available")
+		return
+	}
+
+	grid, err := h.aqService.InterpolateGrid(r.Context(), airquality.GridRequest{
+		MinLat:     input.BBox.MinLat,
+		MinLon:     input.BBox.MinLon,
+		MaxLat:     input.BBox.MaxLat,
+		MaxLon:     input.BBox.MaxLon,
+		Resolution: input.ResolutionDeg,
+	})
+	switch {
+	case errors.Is(err, airquality.ErrInvalidGrid):
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{
+			{Field: "bbox", Message: "must have min below max and lie within valid coordinates"},
+			{Field: "resolutionDeg", Message: "must be positive"},
+		})
+		return
+	case errors.Is(err, airquality.ErrGridTooLarge):
+		response.BadRequest(w, r, models.ErrorCodeGridTooLarge, "grid too large", []models.FieldError{
+			{Field: "resolutionDeg", Message: fmt.Sprintf("too fine for this box; at most %d cells are allowed", h.aqService.MaxGridCells())},
+		})
+		return
+	case r.Context().Err() != nil:
+		// The client went away or the request timed out; stop without a body
+		return
+	case err != nil:
+		response.ServiceUnavailable(w, r, models.ErrorCodeAirQualityUnavailable, "air quality data is temporarily unavailable")
+		return
+	}
+
+	cells := make([]*models.AirQualityGridCell, len(grid.Cells))
+	for i, point := range grid.Cells {
+		if point != nil {
+			cells[i] = toGridCell(point)
+		}
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, models.AirQualityGridResponse{
+		SnapshotAt:    models.Timestamp(grid.SnapshotAt),
+		BBox:          input.BBox,
+		ResolutionDeg: grid.Resolution,
+		Rows:          grid.Rows,
+		Cols:          grid.Cols,
+		Cells:         cells,
+	})
+}
+
+// GetNearestStation handles GET /v1/air-quality/nearest - the monitoring
+// station closest to a point, with its latest measurements.
+func (h *AirQualityHandler) GetNearestStation(w http.ResponseWriter, r *http.Request) {
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+
+	var fieldErrors []models.FieldError
+	if latErr != nil || lat < -90 || lat > 90 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "lat", Message: "must be a number between -90 and 90"})
+	}
+	if lonErr != nil || lon < -180 || lon > 180 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "lon", Message: "must be a number between -180 and 180"})
+	}
+	if len(fieldErrors) > 0 {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", fieldErrors)
+		return
+	}
+
+	if h.aqService == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeAirQualityUnavailable, "air quality data is not available")
+		return
+	}
+
+	result, err := h.aqService.NearestStation(r.Context(), lat, lon)
+	switch {
+	case errors.Is(err, airquality.ErrNoStationsInRange):
+		response.NotFound(w, r, models.ErrorCodeNoStationInRange, "no monitoring station within range of this location")
+		return
+	case err != nil:
+		response.ServiceUnavailable(w, r, models.ErrorCodeAirQualityUnavailable, "air quality data is temporarily unavailable")
+		return
+	}
+
+	pollutants := make([]models.Pollutant, 0, len(result.Station.Pollutants))
+	for _, p := range result.Station.Pollutants {
+		pollutants = append(pollutants, models.Pollutant(p))
+	}
+
+	measurements := make([]models.StationMeasurement, 0, len(result.Measurements))
+	for _, m := range result.Measurements {
+		trend, ok := result.Trends[m.Pollutant]
+		if !ok {
+			trend.Trend = airquality.TrendUnknown
+		}
+		measurements = append(measurements, models.StationMeasurement{
+			Pollutant:     models.Pollutant(m.Pollutant),
+			Value:         m.Value,
+			Unit:          m.Unit,
+			MeasuredAt:    models.Timestamp(m.MeasuredAt),
+			Trend:         models.Trend(trend.Trend),
+			DeltaLastHour: trend.Delta,
+		})
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, models.NearestStationResponse{
+		Station: models.Station{
+			StationID:  result.Station.ID,
+			Name:       result.Station.Name,
+			Point:      models.Point{Lat: result.Station.Lat, Lon: result.Station.Lon},
+			Pollutants: pollutants,
+			UpdatedAt:  models.Timestamp(result.Station.UpdatedAt),
+		},
+		DistanceMeters: result.Distance,
+		Measurements:   measurements,
+	})
+}
+
+// toGridCell converts an interpolated point to its API representation, with
+// pollutant values in a stable order.
+func toGridCell(point *airquality.InterpolatedPoint) *models.AirQualityGridCell {
+	values := make([]models.AirQualityPollutantValue, 0, len(point.Values))
+	for pollutant, value := range point.Values {
+		values = append(values, models.AirQualityPollutantValue{
+			Pollutant:     models.Pollutant(pollutant),
+			Value:         value.Value,
+			Unit:          airquality.UnitMicrogramsPerCubicMeter,
+			Confidence:    models.Confidence(value.Confidence),
+			Trend:         models.Trend(value.Trend),
+			DeltaLastHour: value.Delta,
+		})
+	}
+	sort.Slice(values, func(a, b int) bool {
+		return values[a].Pollutant < values[b].Pollutant
+	})
+
+	return &models.AirQualityGridCell{
+		Point:       models.Point{Lat: point.Lat, Lon: point.Lon},
+		AQICategory: string(point.AQICategory),
+		Confidence:  models.Confidence(point.Confidence()),
+		Values:      values,
+	}
+}
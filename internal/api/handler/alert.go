@@ -25,7 +25,7 @@ func NewAlertHandler() *AlertHandler {
 func (h *AlertHandler) PreviewDepartureWindows(w http.ResponseWriter, r *http.Request) {
 	var input models.AlertPreviewRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
@@ -92,7 +92,7 @@ func (h *AlertHandler) ListAlertSubscriptions(w http.ResponseWriter, _ *http.Req
 func (h *AlertHandler) CreateAlertSubscription(w http.ResponseWriter, r *http.Request) {
 	var input models.AlertSubscriptionCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
@@ -132,7 +132,7 @@ func (h *AlertHandler) CreateAlertSubscription(w http.ResponseWriter, r *http.Re
 func (h *AlertHandler) GetAlertSubscription(w http.ResponseWriter, r *http.Request) {
 	subscriptionID := chi.URLParam(r, "subscriptionId")
 	if subscriptionID == "" {
-		response.BadRequest(w, r, "subscriptionId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "subscriptionId is required", nil)
 		return
 	}
 
@@ -161,13 +161,13 @@ func (h *AlertHandler) GetAlertSubscription(w http.ResponseWriter, r *http.Reque
 func (h *AlertHandler) UpdateAlertSubscription(w http.ResponseWriter, r *http.Request) {
 	subscriptionID := chi.URLParam(r, "subscriptionId")
 	if subscriptionID == "" {
-		response.BadRequest(w, r, "subscriptionId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "subscriptionId is required", nil)
 		return
 	}
 
 	var input models.AlertSubscriptionUpdateRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
@@ -204,7 +204,7 @@ func (h *AlertHandler) UpdateAlertSubscription(w http.ResponseWriter, r *http.Re
 func (h *AlertHandler) DeleteAlertSubscription(w http.ResponseWriter, r *http.Request) {
 	subscriptionID := chi.URLParam(r, "subscriptionId")
 	if subscriptionID == "" {
-		response.BadRequest(w, r, "subscriptionId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "subscriptionId is required", nil)
 		return
 	}
 
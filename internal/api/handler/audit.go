@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/audit"
+)
+
+// AuditHandler handles the admin audit history endpoint.
+type AuditHandler struct {
+	service *audit.Service
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(service *audit.Service) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListUserAuditEntries handles GET /v1/admin/users/{userId}/audit - a user's
+// most recent changes, newest first. The optional limit query parameter
+// defaults to audit.DefaultListLimit.
+func (h *AuditHandler) ListUserAuditEntries(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "audit log is not available")
+		return
+	}
+
+	userID := chi.URLParam(r, "userId")
+	if userID == "" {
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "userId is required", nil)
+		return
+	}
+
+	limit := audit.DefaultListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > audit.MaxListLimit {
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{{
+				Field:   "limit",
+				Message: fmt.Sprintf("must be an integer between 1 and %d", audit.MaxListLimit),
+				Code:    models.FieldCodeOutOfRange,
+			}})
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := h.service.List(r.Context(), userID, limit)
+	if err != nil {
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to list audit entries")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, entries)
+}
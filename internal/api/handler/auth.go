@@ -26,7 +26,7 @@ func NewAuthHandler(authService *auth.Service) *AuthHandler {
 func (h *AuthHandler) SignInWithApple(w http.ResponseWriter, r *http.Request) {
 	var req auth.SIWATokenRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
@@ -40,7 +40,7 @@ func (h *AuthHandler) SignInWithApple(w http.ResponseWriter, r *http.Request) {
 				Code:    e.Code,
 			}
 		}
-		response.BadRequest(w, r, "validation error", fieldErrors)
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation error", fieldErrors)
 		return
 	}
 
@@ -52,21 +52,21 @@ func (h *AuthHandler) SignInWithApple(w http.ResponseWriter, r *http.Request) {
 			errors.Is(err, auth.ErrInvalidIssuer) ||
 			errors.Is(err, auth.ErrInvalidAudience) ||
 			errors.Is(err, auth.ErrNonceMismatch) {
-			response.Unauthorized(w, r, "invalid Apple identity token")
+			response.Unauthorized(w, r, models.ErrorCodeInvalidAppleToken, "invalid Apple identity token")
 			return
 		}
 		if errors.Is(err, auth.ErrTokenExpired) {
-			response.Unauthorized(w, r, "Apple identity token has expired")
+			response.Unauthorized(w, r, models.ErrorCodeAppleTokenExpired, "Apple identity token has expired")
 			return
 		}
 		if errors.Is(err, auth.ErrKeyNotFound) ||
 			errors.Is(err, auth.ErrFetchingAppleKeys) {
-			response.ServiceUnavailable(w, r, "unable to verify Apple token at this time")
+			response.ServiceUnavailable(w, r, models.ErrorCodeAppleVerificationUnavailable, "unable to verify Apple token at this time")
 			return
 		}
 
 		// Generic error
-		response.InternalError(w, r, "authentication failed")
+		response.InternalError(w, r, models.ErrorCodeInternal, "authentication failed")
 		return
 	}
 
@@ -78,7 +78,7 @@ func (h *AuthHandler) SignInWithApple(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req auth.RefreshTokenRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
@@ -92,7 +92,7 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 				Code:    e.Code,
 			}
 		}
-		response.BadRequest(w, r, "validation error", fieldErrors)
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation error", fieldErrors)
 		return
 	}
 
@@ -100,19 +100,23 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	tokenResp, err := h.authService.RefreshAccessToken(r.Context(), req.RefreshToken)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidRefreshToken) {
-			response.Unauthorized(w, r, "invalid refresh token")
+			response.Unauthorized(w, r, models.ErrorCodeInvalidRefreshToken, "invalid refresh token")
 			return
 		}
 		if errors.Is(err, auth.ErrRefreshTokenExpired) {
-			response.Unauthorized(w, r, "refresh token has expired")
+			response.Unauthorized(w, r, models.ErrorCodeRefreshTokenExpired, "refresh token has expired")
+			return
+		}
+		if errors.Is(err, auth.ErrRefreshTokenReused) {
+			response.Unauthorized(w, r, models.ErrorCodeRefreshTokenReused, "refresh token has already been used; sign in again")
 			return
 		}
 		if errors.Is(err, auth.ErrUserNotFound) {
-			response.Unauthorized(w, r, "user not found")
+			response.Unauthorized(w, r, models.ErrorCodeUserNotFound, "user not found")
 			return
 		}
 
-		response.InternalError(w, r, "token refresh failed")
+		response.InternalError(w, r, models.ErrorCodeInternal, "token refresh failed")
 		return
 	}
 
@@ -126,19 +130,19 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
 	if req.RefreshToken == "" {
-		response.BadRequest(w, r, "refreshToken is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "refreshToken is required", nil)
 		return
 	}
 
 	// Revoke the refresh token
 	if err := h.authService.RevokeRefreshToken(r.Context(), req.RefreshToken); err != nil {
 		// Log error but don't expose details
-		response.InternalError(w, r, "logout failed")
+		response.InternalError(w, r, models.ErrorCodeInternal, "logout failed")
 		return
 	}
 
@@ -151,19 +155,53 @@ func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID := GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	// Revoke all refresh tokens for the user
 	if err := h.authService.RevokeAllTokens(r.Context(), userID); err != nil {
-		response.InternalError(w, r, "logout failed")
+		response.InternalError(w, r, models.ErrorCodeInternal, "logout failed")
 		return
 	}
 
 	response.NoContent(w)
 }
 
+// ListIdentities handles GET /v1/me/identities - list sign-in identities linked to the user.
+func (h *AuthHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	identities, err := h.authService.ListIdentities(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to list identities")
+		return
+	}
+
+	items := make([]models.Identity, 0, len(identities))
+	for _, identity := range identities {
+		item := models.Identity{
+			ID:             identity.ID,
+			Provider:       identity.Provider,
+			EmailVerified:  identity.EmailVerified,
+			IsPrivateEmail: identity.IsPrivateEmail,
+			LinkedAt:       models.Timestamp(identity.CreatedAt),
+			LastUsedAt:     models.Timestamp(identity.LastUsedAt),
+		}
+		if identity.Email != "" {
+			email := identity.Email
+			item.Email = &email
+		}
+		items = append(items, item)
+	}
+
+	response.JSON(w, http.StatusOK, models.IdentityList{Items: items})
+}
+
 // DevLogin handles POST /v1/auth/dev - development-only authentication.
 // This endpoint is only available when AUTH_DEV_MODE=true.
 // It creates a test user and returns valid tokens for local testing.
@@ -176,7 +214,7 @@ func (h *AuthHandler) DevLogin(w http.ResponseWriter, r *http.Request) {
 
 	tokenResp, err := h.authService.DevAuthenticate(r.Context(), &req)
 	if err != nil {
-		response.InternalError(w, r, "dev authentication failed")
+		response.InternalError(w, r, models.ErrorCodeInternal, "dev authentication failed")
 		return
 	}
 
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+)
+
+// ManagedCache is a service cache that operators can inspect and clear.
+type ManagedCache struct {
+	// Stats reports the cache's current contents and hit counts.
+	Stats func() models.ServiceCache
+
+	// Invalidate clears the cache.
+	Invalidate func()
+}
+
+// CacheHandler handles the operator cache endpoints.
+type CacheHandler struct {
+	caches map[string]ManagedCache
+}
+
+// NewCacheHandler creates a new CacheHandler.
+func NewCacheHandler() *CacheHandler {
+	return &CacheHandler{caches: make(map[string]ManagedCache)}
+}
+
+// WithCache adds a named service cache.
+func (h *CacheHandler) WithCache(name string, cache ManagedCache) *CacheHandler {
+	h.caches[name] = cache
+	return h
+}
+
+// GetCaches handles GET /v1/ops/cache - report every service cache.
+func (h *CacheHandler) GetCaches(w http.ResponseWriter, r *http.Request) {
+	names := h.names()
+	report := models.CacheReport{
+		Time:   models.Timestamp(time.Now()),
+		Caches: make([]models.ServiceCache, 0, len(names)),
+	}
+	for _, name := range names {
+		stats := h.caches[name].Stats()
+		stats.Name = name
+		if total := stats.Hits + stats.Misses; total > 0 {
+			stats.HitRatio = float64(stats.Hits) / float64(total)
+		}
+		report.Caches = append(report.Caches, stats)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, report)
+}
+
+// InvalidateCaches handles POST /v1/ops/cache:invalidate - clear the named
+// service caches, or all of them if the body is empty or names none.
+func (h *CacheHandler) InvalidateCaches(w http.ResponseWriter, r *http.Request) {
+	var input models.CacheInvalidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+
+	names := input.Services
+	if len(names) == 0 {
+		names = h.names()
+	}
+	for _, name := range names {
+		if _, ok := h.caches[name]; !ok {
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{
+				{Field: "services", Message: "unknown service " + name, Code: models.FieldCodeUnknownValue},
+			})
+			return
+		}
+	}
+
+	for _, name := range names {
+		h.caches[name].Invalidate()
+	}
+	response.JSON(w, http.StatusOK, models.CacheInvalidateResponse{Invalidated: names})
+}
+
+// names returns the registered cache names in sorted order.
+func (h *CacheHandler) names() []string {
+	names := make([]string, 0, len(h.caches))
+	for name := range h.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -4,19 +4,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/breatheroute/breatheroute/internal/airquality"
 	"github.com/breatheroute/breatheroute/internal/api/middleware"
 	"github.com/breatheroute/breatheroute/internal/api/models"
 	"github.com/breatheroute/breatheroute/internal/api/response"
 	"github.com/breatheroute/breatheroute/internal/commute"
+	"github.com/breatheroute/breatheroute/internal/pollen"
 )
 
 // CommuteHandler handles commute endpoints.
 type CommuteHandler struct {
-	service *commute.Service
+	service           *commute.Service
+	airQualityService *airquality.Service
+	pollenService     *pollen.Service
 }
 
 // NewCommuteHandler creates a new CommuteHandler.
@@ -24,17 +31,31 @@ func NewCommuteHandler(service *commute.Service) *CommuteHandler {
 	return &CommuteHandler{service: service}
 }
 
+// WithAirQualityService sets the air quality service used to forecast
+// exposure on upcoming commute days.
+func (h *CommuteHandler) WithAirQualityService(airQualityService *airquality.Service) *CommuteHandler {
+	h.airQualityService = airQualityService
+	return h
+}
+
+// WithPollenService sets the pollen service whose forecast scales predicted
+// exposure. Without it, pollen is left out of the ranking.
+func (h *CommuteHandler) WithPollenService(pollenService *pollen.Service) *CommuteHandler {
+	h.pollenService = pollenService
+	return h
+}
+
 // ListCommutes handles GET /v1/me/commutes - list saved commutes.
 func (h *CommuteHandler) ListCommutes(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	commutes, err := h.service.List(r.Context(), userID, 50)
 	if err != nil {
-		response.InternalError(w, r, "failed to list commutes")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to list commutes")
 		return
 	}
 
@@ -45,13 +66,13 @@ func (h *CommuteHandler) ListCommutes(w http.ResponseWriter, r *http.Request) {
 func (h *CommuteHandler) CreateCommute(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	var input models.CommuteCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
@@ -59,14 +80,15 @@ func (h *CommuteHandler) CreateCommute(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		var validationErr *commute.ValidationError
 		if errors.As(err, &validationErr) {
-			response.BadRequest(w, r, "validation failed", validationErr.Errors)
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", validationErr.Errors)
 			return
 		}
-		response.InternalError(w, r, "failed to create commute")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to create commute")
 		return
 	}
 
 	location := fmt.Sprintf("/v1/me/commutes/%s", result.ID)
+	setCommuteETag(w, result)
 	response.Created(w, location, result)
 }
 
@@ -74,90 +96,169 @@ func (h *CommuteHandler) CreateCommute(w http.ResponseWriter, r *http.Request) {
 func (h *CommuteHandler) GetCommute(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	commuteID := chi.URLParam(r, "commuteId")
 	if commuteID == "" {
-		response.BadRequest(w, r, "commuteId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "commuteId is required", nil)
 		return
 	}
 
 	result, err := h.service.Get(r.Context(), userID, commuteID)
 	if err != nil {
 		if errors.Is(err, commute.ErrCommuteNotFound) {
-			response.NotFound(w, r, "commute not found")
+			response.NotFound(w, r, models.ErrorCodeCommuteNotFound, "commute not found")
 			return
 		}
-		response.InternalError(w, r, "failed to get commute")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to get commute")
 		return
 	}
 
+	setCommuteETag(w, result)
 	response.JSON(w, http.StatusOK, result)
 }
 
 // UpdateCommute handles PUT /v1/me/commutes/{commuteId} - update a saved commute.
+// An If-Match header makes the update conditional on the commute's ETag.
 func (h *CommuteHandler) UpdateCommute(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	commuteID := chi.URLParam(r, "commuteId")
 	if commuteID == "" {
-		response.BadRequest(w, r, "commuteId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "commuteId is required", nil)
 		return
 	}
 
 	var input models.CommuteUpdateRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+
+	expectedVersion, ok := parseIfMatch(r.Header.Get("If-Match"))
+	if !ok {
+		response.PreconditionFailed(w, r, models.ErrorCodeCommuteVersionMismatch, "If-Match does not match the current commute version")
 		return
 	}
 
-	result, err := h.service.Update(r.Context(), userID, commuteID, &input)
+	result, err := h.service.Update(r.Context(), userID, commuteID, expectedVersion, &input)
 	if err != nil {
 		if errors.Is(err, commute.ErrCommuteNotFound) {
-			response.NotFound(w, r, "commute not found")
+			response.NotFound(w, r, models.ErrorCodeCommuteNotFound, "commute not found")
+			return
+		}
+		if errors.Is(err, commute.ErrVersionConflict) {
+			response.PreconditionFailed(w, r, models.ErrorCodeCommuteVersionMismatch, "commute has been modified; fetch the latest version and retry")
 			return
 		}
 		var validationErr *commute.ValidationError
 		if errors.As(err, &validationErr) {
-			response.BadRequest(w, r, "validation failed", validationErr.Errors)
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", validationErr.Errors)
 			return
 		}
-		response.InternalError(w, r, "failed to update commute")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to update commute")
 		return
 	}
 
+	setCommuteETag(w, result)
 	response.JSON(w, http.StatusOK, result)
 }
 
+// CloneCommute handles POST /v1/me/commutes/{commuteId}:clone - copy a saved
+// commute into a new one. The optional body overrides fields of the copy.
+func (h *CommuteHandler) CloneCommute(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	commuteID := chi.URLParam(r, "commuteId")
+	if commuteID == "" {
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "commuteId is required", nil)
+		return
+	}
+
+	var overrides models.CommuteUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+
+	result, err := h.service.Clone(r.Context(), userID, commuteID, &overrides)
+	if err != nil {
+		if errors.Is(err, commute.ErrCommuteNotFound) {
+			response.NotFound(w, r, models.ErrorCodeCommuteNotFound, "commute not found")
+			return
+		}
+		var validationErr *commute.ValidationError
+		if errors.As(err, &validationErr) {
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", validationErr.Errors)
+			return
+		}
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to clone commute")
+		return
+	}
+
+	location := fmt.Sprintf("/v1/me/commutes/%s", result.ID)
+	setCommuteETag(w, result)
+	response.Created(w, location, result)
+}
+
 // DeleteCommute handles DELETE /v1/me/commutes/{commuteId} - delete a saved commute.
 func (h *CommuteHandler) DeleteCommute(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	commuteID := chi.URLParam(r, "commuteId")
 	if commuteID == "" {
-		response.BadRequest(w, r, "commuteId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "commuteId is required", nil)
 		return
 	}
 
 	err := h.service.Delete(r.Context(), userID, commuteID)
 	if err != nil {
 		if errors.Is(err, commute.ErrCommuteNotFound) {
-			response.NotFound(w, r, "commute not found")
+			response.NotFound(w, r, models.ErrorCodeCommuteNotFound, "commute not found")
 			return
 		}
-		response.InternalError(w, r, "failed to delete commute")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to delete commute")
 		return
 	}
 
 	response.NoContent(w)
 }
+
+// setCommuteETag sets the ETag header to the commute's version.
+func setCommuteETag(w http.ResponseWriter, c *models.Commute) {
+	w.Header().Set("ETag", fmt.Sprintf("%q", strconv.FormatInt(c.Version, 10)))
+}
+
+// parseIfMatch returns the version an If-Match header requires, or 0 if the
+// header is absent or "*". Weak validators are accepted. ok is false if the
+// header is not a commute ETag, which can never match.
+func parseIfMatch(header string) (version int64, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, false
+	}
+	version, err = strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/commute"
+	"github.com/breatheroute/breatheroute/internal/pollen"
+)
+
+// bestDayCandidates is how many upcoming scheduled days are ranked.
+const bestDayCandidates = 7
+
+// exposureLimits are the EAQI "good" limits concentrations are scored
+// against, for commute days and route legs.
+var exposureLimits = map[airquality.Pollutant]float64{
+	airquality.PollutantNO2:  airquality.EAQINO2GoodMax,
+	airquality.PollutantPM25: airquality.EAQIPM25GoodMax,
+	airquality.PollutantO3:   airquality.EAQIO3GoodMax,
+}
+
+// GetBestDay handles GET /v1/me/commutes/{commuteId}/best-day - rank the
+// commute's next scheduled days by predicted exposure at arrival.
+func (h *CommuteHandler) GetBestDay(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	commuteID := chi.URLParam(r, "commuteId")
+	if commuteID == "" {
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "commuteId is required", nil)
+		return
+	}
+
+	result, arrivals, err := h.service.Upcoming(r.Context(), userID, commuteID, bestDayCandidates)
+	if err != nil {
+		if errors.Is(err, commute.ErrCommuteNotFound) {
+			response.NotFound(w, r, models.ErrorCodeCommuteNotFound, "commute not found")
+			return
+		}
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to get commute")
+		return
+	}
+
+	if h.airQualityService == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeAirQualityUnavailable, "air quality data is not available")
+		return
+	}
+
+	stops := make([]models.Point, 0, len(result.Waypoints)+2)
+	stops = append(stops, result.Origin.Point)
+	stops = append(stops, result.Waypoints...)
+	stops = append(stops, result.Destination.Point)
+
+	days := make([]models.CommuteDayForecast, 0, len(arrivals))
+	for _, arrival := range arrivals {
+		days = append(days, h.forecastDay(r.Context(), stops, arrival))
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	response.JSON(w, http.StatusOK, rankDays(commuteID, days, time.Now()))
+}
+
+// forecastDay predicts the exposure at the commute's stops at arrival,
+// scaled by the pollen forecast for the day.
+func (h *CommuteHandler) forecastDay(ctx context.Context, stops []models.Point, arrival time.Time) models.CommuteDayForecast {
+	day := models.CommuteDayForecast{
+		Date:      arrival.Format("2006-01-02"),
+		ArrivalAt: models.Timestamp(arrival),
+	}
+
+	var total float64
+	var samples int
+	category := airquality.AQIUnknown
+	for _, stop := range stops {
+		point, err := h.airQualityService.ForecastAt(ctx, stop.Lat, stop.Lon, arrival)
+		if err != nil {
+			day.UnrankedReason = models.UnrankedAirQualityForecastUnavailable
+			return day
+		}
+		for pollutant, limit := range exposureLimits {
+			if value, ok := point.Values[pollutant]; ok {
+				total += value.Value / limit
+				samples++
+			}
+		}
+		if point.AQICategory.WorseThan(category) {
+			category = point.AQICategory
+		}
+	}
+	if samples == 0 {
+		day.UnrankedReason = models.UnrankedAirQualityForecastUnavailable
+		return day
+	}
+	day.AQICategory = string(category)
+
+	factor := 1.0
+	if h.pollenService != nil {
+		origin := stops[0]
+		pollenFactor, risk, err := h.pollenService.GetForecastExposureFactor(ctx, origin.Lat, origin.Lon, arrival, nil)
+		switch {
+		case errors.Is(err, pollen.ErrPollenDisabled):
+			// Pollen is left out of the score
+		case err != nil:
+			day.UnrankedReason = models.UnrankedPollenForecastUnavailable
+			return day
+		default:
+			factor = pollenFactor
+			day.PollenRisk = string(risk)
+		}
+	}
+
+	score := total / float64(samples) * 100 * factor
+	day.Score = &score
+	return day
+}
+
+// rankDays numbers the scored days from lowest score, earliest first on
+// ties, and picks the best.
+func rankDays(commuteID string, days []models.CommuteDayForecast, now time.Time) *models.CommuteBestDay {
+	ranked := make([]int, 0, len(days))
+	for i := range days {
+		if days[i].Score != nil {
+			ranked = append(ranked, i)
+		}
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return *days[ranked[a]].Score < *days[ranked[b]].Score
+	})
+
+	result := &models.CommuteBestDay{
+		CommuteID:   commuteID,
+		Days:        days,
+		GeneratedAt: models.Timestamp(now),
+	}
+	for rank, i := range ranked {
+		days[i].Rank = intPtr(rank + 1)
+	}
+	if len(ranked) > 0 {
+		best := days[ranked[0]]
+		result.Best = &best
+	}
+	return result
+}
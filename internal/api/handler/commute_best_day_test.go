@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+func TestRankDays(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	days := []models.CommuteDayForecast{
+		{Date: "2026-10-19", Score: score(40)},
+		{Date: "2026-10-20", Score: score(25)},
+		{Date: "2026-10-21", UnrankedReason: models.UnrankedAirQualityForecastUnavailable},
+		{Date: "2026-10-22", Score: score(25)},
+	}
+
+	result := rankDays("cmt_1", days, time.Now())
+
+	wantRanks := map[string]int{"2026-10-19": 3, "2026-10-20": 1, "2026-10-22": 2}
+	for _, day := range result.Days {
+		want, ranked := wantRanks[day.Date]
+		if !ranked {
+			if day.Rank != nil {
+				t.Errorf("day %s rank = %d, want unranked", day.Date, *day.Rank)
+			}
+			continue
+		}
+		if day.Rank == nil || *day.Rank != want {
+			t.Errorf("day %s rank = %v, want %d", day.Date, day.Rank, want)
+		}
+	}
+
+	if result.Best == nil || result.Best.Date != "2026-10-20" {
+		t.Errorf("best = %v, want 2026-10-20", result.Best)
+	}
+
+	if rankDays("cmt_1", days[2:3], time.Now()).Best != nil {
+		t.Error("best set with no ranked days")
+	}
+}
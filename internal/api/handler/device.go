@@ -28,13 +28,13 @@ func NewDeviceHandler(service *device.Service) *DeviceHandler {
 func (h *DeviceHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	devices, err := h.service.List(r.Context(), userID, 50)
 	if err != nil {
-		response.InternalError(w, r, "failed to list devices")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to list devices")
 		return
 	}
 
@@ -45,25 +45,25 @@ func (h *DeviceHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
 func (h *DeviceHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	var input models.DeviceRegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
 	// Validate input
 	if fieldErrors := h.validateRegisterInput(&input); len(fieldErrors) > 0 {
-		response.BadRequest(w, r, "validation failed", fieldErrors)
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", fieldErrors)
 		return
 	}
 
 	result, created, err := h.service.Register(r.Context(), userID, &input)
 	if err != nil {
-		response.InternalError(w, r, "failed to register device")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to register device")
 		return
 	}
 
@@ -80,23 +80,23 @@ func (h *DeviceHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
 func (h *DeviceHandler) UnregisterDevice(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "authentication required")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
 		return
 	}
 
 	deviceID := chi.URLParam(r, "deviceId")
 	if deviceID == "" {
-		response.BadRequest(w, r, "deviceId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "deviceId is required", nil)
 		return
 	}
 
 	err := h.service.Unregister(r.Context(), userID, deviceID)
 	if err != nil {
 		if errors.Is(err, device.ErrDeviceNotFound) {
-			response.NotFound(w, r, "device not found")
+			response.NotFound(w, r, models.ErrorCodeDeviceNotFound, "device not found")
 			return
 		}
-		response.InternalError(w, r, "failed to unregister device")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to unregister device")
 		return
 	}
 
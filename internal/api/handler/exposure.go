@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/exposure"
+)
+
+// ExposureHandler handles the exposure history endpoint.
+type ExposureHandler struct {
+	service *exposure.Service
+}
+
+// NewExposureHandler creates a new ExposureHandler.
+func NewExposureHandler(service *exposure.Service) *ExposureHandler {
+	return &ExposureHandler{service: service}
+}
+
+// GetExposureHistory handles GET /v1/me/exposure/history - the user's
+// estimated commute exposure per week, newest first. The optional weeks
+// query parameter defaults to exposure.DefaultHistoryWeeks.
+func (h *ExposureHandler) GetExposureHistory(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "exposure history is not available")
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	weeks := exposure.DefaultHistoryWeeks
+	if raw := r.URL.Query().Get("weeks"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > exposure.MaxHistoryWeeks {
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{{
+				Field:   "weeks",
+				Message: fmt.Sprintf("must be an integer between 1 and %d", exposure.MaxHistoryWeeks),
+				Code:    models.FieldCodeOutOfRange,
+			}})
+			return
+		}
+		weeks = parsed
+	}
+
+	history, err := h.service.WeeklyHistory(r.Context(), userID, weeks)
+	if err != nil {
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to load exposure history")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, history)
+}
@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
 	"github.com/breatheroute/breatheroute/internal/api/response"
 	"github.com/breatheroute/breatheroute/internal/featureflags"
 )
@@ -26,11 +33,87 @@ func (h *FeatureFlagsHandler) ListFeatureFlags(w http.ResponseWriter, r *http.Re
 }
 
 // UpsertFeatureFlags handles PUT /v1/admin/flags - update feature flags.
+// Every change is recorded in the flag's history with the admin's user ID.
 func (h *FeatureFlagsHandler) UpsertFeatureFlags(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement upserting feature flags
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "feature flags are not available")
+		return
+	}
+
+	var input models.FeatureFlagsUpdateRequest
+	if err := middleware.DecodeJSON(r, &input); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+	if len(input.Flags) == 0 {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{{
+			Field:   "flags",
+			Message: "at least one flag is required",
+			Code:    models.FieldCodeRequired,
+		}})
+		return
+	}
+
+	err := h.service.SetFlags(r.Context(), input.Flags, middleware.GetUserID(r.Context()))
+	if errors.Is(err, featureflags.ErrInvalidFlagValue) {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{{
+			Field:   "flags",
+			Message: err.Error(),
+			Code:    models.FieldCodeInvalidFormat,
+		}})
+		return
+	}
+	if err != nil {
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to update feature flags")
+		return
+	}
 	response.NoContent(w)
 }
 
+// GetFlagHistory handles GET /v1/ops/flags/{key}/history - a flag's most
+// recent changes, newest first. The optional limit query parameter defaults
+// to featureflags.DefaultHistoryLimit.
+func (h *FeatureFlagsHandler) GetFlagHistory(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "feature flags are not available")
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	limit := featureflags.DefaultHistoryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > featureflags.MaxHistoryLimit {
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{{
+				Field:   "limit",
+				Message: fmt.Sprintf("must be an integer between 1 and %d", featureflags.MaxHistoryLimit),
+				Code:    models.FieldCodeOutOfRange,
+			}})
+			return
+		}
+		limit = parsed
+	}
+
+	changes, err := h.service.History(r.Context(), key, limit)
+	if err != nil {
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to list flag history")
+		return
+	}
+
+	history := models.FlagHistory{Key: key, Changes: make([]models.FlagChange, 0, len(changes))}
+	for _, c := range changes {
+		history.Changes = append(history.Changes, models.FlagChange{
+			PreviousValue: c.PreviousValue,
+			NewValue:      c.NewValue,
+			ChangedBy:     c.ChangedBy,
+			ChangedAt:     models.Timestamp(c.ChangedAt),
+		})
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, history)
+}
+
 // InvalidateCache handles POST /v1/admin/flags/invalidate - invalidate flag cache.
 func (h *FeatureFlagsHandler) InvalidateCache(w http.ResponseWriter, r *http.Request) {
 	// TODO: Implement cache invalidation
@@ -65,7 +65,7 @@ func (h *GDPRHandler) ListExportRequests(w http.ResponseWriter, _ *http.Request)
 func (h *GDPRHandler) GetExportRequest(w http.ResponseWriter, r *http.Request) {
 	requestID := chi.URLParam(r, "exportRequestId")
 	if requestID == "" {
-		response.BadRequest(w, r, "exportRequestId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "exportRequestId is required", nil)
 		return
 	}
 
@@ -124,7 +124,7 @@ func (h *GDPRHandler) ListDeletionRequests(w http.ResponseWriter, _ *http.Reques
 func (h *GDPRHandler) GetDeletionRequest(w http.ResponseWriter, r *http.Request) {
 	requestID := chi.URLParam(r, "deletionRequestId")
 	if requestID == "" {
-		response.BadRequest(w, r, "deletionRequestId is required", nil)
+		response.BadRequest(w, r, models.ErrorCodeMissingParameter, "deletionRequestId is required", nil)
 		return
 	}
 
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -25,17 +24,17 @@ func NewMeHandler(userService *user.Service) *MeHandler {
 func (h *MeHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "user not authenticated")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
 		return
 	}
 
 	me, err := h.userService.GetMe(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
-			response.NotFound(w, r, "user")
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
 			return
 		}
-		response.InternalError(w, r, "internal server error")
+		response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
 		return
 	}
 
@@ -46,20 +45,20 @@ func (h *MeHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 func (h *MeHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "user not authenticated")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
 		return
 	}
 
 	var input models.MeInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+	if err := middleware.DecodeJSON(r, &input); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
 	// Validate units if provided
 	if input.Units != nil {
 		if *input.Units != models.UnitsMetric && *input.Units != models.UnitsImperial {
-			response.BadRequest(w, r, "invalid units value", []models.FieldError{
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "invalid units value", []models.FieldError{
 				{Field: "units", Message: "must be METRIC or IMPERIAL"},
 			})
 			return
@@ -69,10 +68,10 @@ func (h *MeHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	me, err := h.userService.UpdateMe(r.Context(), userID, &input)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
-			response.NotFound(w, r, "user")
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
 			return
 		}
-		response.InternalError(w, r, "internal server error")
+		response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
 		return
 	}
 
@@ -83,17 +82,17 @@ func (h *MeHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 func (h *MeHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "user not authenticated")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
 		return
 	}
 
 	consents, err := h.userService.GetConsents(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
-			response.NotFound(w, r, "user")
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
 			return
 		}
-		response.InternalError(w, r, "internal server error")
+		response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
 		return
 	}
 
@@ -104,23 +103,23 @@ func (h *MeHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
 func (h *MeHandler) UpdateConsents(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "user not authenticated")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
 		return
 	}
 
 	var input models.ConsentsInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+	if err := middleware.DecodeJSON(r, &input); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
 	consents, err := h.userService.UpdateConsents(r.Context(), userID, &input)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
-			response.NotFound(w, r, "user")
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
 			return
 		}
-		response.InternalError(w, r, "internal server error")
+		response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
 		return
 	}
 
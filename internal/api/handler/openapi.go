@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/breatheroute/breatheroute/internal/api/openapi"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+)
+
+// OpenAPIHandler serves the API's OpenAPI document.
+type OpenAPIHandler struct {
+	doc *openapi.Document
+}
+
+// NewOpenAPIHandler creates a new OpenAPIHandler for the given API version.
+// The document is built once, as it only changes between releases.
+func NewOpenAPIHandler(version string) *OpenAPIHandler {
+	return &OpenAPIHandler{doc: openapi.Spec(version)}
+}
+
+// GetSpec handles GET /v1/openapi.json - the OpenAPI 3 description of the API.
+func (h *OpenAPIHandler) GetSpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	response.JSON(w, http.StatusOK, h.doc)
+}
@@ -2,7 +2,10 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/sony/gobreaker/v2"
@@ -14,11 +17,40 @@ import (
 
 // OpsHandler handles operational endpoints.
 type OpsHandler struct {
-	version          string
-	buildTime        string
-	providerRegistry *resilience.Registry
+	version            string
+	buildTime          string
+	providerRegistries []*resilience.Registry
+	providerNames      map[string]string
+	cacheStats         map[string]CacheStatsFunc
+	staleThresholds    map[string]time.Duration
+	readinessChecks    map[string]ReadinessCheckFunc
+	poolStats          PoolStatsFunc
+	refreshMetrics     RefreshMetricsFunc
 }
 
+// CacheStatsFunc reports a service's cache statistics, including when its
+// entries were fetched.
+type CacheStatsFunc func() models.ServiceCache
+
+// PoolStatsFunc reports the current database connection pool usage.
+type PoolStatsFunc func() models.DatabasePool
+
+// RefreshMetricsFunc returns a snapshot of the provider refresh job's
+// metrics, such as worker.RefreshJob.MetricsSnapshot.
+type RefreshMetricsFunc func() map[string]interface{}
+
+// ReadinessCheckFunc returns an error if a subsystem cannot serve traffic.
+type ReadinessCheckFunc func(ctx context.Context) error
+
+// DefaultCacheStaleThreshold is how old a cache's newest data may get
+// before the system status reports it as serving stale data. It allows for
+// hourly air quality updates.
+const DefaultCacheStaleThreshold = 90 * time.Minute
+
+// readinessCheckTimeout bounds each readiness check so a hung dependency
+// fails the probe instead of stalling it.
+const readinessCheckTimeout = 2 * time.Second
+
 // NewOpsHandler creates a new OpsHandler.
 func NewOpsHandler(version, buildTime string) *OpsHandler {
 	return &OpsHandler{
@@ -27,33 +59,144 @@ func NewOpsHandler(version, buildTime string) *OpsHandler {
 	}
 }
 
-// WithProviderRegistry sets the provider registry for health reporting.
+// WithProviderRegistry adds a provider registry for health reporting. The
+// providers of every registry added are reported together; nil is ignored.
 func (h *OpsHandler) WithProviderRegistry(registry *resilience.Registry) *OpsHandler {
-	h.providerRegistry = registry
+	if registry != nil {
+		h.providerRegistries = append(h.providerRegistries, registry)
+	}
+	return h
+}
+
+// WithProviderNames sets the configured provider names keyed by data domain
+// (e.g., "routing" -> "openrouteservice") for reporting in the health check.
+func (h *OpsHandler) WithProviderNames(names map[string]string) *OpsHandler {
+	h.providerNames = names
+	return h
+}
+
+// WithCacheStats adds a named cache whose hit ratio and freshness are
+// reported in the system status.
+func (h *OpsHandler) WithCacheStats(name string, stats CacheStatsFunc) *OpsHandler {
+	if h.cacheStats == nil {
+		h.cacheStats = make(map[string]CacheStatsFunc)
+	}
+	h.cacheStats[name] = stats
+	return h
+}
+
+// WithStaleThreshold sets how old the named cache's newest data may get
+// before it is reported as serving stale data (default:
+// DefaultCacheStaleThreshold). Zero or negative thresholds are ignored.
+func (h *OpsHandler) WithStaleThreshold(name string, threshold time.Duration) *OpsHandler {
+	if threshold <= 0 {
+		return h
+	}
+	if h.staleThresholds == nil {
+		h.staleThresholds = make(map[string]time.Duration)
+	}
+	h.staleThresholds[name] = threshold
+	return h
+}
+
+// WithReadinessCheck adds a named subsystem that must be ready before the
+// readiness check reports OK.
+func (h *OpsHandler) WithReadinessCheck(name string, check ReadinessCheckFunc) *OpsHandler {
+	if h.readinessChecks == nil {
+		h.readinessChecks = make(map[string]ReadinessCheckFunc)
+	}
+	h.readinessChecks[name] = check
+	return h
+}
+
+// WithPoolStats sets the database connection pool reported in the system
+// status.
+func (h *OpsHandler) WithPoolStats(stats PoolStatsFunc) *OpsHandler {
+	h.poolStats = stats
+	return h
+}
+
+// WithRefreshMetrics sets the refresh job metrics reported by the metrics
+// snapshot.
+func (h *OpsHandler) WithRefreshMetrics(metrics RefreshMetricsFunc) *OpsHandler {
+	h.refreshMetrics = metrics
 	return h
 }
 
 // HealthCheck handles GET /v1/ops/health - liveness check.
+// Reports the running build and configured providers so operators can
+// confirm which version is live during a rollout.
 func (h *OpsHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	details := map[string]interface{}{
+		"version":   h.version,
+		"buildTime": h.buildTime,
+	}
+	if len(h.providerNames) > 0 {
+		details["providers"] = h.providerNames
+	}
+
 	health := models.Health{
-		Status: models.HealthStatusOK,
-		Time:   models.Timestamp(time.Now()),
-		Details: map[string]interface{}{
-			"version":   h.version,
-			"buildTime": h.buildTime,
-		},
+		Status:  models.HealthStatusOK,
+		Time:    models.Timestamp(time.Now()),
+		Details: details,
 	}
 	response.JSON(w, http.StatusOK, health)
 }
 
 // ReadinessCheck handles GET /v1/ops/ready - readiness check.
+// Runs every registered readiness check concurrently and returns 503 with
+// the failing subsystems if any check fails. Liveness (/health) does not
+// depend on these checks.
 func (h *OpsHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
-	// TODO: Add actual dependency checks (database, cache, etc.)
+	results := h.runReadinessChecks(r.Context())
+
 	health := models.Health{
 		Status: models.HealthStatusOK,
 		Time:   models.Timestamp(time.Now()),
 	}
-	response.JSON(w, http.StatusOK, health)
+	status := http.StatusOK
+
+	if len(results) > 0 {
+		checks := make(map[string]string, len(results))
+		for name, err := range results {
+			if err != nil {
+				checks[name] = err.Error()
+				health.Status = models.HealthStatusFail
+				status = http.StatusServiceUnavailable
+			} else {
+				checks[name] = string(models.HealthStatusOK)
+			}
+		}
+		health.Details = map[string]interface{}{"checks": checks}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, status, health)
+}
+
+// runReadinessChecks runs all readiness checks concurrently, each with its
+// own timeout, and returns their results by name.
+func (h *OpsHandler) runReadinessChecks(ctx context.Context) map[string]error {
+	results := make(map[string]error, len(h.readinessChecks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, check := range h.readinessChecks {
+		wg.Add(1)
+		go func(name string, check ReadinessCheckFunc) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+			defer cancel()
+			err := check(checkCtx)
+
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+	return results
 }
 
 // SystemStatus handles GET /v1/ops/status - provider and subsystem status.
@@ -74,25 +217,98 @@ func (h *OpsHandler) SystemStatus(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	database := models.SubsystemStatus{Name: "cloud-sql", Status: models.HealthStatusOK}
+	var pool *models.DatabasePool
+	if h.poolStats != nil {
+		stats := h.poolStats()
+		pool = &stats
+		// Every connection in use means new queries queue for one
+		if stats.MaxConns > 0 && stats.AcquiredConns >= stats.MaxConns {
+			database.Status = models.HealthStatusDegraded
+			detail := "connection pool exhausted"
+			database.Detail = &detail
+		}
+	}
+
 	status := models.SystemStatus{
 		Status: overallStatus,
 		Time:   now,
 		Subsystems: []models.SubsystemStatus{
-			{Name: "cloud-sql", Status: models.HealthStatusOK},
+			database,
 			{Name: "redis", Status: models.HealthStatusOK},
 		},
-		Providers: providers,
+		Providers:    providers,
+		Caches:       h.getCacheStatuses(time.Now()),
+		DatabasePool: pool,
 	}
 	response.JSON(w, http.StatusOK, status)
 }
 
-// getProviderStatuses returns the status of all registered providers.
-func (h *OpsHandler) getProviderStatuses() []models.ProviderStatus {
-	if h.providerRegistry == nil {
-		return []models.ProviderStatus{}
+// Metrics handles GET /v1/ops/metrics - JSON metrics snapshot.
+// Combines refresh job counters, cache statistics and provider health in one
+// document. It only reads counters already kept in memory and never calls a
+// provider.
+func (h *OpsHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	report := models.MetricsReport{
+		Time:      models.Timestamp(now),
+		Caches:    h.getCacheStatuses(now),
+		Providers: h.getProviderStatuses(),
+	}
+	if h.refreshMetrics != nil {
+		report.Refresh = h.refreshMetrics()
 	}
 
-	healthList := h.providerRegistry.GetAllHealth()
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, report)
+}
+
+// getCacheStatuses returns the hit ratio and data age of each registered
+// cache at now, sorted by name.
+func (h *OpsHandler) getCacheStatuses(now time.Time) []models.CacheStatus {
+	statuses := make([]models.CacheStatus, 0, len(h.cacheStats))
+	for name, statsFunc := range h.cacheStats {
+		stats := statsFunc()
+		threshold := h.staleThreshold(name)
+		cs := models.CacheStatus{
+			Name:                  name,
+			Provider:              stats.Provider,
+			Hits:                  stats.Hits,
+			Misses:                stats.Misses,
+			StaleThresholdSeconds: int(threshold.Seconds()),
+		}
+		if total := stats.Hits + stats.Misses; total > 0 {
+			cs.HitRatio = float64(stats.Hits) / float64(total)
+		}
+		if stats.FetchedAt != nil {
+			age := now.Sub(time.Time(*stats.FetchedAt))
+			cs.DataAgeSeconds = intPtr(int(age.Seconds()))
+			cs.ServingStale = age > threshold
+		}
+		if stats.OldestFreshAt != nil {
+			cs.OldestFreshAgeSeconds = intPtr(int(now.Sub(time.Time(*stats.OldestFreshAt)).Seconds()))
+		}
+		statuses = append(statuses, cs)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// staleThreshold returns the stale threshold of the named cache.
+func (h *OpsHandler) staleThreshold(name string) time.Duration {
+	if threshold, ok := h.staleThresholds[name]; ok {
+		return threshold
+	}
+	return DefaultCacheStaleThreshold
+}
+
+// getProviderStatuses returns the status of the providers in all
+// registries, sorted by name.
+func (h *OpsHandler) getProviderStatuses() []models.ProviderStatus {
+	healthList := resilience.SnapshotAll(h.providerRegistries...)
 	statuses := make([]models.ProviderStatus, 0, len(healthList))
 
 	for _, health := range healthList {
@@ -101,6 +317,11 @@ func (h *OpsHandler) getProviderStatuses() []models.ProviderStatus {
 			Status:   h.mapCircuitStateToHealth(health.CircuitState),
 		}
 
+		if health.Counts.Requests > 0 {
+			errorRate := health.ErrorRate()
+			ps.ErrorRate = &errorRate
+		}
+
 		if health.LastSuccessAt != nil {
 			ts := models.Timestamp(*health.LastSuccessAt)
 			ps.LastSuccessAt = &ts
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+func TestSystemStatus_CacheFreshness(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) *models.Timestamp {
+		ts := models.Timestamp(now.Add(-ago))
+		return &ts
+	}
+
+	h := NewOpsHandler("test", "").
+		// Healthy provider, fresh cache
+		WithCacheStats("routing", func() models.ServiceCache {
+			return models.ServiceCache{
+				Provider:      "openrouteservice",
+				Hits:          3,
+				Misses:        1,
+				FetchedAt:     at(time.Minute),
+				OldestFreshAt: at(4 * time.Minute),
+			}
+		}).
+		// Provider down: nothing fetched for 20 minutes
+		WithCacheStats("transit", func() models.ServiceCache {
+			return models.ServiceCache{Provider: "ns", FetchedAt: at(20 * time.Minute)}
+		}).
+		WithStaleThreshold("transit", 15*time.Minute).
+		// Nothing cached yet
+		WithCacheStats("weather", func() models.ServiceCache {
+			return models.ServiceCache{Provider: "openweathermap"}
+		})
+
+	w := httptest.NewRecorder()
+	h.SystemStatus(w, httptest.NewRequest(http.MethodGet, "/v1/ops/status", http.NoBody))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var status models.SystemStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	if len(status.Caches) != 3 {
+		t.Fatalf("got %d caches, want 3", len(status.Caches))
+	}
+
+	routing, transit, weather := status.Caches[0], status.Caches[1], status.Caches[2]
+
+	if routing.Provider != "openrouteservice" || routing.HitRatio != 0.75 {
+		t.Errorf("routing = %+v, want provider and hit ratio 0.75", routing)
+	}
+	if routing.ServingStale {
+		t.Error("routing cache is fresh")
+	}
+	if routing.DataAgeSeconds == nil || *routing.DataAgeSeconds != 60 {
+		t.Errorf("routing data age = %v, want 60", routing.DataAgeSeconds)
+	}
+	if routing.OldestFreshAgeSeconds == nil || *routing.OldestFreshAgeSeconds != 240 {
+		t.Errorf("routing oldest fresh age = %v, want 240", routing.OldestFreshAgeSeconds)
+	}
+	if routing.StaleThresholdSeconds != int(DefaultCacheStaleThreshold.Seconds()) {
+		t.Errorf("routing threshold = %d, want default", routing.StaleThresholdSeconds)
+	}
+
+	if !transit.ServingStale {
+		t.Error("transit data older than its threshold should be stale")
+	}
+	if transit.StaleThresholdSeconds != 900 {
+		t.Errorf("transit threshold = %d, want 900", transit.StaleThresholdSeconds)
+	}
+	if transit.OldestFreshAgeSeconds != nil {
+		t.Error("transit has no fresh entries")
+	}
+
+	if weather.ServingStale || weather.DataAgeSeconds != nil {
+		t.Errorf("empty weather cache = %+v, want no age and not stale", weather)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	fetched := models.Timestamp(time.Now().Add(-time.Minute))
+	h := NewOpsHandler("test", "").
+		WithCacheStats("routing", func() models.ServiceCache {
+			return models.ServiceCache{Provider: "openrouteservice", Hits: 1, Misses: 1, FetchedAt: &fetched}
+		}).
+		WithRefreshMetrics(func() map[string]interface{} {
+			return map[string]interface{}{"total_refreshes": 4}
+		})
+
+	w := httptest.NewRecorder()
+	h.Metrics(w, httptest.NewRequest(http.MethodGet, "/v1/ops/metrics", http.NoBody))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+
+	var report models.MetricsReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode metrics: %v", err)
+	}
+	if got := report.Refresh["total_refreshes"]; got != float64(4) {
+		t.Errorf("total_refreshes = %v, want 4", got)
+	}
+	if len(report.Caches) != 1 || report.Caches[0].HitRatio != 0.5 {
+		t.Errorf("caches = %+v, want routing with hit ratio 0.5", report.Caches)
+	}
+	if report.Providers == nil {
+		t.Error("providers should be an empty list, not null")
+	}
+}
@@ -1,9 +1,9 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/breatheroute/breatheroute/internal/api/middleware"
 	"github.com/breatheroute/breatheroute/internal/api/models"
@@ -25,17 +25,17 @@ func NewProfileHandler(userService *user.Service) *ProfileHandler {
 func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "user not authenticated")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
 		return
 	}
 
 	profile, err := h.userService.GetProfile(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
-			response.NotFound(w, r, "user")
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
 			return
 		}
-		response.InternalError(w, r, "internal server error")
+		response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
 		return
 	}
 
@@ -46,29 +46,71 @@ func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 func (h *ProfileHandler) UpsertProfile(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
-		response.Unauthorized(w, r, "user not authenticated")
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
 		return
 	}
 
 	var input models.ProfileInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+	if err := middleware.DecodeJSON(r, &input); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
 	fieldErrors := validateProfileInput(&input)
 	if len(fieldErrors) > 0 {
-		response.BadRequest(w, r, "validation failed", fieldErrors)
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", fieldErrors)
 		return
 	}
 
 	profile, err := h.userService.UpsertProfile(r.Context(), userID, &input)
 	if err != nil {
-		if errors.Is(err, user.ErrUserNotFound) {
-			response.NotFound(w, r, "user")
-			return
+		var validationErr *user.ValidationError
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
+		case errors.As(err, &validationErr):
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", validationErr.Errors)
+		default:
+			response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, profile)
+}
+
+// PatchProfile handles PATCH /v1/me/profile - partially update profile.
+// Unlike PUT, omitted fields (including the whole weights object) are left unchanged.
+func (h *ProfileHandler) PatchProfile(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "user not authenticated")
+		return
+	}
+
+	var patch models.ProfilePatch
+	if err := middleware.DecodeJSON(r, &patch); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+
+	fieldErrors := validateProfilePatch(&patch)
+	if len(fieldErrors) > 0 {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", fieldErrors)
+		return
+	}
+
+	profile, err := h.userService.PatchProfile(r.Context(), userID, &patch)
+	if err != nil {
+		var validationErr *user.ValidationError
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			response.NotFound(w, r, models.ErrorCodeUserNotFound, "user")
+		case errors.As(err, &validationErr):
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", validationErr.Errors)
+		default:
+			response.InternalError(w, r, models.ErrorCodeInternal, "internal server error")
 		}
-		response.InternalError(w, r, "internal server error")
 		return
 	}
 
@@ -88,9 +130,43 @@ func validateProfileInput(input *models.ProfileInput) []models.FieldError {
 	// Validate route constraints
 	fieldErrors = validateConstraints(fieldErrors, input.Constraints)
 
+	fieldErrors = validatePollenSensitivities(fieldErrors, input.PollenSensitivities)
+
+	return fieldErrors
+}
+
+// validateProfilePatch validates the fields present in a profile patch.
+func validateProfilePatch(patch *models.ProfilePatch) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if patch.Weights != nil {
+		fieldErrors = validateOptionalWeight(fieldErrors, patch.Weights.NO2, "weights.no2")
+		fieldErrors = validateOptionalWeight(fieldErrors, patch.Weights.PM25, "weights.pm25")
+		fieldErrors = validateOptionalWeight(fieldErrors, patch.Weights.O3, "weights.o3")
+		fieldErrors = validateOptionalWeight(fieldErrors, patch.Weights.Pollen, "weights.pollen")
+	}
+
+	if patch.Constraints != nil {
+		fieldErrors = validateConstraints(fieldErrors, models.RouteConstraints{
+			MaxExtraMinutesVsFastest: patch.Constraints.MaxExtraMinutesVsFastest,
+			MaxTransfers:             patch.Constraints.MaxTransfers,
+			EffortWeight:             patch.Constraints.EffortWeight,
+		})
+	}
+
+	fieldErrors = validatePollenSensitivities(fieldErrors, patch.PollenSensitivities)
+
 	return fieldErrors
 }
 
+// validateOptionalWeight validates a weight field if present.
+func validateOptionalWeight(errs []models.FieldError, value *float64, field string) []models.FieldError {
+	if value == nil {
+		return errs
+	}
+	return validateWeight(errs, *value, field)
+}
+
 // validateWeight validates a weight field is in range [0, 1].
 func validateWeight(errs []models.FieldError, value float64, field string) []models.FieldError {
 	if value < 0 || value > 1 {
@@ -102,6 +178,32 @@ func validateWeight(errs []models.FieldError, value float64, field string) []mod
 	return errs
 }
 
+// Limits on the pollen sensitivities stored on a profile.
+const (
+	maxPollenSensitivities     = 20
+	maxPollenSensitivityLength = 50
+)
+
+// validatePollenSensitivities validates the number and length of pollen
+// sensitivities.
+func validatePollenSensitivities(errs []models.FieldError, values []string) []models.FieldError {
+	if len(values) > maxPollenSensitivities {
+		return append(errs, models.FieldError{
+			Field:   "pollenSensitivities",
+			Message: "must have at most 20 entries",
+		})
+	}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value == "" || len(value) > maxPollenSensitivityLength {
+			return append(errs, models.FieldError{
+				Field:   "pollenSensitivities",
+				Message: "entries must be between 1 and 50 characters",
+			})
+		}
+	}
+	return errs
+}
+
 // validateConstraints validates route constraint fields.
 func validateConstraints(errs []models.FieldError, constraints models.RouteConstraints) []models.FieldError {
 	if constraints.MaxExtraMinutesVsFastest != nil {
@@ -120,5 +222,8 @@ func validateConstraints(errs []models.FieldError, constraints models.RouteConst
 			})
 		}
 	}
+	if constraints.EffortWeight != nil {
+		errs = validateWeight(errs, *constraints.EffortWeight, "constraints.effortWeight")
+	}
 	return errs
 }
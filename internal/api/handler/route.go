@@ -5,22 +5,42 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
 	"github.com/breatheroute/breatheroute/internal/api/models"
 	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/routestore"
 	"github.com/breatheroute/breatheroute/internal/routing"
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/user"
+	"github.com/breatheroute/breatheroute/internal/weather"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
 )
 
 // RouteHandler handles routing endpoints.
 type RouteHandler struct {
-	routingService *routing.Service
-	logger         zerolog.Logger
+	routingService     *routing.Service
+	transitService     *transit.Service
+	airQualityService  *airquality.Service
+	weatherService     *weather.Service
+	exposureConfidence ExposureConfidenceConfig
+	exposureSampling   ExposureSamplingConfig
+	routeStore         *routestore.Service
+	maintenance        middleware.MaintenanceCheck
+	userService        *user.Service
+	estimateFallback   bool
+	logger             zerolog.Logger
 }
 
 // NewRouteHandler creates a new RouteHandler.
@@ -31,17 +51,95 @@ func NewRouteHandler(routingService *routing.Service, logger zerolog.Logger) *Ro
 	}
 }
 
+// WithTransitService enables disruption reporting on train legs.
+// transitService may be nil when no transit provider is configured.
+func (h *RouteHandler) WithTransitService(transitService *transit.Service) *RouteHandler {
+	h.transitService = transitService
+	return h
+}
+
+// WithAirQualityService enables exposure confidence reporting on route
+// options. airQualityService may be nil when no air quality provider is
+// configured.
+func (h *RouteHandler) WithAirQualityService(airQualityService *airquality.Service) *RouteHandler {
+	h.airQualityService = airQualityService
+	return h
+}
+
+// WithWeatherService enables weather in route responses for requests with
+// includeWeather set. weatherService may be nil when no weather provider is
+// configured.
+func (h *RouteHandler) WithWeatherService(weatherService *weather.Service) *RouteHandler {
+	h.weatherService = weatherService
+	return h
+}
+
+// WithExposureConfidence sets when an option's exposure estimate is flagged
+// as unreliable.
+func (h *RouteHandler) WithExposureConfidence(cfg ExposureConfidenceConfig) *RouteHandler {
+	h.exposureConfidence = cfg
+	return h
+}
+
+// WithExposureSampling sets how densely routes are sampled for air quality.
+func (h *RouteHandler) WithExposureSampling(cfg ExposureSamplingConfig) *RouteHandler {
+	h.exposureSampling = cfg
+	return h
+}
+
+// WithRouteStore enables storing computed routes, so they can be fetched
+// again by ID. routeStore may be nil, in which case responses carry no ID.
+func (h *RouteHandler) WithRouteStore(routeStore *routestore.Service) *RouteHandler {
+	h.routeStore = routeStore
+	return h
+}
+
+// WithMaintenanceCheck skips storing computed routes while check reports
+// maintenance mode, so route computation keeps working without writing.
+// Responses computed during maintenance carry no ID.
+func (h *RouteHandler) WithMaintenanceCheck(check middleware.MaintenanceCheck) *RouteHandler {
+	h.maintenance = check
+	return h
+}
+
+// WithUserService enables ranking balanced routes for signed-in users with
+// the effort weight stored in their profile when the request carries none.
+// userService may be nil, in which case only profile overrides are used.
+func (h *RouteHandler) WithUserService(userService *user.Service) *RouteHandler {
+	h.userService = userService
+	return h
+}
+
+// WithEstimatedFallback enables straight-line route estimates for modes the
+// routing provider cannot route because it is down or rate limited. The
+// estimates are flagged as estimated and have LOW confidence.
+func (h *RouteHandler) WithEstimatedFallback(enabled bool) *RouteHandler {
+	h.estimateFallback = enabled
+	return h
+}
+
+// Server-Sent Event names used when streaming route computation.
+const (
+	eventOption  = "option"
+	eventWarning = "warning"
+	eventWeather = "weather"
+	eventDone    = "done"
+)
+
 // ComputeRoutes handles POST /v1/routes:compute - compute route options.
+// Clients sending "Accept: text/event-stream" receive options progressively via SSE,
+// and clients sending "Accept: application/geo+json" a GeoJSON FeatureCollection.
+// Route geometry is simplified unless the "full=true" query parameter is set.
 func (h *RouteHandler) ComputeRoutes(w http.ResponseWriter, r *http.Request) {
 	var input models.RouteComputeRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.BadRequest(w, r, "invalid JSON body", nil)
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
 		return
 	}
 
 	// Validate: either commuteId or origin+destination required
 	if input.CommuteID == nil && (input.Origin == nil || input.Destination == nil) {
-		response.BadRequest(w, r, "either commuteId or origin and destination are required", []models.FieldError{
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "either commuteId or origin and destination are required", []models.FieldError{
 			{Field: "commuteId", Message: "required if origin/destination not provided"},
 			{Field: "origin", Message: "required if commuteId not provided"},
 			{Field: "destination", Message: "required if commuteId not provided"},
@@ -49,64 +147,331 @@ func (h *RouteHandler) ComputeRoutes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-	now := models.Timestamp(time.Now())
+	if fieldErrors := append(validateWaypoints(input.Waypoints), validateBlend(input.Blend)...); len(fieldErrors) > 0 {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", fieldErrors)
+		return
+	}
 
-	// Determine which modes to compute routes for
-	modes := input.Modes
-	if len(modes) == 0 {
-		// Default modes: BIKE and WALK
-		modes = []models.Mode{models.ModeBike, models.ModeWalk}
+	if err := h.checkSeparation(input); err != nil {
+		response.UnprocessableEntity(w, r, models.ErrorCodeSameOriginDestination, err.Error())
+		return
 	}
+	if err := h.checkRouteDistance(input); err != nil {
+		response.UnprocessableEntity(w, r, models.ErrorCodeRouteTooLong, err.Error())
+		return
+	}
+
+	if acceptsEventStream(r) {
+		h.streamRoutes(w, r, input)
+		return
+	}
+
+	ctx := r.Context()
+	now := models.Timestamp(time.Now())
 
 	var options []models.RouteOption
 	var warnings []models.Warning
 
+	// Weather does not depend on the routes, so it is fetched alongside them
+	var conditions *models.RouteWeather
+	var weatherWarnings []models.Warning
+	weatherDone := make(chan struct{})
+	go func() {
+		defer close(weatherDone)
+		if wantsWeather(input) {
+			conditions, weatherWarnings = h.routeWeather(ctx, input, time.Now())
+		}
+	}()
+
 	// Compute routes for each mode
-	for _, mode := range modes {
+	for _, mode := range requestedModes(input) {
 		profile := modeToProfile(mode)
 		if profile == "" {
 			// Skip unsupported modes like TRAIN (handled separately via NS API)
 			continue
 		}
 
-		routeOptions, modeWarnings := h.computeRoutesForMode(ctx, input, mode, profile)
+		routeOptions, modeWarnings := h.computeRoutesForMode(ctx, input, mode, profile, wantsFullGeometry(r))
 		options = append(options, routeOptions...)
 		warnings = append(warnings, modeWarnings...)
 	}
+	h.attachTrainDisruptions(ctx, options, requestLocale(r))
+	h.assessExposureConfidence(ctx, options)
 
 	// Sort options by objective
-	h.sortOptionsByObjective(options, input.Objective)
+	h.sortOptions(options, h.rankingFor(ctx, middleware.GetUserID(ctx), input))
 
 	// Apply maxOptions limit
-	maxOptions := 5
-	if input.MaxOptions != nil && *input.MaxOptions > 0 {
-		maxOptions = *input.MaxOptions
-	}
+	maxOptions := maxOptionsFor(input)
 	if len(options) > maxOptions {
 		options = options[:maxOptions]
 	}
 
-	resp := models.RouteComputeResponse{
+	<-weatherDone
+	resp := h.storeRoutes(ctx, models.RouteComputeResponse{
 		GeneratedAt: now,
 		Options:     options,
-		Warnings:    warnings,
+		Weather:     conditions,
+		Warnings:    append(warnings, weatherWarnings...),
+	})
+
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	writeRoutes(w, r, resp)
+}
+
+// GetRoute handles GET /v1/routes/{routeId} - a stored route computation.
+// Like ComputeRoutes, it responds with GeoJSON if the client accepts it.
+func (h *RouteHandler) GetRoute(w http.ResponseWriter, r *http.Request) {
+	route, ok := h.storedRoute(w, r)
+	if !ok {
+		return
 	}
 
 	w.Header().Set("Cache-Control", "private, max-age=60")
+	writeRoutes(w, r, route.Response)
+}
+
+// writeRoutes writes a route computation as GeoJSON or the default JSON
+// model, depending on the Accept header.
+func writeRoutes(w http.ResponseWriter, r *http.Request, resp models.RouteComputeResponse) {
+	w.Header().Add("Vary", "Accept")
+	if acceptsGeoJSON(r) {
+		response.GeoJSON(w, http.StatusOK, routeFeatureCollection(resp))
+		return
+	}
 	response.JSON(w, http.StatusOK, resp)
 }
 
+// storeRoutes saves the response if a route store is configured and the
+// API is not in maintenance mode, returning it with its ID. A failure to
+// store is logged and the response returned without an ID, since the routes
+// themselves are still valid.
+func (h *RouteHandler) storeRoutes(ctx context.Context, resp models.RouteComputeResponse) models.RouteComputeResponse {
+	if h.routeStore == nil || (h.maintenance != nil && h.maintenance(ctx)) {
+		return resp
+	}
+	route, err := h.routeStore.Save(ctx, middleware.GetUserID(ctx), resp)
+	if err != nil {
+		telemetry.Logger(ctx, h.logger).Warn().Err(err).Msg("failed to store computed routes")
+		return resp
+	}
+	return route.Response
+}
+
+// storedRoute looks up the route named by the routeId URL parameter for the
+// requesting user, writing an error response if it cannot be served.
+func (h *RouteHandler) storedRoute(w http.ResponseWriter, r *http.Request) (*routestore.StoredRoute, bool) {
+	if h.routeStore == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "stored routes are not available")
+		return nil, false
+	}
+
+	route, err := h.routeStore.Get(r.Context(), chi.URLParam(r, "routeId"), middleware.GetUserID(r.Context()))
+	if errors.Is(err, routestore.ErrRouteNotFound) {
+		response.NotFound(w, r, models.ErrorCodeRouteNotFound, "route not found or expired; compute it again")
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to load stored route")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to load route")
+		return nil, false
+	}
+	return route, true
+}
+
+// streamRoutes computes routes mode by mode, emitting each option as an SSE
+// "option" event as soon as it is scored, followed by a "weather" event if
+// requested and a final "done" event with the ranking. A client disconnect cancels the request context, which aborts
+// any in-flight provider calls and stops the stream.
+func (h *RouteHandler) streamRoutes(w http.ResponseWriter, r *http.Request, input models.RouteComputeRequest) {
+	ctx := r.Context()
+	stream := response.NewEventStream(w)
+
+	var options []models.RouteOption
+	for _, mode := range requestedModes(input) {
+		profile := modeToProfile(mode)
+		if profile == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			h.logger.Debug().Msg("client disconnected, cancelling route stream")
+			return
+		}
+
+		routeOptions, modeWarnings := h.computeRoutesForMode(ctx, input, mode, profile, wantsFullGeometry(r))
+		h.attachTrainDisruptions(ctx, routeOptions, requestLocale(r))
+		h.assessExposureConfidence(ctx, routeOptions)
+		for _, warning := range modeWarnings {
+			if err := stream.Send(eventWarning, warning); err != nil {
+				return
+			}
+		}
+		for _, option := range routeOptions {
+			if err := stream.Send(eventOption, option); err != nil {
+				return
+			}
+		}
+		options = append(options, routeOptions...)
+	}
+
+	h.sortOptions(options, h.rankingFor(ctx, middleware.GetUserID(ctx), input))
+	if maxOptions := maxOptionsFor(input); len(options) > maxOptions {
+		options = options[:maxOptions]
+	}
+
+	ranked := make([]string, 0, len(options))
+	for _, option := range options {
+		ranked = append(ranked, option.ID)
+	}
+
+	var conditions *models.RouteWeather
+	if wantsWeather(input) {
+		var weatherWarnings []models.Warning
+		conditions, weatherWarnings = h.routeWeather(ctx, input, time.Now())
+		for _, warning := range weatherWarnings {
+			if err := stream.Send(eventWarning, warning); err != nil {
+				return
+			}
+		}
+		if conditions != nil {
+			if err := stream.Send(eventWeather, conditions); err != nil {
+				return
+			}
+		}
+	}
+
+	// Warnings were streamed as they occurred and are not stored
+	stored := h.storeRoutes(ctx, models.RouteComputeResponse{
+		GeneratedAt: models.Timestamp(time.Now()),
+		Options:     options,
+		Weather:     conditions,
+	})
+	_ = stream.Send(eventDone, models.RouteStreamDone{
+		RouteID:         stored.ID,
+		GeneratedAt:     stored.GeneratedAt,
+		RankedOptionIDs: ranked,
+	})
+}
+
+// acceptsEventStream reports whether the client asked for a Server-Sent Events response.
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// wantsFullGeometry reports whether the client asked for full-resolution route geometry.
+func wantsFullGeometry(r *http.Request) bool {
+	return r.URL.Query().Get("full") == "true"
+}
+
+// requestedModes returns the modes to compute, defaulting to BIKE and WALK.
+func requestedModes(input models.RouteComputeRequest) []models.Mode {
+	if len(input.Modes) == 0 {
+		return []models.Mode{models.ModeBike, models.ModeWalk}
+	}
+	return input.Modes
+}
+
+// maxOptionsFor returns the maximum number of options to return (default 5).
+func maxOptionsFor(input models.RouteComputeRequest) int {
+	if input.MaxOptions != nil && *input.MaxOptions > 0 {
+		return *input.MaxOptions
+	}
+	return 5
+}
+
+// requestPoints returns the origin, waypoints and destination of a request
+// that gives them explicitly, or nil if it refers to a commute.
+func requestPoints(input models.RouteComputeRequest) []routing.Coordinate {
+	if input.Origin == nil || input.Destination == nil {
+		return nil
+	}
+
+	points := make([]routing.Coordinate, 0, len(input.Waypoints)+2)
+	points = append(points, routing.Coordinate{Lat: input.Origin.Lat, Lon: input.Origin.Lon})
+	for _, p := range input.Waypoints {
+		points = append(points, routing.Coordinate{Lat: p.Lat, Lon: p.Lon})
+	}
+	return append(points, routing.Coordinate{Lat: input.Destination.Lat, Lon: input.Destination.Lon})
+}
+
+// checkSeparation returns an error if any leg of the route starts and ends
+// at the same place. A round trip through a waypoint is allowed.
+func (h *RouteHandler) checkSeparation(input models.RouteComputeRequest) error {
+	points := requestPoints(input)
+	for i := 1; i < len(points); i++ {
+		if err := h.routingService.CheckSeparation(points[i-1], points[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRouteDistance returns an error if the route is too long for every
+// requested mode. When only some modes are too long, those fail individually
+// and are reported as warnings.
+func (h *RouteHandler) checkRouteDistance(input models.RouteComputeRequest) error {
+	points := requestPoints(input)
+	if points == nil {
+		return nil
+	}
+
+	var tooLong error
+	for _, mode := range requestedModes(input) {
+		profile := modeToProfile(mode)
+		if profile == "" {
+			continue
+		}
+		err := h.routingService.CheckDistance(profile, points...)
+		if err == nil {
+			return nil
+		}
+		tooLong = err
+	}
+	return tooLong
+}
+
+// instructionOptions returns the instructions requested: included unless
+// turned off, in the language of the client's locale.
+func instructionOptions(input models.RouteComputeRequest) routing.InstructionOptions {
+	opts := routing.InstructionOptions{
+		IncludeInstructions: input.IncludeInstructions == nil || *input.IncludeInstructions,
+	}
+	if input.ClientContext != nil && input.ClientContext.Locale != nil {
+		opts.Language = *input.ClientContext.Locale
+	}
+	return opts
+}
+
+// validateWaypoints checks the waypoint count and each waypoint's coordinates.
+func validateWaypoints(waypoints []models.Point) []models.FieldError {
+	if len(waypoints) > routing.MaxWaypoints {
+		return []models.FieldError{{
+			Field:   "waypoints",
+			Message: fmt.Sprintf("must contain at most %d waypoints", routing.MaxWaypoints),
+		}}
+	}
+
+	var errs []models.FieldError
+	for i, p := range waypoints {
+		if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+			errs = append(errs, models.FieldError{
+				Field:   fmt.Sprintf("waypoints[%d]", i),
+				Message: "must have lat between -90 and 90 and lon between -180 and 180",
+			})
+		}
+	}
+	return errs
+}
+
 // computeRoutesForMode computes routes for a specific mode.
+// Requests with waypoints produce a single multi-leg option.
 func (h *RouteHandler) computeRoutesForMode(
 	ctx context.Context,
 	input models.RouteComputeRequest,
 	mode models.Mode,
 	profile routing.RouteProfile,
+	fullGeometry bool,
 ) ([]models.RouteOption, []models.Warning) {
-	options := make([]models.RouteOption, 0, 3) // Pre-allocate for typical route count
-	warnings := make([]models.Warning, 0, 1)
-
 	req := routing.DirectionsRequest{
 		Origin: routing.Coordinate{
 			Lat: input.Origin.Lat,
@@ -116,35 +481,32 @@ func (h *RouteHandler) computeRoutesForMode(
 			Lat: input.Destination.Lat,
 			Lon: input.Destination.Lon,
 		},
-		Profile:         profile,
-		MaxAlternatives: 3, // Request up to 3 alternatives per mode
+		Profile:            profile,
+		MaxAlternatives:    3, // Request up to 3 alternatives per mode
+		FullGeometry:       fullGeometry,
+		Constraints:        routeConstraints(input.ProfileOverride),
+		InstructionOptions: instructionOptions(input),
+	}
+	if departure, err := time.Parse(time.RFC3339, input.DepartureTime); err == nil {
+		req.DepartureTime = departure
+	}
+	if len(input.Waypoints) > 0 {
+		return h.computeMultiLegForMode(ctx, input, mode, req)
 	}
 
+	options := make([]models.RouteOption, 0, 3) // Pre-allocate for typical route count
+	warnings := make([]models.Warning, 0, 1)
+
 	resp, err := h.routingService.GetDirections(ctx, req)
 	if err != nil {
-		h.logger.Warn().
-			Err(err).
-			Str("mode", string(mode)).
-			Str("profile", string(profile)).
-			Msg("failed to get directions for mode")
-
-		// Add warning for failed mode
-		provider := h.routingService.ProviderName()
-		var routingErr *routing.Error
-		warningCode := "PROVIDER_ERROR"
-		warningMsg := "routing provider temporarily unavailable for " + string(mode)
-
-		if errors.As(err, &routingErr) {
-			warningCode = routingErr.Code
-			warningMsg = routingErr.Message
-		}
-
-		warnings = append(warnings, models.Warning{
-			Code:     warningCode,
-			Message:  warningMsg,
-			Provider: &provider,
-		})
-
+		warnings = append(warnings, h.modeFailureWarning(err, mode, profile))
+		stops := []models.LegPoint{
+			{Name: "Origin", Point: *input.Origin},
+			{Name: "Destination", Point: *input.Destination},
+		}
+		if option, ok := h.estimatedOption(err, input.Objective, mode, profile, stops); ok {
+			options = append(options, option)
+		}
 		return options, warnings
 	}
 
@@ -157,6 +519,468 @@ func (h *RouteHandler) computeRoutesForMode(
 	return options, warnings
 }
 
+// computeMultiLegForMode computes a route from the origin through each
+// waypoint to the destination, as one option with a leg per segment. Every
+// leg is requested with req's profile and options. The option's exposure is
+// integrated over the legs, each scored from air quality along its geometry.
+func (h *RouteHandler) computeMultiLegForMode(
+	ctx context.Context,
+	input models.RouteComputeRequest,
+	mode models.Mode,
+	req routing.DirectionsRequest,
+) ([]models.RouteOption, []models.Warning) {
+	profile := req.Profile
+	stops := make([]models.LegPoint, 0, len(input.Waypoints)+2)
+	stops = append(stops, models.LegPoint{Name: "Origin", Point: *input.Origin})
+	for i, p := range input.Waypoints {
+		stops = append(stops, models.LegPoint{Name: fmt.Sprintf("Waypoint %d", i+1), Point: p})
+	}
+	stops = append(stops, models.LegPoint{Name: "Destination", Point: *input.Destination})
+
+	points := make([]routing.Coordinate, len(stops))
+	for i, stop := range stops {
+		points[i] = routing.Coordinate{Lat: stop.Point.Lat, Lon: stop.Point.Lon}
+	}
+
+	route, err := h.routingService.GetDirectionsVia(ctx, points, req)
+	if err != nil {
+		warnings := []models.Warning{h.modeFailureWarning(err, mode, profile)}
+		if option, ok := h.estimatedOption(err, input.Objective, mode, profile, stops); ok {
+			return []models.RouteOption{option}, warnings
+		}
+		return nil, warnings
+	}
+
+	legs := make([]models.RouteLeg, len(route.Legs))
+	for i, leg := range route.Legs {
+		legs[i] = h.routeLeg(leg, mode, stops[i], stops[i+1])
+	}
+
+	summary := buildRouteSummary(mode, routing.Route{
+		DistanceMeters:  route.DistanceMeters,
+		DurationSeconds: route.DurationSeconds,
+	}, 0)
+	summary.Highlights = append(summary.Highlights, fmt.Sprintf("%d stops on the way", len(input.Waypoints)))
+
+	return []models.RouteOption{{
+		ID:              "opt_" + uuid.New().String()[:12],
+		Objective:       input.Objective,
+		DurationSeconds: route.DurationSeconds,
+		DistanceMeters:  intPtr(route.DistanceMeters),
+		ExposureScore:   integrateLegExposure(legs, h.legExposures(ctx, legs)),
+		Confidence:      models.ConfidenceMedium, // Medium until we have AQ data
+		Legs:            legs,
+		Summary:         summary,
+	}}, nil
+}
+
+// modeFailureWarning logs a failed directions request and converts it to a
+// warning for the response.
+func (h *RouteHandler) modeFailureWarning(err error, mode models.Mode, profile routing.RouteProfile) models.Warning {
+	h.logger.Warn().
+		Err(err).
+		Str("mode", string(mode)).
+		Str("profile", string(profile)).
+		Msg("failed to get directions for mode")
+
+	provider := h.routingService.ProviderName()
+	var routingErr *routing.Error
+	warningCode := "PROVIDER_ERROR"
+	warningMsg := "routing provider temporarily unavailable for " + string(mode)
+
+	if errors.As(err, &routingErr) {
+		warningCode = routingErr.Code
+		warningMsg = routingErr.Message
+	}
+
+	return models.Warning{
+		Code:     warningCode,
+		Message:  warningMsg,
+		Provider: &provider,
+	}
+}
+
+// estimatedProvider is the leg provider of estimated routes.
+const estimatedProvider = "estimate"
+
+// estimatedOption returns a straight-line estimate of the route through
+// stops, if estimates are enabled and err shows the provider could not be
+// reached. Each leg gets its own estimate; none has geometry.
+func (h *RouteHandler) estimatedOption(
+	err error,
+	objective models.Objective,
+	mode models.Mode,
+	profile routing.RouteProfile,
+	stops []models.LegPoint,
+) (models.RouteOption, bool) {
+	var routingErr *routing.Error
+	if !h.estimateFallback || !errors.As(err, &routingErr) || !routingErr.IsRetryable() {
+		return models.RouteOption{}, false
+	}
+
+	var total routing.Route
+	legs := make([]models.RouteLeg, 0, len(stops)-1)
+	for i := 1; i < len(stops); i++ {
+		estimate, ok := routing.EstimateRoute(profile,
+			routing.Coordinate{Lat: stops[i-1].Point.Lat, Lon: stops[i-1].Point.Lon},
+			routing.Coordinate{Lat: stops[i].Point.Lat, Lon: stops[i].Point.Lon},
+		)
+		if !ok {
+			return models.RouteOption{}, false
+		}
+		legs = append(legs, models.RouteLeg{
+			Mode:            mode,
+			Provider:        estimatedProvider,
+			Start:           stops[i-1],
+			End:             stops[i],
+			DurationSeconds: estimate.DurationSeconds,
+			DistanceMeters:  intPtr(estimate.DistanceMeters),
+		})
+		total.DistanceMeters += estimate.DistanceMeters
+		total.DurationSeconds += estimate.DurationSeconds
+	}
+
+	summary := buildRouteSummary(mode, total, 0)
+	summary.Title = strings.Replace(summary.Title, "Fastest", "Estimated", 1)
+	summary.Highlights = append(summary.Highlights, "Straight-line estimate while routing is unavailable")
+
+	return models.RouteOption{
+		ID:              "opt_" + uuid.New().String()[:12],
+		Objective:       objective,
+		DurationSeconds: total.DurationSeconds,
+		DistanceMeters:  intPtr(total.DistanceMeters),
+		ExposureScore:   placeholderExposureScore(0),
+		Confidence:      models.ConfidenceLow,
+		Estimated:       true,
+		Legs:            legs,
+		Summary:         summary,
+	}, true
+}
+
+// integrateLegExposure combines per-leg exposure scores into a route score,
+// weighting each leg by the time spent on it so long legs dominate short ones.
+// If no leg has a duration, the legs are weighted equally.
+func integrateLegExposure(legs []models.RouteLeg, exposures []float64) float64 {
+	var weighted, sum, total float64
+	for i, leg := range legs {
+		weighted += exposures[i] * float64(leg.DurationSeconds)
+		sum += exposures[i]
+		total += float64(leg.DurationSeconds)
+	}
+	switch {
+	case total > 0:
+		return weighted / total
+	case len(legs) > 0:
+		return sum / float64(len(legs))
+	default:
+		return 0
+	}
+}
+
+// legExposures scores each leg from current air quality sampled along its
+// geometry: the mean of NO2, PM2.5 and O3 as a percentage of their EAQI
+// "good" limits. Legs with no estimate, or every leg if air quality is
+// unavailable, get the placeholder score.
+func (h *RouteHandler) legExposures(ctx context.Context, legs []models.RouteLeg) []float64 {
+	exposures := make([]float64, len(legs))
+	for i := range exposures {
+		exposures[i] = placeholderExposureScore(0)
+	}
+	if h.airQualityService == nil {
+		return exposures
+	}
+
+	perLeg := h.legSamples(legs)
+	var points []struct{ Lat, Lon float64 }
+	for _, legPoints := range perLeg {
+		points = append(points, legPoints...)
+	}
+	samples, err := h.airQualityService.InterpolatePoints(ctx, points)
+	if err != nil || len(samples) != len(points) {
+		telemetry.Logger(ctx, h.logger).Warn().Err(err).Msg("failed to sample air quality along route legs")
+		return exposures
+	}
+
+	offset := 0
+	for i, legPoints := range perLeg {
+		if score, ok := exposureScore(samples[offset : offset+len(legPoints)]); ok {
+			exposures[i] = score
+		}
+		offset += len(legPoints)
+	}
+	return exposures
+}
+
+// exposureScore returns the mean of the samples' scored pollutants as a
+// percentage of their EAQI "good" limits, or false if no sample has any.
+func exposureScore(samples []*airquality.InterpolatedPoint) (float64, bool) {
+	var total float64
+	var count int
+	for _, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		for pollutant, limit := range exposureLimits {
+			if value, ok := sample.Values[pollutant]; ok {
+				total += value.Value / limit
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / float64(count) * 100, true
+}
+
+// attachTrainDisruptions attaches the disruptions on each train leg to the
+// leg, and lowers the option's confidence when a leg is badly disrupted.
+// Stations are identified by code when the leg has one, else by name.
+func (h *RouteHandler) attachTrainDisruptions(ctx context.Context, options []models.RouteOption, locale string) {
+	if h.transitService == nil {
+		return
+	}
+
+	for i := range options {
+		option := &options[i]
+		for j := range option.Legs {
+			leg := &option.Legs[j]
+			if leg.Mode != models.ModeTrain || leg.Transit == nil {
+				continue
+			}
+
+			origin := stationOrName(leg.Transit.OriginStation, leg.Start.Name)
+			destination := stationOrName(leg.Transit.DestinationStation, leg.End.Name)
+			disruptions, err := h.transitService.GetDisruptionsForLeg(ctx, origin, destination, locale)
+			if err != nil {
+				telemetry.Logger(ctx, h.logger).Warn().Err(err).
+					Str("origin", origin).
+					Str("destination", destination).
+					Msg("failed to get disruptions for train leg")
+				continue
+			}
+
+			resp := toRouteDisruptionsResponse(disruptions)
+			leg.Transit.Disruptions = &resp
+			option.Confidence = disruptedConfidence(option.Confidence, disruptions.OverallImpact)
+		}
+	}
+}
+
+// stationOrName returns the station code if set, else the station name.
+func stationOrName(code *string, name string) string {
+	if code != nil && *code != "" {
+		return *code
+	}
+	return name
+}
+
+// disruptedConfidence lowers confidence for a leg with the given disruption
+// impact: no service means LOW, and major delays lower it by one level.
+func disruptedConfidence(confidence models.Confidence, impact transit.Impact) models.Confidence {
+	switch impact {
+	case transit.ImpactSevere:
+		return models.ConfidenceLow
+	case transit.ImpactMajor:
+		if confidence == models.ConfidenceHigh {
+			return models.ConfidenceMedium
+		}
+		return models.ConfidenceLow
+	default:
+		return confidence
+	}
+}
+
+// DefaultMaxLowConfidenceFraction is the fraction of low confidence samples
+// above which an option's exposure confidence is LOW.
+const DefaultMaxLowConfidenceFraction = 0.5
+
+// Exposure sampling defaults.
+const (
+	// DefaultMaxExposureSamples caps the points sampled along a route.
+	DefaultMaxExposureSamples = 200
+
+	// DefaultMinSampleIntervalMeters is the closest spacing of the points
+	// sampled along a route.
+	DefaultMinSampleIntervalMeters = 50.0
+)
+
+// ExposureSamplingConfig sets how densely a route is sampled for air quality.
+// Points are spread evenly over the whole route, MinIntervalMeters apart
+// unless that would exceed MaxSamples, in which case the interval grows with
+// the route's length. Short routes thus keep street-level resolution, while
+// the interpolation cost of long routes is bounded. Every interpolation costs
+// the same, so a lower MaxSamples is proportionally cheaper but averages over
+// longer stretches on long routes. Zero values use the defaults.
+type ExposureSamplingConfig struct {
+	// MaxSamples caps the points per route, plus the end points of each
+	// leg (default: 200).
+	MaxSamples int
+
+	// MinIntervalMeters is the closest spacing of points (default: 50).
+	MinIntervalMeters float64
+}
+
+// intervalFor returns the sample spacing in meters for a route of the given
+// length.
+func (c ExposureSamplingConfig) intervalFor(lengthMeters float64) float64 {
+	maxSamples := c.MaxSamples
+	if maxSamples <= 0 {
+		maxSamples = DefaultMaxExposureSamples
+	}
+	minInterval := c.MinIntervalMeters
+	if minInterval <= 0 {
+		minInterval = DefaultMinSampleIntervalMeters
+	}
+	if maxSamples < 2 {
+		return math.Max(minInterval, lengthMeters)
+	}
+	return math.Max(minInterval, lengthMeters/float64(maxSamples-1))
+}
+
+// ExposureConfidenceConfig sets when a route option's exposure estimate is
+// considered unreliable. Zero values use the defaults.
+type ExposureConfidenceConfig struct {
+	// MinSampleConfidence is the lowest confidence a sampled point may have
+	// without counting as low confidence (default: MEDIUM).
+	MinSampleConfidence models.Confidence
+
+	// MaxLowFraction is the fraction (0-1) of low confidence samples above
+	// which the option is flagged LOW (default: 0.5).
+	MaxLowFraction float64
+
+	// DemoteLow ranks options flagged LOW after all others when the
+	// objective weighs exposure, i.e. any objective but FASTEST.
+	DemoteLow bool
+}
+
+// confidenceRank orders confidence levels from LOW (0) to HIGH (2).
+func confidenceRank(c models.Confidence) int {
+	switch c {
+	case models.ConfidenceHigh:
+		return 2
+	case models.ConfidenceMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// assessExposureConfidence samples each option's legs against the current air
+// quality snapshot and sets its exposure confidence and the fraction of
+// samples below MinSampleConfidence. Samples with no estimate at all count as
+// low confidence. Estimated options follow a straight line rather than real
+// streets, so they are not sampled and get low exposure confidence. Options
+// are left unchanged if the air quality service is not configured or has no
+// data.
+func (h *RouteHandler) assessExposureConfidence(ctx context.Context, options []models.RouteOption) {
+	if h.airQualityService == nil {
+		return
+	}
+
+	cfg := h.exposureConfidence
+	if cfg.MinSampleConfidence == "" {
+		cfg.MinSampleConfidence = models.ConfidenceMedium
+	}
+	if cfg.MaxLowFraction <= 0 {
+		cfg.MaxLowFraction = DefaultMaxLowConfidenceFraction
+	}
+
+	for i := range options {
+		option := &options[i]
+		if option.Estimated {
+			option.ExposureConfidence = models.ConfidenceLow
+			continue
+		}
+		samples, err := h.airQualityService.InterpolatePoints(ctx, h.routeSamples(option.Legs))
+		if err != nil {
+			telemetry.Logger(ctx, h.logger).Warn().Err(err).Msg("failed to assess exposure confidence")
+			return
+		}
+		if len(samples) == 0 {
+			continue
+		}
+
+		low, high := 0, 0
+		for _, sample := range samples {
+			if sample == nil {
+				low++
+				continue
+			}
+			confidence := models.Confidence(sample.Confidence())
+			if confidenceRank(confidence) < confidenceRank(cfg.MinSampleConfidence) {
+				low++
+			}
+			if confidence == models.ConfidenceHigh {
+				high++
+			}
+		}
+
+		fraction := float64(low) / float64(len(samples))
+		option.LowConfidenceSampleFraction = &fraction
+		switch {
+		case fraction > cfg.MaxLowFraction:
+			option.ExposureConfidence = models.ConfidenceLow
+		case high == len(samples):
+			option.ExposureConfidence = models.ConfidenceHigh
+		default:
+			option.ExposureConfidence = models.ConfidenceMedium
+		}
+	}
+}
+
+// routeSamples returns points along each leg's geometry, spaced for the
+// route's total length by the sampling config. Legs without geometry
+// contribute their start and end points.
+func (h *RouteHandler) routeSamples(legs []models.RouteLeg) []struct{ Lat, Lon float64 } {
+	var points []struct{ Lat, Lon float64 }
+	for _, legPoints := range h.legSamples(legs) {
+		points = append(points, legPoints...)
+	}
+	return points
+}
+
+// legSamples returns the routeSamples points of each leg separately.
+func (h *RouteHandler) legSamples(legs []models.RouteLeg) [][]struct{ Lat, Lon float64 } {
+	geometries := make([][]polyline.Coordinate, len(legs))
+	length := 0.0
+	for i, leg := range legs {
+		if leg.GeometryPolyline != nil {
+			geometries[i] = polyline.Decode(*leg.GeometryPolyline)
+			length += polyline.Length(geometries[i])
+		}
+	}
+
+	interval := h.exposureSampling.intervalFor(length)
+	points := make([][]struct{ Lat, Lon float64 }, len(legs))
+	for i, leg := range legs {
+		coords := polyline.Resample(geometries[i], interval)
+		if len(coords) == 0 {
+			coords = []polyline.Coordinate{
+				{Lat: leg.Start.Point.Lat, Lon: leg.Start.Point.Lon},
+				{Lat: leg.End.Point.Lat, Lon: leg.End.Point.Lon},
+			}
+		}
+		for _, c := range coords {
+			points[i] = append(points[i], struct{ Lat, Lon float64 }{c.Lat, c.Lon})
+		}
+	}
+	return points
+}
+
+// requestLocale returns the supported locale best matching the request's
+// Accept-Language header, else English.
+func requestLocale(r *http.Request) string {
+	return transit.ResolveLocale(preferredLocale(r.Header.Get("Accept-Language")))
+}
+
+// placeholderExposureScore returns a stand-in exposure score for the
+// provider's index-th route alternative.
+// TODO: Calculate actual exposure score based on air quality data along route
+func placeholderExposureScore(index int) float64 {
+	return 30.0 + float64(index)*5.0
+}
+
 // routeToOption converts a routing.Route to a models.RouteOption.
 func (h *RouteHandler) routeToOption(
 	route routing.Route,
@@ -168,21 +992,38 @@ func (h *RouteHandler) routeToOption(
 	// Generate unique ID
 	optionID := "opt_" + uuid.New().String()[:12]
 
-	// Create the route leg
+	leg := h.routeLeg(route, mode,
+		models.LegPoint{Name: "Origin", Point: origin},
+		models.LegPoint{Name: "Destination", Point: destination},
+	)
+
+	// Build summary and highlights
+	summary := buildRouteSummary(mode, route, index)
+
+	return models.RouteOption{
+		ID:              optionID,
+		Objective:       objective,
+		DurationSeconds: route.DurationSeconds,
+		DistanceMeters:  intPtr(route.DistanceMeters),
+		ExposureScore:   placeholderExposureScore(index),
+		Confidence:      models.ConfidenceMedium, // Medium until we have AQ data
+		Legs:            []models.RouteLeg{leg},
+		Summary:         summary,
+	}
+}
+
+// routeLeg converts a routing.Route to a models.RouteLeg between two points.
+func (h *RouteHandler) routeLeg(route routing.Route, mode models.Mode, start, end models.LegPoint) models.RouteLeg {
 	leg := models.RouteLeg{
-		Mode:     mode,
-		Provider: h.routingService.ProviderName(),
-		Start: models.LegPoint{
-			Name:  "Origin",
-			Point: origin,
-		},
-		End: models.LegPoint{
-			Name:  "Destination",
-			Point: destination,
-		},
+		Mode:             mode,
+		Provider:         h.routingService.ProviderName(),
+		Start:            start,
+		End:              end,
 		DurationSeconds:  route.DurationSeconds,
 		DistanceMeters:   intPtr(route.DistanceMeters),
+		AscentMeters:     intPtr(route.AscentMeters),
 		GeometryPolyline: strPtr(route.GeometryPolyline),
+		RoadNames:        route.RoadNames,
 	}
 
 	// Add instructions if available
@@ -193,42 +1034,197 @@ func (h *RouteHandler) routeToOption(
 		})
 	}
 
-	// Build summary and highlights
-	summary := buildRouteSummary(mode, route, index)
+	return leg
+}
 
-	// TODO: Calculate actual exposure score based on air quality data along route
-	// For now, use a placeholder score based on route index
-	exposureScore := 30.0 + float64(index)*5.0
+// effortPenaltyPerMeterAscent is the balanced-score penalty per meter climbed at
+// an effort weight of 1.0 (e.g., a 50m climb adds 10 points, comparable to 10 minutes).
+const effortPenaltyPerMeterAscent = 0.2
 
-	return models.RouteOption{
-		ID:              optionID,
-		Objective:       objective,
-		DurationSeconds: route.DurationSeconds,
-		DistanceMeters:  intPtr(route.DistanceMeters),
-		ExposureScore:   exposureScore,
-		Confidence:      models.ConfidenceMedium, // Medium until we have AQ data
-		Legs:            []models.RouteLeg{leg},
-		Summary:         summary,
+// defaultBlend weights time and exposure equally for the BALANCED objective.
+const defaultBlend = 0.5
+
+// routeRanking is how route options are ordered. Each objective is a preset
+// blend: FASTEST ranks by time only, LOWEST_EXPOSURE by exposure only, and
+// BALANCED by the request's blend.
+type routeRanking struct {
+	blend        float64 // 0 = exposure only, 1 = time only
+	effortWeight float64 // climbing penalty added to time, BALANCED only
+
+	// demoteLowConfidence ranks options with LOW exposure confidence after
+	// all others.
+	demoteLowConfidence bool
+}
+
+// rankingFor returns the ranking for a route request, demoting options with
+// unreliable exposure estimates if configured and the ranking weighs exposure.
+// Balanced requests from signed-in users without an effort weight override
+// use the effort weight stored in their profile.
+func (h *RouteHandler) rankingFor(ctx context.Context, userID string, input models.RouteComputeRequest) routeRanking {
+	ranking := rankingFor(input)
+	if input.Objective == models.ObjectiveBalanced && !hasEffortWeight(input.ProfileOverride) {
+		ranking.effortWeight = h.storedEffortWeight(ctx, userID)
 	}
+	ranking.demoteLowConfidence = h.exposureConfidence.DemoteLow && ranking.blend < 1
+	return ranking
 }
 
-// sortOptionsByObjective sorts route options based on the requested objective.
-func (h *RouteHandler) sortOptionsByObjective(options []models.RouteOption, objective models.Objective) {
-	sort.Slice(options, func(i, j int) bool {
-		switch objective {
-		case models.ObjectiveFastest:
-			return options[i].DurationSeconds < options[j].DurationSeconds
-		case models.ObjectiveLowestExposure:
-			return options[i].ExposureScore < options[j].ExposureScore
-		case models.ObjectiveBalanced:
-			// Balanced: weighted combination of duration and exposure
-			scoreI := float64(options[i].DurationSeconds)/60.0 + options[i].ExposureScore
-			scoreJ := float64(options[j].DurationSeconds)/60.0 + options[j].ExposureScore
-			return scoreI < scoreJ
-		default:
-			return options[i].DurationSeconds < options[j].DurationSeconds
+// rankingFor returns the ranking for a route request.
+func rankingFor(input models.RouteComputeRequest) routeRanking {
+	switch input.Objective {
+	case models.ObjectiveLowestExposure:
+		return routeRanking{blend: 0}
+	case models.ObjectiveBalanced:
+		ranking := routeRanking{blend: defaultBlend, effortWeight: effortWeight(input.ProfileOverride)}
+		if input.Blend != nil {
+			ranking.blend = *input.Blend
+		}
+		return ranking
+	default:
+		return routeRanking{blend: 1}
+	}
+}
+
+// validateBlend checks that a blend is between 0 and 1.
+func validateBlend(blend *float64) []models.FieldError {
+	if blend == nil || (*blend >= 0 && *blend <= 1) {
+		return nil
+	}
+	return []models.FieldError{{
+		Field:   "blend",
+		Code:    models.FieldCodeOutOfRange,
+		Message: "must be between 0 and 1",
+	}}
+}
+
+// sortOptions orders route options by ranking. Time (including any climbing
+// penalty) and exposure are normalized to 0-1 across the options, so the
+// blend weighs them on the same scale. Options with equal scores are ordered
+// by duration, then by exposure, then by their original order. If the ranking
+// demotes low confidence options, they follow all others in the same order.
+func (h *RouteHandler) sortOptions(options []models.RouteOption, ranking routeRanking) {
+	if len(options) < 2 {
+		return
+	}
+
+	times := make([]float64, len(options))
+	exposures := make([]float64, len(options))
+	for i, option := range options {
+		times[i] = effortAdjustedMinutes(option, ranking.effortWeight)
+		exposures[i] = option.ExposureScore
+	}
+	normalize(times)
+	normalize(exposures)
+
+	type scored struct {
+		option models.RouteOption
+		score  float64
+	}
+	ranked := make([]scored, len(options))
+	for i, option := range options {
+		ranked[i] = scored{option, ranking.blend*times[i] + (1-ranking.blend)*exposures[i]}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if ranking.demoteLowConfidence {
+			aLow := a.option.ExposureConfidence == models.ConfidenceLow
+			bLow := b.option.ExposureConfidence == models.ConfidenceLow
+			if aLow != bLow {
+				return bLow
+			}
 		}
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		if a.option.DurationSeconds != b.option.DurationSeconds {
+			return a.option.DurationSeconds < b.option.DurationSeconds
+		}
+		return a.option.ExposureScore < b.option.ExposureScore
 	})
+	for i := range ranked {
+		options[i] = ranked[i].option
+	}
+}
+
+// effortAdjustedMinutes returns an option's duration in minutes plus its
+// climbing penalty at effortWeight (0-1).
+func effortAdjustedMinutes(option models.RouteOption, effortWeight float64) float64 {
+	minutes := float64(option.DurationSeconds) / 60.0
+
+	if effortWeight > 0 {
+		ascent := 0
+		for _, leg := range option.Legs {
+			if leg.AscentMeters != nil {
+				ascent += *leg.AscentMeters
+			}
+		}
+		minutes += effortWeight * effortPenaltyPerMeterAscent * float64(ascent)
+	}
+
+	return minutes
+}
+
+// normalize rescales values in place to 0 (lowest) through 1 (highest). If all
+// values are equal they all become 0.
+func normalize(values []float64) {
+	lowest, highest := values[0], values[0]
+	for _, v := range values[1:] {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+	}
+	for i, v := range values {
+		if highest > lowest {
+			values[i] = (v - lowest) / (highest - lowest)
+		} else {
+			values[i] = 0
+		}
+	}
+}
+
+// effortWeight returns the effort weight from a profile override, or 0 if unset.
+func effortWeight(profile *models.ProfileInput) float64 {
+	if !hasEffortWeight(profile) {
+		return 0
+	}
+	return *profile.Constraints.EffortWeight
+}
+
+// hasEffortWeight reports whether a profile override sets an effort weight.
+func hasEffortWeight(profile *models.ProfileInput) bool {
+	return profile != nil && profile.Constraints.EffortWeight != nil
+}
+
+// storedEffortWeight returns the effort weight from the user's stored
+// profile, or 0 if the user is anonymous, has none set, or the profile
+// cannot be loaded.
+func (h *RouteHandler) storedEffortWeight(ctx context.Context, userID string) float64 {
+	if h.userService == nil || userID == "" {
+		return 0
+	}
+	profile, err := h.userService.GetProfile(ctx, userID)
+	if err != nil {
+		telemetry.Logger(ctx, h.logger).Warn().Err(err).Str("user_id", userID).Msg("failed to load profile for route ranking")
+		return 0
+	}
+	if profile.Constraints.EffortWeight == nil {
+		return 0
+	}
+	return *profile.Constraints.EffortWeight
+}
+
+// routeConstraints returns the features the profile override asks routes
+// to avoid.
+func routeConstraints(profile *models.ProfileInput) routing.RouteConstraints {
+	if profile == nil {
+		return routing.RouteConstraints{}
+	}
+	return routing.RouteConstraints{
+		AvoidMajorRoads:      profile.Constraints.AvoidMajorRoads,
+		AvoidFerries:         profile.Constraints.AvoidFerries,
+		AvoidUnpaved:         profile.Constraints.AvoidUnpaved,
+		AvoidCongestionZones: profile.Constraints.AvoidCongestionZones,
+	}
 }
 
 // modeToProfile maps API modes to ORS routing profiles.
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
+)
+
+// acceptsGeoJSON reports whether the client asked for a GeoJSON response.
+func acceptsGeoJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), models.MediaTypeGeoJSON)
+}
+
+// routeFeatureCollection converts a route computation to GeoJSON, keeping the
+// option order.
+func routeFeatureCollection(resp models.RouteComputeResponse) models.RouteFeatureCollection {
+	features := make([]models.RouteFeature, 0, len(resp.Options))
+	for _, option := range resp.Options {
+		features = append(features, routeFeature(option))
+	}
+	return models.RouteFeatureCollection{
+		Type:        "FeatureCollection",
+		Features:    features,
+		GeneratedAt: resp.GeneratedAt,
+		Warnings:    resp.Warnings,
+	}
+}
+
+// routeFeature converts a route option to a GeoJSON feature whose geometry
+// joins the geometry of all its legs.
+func routeFeature(option models.RouteOption) models.RouteFeature {
+	modes := make([]models.Mode, 0, len(option.Legs))
+	for _, leg := range option.Legs {
+		if len(modes) == 0 || modes[len(modes)-1] != leg.Mode {
+			modes = append(modes, leg.Mode)
+		}
+	}
+
+	return models.RouteFeature{
+		Type: "Feature",
+		ID:   option.ID,
+		Geometry: models.LineString{
+			Type:        "LineString",
+			Coordinates: legCoordinates(option.Legs),
+		},
+		Properties: models.RouteFeatureProperties{
+			Objective:       option.Objective,
+			Modes:           modes,
+			DistanceMeters:  option.DistanceMeters,
+			DurationSeconds: option.DurationSeconds,
+			ExposureScore:   option.ExposureScore,
+			Confidence:      option.Confidence,
+			Title:           option.Summary.Title,
+		},
+	}
+}
+
+// legCoordinates decodes the legs' polylines into GeoJSON [lon, lat]
+// positions. Legs without geometry contribute their start and end points,
+// and a point shared by consecutive legs appears once.
+func legCoordinates(legs []models.RouteLeg) [][2]float64 {
+	var coords [][2]float64
+	appendPoint := func(lat, lon float64) {
+		p := [2]float64{lon, lat}
+		if n := len(coords); n > 0 && coords[n-1] == p {
+			return
+		}
+		coords = append(coords, p)
+	}
+
+	for _, leg := range legs {
+		var points []polyline.Coordinate
+		if leg.GeometryPolyline != nil {
+			points = polyline.Decode(*leg.GeometryPolyline)
+		}
+		if len(points) == 0 {
+			points = []polyline.Coordinate{
+				{Lat: leg.Start.Point.Lat, Lon: leg.Start.Point.Lon},
+				{Lat: leg.End.Point.Lat, Lon: leg.End.Point.Lon},
+			}
+		}
+		for _, p := range points {
+			appendPoint(p.Lat, p.Lon)
+		}
+	}
+
+	// A LineString needs at least two positions
+	if len(coords) == 1 {
+		coords = append(coords, coords[0])
+	}
+	if coords == nil {
+		coords = [][2]float64{}
+	}
+	return coords
+}
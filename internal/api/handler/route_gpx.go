@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
+)
+
+// MediaTypeGPX is the media type of GPX route exports.
+const MediaTypeGPX = "application/gpx+xml"
+
+// GPX 1.1 document, limited to the elements route exports use. Field order
+// follows the element order the GPX schema requires.
+type gpxDocument struct {
+	XMLName   xml.Name    `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version   string      `xml:"version,attr"`
+	Creator   string      `xml:"creator,attr"`
+	Metadata  gpxMetadata `xml:"metadata"`
+	Waypoints []gpxPoint  `xml:"wpt"`
+	Tracks    []gpxTrack  `xml:"trk"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Time string `xml:"time"`
+}
+
+type gpxPoint struct {
+	Lat  gpxDegrees `xml:"lat,attr"`
+	Lon  gpxDegrees `xml:"lon,attr"`
+	Name string     `xml:"name,omitempty"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Type     string       `xml:"type,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// gpxDegrees is a latitude or longitude. GPX declares them as xsd:decimal,
+// which does not allow the exponent notation encoding/xml uses for small
+// floats.
+type gpxDegrees float64
+
+// MarshalXMLAttr formats the value in plain decimal notation.
+func (d gpxDegrees) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: strconv.FormatFloat(float64(d), 'f', 6, 64)}, nil
+}
+
+// ExportGPX handles GET /v1/routes/{routeId}/export.gpx - export an option
+// of a stored route as GPX, with a track segment per leg and a waypoint per
+// turn instruction. The optional optionId query parameter picks the option;
+// by default the top-ranked option is exported.
+func (h *RouteHandler) ExportGPX(w http.ResponseWriter, r *http.Request) {
+	route, ok := h.storedRoute(w, r)
+	if !ok {
+		return
+	}
+	option, ok := route.Option(r.URL.Query().Get("optionId"))
+	if !ok {
+		response.NotFound(w, r, models.ErrorCodeRouteNotFound, "route has no such option")
+		return
+	}
+
+	data, err := xml.MarshalIndent(routeGPX(option, time.Now()), "", "  ")
+	if err != nil {
+		h.logger.Error().Err(err).Str("route_id", route.ID).Msg("failed to encode GPX")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to encode route")
+		return
+	}
+
+	w.Header().Set("Content-Type", MediaTypeGPX)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+option.ID+`.gpx"`)
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+}
+
+// routeGPX converts a route option to a GPX document.
+func routeGPX(option models.RouteOption, now time.Time) gpxDocument {
+	track := gpxTrack{Name: option.Summary.Title, Type: gpxTrackType(option.Legs)}
+	var waypoints []gpxPoint
+
+	for _, leg := range option.Legs {
+		var coords []polyline.Coordinate
+		if leg.GeometryPolyline != nil {
+			coords = polyline.Decode(*leg.GeometryPolyline)
+		}
+		if len(coords) == 0 {
+			coords = []polyline.Coordinate{
+				{Lat: leg.Start.Point.Lat, Lon: leg.Start.Point.Lon},
+				{Lat: leg.End.Point.Lat, Lon: leg.End.Point.Lon},
+			}
+		}
+
+		segment := gpxSegment{Points: make([]gpxPoint, 0, len(coords))}
+		for _, c := range coords {
+			segment.Points = append(segment.Points, gpxPoint{Lat: gpxDegrees(c.Lat), Lon: gpxDegrees(c.Lon)})
+		}
+		track.Segments = append(track.Segments, segment)
+
+		// Each instruction applies where the previous ones' distance ends
+		var traveled float64
+		for _, inst := range leg.Instructions {
+			at := polyline.PointAt(coords, traveled)
+			waypoints = append(waypoints, gpxPoint{Lat: gpxDegrees(at.Lat), Lon: gpxDegrees(at.Lon), Name: inst.Text})
+			traveled += float64(inst.DistanceMeters)
+		}
+	}
+
+	doc := gpxDocument{
+		Version: "1.1",
+		Creator: "BreatheRoute",
+		Metadata: gpxMetadata{
+			Name: option.Summary.Title,
+			Time: now.UTC().Format(time.RFC3339),
+		},
+		Waypoints: waypoints,
+	}
+	if len(track.Segments) > 0 {
+		doc.Tracks = []gpxTrack{track}
+	}
+	return doc
+}
+
+// gpxTrackType names the track type after the route's first mode (e.g.
+// "bike"), which navigation devices use to pick a routing profile.
+func gpxTrackType(legs []models.RouteLeg) string {
+	if len(legs) == 0 {
+		return ""
+	}
+	return strings.ToLower(string(legs[0].Mode))
+}
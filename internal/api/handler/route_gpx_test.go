@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/routestore"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
+)
+
+// gpxNamespace is the GPX 1.1 target namespace.
+const gpxNamespace = "http://www.topografix.com/GPX/1/1"
+
+// xmlNode is a generic XML element, so documents are checked as parsed
+// rather than through the types that produced them.
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []xmlNode  `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+func (n xmlNode) attr(name string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// gpxChildOrder is the element sequence of each GPX 1.1 complex type used by
+// route exports, from the GPX 1.1 schema.
+var gpxChildOrder = map[string][]string{
+	"gpx":      {"metadata", "wpt", "rte", "trk", "extensions"},
+	"metadata": {"name", "desc", "author", "copyright", "link", "time", "keywords", "bounds", "extensions"},
+	"wpt": {"ele", "time", "magvar", "geoidheight", "name", "cmt", "desc", "src", "link", "sym", "type",
+		"fix", "sat", "hdop", "vdop", "pdop", "ageofdgpsdata", "dgpsid", "extensions"},
+	"trk":    {"name", "cmt", "desc", "src", "link", "number", "type", "extensions", "trkseg"},
+	"trkseg": {"trkpt", "extensions"},
+}
+
+// validateGPX checks data against the GPX 1.1 schema rules route exports can
+// break: the namespace and version, element order, required attributes and
+// the latitude, longitude and dateTime value spaces. There is no XSD
+// validator in the standard library, so the rules are encoded here.
+func validateGPX(t *testing.T, data []byte) {
+	t.Helper()
+
+	var root xmlNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		t.Fatalf("GPX is not well-formed XML: %v", err)
+	}
+	if root.XMLName.Space != gpxNamespace || root.XMLName.Local != "gpx" {
+		t.Fatalf("expected root {%s}gpx, got {%s}%s", gpxNamespace, root.XMLName.Space, root.XMLName.Local)
+	}
+	if v, _ := root.attr("version"); v != "1.1" {
+		t.Errorf("expected version 1.1, got %q", v)
+	}
+	if c, ok := root.attr("creator"); !ok || c == "" {
+		t.Error("creator attribute is required")
+	}
+
+	var walk func(n xmlNode, path string)
+	walk = func(n xmlNode, path string) {
+		name := n.XMLName.Local
+		if n.XMLName.Space != gpxNamespace {
+			t.Errorf("%s: element outside the GPX namespace: {%s}%s", path, n.XMLName.Space, name)
+		}
+
+		if order, ok := gpxChildOrder[name]; ok {
+			last := -1
+			for _, child := range n.Children {
+				pos := indexOf(order, child.XMLName.Local)
+				if pos < 0 {
+					t.Errorf("%s: unexpected element %s", path, child.XMLName.Local)
+				} else if pos < last {
+					t.Errorf("%s: element %s out of order", path, child.XMLName.Local)
+				} else {
+					last = pos
+				}
+			}
+		}
+
+		switch name {
+		case "wpt", "trkpt":
+			checkDegrees(t, path, n, "lat", -90, 90, true)
+			checkDegrees(t, path, n, "lon", -180, 180, false)
+		case "time":
+			if _, err := time.Parse(time.RFC3339, strings.TrimSpace(n.Text)); err != nil {
+				t.Errorf("%s: invalid dateTime %q", path, n.Text)
+			}
+		}
+
+		for _, child := range n.Children {
+			walk(child, path+"/"+child.XMLName.Local)
+		}
+	}
+	walk(root, "gpx")
+}
+
+// checkDegrees checks a required xsd:decimal attribute against its range.
+// Longitudes exclude 180 itself.
+func checkDegrees(t *testing.T, path string, n xmlNode, attr string, minValue, maxValue float64, maxInclusive bool) {
+	t.Helper()
+	value, ok := n.attr(attr)
+	if !ok {
+		t.Errorf("%s: missing %s", path, attr)
+		return
+	}
+	if strings.ContainsAny(value, "eE") {
+		t.Errorf("%s: %s %q is not an xsd:decimal", path, attr, value)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || v < minValue || v > maxValue || (!maxInclusive && v == maxValue) {
+		t.Errorf("%s: %s %q out of range", path, attr, value)
+	}
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRouteGPX(t *testing.T) {
+	encoded := polyline.Encode([]polyline.Coordinate{
+		{Lat: 52.0, Lon: 4.0},
+		{Lat: 52.01, Lon: 4.0},
+		{Lat: 52.01, Lon: 4.01},
+	})
+	option := models.RouteOption{
+		ID:      "opt_test",
+		Summary: models.RouteSummary{Title: "Via the park"},
+		Legs: []models.RouteLeg{
+			{
+				Mode:             models.ModeBike,
+				GeometryPolyline: &encoded,
+				Instructions: []models.Instruction{
+					{Text: "Head north", DistanceMeters: 1112},
+					{Text: "Turn right", DistanceMeters: 686},
+					{Text: "Arrive at destination", DistanceMeters: 0},
+				},
+			},
+			// No geometry: start and end only
+			{
+				Mode:  models.ModeWalk,
+				Start: models.LegPoint{Point: models.Point{Lat: 52.01, Lon: 4.01}},
+				End:   models.LegPoint{Point: models.Point{Lat: 52.02, Lon: 0.000001}},
+			},
+		},
+	}
+
+	doc := routeGPX(option, time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC))
+	data, err := xml.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	validateGPX(t, data)
+
+	if len(doc.Tracks) != 1 || len(doc.Tracks[0].Segments) != 2 {
+		t.Fatalf("expected one track with a segment per leg, got %+v", doc.Tracks)
+	}
+	if doc.Tracks[0].Type != "bike" {
+		t.Errorf("expected track type bike, got %q", doc.Tracks[0].Type)
+	}
+	if got := len(doc.Tracks[0].Segments[0].Points); got != 3 {
+		t.Errorf("expected 3 track points from the polyline, got %d", got)
+	}
+	if got := len(doc.Tracks[0].Segments[1].Points); got != 2 {
+		t.Errorf("expected start and end track points, got %d", got)
+	}
+
+	// Waypoints fall where each instruction begins
+	want := []struct {
+		name     string
+		lat, lon float64
+	}{
+		{"Head north", 52.0, 4.0},
+		{"Turn right", 52.01, 4.0},
+		{"Arrive at destination", 52.01, 4.01},
+	}
+	if len(doc.Waypoints) != len(want) {
+		t.Fatalf("expected %d waypoints, got %d", len(want), len(doc.Waypoints))
+	}
+	for i, w := range want {
+		got := doc.Waypoints[i]
+		if got.Name != w.name {
+			t.Errorf("waypoint %d: expected name %q, got %q", i, w.name, got.Name)
+		}
+		if !coordsClose(float64(got.Lat), w.lat) || !coordsClose(float64(got.Lon), w.lon) {
+			t.Errorf("waypoint %d: expected %v,%v, got %v,%v", i, w.lat, w.lon, got.Lat, got.Lon)
+		}
+	}
+}
+
+func coordsClose(a, b float64) bool {
+	return a-b < 0.0001 && b-a < 0.0001
+}
+
+func TestExportGPX(t *testing.T) {
+	store := routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()})
+	route, err := store.Save(context.Background(), "", models.RouteComputeResponse{Options: []models.RouteOption{
+		{ID: "opt_top", Legs: []models.RouteLeg{{
+			Start: models.LegPoint{Point: models.Point{Lat: 52.37, Lon: 4.89}},
+			End:   models.LegPoint{Point: models.Point{Lat: 52.31, Lon: 4.76}},
+		}}},
+		{ID: "opt_second"},
+	}})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	h := NewRouteHandler(nil, zerolog.Nop()).WithRouteStore(store)
+	router := chi.NewRouter()
+	router.Get("/v1/routes/{routeId}/export.gpx", h.ExportGPX)
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		filename string
+	}{
+		{"top-ranked option by default", "/v1/routes/" + route.ID + "/export.gpx", http.StatusOK, "opt_top.gpx"},
+		{"option by ID", "/v1/routes/" + route.ID + "/export.gpx?optionId=opt_second", http.StatusOK, "opt_second.gpx"},
+		{"unknown option", "/v1/routes/" + route.ID + "/export.gpx?optionId=opt_unknown", http.StatusNotFound, ""},
+		{"unknown route", "/v1/routes/rte_unknown/export.gpx", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, w.Code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != MediaTypeGPX {
+				t.Errorf("expected Content-Type %s, got %s", MediaTypeGPX, ct)
+			}
+			if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, tt.filename) {
+				t.Errorf("expected Content-Disposition naming %s, got %s", tt.filename, cd)
+			}
+			if !bytes.HasPrefix(w.Body.Bytes(), []byte("<?xml")) {
+				t.Error("expected an XML declaration")
+			}
+			validateGPX(t, w.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/routestore"
+)
+
+// publicRoutePath is where shared routes are served.
+const publicRoutePath = "/v1/public/routes/"
+
+// ShareRoute handles POST /v1/routes/{routeId}:share - create a public,
+// read-only link to a stored route the user can fetch.
+func (h *RouteHandler) ShareRoute(w http.ResponseWriter, r *http.Request) {
+	if h.routeStore == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "stored routes are not available")
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	share, err := h.routeStore.Share(r.Context(), chi.URLParam(r, "routeId"), userID)
+	if errors.Is(err, routestore.ErrRouteNotFound) {
+		response.NotFound(w, r, models.ErrorCodeRouteNotFound, "route not found or expired; compute it again")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to share route")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to share route")
+		return
+	}
+
+	path := publicRoutePath + share.Token
+	response.Created(w, path, models.RouteShare{
+		Token:     share.Token,
+		Path:      path,
+		ExpiresAt: models.Timestamp(share.ExpiresAt),
+	})
+}
+
+// RevokeRouteShare handles DELETE /v1/me/route-shares/{shareToken} - revoke
+// one of the user's route shares, so its link stops working.
+func (h *RouteHandler) RevokeRouteShare(w http.ResponseWriter, r *http.Request) {
+	if h.routeStore == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "stored routes are not available")
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	err := h.routeStore.RevokeShare(r.Context(), userID, chi.URLParam(r, "shareToken"))
+	if errors.Is(err, routestore.ErrShareNotFound) {
+		response.NotFound(w, r, models.ErrorCodeRouteShareNotFound, "route share not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to revoke route share")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to revoke route share")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetPublicRoute handles GET /v1/public/routes/{shareToken} - a shared
+// route, without authentication. Expired and revoked shares are not found.
+func (h *RouteHandler) GetPublicRoute(w http.ResponseWriter, r *http.Request) {
+	if h.routeStore == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeUnavailable, "stored routes are not available")
+		return
+	}
+
+	share, err := h.routeStore.GetShare(r.Context(), chi.URLParam(r, "shareToken"))
+	if errors.Is(err, routestore.ErrShareNotFound) {
+		response.NotFound(w, r, models.ErrorCodeRouteShareNotFound, "route share not found, expired or revoked")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to load route share")
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to load route share")
+		return
+	}
+
+	// Short-lived, so a revoked share soon stops being served from caches
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	response.JSON(w, http.StatusOK, share.Route)
+}
@@ -0,0 +1,732 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/user"
+	"github.com/breatheroute/breatheroute/internal/weather"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
+)
+
+func TestSortOptions_BalancedEffortWeighting(t *testing.T) {
+	newOptions := func() []models.RouteOption {
+		return []models.RouteOption{
+			{
+				ID:              "hilly",
+				DurationSeconds: 1200, // 20 min
+				ExposureScore:   30,
+				Legs:            []models.RouteLeg{{AscentMeters: intPtr(80)}},
+			},
+			{
+				ID:              "flat",
+				DurationSeconds: 1380, // 23 min
+				ExposureScore:   30,
+				Legs:            []models.RouteLeg{{AscentMeters: intPtr(5)}},
+			},
+		}
+	}
+
+	h := NewRouteHandler(nil, zerolog.Nop())
+
+	// Without effort weighting the shorter hilly route wins
+	options := newOptions()
+	h.sortOptions(options, routeRanking{blend: defaultBlend})
+	if options[0].ID != "hilly" {
+		t.Errorf("expected hilly route first without effort weighting, got %s", options[0].ID)
+	}
+
+	// With effort weighting the flatter route ranks higher
+	options = newOptions()
+	h.sortOptions(options, routeRanking{blend: defaultBlend, effortWeight: 0.5})
+	if options[0].ID != "flat" {
+		t.Errorf("expected flat route first with effort weighting, got %s", options[0].ID)
+	}
+
+	// Effort weighting does not affect the fastest objective
+	options = newOptions()
+	effort := 1.0
+	h.sortOptions(options, rankingFor(models.RouteComputeRequest{
+		Objective:       models.ObjectiveFastest,
+		ProfileOverride: &models.ProfileInput{Constraints: models.RouteConstraints{EffortWeight: &effort}},
+	}))
+	if options[0].ID != "hilly" {
+		t.Errorf("expected hilly route first for fastest objective, got %s", options[0].ID)
+	}
+}
+
+func TestRankingFor_StoredEffortWeight(t *testing.T) {
+	newOptions := func() []models.RouteOption {
+		return []models.RouteOption{
+			{ID: "hilly", DurationSeconds: 1200, ExposureScore: 30, Legs: []models.RouteLeg{{AscentMeters: intPtr(80)}}},
+			{ID: "flat", DurationSeconds: 1380, ExposureScore: 30, Legs: []models.RouteLeg{{AscentMeters: intPtr(5)}}},
+		}
+	}
+
+	ctx := context.Background()
+	users := user.NewService(user.NewInMemoryRepository())
+	if _, err := users.CreateUser(ctx, "usr_effort", "nl-NL"); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	stored := 0.5
+	if _, err := users.PatchProfile(ctx, "usr_effort", &models.ProfilePatch{
+		Constraints: &models.RouteConstraintsPatch{EffortWeight: &stored},
+	}); err != nil {
+		t.Fatalf("patch profile: %v", err)
+	}
+
+	h := NewRouteHandler(nil, zerolog.Nop()).WithUserService(users)
+	balanced := models.RouteComputeRequest{Objective: models.ObjectiveBalanced}
+
+	// Anonymous requests have no stored effort weight
+	options := newOptions()
+	h.sortOptions(options, h.rankingFor(ctx, "", balanced))
+	if options[0].ID != "hilly" {
+		t.Errorf("expected hilly route first for anonymous request, got %s", options[0].ID)
+	}
+
+	// The stored effort weight favours the flatter route
+	options = newOptions()
+	h.sortOptions(options, h.rankingFor(ctx, "usr_effort", balanced))
+	if options[0].ID != "flat" {
+		t.Errorf("expected flat route first with stored effort weight, got %s", options[0].ID)
+	}
+
+	// A profile override takes precedence over the stored effort weight
+	none := 0.0
+	override := balanced
+	override.ProfileOverride = &models.ProfileInput{Constraints: models.RouteConstraints{EffortWeight: &none}}
+	options = newOptions()
+	h.sortOptions(options, h.rankingFor(ctx, "usr_effort", override))
+	if options[0].ID != "hilly" {
+		t.Errorf("expected hilly route first with override, got %s", options[0].ID)
+	}
+}
+
+func TestSortOptions_Blend(t *testing.T) {
+	newOptions := func() []models.RouteOption {
+		return []models.RouteOption{
+			{ID: "clean", DurationSeconds: 2400, ExposureScore: 20},  // 40 min
+			{ID: "quick", DurationSeconds: 1200, ExposureScore: 60},  // 20 min
+			{ID: "middle", DurationSeconds: 1500, ExposureScore: 30}, // 25 min
+		}
+	}
+	blend := func(b float64) *float64 { return &b }
+
+	tests := []struct {
+		name      string
+		objective models.Objective
+		blend     *float64
+		expected  []string
+	}{
+		{"fastest", models.ObjectiveFastest, nil, []string{"quick", "middle", "clean"}},
+		{"lowest exposure", models.ObjectiveLowestExposure, nil, []string{"clean", "middle", "quick"}},
+		{"balanced default", models.ObjectiveBalanced, nil, []string{"middle", "quick", "clean"}},
+		{"mostly time", models.ObjectiveBalanced, blend(0.9), []string{"quick", "middle", "clean"}},
+		{"mostly exposure", models.ObjectiveBalanced, blend(0.1), []string{"clean", "middle", "quick"}},
+		// Blend is ignored by the preset objectives
+		{"fastest ignores blend", models.ObjectiveFastest, blend(0), []string{"quick", "middle", "clean"}},
+	}
+
+	h := NewRouteHandler(nil, zerolog.Nop())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := newOptions()
+			h.sortOptions(options, rankingFor(models.RouteComputeRequest{Objective: tt.objective, Blend: tt.blend}))
+			for i, id := range tt.expected {
+				if options[i].ID != id {
+					t.Errorf("position %d: expected %s, got %s", i, id, options[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestSortOptions_Ties(t *testing.T) {
+	// Equal scores are ordered by duration, then exposure, then input order
+	options := []models.RouteOption{
+		{ID: "a", DurationSeconds: 600, ExposureScore: 40},
+		{ID: "b", DurationSeconds: 600, ExposureScore: 40},
+		{ID: "c", DurationSeconds: 600, ExposureScore: 30},
+	}
+
+	h := NewRouteHandler(nil, zerolog.Nop())
+	h.sortOptions(options, routeRanking{blend: 1})
+
+	for i, id := range []string{"c", "a", "b"} {
+		if options[i].ID != id {
+			t.Errorf("position %d: expected %s, got %s", i, id, options[i].ID)
+		}
+	}
+}
+
+func TestValidateBlend(t *testing.T) {
+	for _, b := range []float64{0, 0.3, 1} {
+		if errs := validateBlend(&b); len(errs) != 0 {
+			t.Errorf("expected blend %v to be valid, got %v", b, errs)
+		}
+	}
+	for _, b := range []float64{-0.1, 1.5} {
+		errs := validateBlend(&b)
+		if len(errs) != 1 || errs[0].Field != "blend" || errs[0].Code != models.FieldCodeOutOfRange {
+			t.Errorf("expected out of range error for blend %v, got %v", b, errs)
+		}
+	}
+}
+
+func TestIntegrateLegExposure(t *testing.T) {
+	legs := []models.RouteLeg{
+		{DurationSeconds: 600},  // 10 min at low exposure
+		{DurationSeconds: 1800}, // 30 min along a busy road
+	}
+
+	got := integrateLegExposure(legs, []float64{20, 60})
+	if want := 50.0; got != want {
+		t.Errorf("expected time-weighted exposure %v, got %v", want, got)
+	}
+
+	got = integrateLegExposure([]models.RouteLeg{{}, {}}, []float64{20, 60})
+	if want := 40.0; got != want {
+		t.Errorf("expected equal weighting without durations %v, got %v", want, got)
+	}
+}
+
+// stubTransitProvider reports a single disruption between Amsterdam and
+// Utrecht.
+type stubTransitProvider struct{}
+
+func (stubTransitProvider) Name() string { return "stub" }
+
+func (stubTransitProvider) GetAllDisruptions(context.Context) ([]*transit.Disruption, error) {
+	return []*transit.Disruption{{
+		ID:               "d1",
+		Type:             transit.DisruptionDisturbance,
+		Title:            "Signal failure",
+		Impact:           transit.ImpactMajor,
+		AffectedStations: []string{"ASD", "UT"},
+		Start:            time.Now().Add(-time.Hour),
+	}}, nil
+}
+
+func (p stubTransitProvider) GetDisruptionsForRoute(ctx context.Context, origin, destination string) (*transit.RouteDisruptions, error) {
+	all, _ := p.GetAllDisruptions(ctx)
+	result := &transit.RouteDisruptions{Origin: origin, Destination: destination}
+	for _, d := range all {
+		if d.AffectsStation(origin) || d.AffectsStation(destination) {
+			result.Disruptions = append(result.Disruptions, d)
+		}
+	}
+	result.HasDisruptions = len(result.Disruptions) > 0
+	result.OverallImpact = transit.CalculateOverallImpact(result.Disruptions)
+	return result, nil
+}
+
+func (stubTransitProvider) GetStations(context.Context) ([]*transit.Station, error) {
+	return []*transit.Station{
+		{Code: "ASD", Name: "Amsterdam Centraal"},
+		{Code: "UT", Name: "Utrecht Centraal"},
+		{Code: "RTD", Name: "Rotterdam Centraal"},
+	}, nil
+}
+
+func TestAttachTrainDisruptions(t *testing.T) {
+	service := transit.NewService(transit.ServiceConfig{Provider: stubTransitProvider{}, Logger: zerolog.Nop()})
+	h := NewRouteHandler(nil, zerolog.Nop()).WithTransitService(service)
+
+	asd := "ASD"
+	options := []models.RouteOption{
+		{
+			ID:         "train",
+			Confidence: models.ConfidenceHigh,
+			Legs: []models.RouteLeg{
+				{Mode: models.ModeWalk, Start: models.LegPoint{Name: "Home"}, End: models.LegPoint{Name: "Amsterdam Centraal"}},
+				{
+					Mode:    models.ModeTrain,
+					Start:   models.LegPoint{Name: "Amsterdam Centraal"},
+					End:     models.LegPoint{Name: "Utrecht Centraal"},
+					Transit: &models.TransitLeg{OriginStation: &asd},
+				},
+			},
+		},
+		{
+			ID:         "unaffected",
+			Confidence: models.ConfidenceHigh,
+			Legs: []models.RouteLeg{{
+				Mode:    models.ModeTrain,
+				Start:   models.LegPoint{Name: "Rotterdam Centraal"},
+				End:     models.LegPoint{Name: "Den Haag Centraal"},
+				Transit: &models.TransitLeg{},
+			}},
+		},
+	}
+
+	h.attachTrainDisruptions(context.Background(), options, "en")
+
+	if options[0].Legs[0].Transit != nil {
+		t.Error("expected walk leg to be left alone")
+	}
+	disruptions := options[0].Legs[1].Transit.Disruptions
+	if disruptions == nil || !disruptions.HasDisruptions || len(disruptions.Disruptions) != 1 {
+		t.Fatalf("expected one disruption on the train leg, got %+v", disruptions)
+	}
+	if options[0].Confidence != models.ConfidenceMedium {
+		t.Errorf("expected confidence lowered to MEDIUM, got %s", options[0].Confidence)
+	}
+
+	if d := options[1].Legs[0].Transit.Disruptions; d == nil || d.HasDisruptions {
+		t.Errorf("expected an empty disruption result for the unaffected leg, got %+v", d)
+	}
+	if options[1].Confidence != models.ConfidenceHigh {
+		t.Errorf("expected unaffected confidence to stay HIGH, got %s", options[1].Confidence)
+	}
+}
+
+func TestDisruptedConfidence(t *testing.T) {
+	tests := []struct {
+		confidence models.Confidence
+		impact     transit.Impact
+		expected   models.Confidence
+	}{
+		{models.ConfidenceHigh, "", models.ConfidenceHigh},
+		{models.ConfidenceHigh, transit.ImpactModerate, models.ConfidenceHigh},
+		{models.ConfidenceHigh, transit.ImpactMajor, models.ConfidenceMedium},
+		{models.ConfidenceMedium, transit.ImpactMajor, models.ConfidenceLow},
+		{models.ConfidenceHigh, transit.ImpactSevere, models.ConfidenceLow},
+	}
+
+	for _, tt := range tests {
+		if got := disruptedConfidence(tt.confidence, tt.impact); got != tt.expected {
+			t.Errorf("disruptedConfidence(%s, %s) = %s, want %s", tt.confidence, tt.impact, got, tt.expected)
+		}
+	}
+}
+
+// stubAirQualityProvider serves two stations in central Amsterdam.
+type stubAirQualityProvider struct{}
+
+func (stubAirQualityProvider) FetchSnapshot(context.Context) (*airquality.AQSnapshot, error) {
+	snapshot := airquality.NewAQSnapshot("stub")
+	for _, s := range []*airquality.Station{
+		{ID: "NL49012", Lat: 52.374, Lon: 4.899},
+		{ID: "NL49014", Lat: 52.359, Lon: 4.866},
+	} {
+		s.Pollutants = []airquality.Pollutant{airquality.PollutantNO2}
+		snapshot.Stations[s.ID] = s
+		snapshot.SetMeasurement(&airquality.Measurement{
+			StationID:  s.ID,
+			Pollutant:  airquality.PollutantNO2,
+			Value:      25,
+			MeasuredAt: time.Now(),
+		})
+	}
+	return snapshot, nil
+}
+
+func (p stubAirQualityProvider) FetchStations(ctx context.Context) ([]*airquality.Station, error) {
+	snapshot, _ := p.FetchSnapshot(ctx)
+	return snapshot.StationList(), nil
+}
+
+func (stubAirQualityProvider) FetchLatestMeasurements(context.Context) ([]*airquality.Measurement, error) {
+	return nil, nil
+}
+
+// legAirQualityProvider serves a clean station in Amsterdam and a polluted
+// one in Utrecht, too far apart to influence each other's estimates.
+type legAirQualityProvider struct{ stubAirQualityProvider }
+
+func (legAirQualityProvider) FetchSnapshot(context.Context) (*airquality.AQSnapshot, error) {
+	snapshot := airquality.NewAQSnapshot("stub")
+	for _, s := range []struct {
+		station *airquality.Station
+		no2     float64
+	}{
+		{&airquality.Station{ID: "NL49012", Lat: 52.374, Lon: 4.899}, 20},
+		{&airquality.Station{ID: "NL10636", Lat: 52.091, Lon: 5.122}, 60},
+	} {
+		s.station.Pollutants = []airquality.Pollutant{airquality.PollutantNO2}
+		snapshot.Stations[s.station.ID] = s.station
+		snapshot.SetMeasurement(&airquality.Measurement{
+			StationID:  s.station.ID,
+			Pollutant:  airquality.PollutantNO2,
+			Value:      s.no2,
+			MeasuredAt: time.Now(),
+		})
+	}
+	return snapshot, nil
+}
+
+func TestLegExposures_IntegratedByDuration(t *testing.T) {
+	amsterdam := models.Point{Lat: 52.374, Lon: 4.899}
+	utrecht := models.Point{Lat: 52.091, Lon: 5.122}
+	legs := []models.RouteLeg{
+		{Start: models.LegPoint{Point: amsterdam}, End: models.LegPoint{Point: amsterdam}, DurationSeconds: 600},
+		{Start: models.LegPoint{Point: utrecht}, End: models.LegPoint{Point: utrecht}, DurationSeconds: 1800},
+	}
+	aq := airquality.NewService(airquality.ServiceConfig{Provider: legAirQualityProvider{}, Logger: zerolog.Nop()})
+	h := NewRouteHandler(nil, zerolog.Nop()).WithAirQualityService(aq)
+
+	exposures := h.legExposures(context.Background(), legs)
+
+	clean := 20 / airquality.EAQINO2GoodMax * 100
+	polluted := 60 / airquality.EAQINO2GoodMax * 100
+	if math.Abs(exposures[0]-clean) > 0.01 || math.Abs(exposures[1]-polluted) > 0.01 {
+		t.Fatalf("expected leg exposures %.2f and %.2f, got %v", clean, polluted, exposures)
+	}
+	want := (clean*600 + polluted*1800) / 2400
+	if got := integrateLegExposure(legs, exposures); math.Abs(got-want) > 0.01 {
+		t.Errorf("expected duration-weighted exposure %.2f, got %.2f", want, got)
+	}
+
+	// Without air quality every leg gets the placeholder
+	exposures = NewRouteHandler(nil, zerolog.Nop()).legExposures(context.Background(), legs)
+	if exposures[0] != placeholderExposureScore(0) || exposures[1] != placeholderExposureScore(0) {
+		t.Errorf("expected placeholder exposures, got %v", exposures)
+	}
+}
+
+func TestAssessExposureConfidence(t *testing.T) {
+	central := models.Point{Lat: 52.370, Lon: 4.890} // near both stations
+	remote := models.Point{Lat: 52.520, Lon: 5.470}  // ~40km away
+	leg := func(start, end models.Point) []models.RouteLeg {
+		return []models.RouteLeg{{Start: models.LegPoint{Point: start}, End: models.LegPoint{Point: end}}}
+	}
+	newOptions := func() []models.RouteOption {
+		return []models.RouteOption{
+			{ID: "central", Legs: leg(central, central)},
+			{ID: "remote", Legs: leg(remote, remote)},
+			{ID: "mixed", Legs: leg(central, remote)},
+		}
+	}
+	aq := airquality.NewService(airquality.ServiceConfig{Provider: stubAirQualityProvider{}, Logger: zerolog.Nop()})
+
+	tests := []struct {
+		name       string
+		cfg        ExposureConfidenceConfig
+		confidence []models.Confidence
+		fraction   []float64
+	}{
+		{
+			name:       "defaults",
+			confidence: []models.Confidence{models.ConfidenceHigh, models.ConfidenceLow, models.ConfidenceMedium},
+			fraction:   []float64{0, 1, 0.5},
+		},
+		{
+			name:       "stricter fraction",
+			cfg:        ExposureConfidenceConfig{MaxLowFraction: 0.25},
+			confidence: []models.Confidence{models.ConfidenceHigh, models.ConfidenceLow, models.ConfidenceLow},
+			fraction:   []float64{0, 1, 0.5},
+		},
+		{
+			name:       "high samples required",
+			cfg:        ExposureConfidenceConfig{MinSampleConfidence: models.ConfidenceHigh, MaxLowFraction: 0.75},
+			confidence: []models.Confidence{models.ConfidenceHigh, models.ConfidenceLow, models.ConfidenceMedium},
+			fraction:   []float64{0, 1, 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewRouteHandler(nil, zerolog.Nop()).WithAirQualityService(aq).WithExposureConfidence(tt.cfg)
+			options := newOptions()
+			h.assessExposureConfidence(context.Background(), options)
+
+			for i, option := range options {
+				if option.ExposureConfidence != tt.confidence[i] {
+					t.Errorf("%s: expected confidence %s, got %s", option.ID, tt.confidence[i], option.ExposureConfidence)
+				}
+				if option.LowConfidenceSampleFraction == nil || *option.LowConfidenceSampleFraction != tt.fraction[i] {
+					t.Errorf("%s: expected low fraction %v, got %v", option.ID, tt.fraction[i], option.LowConfidenceSampleFraction)
+				}
+			}
+		})
+	}
+
+	// Estimated options are never sampled, even where every sample is high
+	estimated := []models.RouteOption{{ID: "estimated", Legs: leg(central, central), Estimated: true}}
+	NewRouteHandler(nil, zerolog.Nop()).WithAirQualityService(aq).assessExposureConfidence(context.Background(), estimated)
+	if estimated[0].ExposureConfidence != models.ConfidenceLow || estimated[0].LowConfidenceSampleFraction != nil {
+		t.Errorf("expected low exposure confidence for estimated option, got %+v", estimated[0])
+	}
+
+	// Without air quality data the options are left unflagged
+	options := newOptions()
+	NewRouteHandler(nil, zerolog.Nop()).assessExposureConfidence(context.Background(), options)
+	if options[0].ExposureConfidence != "" || options[0].LowConfidenceSampleFraction != nil {
+		t.Errorf("expected no exposure confidence without air quality service, got %+v", options[0])
+	}
+}
+
+func TestSortOptions_DemoteLowConfidence(t *testing.T) {
+	newOptions := func() []models.RouteOption {
+		return []models.RouteOption{
+			{ID: "unreliable", DurationSeconds: 1200, ExposureScore: 10, ExposureConfidence: models.ConfidenceLow},
+			{ID: "quick", DurationSeconds: 900, ExposureScore: 40, ExposureConfidence: models.ConfidenceMedium},
+			{ID: "clean", DurationSeconds: 1500, ExposureScore: 20, ExposureConfidence: models.ConfidenceHigh},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		demote    bool
+		objective models.Objective
+		expected  []string
+	}{
+		{"not demoted", false, models.ObjectiveLowestExposure, []string{"unreliable", "clean", "quick"}},
+		{"lowest exposure", true, models.ObjectiveLowestExposure, []string{"clean", "quick", "unreliable"}},
+		{"balanced", true, models.ObjectiveBalanced, []string{"quick", "clean", "unreliable"}},
+		// Exposure plays no part in the fastest ranking
+		{"fastest", true, models.ObjectiveFastest, []string{"quick", "unreliable", "clean"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewRouteHandler(nil, zerolog.Nop()).WithExposureConfidence(ExposureConfidenceConfig{DemoteLow: tt.demote})
+			options := newOptions()
+			h.sortOptions(options, h.rankingFor(context.Background(), "", models.RouteComputeRequest{Objective: tt.objective}))
+			for i, id := range tt.expected {
+				if options[i].ID != id {
+					t.Errorf("position %d: expected %s, got %s", i, id, options[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestExposureSamplingConfig_IntervalFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      ExposureSamplingConfig
+		length   float64
+		expected float64
+	}{
+		{"short route uses min interval", ExposureSamplingConfig{}, 3000, 50},
+		{"long route is capped", ExposureSamplingConfig{}, 199000, 1000},
+		{"custom cap", ExposureSamplingConfig{MaxSamples: 11, MinIntervalMeters: 100}, 5000, 500},
+		{"custom min interval", ExposureSamplingConfig{MaxSamples: 11, MinIntervalMeters: 100}, 500, 100},
+		{"single sample", ExposureSamplingConfig{MaxSamples: 1}, 5000, 5000},
+	}
+
+	for _, tt := range tests {
+		if got := tt.cfg.intervalFor(tt.length); got != tt.expected {
+			t.Errorf("%s: expected interval %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+// longRouteLeg returns a leg of about 100 km with a point every 20 m.
+func longRouteLeg() models.RouteLeg {
+	coords := make([]polyline.Coordinate, 5000)
+	for i := range coords {
+		coords[i] = polyline.Coordinate{Lat: 52.0 + float64(i)*0.00018, Lon: 4.9}
+	}
+	encoded := polyline.Encode(coords)
+	return models.RouteLeg{GeometryPolyline: &encoded}
+}
+
+func TestRouteSamples(t *testing.T) {
+	leg := longRouteLeg()
+
+	h := NewRouteHandler(nil, zerolog.Nop())
+	if n := len(h.routeSamples([]models.RouteLeg{leg})); n < DefaultMaxExposureSamples-1 || n > DefaultMaxExposureSamples+1 {
+		t.Errorf("expected about %d samples on a long route, got %d", DefaultMaxExposureSamples, n)
+	}
+
+	h.WithExposureSampling(ExposureSamplingConfig{MaxSamples: 20})
+	if n := len(h.routeSamples([]models.RouteLeg{leg, leg})); n < 19 || n > 22 {
+		t.Errorf("expected about 20 samples across both legs, got %d", n)
+	}
+
+	// A 1 km route keeps the minimum interval
+	short := polyline.Encode([]polyline.Coordinate{{Lat: 52.0, Lon: 4.9}, {Lat: 52.009, Lon: 4.9}})
+	if n := len(h.routeSamples([]models.RouteLeg{{GeometryPolyline: &short}})); n < 20 || n > 22 {
+		t.Errorf("expected a sample about every 50 m on a 1 km route, got %d", n)
+	}
+}
+
+func BenchmarkAssessExposureConfidence_LongRoute(b *testing.B) {
+	aq := airquality.NewService(airquality.ServiceConfig{Provider: stubAirQualityProvider{}, Logger: zerolog.Nop()})
+	options := []models.RouteOption{{Legs: []models.RouteLeg{longRouteLeg()}}}
+
+	for _, maxSamples := range []int{50, 200, 1000} {
+		b.Run(fmt.Sprintf("max_samples_%d", maxSamples), func(b *testing.B) {
+			h := NewRouteHandler(nil, zerolog.Nop()).
+				WithAirQualityService(aq).
+				WithExposureSampling(ExposureSamplingConfig{MaxSamples: maxSamples})
+			ctx := context.Background()
+			h.assessExposureConfidence(ctx, options) // warm the snapshot cache
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.assessExposureConfidence(ctx, options)
+			}
+		})
+	}
+}
+
+func TestLegCoordinates(t *testing.T) {
+	encoded := polyline.Encode([]polyline.Coordinate{{Lat: 52.0, Lon: 4.0}, {Lat: 52.1, Lon: 4.1}})
+	legs := []models.RouteLeg{
+		{GeometryPolyline: &encoded},
+		// No geometry: start and end only, sharing the previous leg's end
+		{
+			Start: models.LegPoint{Point: models.Point{Lat: 52.1, Lon: 4.1}},
+			End:   models.LegPoint{Point: models.Point{Lat: 52.2, Lon: 4.3}},
+		},
+	}
+
+	got := legCoordinates(legs)
+	want := [][2]float64{{4.0, 52.0}, {4.1, 52.1}, {4.3, 52.2}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d positions, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("position %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+// stubWeatherProvider reports fixed weather, with hourly forecasts for the
+// next 48 hours, or fails with err.
+type stubWeatherProvider struct {
+	err error
+}
+
+func (stubWeatherProvider) Name() string { return "stub" }
+
+func (p stubWeatherProvider) GetCurrentWeather(_ context.Context, lat, lon float64) (*weather.Observation, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &weather.Observation{
+		Lat: lat, Lon: lon,
+		Temperature: 12.5, WindSpeed: 6, WindDirection: 240, WindGust: 11,
+		Condition:  weather.ConditionRain,
+		ObservedAt: time.Now().Add(-10 * time.Minute).Truncate(time.Second),
+	}, nil
+}
+
+func (p stubWeatherProvider) GetForecast(_ context.Context, lat, lon float64) (*weather.Forecast, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	forecast := &weather.Forecast{Lat: lat, Lon: lon}
+	start := time.Now().Truncate(time.Hour)
+	for i := range 48 {
+		forecast.Hourly = append(forecast.Hourly, weather.HourlyForecast{
+			Time:        start.Add(time.Duration(i) * time.Hour),
+			Temperature: float64(i),
+			Condition:   weather.ConditionClouds,
+			PrecipProb:  0.4,
+		})
+	}
+	return forecast, nil
+}
+
+func TestRouteWeather(t *testing.T) {
+	now := time.Now()
+	newHandler := func(provider weather.Provider) *RouteHandler {
+		return NewRouteHandler(nil, zerolog.Nop()).WithWeatherService(weather.NewService(weather.ServiceConfig{
+			Provider: provider,
+			Logger:   zerolog.Nop(),
+		}))
+	}
+	input := func(departure time.Time) models.RouteComputeRequest {
+		return models.RouteComputeRequest{
+			Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+			Destination:   &models.Point{Lat: 52.09, Lon: 5.12},
+			DepartureTime: departure.Format(time.RFC3339),
+		}
+	}
+
+	t.Run("current conditions for departures now", func(t *testing.T) {
+		got, warnings := newHandler(stubWeatherProvider{}).routeWeather(context.Background(), input(now), now)
+		if got == nil || got.Origin == nil || got.Destination == nil {
+			t.Fatalf("expected weather at both points, got %+v", got)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", warnings)
+		}
+		origin := got.Origin
+		if origin.Source != models.WeatherSourceCurrent || origin.Condition != "RAIN" || origin.TemperatureCelsius != 12.5 {
+			t.Errorf("unexpected origin weather %+v", origin)
+		}
+		if time.Time(origin.ObservedAt).IsZero() {
+			t.Error("expected the observation time")
+		}
+		if origin.WindGustMps == nil || *origin.WindGustMps != 11 || origin.PrecipitationProbability != nil {
+			t.Errorf("unexpected wind gust or precipitation %+v", origin)
+		}
+	})
+
+	t.Run("forecast for later departures", func(t *testing.T) {
+		departure := now.Add(5 * time.Hour)
+		got, _ := newHandler(stubWeatherProvider{}).routeWeather(context.Background(), input(departure), now)
+		if got == nil || got.Origin == nil {
+			t.Fatalf("expected forecast weather, got %+v", got)
+		}
+		if got.Origin.Source != models.WeatherSourceForecast {
+			t.Errorf("expected FORECAST, got %s", got.Origin.Source)
+		}
+		if d := time.Time(got.Origin.ObservedAt).Sub(departure).Abs(); d > 30*time.Minute {
+			t.Errorf("expected the forecast hour nearest the departure, got %v", time.Time(got.Origin.ObservedAt))
+		}
+		if got.Origin.PrecipitationProbability == nil || *got.Origin.PrecipitationProbability != 0.4 {
+			t.Errorf("expected precipitation probability, got %+v", got.Origin)
+		}
+	})
+
+	t.Run("omitted beyond the forecast", func(t *testing.T) {
+		got, warnings := newHandler(stubWeatherProvider{}).routeWeather(context.Background(), input(now.Add(7*24*time.Hour)), now)
+		if got != nil || len(warnings) != 0 {
+			t.Errorf("expected no weather and no warnings, got %+v, %v", got, warnings)
+		}
+	})
+
+	t.Run("provider errors are a warning", func(t *testing.T) {
+		got, warnings := newHandler(stubWeatherProvider{err: errors.New("provider down")}).routeWeather(context.Background(), input(now), now)
+		if got != nil {
+			t.Errorf("expected no weather, got %+v", got)
+		}
+		if len(warnings) != 1 || warnings[0].Code != "WEATHER_UNAVAILABLE" {
+			t.Errorf("expected a WEATHER_UNAVAILABLE warning, got %v", warnings)
+		}
+	})
+
+	t.Run("no weather service", func(t *testing.T) {
+		got, warnings := NewRouteHandler(nil, zerolog.Nop()).routeWeather(context.Background(), input(now), now)
+		if got != nil || warnings != nil {
+			t.Errorf("expected nothing, got %+v, %v", got, warnings)
+		}
+	})
+}
+
+func TestInstructionOptions(t *testing.T) {
+	locale := "nl-NL"
+	off := false
+
+	opts := instructionOptions(models.RouteComputeRequest{})
+	if !opts.IncludeInstructions || opts.Language != "" {
+		t.Errorf("default = %+v, want instructions in the provider default language", opts)
+	}
+
+	opts = instructionOptions(models.RouteComputeRequest{ClientContext: &models.ClientContext{Locale: &locale}})
+	if !opts.IncludeInstructions || opts.Language != "nl-NL" {
+		t.Errorf("with locale = %+v, want Dutch instructions", opts)
+	}
+
+	opts = instructionOptions(models.RouteComputeRequest{IncludeInstructions: &off})
+	if opts.IncludeInstructions {
+		t.Error("instructions included when turned off")
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// currentWeatherWindow is how far from now a departure may be for current
+// conditions to describe it; further ahead, the forecast is used.
+const currentWeatherWindow = time.Hour
+
+// wantsWeather reports whether the request asked for weather.
+func wantsWeather(input models.RouteComputeRequest) bool {
+	return input.IncludeWeather != nil && *input.IncludeWeather
+}
+
+// routeWeather looks up the weather at the request's origin and destination
+// for its departure time. Points whose weather cannot be fetched are left
+// out with a warning rather than failing the routes; nil is returned if the
+// weather service is not configured or the request has no coordinates.
+func (h *RouteHandler) routeWeather(ctx context.Context, input models.RouteComputeRequest, now time.Time) (*models.RouteWeather, []models.Warning) {
+	if h.weatherService == nil || input.Origin == nil || input.Destination == nil {
+		return nil, nil
+	}
+
+	departure, err := time.Parse(time.RFC3339, input.DepartureTime)
+	if err != nil {
+		departure = now
+	}
+
+	var result models.RouteWeather
+	var origErr, destErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		result.Origin, origErr = h.weatherAt(ctx, *input.Origin, departure, now)
+	}()
+	go func() {
+		defer wg.Done()
+		result.Destination, destErr = h.weatherAt(ctx, *input.Destination, departure, now)
+	}()
+	wg.Wait()
+
+	var warnings []models.Warning
+	if origErr != nil || destErr != nil {
+		h.logger.Warn().Errs("errors", []error{origErr, destErr}).Msg("failed to get weather for route")
+		warnings = append(warnings, models.Warning{
+			Code:    "WEATHER_UNAVAILABLE",
+			Message: "weather is unavailable for part of the route",
+		})
+	}
+	if result.Origin == nil && result.Destination == nil {
+		return nil, warnings
+	}
+	return &result, warnings
+}
+
+// weatherAt returns the weather at p for a departure. Departures within
+// currentWeatherWindow of now use current conditions; later ones use the
+// forecast hour nearest the departure. It returns nil without an error if
+// the departure is beyond the forecast.
+func (h *RouteHandler) weatherAt(ctx context.Context, p models.Point, departure, now time.Time) (*models.WeatherConditions, error) {
+	if departure.Sub(now) <= currentWeatherWindow {
+		obs, err := h.weatherService.GetCurrentWeather(ctx, p.Lat, p.Lon)
+		if err != nil {
+			return nil, err
+		}
+		return &models.WeatherConditions{
+			Source:             models.WeatherSourceCurrent,
+			ObservedAt:         models.Timestamp(obs.ObservedAt),
+			TemperatureCelsius: obs.Temperature,
+			Condition:          string(obs.Condition),
+			WindSpeedMps:       obs.WindSpeed,
+			WindGustMps:        windGust(obs.WindGust),
+			WindDirectionDeg:   obs.WindDirection,
+		}, nil
+	}
+
+	forecast, err := h.weatherService.GetForecast(ctx, p.Lat, p.Lon)
+	if err != nil {
+		return nil, err
+	}
+	hour := forecast.NearestHour(departure)
+	if hour == nil {
+		return nil, nil
+	}
+	return &models.WeatherConditions{
+		Source:                   models.WeatherSourceForecast,
+		ObservedAt:               models.Timestamp(hour.Time),
+		TemperatureCelsius:       hour.Temperature,
+		Condition:                string(hour.Condition),
+		PrecipitationProbability: &hour.PrecipProb,
+		WindSpeedMps:             hour.WindSpeed,
+		WindGustMps:              windGust(hour.WindGust),
+		WindDirectionDeg:         hour.WindDirection,
+	}, nil
+}
+
+// windGust returns the gust speed, or nil if the provider reported none.
+func windGust(gust float64) *float64 {
+	if gust <= 0 {
+		return nil
+	}
+	return &gust
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/user"
+)
+
+// DefaultMaxDisruptionStreams is the default cap on concurrent disruption streams.
+const DefaultMaxDisruptionStreams = 500
+
+// disruptionStreamKeepAlive is how often an idle disruption stream sends a
+// comment, so proxies keep the connection open and disconnects are noticed.
+const disruptionStreamKeepAlive = 25 * time.Second
+
+// Server-Sent Event names used by the disruption stream.
+const (
+	eventSummary = "summary"
+	eventDelta   = "delta"
+)
+
+// TransitHandler handles transit disruption endpoints.
+type TransitHandler struct {
+	transitService *transit.Service
+	userService    *user.Service
+	maxStreams     int64
+	activeStreams  atomic.Int64
+}
+
+// NewTransitHandler creates a new TransitHandler.
+// transitService may be nil when no transit provider is configured.
+func NewTransitHandler(transitService *transit.Service, userService *user.Service) *TransitHandler {
+	return &TransitHandler{
+		transitService: transitService,
+		userService:    userService,
+		maxStreams:     DefaultMaxDisruptionStreams,
+	}
+}
+
+// WithMaxStreams sets the cap on concurrent disruption streams. Values <= 0
+// keep the default.
+func (h *TransitHandler) WithMaxStreams(n int) *TransitHandler {
+	if n > 0 {
+		h.maxStreams = int64(n)
+	}
+	return h
+}
+
+// GetRouteDisruptions handles GET /v1/transit/disruptions - disruptions between two stations.
+// The advisory is localized to the user's profile locale, or Accept-Language when
+// the profile locale is not supported.
+func (h *TransitHandler) GetRouteDisruptions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	if h.transitService == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeTransitUnavailable, "transit data is not available")
+		return
+	}
+
+	origin := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("origin")))
+	destination := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("destination")))
+
+	var fieldErrors []models.FieldError
+	if origin == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "origin", Message: "is required"})
+	}
+	if destination == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "destination", Message: "is required"})
+	}
+	if len(fieldErrors) > 0 {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", fieldErrors)
+		return
+	}
+
+	locale := h.resolveLocale(r, userID)
+
+	disruptions, err := h.transitService.GetDisruptionsForRouteInLocale(r.Context(), origin, destination, locale)
+	if err != nil {
+		if errors.Is(err, transit.ErrProviderUnavailable) {
+			response.ServiceUnavailable(w, r, models.ErrorCodeTransitUnavailable, "transit provider unavailable")
+			return
+		}
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to fetch transit disruptions")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRouteDisruptionsResponse(disruptions))
+}
+
+// StreamDisruptions handles GET /v1/transit/disruptions/stream - live disruptions via SSE.
+// A "summary" event with the current disruption summary is sent on connect,
+// followed by a "delta" event with the added, removed and changed disruptions
+// each time the transit cache refreshes. The stream never calls the provider
+// itself. A client disconnect cancels the request context and ends the stream.
+func (h *TransitHandler) StreamDisruptions(w http.ResponseWriter, r *http.Request) {
+	if h.transitService == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeTransitUnavailable, "transit data is not available")
+		return
+	}
+
+	if h.activeStreams.Add(1) > h.maxStreams {
+		h.activeStreams.Add(-1)
+		response.ServiceUnavailable(w, r, models.ErrorCodeStreamLimitReached, "too many active disruption streams")
+		return
+	}
+	defer h.activeStreams.Add(-1)
+
+	// Subscribe before reading the summary so no refresh is missed in between
+	changes, unsubscribe := h.transitService.SubscribeChanges()
+	defer unsubscribe()
+
+	ctx := r.Context()
+	summary, err := h.transitService.GetDisruptionSummary(ctx)
+	if err != nil {
+		if errors.Is(err, transit.ErrProviderUnavailable) {
+			response.ServiceUnavailable(w, r, models.ErrorCodeTransitUnavailable, "transit provider unavailable")
+			return
+		}
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to fetch transit disruptions")
+		return
+	}
+
+	// The stream outlives the server's write timeout; lift it for this response
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	stream := response.NewEventStream(w)
+	if err := stream.Send(eventSummary, toDisruptionSummaryResponse(summary)); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(disruptionStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if err := stream.Comment("keep-alive"); err != nil {
+				return
+			}
+		case delta, ok := <-changes:
+			if !ok {
+				// Fell behind and missed changes; the client reconnects for a fresh summary
+				return
+			}
+			if err := stream.Send(eventDelta, toDisruptionDeltaResponse(delta)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// resolveLocale returns the advisory locale for the request: the user's profile
+// locale when supported, else the best supported Accept-Language, else English.
+func (h *TransitHandler) resolveLocale(r *http.Request, userID string) string {
+	if h.userService != nil {
+		if me, err := h.userService.GetMe(r.Context(), userID); err == nil && transit.SupportsLocale(me.Locale) {
+			return transit.ResolveLocale(me.Locale)
+		}
+	}
+	return transit.ResolveLocale(preferredLocale(r.Header.Get("Accept-Language")))
+}
+
+// preferredLocale returns the highest-weighted supported language from an
+// Accept-Language header, or "" when none is supported.
+func preferredLocale(header string) string {
+	best := ""
+	bestQ := 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !transit.SupportsLocale(tag) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// toRouteDisruptionsResponse converts route disruptions to the API response.
+func toRouteDisruptionsResponse(rd *transit.RouteDisruptions) models.RouteDisruptions {
+	resp := models.RouteDisruptions{
+		Origin:         rd.Origin,
+		Destination:    rd.Destination,
+		HasDisruptions: rd.HasDisruptions,
+		Locale:         rd.Locale,
+		FetchedAt:      models.Timestamp(rd.FetchedAt),
+	}
+
+	if rd.OverallImpact != "" {
+		impact := string(rd.OverallImpact)
+		resp.OverallImpact = &impact
+	}
+
+	if rd.Advisory.Headline != "" {
+		resp.Advisory = &models.TransitAdvisory{
+			Headline: rd.Advisory.Headline,
+			Details:  rd.Advisory.Details,
+			Severity: string(rd.Advisory.Severity),
+			Message:  rd.Advisory.Text(),
+		}
+	}
+
+	resp.Disruptions = toTransitDisruptions(rd.Disruptions)
+
+	return resp
+}
+
+// toDisruptionSummaryResponse converts a disruption summary to the API model.
+func toDisruptionSummaryResponse(summary *transit.DisruptionSummary) models.TransitDisruptionSummary {
+	resp := models.TransitDisruptionSummary{
+		TotalDisruptions: summary.TotalDisruptions,
+		ByImpact:         make(map[string]int, len(summary.ByImpact)),
+		ByType:           make(map[string]int, len(summary.ByType)),
+		Provider:         summary.Provider,
+		FetchedAt:        models.Timestamp(summary.FetchedAt),
+	}
+
+	for impact, count := range summary.ByImpact {
+		resp.ByImpact[string(impact)] = count
+	}
+	for disruptionType, count := range summary.ByType {
+		resp.ByType[string(disruptionType)] = count
+	}
+	if summary.MostSevere != "" {
+		mostSevere := string(summary.MostSevere)
+		resp.MostSevere = &mostSevere
+	}
+
+	return resp
+}
+
+// toDisruptionDeltaResponse converts disruption changes to the API model.
+func toDisruptionDeltaResponse(changes transit.DisruptionChanges) models.TransitDisruptionDelta {
+	return models.TransitDisruptionDelta{
+		Added:      toTransitDisruptions(changes.Added),
+		Removed:    toTransitDisruptions(changes.Removed),
+		Changed:    toTransitDisruptions(changes.Changed),
+		DetectedAt: models.Timestamp(changes.DetectedAt),
+	}
+}
+
+// toTransitDisruptions converts disruptions to their API representation.
+func toTransitDisruptions(disruptions []*transit.Disruption) []models.TransitDisruption {
+	result := make([]models.TransitDisruption, 0, len(disruptions))
+	for _, d := range disruptions {
+		disruption := models.TransitDisruption{
+			ID:               d.ID,
+			Type:             string(d.Type),
+			Title:            d.Title,
+			Impact:           string(d.Impact),
+			AffectedStations: d.AffectedStations,
+			IsPlanned:        d.IsPlanned,
+		}
+		if d.ExpectedDuration > 0 {
+			duration := d.ExpectedDuration
+			disruption.ExpectedDuration = &duration
+		}
+		if d.AlternativeTransport != "" {
+			alternative := d.AlternativeTransport
+			disruption.AlternativeTransport = &alternative
+		}
+		if !d.Start.IsZero() {
+			start := models.Timestamp(d.Start)
+			disruption.Start = &start
+		}
+		if !d.End.IsZero() {
+			end := models.Timestamp(d.End)
+			disruption.End = &end
+		}
+		result = append(result, disruption)
+	}
+	return result
+}
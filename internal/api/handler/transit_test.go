@@ -0,0 +1,23 @@
+package handler
+
+import "testing"
+
+func TestPreferredLocale(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"nl-NL", "nl-NL"},
+		{"de-DE, en-GB;q=0.8", "en-GB"},
+		{"en-US;q=0.5, nl;q=0.9", "nl"},
+		{"fr-FR, de;q=0.7", ""},
+		{"nl;q=bogus, en", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := preferredLocale(tt.header); got != tt.expected {
+			t.Errorf("preferredLocale(%q) = %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+}
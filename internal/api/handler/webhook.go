@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+	"github.com/breatheroute/breatheroute/internal/webhook"
+)
+
+// WebhookHandler handles webhook subscription endpoints.
+type WebhookHandler struct {
+	service *webhook.Service
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(service *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// ListWebhooks handles GET /v1/me/webhooks - list registered webhooks.
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeWebhooksUnavailable, "webhooks are not available")
+		return
+	}
+
+	webhooks, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to list webhooks")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, webhooks)
+}
+
+// CreateWebhook handles POST /v1/me/webhooks - register a webhook endpoint.
+// The response includes the signing secret, which is not returned again.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeWebhooksUnavailable, "webhooks are not available")
+		return
+	}
+
+	var input models.WebhookCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, r, models.ErrorCodeInvalidJSON, "invalid JSON body", nil)
+		return
+	}
+
+	if input.URL == "" {
+		response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{
+			{Field: "url", Message: "is required"},
+		})
+		return
+	}
+
+	result, err := h.service.Register(r.Context(), userID, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, webhook.ErrInvalidURL):
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{
+				{Field: "url", Message: "must be an absolute https URL"},
+			})
+		case errors.Is(err, webhook.ErrPrivateAddress):
+			response.BadRequest(w, r, models.ErrorCodeValidationFailed, "validation failed", []models.FieldError{
+				{Field: "url", Message: "must not point to a private or internal address"},
+			})
+		case errors.Is(err, webhook.ErrLimitReached):
+			response.Conflict(w, r, models.ErrorCodeWebhookLimitReached, "webhook limit reached")
+		default:
+			response.InternalError(w, r, models.ErrorCodeInternal, "failed to register webhook")
+		}
+		return
+	}
+
+	response.Created(w, fmt.Sprintf("/v1/me/webhooks/%s", result.ID), result)
+}
+
+// DeleteWebhook handles DELETE /v1/me/webhooks/{webhookId} - remove a webhook.
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+		return
+	}
+
+	if h.service == nil {
+		response.ServiceUnavailable(w, r, models.ErrorCodeWebhooksUnavailable, "webhooks are not available")
+		return
+	}
+
+	webhookID := chi.URLParam(r, "webhookId")
+	if err := h.service.Delete(r.Context(), userID, webhookID); err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			response.NotFound(w, r, models.ErrorCodeWebhookNotFound, "webhook not found")
+			return
+		}
+		response.InternalError(w, r, models.ErrorCodeInternal, "failed to delete webhook")
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/breatheroute/breatheroute/internal/audit"
+)
+
+// AuditRecorder records audit entries without failing the request.
+// *audit.Service implements it.
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *audit.Entry)
+}
+
+// Audit records an audit entry for each successful POST, PUT, PATCH or DELETE
+// by an authenticated user on resources of resourceType. It must run after
+// Auth. POST responses with 201 Created are recorded as creates, DELETE as
+// deletes and everything else as updates.
+//
+// The resource ID is taken from the response's Location header, then from the
+// last URL parameter (e.g. {commuteId}), and otherwise is the user ID, for
+// resources such as the profile that have no ID of their own.
+func Audit(recorder AuditRecorder, resourceType string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if recorder == nil || !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := newResponseWriter(w)
+			next.ServeHTTP(rw, r)
+
+			userID := GetUserID(r.Context())
+			if userID == "" || rw.statusCode < 200 || rw.statusCode > 299 {
+				return
+			}
+
+			recorder.Record(r.Context(), &audit.Entry{
+				UserID:       userID,
+				Action:       auditAction(r.Method, rw.statusCode),
+				ResourceType: resourceType,
+				ResourceID:   auditResourceID(r, rw.Header(), userID),
+				RequestID:    GetRequestID(r.Context()),
+			})
+		})
+	}
+}
+
+// isMutating reports whether method changes server state.
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditAction maps a successful request to the action it performed.
+func auditAction(method string, status int) audit.Action {
+	switch {
+	case method == http.MethodDelete:
+		return audit.ActionDelete
+	case method == http.MethodPost && status == http.StatusCreated:
+		return audit.ActionCreate
+	default:
+		return audit.ActionUpdate
+	}
+}
+
+// auditResourceID returns the ID of the resource a request changed.
+func auditResourceID(r *http.Request, header http.Header, userID string) string {
+	if location := header.Get("Location"); location != "" {
+		return path.Base(location)
+	}
+	// The route context is filled in as chi matches, so after the handler
+	// has run it holds the parameters of nested routes too. The "*" entries
+	// are the paths left over for mounted subrouters, not parameters.
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		params := rctx.URLParams
+		for i := len(params.Keys) - 1; i >= 0; i-- {
+			if params.Keys[i] != "*" && params.Values[i] != "" {
+				return params.Values[i]
+			}
+		}
+	}
+	return userID
+}
@@ -13,6 +13,9 @@ import (
 // userIDKey is the context key for the authenticated user ID.
 type userIDKey struct{}
 
+// roleKey is the context key for the authenticated user's role.
+type roleKey struct{}
+
 // Auth creates authentication middleware that validates JWT bearer tokens.
 func Auth(authService *auth.Service) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -20,7 +23,7 @@ func Auth(authService *auth.Service) func(http.Handler) http.Handler {
 			// Extract bearer token from Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				writeUnauthorized(w, r, "missing authorization header")
+				writeUnauthorized(w, r, models.ErrorCodeUnauthorized, "missing authorization header")
 				return
 			}
 
@@ -28,42 +31,60 @@ func Auth(authService *auth.Service) func(http.Handler) http.Handler {
 			const bearerPrefix = "Bearer "
 			if len(authHeader) < len(bearerPrefix) ||
 				!strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
-				writeUnauthorized(w, r, "invalid authorization header format")
+				writeUnauthorized(w, r, models.ErrorCodeInvalidAccessToken, "invalid authorization header format")
 				return
 			}
 
 			tokenString := authHeader[len(bearerPrefix):]
 			if tokenString == "" {
-				writeUnauthorized(w, r, "missing bearer token")
+				writeUnauthorized(w, r, models.ErrorCodeUnauthorized, "missing bearer token")
 				return
 			}
 
 			// Validate the token
-			userID, err := authService.ValidateAccessToken(tokenString)
+			claims, err := authService.ValidateAccessTokenClaims(tokenString)
 			if err != nil {
 				switch {
 				case errors.Is(err, auth.ErrAccessTokenExpired):
-					writeUnauthorized(w, r, "access token has expired")
+					writeUnauthorized(w, r, models.ErrorCodeAccessTokenExpired, "access token has expired")
 				case errors.Is(err, auth.ErrInvalidAccessToken):
-					writeUnauthorized(w, r, "invalid access token")
+					writeUnauthorized(w, r, models.ErrorCodeInvalidAccessToken, "invalid access token")
 				default:
-					writeUnauthorized(w, r, "authentication failed")
+					writeUnauthorized(w, r, models.ErrorCodeInvalidAccessToken, "authentication failed")
 				}
 				return
 			}
 
-			// Add user ID to context
-			ctx := context.WithValue(r.Context(), userIDKey{}, userID)
+			// Add user ID and role to context
+			ctx := context.WithValue(r.Context(), userIDKey{}, claims.UserID)
+			ctx = context.WithValue(ctx, roleKey{}, claims.Role)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// OptionalAuth authenticates requests that send a bearer token, like Auth,
+// and passes requests without an Authorization header through anonymously.
+// Invalid tokens are still rejected, so clients are not silently downgraded.
+func OptionalAuth(authService *auth.Service) func(http.Handler) http.Handler {
+	authenticate := Auth(authService)
+	return func(next http.Handler) http.Handler {
+		authenticated := authenticate(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
 // writeUnauthorized writes a 401 Unauthorized response.
 // This is implemented directly here to avoid import cycle with response package.
-func writeUnauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+func writeUnauthorized(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := GetRequestID(r.Context())
-	problem := models.NewUnauthorized(traceID, detail)
+	problem := models.NewUnauthorized(traceID, detail).WithCode(code)
 	problem.Instance = r.URL.Path
 	problem.Write(w)
 }
@@ -76,3 +97,32 @@ func GetUserID(ctx context.Context) string {
 	}
 	return ""
 }
+
+// GetRole retrieves the authenticated user's role from the context.
+// Returns an empty role if not authenticated.
+func GetRole(ctx context.Context) auth.Role {
+	if role, ok := ctx.Value(roleKey{}).(auth.Role); ok {
+		return role
+	}
+	return ""
+}
+
+// RequireRole restricts a route to users with the given role. It must run
+// after Auth; authenticated users without the role receive 403 Forbidden.
+func RequireRole(role auth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if GetUserID(r.Context()) == "" {
+				writeUnauthorized(w, r, models.ErrorCodeUnauthorized, "authentication required")
+				return
+			}
+			if GetRole(r.Context()) != role {
+				problem := models.NewForbidden(GetRequestID(r.Context()), "insufficient privileges")
+				problem.Instance = r.URL.Path
+				problem.Write(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// DefaultMaxJSONDepth is the default maximum nesting depth of JSON request bodies.
+const DefaultMaxJSONDepth = 32
+
+// BodyLimitConfig holds configuration for request body limits.
+type BodyLimitConfig struct {
+	// MaxBytes is the largest accepted request body.
+	MaxBytes int64
+
+	// MaxDepth is the deepest accepted nesting of JSON objects and arrays
+	// (default: DefaultMaxJSONDepth).
+	MaxDepth int
+
+	// DisallowUnknownFields makes DecodeJSON reject fields the target type
+	// does not declare.
+	DisallowUnknownFields bool
+}
+
+// Default body limit configurations.
+var (
+	// SmallBodyLimit applies to small settings and auth payloads (16 KiB).
+	SmallBodyLimit = BodyLimitConfig{
+		MaxBytes: 16 << 10,
+	}
+
+	// StrictSmallBodyLimit is SmallBodyLimit rejecting unknown fields, for
+	// closed schemas where an unknown field is a client bug (16 KiB).
+	StrictSmallBodyLimit = BodyLimitConfig{
+		MaxBytes:              16 << 10,
+		DisallowUnknownFields: true,
+	}
+
+	// StandardBodyLimit applies to regular API payloads (64 KiB).
+	StandardBodyLimit = BodyLimitConfig{
+		MaxBytes: 64 << 10,
+	}
+
+	// LargeBodyLimit applies to bulk payloads such as GDPR requests (1 MiB).
+	LargeBodyLimit = BodyLimitConfig{
+		MaxBytes: 1 << 20,
+	}
+)
+
+type strictJSONKey struct{}
+
+// LimitBody rejects request bodies larger than cfg.MaxBytes with 413 and
+// JSON bodies nested deeper than cfg.MaxDepth with 400. The body is read
+// up front (at most MaxBytes) so both limits apply before the handler runs.
+func LimitBody(cfg BodyLimitConfig) func(http.Handler) http.Handler {
+	maxDepth := cfg.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxJSONDepth
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.DisallowUnknownFields {
+				r = r.WithContext(context.WithValue(r.Context(), strictJSONKey{}, true))
+			}
+
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > cfg.MaxBytes {
+				writePayloadTooLarge(w, r, cfg.MaxBytes)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, cfg.MaxBytes))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					writePayloadTooLarge(w, r, cfg.MaxBytes)
+					return
+				}
+				writeBodyProblem(w, r, models.ErrorCodeInvalidBody, "failed to read request body")
+				return
+			}
+
+			if isJSONRequest(r) && jsonDepthExceeds(body, maxDepth) {
+				writeBodyProblem(w, r, models.ErrorCodeBodyTooDeep, fmt.Sprintf("JSON body is nested deeper than %d levels", maxDepth))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DecodeJSON decodes the request body into v. Unknown fields are rejected
+// when the route's body limit sets DisallowUnknownFields.
+func DecodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	if strict, _ := r.Context().Value(strictJSONKey{}).(bool); strict {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(v)
+}
+
+// isJSONRequest reports whether the request body should be treated as JSON.
+// Requests without a Content-Type are assumed to be JSON, as in RequireJSON.
+func isJSONRequest(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return contentType == "" || strings.HasPrefix(contentType, "application/json")
+}
+
+// jsonDepthExceeds reports whether JSON data nests objects and arrays deeper
+// than max. It only tracks brackets outside strings; syntax errors are left
+// for the decoder to report.
+func jsonDepthExceeds(data []byte, max int) bool {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > max {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return false
+}
+
+// writePayloadTooLarge writes an RFC7807 Problem response for an oversized body.
+func writePayloadTooLarge(w http.ResponseWriter, r *http.Request, maxBytes int64) {
+	problem := models.NewPayloadTooLarge(GetRequestID(r.Context()),
+		fmt.Sprintf("request body exceeds the %d byte limit", maxBytes))
+	problem.Instance = r.URL.Path
+	problem.Write(w)
+}
+
+// writeBodyProblem writes an RFC7807 Problem response for a rejected body.
+func writeBodyProblem(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
+	problem := models.NewBadRequest(GetRequestID(r.Context()), detail, nil).WithCode(code)
+	problem.Instance = r.URL.Path
+	problem.Write(w)
+}
@@ -0,0 +1,141 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+)
+
+func TestLimitBody_PassesBodyWithinLimit(t *testing.T) {
+	var received string
+	handler := middleware.LimitBody(middleware.BodyLimitConfig{MaxBytes: 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"ok"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"name":"ok"}`, received)
+}
+
+func TestLimitBody_RejectsOversizedBody(t *testing.T) {
+	handler := middleware.LimitBody(middleware.BodyLimitConfig{MaxBytes: 16})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	tests := []struct {
+		name          string
+		contentLength int64
+	}{
+		{name: "declared content length", contentLength: 32},
+		{name: "unknown content length", contentLength: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"`+strings.Repeat("x", 22)+`"}`))
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Body.String(), "16 byte limit")
+		})
+	}
+}
+
+func TestLimitBody_RejectsDeeplyNestedJSON(t *testing.T) {
+	handler := middleware.LimitBody(middleware.BodyLimitConfig{MaxBytes: 1024, MaxDepth: 3})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "within depth", body: `{"a":{"b":[1]}}`, wantStatus: http.StatusOK},
+		{name: "too deep", body: `{"a":{"b":[[1]]}}`, wantStatus: http.StatusBadRequest},
+		{name: "brackets inside strings", body: `{"a":"[[[[{{{{\"]]]]"}`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestLimitBody_SkipsDepthCheckForNonJSON(t *testing.T) {
+	handler := middleware.LimitBody(middleware.BodyLimitConfig{MaxBytes: 1024, MaxDepth: 1})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("[[[[text]]]]"))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestDecodeJSON_UnknownFields(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name    string
+		cfg     middleware.BodyLimitConfig
+		wantErr bool
+	}{
+		{name: "lenient", cfg: middleware.SmallBodyLimit, wantErr: false},
+		{name: "strict", cfg: middleware.StrictSmallBodyLimit, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decodeErr error
+			handler := middleware.LimitBody(tt.cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var p payload
+				decodeErr = middleware.DecodeJSON(r, &p)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"a","extra":true}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if tt.wantErr {
+				assert.Error(t, decodeErr)
+			} else {
+				assert.NoError(t, decodeErr)
+			}
+		})
+	}
+}
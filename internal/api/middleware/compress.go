@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressMinSize is the smallest response body that is compressed. Smaller
+// bodies are sent as-is, since compression would save little or even grow them.
+const CompressMinSize = 1024
+
+// Content encodings supported by Compress.
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// compressibleTypes are the media types worth compressing. Event streams are
+// excluded so that each event reaches the client as soon as it is flushed.
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/problem+json": true,
+	"application/geo+json":     true,
+	"application/gpx+xml":      true,
+	"text/plain":               true,
+	"text/html":                true,
+	"text/csv":                 true,
+}
+
+var (
+	gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	zlibWriters = sync.Pool{New: func() any { return zlib.NewWriter(io.Discard) }}
+)
+
+// Compress compresses response bodies with gzip or deflate, whichever the
+// client's Accept-Encoding header prefers (gzip on a tie). Only bodies of at
+// least CompressMinSize bytes with a compressible Content-Type are compressed.
+// Vary: Accept-Encoding is always set so caches keep the variants apart.
+//
+// A compressed response's strong ETag is made weak, since its bytes differ
+// from the identity encoding. If-Match on commutes accepts weak validators,
+// so conditional updates keep working. Place Compress inside RequestID and
+// Logger so that compressed responses keep their request ID and the logged
+// size is the bytes actually sent.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the supported encoding the Accept-Encoding header
+// prefers, or "" if neither is acceptable.
+func negotiateEncoding(header string) string {
+	var gzipQ, deflateQ, wildcardQ float64 = -1, -1, -1
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case encodingGzip:
+			gzipQ = q
+		case encodingDeflate:
+			deflateQ = q
+		case "*":
+			wildcardQ = q
+		}
+	}
+
+	// The wildcard covers encodings not listed explicitly
+	if gzipQ < 0 {
+		gzipQ = wildcardQ
+	}
+	if deflateQ < 0 {
+		deflateQ = wildcardQ
+	}
+
+	switch {
+	case gzipQ > 0 && gzipQ >= deflateQ:
+		return encodingGzip
+	case deflateQ > 0:
+		return encodingDeflate
+	default:
+		return ""
+	}
+}
+
+// compressWriter buffers the start of a response until it knows whether the
+// body is worth compressing, then either compresses it or passes it through.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+
+	buf         []byte
+	decided     bool
+	wroteHeader bool
+	encoder     io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+
+	// Bodiless responses are never compressed
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		cw.passThrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	cw.wroteHeader = true
+
+	if !cw.decided {
+		if !cw.compressible() {
+			cw.passThrough()
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) < CompressMinSize {
+				return len(p), nil
+			}
+			if err := cw.startCompression(); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends any buffered bytes to the client. A response that is still
+// undecided is sent uncompressed.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.passThrough()
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Close finishes the response, sending a small buffered body uncompressed.
+func (cw *compressWriter) Close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			return
+		}
+		cw.passThrough()
+	}
+	if cw.encoder == nil {
+		return
+	}
+
+	_ = cw.encoder.Close()
+	switch e := cw.encoder.(type) {
+	case *gzip.Writer:
+		gzipWriters.Put(e)
+	case *zlib.Writer:
+		zlibWriters.Put(e)
+	}
+	cw.encoder = nil
+}
+
+// Unwrap returns the underlying ResponseWriter so http.ResponseController
+// can reach optional interfaces such as http.Hijacker.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether the response headers allow compression.
+func (cw *compressWriter) compressible() bool {
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && compressibleTypes[mediaType]
+}
+
+// passThrough sends the status and any buffered bytes without compression.
+func (cw *compressWriter) passThrough() {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buf)
+		cw.buf = nil
+	}
+}
+
+// startCompression sends the compressed headers and status, then the buffered
+// bytes through the encoder.
+func (cw *compressWriter) startCompression() error {
+	cw.decided = true
+
+	header := cw.Header()
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.encoding {
+	case encodingGzip:
+		gw := gzipWriters.Get().(*gzip.Writer)
+		gw.Reset(cw.ResponseWriter)
+		cw.encoder = gw
+	default:
+		zw := zlibWriters.Get().(*zlib.Writer)
+		zw.Reset(cw.ResponseWriter)
+		cw.encoder = zw
+	}
+
+	_, err := cw.encoder.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
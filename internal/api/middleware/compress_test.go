@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/response"
+)
+
+var largeBody = `{"stations":"` + strings.Repeat("Amsterdam Centraal ", 200) + `"}`
+
+func jsonHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"3"`)
+		_, _ = io.WriteString(w, body)
+	})
+}
+
+func serveCompressed(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	middleware.Compress(h).ServeHTTP(w, req)
+	return w
+}
+
+func TestCompress_Gzip(t *testing.T) {
+	w := serveCompressed(jsonHandler(largeBody), "gzip, deflate, br")
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, `W/"3"`, w.Header().Get("ETag"))
+	assert.Less(t, w.Body.Len(), len(largeBody))
+
+	gr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, largeBody, string(body))
+}
+
+func TestCompress_Deflate(t *testing.T) {
+	w := serveCompressed(jsonHandler(largeBody), "gzip;q=0.5, deflate")
+
+	assert.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+
+	zr, err := zlib.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, largeBody, string(body))
+}
+
+func TestCompress_Negotiation(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"br", ""},
+		{"gzip;q=0", ""},
+		{"*", "gzip"},
+		{"deflate, gzip", "gzip"},
+		{"*;q=0.5, deflate", "deflate"},
+		{"gzip;q=0, *", "deflate"},
+		{"GZIP", "gzip"},
+	}
+
+	for _, tt := range tests {
+		w := serveCompressed(jsonHandler(largeBody), tt.header)
+		assert.Equal(t, tt.expected, w.Header().Get("Content-Encoding"), "Accept-Encoding %q", tt.header)
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"), "Accept-Encoding %q", tt.header)
+	}
+}
+
+func TestCompress_SkipsSmallResponses(t *testing.T) {
+	w := serveCompressed(jsonHandler(`{"status":"ok"}`), "gzip")
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestCompress_SkipsNonCompressibleContent(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = io.WriteString(w, largeBody)
+	})
+	w := serveCompressed(h, "gzip")
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, largeBody, w.Body.String())
+}
+
+func TestCompress_PreservesStatus(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, largeBody)
+	})
+	w := serveCompressed(h, "gzip")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	noContent := serveCompressed(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), "gzip")
+	assert.Equal(t, http.StatusNoContent, noContent.Code)
+	assert.Empty(t, noContent.Header().Get("Content-Encoding"))
+}
+
+func TestCompress_EventStreamIsFlushedUncompressed(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream := response.NewEventStream(w)
+		require.NoError(t, stream.Send("option", map[string]string{"id": "opt_1"}))
+	})
+	w := serveCompressed(h, "gzip")
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, w.Flushed)
+	assert.Contains(t, w.Body.String(), "event: option")
+}
+
+func TestCompress_KeepsRequestID(t *testing.T) {
+	h := middleware.RequestID(middleware.Compress(jsonHandler(largeBody)))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.NotEmpty(t, w.Header().Get("X-Request-Id"))
+}
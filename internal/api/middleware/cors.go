@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// CORSConfig holds configuration for cross-origin requests.
+type CORSConfig struct {
+	// AllowedOrigins are the origins (e.g. "https://app.breatheroute.nl")
+	// allowed to call the API from a browser. "*" allows any origin but never
+	// with credentials. With none, every cross-origin request is refused.
+	AllowedOrigins []string
+
+	// AllowedMethods are the methods a preflight may request (default:
+	// DefaultCORSMethods).
+	AllowedMethods []string
+
+	// AllowedHeaders are the request headers a preflight may request
+	// (default: DefaultCORSHeaders).
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers readable by the calling page
+	// (default: DefaultCORSExposedHeaders).
+	ExposedHeaders []string
+
+	// AllowCredentials lets pages send cookies and Authorization headers.
+	AllowCredentials bool
+
+	// MaxAgeSeconds is how long browsers may cache a preflight result
+	// (default: DefaultCORSMaxAge).
+	MaxAgeSeconds int
+}
+
+// Default CORS settings.
+var (
+	// DefaultCORSMethods are the methods used by the API.
+	DefaultCORSMethods = []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}
+
+	// DefaultCORSHeaders are the request headers the API reads.
+	DefaultCORSHeaders = []string{
+		"Accept", "Accept-Language", "Authorization", "Content-Type", "If-Match", "X-Request-Id", "traceparent",
+	}
+
+	// DefaultCORSExposedHeaders are the response headers clients rely on.
+	DefaultCORSExposedHeaders = []string{
+		"ETag", "Location", "Retry-After", "X-Request-Id",
+	}
+)
+
+// DefaultCORSMaxAge is the default preflight cache lifetime (10 minutes).
+const DefaultCORSMaxAge = 600
+
+// CORS returns middleware that answers preflight requests and adds CORS
+// headers for allowed origins. It must run before routing so preflights for
+// authenticated routes are answered without credentials, and after RequestID
+// so refused preflights carry a trace ID.
+//
+// Requests without an Origin header, such as those from native apps, pass
+// through unchanged. Requests from other origins pass through without CORS
+// headers, so browsers block the response; their preflights are refused with
+// 403.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
+	}
+	exposed := cfg.ExposedHeaders
+	if len(exposed) == 0 {
+		exposed = DefaultCORSExposedHeaders
+	}
+	maxAge := cfg.MaxAgeSeconds
+	if maxAge <= 0 {
+		maxAge = DefaultCORSMaxAge
+	}
+
+	anyOrigin := false
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+			continue
+		}
+		origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	allowedMethods := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		allowedMethods[strings.ToUpper(m)] = true
+	}
+	allowedHeaders := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		allowedHeaders[strings.ToLower(h)] = true
+	}
+
+	methodList := strings.Join(methods, ", ")
+	headerList := strings.Join(headers, ", ")
+	exposedList := strings.Join(exposed, ", ")
+	maxAgeValue := strconv.Itoa(maxAge)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The response depends on the Origin, so caches must key on it
+			w.Header().Add("Vary", "Origin")
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			allowed := origins[strings.ToLower(origin)]
+			if !allowed && !anyOrigin {
+				if preflight {
+					writeCORSRefused(w, r, "origin not allowed")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowed && cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			} else if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+
+			if !preflight {
+				w.Header().Set("Access-Control-Expose-Headers", exposedList)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if !allowedMethods[strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))] {
+				writeCORSRefused(w, r, "method not allowed")
+				return
+			}
+			for _, h := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+				if h = strings.TrimSpace(h); h != "" && !allowedHeaders[strings.ToLower(h)] {
+					writeCORSRefused(w, r, "header "+h+" not allowed")
+					return
+				}
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", methodList)
+			w.Header().Set("Access-Control-Allow-Headers", headerList)
+			w.Header().Set("Access-Control-Max-Age", maxAgeValue)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// writeCORSRefused writes a 403 problem for a refused preflight request.
+func writeCORSRefused(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Del("Access-Control-Allow-Origin")
+	w.Header().Del("Access-Control-Allow-Credentials")
+
+	models.NewForbidden(GetRequestID(r.Context()), detail).
+		WithInstance(r.URL.Path).
+		Write(w)
+}
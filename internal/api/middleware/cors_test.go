@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+)
+
+func corsHandler(cfg middleware.CORSConfig) http.Handler {
+	return middleware.CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func preflight(origin, method, headers string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/v1/me", http.NoBody)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func TestCORS_DefaultRefusesCrossOrigin(t *testing.T) {
+	handler := corsHandler(middleware.CORSConfig{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight("https://evil.example", http.MethodGet, ""))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Simple requests are served, but browsers hide the response
+	req := httptest.NewRequest(http.MethodGet, "/v1/me", http.NoBody)
+	req.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+}
+
+func TestCORS_NoOriginPassesThrough(t *testing.T) {
+	handler := corsHandler(middleware.CORSConfig{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/me", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Vary"))
+}
+
+func TestCORS_AllowedOrigin(t *testing.T) {
+	handler := corsHandler(middleware.CORSConfig{
+		AllowedOrigins:   []string{"https://app.breatheroute.nl/"},
+		AllowCredentials: true,
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight("https://app.breatheroute.nl", http.MethodPatch, "authorization, content-type"))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.breatheroute.nl", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPatch)
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/me", http.NoBody)
+	req.Header.Set("Origin", "https://app.breatheroute.nl")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://app.breatheroute.nl", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "X-Request-Id")
+}
+
+func TestCORS_PreflightRefusesUnlistedMethodAndHeader(t *testing.T) {
+	handler := corsHandler(middleware.CORSConfig{AllowedOrigins: []string{"https://app.breatheroute.nl"}})
+
+	for _, req := range []*http.Request{
+		preflight("https://app.breatheroute.nl", "TRACE", ""),
+		preflight("https://app.breatheroute.nl", http.MethodGet, "X-Api-Key"),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCORS_WildcardNeverAllowsCredentials(t *testing.T) {
+	handler := corsHandler(middleware.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight("https://any.example", http.MethodGet, ""))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -5,7 +5,8 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
-	"go.opentelemetry.io/otel/trace"
+
+	"github.com/breatheroute/breatheroute/internal/telemetry"
 )
 
 // responseWriter wraps http.ResponseWriter to capture the status code.
@@ -30,6 +31,12 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter so http.ResponseController
+// can reach optional interfaces such as http.Flusher.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logger returns a middleware that logs HTTP requests.
 func Logger(log zerolog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -40,23 +47,9 @@ func Logger(log zerolog.Logger) func(http.Handler) http.Handler {
 			// Process request
 			next.ServeHTTP(wrapped, r)
 
-			// Log request
+			// Log request with the same request and trace IDs as service logs
 			duration := time.Since(start)
-			requestID := GetRequestID(r.Context())
-
-			// Extract trace ID from span context
-			spanCtx := trace.SpanContextFromContext(r.Context())
-			traceID := ""
-			spanID := ""
-			if spanCtx.IsValid() {
-				traceID = spanCtx.TraceID().String()
-				spanID = spanCtx.SpanID().String()
-			}
-
-			log.Info().
-				Str("request_id", requestID).
-				Str("trace_id", traceID).
-				Str("span_id", spanID).
+			telemetry.Logger(r.Context(), log).Info().
 				Str("method", r.Method).
 				Str("path", r.URL.Path).
 				Int("status", wrapped.statusCode).
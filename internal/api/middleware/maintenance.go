@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// MaintenanceRetryAfter is how long clients are told to wait before retrying
+// a write rejected in maintenance mode.
+const MaintenanceRetryAfter = 5 * time.Minute
+
+// MaintenanceCheck reports whether the API is in maintenance mode, such as
+// featureflags.Service.IsMaintenanceMode.
+type MaintenanceCheck func(ctx context.Context) bool
+
+// Maintenance rejects writes with 503 and a Retry-After header while check
+// reports maintenance mode. Safe methods (GET, HEAD, OPTIONS) and paths
+// starting with one of exempt always pass, so reads keep working and
+// operators can still reach the endpoints they need to end maintenance.
+// The check runs only for writes.
+func Maintenance(check MaintenanceCheck, exempt ...string) func(http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(int(MaintenanceRetryAfter.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if check == nil || isSafeMethod(r.Method) || hasAnyPrefix(r.URL.Path, exempt) || !check(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", retryAfter)
+			problem := models.NewServiceUnavailable(GetRequestID(r.Context()),
+				"The API is in maintenance mode; changes are temporarily disabled")
+			problem.Code = models.ErrorCodeMaintenanceMode
+			problem.Instance = r.URL.Path
+			problem.Write(w)
+		})
+	}
+}
+
+// isSafeMethod reports whether method only reads.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasAnyPrefix reports whether path starts with any of prefixes.
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -130,6 +130,12 @@ func (rw *metricsResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter so http.ResponseController
+// can reach optional interfaces such as http.Flusher.
+func (rw *metricsResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // ProviderMetrics holds metrics for external provider calls.
 type ProviderMetrics struct {
 	requestDuration metric.Float64Histogram
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/breatheroute/breatheroute/internal/database"
+)
+
+// ReplicaReads lets GET and HEAD requests read from the database read
+// replica, when one is configured. Other requests read from the primary, so
+// a create followed by a get in the same request sees the new row.
+func ReplicaReads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			r = r.WithContext(database.WithReplicaReads(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/database"
+)
+
+func TestReplicaReads(t *testing.T) {
+	tests := []struct {
+		method     string
+		useReplica bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodHead, true},
+		{http.MethodPost, false},
+		{http.MethodPut, false},
+		{http.MethodDelete, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			var useReplica bool
+			handler := middleware.ReplicaReads(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				useReplica = database.UseReplica(r.Context())
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, "/v1/me/commutes", nil))
+
+			assert.Equal(t, tt.useReplica, useReplica)
+		})
+	}
+}
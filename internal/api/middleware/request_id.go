@@ -6,13 +6,13 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
-)
 
-// requestIDKey is the context key for the request ID.
-type requestIDKey struct{}
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+)
 
-// RequestID generates a unique request ID and adds it to the request context.
-// The ID is also set in the X-Request-Id response header.
+// RequestID generates a unique request ID and adds it to the request context,
+// where services read it through telemetry.Logger. The ID is also set in the
+// X-Request-Id response header.
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if request already has an ID
@@ -26,15 +26,11 @@ func RequestID(next http.Handler) http.Handler {
 		w.Header().Set("X-Request-Id", requestID)
 
 		// Add to context
-		ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(telemetry.WithRequestID(r.Context(), requestID)))
 	})
 }
 
 // GetRequestID retrieves the request ID from the context.
 func GetRequestID(ctx context.Context) string {
-	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
-		return id
-	}
-	return ""
+	return telemetry.RequestID(ctx)
 }
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Default request timeouts. Both stay below the server's 15s WriteTimeout so
+// a 504 can still be written before the connection is cut.
+const (
+	// StandardTimeout applies to regular API requests.
+	StandardTimeout = 8 * time.Second
+
+	// ExpensiveTimeout applies to endpoints that call several upstream
+	// providers, such as route computation and grid interpolation.
+	ExpensiveTimeout = 12 * time.Second
+)
+
+// Timeout gives each request a context deadline of timeout. If the deadline
+// passes before the handler starts its response, the client receives a 504
+// problem+json at once, and anything the handler writes afterwards is
+// discarded. A response already in progress (such as an event stream) is
+// left alone, but its context is still cancelled.
+//
+// Handlers and provider calls must honor ctx cancellation for the request to
+// actually stop; Timeout waits for the handler to return either way.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{w: w, r: r, header: w.Header().Clone()}
+			stop := context.AfterFunc(ctx, tw.timeout)
+			defer func() {
+				// Also on panic, so the callback cannot race Recovery
+				stop()
+				tw.close()
+			}()
+
+			next.ServeHTTP(tw, r)
+			tw.finish()
+		})
+	}
+}
+
+// timeoutWriter serializes writes from the handler and the deadline callback.
+// The handler gets its own header map so the callback can write the 504
+// without racing the handler's header changes.
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+	done        bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(code)
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.writeHeader(http.StatusOK)
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return tw.w.Write(p)
+}
+
+// Flush sends buffered data to the client, unless the request timed out.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.writeHeader(http.StatusOK)
+	if !tw.timedOut {
+		_ = http.NewResponseController(tw.w).Flush()
+	}
+}
+
+// writeHeader starts the handler's response. A response started after the
+// deadline becomes the 504 instead. Caller must hold tw.mu.
+func (tw *timeoutWriter) writeHeader(code int) {
+	if tw.wroteHeader {
+		return
+	}
+	if errors.Is(tw.r.Context().Err(), context.DeadlineExceeded) {
+		tw.writeTimeout()
+		return
+	}
+
+	tw.wroteHeader = true
+	dst := tw.w.Header()
+	clear(dst)
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	tw.w.WriteHeader(code)
+}
+
+// timeout runs when the request context ends. It writes the 504 if the
+// deadline passed before the handler started its response.
+func (tw *timeoutWriter) timeout() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.done || tw.wroteHeader || !errors.Is(tw.r.Context().Err(), context.DeadlineExceeded) {
+		return
+	}
+	tw.writeTimeout()
+	_ = http.NewResponseController(tw.w).Flush()
+}
+
+// finish sends the handler's headers if it returned without writing, or the
+// 504 if it gave up on the deadline without responding.
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(http.StatusOK)
+}
+
+// close marks the handler as returned; later timeout callbacks do nothing.
+func (tw *timeoutWriter) close() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.done = true
+}
+
+// writeTimeout writes the 504 problem. Caller must hold tw.mu.
+func (tw *timeoutWriter) writeTimeout() {
+	tw.wroteHeader = true
+	tw.timedOut = true
+
+	problem := models.NewGatewayTimeout(GetRequestID(tw.r.Context()), "the request took too long to complete")
+	problem.Instance = tw.r.URL.Path
+	problem.Write(tw.w)
+}
@@ -0,0 +1,131 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+func serveWithTimeout(timeout time.Duration, h http.Handler) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/routes:compute", http.NoBody)
+	w := httptest.NewRecorder()
+	middleware.RequestID(middleware.Timeout(timeout)(h)).ServeHTTP(w, req)
+	return w
+}
+
+func TestTimeout_FastHandler(t *testing.T) {
+	w := serveWithTimeout(time.Second, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.NotEmpty(t, w.Header().Get("X-Request-Id"))
+	assert.Equal(t, `{"ok":true}`, w.Body.String())
+}
+
+func TestTimeout_HeadersWithoutBody(t *testing.T) {
+	w := serveWithTimeout(time.Second, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+	}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
+func TestTimeout_SlowHandlerGets504(t *testing.T) {
+	var writeErr error
+	w := serveWithTimeout(20*time.Millisecond, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A provider call that honors cancellation
+		<-r.Context().Done()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, writeErr = io.WriteString(w, `{"late":true}`)
+	}))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.ErrorIs(t, writeErr, http.ErrHandlerTimeout)
+
+	var problem models.Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, models.ErrorCodeTimeout, problem.Code)
+	assert.Equal(t, http.StatusGatewayTimeout, problem.Status)
+	assert.Equal(t, "/v1/routes:compute", problem.Instance)
+	assert.Equal(t, w.Header().Get("X-Request-Id"), problem.TraceID)
+}
+
+func TestTimeout_HandlerReturnsSilently(t *testing.T) {
+	w := serveWithTimeout(20*time.Millisecond, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestTimeout_StartedResponseIsKept(t *testing.T) {
+	w := serveWithTimeout(20*time.Millisecond, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: option\n\n")
+		http.NewResponseController(w).Flush()
+
+		<-r.Context().Done()
+		_, _ = io.WriteString(w, "event: done\n\n")
+	}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "event: option\n\nevent: done\n\n", w.Body.String())
+}
+
+func TestTimeout_RespondsBeforeHandlerReturns(t *testing.T) {
+	release := make(chan struct{})
+	responded := make(chan int, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", http.NoBody)
+	w := &notifyRecorder{ResponseRecorder: httptest.NewRecorder(), notify: responded}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		middleware.Timeout(20*time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Ignores cancellation, as a CPU-bound loop might
+			<-release
+		})).ServeHTTP(w, req)
+	}()
+
+	select {
+	case code := <-responded:
+		assert.Equal(t, http.StatusGatewayTimeout, code)
+	case <-time.After(time.Second):
+		t.Fatal("expected 504 to be written at the deadline")
+	}
+	close(release)
+	<-done
+}
+
+// notifyRecorder reports the status code once the response is flushed.
+type notifyRecorder struct {
+	*httptest.ResponseRecorder
+	notify chan int
+}
+
+func (r *notifyRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	r.notify <- r.Code
+}
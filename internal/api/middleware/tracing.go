@@ -91,6 +91,12 @@ func (rw *tracingResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter so http.ResponseController
+// can reach optional interfaces such as http.Flusher.
+func (rw *tracingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // scheme returns the request scheme.
 func scheme(r *http.Request) string {
 	if r.TLS != nil {
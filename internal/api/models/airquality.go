@@ -0,0 +1,70 @@
+package models
+
+// AirQualityGridRequest represents a request for an interpolated air quality grid.
+type AirQualityGridRequest struct {
+	BBox GeoBox `json:"bbox"`
+	// ResolutionDeg is the cell size in degrees.
+	ResolutionDeg float64 `json:"resolutionDeg"`
+}
+
+// AirQualityGridResponse represents an interpolated air quality grid for map overlays.
+// Cells are in row-major order starting at the south-west corner of the box;
+// a null cell has no monitoring stations within range.
+type AirQualityGridResponse struct {
+	SnapshotAt    Timestamp             `json:"snapshotAt"`
+	BBox          GeoBox                `json:"bbox"`
+	ResolutionDeg float64               `json:"resolutionDeg"`
+	Rows          int                   `json:"rows"`
+	Cols          int                   `json:"cols"`
+	Cells         []*AirQualityGridCell `json:"cells"`
+}
+
+// AirQualityGridCell represents interpolated air quality at the center of a grid cell.
+type AirQualityGridCell struct {
+	Point       Point                      `json:"point"`
+	AQICategory string                     `json:"aqiCategory"`
+	Confidence  Confidence                 `json:"confidence"`
+	Values      []AirQualityPollutantValue `json:"values"`
+}
+
+// AirQualityPollutantValue represents an interpolated pollutant concentration.
+type AirQualityPollutantValue struct {
+	Pollutant  Pollutant  `json:"pollutant"`
+	Value      float64    `json:"value"`
+	Unit       string     `json:"unit"`
+	Confidence Confidence `json:"confidence"`
+	Trend      Trend      `json:"trend"`
+	// DeltaLastHour is the change in value over the last hour, omitted when
+	// the trend is UNKNOWN.
+	DeltaLastHour *float64 `json:"deltaLastHour,omitempty"`
+}
+
+// Trend is the direction of a concentration over the last hour.
+type Trend string
+
+// Trend values.
+const (
+	TrendRising  Trend = "RISING"
+	TrendSteady  Trend = "STEADY"
+	TrendFalling Trend = "FALLING"
+	TrendUnknown Trend = "UNKNOWN"
+)
+
+// NearestStationResponse represents the monitoring station closest to a point.
+type NearestStationResponse struct {
+	Station        Station              `json:"station"`
+	DistanceMeters float64              `json:"distanceMeters"`
+	Measurements   []StationMeasurement `json:"measurements"`
+}
+
+// StationMeasurement represents a station's latest reading for one pollutant.
+type StationMeasurement struct {
+	Pollutant  Pollutant `json:"pollutant"`
+	Value      float64   `json:"value"`
+	Unit       string    `json:"unit"`
+	MeasuredAt Timestamp `json:"measuredAt"`
+	Trend      Trend     `json:"trend"`
+	// DeltaLastHour is the change in value over the last hour, omitted when
+	// the trend is UNKNOWN.
+	DeltaLastHour *float64 `json:"deltaLastHour,omitempty"`
+}
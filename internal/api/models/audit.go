@@ -0,0 +1,18 @@
+package models
+
+// AuditEntry is a recorded change to a user's resources.
+type AuditEntry struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	RequestID    string    `json:"requestId,omitempty"`
+	CreatedAt    Timestamp `json:"createdAt"`
+}
+
+// PagedAuditEntries represents a user's audit history, newest first.
+type PagedAuditEntries struct {
+	Items []AuditEntry      `json:"items"`
+	Meta  PagedResponseMeta `json:"meta"`
+}
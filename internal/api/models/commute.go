@@ -16,6 +16,10 @@ type CommuteSchedule struct {
 	ArrivalTime string `json:"arrivalTime"`
 	// Timezone is the IANA timezone identifier (e.g., "Europe/Amsterdam")
 	Timezone string `json:"timezone"`
+	// Exceptions contains YYYY-MM-DD dates on which the commute does not run
+	Exceptions []string `json:"exceptions"`
+	// SkipPublicHolidays indicates the commute does not run on Dutch public holidays
+	SkipPublicHolidays bool `json:"skipPublicHolidays"`
 	// NextOccurrence is the next scheduled commute time (if within 7 days), in RFC3339 format
 	NextOccurrence *string `json:"nextOccurrence,omitempty"`
 	// IsActiveToday indicates if the commute is scheduled for today
@@ -28,10 +32,13 @@ type Commute struct {
 	Label       string          `json:"label"`
 	Origin      CommuteLocation `json:"origin"`
 	Destination CommuteLocation `json:"destination"`
+	Waypoints   []Point         `json:"waypoints"`
 	Schedule    CommuteSchedule `json:"schedule"`
 	Notes       *string         `json:"notes,omitempty"`
-	CreatedAt   Timestamp       `json:"createdAt"`
-	UpdatedAt   Timestamp       `json:"updatedAt"`
+	// Version is incremented on each update and mirrored in the ETag header
+	Version   int64     `json:"version"`
+	CreatedAt Timestamp `json:"createdAt"`
+	UpdatedAt Timestamp `json:"updatedAt"`
 }
 
 // CommuteCreateRequest is the request body for creating a commute.
@@ -39,9 +46,12 @@ type CommuteCreateRequest struct {
 	Label                     string          `json:"label" validate:"required,min=1,max=80"`
 	Origin                    CommuteLocation `json:"origin" validate:"required"`
 	Destination               CommuteLocation `json:"destination" validate:"required"`
+	Waypoints                 []Point         `json:"waypoints,omitempty" validate:"omitempty,max=5"`
 	DaysOfWeek                []int           `json:"daysOfWeek" validate:"required,dive,gte=1,lte=7"`
 	PreferredArrivalTimeLocal string          `json:"preferredArrivalTimeLocal" validate:"required,time_hhmm"`
 	Timezone                  *string         `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Exceptions                []string        `json:"exceptions,omitempty" validate:"omitempty,dive,datetime=2006-01-02"`
+	SkipPublicHolidays        bool            `json:"skipPublicHolidays,omitempty"`
 	Notes                     *string         `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
@@ -50,9 +60,12 @@ type CommuteUpdateRequest struct {
 	Label                     *string          `json:"label,omitempty" validate:"omitempty,min=1,max=80"`
 	Origin                    *CommuteLocation `json:"origin,omitempty"`
 	Destination               *CommuteLocation `json:"destination,omitempty"`
+	Waypoints                 []Point          `json:"waypoints,omitempty" validate:"omitempty,max=5"`
 	DaysOfWeek                []int            `json:"daysOfWeek,omitempty" validate:"omitempty,dive,gte=1,lte=7"`
 	PreferredArrivalTimeLocal *string          `json:"preferredArrivalTimeLocal,omitempty" validate:"omitempty,time_hhmm"`
 	Timezone                  *string          `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Exceptions                []string         `json:"exceptions,omitempty" validate:"omitempty,dive,datetime=2006-01-02"`
+	SkipPublicHolidays        *bool            `json:"skipPublicHolidays,omitempty"`
 	Notes                     *string          `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
@@ -61,3 +74,41 @@ type PagedCommutes struct {
 	Items []Commute         `json:"items"`
 	Meta  PagedResponseMeta `json:"meta"`
 }
+
+// CommuteBestDay is the response for GET /v1/me/commutes/{commuteId}/best-day,
+// ranking the commute's next scheduled days by predicted exposure.
+type CommuteBestDay struct {
+	CommuteID string `json:"commuteId"`
+	// Best is the ranked day with the lowest score, or null if no day could
+	// be ranked.
+	Best *CommuteDayForecast `json:"best"`
+	// Days lists the next scheduled days in date order, ranked or not.
+	Days        []CommuteDayForecast `json:"days"`
+	GeneratedAt Timestamp            `json:"generatedAt"`
+}
+
+// CommuteDayForecast is the predicted exposure for one scheduled commute day.
+type CommuteDayForecast struct {
+	// Date is the calendar date in the commute's timezone (YYYY-MM-DD).
+	Date      string    `json:"date"`
+	ArrivalAt Timestamp `json:"arrivalAt"`
+	// Rank orders the ranked days from 1 (best); absent if unranked.
+	Rank *int `json:"rank,omitempty"`
+	// Score is the predicted exposure at arrival, where 100 means every
+	// pollutant at the EAQI "good" limit, scaled by the pollen factor.
+	// Lower is better; absent if unranked.
+	Score       *float64 `json:"score,omitempty"`
+	AQICategory string   `json:"aqiCategory,omitempty"`
+	PollenRisk  string   `json:"pollenRisk,omitempty"`
+	// UnrankedReason explains why the day has no score.
+	UnrankedReason UnrankedReason `json:"unrankedReason,omitempty"`
+}
+
+// UnrankedReason explains why a commute day could not be ranked.
+type UnrankedReason string
+
+// UnrankedReason values.
+const (
+	UnrankedAirQualityForecastUnavailable UnrankedReason = "AIR_QUALITY_FORECAST_UNAVAILABLE"
+	UnrankedPollenForecastUnavailable     UnrankedReason = "POLLEN_FORECAST_UNAVAILABLE"
+)
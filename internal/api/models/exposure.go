@@ -0,0 +1,21 @@
+package models
+
+// ExposureHistory is a user's estimated commute exposure per week, newest
+// week first. The current week is always included, so clients can compare
+// it with the one before.
+type ExposureHistory struct {
+	Weeks []ExposureWeek `json:"weeks"`
+}
+
+// ExposureWeek aggregates the exposure on a week's completed commute trips.
+type ExposureWeek struct {
+	// WeekStart is the week's Monday (YYYY-MM-DD).
+	WeekStart string `json:"weekStart"`
+
+	// Trips is the number of trips with an exposure estimate.
+	Trips int `json:"trips"`
+
+	// Averages are the mean concentrations over the week's trips; omitted
+	// if there were none.
+	Averages *ExposureRawAverages `json:"averages,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// FeatureFlagsUpdateRequest sets feature flags with
+// PUT /v1/admin/feature-flags. Values are booleans, numbers or targeting
+// objects, keyed by flag key.
+type FeatureFlagsUpdateRequest struct {
+	Flags map[string]any `json:"flags"`
+}
+
+// FlagChange is a recorded change to a feature flag.
+type FlagChange struct {
+	// PreviousValue is omitted when the change created the flag, and
+	// NewValue when it deleted it.
+	PreviousValue any       `json:"previousValue,omitempty"`
+	NewValue      any       `json:"newValue,omitempty"`
+	ChangedBy     string    `json:"changedBy,omitempty"`
+	ChangedAt     Timestamp `json:"changedAt"`
+}
+
+// FlagHistory is a feature flag's change history, newest first.
+type FlagHistory struct {
+	Key     string       `json:"key"`
+	Changes []FlagChange `json:"changes"`
+}
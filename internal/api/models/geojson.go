@@ -0,0 +1,42 @@
+package models
+
+// MediaTypeGeoJSON is the GeoJSON media type, served by /v1/routes:compute
+// on request.
+const MediaTypeGeoJSON = "application/geo+json"
+
+// RouteFeatureCollection is the GeoJSON (RFC 7946) form of a route computation:
+// one LineString feature per route option, in ranked order. GeneratedAt and
+// Warnings are foreign members carried over from RouteComputeResponse.
+type RouteFeatureCollection struct {
+	Type        string         `json:"type"` // always "FeatureCollection"
+	Features    []RouteFeature `json:"features"`
+	GeneratedAt Timestamp      `json:"generatedAt"`
+	Warnings    []Warning      `json:"warnings,omitempty"`
+}
+
+// RouteFeature is a route option as a GeoJSON feature.
+type RouteFeature struct {
+	Type       string                 `json:"type"` // always "Feature"
+	ID         string                 `json:"id"`
+	Geometry   LineString             `json:"geometry"`
+	Properties RouteFeatureProperties `json:"properties"`
+}
+
+// LineString is a GeoJSON LineString geometry. Coordinates are
+// [longitude, latitude] pairs, as GeoJSON requires.
+type LineString struct {
+	Type        string       `json:"type"` // always "LineString"
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+// RouteFeatureProperties are the route option fields mapping libraries
+// typically style or label routes by.
+type RouteFeatureProperties struct {
+	Objective       Objective  `json:"objective"`
+	Modes           []Mode     `json:"modes"`
+	DistanceMeters  *int       `json:"distanceMeters,omitempty"`
+	DurationSeconds int        `json:"durationSeconds"`
+	ExposureScore   float64    `json:"exposureScore"`
+	Confidence      Confidence `json:"confidence"`
+	Title           string     `json:"title"`
+}
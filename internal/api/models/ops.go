@@ -13,9 +13,87 @@ type SystemStatus struct {
 	Time                   Timestamp         `json:"time"`
 	Subsystems             []SubsystemStatus `json:"subsystems"`
 	Providers              []ProviderStatus  `json:"providers"`
+	Caches                 []CacheStatus     `json:"caches,omitempty"`
+	DatabasePool           *DatabasePool     `json:"databasePool,omitempty"`
 	ActiveDegradationFlags []string          `json:"activeDegradationFlags,omitempty"`
 }
 
+// CacheStatus reports cumulative cache effectiveness and data freshness for
+// a caching service.
+type CacheStatus struct {
+	Name     string  `json:"name"`
+	Provider string  `json:"provider,omitempty"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hitRatio"`
+
+	// DataAgeSeconds is the age of the newest cached data, and
+	// OldestFreshAgeSeconds the age of the oldest unexpired entry. Each is
+	// omitted if the cache holds no such entry.
+	DataAgeSeconds        *int `json:"dataAgeSeconds,omitempty"`
+	OldestFreshAgeSeconds *int `json:"oldestFreshAgeSeconds,omitempty"`
+
+	// ServingStale is set once the newest cached data is older than
+	// StaleThresholdSeconds: nothing has been fetched since, typically
+	// because the provider is down.
+	StaleThresholdSeconds int  `json:"staleThresholdSeconds"`
+	ServingStale          bool `json:"servingStale"`
+}
+
+// MetricsReport is a JSON snapshot of operational metrics, returned by
+// GET /v1/ops/metrics for deployments that do not scrape Prometheus.
+type MetricsReport struct {
+	Time Timestamp `json:"time"`
+	// Refresh holds the provider refresh job's counters, omitted when no
+	// refresh job runs in this process.
+	Refresh   map[string]interface{} `json:"refresh,omitempty"`
+	Caches    []CacheStatus          `json:"caches"`
+	Providers []ProviderStatus       `json:"providers"`
+}
+
+// CacheReport lists the caches of every caching service, returned by
+// GET /v1/ops/cache.
+type CacheReport struct {
+	Time   Timestamp      `json:"time"`
+	Caches []ServiceCache `json:"caches"`
+}
+
+// ServiceCache reports the contents and effectiveness of one service's cache.
+type ServiceCache struct {
+	Name         string  `json:"name"`
+	Provider     string  `json:"provider,omitempty"`
+	Entries      int     `json:"entries"`
+	FreshEntries int     `json:"freshEntries"`
+	Hits         uint64  `json:"hits"`
+	Misses       uint64  `json:"misses"`
+	HitRatio     float64 `json:"hitRatio"`
+	Evictions    uint64  `json:"evictions"`
+
+	// FetchedAt is when the newest entry was fetched, and OldestFreshAt
+	// when the oldest unexpired one was.
+	FetchedAt     *Timestamp `json:"fetchedAt,omitempty"`
+	OldestFreshAt *Timestamp `json:"oldestFreshAt,omitempty"`
+}
+
+// CacheInvalidateRequest selects the caches to clear with
+// POST /v1/ops/cache:invalidate. With no services, every cache is cleared.
+type CacheInvalidateRequest struct {
+	Services []string `json:"services,omitempty"`
+}
+
+// CacheInvalidateResponse lists the caches that were cleared.
+type CacheInvalidateResponse struct {
+	Invalidated []string `json:"invalidated"`
+}
+
+// DatabasePool reports database connection pool usage.
+type DatabasePool struct {
+	AcquiredConns int32 `json:"acquiredConns"`
+	IdleConns     int32 `json:"idleConns"`
+	TotalConns    int32 `json:"totalConns"`
+	MaxConns      int32 `json:"maxConns"`
+}
+
 // SubsystemStatus represents the status of a subsystem.
 type SubsystemStatus struct {
 	Name   string       `json:"name"`
@@ -29,5 +107,8 @@ type ProviderStatus struct {
 	Status        HealthStatus `json:"status"`
 	LastSuccessAt *Timestamp   `json:"lastSuccessAt,omitempty"`
 	LastFailureAt *Timestamp   `json:"lastFailureAt,omitempty"`
-	Message       *string      `json:"message,omitempty"`
+	// ErrorRate is the share of requests that failed in the circuit
+	// breaker's current counting interval, omitted before any request.
+	ErrorRate *float64 `json:"errorRate,omitempty"`
+	Message   *string  `json:"message,omitempty"`
 }
@@ -23,6 +23,10 @@ type Problem struct {
 	// Instance is a URI reference that identifies the specific occurrence.
 	Instance string `json:"instance,omitempty"`
 
+	// Code is a stable, machine-readable identifier for the error that
+	// clients can switch on (e.g., to pick a localized message).
+	Code ErrorCode `json:"code"`
+
 	// TraceID is the request trace identifier for debugging.
 	TraceID string `json:"traceId"`
 
@@ -37,15 +41,102 @@ type FieldError struct {
 	Code    string `json:"code,omitempty"`
 }
 
+// FieldError codes. Like ErrorCode values, these are stable identifiers for
+// clients to localize; Message stays for debugging.
+const (
+	FieldCodeRequired        = "REQUIRED"
+	FieldCodeTooLong         = "TOO_LONG"
+	FieldCodeTooMany         = "TOO_MANY"
+	FieldCodeOutOfRange      = "OUT_OF_RANGE"
+	FieldCodeInvalidFormat   = "INVALID_FORMAT"
+	FieldCodeInvalidTimezone = "INVALID_TIMEZONE"
+	FieldCodeUnknownValue    = "UNKNOWN_VALUE"
+)
+
 // ProblemType constants for standard error types.
 const (
 	ProblemTypeValidation      = "https://api.breatheroute.nl/problems/validation-error"
 	ProblemTypeUnauthorized    = "https://api.breatheroute.nl/problems/unauthorized"
+	ProblemTypeForbidden       = "https://api.breatheroute.nl/problems/forbidden"
 	ProblemTypeNotFound        = "https://api.breatheroute.nl/problems/not-found"
 	ProblemTypeConflict        = "https://api.breatheroute.nl/problems/conflict"
+	ProblemTypePrecondition    = "https://api.breatheroute.nl/problems/precondition-failed"
 	ProblemTypeTooManyRequests = "https://api.breatheroute.nl/problems/too-many-requests"
+	ProblemTypePayloadTooLarge = "https://api.breatheroute.nl/problems/payload-too-large"
+	ProblemTypeUnprocessable   = "https://api.breatheroute.nl/problems/unprocessable"
 	ProblemTypeInternal        = "https://api.breatheroute.nl/problems/internal-error"
 	ProblemTypeUnavailable     = "https://api.breatheroute.nl/problems/service-unavailable"
+	ProblemTypeTimeout         = "https://api.breatheroute.nl/problems/timeout"
+)
+
+// ErrorCode identifies an error independently of its HTTP status and prose.
+// Codes are part of the API contract: add new ones freely, but never rename
+// or reuse an existing code.
+type ErrorCode string
+
+// Generic error codes, set by the NewXxx constructors.
+const (
+	ErrorCodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	ErrorCodeUnauthorized       ErrorCode = "AUTHENTICATION_REQUIRED"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrorCodeConflict           ErrorCode = "CONFLICT"
+	ErrorCodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+	ErrorCodePayloadTooLarge    ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrorCodeUnprocessable      ErrorCode = "UNPROCESSABLE"
+	ErrorCodeRateLimited        ErrorCode = "RATE_LIMITED"
+	ErrorCodeInternal           ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeUnavailable        ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrorCodeTimeout            ErrorCode = "REQUEST_TIMEOUT"
+)
+
+// Request errors (400).
+const (
+	ErrorCodeInvalidJSON      ErrorCode = "INVALID_JSON"
+	ErrorCodeInvalidBody      ErrorCode = "INVALID_BODY"
+	ErrorCodeBodyTooDeep      ErrorCode = "BODY_TOO_DEEP"
+	ErrorCodeMissingParameter ErrorCode = "MISSING_PARAMETER"
+	ErrorCodeGridTooLarge     ErrorCode = "GRID_TOO_LARGE"
+)
+
+// Authentication errors (401).
+const (
+	ErrorCodeAccessTokenExpired  ErrorCode = "ACCESS_TOKEN_EXPIRED"
+	ErrorCodeInvalidAccessToken  ErrorCode = "INVALID_ACCESS_TOKEN"
+	ErrorCodeRefreshTokenExpired ErrorCode = "REFRESH_TOKEN_EXPIRED"
+	ErrorCodeRefreshTokenReused  ErrorCode = "REFRESH_TOKEN_REUSED"
+	ErrorCodeInvalidRefreshToken ErrorCode = "INVALID_REFRESH_TOKEN"
+	ErrorCodeAppleTokenExpired   ErrorCode = "APPLE_TOKEN_EXPIRED"
+	ErrorCodeInvalidAppleToken   ErrorCode = "INVALID_APPLE_TOKEN"
+)
+
+// Resource errors (404, 409, 412).
+const (
+	ErrorCodeUserNotFound           ErrorCode = "USER_NOT_FOUND"
+	ErrorCodeCommuteNotFound        ErrorCode = "COMMUTE_NOT_FOUND"
+	ErrorCodeDeviceNotFound         ErrorCode = "DEVICE_NOT_FOUND"
+	ErrorCodeWebhookNotFound        ErrorCode = "WEBHOOK_NOT_FOUND"
+	ErrorCodeRouteNotFound          ErrorCode = "ROUTE_NOT_FOUND"
+	ErrorCodeRouteShareNotFound     ErrorCode = "ROUTE_SHARE_NOT_FOUND"
+	ErrorCodeNoStationInRange       ErrorCode = "NO_STATION_IN_RANGE"
+	ErrorCodeWebhookLimitReached    ErrorCode = "WEBHOOK_LIMIT_REACHED"
+	ErrorCodeCommuteVersionMismatch ErrorCode = "COMMUTE_VERSION_MISMATCH"
+)
+
+// Request errors that are well-formed but cannot be served (422).
+const (
+	ErrorCodeRouteTooLong          ErrorCode = "ROUTE_TOO_LONG"
+	ErrorCodeSameOriginDestination ErrorCode = "SAME_ORIGIN_DESTINATION"
+)
+
+// Availability errors (503).
+const (
+	ErrorCodeAirQualityUnavailable        ErrorCode = "AIR_QUALITY_UNAVAILABLE"
+	ErrorCodeTransitUnavailable           ErrorCode = "TRANSIT_UNAVAILABLE"
+	ErrorCodeWebhooksUnavailable          ErrorCode = "WEBHOOKS_UNAVAILABLE"
+	ErrorCodeAppleVerificationUnavailable ErrorCode = "APPLE_VERIFICATION_UNAVAILABLE"
+	ErrorCodeStreamLimitReached           ErrorCode = "STREAM_LIMIT_REACHED"
+	ErrorCodeMaintenanceMode              ErrorCode = "MAINTENANCE_MODE"
 )
 
 // NewProblem creates a new Problem with the given parameters.
@@ -58,6 +149,12 @@ func NewProblem(problemType, title string, status int, traceID string) *Problem
 	}
 }
 
+// WithCode sets the machine-readable error code of the Problem.
+func (p *Problem) WithCode(code ErrorCode) *Problem {
+	p.Code = code
+	return p
+}
+
 // WithDetail adds a detail message to the Problem.
 func (p *Problem) WithDetail(detail string) *Problem {
 	p.Detail = detail
@@ -87,6 +184,7 @@ func (p *Problem) Write(w http.ResponseWriter) {
 // NewBadRequest creates a 400 Bad Request problem.
 func NewBadRequest(traceID, detail string, errors []FieldError) *Problem {
 	p := NewProblem(ProblemTypeValidation, "Validation error", http.StatusBadRequest, traceID)
+	p.Code = ErrorCodeValidationFailed
 	p.Detail = detail
 	p.Errors = errors
 	return p
@@ -95,6 +193,15 @@ func NewBadRequest(traceID, detail string, errors []FieldError) *Problem {
 // NewUnauthorized creates a 401 Unauthorized problem.
 func NewUnauthorized(traceID, detail string) *Problem {
 	p := NewProblem(ProblemTypeUnauthorized, "Unauthorized", http.StatusUnauthorized, traceID)
+	p.Code = ErrorCodeUnauthorized
+	p.Detail = detail
+	return p
+}
+
+// NewForbidden creates a 403 Forbidden problem.
+func NewForbidden(traceID, detail string) *Problem {
+	p := NewProblem(ProblemTypeForbidden, "Forbidden", http.StatusForbidden, traceID)
+	p.Code = ErrorCodeForbidden
 	p.Detail = detail
 	return p
 }
@@ -102,6 +209,7 @@ func NewUnauthorized(traceID, detail string) *Problem {
 // NewNotFound creates a 404 Not Found problem.
 func NewNotFound(traceID, detail string) *Problem {
 	p := NewProblem(ProblemTypeNotFound, "Not found", http.StatusNotFound, traceID)
+	p.Code = ErrorCodeNotFound
 	p.Detail = detail
 	return p
 }
@@ -109,6 +217,31 @@ func NewNotFound(traceID, detail string) *Problem {
 // NewConflict creates a 409 Conflict problem.
 func NewConflict(traceID, detail string) *Problem {
 	p := NewProblem(ProblemTypeConflict, "Conflict", http.StatusConflict, traceID)
+	p.Code = ErrorCodeConflict
+	p.Detail = detail
+	return p
+}
+
+// NewPreconditionFailed creates a 412 Precondition Failed problem.
+func NewPreconditionFailed(traceID, detail string) *Problem {
+	p := NewProblem(ProblemTypePrecondition, "Precondition failed", http.StatusPreconditionFailed, traceID)
+	p.Code = ErrorCodePreconditionFailed
+	p.Detail = detail
+	return p
+}
+
+// NewPayloadTooLarge creates a 413 Payload Too Large problem.
+func NewPayloadTooLarge(traceID, detail string) *Problem {
+	p := NewProblem(ProblemTypePayloadTooLarge, "Payload too large", http.StatusRequestEntityTooLarge, traceID)
+	p.Code = ErrorCodePayloadTooLarge
+	p.Detail = detail
+	return p
+}
+
+// NewUnprocessableEntity creates a 422 Unprocessable Entity problem.
+func NewUnprocessableEntity(traceID, detail string) *Problem {
+	p := NewProblem(ProblemTypeUnprocessable, "Unprocessable request", http.StatusUnprocessableEntity, traceID)
+	p.Code = ErrorCodeUnprocessable
 	p.Detail = detail
 	return p
 }
@@ -116,6 +249,7 @@ func NewConflict(traceID, detail string) *Problem {
 // NewTooManyRequests creates a 429 Too Many Requests problem.
 func NewTooManyRequests(traceID, detail string) *Problem {
 	p := NewProblem(ProblemTypeTooManyRequests, "Too many requests", http.StatusTooManyRequests, traceID)
+	p.Code = ErrorCodeRateLimited
 	p.Detail = detail
 	return p
 }
@@ -123,6 +257,7 @@ func NewTooManyRequests(traceID, detail string) *Problem {
 // NewInternalError creates a 500 Internal Server Error problem.
 func NewInternalError(traceID, detail string) *Problem {
 	p := NewProblem(ProblemTypeInternal, "Internal server error", http.StatusInternalServerError, traceID)
+	p.Code = ErrorCodeInternal
 	p.Detail = detail
 	return p
 }
@@ -130,6 +265,15 @@ func NewInternalError(traceID, detail string) *Problem {
 // NewServiceUnavailable creates a 503 Service Unavailable problem.
 func NewServiceUnavailable(traceID, detail string) *Problem {
 	p := NewProblem(ProblemTypeUnavailable, "Service unavailable", http.StatusServiceUnavailable, traceID)
+	p.Code = ErrorCodeUnavailable
+	p.Detail = detail
+	return p
+}
+
+// NewGatewayTimeout creates a 504 Gateway Timeout problem.
+func NewGatewayTimeout(traceID, detail string) *Problem {
+	p := NewProblem(ProblemTypeTimeout, "Gateway timeout", http.StatusGatewayTimeout, traceID)
+	p.Code = ErrorCodeTimeout
 	p.Detail = detail
 	return p
 }
@@ -51,6 +51,17 @@ func TestProblem_WithInstance(t *testing.T) {
 	assert.Equal(t, "/v1/routes:compute", p.Instance)
 }
 
+func TestProblem_WithCode(t *testing.T) {
+	p := models.NewNotFound("req_123", "commute not found").
+		WithCode(models.ErrorCodeCommuteNotFound)
+
+	assert.Equal(t, models.ErrorCodeCommuteNotFound, p.Code)
+
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"code":"COMMUTE_NOT_FOUND"`)
+}
+
 func TestProblem_WithErrors(t *testing.T) {
 	fieldErrors := []models.FieldError{
 		{Field: "origin.lat", Message: "must be between -90 and 90", Code: "OUT_OF_RANGE"},
@@ -93,6 +104,7 @@ func TestProblem_Write(t *testing.T) {
 	assert.Equal(t, "invalid input", result.Detail)
 	assert.Equal(t, "/v1/me/profile", result.Instance)
 	assert.Equal(t, "req_test123", result.TraceID)
+	assert.Equal(t, models.ErrorCodeValidationFailed, result.Code)
 	require.Len(t, result.Errors, 1)
 	assert.Equal(t, "email", result.Errors[0].Field)
 }
@@ -104,6 +116,7 @@ func TestNewBadRequest(t *testing.T) {
 	assert.Equal(t, "Validation error", p.Title)
 	assert.Equal(t, http.StatusBadRequest, p.Status)
 	assert.Equal(t, "invalid data", p.Detail)
+	assert.Equal(t, models.ErrorCodeValidationFailed, p.Code)
 	assert.Equal(t, "req_123", p.TraceID)
 }
 
@@ -114,6 +127,7 @@ func TestNewUnauthorized(t *testing.T) {
 	assert.Equal(t, "Unauthorized", p.Title)
 	assert.Equal(t, http.StatusUnauthorized, p.Status)
 	assert.Equal(t, "token expired", p.Detail)
+	assert.Equal(t, models.ErrorCodeUnauthorized, p.Code)
 }
 
 func TestNewNotFound(t *testing.T) {
@@ -123,6 +137,7 @@ func TestNewNotFound(t *testing.T) {
 	assert.Equal(t, "Not found", p.Title)
 	assert.Equal(t, http.StatusNotFound, p.Status)
 	assert.Equal(t, "user not found", p.Detail)
+	assert.Equal(t, models.ErrorCodeNotFound, p.Code)
 }
 
 func TestNewConflict(t *testing.T) {
@@ -132,6 +147,7 @@ func TestNewConflict(t *testing.T) {
 	assert.Equal(t, "Conflict", p.Title)
 	assert.Equal(t, http.StatusConflict, p.Status)
 	assert.Equal(t, "duplicate entry", p.Detail)
+	assert.Equal(t, models.ErrorCodeConflict, p.Code)
 }
 
 func TestNewTooManyRequests(t *testing.T) {
@@ -141,6 +157,7 @@ func TestNewTooManyRequests(t *testing.T) {
 	assert.Equal(t, "Too many requests", p.Title)
 	assert.Equal(t, http.StatusTooManyRequests, p.Status)
 	assert.Equal(t, "rate limit exceeded", p.Detail)
+	assert.Equal(t, models.ErrorCodeRateLimited, p.Code)
 }
 
 func TestNewInternalError(t *testing.T) {
@@ -150,6 +167,7 @@ func TestNewInternalError(t *testing.T) {
 	assert.Equal(t, "Internal server error", p.Title)
 	assert.Equal(t, http.StatusInternalServerError, p.Status)
 	assert.Equal(t, "database error", p.Detail)
+	assert.Equal(t, models.ErrorCodeInternal, p.Code)
 }
 
 func TestNewServiceUnavailable(t *testing.T) {
@@ -159,4 +177,5 @@ func TestNewServiceUnavailable(t *testing.T) {
 	assert.Equal(t, "Service unavailable", p.Title)
 	assert.Equal(t, http.StatusServiceUnavailable, p.Status)
 	assert.Equal(t, "upstream unavailable", p.Detail)
+	assert.Equal(t, models.ErrorCodeUnavailable, p.Code)
 }
@@ -9,25 +9,66 @@ type ClientContext struct {
 
 // RouteComputeRequest is the request body for computing routes.
 type RouteComputeRequest struct {
-	CommuteID             *string        `json:"commuteId,omitempty"`
-	Origin                *Point         `json:"origin,omitempty"`
-	Destination           *Point         `json:"destination,omitempty"`
-	DepartureTime         string         `json:"departureTime" validate:"required"`
-	Modes                 []Mode         `json:"modes,omitempty"`
-	Objective             Objective      `json:"objective" validate:"required,oneof=FASTEST LOWEST_EXPOSURE BALANCED"`
-	MaxOptions            *int           `json:"maxOptions,omitempty" validate:"omitempty,gte=1,lte=10"`
-	ProfileOverride       *ProfileInput  `json:"profileOverride,omitempty"`
-	IncludeExplainability *bool          `json:"includeExplainability,omitempty"`
-	ClientContext         *ClientContext `json:"clientContext,omitempty"`
+	CommuteID     *string   `json:"commuteId,omitempty"`
+	Origin        *Point    `json:"origin,omitempty"`
+	Destination   *Point    `json:"destination,omitempty"`
+	Waypoints     []Point   `json:"waypoints,omitempty" validate:"omitempty,max=5"`
+	DepartureTime string    `json:"departureTime" validate:"required"`
+	Modes         []Mode    `json:"modes,omitempty"`
+	Objective     Objective `json:"objective" validate:"required,oneof=FASTEST LOWEST_EXPOSURE BALANCED"`
+	// Blend weights time against exposure for the BALANCED objective, from 0
+	// (lowest exposure only) to 1 (fastest only). Defaults to 0.5.
+	Blend                 *float64      `json:"blend,omitempty" validate:"omitempty,gte=0,lte=1"`
+	MaxOptions            *int          `json:"maxOptions,omitempty" validate:"omitempty,gte=1,lte=10"`
+	ProfileOverride       *ProfileInput `json:"profileOverride,omitempty"`
+	IncludeExplainability *bool         `json:"includeExplainability,omitempty"`
+	// IncludeWeather adds the weather at the origin and destination for the
+	// departure time to the response.
+	IncludeWeather *bool `json:"includeWeather,omitempty"`
+	// IncludeInstructions adds turn-by-turn instructions to each leg, in the
+	// language of clientContext.locale. Defaults to true.
+	IncludeInstructions *bool          `json:"includeInstructions,omitempty"`
+	ClientContext       *ClientContext `json:"clientContext,omitempty"`
 }
 
 // RouteComputeResponse is the response for route computation.
 type RouteComputeResponse struct {
+	// ID identifies the stored response for GET /v1/routes/{routeId} until
+	// ExpiresAt. Both are omitted when routes are not stored.
+	ID          string        `json:"id,omitempty"`
+	ExpiresAt   *Timestamp    `json:"expiresAt,omitempty"`
 	GeneratedAt Timestamp     `json:"generatedAt"`
 	Options     []RouteOption `json:"options"`
+	Weather     *RouteWeather `json:"weather,omitempty"`
 	Warnings    []Warning     `json:"warnings,omitempty"`
 }
 
+// PublicRoute is a shared route, served without authentication. It carries
+// only the route options and when the share expires.
+type PublicRoute struct {
+	GeneratedAt Timestamp     `json:"generatedAt"`
+	ExpiresAt   Timestamp     `json:"expiresAt"`
+	Options     []RouteOption `json:"options"`
+}
+
+// RouteShare is the response for sharing a route. Path is where the public
+// route is served.
+type RouteShare struct {
+	Token     string    `json:"token"`
+	Path      string    `json:"path"`
+	ExpiresAt Timestamp `json:"expiresAt"`
+}
+
+// RouteStreamDone is the final event of a streamed route computation.
+// RankedOptionIDs lists the streamed options ordered by the requested objective,
+// limited to maxOptions. RouteID identifies the stored ranked options, if
+// routes are stored.
+type RouteStreamDone struct {
+	RouteID         string    `json:"routeId,omitempty"`
+	GeneratedAt     Timestamp `json:"generatedAt"`
+	RankedOptionIDs []string  `json:"rankedOptionIds"`
+}
+
 // Warning represents a non-fatal issue in the response.
 type Warning struct {
 	Code     string  `json:"code"`
@@ -37,18 +78,24 @@ type Warning struct {
 
 // RouteOption represents a single route alternative.
 type RouteOption struct {
-	ID              string             `json:"id"`
-	Objective       Objective          `json:"objective"`
-	DurationSeconds int                `json:"durationSeconds"`
-	Transfers       *int               `json:"transfers,omitempty"`
-	DistanceMeters  *int               `json:"distanceMeters,omitempty"`
-	ExposureScore   float64            `json:"exposureScore"`
-	Confidence      Confidence         `json:"confidence"`
-	DeltaVsFastest  *Delta             `json:"deltaVsFastest,omitempty"`
-	Breakdown       *ExposureBreakdown `json:"breakdown,omitempty"`
-	Explainability  *Explainability    `json:"explainability,omitempty"`
-	Legs            []RouteLeg         `json:"legs"`
-	Summary         RouteSummary       `json:"summary"`
+	ID                          string             `json:"id"`
+	Objective                   Objective          `json:"objective"`
+	DurationSeconds             int                `json:"durationSeconds"`
+	Transfers                   *int               `json:"transfers,omitempty"`
+	DistanceMeters              *int               `json:"distanceMeters,omitempty"`
+	ExposureScore               float64            `json:"exposureScore"`
+	Confidence                  Confidence         `json:"confidence"`
+	ExposureConfidence          Confidence         `json:"exposureConfidence,omitempty"`
+	LowConfidenceSampleFraction *float64           `json:"lowConfidenceSampleFraction,omitempty"`
+	DeltaVsFastest              *Delta             `json:"deltaVsFastest,omitempty"`
+	Breakdown                   *ExposureBreakdown `json:"breakdown,omitempty"`
+	Explainability              *Explainability    `json:"explainability,omitempty"`
+	Legs                        []RouteLeg         `json:"legs"`
+	Summary                     RouteSummary       `json:"summary"`
+	// Estimated marks a straight-line estimate returned while the routing
+	// provider is unavailable. Its legs have no geometry or instructions and
+	// its confidence is LOW.
+	Estimated bool `json:"estimated,omitempty"`
 }
 
 // Delta represents the difference versus the fastest option.
@@ -71,9 +118,11 @@ type RouteLeg struct {
 	End              LegPoint      `json:"end"`
 	DurationSeconds  int           `json:"durationSeconds"`
 	DistanceMeters   *int          `json:"distanceMeters,omitempty"`
+	AscentMeters     *int          `json:"ascentMeters,omitempty"`
 	GeometryPolyline *string       `json:"geometryPolyline,omitempty"`
 	Transit          *TransitLeg   `json:"transit,omitempty"`
 	Instructions     []Instruction `json:"instructions,omitempty"`
+	RoadNames        []string      `json:"roadNames,omitempty"`
 }
 
 // LegPoint represents a point in a route leg.
@@ -90,6 +139,11 @@ type TransitLeg struct {
 	ArrivalTime   Timestamp      `json:"arrivalTime"`
 	Platform      *string        `json:"platform,omitempty"`
 	Alerts        []TransitAlert `json:"alerts,omitempty"`
+	// OriginStation and DestinationStation are station codes (e.g., "ASD").
+	// When absent, the leg's start and end names identify the stations.
+	OriginStation      *string           `json:"originStation,omitempty"`
+	DestinationStation *string           `json:"destinationStation,omitempty"`
+	Disruptions        *RouteDisruptions `json:"disruptions,omitempty"`
 }
 
 // TransitAlert represents a service alert for transit.
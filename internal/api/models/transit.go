@@ -0,0 +1,54 @@
+package models
+
+// RouteDisruptions is the response for transit disruptions on a route.
+type RouteDisruptions struct {
+	Origin         string              `json:"origin"`
+	Destination    string              `json:"destination"`
+	HasDisruptions bool                `json:"hasDisruptions"`
+	OverallImpact  *string             `json:"overallImpact,omitempty"`
+	Locale         string              `json:"locale"`
+	Advisory       *TransitAdvisory    `json:"advisory,omitempty"`
+	Disruptions    []TransitDisruption `json:"disruptions"`
+	FetchedAt      Timestamp           `json:"fetchedAt"`
+}
+
+// TransitAdvisory is a localized summary of disruptions on a route.
+type TransitAdvisory struct {
+	Headline string   `json:"headline"`
+	Details  []string `json:"details"`
+	Severity string   `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// TransitDisruption describes a single transit disruption.
+type TransitDisruption struct {
+	ID                   string     `json:"id"`
+	Type                 string     `json:"type"`
+	Title                string     `json:"title"`
+	Impact               string     `json:"impact"`
+	AffectedStations     []string   `json:"affectedStations,omitempty"`
+	IsPlanned            bool       `json:"isPlanned"`
+	ExpectedDuration     *int       `json:"expectedDurationMinutes,omitempty"`
+	AlternativeTransport *string    `json:"alternativeTransport,omitempty"`
+	Start                *Timestamp `json:"start,omitempty"`
+	End                  *Timestamp `json:"end,omitempty"`
+}
+
+// TransitDisruptionSummary is a snapshot of current network-wide disruptions,
+// sent as the first event of the disruption stream.
+type TransitDisruptionSummary struct {
+	TotalDisruptions int            `json:"totalDisruptions"`
+	ByImpact         map[string]int `json:"byImpact"`
+	ByType           map[string]int `json:"byType"`
+	MostSevere       *string        `json:"mostSevere,omitempty"`
+	Provider         string         `json:"provider"`
+	FetchedAt        Timestamp      `json:"fetchedAt"`
+}
+
+// TransitDisruptionDelta lists disruptions that changed on a cache refresh.
+type TransitDisruptionDelta struct {
+	Added      []TransitDisruption `json:"added"`
+	Removed    []TransitDisruption `json:"removed"`
+	Changed    []TransitDisruption `json:"changed"`
+	DetectedAt Timestamp           `json:"detectedAt"`
+}
@@ -34,6 +34,23 @@ type Me struct {
 	CreatedAt Timestamp `json:"createdAt"`
 }
 
+// Identity is a sign-in identity linked to the authenticated user.
+// The provider's subject identifier is never exposed.
+type Identity struct {
+	ID             string    `json:"id"`
+	Provider       string    `json:"provider"`
+	Email          *string   `json:"email,omitempty"`
+	EmailVerified  bool      `json:"emailVerified"`
+	IsPrivateEmail bool      `json:"isPrivateEmail"`
+	LinkedAt       Timestamp `json:"linkedAt"`
+	LastUsedAt     Timestamp `json:"lastUsedAt"`
+}
+
+// IdentityList is the response for listing linked identities.
+type IdentityList struct {
+	Items []Identity `json:"items"`
+}
+
 // MeInput is the request body for updating user settings.
 type MeInput struct {
 	Locale *string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag"`
@@ -61,8 +78,12 @@ type Profile struct {
 	Constraints         RouteConstraints    `json:"constraints"`
 	PreferredMode       TransportMode       `json:"preferredMode"`
 	ExposureSensitivity ExposureSensitivity `json:"exposureSensitivity"`
-	CreatedAt           Timestamp           `json:"createdAt"`
-	UpdatedAt           Timestamp           `json:"updatedAt"`
+	// PollenSensitivities are the pollen types (GRASS, TREE, WEED) or
+	// species (e.g. Birch) the user is allergic to, matched ignoring case.
+	// When empty, the overall pollen risk applies.
+	PollenSensitivities []string  `json:"pollenSensitivities"`
+	CreatedAt           Timestamp `json:"createdAt"`
+	UpdatedAt           Timestamp `json:"updatedAt"`
 }
 
 // ProfileInput is the request body for creating or updating a profile.
@@ -71,6 +92,45 @@ type ProfileInput struct {
 	Constraints         RouteConstraints     `json:"constraints" validate:"required"`
 	PreferredMode       *TransportMode       `json:"preferredMode,omitempty" validate:"omitempty,oneof=BIKE WALK TRANSIT"`
 	ExposureSensitivity *ExposureSensitivity `json:"exposureSensitivity,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH"`
+	// PollenSensitivities replaces the stored list when present; an empty
+	// list clears it.
+	PollenSensitivities []string `json:"pollenSensitivities,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+	// Normalize scales the weights to sum to 1.0 before saving.
+	Normalize bool `json:"normalize,omitempty"`
+}
+
+// ProfilePatch is the request body for partially updating a profile.
+// Omitted fields are left unchanged.
+type ProfilePatch struct {
+	Weights             *ExposureWeightsPatch  `json:"weights,omitempty"`
+	Constraints         *RouteConstraintsPatch `json:"constraints,omitempty"`
+	PreferredMode       *TransportMode         `json:"preferredMode,omitempty" validate:"omitempty,oneof=BIKE WALK TRANSIT"`
+	ExposureSensitivity *ExposureSensitivity   `json:"exposureSensitivity,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH"`
+	// PollenSensitivities replaces the stored list when present; an empty
+	// list clears it.
+	PollenSensitivities []string `json:"pollenSensitivities,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+	// Normalize scales the resulting weights to sum to 1.0 before saving.
+	Normalize bool `json:"normalize,omitempty"`
+}
+
+// ExposureWeightsPatch is a partial update to exposure weights.
+type ExposureWeightsPatch struct {
+	NO2    *float64 `json:"no2,omitempty" validate:"omitempty,gte=0,lte=1"`
+	PM25   *float64 `json:"pm25,omitempty" validate:"omitempty,gte=0,lte=1"`
+	O3     *float64 `json:"o3,omitempty" validate:"omitempty,gte=0,lte=1"`
+	Pollen *float64 `json:"pollen,omitempty" validate:"omitempty,gte=0,lte=1"`
+}
+
+// RouteConstraintsPatch is a partial update to route constraints.
+type RouteConstraintsPatch struct {
+	AvoidMajorRoads          *bool    `json:"avoidMajorRoads,omitempty"`
+	AvoidFerries             *bool    `json:"avoidFerries,omitempty"`
+	AvoidUnpaved             *bool    `json:"avoidUnpaved,omitempty"`
+	AvoidCongestionZones     *bool    `json:"avoidCongestionZones,omitempty"`
+	PreferParks              *bool    `json:"preferParks,omitempty"`
+	MaxExtraMinutesVsFastest *int     `json:"maxExtraMinutesVsFastest,omitempty" validate:"omitempty,gte=0,lte=120"`
+	MaxTransfers             *int     `json:"maxTransfers,omitempty" validate:"omitempty,gte=0,lte=10"`
+	EffortWeight             *float64 `json:"effortWeight,omitempty" validate:"omitempty,gte=0,lte=1"`
 }
 
 // ExposureWeights represents the relative importance of pollutant factors.
@@ -84,7 +144,15 @@ type ExposureWeights struct {
 // RouteConstraints represents route generation preferences.
 type RouteConstraints struct {
 	AvoidMajorRoads          bool  `json:"avoidMajorRoads"`
+	AvoidFerries             bool  `json:"avoidFerries"`
+	AvoidUnpaved             bool  `json:"avoidUnpaved"`
 	PreferParks              *bool `json:"preferParks,omitempty"`
 	MaxExtraMinutesVsFastest *int  `json:"maxExtraMinutesVsFastest,omitempty" validate:"omitempty,gte=0,lte=120"`
 	MaxTransfers             *int  `json:"maxTransfers,omitempty" validate:"omitempty,gte=0,lte=10"`
+	// EffortWeight penalizes climbing in BALANCED ranking (0 = ignore hills, 1 = strongest penalty).
+	EffortWeight *float64 `json:"effortWeight,omitempty" validate:"omitempty,gte=0,lte=1"`
+	// AvoidCongestionZones keeps routes out of city-centre low-emission
+	// zones. Air is usually cleaner inside them, so LOWEST_EXPOSURE routes
+	// may score worse with it set.
+	AvoidCongestionZones bool `json:"avoidCongestionZones"`
 }
@@ -0,0 +1,31 @@
+package models
+
+// WeatherSource says whether weather is an observation or a forecast.
+type WeatherSource string
+
+// Weather sources.
+const (
+	WeatherSourceCurrent  WeatherSource = "CURRENT"
+	WeatherSourceForecast WeatherSource = "FORECAST"
+)
+
+// RouteWeather is the weather at a route's origin and destination for the
+// departure time. A point is omitted if its weather is unavailable.
+type RouteWeather struct {
+	Origin      *WeatherConditions `json:"origin,omitempty"`
+	Destination *WeatherConditions `json:"destination,omitempty"`
+}
+
+// WeatherConditions is the weather at one point. ObservedAt is when a
+// CURRENT observation was made, or the hour a FORECAST is for.
+type WeatherConditions struct {
+	Source             WeatherSource `json:"source"`
+	ObservedAt         Timestamp     `json:"observedAt"`
+	TemperatureCelsius float64       `json:"temperatureCelsius"`
+	Condition          string        `json:"condition"`
+	// PrecipitationProbability (0-1) is only known for forecasts.
+	PrecipitationProbability *float64 `json:"precipitationProbability,omitempty"`
+	WindSpeedMps             float64  `json:"windSpeedMps"`
+	WindGustMps              *float64 `json:"windGustMps,omitempty"`
+	WindDirectionDeg         float64  `json:"windDirectionDeg"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Webhook represents a registered webhook endpoint.
+type Webhook struct {
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastDeliveryAt      *Timestamp `json:"lastDeliveryAt,omitempty"`
+	LastError           *string    `json:"lastError,omitempty"`
+	DisabledAt          *Timestamp `json:"disabledAt,omitempty"`
+	CreatedAt           Timestamp  `json:"createdAt"`
+}
+
+// WebhookCreated is returned when a webhook is registered.
+// The signing secret is only ever returned here.
+type WebhookCreated struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookCreateRequest is the request body for registering a webhook.
+type WebhookCreateRequest struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+// WebhookList represents the list of a user's webhooks.
+type WebhookList struct {
+	Items []Webhook `json:"items"`
+}
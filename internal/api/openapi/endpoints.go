@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/auth"
+)
+
+// problemModel is the error body of every operation.
+var problemModel = models.Problem{}
+
+// endpoint describes one operation registered by api.NewRouter. Request and
+// response are zero values of the body types, or nil for no body.
+type endpoint struct {
+	method  string
+	path    string
+	id      string
+	summary string
+	tag     string
+	auth    bool
+	// optionalAuth accepts both anonymous and authenticated requests
+	optionalAuth bool
+	query        []queryParam
+	request      any
+	status       int // default: 200
+	response     any
+	stream       bool // responds with text/event-stream
+	// alternates are other response bodies by media type, chosen by Accept,
+	// or the only body of endpoints that do not respond with JSON
+	alternates map[string]any
+}
+
+// queryParam is a query string parameter of an endpoint.
+type queryParam struct {
+	name     string
+	typ      string
+	required bool
+}
+
+// endpoints lists every route of the API. Keep it in step with
+// api.NewRouter; the router tests fail on any route missing here.
+// Development-only routes are left out.
+var endpoints = []endpoint{
+	// Auth
+	{method: http.MethodPost, path: "/v1/auth/siwa", id: "signInWithApple", summary: "Sign in with Apple", tag: "auth",
+		request: auth.SIWATokenRequest{}, response: auth.TokenResponse{}},
+	{method: http.MethodPost, path: "/v1/auth/refresh", id: "refreshToken", summary: "Exchange a refresh token for new tokens", tag: "auth",
+		request: auth.RefreshTokenRequest{}, response: auth.TokenResponse{}},
+	{method: http.MethodPost, path: "/v1/auth/logout", id: "logout", summary: "Revoke a refresh token", tag: "auth",
+		request: struct {
+			RefreshToken string `json:"refreshToken"`
+		}{}, status: http.StatusNoContent},
+	{method: http.MethodPost, path: "/v1/auth/logout-all", id: "logoutAll", summary: "Revoke all of the user's refresh tokens", tag: "auth",
+		auth: true, status: http.StatusNoContent},
+
+	// Ops
+	{method: http.MethodGet, path: "/v1/ops/health", id: "getHealth", summary: "Liveness check", tag: "ops",
+		response: models.Health{}},
+	{method: http.MethodGet, path: "/v1/ops/ready", id: "getReadiness", summary: "Readiness check", tag: "ops",
+		response: models.Health{}},
+	{method: http.MethodGet, path: "/v1/ops/status", id: "getSystemStatus", summary: "Subsystem and provider status", tag: "ops",
+		auth: true, response: models.SystemStatus{}},
+	{method: http.MethodGet, path: "/v1/ops/metrics", id: "getMetrics", summary: "JSON metrics snapshot (admin)", tag: "ops",
+		auth: true, response: models.MetricsReport{}},
+	{method: http.MethodGet, path: "/v1/ops/flags/{key}/history", id: "getFlagHistory", summary: "A feature flag's change history (admin)", tag: "ops",
+		auth: true, response: models.FlagHistory{}},
+	{method: http.MethodGet, path: "/v1/ops/cache", id: "getCaches", summary: "Inspect service caches (admin)", tag: "ops",
+		auth: true, response: models.CacheReport{}},
+	{method: http.MethodPost, path: "/v1/ops/cache:invalidate", id: "invalidateCaches", summary: "Clear service caches (admin)", tag: "ops",
+		auth: true, request: models.CacheInvalidateRequest{}, response: models.CacheInvalidateResponse{}},
+
+	// API description
+	{method: http.MethodGet, path: "/v1/openapi.json", id: "getOpenAPI", summary: "This OpenAPI document", tag: "metadata",
+		response: map[string]any{}},
+
+	// Metadata
+	{method: http.MethodGet, path: "/v1/metadata/air-quality/stations", id: "listAirQualityStations", summary: "List air quality stations", tag: "metadata",
+		response: models.PagedStations{}},
+	{method: http.MethodGet, path: "/v1/metadata/enums", id: "getEnums", summary: "List enum values", tag: "metadata",
+		response: models.Enums{}},
+
+	// Me
+	{method: http.MethodGet, path: "/v1/me", id: "getMe", summary: "Get the user's account", tag: "me",
+		auth: true, response: models.Me{}},
+	{method: http.MethodPut, path: "/v1/me", id: "updateMe", summary: "Update the user's account", tag: "me",
+		auth: true, request: models.MeInput{}, response: models.Me{}},
+	{method: http.MethodGet, path: "/v1/me/identities", id: "listIdentities", summary: "List linked sign-in identities", tag: "me",
+		auth: true, response: models.IdentityList{}},
+	{method: http.MethodGet, path: "/v1/me/consents", id: "getConsents", summary: "Get the user's consents", tag: "me",
+		auth: true, response: models.Consents{}},
+	{method: http.MethodPut, path: "/v1/me/consents", id: "updateConsents", summary: "Update the user's consents", tag: "me",
+		auth: true, request: models.ConsentsInput{}, response: models.Consents{}},
+	{method: http.MethodGet, path: "/v1/me/profile", id: "getProfile", summary: "Get the user's routing profile", tag: "profile",
+		auth: true, response: models.Profile{}},
+	{method: http.MethodPut, path: "/v1/me/profile", id: "upsertProfile", summary: "Replace the user's routing profile", tag: "profile",
+		auth: true, request: models.ProfileInput{}, response: models.Profile{}},
+	{method: http.MethodPatch, path: "/v1/me/profile", id: "patchProfile", summary: "Update part of the user's routing profile", tag: "profile",
+		auth: true, request: models.ProfilePatch{}, response: models.Profile{}},
+	{method: http.MethodGet, path: "/v1/me/exposure/history", id: "getExposureHistory", summary: "Weekly exposure on completed commute trips", tag: "me",
+		auth: true, query: []queryParam{{name: "weeks", typ: "integer"}}, response: models.ExposureHistory{}},
+
+	// Commutes
+	{method: http.MethodGet, path: "/v1/me/commutes", id: "listCommutes", summary: "List saved commutes", tag: "commutes",
+		auth: true, response: models.PagedCommutes{}},
+	{method: http.MethodPost, path: "/v1/me/commutes", id: "createCommute", summary: "Save a commute", tag: "commutes",
+		auth: true, request: models.CommuteCreateRequest{}, status: http.StatusCreated, response: models.Commute{}},
+	{method: http.MethodPost, path: "/v1/me/commutes/{commuteId}:clone", id: "cloneCommute", summary: "Copy a commute with optional changes", tag: "commutes",
+		auth: true, request: models.CommuteUpdateRequest{}, status: http.StatusCreated, response: models.Commute{}},
+	{method: http.MethodGet, path: "/v1/me/commutes/{commuteId}", id: "getCommute", summary: "Get a commute", tag: "commutes",
+		auth: true, response: models.Commute{}},
+	{method: http.MethodPut, path: "/v1/me/commutes/{commuteId}", id: "updateCommute", summary: "Update a commute", tag: "commutes",
+		auth: true, request: models.CommuteUpdateRequest{}, response: models.Commute{}},
+	{method: http.MethodDelete, path: "/v1/me/commutes/{commuteId}", id: "deleteCommute", summary: "Delete a commute", tag: "commutes",
+		auth: true, status: http.StatusNoContent},
+	{method: http.MethodGet, path: "/v1/me/commutes/{commuteId}/best-day", id: "getCommuteBestDay", summary: "Rank the commute's upcoming days by forecast exposure", tag: "commutes",
+		auth: true, response: models.CommuteBestDay{}},
+
+	// Alert subscriptions
+	{method: http.MethodGet, path: "/v1/me/alerts/subscriptions", id: "listAlertSubscriptions", summary: "List alert subscriptions", tag: "alerts",
+		auth: true, response: models.PagedAlertSubscriptions{}},
+	{method: http.MethodPost, path: "/v1/me/alerts/subscriptions", id: "createAlertSubscription", summary: "Create an alert subscription", tag: "alerts",
+		auth: true, request: models.AlertSubscriptionCreateRequest{}, status: http.StatusCreated, response: models.AlertSubscription{}},
+	{method: http.MethodGet, path: "/v1/me/alerts/subscriptions/{subscriptionId}", id: "getAlertSubscription", summary: "Get an alert subscription", tag: "alerts",
+		auth: true, response: models.AlertSubscription{}},
+	{method: http.MethodPut, path: "/v1/me/alerts/subscriptions/{subscriptionId}", id: "updateAlertSubscription", summary: "Update an alert subscription", tag: "alerts",
+		auth: true, request: models.AlertSubscriptionUpdateRequest{}, response: models.AlertSubscription{}},
+	{method: http.MethodDelete, path: "/v1/me/alerts/subscriptions/{subscriptionId}", id: "deleteAlertSubscription", summary: "Delete an alert subscription", tag: "alerts",
+		auth: true, status: http.StatusNoContent},
+
+	// Devices
+	{method: http.MethodGet, path: "/v1/me/devices", id: "listDevices", summary: "List push notification devices", tag: "devices",
+		auth: true, response: models.PagedDevices{}},
+	{method: http.MethodPost, path: "/v1/me/devices", id: "registerDevice", summary: "Register a push notification device", tag: "devices",
+		auth: true, request: models.DeviceRegisterRequest{}, status: http.StatusCreated, response: models.Device{}},
+	{method: http.MethodDelete, path: "/v1/me/devices/{deviceId}", id: "unregisterDevice", summary: "Unregister a device", tag: "devices",
+		auth: true, status: http.StatusNoContent},
+
+	// Webhooks
+	{method: http.MethodGet, path: "/v1/me/webhooks", id: "listWebhooks", summary: "List transit disruption webhooks", tag: "webhooks",
+		auth: true, response: models.WebhookList{}},
+	{method: http.MethodPost, path: "/v1/me/webhooks", id: "createWebhook", summary: "Register a webhook", tag: "webhooks",
+		auth: true, request: models.WebhookCreateRequest{}, status: http.StatusCreated, response: models.WebhookCreated{}},
+	{method: http.MethodDelete, path: "/v1/me/webhooks/{webhookId}", id: "deleteWebhook", summary: "Delete a webhook", tag: "webhooks",
+		auth: true, status: http.StatusNoContent},
+	{method: http.MethodDelete, path: "/v1/me/route-shares/{shareToken}", id: "revokeRouteShare", summary: "Revoke a route share link", tag: "routes",
+		auth: true, status: http.StatusNoContent},
+
+	// Routes
+	{method: http.MethodPost, path: "/v1/routes:compute", id: "computeRoutes", summary: "Compute routes ranked by exposure", tag: "routes",
+		optionalAuth: true, query: []queryParam{{name: "full", typ: "boolean"}}, request: models.RouteComputeRequest{}, response: models.RouteComputeResponse{},
+		alternates: map[string]any{models.MediaTypeGeoJSON: models.RouteFeatureCollection{}}},
+	{method: http.MethodGet, path: "/v1/routes/{routeId}", id: "getRoute", summary: "Get a stored route computation", tag: "routes",
+		optionalAuth: true, response: models.RouteComputeResponse{},
+		alternates: map[string]any{models.MediaTypeGeoJSON: models.RouteFeatureCollection{}}},
+	{method: http.MethodGet, path: "/v1/routes/{routeId}/export.gpx", id: "exportRouteGPX", summary: "Export a stored route option as GPX", tag: "routes",
+		optionalAuth: true, query: []queryParam{{name: "optionId", typ: "string"}},
+		alternates: map[string]any{"application/gpx+xml": ""}}, // GPX documents are described as strings
+	{method: http.MethodPost, path: "/v1/routes/{routeId}:share", id: "shareRoute", summary: "Create a public link to a stored route", tag: "routes",
+		auth: true, status: http.StatusCreated, response: models.RouteShare{}},
+	{method: http.MethodGet, path: "/v1/public/routes/{shareToken}", id: "getPublicRoute", summary: "Get a shared route", tag: "routes",
+		response: models.PublicRoute{}},
+
+	// Air quality
+	{method: http.MethodPost, path: "/v1/air-quality/grid", id: "getAirQualityGrid", summary: "Interpolated air quality grid", tag: "air-quality",
+		request: models.AirQualityGridRequest{}, response: models.AirQualityGridResponse{}},
+	{method: http.MethodGet, path: "/v1/air-quality/nearest", id: "getNearestStation", summary: "Nearest air quality station", tag: "air-quality",
+		query:    []queryParam{{name: "lat", typ: "number", required: true}, {name: "lon", typ: "number", required: true}},
+		response: models.NearestStationResponse{}},
+
+	// Transit
+	{method: http.MethodGet, path: "/v1/transit/disruptions", id: "getRouteDisruptions", summary: "Disruptions between two stations", tag: "transit",
+		auth: true, query: []queryParam{{name: "origin", typ: "string", required: true}, {name: "destination", typ: "string", required: true}},
+		response: models.RouteDisruptions{}},
+	{method: http.MethodGet, path: "/v1/transit/disruptions/stream", id: "streamDisruptions", summary: "Stream disruption changes", tag: "transit",
+		auth: true, stream: true},
+
+	// Alerts preview
+	{method: http.MethodPost, path: "/v1/alerts/preview", id: "previewDepartureWindows", summary: "Preview recommended departure windows", tag: "alerts",
+		request: models.AlertPreviewRequest{}, response: models.AlertPreviewResponse{}},
+
+	// GDPR
+	{method: http.MethodGet, path: "/v1/gdpr/export-requests", id: "listExportRequests", summary: "List data export requests", tag: "gdpr",
+		auth: true, response: models.PagedExportRequests{}},
+	{method: http.MethodPost, path: "/v1/gdpr/export-requests", id: "createExportRequest", summary: "Request a data export", tag: "gdpr",
+		auth: true, request: models.ExportRequestCreate{}, status: http.StatusAccepted, response: models.ExportRequest{}},
+	{method: http.MethodGet, path: "/v1/gdpr/export-requests/{exportRequestId}", id: "getExportRequest", summary: "Get a data export request", tag: "gdpr",
+		auth: true, response: models.ExportRequest{}},
+	{method: http.MethodGet, path: "/v1/gdpr/deletion-requests", id: "listDeletionRequests", summary: "List account deletion requests", tag: "gdpr",
+		auth: true, response: models.PagedDeletionRequests{}},
+	{method: http.MethodPost, path: "/v1/gdpr/deletion-requests", id: "createDeletionRequest", summary: "Request account deletion", tag: "gdpr",
+		auth: true, request: models.DeletionRequestCreate{}, status: http.StatusAccepted, response: models.DeletionRequest{}},
+	{method: http.MethodGet, path: "/v1/gdpr/deletion-requests/{deletionRequestId}", id: "getDeletionRequest", summary: "Get an account deletion request", tag: "gdpr",
+		auth: true, response: models.DeletionRequest{}},
+
+	// Admin
+	{method: http.MethodGet, path: "/v1/admin/feature-flags", id: "listFeatureFlags", summary: "List feature flags (admin)", tag: "admin",
+		auth: true, response: map[string]any{}},
+	{method: http.MethodPut, path: "/v1/admin/feature-flags", id: "upsertFeatureFlags", summary: "Update feature flags (admin)", tag: "admin",
+		auth: true, request: models.FeatureFlagsUpdateRequest{}, status: http.StatusNoContent},
+	{method: http.MethodPost, path: "/v1/admin/feature-flags/invalidate", id: "invalidateFeatureFlags", summary: "Clear the feature flag cache (admin)", tag: "admin",
+		auth: true, status: http.StatusNoContent},
+	{method: http.MethodGet, path: "/v1/admin/users/{userId}/audit", id: "listUserAuditEntries", summary: "A user's change history (admin)", tag: "admin",
+		auth: true, query: []queryParam{{name: "limit", typ: "integer"}}, response: models.PagedAuditEntries{}},
+}
@@ -0,0 +1,189 @@
+// Package openapi describes the BreatheRoute API as an OpenAPI 3 document.
+// Operations are listed by hand next to the router; request and response
+// schemas are derived from the models package structs by their JSON tags.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI specification version of the generated document.
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the API is served from.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lower-case HTTP methods to the operations on one path.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's request body.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType gives the schema of a body in one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas and security schemes operations refer to.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes how clients authenticate.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// bearerAuth names the JWT security scheme.
+const bearerAuth = "bearerAuth"
+
+// pathParamPattern matches chi path parameters, which share OpenAPI syntax.
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Spec builds the OpenAPI document for the API at the given version.
+func Spec(version string) *Document {
+	if version == "" {
+		version = "dev"
+	}
+
+	registry := newSchemaRegistry()
+	problem := registry.schemaFor(reflect.TypeOf(problemModel))
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "BreatheRoute API",
+			Description: "Commute routes ranked by air quality exposure.",
+			Version:     version,
+		},
+		Servers: []Server{{URL: "https://api.breatheroute.nl"}},
+		Paths:   make(map[string]PathItem),
+	}
+
+	for _, e := range endpoints {
+		op := &Operation{
+			OperationID: e.id,
+			Summary:     e.summary,
+			Tags:        []string{e.tag},
+			Responses: map[string]Response{
+				"default": {
+					Description: "Error",
+					Content:     map[string]MediaType{"application/problem+json": {Schema: problem}},
+				},
+			},
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(e.path, -1) {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"},
+			})
+		}
+		for _, q := range e.query {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name: q.name, In: "query", Required: q.required, Schema: &Schema{Type: q.typ},
+			})
+		}
+
+		if e.request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: registry.schemaFor(reflect.TypeOf(e.request))}},
+			}
+		}
+
+		status := e.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		resp := Response{Description: http.StatusText(status)}
+		switch {
+		case e.stream:
+			resp.Content = map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}}
+		case e.response != nil:
+			resp.Content = map[string]MediaType{"application/json": {Schema: registry.schemaFor(reflect.TypeOf(e.response))}}
+		}
+		for mediaType, body := range e.alternates {
+			if resp.Content == nil {
+				resp.Content = make(map[string]MediaType)
+			}
+			resp.Content[mediaType] = MediaType{Schema: registry.schemaFor(reflect.TypeOf(body))}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+
+		switch {
+		case e.auth:
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		case e.optionalAuth:
+			// An empty requirement allows anonymous requests
+			op.Security = []map[string][]string{{bearerAuth: {}}, {}}
+		}
+
+		item, ok := doc.Paths[e.path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[e.path] = item
+		}
+		item[strings.ToLower(e.method)] = op
+	}
+
+	doc.Components = Components{
+		Schemas: registry.schemas,
+		SecuritySchemes: map[string]SecurityScheme{
+			bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+	return doc
+}
@@ -0,0 +1,93 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/openapi"
+)
+
+func TestSpec_Schemas(t *testing.T) {
+	doc := openapi.Spec("1.2.3")
+	assert.Equal(t, "1.2.3", doc.Info.Version)
+
+	for _, name := range []string{
+		"RouteComputeRequest", "RouteComputeResponse", "RouteOption",
+		"Commute", "CommuteCreateRequest", "PagedCommutes",
+		"Profile", "ProfileInput", "ProfilePatch",
+		"Problem", "FieldError",
+	} {
+		assert.Contains(t, doc.Components.Schemas, name)
+	}
+
+	problem := doc.Components.Schemas["Problem"]
+	assert.Equal(t, "object", problem.Type)
+	assert.Contains(t, problem.Required, "status")
+	assert.NotContains(t, problem.Required, "errors")
+
+	// Timestamps are strings, not the underlying struct
+	createdAt := doc.Components.Schemas["Commute"].Properties["createdAt"]
+	require.NotNil(t, createdAt)
+	assert.Equal(t, "date-time", createdAt.Format)
+
+	// Embedded structs contribute their fields
+	created := doc.Components.Schemas["WebhookCreated"]
+	assert.Contains(t, created.Properties, "id")
+	assert.Contains(t, created.Properties, "secret")
+}
+
+func TestSpec_Operations(t *testing.T) {
+	doc := openapi.Spec("")
+
+	compute := doc.Paths["/v1/routes:compute"]["post"]
+	require.NotNil(t, compute)
+	assert.Equal(t, "#/components/schemas/RouteComputeRequest", compute.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/RouteComputeResponse", compute.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/RouteFeatureCollection", compute.Responses["200"].Content["application/geo+json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Problem", compute.Responses["default"].Content["application/problem+json"].Schema.Ref)
+	// Anonymous or authenticated
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}, {}}, compute.Security)
+
+	get := doc.Paths["/v1/me/commutes/{commuteId}"]["get"]
+	require.NotNil(t, get)
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, "commuteId", get.Parameters[0].Name)
+	assert.Equal(t, "path", get.Parameters[0].In)
+	assert.NotEmpty(t, get.Security)
+
+	create := doc.Paths["/v1/me/commutes"]["post"]
+	require.NotNil(t, create)
+	assert.Contains(t, create.Responses, "201")
+}
+
+func TestSpec_RefsResolve(t *testing.T) {
+	doc := openapi.Spec("")
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var raw any
+	require.NoError(t, json.Unmarshal(data, &raw))
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/schemas/")
+				assert.Contains(t, doc.Components.Schemas, name, "unresolved $ref %s", ref)
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(raw)
+}
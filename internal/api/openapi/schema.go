@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Schema is an OpenAPI 3 schema object, limited to what the API models need.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timestampType = reflect.TypeOf(models.Timestamp{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaRegistry derives schemas from Go types by their JSON encoding. Named
+// structs become components referenced by $ref, so shared models such as
+// Point are described once.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema for values of type t.
+func (g *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType || t == timestampType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType) || t.Implements(textType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.register(t)}
+	default:
+		// Interfaces and anything else accept any JSON value
+		return &Schema{}
+	}
+}
+
+// register adds the named struct t to the components, returning its name.
+func (g *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		// Same name in another package, e.g. auth.FieldError
+		pkg := []rune(path.Base(t.PkgPath()))
+		pkg[0] = unicode.ToUpper(pkg[0])
+		name = string(pkg) + name
+	}
+
+	// Reserve the name first so recursive types terminate
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t)
+	return name
+}
+
+// structSchema describes the JSON object encoding a struct. Fields without
+// omitempty are required; embedded structs contribute their fields.
+func (g *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded := g.structSchema(ft)
+				for prop, schema := range embedded.Properties {
+					s.Properties[prop] = schema
+				}
+				s.Required = append(s.Required, embedded.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schemaFor(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
@@ -18,58 +18,101 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
+// GeoJSON writes a GeoJSON response with the given status code.
+func GeoJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", models.MediaTypeGeoJSON)
+	w.WriteHeader(status)
+	if data != nil {
+		_ = json.NewEncoder(w).Encode(data)
+	}
+}
+
 // Error writes a Problem+JSON error response.
+//
+// The status helpers below take the machine-readable code to report; pass the
+// most specific models.ErrorCode that applies.
 func Error(w http.ResponseWriter, r *http.Request, problem *models.Problem) {
 	problem.Instance = r.URL.Path
 	problem.Write(w)
 }
 
 // BadRequest writes a 400 Bad Request error response.
-func BadRequest(w http.ResponseWriter, r *http.Request, detail string, errors []models.FieldError) {
+func BadRequest(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string, errors []models.FieldError) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewBadRequest(traceID, detail, errors)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
 // Unauthorized writes a 401 Unauthorized error response.
-func Unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+func Unauthorized(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewUnauthorized(traceID, detail)
+	problem.Code = code
+	Error(w, r, problem)
+}
+
+// Forbidden writes a 403 Forbidden error response.
+func Forbidden(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
+	traceID := middleware.GetRequestID(r.Context())
+	problem := models.NewForbidden(traceID, detail)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
 // NotFound writes a 404 Not Found error response.
-func NotFound(w http.ResponseWriter, r *http.Request, detail string) {
+func NotFound(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewNotFound(traceID, detail)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
 // Conflict writes a 409 Conflict error response.
-func Conflict(w http.ResponseWriter, r *http.Request, detail string) {
+func Conflict(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewConflict(traceID, detail)
+	problem.Code = code
+	Error(w, r, problem)
+}
+
+// PreconditionFailed writes a 412 Precondition Failed error response.
+func PreconditionFailed(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
+	traceID := middleware.GetRequestID(r.Context())
+	problem := models.NewPreconditionFailed(traceID, detail)
+	problem.Code = code
+	Error(w, r, problem)
+}
+
+// UnprocessableEntity writes a 422 Unprocessable Entity error response.
+func UnprocessableEntity(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
+	traceID := middleware.GetRequestID(r.Context())
+	problem := models.NewUnprocessableEntity(traceID, detail)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
 // TooManyRequests writes a 429 Too Many Requests error response.
-func TooManyRequests(w http.ResponseWriter, r *http.Request, detail string) {
+func TooManyRequests(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewTooManyRequests(traceID, detail)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
 // InternalError writes a 500 Internal Server Error response.
-func InternalError(w http.ResponseWriter, r *http.Request, detail string) {
+func InternalError(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewInternalError(traceID, detail)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
 // ServiceUnavailable writes a 503 Service Unavailable error response.
-func ServiceUnavailable(w http.ResponseWriter, r *http.Request, detail string) {
+func ServiceUnavailable(w http.ResponseWriter, r *http.Request, code models.ErrorCode, detail string) {
 	traceID := middleware.GetRequestID(r.Context())
 	problem := models.NewServiceUnavailable(traceID, detail)
+	problem.Code = code
 	Error(w, r, problem)
 }
 
@@ -0,0 +1,58 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// EventStream writes Server-Sent Events (text/event-stream) to a client.
+type EventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewEventStream sets the SSE response headers and writes the 200 status.
+// Events are flushed to the client as they are sent.
+func NewEventStream(w http.ResponseWriter) *EventStream {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering
+	w.WriteHeader(http.StatusOK)
+
+	return &EventStream{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+}
+
+// Send writes a named event with a JSON-encoded data payload and flushes it.
+func (s *EventStream) Send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding event data: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("flushing event: %w", err)
+	}
+	return nil
+}
+
+// Comment writes an SSE comment line and flushes it. Clients ignore comments,
+// so they serve as keep-alives on otherwise idle streams.
+func (s *EventStream) Comment(text string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", text); err != nil {
+		return fmt.Errorf("writing comment: %w", err)
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("flushing comment: %w", err)
+	}
+	return nil
+}
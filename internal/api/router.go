@@ -2,19 +2,31 @@
 package api
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 
+	"github.com/breatheroute/breatheroute/internal/airquality"
 	"github.com/breatheroute/breatheroute/internal/api/handler"
 	"github.com/breatheroute/breatheroute/internal/api/middleware"
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/audit"
 	"github.com/breatheroute/breatheroute/internal/auth"
 	"github.com/breatheroute/breatheroute/internal/commute"
 	"github.com/breatheroute/breatheroute/internal/device"
+	"github.com/breatheroute/breatheroute/internal/exposure"
 	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/pollen"
 	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+	"github.com/breatheroute/breatheroute/internal/routestore"
 	"github.com/breatheroute/breatheroute/internal/routing"
+	"github.com/breatheroute/breatheroute/internal/transit"
 	"github.com/breatheroute/breatheroute/internal/user"
+	"github.com/breatheroute/breatheroute/internal/weather"
+	"github.com/breatheroute/breatheroute/internal/webhook"
 )
 
 // RouterConfig holds configuration for the router.
@@ -30,7 +42,53 @@ type RouterConfig struct {
 	CommuteService     *commute.Service
 	DeviceService      *device.Service
 	RoutingService     *routing.Service
+	TransitService     *transit.Service
+	AirQualityService  *airquality.Service
+	WeatherService     *weather.Service
+	PollenService      *pollen.Service
+	WebhookService     *webhook.Service
 	ProviderRegistry   *resilience.Registry
+	// AuditService records changes to commutes, devices and profiles, and
+	// serves /v1/admin/users/{userId}/audit (optional).
+	AuditService *audit.Service
+	// ExposureService serves /v1/me/exposure/history (optional).
+	ExposureService *exposure.Service
+	// RouteStore keeps computed routes for /v1/routes/{routeId} and GPX
+	// export (optional).
+	RouteStore *routestore.Service
+	// ExposureConfidence sets when route options are flagged as having an
+	// unreliable exposure estimate. Requires AirQualityService.
+	ExposureConfidence handler.ExposureConfidenceConfig
+	// ExposureSampling sets how densely routes are sampled for air quality,
+	// trading exposure accuracy against interpolation cost on long routes.
+	ExposureSampling handler.ExposureSamplingConfig
+	// EstimateRoutesWhenUnavailable returns straight-line route estimates
+	// for modes the routing provider cannot currently route.
+	EstimateRoutesWhenUnavailable bool
+	// CacheStaleThresholds sets, by cache name (routing, airquality,
+	// transit, weather, pollen), how old a cache's newest data may get
+	// before /v1/ops/status reports it as serving stale data (default:
+	// handler.DefaultCacheStaleThreshold).
+	CacheStaleThresholds map[string]time.Duration
+	// MaxDisruptionStreams caps concurrent /v1/transit/disruptions/stream
+	// connections (default: handler.DefaultMaxDisruptionStreams).
+	MaxDisruptionStreams int
+	// ProviderNames maps data domains (routing, airquality, transit, pollen,
+	// weather) to the configured provider name, reported by /v1/ops/health.
+	// The routing entry is filled from RoutingService when not set.
+	ProviderNames map[string]string
+	// ReadinessChecks are the subsystems /v1/ops/ready requires, by name
+	// (e.g. "database"). With none, the readiness check always reports OK.
+	ReadinessChecks map[string]handler.ReadinessCheckFunc
+	// RefreshMetrics reports the provider refresh job's metrics in
+	// /v1/ops/metrics when the job runs in this process (optional).
+	RefreshMetrics handler.RefreshMetricsFunc
+	// DatabasePoolStats reports connection pool usage in /v1/ops/status
+	// (optional).
+	DatabasePoolStats handler.PoolStatsFunc
+	// CORS sets the browser origins allowed to call the API. The zero value
+	// refuses all cross-origin requests.
+	CORS middleware.CORSConfig
 	// DevMode enables development-only endpoints (e.g., /auth/dev).
 	// Should never be true in production.
 	DevMode bool
@@ -53,39 +111,98 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 		r.Use(cfg.Metrics.Middleware()) // HTTP metrics
 	}
 	r.Use(middleware.Logger(cfg.Logger))   // Structured logging
+	r.Use(middleware.Compress)             // gzip/deflate, inside Logger so sizes are bytes sent
 	r.Use(middleware.Recovery(cfg.Logger)) // Panic recovery
 	r.Use(chimiddleware.RealIP)            // Real IP extraction
+	r.Use(middleware.CORS(cfg.CORS))       // CORS, answering preflights before routing and auth
 	r.Use(middleware.SecurityHeaders)      // Security headers (HSTS, CSP, etc.)
 	r.Use(middleware.RequireTLS)           // TLS enforcement (enabled via REQUIRE_TLS=true)
+	r.Use(maintenanceMode(cfg))            // Reject writes while the maintenance_mode flag is set
 	r.Use(middleware.ContentTypeJSON)      // JSON content type
+	r.Use(middleware.ReplicaReads)         // Read replica for GET requests
 
 	// Initialize handlers
 	opsHandler := handler.NewOpsHandler(cfg.Version, cfg.BuildTime).
-		WithProviderRegistry(cfg.ProviderRegistry)
+		WithProviderRegistry(cfg.ProviderRegistry).
+		WithProviderNames(providerNames(cfg)).
+		WithPoolStats(cfg.DatabasePoolStats).
+		WithRefreshMetrics(cfg.RefreshMetrics)
+	for name, check := range cfg.ReadinessChecks {
+		opsHandler.WithReadinessCheck(name, check)
+	}
+	cacheHandler := handler.NewCacheHandler()
+	for name, cache := range managedCaches(cfg) {
+		cacheHandler.WithCache(name, cache)
+		opsHandler.WithCacheStats(name, cache.Stats).
+			WithStaleThreshold(name, cfg.CacheStaleThresholds[name])
+	}
 	authHandler := handler.NewAuthHandler(cfg.AuthService)
 	meHandler := handler.NewMeHandler(cfg.UserService)
 	profileHandler := handler.NewProfileHandler(cfg.UserService)
-	commuteHandler := handler.NewCommuteHandler(cfg.CommuteService)
-	routeHandler := handler.NewRouteHandler(cfg.RoutingService, cfg.Logger)
+	commuteHandler := handler.NewCommuteHandler(cfg.CommuteService).
+		WithAirQualityService(cfg.AirQualityService).
+		WithPollenService(cfg.PollenService)
+	routeHandler := handler.NewRouteHandler(cfg.RoutingService, cfg.Logger).
+		WithTransitService(cfg.TransitService).
+		WithAirQualityService(cfg.AirQualityService).
+		WithWeatherService(cfg.WeatherService).
+		WithExposureConfidence(cfg.ExposureConfidence).
+		WithExposureSampling(cfg.ExposureSampling).
+		WithRouteStore(cfg.RouteStore).
+		WithMaintenanceCheck(cfg.FeatureFlagService.IsMaintenanceMode).
+		WithUserService(cfg.UserService).
+		WithEstimatedFallback(cfg.EstimateRoutesWhenUnavailable)
 	alertHandler := handler.NewAlertHandler()
 	deviceHandler := handler.NewDeviceHandler(cfg.DeviceService)
 	gdprHandler := handler.NewGDPRHandler()
 	metadataHandler := handler.NewMetadataHandler()
+	airQualityHandler := handler.NewAirQualityHandler(cfg.AirQualityService)
 	featureFlagsHandler := handler.NewFeatureFlagsHandler(cfg.FeatureFlagService)
+	transitHandler := handler.NewTransitHandler(cfg.TransitService, cfg.UserService).
+		WithMaxStreams(cfg.MaxDisruptionStreams)
+	webhookHandler := handler.NewWebhookHandler(cfg.WebhookService)
+	auditHandler := handler.NewAuditHandler(cfg.AuditService)
+	exposureHandler := handler.NewExposureHandler(cfg.ExposureService)
+	openAPIHandler := handler.NewOpenAPIHandler(cfg.Version)
 
 	// Create auth middleware
 	authMiddleware := middleware.Auth(cfg.AuthService)
+	optionalAuth := middleware.OptionalAuth(cfg.AuthService)
+
+	// Create audit middleware for user resources. A nil *audit.Service must
+	// not become a non-nil interface, or every mutation would panic.
+	var auditRecorder middleware.AuditRecorder
+	if cfg.AuditService != nil {
+		auditRecorder = cfg.AuditService
+	}
+	auditCommutes := middleware.Audit(auditRecorder, audit.ResourceCommute)
+	auditDevices := middleware.Audit(auditRecorder, audit.ResourceDevice)
+	auditProfile := middleware.Audit(auditRecorder, audit.ResourceProfile)
 
 	// Create rate limit middleware for different endpoint categories
 	authRateLimit := middleware.RateLimitByIP(middleware.AuthRateLimit)           // 10 req/min
 	expensiveRateLimit := middleware.RateLimitByIP(middleware.ExpensiveRateLimit) // 30 req/min
 	standardRateLimit := middleware.RateLimitByIP(middleware.StandardRateLimit)   // 100 req/min
 
+	// Create body limit middleware, applied per route so bulk payloads are not
+	// capped like small settings updates
+	smallBody := middleware.LimitBody(middleware.SmallBodyLimit)             // 16 KiB
+	strictSmallBody := middleware.LimitBody(middleware.StrictSmallBodyLimit) // 16 KiB, unknown fields rejected
+	standardBody := middleware.LimitBody(middleware.StandardBodyLimit)       // 64 KiB
+	largeBody := middleware.LimitBody(middleware.LargeBodyLimit)             // 1 MiB
+
+	// Create request timeout middleware, applied per route so long-lived event
+	// streams are not cut off
+	standardTimeout := middleware.Timeout(middleware.StandardTimeout)   // 8s
+	expensiveTimeout := middleware.Timeout(middleware.ExpensiveTimeout) // 12s
+
 	// API v1 routes
 	r.Route("/v1", func(r chi.Router) {
 		// Auth endpoints (public) - strict rate limiting
 		r.Route("/auth", func(r chi.Router) {
+			r.Use(standardTimeout)
 			r.Use(authRateLimit) // 10 requests per minute per IP
+			r.Use(smallBody)
 			r.Post("/siwa", authHandler.SignInWithApple)
 			r.Post("/refresh", authHandler.RefreshToken)
 			r.Post("/logout", authHandler.Logout)
@@ -100,14 +217,30 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 
 		// Ops endpoints (public)
 		r.Route("/ops", func(r chi.Router) {
+			r.Use(standardTimeout)
 			r.Get("/health", opsHandler.HealthCheck)
 			r.Get("/ready", opsHandler.ReadinessCheck)
 			// Status endpoint requires authentication
 			r.With(authMiddleware).Get("/status", opsHandler.SystemStatus)
+
+			// Metrics, flag history, cache inspection and invalidation are admin-only
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(middleware.RequireRole(auth.RoleAdmin))
+				r.Use(smallBody)
+				r.Get("/metrics", opsHandler.Metrics)
+				r.Get("/flags/{key}/history", featureFlagsHandler.GetFlagHistory)
+				r.Get("/cache", cacheHandler.GetCaches)
+				r.Post("/cache:invalidate", cacheHandler.InvalidateCaches)
+			})
 		})
 
+		// Machine-readable API contract (public)
+		r.With(standardTimeout, standardRateLimit).Get("/openapi.json", openAPIHandler.GetSpec)
+
 		// Metadata endpoints (public) - standard rate limiting
 		r.Route("/metadata", func(r chi.Router) {
+			r.Use(standardTimeout)
 			r.Use(standardRateLimit)
 			r.Get("/air-quality/stations", metadataHandler.ListAirQualityStations)
 			r.Get("/enums", metadataHandler.GetEnums)
@@ -115,32 +248,45 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 
 		// Me endpoints (authenticated) - user-based rate limiting
 		r.Route("/me", func(r chi.Router) {
+			r.Use(standardTimeout)
 			r.Use(authMiddleware)
 			r.Use(middleware.RateLimitByUser(middleware.StandardRateLimit)) // 100 req/min per user
 			r.Get("/", meHandler.GetMe)
-			r.Put("/", meHandler.UpdateMe)
+			r.With(strictSmallBody).Put("/", meHandler.UpdateMe)
+
+			// Linked sign-in identities
+			r.Get("/identities", authHandler.ListIdentities)
 
 			// Consents
 			r.Get("/consents", meHandler.GetConsents)
-			r.Put("/consents", meHandler.UpdateConsents)
+			r.With(strictSmallBody).Put("/consents", meHandler.UpdateConsents)
 
 			// Profile
 			r.Get("/profile", profileHandler.GetProfile)
-			r.Put("/profile", profileHandler.UpsertProfile)
+			r.With(strictSmallBody, auditProfile).Put("/profile", profileHandler.UpsertProfile)
+			r.With(strictSmallBody, auditProfile).Patch("/profile", profileHandler.PatchProfile)
+
+			// Weekly exposure on completed commute trips
+			r.Get("/exposure/history", exposureHandler.GetExposureHistory)
 
 			// Commutes
 			r.Route("/commutes", func(r chi.Router) {
+				r.Use(standardBody)
+				r.Use(auditCommutes)
 				r.Get("/", commuteHandler.ListCommutes)
 				r.Post("/", commuteHandler.CreateCommute)
+				r.Post("/{commuteId}:clone", commuteHandler.CloneCommute)
 				r.Route("/{commuteId}", func(r chi.Router) {
 					r.Get("/", commuteHandler.GetCommute)
 					r.Put("/", commuteHandler.UpdateCommute)
 					r.Delete("/", commuteHandler.DeleteCommute)
+					r.Get("/best-day", commuteHandler.GetBestDay)
 				})
 			})
 
 			// Alert subscriptions
 			r.Route("/alerts/subscriptions", func(r chi.Router) {
+				r.Use(standardBody)
 				r.Get("/", alertHandler.ListAlertSubscriptions)
 				r.Post("/", alertHandler.CreateAlertSubscription)
 				r.Route("/{subscriptionId}", func(r chi.Router) {
@@ -152,22 +298,62 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 
 			// Devices
 			r.Route("/devices", func(r chi.Router) {
+				r.Use(smallBody)
+				r.Use(auditDevices)
 				r.Get("/", deviceHandler.ListDevices)
 				r.Post("/", deviceHandler.RegisterDevice)
 				r.Delete("/{deviceId}", deviceHandler.UnregisterDevice)
 			})
+
+			// Webhooks for transit disruption changes
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Use(smallBody)
+				r.Get("/", webhookHandler.ListWebhooks)
+				r.Post("/", webhookHandler.CreateWebhook)
+				r.Delete("/{webhookId}", webhookHandler.DeleteWebhook)
+			})
+
+			// Route share links; shares are created on the route itself
+			r.Delete("/route-shares/{shareToken}", routeHandler.RevokeRouteShare)
+		})
+
+		// Routes endpoints - public, but computed routes are owned by the
+		// signed-in user, if any. Compute is expensive, strict rate limiting.
+		r.With(expensiveTimeout, expensiveRateLimit, standardBody, optionalAuth).Post("/routes:compute", routeHandler.ComputeRoutes)
+		r.Route("/routes/{routeId}", func(r chi.Router) {
+			r.Use(standardTimeout, standardRateLimit, optionalAuth)
+			r.Get("/", routeHandler.GetRoute)
+			r.Get("/export.gpx", routeHandler.ExportGPX)
+		})
+		r.With(standardTimeout, standardRateLimit, authMiddleware).Post("/routes/{routeId}:share", routeHandler.ShareRoute)
+
+		// Shared routes (public, read-only)
+		r.With(standardTimeout, standardRateLimit).Get("/public/routes/{shareToken}", routeHandler.GetPublicRoute)
+
+		// Air quality endpoints (public)
+		r.Route("/air-quality", func(r chi.Router) {
+			// Grid for map overlays - bounded by a cell cap, expensive rate limiting
+			r.With(expensiveTimeout, expensiveRateLimit, smallBody).Post("/grid", airQualityHandler.GetGrid)
+			r.With(standardTimeout, standardRateLimit).Get("/nearest", airQualityHandler.GetNearestStation)
 		})
 
-		// Routes endpoint - expensive compute, strict rate limiting
-		r.With(expensiveRateLimit).Post("/routes:compute", routeHandler.ComputeRoutes)
+		// Transit disruptions (authenticated, advisory localized to the user)
+		r.Route("/transit", func(r chi.Router) {
+			r.Use(authMiddleware)
+			r.Use(middleware.RateLimitByUser(middleware.StandardRateLimit)) // 100 req/min per user
+			r.With(standardTimeout).Get("/disruptions", transitHandler.GetRouteDisruptions)
+			r.Get("/disruptions/stream", transitHandler.StreamDisruptions) // long-lived, no timeout
+		})
 
 		// Alerts preview endpoint - standard rate limiting
-		r.With(standardRateLimit).Post("/alerts/preview", alertHandler.PreviewDepartureWindows)
+		r.With(standardTimeout, standardRateLimit, standardBody).Post("/alerts/preview", alertHandler.PreviewDepartureWindows)
 
 		// GDPR endpoints (authenticated) - user-based rate limiting
 		r.Route("/gdpr", func(r chi.Router) {
+			r.Use(standardTimeout)
 			r.Use(authMiddleware)
 			r.Use(middleware.RateLimitByUser(middleware.StandardRateLimit)) // 100 req/min per user
+			r.Use(largeBody)
 			r.Route("/export-requests", func(r chi.Router) {
 				r.Get("/", gdprHandler.ListExportRequests)
 				r.Post("/", gdprHandler.CreateExportRequest)
@@ -180,10 +366,13 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 			})
 		})
 
-		// Admin endpoints (authenticated) - for internal operations
+		// Admin endpoints (admin role) - for internal operations
 		r.Route("/admin", func(r chi.Router) {
+			r.Use(standardTimeout)
 			r.Use(authMiddleware)
+			r.Use(middleware.RequireRole(auth.RoleAdmin))
 			r.Use(standardRateLimit)
+			r.Use(standardBody)
 
 			// Feature flags management
 			r.Route("/feature-flags", func(r chi.Router) {
@@ -191,8 +380,156 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 				r.Put("/", featureFlagsHandler.UpsertFeatureFlags)
 				r.Post("/invalidate", featureFlagsHandler.InvalidateCache)
 			})
+
+			// Audit history of a user's changes
+			r.Get("/users/{userId}/audit", auditHandler.ListUserAuditEntries)
 		})
 	})
 
 	return r
 }
+
+// maintenanceMode returns middleware rejecting writes while the
+// maintenance_mode feature flag is set. Ops and admin endpoints stay
+// writable so operators can work during maintenance, and token refresh and
+// logout so sessions keep working; sign-in creates users, so it is
+// rejected. Route computation, the air quality grid and alert previews are
+// POSTs but only read, so they stay available; the route handler skips
+// storing the result instead.
+func maintenanceMode(cfg RouterConfig) func(http.Handler) http.Handler {
+	return middleware.Maintenance(cfg.FeatureFlagService.IsMaintenanceMode,
+		"/v1/ops/", "/v1/admin/", "/v1/auth/refresh", "/v1/auth/logout",
+		"/v1/routes:compute", "/v1/air-quality/grid", "/v1/alerts/preview")
+}
+
+// providerNames returns the configured provider names, filling in the routing
+// provider from the routing service when the caller did not set it.
+func providerNames(cfg RouterConfig) map[string]string {
+	names := make(map[string]string, len(cfg.ProviderNames)+1)
+	for domain, name := range cfg.ProviderNames {
+		names[domain] = name
+	}
+	if _, ok := names["routing"]; !ok && cfg.RoutingService != nil {
+		names["routing"] = cfg.RoutingService.ProviderName()
+	}
+	return names
+}
+
+// managedCaches adapts the configured services' cache statistics for the
+// operator cache endpoints, keyed by service name.
+func managedCaches(cfg RouterConfig) map[string]handler.ManagedCache {
+	caches := make(map[string]handler.ManagedCache)
+	if svc := cfg.RoutingService; svc != nil {
+		caches["routing"] = handler.ManagedCache{
+			Stats: func() models.ServiceCache {
+				stats := svc.CacheStats()
+				return models.ServiceCache{
+					Provider:      stats.Provider,
+					Entries:       stats.TotalEntries,
+					FreshEntries:  stats.FreshEntries,
+					Hits:          stats.Hits,
+					Misses:        stats.Misses,
+					Evictions:     stats.Evictions,
+					FetchedAt:     optionalTimestamp(stats.NewestFetchedAt),
+					OldestFreshAt: optionalTimestamp(stats.OldestFreshAt),
+				}
+			},
+			Invalidate: svc.InvalidateCache,
+		}
+	}
+	if svc := cfg.AirQualityService; svc != nil {
+		caches["airquality"] = handler.ManagedCache{
+			Stats: func() models.ServiceCache {
+				status := svc.CacheStatus()
+				cache := models.ServiceCache{Provider: status.Provider}
+				if status.HasData {
+					fetchedAt := models.Timestamp(status.FetchedAt)
+					cache.Entries = 1
+					cache.FetchedAt = &fetchedAt
+					if !status.IsExpired {
+						cache.FreshEntries = 1
+						cache.OldestFreshAt = &fetchedAt
+					}
+				}
+				return cache
+			},
+			Invalidate: svc.InvalidateCache,
+		}
+	}
+	if svc := cfg.TransitService; svc != nil {
+		caches["transit"] = handler.ManagedCache{
+			Stats: func() models.ServiceCache {
+				stats := svc.CacheStats()
+				cache := models.ServiceCache{
+					Provider:      stats.Provider,
+					Entries:       stats.RouteCacheEntries,
+					Hits:          stats.Hits,
+					Misses:        stats.Misses,
+					FetchedAt:     optionalTimestamp(stats.NewestFetchedAt),
+					OldestFreshAt: optionalTimestamp(stats.OldestFreshAt),
+				}
+				// The disruption and station lists count as one entry each
+				if stats.HasDisruptionCache {
+					cache.Entries++
+				}
+				if stats.DisruptionCacheFresh {
+					cache.FreshEntries++
+				}
+				if stats.HasStationCache {
+					cache.Entries++
+				}
+				if stats.StationCacheFresh {
+					cache.FreshEntries++
+				}
+				return cache
+			},
+			Invalidate: svc.InvalidateCache,
+		}
+	}
+	if svc := cfg.WeatherService; svc != nil {
+		caches["weather"] = handler.ManagedCache{
+			Stats: func() models.ServiceCache {
+				stats := svc.CacheStats()
+				return models.ServiceCache{
+					Provider:      stats.Provider,
+					Entries:       stats.WeatherEntries + stats.ForecastEntries,
+					FreshEntries:  stats.WeatherFreshEntries + stats.ForecastFreshEntries,
+					Hits:          stats.Hits,
+					Misses:        stats.Misses,
+					Evictions:     stats.Evictions,
+					FetchedAt:     optionalTimestamp(stats.NewestFetchedAt),
+					OldestFreshAt: optionalTimestamp(stats.OldestFreshAt),
+				}
+			},
+			Invalidate: svc.InvalidateCache,
+		}
+	}
+	if svc := cfg.PollenService; svc != nil {
+		caches["pollen"] = handler.ManagedCache{
+			Stats: func() models.ServiceCache {
+				stats := svc.CacheStats()
+				return models.ServiceCache{
+					Provider:      stats.Provider,
+					Entries:       stats.PollenEntries + stats.ForecastEntries,
+					FreshEntries:  stats.PollenFreshEntries + stats.ForecastFreshEntries,
+					Hits:          stats.Hits,
+					Misses:        stats.Misses,
+					Evictions:     stats.Evictions,
+					FetchedAt:     optionalTimestamp(stats.NewestFetchedAt),
+					OldestFreshAt: optionalTimestamp(stats.OldestFreshAt),
+				}
+			},
+			Invalidate: svc.InvalidateCache,
+		}
+	}
+	return caches
+}
+
+// optionalTimestamp returns t as a Timestamp, or nil if it is zero.
+func optionalTimestamp(t time.Time) *models.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	ts := models.Timestamp(t)
+	return &ts
+}
@@ -1,27 +1,42 @@
 package api_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/breatheroute/breatheroute/internal/airquality"
 	"github.com/breatheroute/breatheroute/internal/api"
+	"github.com/breatheroute/breatheroute/internal/api/handler"
+	"github.com/breatheroute/breatheroute/internal/api/middleware"
 	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/audit"
 	"github.com/breatheroute/breatheroute/internal/auth"
 	"github.com/breatheroute/breatheroute/internal/commute"
 	"github.com/breatheroute/breatheroute/internal/device"
+	"github.com/breatheroute/breatheroute/internal/exposure"
+	"github.com/breatheroute/breatheroute/internal/featureflags"
 	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+	"github.com/breatheroute/breatheroute/internal/routestore"
 	"github.com/breatheroute/breatheroute/internal/routing"
+	"github.com/breatheroute/breatheroute/internal/transit"
 	"github.com/breatheroute/breatheroute/internal/user"
+	"github.com/breatheroute/breatheroute/internal/webhook"
 )
 
 // testAuthService creates an auth service for testing.
@@ -59,12 +74,20 @@ func testJWTService() *auth.JWTService {
 
 // generateTestToken generates a valid test token for a user.
 func generateTestToken(t *testing.T) string {
+	t.Helper()
+	return generateTestTokenWithRole(t, auth.RoleUser)
+}
+
+// generateTestTokenWithRole generates a valid test token for the test user
+// with the given role.
+func generateTestTokenWithRole(t *testing.T, role auth.Role) string {
 	t.Helper()
 	jwtService := testJWTService()
 	user := &auth.User{
 		ID:        "usr_testuser123",
 		AppleSub:  "apple.123",
 		Locale:    "nl-NL",
+		Role:      role,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -144,18 +167,133 @@ func testRoutingService() *routing.Service {
 	})
 }
 
+// mockTransitProvider is a mock transit provider for testing.
+type mockTransitProvider struct{}
+
+func (m *mockTransitProvider) GetAllDisruptions(_ context.Context) ([]*transit.Disruption, error) {
+	return []*transit.Disruption{
+		{
+			ID:                   "d1",
+			Type:                 transit.DisruptionDisturbance,
+			Title:                "Signal failure",
+			Impact:               transit.ImpactMajor,
+			AffectedStations:     []string{"ASD"},
+			AffectedStationNames: []string{"Amsterdam Centraal"},
+			Start:                time.Now().Add(-time.Hour),
+		},
+	}, nil
+}
+
+func (m *mockTransitProvider) GetDisruptionsForRoute(ctx context.Context, origin, destination string) (*transit.RouteDisruptions, error) {
+	all, _ := m.GetAllDisruptions(ctx)
+	var relevant []*transit.Disruption
+	for _, d := range all {
+		if d.AffectsStation(origin) || d.AffectsStation(destination) {
+			relevant = append(relevant, d)
+		}
+	}
+	return &transit.RouteDisruptions{
+		Origin:         origin,
+		Destination:    destination,
+		Disruptions:    relevant,
+		HasDisruptions: len(relevant) > 0,
+		OverallImpact:  transit.CalculateOverallImpact(relevant),
+		FetchedAt:      time.Now(),
+	}, nil
+}
+
+func (m *mockTransitProvider) GetStations(_ context.Context) ([]*transit.Station, error) {
+	return nil, nil
+}
+
+func (m *mockTransitProvider) Name() string {
+	return "test-transit"
+}
+
+// mockAirQualityProvider serves a single Amsterdam station.
+type mockAirQualityProvider struct{}
+
+func (m *mockAirQualityProvider) FetchSnapshot(_ context.Context) (*airquality.AQSnapshot, error) {
+	snapshot := airquality.NewAQSnapshot("test-aq")
+	snapshot.Stations["NL10938"] = &airquality.Station{
+		ID:         "NL10938",
+		Name:       "Amsterdam-Einsteinweg",
+		Lat:        52.366,
+		Lon:        4.859,
+		Pollutants: []airquality.Pollutant{airquality.PollutantNO2},
+		UpdatedAt:  time.Now(),
+	}
+	snapshot.SetMeasurement(&airquality.Measurement{
+		StationID:  "NL10938",
+		Pollutant:  airquality.PollutantNO2,
+		Value:      24.0,
+		Unit:       "µg/m³",
+		MeasuredAt: time.Now(),
+	})
+	return snapshot, nil
+}
+
+func (m *mockAirQualityProvider) FetchStations(ctx context.Context) ([]*airquality.Station, error) {
+	snapshot, _ := m.FetchSnapshot(ctx)
+	return snapshot.StationList(), nil
+}
+
+func (m *mockAirQualityProvider) FetchLatestMeasurements(_ context.Context) ([]*airquality.Measurement, error) {
+	return nil, nil
+}
+
+// unavailableAirQualityProvider is an air quality provider that is down.
+type unavailableAirQualityProvider struct {
+	mockAirQualityProvider
+}
+
+func (m *unavailableAirQualityProvider) FetchSnapshot(_ context.Context) (*airquality.AQSnapshot, error) {
+	return nil, errors.New("connection refused")
+}
+
+// testAirQualityService creates an air quality service for testing.
+func testAirQualityService() *airquality.Service {
+	return airquality.NewService(airquality.ServiceConfig{
+		Provider: &mockAirQualityProvider{},
+		Logger:   zerolog.New(io.Discard),
+	})
+}
+
+// testTransitService creates a transit service for testing.
+func testTransitService() *transit.Service {
+	return transit.NewService(transit.ServiceConfig{
+		Provider: &mockTransitProvider{},
+		Logger:   zerolog.New(io.Discard),
+	})
+}
+
 func newTestRouter() http.Handler {
 	logger := zerolog.New(io.Discard)
 	return api.NewRouter(api.RouterConfig{
-		Version:          "test",
-		BuildTime:        "2024-01-01T00:00:00Z",
-		Logger:           logger,
-		AuthService:      testAuthService(),
-		UserService:      testUserService(),
-		CommuteService:   testCommuteService(),
-		DeviceService:    testDeviceService(),
-		RoutingService:   testRoutingService(),
-		ProviderRegistry: testProviderRegistry(),
+		Version:           "test",
+		BuildTime:         "2024-01-01T00:00:00Z",
+		Logger:            logger,
+		AuthService:       testAuthService(),
+		UserService:       testUserService(),
+		CommuteService:    testCommuteService(),
+		DeviceService:     testDeviceService(),
+		RoutingService:    testRoutingService(),
+		TransitService:    testTransitService(),
+		AirQualityService: testAirQualityService(),
+		WebhookService:    testWebhookService(),
+		ProviderRegistry:  testProviderRegistry(),
+		RouteStore:        routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()}),
+		FeatureFlagService: featureflags.NewService(featureflags.ServiceConfig{
+			Repository: featureflags.NewInMemoryRepository(),
+		}),
+	})
+}
+
+// testWebhookService creates a webhook service for testing.
+func testWebhookService() *webhook.Service {
+	return webhook.NewService(webhook.ServiceConfig{
+		Repository: webhook.NewInMemoryRepository(),
+		Logger:     zerolog.New(io.Discard),
 	})
 }
 
@@ -186,6 +324,73 @@ func TestRouter_HealthCheck(t *testing.T) {
 	assert.NotEmpty(t, health.Time)
 }
 
+func TestRouter_HealthCheck_ReportsBuildAndProviders(t *testing.T) {
+	router := api.NewRouter(api.RouterConfig{
+		Version:        "v1.4.2",
+		BuildTime:      "2026-03-01T12:00:00Z",
+		Logger:         zerolog.New(io.Discard),
+		AuthService:    testAuthService(),
+		RoutingService: testRoutingService(),
+		ProviderNames: map[string]string{
+			"airquality": "luchtmeetnet",
+			"transit":    "ns",
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/ops/health", http.NoBody)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var health models.Health
+	err := json.Unmarshal(w.Body.Bytes(), &health)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.4.2", health.Details["version"])
+	assert.Equal(t, "2026-03-01T12:00:00Z", health.Details["buildTime"])
+
+	providers, ok := health.Details["providers"].(map[string]interface{})
+	require.True(t, ok, "expected providers in health details")
+	assert.Equal(t, "test-provider", providers["routing"])
+	assert.Equal(t, "luchtmeetnet", providers["airquality"])
+	assert.Equal(t, "ns", providers["transit"])
+}
+
+func TestRouter_HealthCheck_ReportsEveryProviderDomain(t *testing.T) {
+	configured := map[string]string{
+		"routing":    "openrouteservice",
+		"airquality": "luchtmeetnet",
+		"transit":    "ns",
+		"pollen":     "ambee",
+		"weather":    "openweathermap",
+	}
+	router := api.NewRouter(api.RouterConfig{
+		Logger:         zerolog.New(io.Discard),
+		AuthService:    testAuthService(),
+		RoutingService: testRoutingService(),
+		ProviderNames:  configured,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/ops/health", http.NoBody)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var health models.Health
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+
+	providers, ok := health.Details["providers"].(map[string]interface{})
+	require.True(t, ok, "expected providers in health details")
+	require.Len(t, providers, len(configured))
+	for domain, name := range configured {
+		assert.Equal(t, name, providers[domain], domain)
+	}
+}
+
 func TestRouter_ReadinessCheck(t *testing.T) {
 	router := newTestRouter()
 
@@ -203,6 +408,59 @@ func TestRouter_ReadinessCheck(t *testing.T) {
 	assert.Equal(t, models.HealthStatusOK, health.Status)
 }
 
+func TestRouter_ReadinessCheck_NotReady(t *testing.T) {
+	aqService := airquality.NewService(airquality.ServiceConfig{
+		Provider: &unavailableAirQualityProvider{},
+		Logger:   zerolog.New(io.Discard),
+	})
+	router := api.NewRouter(api.RouterConfig{
+		Version: "test",
+		Logger:  zerolog.New(io.Discard),
+		ReadinessChecks: map[string]handler.ReadinessCheckFunc{
+			"database":   func(context.Context) error { return nil },
+			"airquality": aqService.CheckReady,
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/ops/ready", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var health models.Health
+	err := json.Unmarshal(w.Body.Bytes(), &health)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.HealthStatusFail, health.Status)
+	checks, ok := health.Details["checks"].(map[string]interface{})
+	require.True(t, ok, "expected checks in readiness details")
+	assert.Equal(t, "OK", checks["database"])
+	assert.NotEqual(t, "OK", checks["airquality"])
+
+	// Liveness does not depend on upstreams
+	req = httptest.NewRequest(http.MethodGet, "/v1/ops/health", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouter_ReadinessCheck_Ready(t *testing.T) {
+	router := api.NewRouter(api.RouterConfig{
+		Version: "test",
+		Logger:  zerolog.New(io.Discard),
+		ReadinessChecks: map[string]handler.ReadinessCheckFunc{
+			"airquality": testAirQualityService().CheckReady,
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/ops/ready", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestRouter_SystemStatus(t *testing.T) {
 	router := newTestRouter()
 
@@ -221,6 +479,245 @@ func TestRouter_SystemStatus(t *testing.T) {
 	assert.Equal(t, models.HealthStatusOK, status.Status)
 	assert.NotEmpty(t, status.Subsystems)
 	assert.NotEmpty(t, status.Providers)
+
+	caches := make([]string, 0, len(status.Caches))
+	for _, c := range status.Caches {
+		caches = append(caches, c.Name)
+	}
+	assert.Contains(t, caches, "routing")
+	assert.Contains(t, caches, "transit")
+}
+
+func TestRouter_SystemStatus_DatabasePool(t *testing.T) {
+	tests := []struct {
+		name       string
+		pool       models.DatabasePool
+		wantStatus models.HealthStatus
+	}{
+		{"spare capacity", models.DatabasePool{AcquiredConns: 3, IdleConns: 2, TotalConns: 5, MaxConns: 10}, models.HealthStatusOK},
+		{"exhausted", models.DatabasePool{AcquiredConns: 10, TotalConns: 10, MaxConns: 10}, models.HealthStatusDegraded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := api.NewRouter(api.RouterConfig{
+				Logger:            zerolog.New(io.Discard),
+				AuthService:       testAuthService(),
+				DatabasePoolStats: func() models.DatabasePool { return tt.pool },
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/ops/status", http.NoBody)
+			addAuthHeader(t, req)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var status models.SystemStatus
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+			require.NotNil(t, status.DatabasePool)
+			assert.Equal(t, tt.pool, *status.DatabasePool)
+
+			for _, s := range status.Subsystems {
+				if s.Name == "cloud-sql" {
+					assert.Equal(t, tt.wantStatus, s.Status)
+				}
+			}
+		})
+	}
+}
+
+// newCacheRouter creates a router with the routing, transit and air quality
+// caches.
+func newCacheRouter(routingService *routing.Service) http.Handler {
+	return api.NewRouter(api.RouterConfig{
+		Logger:            zerolog.New(io.Discard),
+		AuthService:       testAuthService(),
+		RoutingService:    routingService,
+		TransitService:    testTransitService(),
+		AirQualityService: testAirQualityService(),
+	})
+}
+
+// addAdminAuthHeader adds a valid Bearer token with the admin role.
+func addAdminAuthHeader(t *testing.T, req *http.Request) {
+	t.Helper()
+	req.Header.Set("Authorization", "Bearer "+generateTestTokenWithRole(t, auth.RoleAdmin))
+}
+
+func TestRouter_CORSPreflight(t *testing.T) {
+	router := api.NewRouter(api.RouterConfig{
+		Logger:      zerolog.New(io.Discard),
+		AuthService: testAuthService(),
+		UserService: testUserService(),
+		CORS:        middleware.CORSConfig{AllowedOrigins: []string{"https://app.breatheroute.nl"}},
+	})
+
+	// Preflights carry no credentials, so they are answered before auth
+	req := httptest.NewRequest(http.MethodOptions, "/v1/me", http.NoBody)
+	req.Header.Set("Origin", "https://app.breatheroute.nl")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	req.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.breatheroute.nl", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.NotEmpty(t, w.Header().Get("X-Request-Id"))
+
+	// The actual request still requires a token
+	req = httptest.NewRequest(http.MethodGet, "/v1/me", http.NoBody)
+	req.Header.Set("Origin", "https://app.breatheroute.nl")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "https://app.breatheroute.nl", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Unknown origins are refused
+	req = httptest.NewRequest(http.MethodOptions, "/v1/me", http.NoBody)
+	req.Header.Set("Origin", "https://evil.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRouter_FlagHistory(t *testing.T) {
+	router := newTestRouter()
+
+	setFlag := func(value string) {
+		req := httptest.NewRequest(http.MethodPut, "/v1/admin/feature-flags", strings.NewReader(`{"flags":{"disable_alerts_sending":`+value+`}}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAdminAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	}
+	setFlag("true")
+	setFlag("false")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/ops/flags/disable_alerts_sending/history", http.NoBody)
+	addAdminAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var history models.FlagHistory
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Len(t, history.Changes, 2)
+
+	// Newest first, with the admin who made each change
+	assert.Equal(t, true, history.Changes[0].PreviousValue)
+	assert.Equal(t, false, history.Changes[0].NewValue)
+	assert.Equal(t, "usr_testuser123", history.Changes[0].ChangedBy)
+	assert.Nil(t, history.Changes[1].PreviousValue)
+	assert.Equal(t, true, history.Changes[1].NewValue)
+}
+
+func TestRouter_OpsCache(t *testing.T) {
+	router := newCacheRouter(testRoutingService())
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/ops/cache", http.NoBody)
+	addAdminAuthHeader(t, req)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var report models.CacheReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	names := make([]string, 0, len(report.Caches))
+	for _, c := range report.Caches {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"airquality", "routing", "transit"}, names)
+}
+
+func TestRouter_AdminEndpoints_RequireAdminRole(t *testing.T) {
+	router := newTestRouter()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/v1/ops/cache", http.NoBody),
+		httptest.NewRequest(http.MethodGet, "/v1/ops/metrics", http.NoBody),
+		httptest.NewRequest(http.MethodGet, "/v1/ops/flags/maintenance_mode/history", http.NoBody),
+		httptest.NewRequest(http.MethodPost, "/v1/ops/cache:invalidate", http.NoBody),
+		httptest.NewRequest(http.MethodGet, "/v1/admin/feature-flags", http.NoBody),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, req.URL.Path)
+
+		addAuthHeader(t, req)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.URL.Path)
+
+		var problem models.Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, models.ErrorCodeForbidden, problem.Code)
+
+		addAdminAuthHeader(t, req)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, req.URL.Path)
+	}
+}
+
+func TestRouter_OpsCacheInvalidate(t *testing.T) {
+	routingService := testRoutingService()
+	router := newCacheRouter(routingService)
+
+	// Populate the routing cache
+	body, _ := json.Marshal(models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.31, Lon: 4.76},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Positive(t, routingService.CacheStats().TotalEntries)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/ops/cache:invalidate", strings.NewReader(`{"services":["routing"]}`))
+	addAdminAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result models.CacheInvalidateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"routing"}, result.Invalidated)
+	assert.Zero(t, routingService.CacheStats().TotalEntries)
+
+	// An empty body clears every cache
+	req = httptest.NewRequest(http.MethodPost, "/v1/ops/cache:invalidate", http.NoBody)
+	addAdminAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"airquality", "routing", "transit"}, result.Invalidated)
+
+	// Unknown services are rejected without clearing anything
+	req = httptest.NewRequest(http.MethodPost, "/v1/ops/cache:invalidate", strings.NewReader(`{"services":["routing","redis"]}`))
+	addAdminAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, models.FieldCodeUnknownValue, problem.Errors[0].Code)
 }
 
 func TestRouter_GetMe(t *testing.T) {
@@ -293,6 +790,106 @@ func TestRouter_UpsertProfile(t *testing.T) {
 	assert.True(t, profile.Constraints.AvoidMajorRoads)
 }
 
+func TestRouter_PatchProfile_OmittedWeightsUnchanged(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.ProfileInput{
+		Weights: models.ExposureWeights{
+			NO2:    0.5,
+			PM25:   0.3,
+			O3:     0.1,
+			Pollen: 0.1,
+		},
+		Constraints: models.RouteConstraints{
+			AvoidMajorRoads: false,
+		},
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/me/profile", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Only flip avoidMajorRoads; weights are omitted entirely
+	req = httptest.NewRequest(http.MethodPatch, "/v1/me/profile",
+		strings.NewReader(`{"constraints":{"avoidMajorRoads":true}}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var profile models.Profile
+	err := json.Unmarshal(w.Body.Bytes(), &profile)
+	require.NoError(t, err)
+
+	assert.True(t, profile.Constraints.AvoidMajorRoads)
+	assert.Equal(t, input.Weights, profile.Weights)
+
+	// Patch a single weight; the others are kept
+	req = httptest.NewRequest(http.MethodPatch, "/v1/me/profile",
+		strings.NewReader(`{"weights":{"pollen":0.9}}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &profile)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.5, profile.Weights.NO2)
+	assert.Equal(t, 0.9, profile.Weights.Pollen)
+	assert.True(t, profile.Constraints.AvoidMajorRoads)
+}
+
+func TestRouter_PatchProfile_Validation(t *testing.T) {
+	router := newTestRouter()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "weight out of range", body: `{"weights":{"no2":1.5}}`},
+		{name: "all weights zero", body: `{"weights":{"no2":0,"pm25":0,"o3":0,"pollen":0}}`},
+		{name: "invalid constraint", body: `{"constraints":{"maxTransfers":20}}`},
+		{name: "blank pollen sensitivity", body: `{"pollenSensitivities":["Birch","  "]}`},
+		{name: "unknown field", body: `{"weigths":{"no2":0.5}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/v1/me/profile", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			addAuthHeader(t, req)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestRouter_PatchProfile_BodyTooLarge(t *testing.T) {
+	router := newTestRouter()
+
+	body := `{"weights":{"no2":0.5},"padding":"` + strings.Repeat("x", 32<<10) + `"}`
+	req := httptest.NewRequest(http.MethodPatch, "/v1/me/profile", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
 func TestRouter_ListCommutes(t *testing.T) {
 	router := newTestRouter()
 
@@ -346,6 +943,73 @@ func TestRouter_CreateCommute(t *testing.T) {
 	assert.NotEmpty(t, commute.ID)
 }
 
+func TestRouter_MaintenanceMode(t *testing.T) {
+	flags := featureflags.NewInMemoryRepository()
+	router := api.NewRouter(api.RouterConfig{
+		Logger:             zerolog.New(io.Discard),
+		AuthService:        testAuthService(),
+		UserService:        testUserService(),
+		CommuteService:     testCommuteService(),
+		FeatureFlagService: featureflags.NewService(featureflags.ServiceConfig{Repository: flags}),
+	})
+
+	createCommute := func() *httptest.ResponseRecorder {
+		body := `{"label":"Home → Work","origin":{"point":{"lat":52.37,"lon":4.89}},` +
+			`"destination":{"point":{"lat":52.31,"lon":4.76}},"daysOfWeek":[1],"preferredArrivalTimeLocal":"09:00"}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	listCommutes := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/me/commutes", http.NoBody)
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusCreated, createCommute().Code)
+
+	require.NoError(t, flags.SetFlag(context.Background(), &featureflags.Flag{Key: featureflags.FlagMaintenanceMode, Value: true}))
+
+	w := createCommute()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "300", w.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, models.ErrorCodeMaintenanceMode, problem.Code)
+
+	assert.Equal(t, http.StatusOK, listCommutes().Code)
+
+	require.NoError(t, flags.SetFlag(context.Background(), &featureflags.Flag{Key: featureflags.FlagMaintenanceMode, Value: false}))
+	assert.Equal(t, http.StatusCreated, createCommute().Code)
+}
+
+func TestRouter_MaintenanceMode_ComputeRoutes(t *testing.T) {
+	flags := featureflags.NewInMemoryRepository()
+	router := api.NewRouter(api.RouterConfig{
+		Logger:             zerolog.New(io.Discard),
+		AuthService:        testAuthService(),
+		RoutingService:     testRoutingService(),
+		AirQualityService:  testAirQualityService(),
+		RouteStore:         routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()}),
+		FeatureFlagService: featureflags.NewService(featureflags.ServiceConfig{Repository: flags}),
+	})
+	require.NoError(t, flags.SetFlag(context.Background(), &featureflags.Flag{Key: featureflags.FlagMaintenanceMode, Value: true}))
+
+	// Routes are still computed, but not stored
+	resp := computeTestRoutes(t, router, generateTestToken(t))
+	assert.NotEmpty(t, resp.Options)
+	assert.Empty(t, resp.ID)
+
+	require.NoError(t, flags.SetFlag(context.Background(), &featureflags.Flag{Key: featureflags.FlagMaintenanceMode, Value: false}))
+	assert.NotEmpty(t, computeTestRoutes(t, router, generateTestToken(t)).ID)
+}
+
 func TestRouter_GetCommute(t *testing.T) {
 	router := newTestRouter()
 
@@ -401,9 +1065,281 @@ func TestRouter_GetCommute_NotFound(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, models.ErrorCodeCommuteNotFound, problem.Code)
 }
 
-func TestRouter_DeleteCommute(t *testing.T) {
+func TestRouter_GetCommuteBestDay(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.CommuteCreateRequest{
+		Label:                     "Daily Commute",
+		Origin:                    models.CommuteLocation{Point: models.Point{Lat: 52.37, Lon: 4.89}},
+		Destination:               models.CommuteLocation{Point: models.Point{Lat: 52.36, Lon: 4.86}},
+		DaysOfWeek:                []int{1, 2, 3, 4, 5, 6, 7},
+		PreferredArrivalTimeLocal: "09:00",
+	}
+	body, _ := json.Marshal(input)
+
+	createReq := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, createReq)
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+	require.Equal(t, http.StatusCreated, createW.Code)
+
+	var created models.Commute
+	require.NoError(t, json.Unmarshal(createW.Body.Bytes(), &created))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/me/commutes/"+created.ID+"/best-day", http.NoBody)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result models.CommuteBestDay
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, created.ID, result.CommuteID)
+	require.Len(t, result.Days, 7)
+
+	// Only the next arrival is within the forecast horizon
+	first := result.Days[0]
+	require.NotNil(t, first.Score)
+	require.NotNil(t, first.Rank)
+	assert.Equal(t, 1, *first.Rank)
+	assert.Empty(t, first.UnrankedReason)
+
+	require.NotNil(t, result.Best)
+	assert.Equal(t, first.Date, result.Best.Date)
+
+	last := result.Days[6]
+	assert.Nil(t, last.Score)
+	assert.Nil(t, last.Rank)
+	assert.Equal(t, models.UnrankedAirQualityForecastUnavailable, last.UnrankedReason)
+}
+
+func TestRouter_GetCommuteBestDay_NotFound(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/me/commutes/cmt_nonexistent/best-day", http.NoBody)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, models.ErrorCodeCommuteNotFound, problem.Code)
+}
+
+func TestRouter_UpdateCommute_IfMatch(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.CommuteCreateRequest{
+		Label: "Versioned Commute",
+		Origin: models.CommuteLocation{
+			Point: models.Point{Lat: 52.37, Lon: 4.89},
+		},
+		Destination: models.CommuteLocation{
+			Point: models.Point{Lat: 52.31, Lon: 4.76},
+		},
+		DaysOfWeek:                []int{1, 2, 3, 4, 5},
+		PreferredArrivalTimeLocal: "09:00",
+	}
+	body, _ := json.Marshal(input)
+
+	createReq := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, createReq)
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+	require.Equal(t, http.StatusCreated, createW.Code)
+
+	var created models.Commute
+	err := json.Unmarshal(createW.Body.Bytes(), &created)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	// GET exposes the version as the ETag
+	getReq := httptest.NewRequest(http.MethodGet, "/v1/me/commutes/"+created.ID, http.NoBody)
+	addAuthHeader(t, getReq)
+	getW := httptest.NewRecorder()
+	router.ServeHTTP(getW, getReq)
+	require.Equal(t, http.StatusOK, getW.Code)
+	etag := getW.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	update := func(label, ifMatch string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.CommuteUpdateRequest{Label: &label})
+		req := httptest.NewRequest(http.MethodPut, "/v1/me/commutes/"+created.ID, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", ifMatch)
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// First writer wins and bumps the version
+	w := update("First", etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	var updated models.Commute
+	err = json.Unmarshal(w.Body.Bytes(), &updated)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+	assert.Equal(t, "First", updated.Label)
+
+	// Second writer still holds the stale ETag
+	w = update("Second", etag)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/problem+json")
+
+	// Malformed validators can never match
+	w = update("Second", "not-an-etag")
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	// Weak validators and unconditional updates are accepted
+	w = update("Weak", `W/"2"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = update("Unconditional", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestRouter_CloneCommute(t *testing.T) {
+	router := newTestRouter()
+
+	notes := "Via the Vondelpark"
+	input := models.CommuteCreateRequest{
+		Label: "Home to Work",
+		Origin: models.CommuteLocation{
+			Point: models.Point{Lat: 52.37, Lon: 4.89},
+		},
+		Destination: models.CommuteLocation{
+			Point: models.Point{Lat: 52.31, Lon: 4.76},
+		},
+		DaysOfWeek:                []int{1, 2, 3, 4, 5},
+		PreferredArrivalTimeLocal: "09:00",
+		Notes:                     &notes,
+	}
+	body, _ := json.Marshal(input)
+
+	createReq := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, createReq)
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+	require.Equal(t, http.StatusCreated, createW.Code)
+
+	var source models.Commute
+	err := json.Unmarshal(createW.Body.Bytes(), &source)
+	require.NoError(t, err)
+
+	clone := func(overrides string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/me/commutes/"+source.ID+":clone", strings.NewReader(overrides))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("copies the source", func(t *testing.T) {
+		w := clone("")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var copied models.Commute
+		err := json.Unmarshal(w.Body.Bytes(), &copied)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, source.ID, copied.ID)
+		assert.Equal(t, "/v1/me/commutes/"+copied.ID, w.Header().Get("Location"))
+		assert.Equal(t, "Home to Work (copy)", copied.Label)
+		assert.Equal(t, source.Origin, copied.Origin)
+		assert.Equal(t, source.Destination, copied.Destination)
+		assert.Equal(t, source.Schedule.DaysOfWeek, copied.Schedule.DaysOfWeek)
+		require.NotNil(t, copied.Notes)
+		assert.Equal(t, notes, *copied.Notes)
+		assert.Equal(t, int64(1), copied.Version)
+	})
+
+	t.Run("applies overrides", func(t *testing.T) {
+		w := clone(`{"label":"Home to Gym","destination":{"point":{"lat":52.35,"lon":4.92}}}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var copied models.Commute
+		err := json.Unmarshal(w.Body.Bytes(), &copied)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Home to Gym", copied.Label)
+		assert.Equal(t, source.Origin, copied.Origin)
+		assert.InDelta(t, 52.35, copied.Destination.Point.Lat, 0.0001)
+	})
+
+	t.Run("validates like create", func(t *testing.T) {
+		w := clone(`{"daysOfWeek":[9]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/me/commutes/cmt_nonexistent:clone", http.NoBody)
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRouter_CreateCommute_Waypoints(t *testing.T) {
+	router := newTestRouter()
+
+	create := func(waypoints []models.Point) *httptest.ResponseRecorder {
+		input := models.CommuteCreateRequest{
+			Label: "School run",
+			Origin: models.CommuteLocation{
+				Point: models.Point{Lat: 52.37, Lon: 4.89},
+			},
+			Destination: models.CommuteLocation{
+				Point: models.Point{Lat: 52.31, Lon: 4.76},
+			},
+			Waypoints:                 waypoints,
+			DaysOfWeek:                []int{1, 2, 3, 4, 5},
+			PreferredArrivalTimeLocal: "09:00",
+		}
+		body, _ := json.Marshal(input)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := create([]models.Point{{Lat: 52.36, Lon: 4.88}, {Lat: 52.34, Lon: 4.86}})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Commute
+	err := json.Unmarshal(w.Body.Bytes(), &created)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Point{{Lat: 52.36, Lon: 4.88}, {Lat: 52.34, Lon: 4.86}}, created.Waypoints)
+
+	w = create([]models.Point{{Lat: 52.36, Lon: 200}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "waypoints[0].point.lon")
+
+	w = create(make([]models.Point, 6))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRouter_DeleteCommute(t *testing.T) {
 	router := newTestRouter()
 
 	// First, create a commute
@@ -420,36 +1356,599 @@ func TestRouter_DeleteCommute(t *testing.T) {
 	}
 	body, _ := json.Marshal(input)
 
-	createReq := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", bytes.NewReader(body))
-	createReq.Header.Set("Content-Type", "application/json")
-	addAuthHeader(t, createReq)
-	createW := httptest.NewRecorder()
-	router.ServeHTTP(createW, createReq)
-	require.Equal(t, http.StatusCreated, createW.Code)
+	createReq := httptest.NewRequest(http.MethodPost, "/v1/me/commutes", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, createReq)
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+	require.Equal(t, http.StatusCreated, createW.Code)
+
+	var created models.Commute
+	err := json.Unmarshal(createW.Body.Bytes(), &created)
+	require.NoError(t, err)
+
+	// Now delete the commute
+	req := httptest.NewRequest(http.MethodDelete, "/v1/me/commutes/"+created.ID, http.NoBody)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Verify it's deleted by trying to get it
+	getReq := httptest.NewRequest(http.MethodGet, "/v1/me/commutes/"+created.ID, http.NoBody)
+	addAuthHeader(t, getReq)
+	getW := httptest.NewRecorder()
+	router.ServeHTTP(getW, getReq)
+
+	assert.Equal(t, http.StatusNotFound, getW.Code)
+}
+
+func TestRouter_ComputeRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.31, Lon: 4.76},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.RouteComputeResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, resp.Options)
+	assert.NotEmpty(t, resp.GeneratedAt)
+}
+
+func TestRouter_ComputeRoutes_GeoJSON(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.31, Lon: 4.76},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/geo+json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/geo+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept")
+
+	// Decode generically to check the RFC 7946 structure, not our own types
+	var collection map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &collection))
+	assert.Equal(t, "FeatureCollection", collection["type"])
+
+	features, ok := collection["features"].([]any)
+	require.True(t, ok)
+	require.NotEmpty(t, features)
+	for _, f := range features {
+		feature, ok := f.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "Feature", feature["type"])
+		assert.NotEmpty(t, feature["id"])
+
+		geometry, ok := feature["geometry"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "LineString", geometry["type"])
+		coordinates, ok := geometry["coordinates"].([]any)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, len(coordinates), 2)
+		for _, c := range coordinates {
+			position, ok := c.([]any)
+			require.True(t, ok)
+			require.Len(t, position, 2)
+			lon, lat := position[0].(float64), position[1].(float64)
+			assert.True(t, lon >= -180 && lon <= 180, "longitude %v", lon)
+			assert.True(t, lat >= -90 && lat <= 90, "latitude %v", lat)
+		}
+		// Positions are [lon, lat]: the mock route starts at 38.5, -120.2
+		assert.Equal(t, []any{-120.2, 38.5}, coordinates[0])
+
+		properties, ok := feature["properties"].(map[string]any)
+		require.True(t, ok)
+		assert.Contains(t, properties, "durationSeconds")
+		assert.Contains(t, properties, "distanceMeters")
+		assert.Contains(t, properties, "exposureScore")
+	}
+}
+
+// computeTestRoutes computes routes through router, authenticated if token
+// is set, and returns the response.
+func computeTestRoutes(t *testing.T, router http.Handler, token string) models.RouteComputeResponse {
+	t.Helper()
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.31, Lon: 4.76},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.RouteComputeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestRouter_GetRoute(t *testing.T) {
+	router := newTestRouter()
+
+	otherToken, _, err := testJWTService().GenerateAccessToken(&auth.User{ID: "usr_otheruser456", Role: auth.RoleUser})
+	require.NoError(t, err)
+
+	getRoute := func(id, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/routes/"+id, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("anonymous routes are served by ID", func(t *testing.T) {
+		computed := computeTestRoutes(t, router, "")
+		require.NotEmpty(t, computed.ID)
+		require.NotNil(t, computed.ExpiresAt)
+
+		w := getRoute(computed.ID, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var got models.RouteComputeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, computed.ID, got.ID)
+		require.Len(t, got.Options, len(computed.Options))
+		assert.Equal(t, computed.Options[0].ID, got.Options[0].ID)
+
+		assert.Equal(t, http.StatusOK, getRoute(computed.ID, otherToken).Code)
+	})
+
+	t.Run("routes computed by a user are served only to them", func(t *testing.T) {
+		computed := computeTestRoutes(t, router, generateTestToken(t))
+		require.NotEmpty(t, computed.ID)
+
+		assert.Equal(t, http.StatusOK, getRoute(computed.ID, generateTestToken(t)).Code)
+		assert.Equal(t, http.StatusNotFound, getRoute(computed.ID, otherToken).Code)
+		assert.Equal(t, http.StatusNotFound, getRoute(computed.ID, "").Code)
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		w := getRoute("rte_unknown", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "ROUTE_NOT_FOUND")
+	})
+
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, getRoute("rte_unknown", "not-a-token").Code)
+	})
+}
+
+// newRouteStoreRouter creates a router storing computed routes in store.
+func newRouteStoreRouter(store *routestore.Service) http.Handler {
+	return api.NewRouter(api.RouterConfig{
+		Logger:            zerolog.New(io.Discard),
+		AuthService:       testAuthService(),
+		RoutingService:    testRoutingService(),
+		AirQualityService: testAirQualityService(),
+		RouteStore:        store,
+	})
+}
+
+// shareTestRoute shares a stored route as the test user and returns the share.
+func shareTestRoute(t *testing.T, router http.Handler, routeID string) models.RouteShare {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes/"+routeID+":share", nil)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var share models.RouteShare
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &share))
+	assert.Equal(t, share.Path, w.Header().Get("Location"))
+	return share
+}
+
+func TestRouter_RouteShares(t *testing.T) {
+	router := newRouteStoreRouter(routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()}))
+	computed := computeTestRoutes(t, router, generateTestToken(t))
+	share := shareTestRoute(t, router, computed.ID)
+	assert.True(t, strings.HasPrefix(share.Token, "shr_"))
+	assert.Equal(t, "/v1/public/routes/"+share.Token, share.Path)
+
+	getPublic := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, share.Path, nil))
+		return w
+	}
+	revoke := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/v1/me/route-shares/"+share.Token, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Served without authentication, and without the route ID or warnings
+	w := getPublic()
+	require.Equal(t, http.StatusOK, w.Code)
+	var public map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &public))
+	assert.NotContains(t, public, "id")
+	assert.NotContains(t, public, "warnings")
+	assert.NotContains(t, w.Body.String(), computed.ID)
+	assert.NotContains(t, w.Body.String(), "usr_testuser123")
+	options, ok := public["options"].([]any)
+	require.True(t, ok)
+	assert.Len(t, options, len(computed.Options))
+
+	// Only the user who shared the route can revoke the share
+	otherToken, _, err := testJWTService().GenerateAccessToken(&auth.User{ID: "usr_otheruser456", Role: auth.RoleUser})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, revoke(otherToken).Code)
+	assert.Equal(t, http.StatusOK, getPublic().Code)
+
+	assert.Equal(t, http.StatusNoContent, revoke(generateTestToken(t)).Code)
+	w = getPublic()
+	assert.Equal(t, http.StatusNotFound, w.Code, "revoked shares are not served")
+	assert.Contains(t, w.Body.String(), "ROUTE_SHARE_NOT_FOUND")
+	assert.Equal(t, http.StatusNotFound, revoke(generateTestToken(t)).Code, "shares are revoked once")
+}
+
+func TestRouter_RouteShares_Expired(t *testing.T) {
+	router := newRouteStoreRouter(routestore.NewService(routestore.ServiceConfig{
+		Repository: routestore.NewInMemoryRepository(),
+		ShareTTL:   time.Millisecond,
+	}))
+	computed := computeTestRoutes(t, router, generateTestToken(t))
+	share := shareTestRoute(t, router, computed.ID)
+	time.Sleep(5 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, share.Path, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "ROUTE_SHARE_NOT_FOUND")
+}
+
+func TestRouter_ShareRoute_Errors(t *testing.T) {
+	router := newRouteStoreRouter(routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()}))
+	computed := computeTestRoutes(t, router, generateTestToken(t))
+
+	t.Run("requires authentication", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/routes/"+computed.ID+":share", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("other users' routes cannot be shared", func(t *testing.T) {
+		otherToken, _, err := testJWTService().GenerateAccessToken(&auth.User{ID: "usr_otheruser456", Role: auth.RoleUser})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/v1/routes/"+computed.ID+":share", nil)
+		req.Header.Set("Authorization", "Bearer "+otherToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unknown share token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/public/routes/shr_unknown", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRouter_ExportRouteGPX(t *testing.T) {
+	router := newTestRouter()
+	computed := computeTestRoutes(t, router, "")
+	require.NotEmpty(t, computed.ID)
+	require.NotEmpty(t, computed.Options)
+
+	// Every returned option can be exported
+	for _, option := range computed.Options {
+		req := httptest.NewRequest(http.MethodGet, "/v1/routes/"+computed.ID+"/export.gpx?optionId="+option.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/gpx+xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), option.ID+".gpx")
+
+		var gpx struct {
+			XMLName xml.Name
+			Version string `xml:"version,attr"`
+			Tracks  []struct {
+				Segments []struct {
+					Points []struct {
+						Lat float64 `xml:"lat,attr"`
+						Lon float64 `xml:"lon,attr"`
+					} `xml:"trkpt"`
+				} `xml:"trkseg"`
+			} `xml:"trk"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &gpx))
+		assert.Equal(t, "http://www.topografix.com/GPX/1/1", gpx.XMLName.Space)
+		assert.Equal(t, "1.1", gpx.Version)
+		require.Len(t, gpx.Tracks, 1)
+		require.NotEmpty(t, gpx.Tracks[0].Segments)
+		points := gpx.Tracks[0].Segments[0].Points
+		require.GreaterOrEqual(t, len(points), 2)
+		// The mock route starts at 38.5, -120.2
+		assert.Equal(t, 38.5, points[0].Lat)
+		assert.Equal(t, -120.2, points[0].Lon)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/routes/rte_unknown/export.gpx", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "ROUTE_NOT_FOUND")
+}
+
+func TestRouter_ComputeRoutes_IncludeWeatherWithoutProvider(t *testing.T) {
+	router := newTestRouter()
+
+	includeWeather := true
+	input := models.RouteComputeRequest{
+		Origin:         &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:    &models.Point{Lat: 52.31, Lon: 4.76},
+		DepartureTime:  "2026-01-15T08:00:00+01:00",
+		Objective:      models.ObjectiveFastest,
+		IncludeWeather: &includeWeather,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Without a weather provider, routes are returned without weather
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.RouteComputeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Options)
+	assert.Nil(t, resp.Weather)
+}
+
+func TestRouter_ComputeRoutes_Waypoints(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.RouteComputeRequest{
+		Origin:      &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination: &models.Point{Lat: 52.31, Lon: 4.76},
+		Waypoints: []models.Point{
+			{Lat: 52.36, Lon: 4.88},
+			{Lat: 52.34, Lon: 4.86},
+		},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Modes:         []models.Mode{models.ModeBike},
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.RouteComputeResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Options, 1)
+	option := resp.Options[0]
+	require.Len(t, option.Legs, 3)
+	assert.Equal(t, "Origin", option.Legs[0].Start.Name)
+	assert.Equal(t, "Waypoint 1", option.Legs[0].End.Name)
+	assert.Equal(t, "Waypoint 2", option.Legs[2].Start.Name)
+	assert.Equal(t, "Destination", option.Legs[2].End.Name)
+	// The mock provider returns 5000m / 1200s for every leg
+	assert.Equal(t, 3*1200, option.DurationSeconds)
+	require.NotNil(t, option.DistanceMeters)
+	assert.Equal(t, 3*5000, *option.DistanceMeters)
+}
+
+func TestRouter_ComputeRoutes_InvalidWaypoints(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.31, Lon: 4.76},
+		Waypoints:     []models.Point{{Lat: 52.36, Lon: 4.88}, {Lat: 120, Lon: 4.86}},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "waypoints[1]")
+}
+
+func TestRouter_ComputeRoutes_TooLong(t *testing.T) {
+	router := newTestRouter()
+
+	// Amsterdam to Maastricht is too far to walk or cycle
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 50.85, Lon: 5.69},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, models.ProblemTypeUnprocessable, problem.Type)
+	assert.Equal(t, models.ErrorCodeRouteTooLong, problem.Code)
+}
+
+func TestRouter_ComputeRoutes_SameOriginDestination(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.37, Lon: 4.89},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, models.ErrorCodeSameOriginDestination, problem.Code)
+}
+
+// unavailableRoutingProvider is a routing provider that is down.
+type unavailableRoutingProvider struct{ mockRoutingProvider }
+
+func (m *unavailableRoutingProvider) GetDirections(ctx context.Context, req routing.DirectionsRequest) (*routing.DirectionsResponse, error) {
+	return nil, &routing.Error{
+		Provider: "test-provider",
+		Code:     "SERVICE_UNAVAILABLE",
+		Message:  "routing service unavailable",
+		Err:      routing.ErrProviderUnavailable,
+	}
+}
+
+func TestRouter_ComputeRoutes_EstimatedWhenUnavailable(t *testing.T) {
+	newRouter := func(estimate bool) http.Handler {
+		return api.NewRouter(api.RouterConfig{
+			Logger:      zerolog.New(io.Discard),
+			AuthService: testAuthService(),
+			RoutingService: routing.NewService(routing.ServiceConfig{
+				Provider: &unavailableRoutingProvider{},
+				Logger:   zerolog.New(io.Discard),
+			}),
+			EstimateRoutesWhenUnavailable: estimate,
+		})
+	}
+
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 52.36, Lon: 4.91},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+		Modes:         []models.Mode{models.ModeBike},
+	}
+	body, _ := json.Marshal(input)
+
+	compute := func(router http.Handler) models.RouteComputeResponse {
+		req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.RouteComputeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	// Disabled: only the provider warning
+	resp := compute(newRouter(false))
+	assert.Empty(t, resp.Options)
+	assert.NotEmpty(t, resp.Warnings)
+
+	resp = compute(newRouter(true))
+	require.Len(t, resp.Options, 1)
+	option := resp.Options[0]
+	assert.True(t, option.Estimated)
+	assert.Equal(t, models.ConfidenceLow, option.Confidence)
+	assert.Positive(t, option.DurationSeconds)
+	require.NotNil(t, option.DistanceMeters)
+	assert.Positive(t, *option.DistanceMeters)
+	require.Len(t, option.Legs, 1)
+	assert.Equal(t, "estimate", option.Legs[0].Provider)
+	assert.Nil(t, option.Legs[0].GeometryPolyline)
+	assert.NotEmpty(t, resp.Warnings, "the provider failure is still reported")
+}
+
+func TestRouter_ComputeRoutes_TooLongForOneMode(t *testing.T) {
+	router := newTestRouter()
 
-	var created models.Commute
-	err := json.Unmarshal(createW.Body.Bytes(), &created)
-	require.NoError(t, err)
+	// Amsterdam to Rotterdam can be cycled but is too far to walk
+	input := models.RouteComputeRequest{
+		Origin:        &models.Point{Lat: 52.37, Lon: 4.89},
+		Destination:   &models.Point{Lat: 51.92, Lon: 4.48},
+		DepartureTime: "2026-01-15T08:00:00+01:00",
+		Objective:     models.ObjectiveFastest,
+	}
+	body, _ := json.Marshal(input)
 
-	// Now delete the commute
-	req := httptest.NewRequest(http.MethodDelete, "/v1/me/commutes/"+created.ID, http.NoBody)
-	addAuthHeader(t, req)
+	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNoContent, w.Code)
-
-	// Verify it's deleted by trying to get it
-	getReq := httptest.NewRequest(http.MethodGet, "/v1/me/commutes/"+created.ID, http.NoBody)
-	addAuthHeader(t, getReq)
-	getW := httptest.NewRecorder()
-	router.ServeHTTP(getW, getReq)
+	require.Equal(t, http.StatusOK, w.Code)
 
-	assert.Equal(t, http.StatusNotFound, getW.Code)
+	var resp models.RouteComputeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Options)
+	for _, option := range resp.Options {
+		require.NotEmpty(t, option.Legs)
+		assert.Equal(t, models.ModeBike, option.Legs[0].Mode)
+	}
+	require.Len(t, resp.Warnings, 1)
+	assert.Equal(t, "ROUTE_TOO_LONG", resp.Warnings[0].Code)
 }
 
-func TestRouter_ComputeRoutes(t *testing.T) {
+func TestRouter_ComputeRoutes_EventStream(t *testing.T) {
 	router := newTestRouter()
 
 	input := models.RouteComputeRequest{
@@ -462,18 +1961,56 @@ func TestRouter_ComputeRoutes(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/v1/routes:compute", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.True(t, w.Flushed, "events should be flushed as they are sent")
 
-	var resp models.RouteComputeResponse
-	err := json.Unmarshal(w.Body.Bytes(), &resp)
-	require.NoError(t, err)
+	// Parse "event:"/"data:" pairs from the stream
+	type sseEvent struct {
+		name string
+		data string
+	}
+	var events []sseEvent
+	scanner := bufio.NewScanner(w.Body)
+	var current sseEvent
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.GreaterOrEqual(t, len(events), 3, "expected multiple option events and a done event")
+
+	optionIDs := make(map[string]bool)
+	for _, ev := range events[:len(events)-1] {
+		require.Equal(t, "option", ev.name)
+		var option models.RouteOption
+		require.NoError(t, json.Unmarshal([]byte(ev.data), &option))
+		assert.NotEmpty(t, option.ID)
+		optionIDs[option.ID] = true
+	}
 
-	assert.NotEmpty(t, resp.Options)
-	assert.NotEmpty(t, resp.GeneratedAt)
+	last := events[len(events)-1]
+	require.Equal(t, "done", last.name)
+	var done models.RouteStreamDone
+	require.NoError(t, json.Unmarshal([]byte(last.data), &done))
+	require.NotEmpty(t, done.RankedOptionIDs)
+	assert.NotEmpty(t, done.RouteID, "streamed routes are stored too")
+	for _, id := range done.RankedOptionIDs {
+		assert.True(t, optionIDs[id], "ranked option %s should have been streamed", id)
+	}
 }
 
 func TestRouter_ComputeRoutes_ValidationError(t *testing.T) {
@@ -500,6 +2037,7 @@ func TestRouter_ComputeRoutes_ValidationError(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, models.ProblemTypeValidation, problem.Type)
+	assert.Equal(t, models.ErrorCodeValidationFailed, problem.Code)
 	assert.NotEmpty(t, problem.TraceID)
 }
 
@@ -596,6 +2134,36 @@ func TestRouter_GetEnums(t *testing.T) {
 	assert.Contains(t, enums.Confidence, models.ConfidenceHigh)
 }
 
+func TestRouter_OpenAPISpecCoversRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/openapi.json", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	routes, ok := router.(chi.Routes)
+	require.True(t, ok)
+	var count int
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		count++
+		// Subrouter index routes are registered with a trailing slash
+		path := strings.TrimSuffix(strings.ReplaceAll(route, "/*", ""), "/")
+		_, ok := spec.Paths[path][strings.ToLower(method)]
+		assert.True(t, ok, "%s %s missing from the OpenAPI document", method, path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, count, 40)
+}
+
 func TestRouter_ListAirQualityStations(t *testing.T) {
 	router := newTestRouter()
 
@@ -691,6 +2259,583 @@ func TestRouter_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestRouter_TransitDisruptions_LocalizedFromProfile(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/transit/disruptions?origin=asd&destination=ut", http.NoBody)
+	req.Header.Set("Accept-Language", "en-GB")
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.RouteDisruptions
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// Test user profile is nl-NL, which takes precedence over Accept-Language
+	assert.Equal(t, "nl", resp.Locale)
+	assert.True(t, resp.HasDisruptions)
+	require.NotNil(t, resp.Advisory)
+	assert.Equal(t, "MAJOR", resp.Advisory.Severity)
+	assert.Equal(t, "Grote verstoringen verwacht. Flinke vertragingen of uitval mogelijk. Houd rekening met extra reistijd.",
+		resp.Advisory.Headline)
+	assert.Equal(t, []string{"Signal failure bij Amsterdam Centraal."}, resp.Advisory.Details)
+	require.Len(t, resp.Disruptions, 1)
+	assert.Equal(t, "d1", resp.Disruptions[0].ID)
+}
+
+func TestRouter_TransitDisruptions_Validation(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/transit/disruptions?origin=ASD", http.NoBody)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "destination")
+}
+
+func TestRouter_TransitDisruptions_RequiresAuth(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/transit/disruptions?origin=ASD&destination=UT", http.NoBody)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRouter_ListIdentities(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/me/identities", http.NoBody)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.IdentityList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.NotNil(t, list.Items)
+}
+
+func TestRouter_ListIdentities_RequiresAuth(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/me/identities", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRouter_Webhooks_Lifecycle(t *testing.T) {
+	router := newTestRouter()
+
+	// Create
+	body := strings.NewReader(`{"url":"https://example.com/hooks/disruptions"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/me/webhooks", body)
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created models.WebhookCreated
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.NotEmpty(t, created.Secret)
+	assert.True(t, created.Active)
+
+	// List does not expose the secret
+	req = httptest.NewRequest(http.MethodGet, "/v1/me/webhooks", http.NoBody)
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Secret)
+	var list models.WebhookList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, created.ID, list.Items[0].ID)
+
+	// Delete
+	req = httptest.NewRequest(http.MethodDelete, "/v1/me/webhooks/"+created.ID, http.NoBody)
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Deleting again is not found
+	req = httptest.NewRequest(http.MethodDelete, "/v1/me/webhooks/"+created.ID, http.NoBody)
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouter_Webhooks_RejectsInsecureURL(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/me/webhooks", strings.NewReader(`{"url":"http://example.com/hook"}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "url")
+}
+
+// changingTransitProvider is a transit provider whose disruptions can be
+// replaced between cache refreshes.
+type changingTransitProvider struct {
+	mockTransitProvider
+	mu          sync.Mutex
+	disruptions []*transit.Disruption
+	calls       int
+}
+
+func (p *changingTransitProvider) GetAllDisruptions(_ context.Context) ([]*transit.Disruption, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.disruptions, nil
+}
+
+func (p *changingTransitProvider) set(disruptions ...*transit.Disruption) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.disruptions = disruptions
+}
+
+func (p *changingTransitProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// readSSEEvent reads the next event from a Server-Sent Events stream,
+// skipping comments.
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (name, data string) {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && name != "":
+			return name, data
+		}
+	}
+}
+
+func TestRouter_TransitDisruptionStream(t *testing.T) {
+	signalFailure := &transit.Disruption{
+		ID: "d1", Type: transit.DisruptionDisturbance, Title: "Signal failure",
+		Impact: transit.ImpactMajor, AffectedStations: []string{"ASD"}, Start: time.Now().Add(-time.Hour),
+	}
+	maintenance := &transit.Disruption{
+		ID: "d2", Type: transit.DisruptionMaintenance, Title: "Track maintenance",
+		Impact: transit.ImpactMinor, AffectedStations: []string{"UT"}, Start: time.Now().Add(-time.Hour), IsPlanned: true,
+	}
+
+	provider := &changingTransitProvider{}
+	provider.set(signalFailure)
+	transitService := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.New(io.Discard),
+		CacheTTL: time.Nanosecond,
+	})
+
+	server := httptest.NewServer(api.NewRouter(api.RouterConfig{
+		Logger:         zerolog.New(io.Discard),
+		AuthService:    testAuthService(),
+		UserService:    testUserService(),
+		RoutingService: testRoutingService(),
+		TransitService: transitService,
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/transit/disruptions/stream", http.NoBody)
+	require.NoError(t, err)
+	addAuthHeader(t, req)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+
+	// Summary on connect
+	name, data := readSSEEvent(t, reader)
+	require.Equal(t, "summary", name)
+	var summary models.TransitDisruptionSummary
+	require.NoError(t, json.Unmarshal([]byte(data), &summary))
+	assert.Equal(t, 1, summary.TotalDisruptions)
+	require.NotNil(t, summary.MostSevere)
+	assert.Equal(t, "MAJOR", *summary.MostSevere)
+	callsAfterConnect := provider.callCount()
+
+	// A cache refresh triggered elsewhere pushes only the changes
+	provider.set(maintenance)
+	_, err = transitService.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	name, data = readSSEEvent(t, reader)
+	require.Equal(t, "delta", name)
+	var delta models.TransitDisruptionDelta
+	require.NoError(t, json.Unmarshal([]byte(data), &delta))
+	require.Len(t, delta.Added, 1)
+	assert.Equal(t, "d2", delta.Added[0].ID)
+	require.Len(t, delta.Removed, 1)
+	assert.Equal(t, "d1", delta.Removed[0].ID)
+	assert.Empty(t, delta.Changed)
+
+	// The stream itself made no provider calls beyond the one refresh above
+	assert.Equal(t, callsAfterConnect+1, provider.callCount())
+}
+
+func TestRouter_TransitDisruptionStream_LimitsConcurrentStreams(t *testing.T) {
+	server := httptest.NewServer(api.NewRouter(api.RouterConfig{
+		Logger:               zerolog.New(io.Discard),
+		AuthService:          testAuthService(),
+		UserService:          testUserService(),
+		RoutingService:       testRoutingService(),
+		TransitService:       testTransitService(),
+		MaxDisruptionStreams: 1,
+	}))
+	defer server.Close()
+
+	open := func(ctx context.Context) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/transit/disruptions/stream", http.NoBody)
+		require.NoError(t, err)
+		addAuthHeader(t, req)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	firstCtx, closeFirst := context.WithCancel(context.Background())
+	first := open(firstCtx)
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	name, _ := readSSEEvent(t, bufio.NewReader(first.Body))
+	require.Equal(t, "summary", name)
+
+	second := open(context.Background())
+	second.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, second.StatusCode)
+
+	// Disconnecting frees the slot
+	closeFirst()
+	first.Body.Close()
+	require.Eventually(t, func() bool {
+		resp := open(context.Background())
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
 func strPtr(s string) *string {
 	return &s
 }
+
+func TestRouter_AirQualityGrid(t *testing.T) {
+	router := newTestRouter()
+
+	// Amsterdam is covered by the station; the north-east corner is over 50km away
+	input := models.AirQualityGridRequest{
+		BBox:          models.GeoBox{MinLat: 52.2, MinLon: 4.7, MaxLat: 52.8, MaxLon: 5.9},
+		ResolutionDeg: 0.2,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/air-quality/grid", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.AirQualityGridResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, 3, resp.Rows)
+	assert.Equal(t, 6, resp.Cols)
+	require.Len(t, resp.Cells, 18)
+	assert.False(t, time.Time(resp.SnapshotAt).IsZero())
+
+	southWest := resp.Cells[0]
+	require.NotNil(t, southWest)
+	assert.InDelta(t, 52.3, southWest.Point.Lat, 0.0001)
+	assert.InDelta(t, 4.8, southWest.Point.Lon, 0.0001)
+	assert.NotEmpty(t, southWest.Confidence)
+	require.Len(t, southWest.Values, 1)
+	assert.Equal(t, models.PollutantNO2, southWest.Values[0].Pollutant)
+
+	assert.Nil(t, resp.Cells[len(resp.Cells)-1], "north-east cell is out of range")
+}
+
+func TestRouter_AirQualityGrid_TooLarge(t *testing.T) {
+	router := newTestRouter()
+
+	input := models.AirQualityGridRequest{
+		BBox:          models.GeoBox{MinLat: 50.7, MinLon: 3.3, MaxLat: 53.6, MaxLon: 7.3},
+		ResolutionDeg: 0.01,
+	}
+	body, _ := json.Marshal(input)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/air-quality/grid", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "resolutionDeg")
+}
+
+func TestRouter_AirQualityNearest(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/air-quality/nearest?lat=52.37&lon=4.89", http.NoBody)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.NearestStationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, "NL10938", resp.Station.StationID)
+	assert.Equal(t, []models.Pollutant{models.PollutantNO2}, resp.Station.Pollutants)
+	assert.False(t, time.Time(resp.Station.UpdatedAt).IsZero())
+	assert.InDelta(t, 2200, resp.DistanceMeters, 200)
+	require.Len(t, resp.Measurements, 1)
+	assert.InDelta(t, 24.0, resp.Measurements[0].Value, 0.001)
+}
+
+func TestRouter_AirQualityNearest_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"out of range", "lat=53.22&lon=6.57", http.StatusNotFound},
+		{"missing lon", "lat=52.37", http.StatusBadRequest},
+		{"invalid lat", "lat=91&lon=4.89", http.StatusBadRequest},
+	}
+
+	router := newTestRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/air-quality/nearest?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+// newAuditRouter creates a test router that records audit entries in repo.
+func newAuditRouter(repo audit.Repository) http.Handler {
+	logger := zerolog.New(io.Discard)
+	return api.NewRouter(api.RouterConfig{
+		Logger:            logger,
+		AuthService:       testAuthService(),
+		UserService:       testUserService(),
+		CommuteService:    testCommuteService(),
+		DeviceService:     testDeviceService(),
+		RoutingService:    testRoutingService(),
+		TransitService:    testTransitService(),
+		AirQualityService: testAirQualityService(),
+		AuditService:      audit.NewService(audit.ServiceConfig{Repository: repo, Logger: logger}),
+	})
+}
+
+func TestRouter_Audit_RecordsChanges(t *testing.T) {
+	repo := audit.NewInMemoryRepository()
+	router := newAuditRouter(repo)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-Id", "req_"+strings.ToLower(method))
+		addAuthHeader(t, req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/v1/me/commutes", `{"label":"Home → Work",`+
+		`"origin":{"point":{"lat":52.37,"lon":4.89}},"destination":{"point":{"lat":52.31,"lon":4.76}},`+
+		`"daysOfWeek":[1,2,3,4,5],"preferredArrivalTimeLocal":"09:00"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var commute models.Commute
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &commute))
+
+	w = serve(http.MethodPatch, "/v1/me/profile", `{"weights":{"no2":0.5}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(http.MethodPost, "/v1/me/devices", `{"deviceId":"dev_test123","platform":"APNS","token":"abc123token456xyz789"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = serve(http.MethodDelete, "/v1/me/devices/dev_test123", "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// Reads and failed changes are not recorded
+	serve(http.MethodGet, "/v1/me/commutes", "")
+	w = serve(http.MethodDelete, "/v1/me/commutes/cmt_nonexistent", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	entries, err := repo.ListByUser(context.Background(), "usr_testuser123", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	type recorded struct {
+		action       audit.Action
+		resourceType string
+		resourceID   string
+		requestID    string
+	}
+	got := make([]recorded, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		got = append(got, recorded{e.Action, e.ResourceType, e.ResourceID, e.RequestID})
+	}
+	assert.Equal(t, []recorded{
+		{audit.ActionCreate, audit.ResourceCommute, commute.ID, "req_post"},
+		{audit.ActionUpdate, audit.ResourceProfile, "usr_testuser123", "req_patch"},
+		{audit.ActionCreate, audit.ResourceDevice, "dev_test123", "req_post"},
+		{audit.ActionDelete, audit.ResourceDevice, "dev_test123", "req_delete"},
+	}, got)
+}
+
+func TestRouter_AdminUserAudit(t *testing.T) {
+	repo := audit.NewInMemoryRepository()
+	router := newAuditRouter(repo)
+
+	req := httptest.NewRequest(http.MethodPatch, "/v1/me/profile", strings.NewReader(`{"weights":{"no2":0.5}}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(t, req)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/admin/users/usr_testuser123/audit?limit=10", http.NoBody)
+	addAdminAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var page models.PagedAuditEntries
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, 10, page.Meta.Limit)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "update", page.Items[0].Action)
+	assert.Equal(t, audit.ResourceProfile, page.Items[0].ResourceType)
+
+	// Only admins may read audit history
+	req = httptest.NewRequest(http.MethodGet, "/v1/admin/users/usr_testuser123/audit", http.NoBody)
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/admin/users/usr_testuser123/audit?limit=1000", http.NoBody)
+	addAdminAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRouter_ExposureHistory(t *testing.T) {
+	repo := exposure.NewInMemoryRepository()
+	require.NoError(t, repo.Create(context.Background(), &exposure.Trip{
+		CommuteID: "cmt_1",
+		UserID:    "usr_testuser123",
+		Date:      exposure.TripDate(time.Now().UTC()),
+		NO2:       24,
+		PM25:      9,
+		O3:        41,
+	}))
+	router := api.NewRouter(api.RouterConfig{
+		Logger:            zerolog.New(io.Discard),
+		AuthService:       testAuthService(),
+		RoutingService:    testRoutingService(),
+		TransitService:    testTransitService(),
+		AirQualityService: testAirQualityService(),
+		ExposureService:   exposure.NewService(exposure.ServiceConfig{Repository: repo, Location: time.UTC}),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/me/exposure/history?weeks=2", http.NoBody)
+	addAuthHeader(t, req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var history models.ExposureHistory
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Len(t, history.Weeks, 2)
+	assert.Equal(t, 1, history.Weeks[0].Trips)
+	require.NotNil(t, history.Weeks[0].Averages)
+	assert.Equal(t, 24.0, *history.Weeks[0].Averages.NO2Ugm3)
+	assert.Zero(t, history.Weeks[1].Trips)
+	assert.Nil(t, history.Weeks[1].Averages)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/me/exposure/history?weeks=53", http.NoBody)
+	addAuthHeader(t, req)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/me/exposure/history", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRouter_MaintenanceMode_ExemptRoutes(t *testing.T) {
+	flags := featureflags.NewInMemoryRepository()
+	router := api.NewRouter(api.RouterConfig{
+		Logger:             zerolog.New(io.Discard),
+		AuthService:        testAuthService(),
+		AirQualityService:  testAirQualityService(),
+		FeatureFlagService: featureflags.NewService(featureflags.ServiceConfig{Repository: flags}),
+	})
+	require.NoError(t, flags.SetFlag(context.Background(), &featureflags.Flag{Key: featureflags.FlagMaintenanceMode, Value: true}))
+
+	post := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Read-only POSTs and session upkeep pass through to their handlers
+	for _, path := range []string{"/v1/air-quality/grid", "/v1/alerts/preview", "/v1/auth/refresh", "/v1/auth/logout"} {
+		assert.NotEqual(t, http.StatusServiceUnavailable, post(path).Code, path)
+	}
+
+	// Sign-in creates users, so it is a write
+	assert.Equal(t, http.StatusServiceUnavailable, post("/v1/auth/siwa").Code)
+}
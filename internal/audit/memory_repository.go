@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemoryRepository is an in-memory implementation of Repository.
+// This is intended for testing. Production should use the PostgreSQL implementation.
+type InMemoryRepository struct {
+	mu      sync.RWMutex
+	entries []*Entry
+}
+
+// NewInMemoryRepository creates a new in-memory audit repository.
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{}
+}
+
+// Create stores a new entry.
+func (r *InMemoryRepository) Create(_ context.Context, entry *Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entryCopy := *entry
+	r.entries = append(r.entries, &entryCopy)
+	return nil
+}
+
+// ListByUser retrieves up to limit entries for a user, newest first.
+func (r *InMemoryRepository) ListByUser(_ context.Context, userID string, limit int) ([]*Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Walk backwards so entries recorded in the same instant stay newest first
+	var items []*Entry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if entry := r.entries[i]; entry.UserID == userID {
+			entryCopy := *entry
+			items = append(items, &entryCopy)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
@@ -0,0 +1,41 @@
+// Package audit records who changed which user resources, for GDPR
+// accountability and security investigations.
+package audit
+
+import "time"
+
+// Action is the kind of change an audit entry records.
+type Action string
+
+// Audited actions.
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource types with audited changes.
+const (
+	ResourceCommute = "commute"
+	ResourceDevice  = "device"
+	ResourceProfile = "profile"
+)
+
+// Entry is a single recorded change.
+type Entry struct {
+	ID     string
+	UserID string
+	Action Action
+
+	// ResourceType is the kind of resource changed (e.g. ResourceCommute).
+	ResourceType string
+
+	// ResourceID identifies the changed resource. Resources without an ID of
+	// their own, such as the profile, use the user ID.
+	ResourceID string
+
+	// RequestID is the X-Request-Id of the request that made the change.
+	RequestID string
+
+	CreatedAt time.Time
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository is a PostgreSQL implementation of Repository.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgreSQL audit repository.
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// Create stores a new entry.
+func (r *PostgresRepository) Create(ctx context.Context, entry *Entry) error {
+	query := `
+		INSERT INTO audit_log (id, user_id, action, resource_type, resource_id, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		entry.ID,
+		entry.UserID,
+		entry.Action,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.RequestID,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+// ListByUser retrieves up to limit entries for a user, newest first.
+func (r *PostgresRepository) ListByUser(ctx context.Context, userID string, limit int) ([]*Entry, error) {
+	query := `
+		SELECT id, user_id, action, resource_type, resource_id, request_id, created_at
+		FROM audit_log
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+	for rows.Next() {
+		var entry Entry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Action,
+			&entry.ResourceType,
+			&entry.ResourceID,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,12 @@
+package audit
+
+import "context"
+
+// Repository defines the interface for audit entry persistence.
+type Repository interface {
+	// Create stores a new entry.
+	Create(ctx context.Context, entry *Entry) error
+
+	// ListByUser retrieves up to limit entries for a user, newest first.
+	ListByUser(ctx context.Context, userID string, limit int) ([]*Entry, error)
+}
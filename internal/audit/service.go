@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Limits for listing a user's audit history.
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// ServiceConfig holds configuration for the audit service.
+type ServiceConfig struct {
+	// Repository stores audit entries (required).
+	Repository Repository
+
+	// Logger for service operations.
+	Logger zerolog.Logger
+
+	// RecordTimeout bounds each write so a slow database cannot hold up the
+	// request being audited (default: 2 seconds).
+	RecordTimeout time.Duration
+}
+
+// Service records and lists audit entries.
+type Service struct {
+	repo          Repository
+	logger        zerolog.Logger
+	recordTimeout time.Duration
+}
+
+// NewService creates a new audit service.
+func NewService(cfg ServiceConfig) *Service {
+	recordTimeout := cfg.RecordTimeout
+	if recordTimeout == 0 {
+		recordTimeout = 2 * time.Second
+	}
+
+	return &Service{
+		repo:          cfg.Repository,
+		logger:        cfg.Logger,
+		recordTimeout: recordTimeout,
+	}
+}
+
+// Record stores an audit entry, filling in its ID and time if unset.
+// Recording is best-effort: failures are logged, never returned, so auditing
+// cannot fail the change being audited. The entry is written even if ctx has
+// been cancelled.
+func (s *Service) Record(ctx context.Context, entry *Entry) {
+	if entry.ID == "" {
+		entry.ID = "aud_" + uuid.New().String()[:22]
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.recordTimeout)
+	defer cancel()
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("user_id", entry.UserID).
+			Str("action", string(entry.Action)).
+			Str("resource_type", entry.ResourceType).
+			Str("resource_id", entry.ResourceID).
+			Str("request_id", entry.RequestID).
+			Msg("failed to record audit entry")
+	}
+}
+
+// List returns a user's most recent audit entries, newest first. The limit
+// is clamped to MaxListLimit; zero or less uses DefaultListLimit.
+func (s *Service) List(ctx context.Context, userID string, limit int) (*models.PagedAuditEntries, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+
+	entries, err := s.repo.ListByUser(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, models.AuditEntry{
+			ID:           e.ID,
+			UserID:       e.UserID,
+			Action:       string(e.Action),
+			ResourceType: e.ResourceType,
+			ResourceID:   e.ResourceID,
+			RequestID:    e.RequestID,
+			CreatedAt:    models.Timestamp(e.CreatedAt),
+		})
+	}
+
+	return &models.PagedAuditEntries{
+		Items: items,
+		Meta:  models.PagedResponseMeta{Limit: limit},
+	}, nil
+}
@@ -0,0 +1,114 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/audit"
+)
+
+const testUserID = "usr_testuser123"
+
+// failingRepository is a Repository whose writes always fail.
+type failingRepository struct {
+	*audit.InMemoryRepository
+}
+
+func (failingRepository) Create(context.Context, *audit.Entry) error {
+	return errors.New("database unavailable")
+}
+
+func TestService_Record(t *testing.T) {
+	repo := audit.NewInMemoryRepository()
+	service := audit.NewService(audit.ServiceConfig{Repository: repo, Logger: zerolog.Nop()})
+
+	service.Record(context.Background(), &audit.Entry{
+		UserID:       testUserID,
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceCommute,
+		ResourceID:   "cmt_1",
+		RequestID:    "req_1",
+	})
+
+	entries, err := repo.ListByUser(context.Background(), testUserID, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, strings.HasPrefix(entries[0].ID, "aud_"))
+	assert.WithinDuration(t, time.Now(), entries[0].CreatedAt, time.Second)
+	assert.Equal(t, audit.ActionCreate, entries[0].Action)
+	assert.Equal(t, "cmt_1", entries[0].ResourceID)
+	assert.Equal(t, "req_1", entries[0].RequestID)
+}
+
+func TestService_Record_CancelledContext(t *testing.T) {
+	repo := audit.NewInMemoryRepository()
+	service := audit.NewService(audit.ServiceConfig{Repository: repo, Logger: zerolog.Nop()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service.Record(ctx, &audit.Entry{UserID: testUserID, Action: audit.ActionDelete, ResourceType: audit.ResourceDevice})
+
+	entries, err := repo.ListByUser(context.Background(), testUserID, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestService_Record_FailureIsLogged(t *testing.T) {
+	var logs bytes.Buffer
+	service := audit.NewService(audit.ServiceConfig{
+		Repository: failingRepository{audit.NewInMemoryRepository()},
+		Logger:     zerolog.New(&logs),
+	})
+
+	service.Record(context.Background(), &audit.Entry{
+		UserID:       testUserID,
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceProfile,
+		ResourceID:   testUserID,
+	})
+
+	assert.Contains(t, logs.String(), "failed to record audit entry")
+	assert.Contains(t, logs.String(), "database unavailable")
+}
+
+func TestService_List(t *testing.T) {
+	repo := audit.NewInMemoryRepository()
+	service := audit.NewService(audit.ServiceConfig{Repository: repo, Logger: zerolog.Nop()})
+
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"cmt_1", "cmt_2", "cmt_3"} {
+		service.Record(context.Background(), &audit.Entry{
+			UserID:       testUserID,
+			Action:       audit.ActionUpdate,
+			ResourceType: audit.ResourceCommute,
+			ResourceID:   id,
+			CreatedAt:    base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	service.Record(context.Background(), &audit.Entry{UserID: "usr_other", Action: audit.ActionCreate, ResourceType: audit.ResourceDevice})
+
+	page, err := service.List(context.Background(), testUserID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Meta.Limit)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "cmt_3", page.Items[0].ResourceID)
+	assert.Equal(t, "cmt_2", page.Items[1].ResourceID)
+	assert.Equal(t, "update", page.Items[0].Action)
+
+	page, err = service.List(context.Background(), testUserID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, audit.DefaultListLimit, page.Meta.Limit)
+	assert.Len(t, page.Items, 3)
+
+	page, err = service.List(context.Background(), testUserID, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, audit.MaxListLimit, page.Meta.Limit)
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FindOrCreateByAppleSub returns the user for an Apple identity token,
+// linking the token's subject to that user.
+//
+// Lookup order:
+//  1. An identity already linked to the subject.
+//  2. A user created before identity linking whose apple_sub matches.
+//  3. Account migration: Apple vouches for the email (email_verified) and
+//     exactly one existing user has a verified Apple identity with it.
+//  4. Otherwise a new user is created.
+//
+// Step 3 is deliberately strict so two distinct people are never merged:
+// a missing or unverified email never links, and an email verified on
+// identities of more than one user is ambiguous and links to none of them.
+func (s *Service) FindOrCreateByAppleSub(ctx context.Context, claims *AppleClaims) (*User, error) {
+	identity, err := s.identityRepo.FindBySubject(ctx, ProviderApple, claims.Subject)
+	if err == nil {
+		applyClaims(identity, claims)
+		identity.LastUsedAt = time.Now()
+		if err := s.identityRepo.RecordLogin(ctx, identity); err != nil {
+			return nil, fmt.Errorf("recording login: %w", err)
+		}
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return s.syncAdminRole(ctx, user, claims.Subject)
+	}
+	if !errors.Is(err, ErrIdentityNotFound) {
+		return nil, fmt.Errorf("finding identity: %w", err)
+	}
+
+	user, err := s.userRepo.FindByAppleSub(ctx, claims.Subject)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+
+	if user == nil {
+		if user, err = s.findMigratedUser(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		if user, err = s.createUser(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	if user, err = s.syncAdminRole(ctx, user, claims.Subject); err != nil {
+		return nil, err
+	}
+	return s.linkIdentity(ctx, user, claims)
+}
+
+// syncAdminRole makes the user's role match the admin allowlist: a user who
+// signed in with a listed Apple subject is promoted to RoleAdmin, and an admin
+// none of whose Apple subjects is listed any more is demoted to RoleUser.
+func (s *Service) syncAdminRole(ctx context.Context, user *User, subject string) (*User, error) {
+	listed, err := s.hasAdminSubject(ctx, user, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	role := RoleUser
+	if listed {
+		role = RoleAdmin
+	}
+	if user.Role.OrDefault() == role {
+		return user, nil
+	}
+	if err := s.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		return nil, fmt.Errorf("updating role to %s: %w", role, err)
+	}
+	user.Role = role
+	return user, nil
+}
+
+// hasAdminSubject reports whether subject, or any other Apple subject of an
+// admin user, is on the admin allowlist. Other identities are only checked
+// for admins, so a regular sign-in costs no extra lookup.
+func (s *Service) hasAdminSubject(ctx context.Context, user *User, subject string) (bool, error) {
+	if s.adminSubs[subject] {
+		return true, nil
+	}
+	if user.Role != RoleAdmin {
+		return false, nil
+	}
+	if s.adminSubs[user.AppleSub] {
+		return true, nil
+	}
+
+	identities, err := s.identityRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("listing identities: %w", err)
+	}
+	for _, identity := range identities {
+		if identity.Provider == ProviderApple && s.adminSubs[identity.Subject] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListIdentities returns the identities linked to a user, oldest first.
+func (s *Service) ListIdentities(ctx context.Context, userID string) ([]*Identity, error) {
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+// findMigratedUser returns the single existing user whose verified Apple
+// email matches the claims, or nil if there is none or the match is ambiguous.
+func (s *Service) findMigratedUser(ctx context.Context, claims *AppleClaims) (*User, error) {
+	email := normalizeEmail(claims.Email)
+	if email == "" || !claimIsTrue(claims.EmailVerified) {
+		return nil, nil
+	}
+
+	candidates, err := s.identityRepo.FindByVerifiedEmail(ctx, ProviderApple, email)
+	if err != nil {
+		return nil, fmt.Errorf("finding identities by email: %w", err)
+	}
+
+	userID := ""
+	for _, candidate := range candidates {
+		if userID != "" && candidate.UserID != userID {
+			return nil, nil // Ambiguous: never merge distinct people
+		}
+		userID = candidate.UserID
+	}
+	if userID == "" {
+		return nil, nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// createUser creates a new user for an Apple identity.
+func (s *Service) createUser(ctx context.Context, claims *AppleClaims) (*User, error) {
+	now := time.Now()
+	user := &User{
+		ID:        generateUserID(),
+		AppleSub:  claims.Subject,
+		Email:     claims.Email,
+		Locale:    s.defaultLocale,
+		Role:      RoleUser,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("creating user: %w", err)
+	}
+
+	return user, nil
+}
+
+// linkIdentity links the claims' subject to user. If a concurrent sign-in
+// linked the subject first, the user it was linked to is returned instead.
+func (s *Service) linkIdentity(ctx context.Context, user *User, claims *AppleClaims) (*User, error) {
+	now := time.Now()
+	identity := &Identity{
+		ID:         "idn_" + uuid.New().String()[:22],
+		UserID:     user.ID,
+		Provider:   ProviderApple,
+		Subject:    claims.Subject,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	applyClaims(identity, claims)
+
+	err := s.identityRepo.Create(ctx, identity)
+	if errors.Is(err, ErrIdentityExists) {
+		existing, err := s.identityRepo.FindBySubject(ctx, ProviderApple, claims.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("finding identity: %w", err)
+		}
+		return s.userRepo.FindByID(ctx, existing.UserID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("linking identity: %w", err)
+	}
+
+	return user, nil
+}
+
+// applyClaims copies the email details from Apple claims onto an identity.
+// Apple may omit the email on later sign-ins, so a missing email keeps the stored one.
+func applyClaims(identity *Identity, claims *AppleClaims) {
+	if claims.Email == "" {
+		return
+	}
+	identity.Email = normalizeEmail(claims.Email)
+	identity.EmailVerified = claimIsTrue(claims.EmailVerified)
+	identity.IsPrivateEmail = claimIsTrue(claims.IsPrivateEmail)
+}
+
+// normalizeEmail lowercases and trims an email for comparison.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// claimIsTrue interprets Apple's boolean claims, which are sent as "true"/"false".
+func claimIsTrue(value string) bool {
+	return strings.EqualFold(value, "true")
+}
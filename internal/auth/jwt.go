@@ -2,9 +2,12 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,17 +18,18 @@ import (
 // This package implements a dual-token authentication strategy:
 //
 // 1. ACCESS TOKENS (Short-lived JWTs)
-//    - Expiry: 1 hour
+//    - Expiry: 1 hour (configurable via JWTConfig.AccessTokenTTL)
 //    - Purpose: Authenticate API requests via Bearer token in Authorization header
 //    - Storage: Should be stored in memory only on the client (not persisted)
 //    - On expiry: Client should use refresh token to obtain a new access token
 //    - Claims: Contains user ID, issuer, audience, issued-at, and expiry
 //
 // 2. REFRESH TOKENS (Long-lived opaque tokens)
-//    - Expiry: 30 days
+//    - Expiry: 30 days (configurable via JWTConfig.RefreshTokenTTL)
 //    - Purpose: Obtain new access tokens without re-authenticating with Apple
 //    - Storage: Should be stored securely on the client (Keychain on iOS)
-//    - Rotation: Each use generates a new refresh token (old one is revoked)
+//    - Rotation: Each use generates a new refresh token (old one is marked used)
+//    - Reuse detection: Presenting a used token revokes its whole token family
 //    - Revocation: Can be explicitly revoked via logout endpoints
 //
 // Token Refresh Flow:
@@ -36,10 +40,18 @@ import (
 //
 // Security Considerations:
 //    - Refresh token rotation prevents token theft from being persistent
+//    - Replaying a rotated refresh token revokes every token from that sign-in,
+//      logging out both the attacker and the legitimate client
 //    - Short access token expiry limits damage from token leakage
 //    - All refresh tokens can be revoked via POST /v1/auth/logout-all
 //    - Tokens are signed with HS256 using a server-side secret key
 //
+// Signing Key Rotation:
+//    - Access tokens carry a "kid" header naming the key that signed them
+//    - RotateSigningKey makes a new key primary; the old key stays valid for
+//      verification only, so tokens already issued keep working until expiry
+//    - Once AccessTokenTTL has passed since rotation, RetireKey removes the old key
+//
 // Session Termination:
 //    - POST /v1/auth/logout: Revokes a specific refresh token
 //    - POST /v1/auth/logout-all: Revokes all refresh tokens for the user
@@ -47,11 +59,11 @@ import (
 
 // Token expiry constants.
 const (
-	// AccessTokenExpiry is how long access tokens are valid.
+	// AccessTokenExpiry is the default for how long access tokens are valid.
 	// Short expiry (1 hour) limits exposure if a token is compromised.
 	AccessTokenExpiry = 1 * time.Hour
 
-	// RefreshTokenExpiry is how long refresh tokens are valid.
+	// RefreshTokenExpiry is the default for how long refresh tokens are valid.
 	// 30 days provides a balance between security and user convenience.
 	// Users won't need to re-authenticate with Apple frequently.
 	RefreshTokenExpiry = 30 * 24 * time.Hour // 30 days
@@ -67,6 +79,11 @@ var (
 	ErrAccessTokenExpired  = errors.New("access token has expired")
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidSigningKey   = errors.New("invalid signing key")
+	ErrDuplicateKeyID      = errors.New("signing key ID already in use")
+	ErrUnknownKeyID        = errors.New("unknown signing key ID")
+	ErrRetirePrimaryKey    = errors.New("cannot retire the primary signing key")
 )
 
 // JWTClaims represents the claims in our API access tokens.
@@ -75,13 +92,22 @@ type JWTClaims struct {
 
 	// UserID is the authenticated user's ID.
 	UserID string `json:"uid"`
+
+	// Role is the user's role when the token was issued. Tokens issued
+	// before roles were introduced have none.
+	Role Role `json:"role,omitempty"`
 }
 
 // JWTService handles JWT creation and validation.
 type JWTService struct {
-	signingKey []byte
 	issuer     string
 	audience   string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+
+	mu           sync.RWMutex
+	signingKeyID string
+	keys         map[string][]byte // kid -> key, including the signing key
 }
 
 // JWTConfig holds configuration for the JWT service.
@@ -89,26 +115,117 @@ type JWTConfig struct {
 	// SigningKey is the secret key used to sign JWTs.
 	SigningKey string
 
+	// SigningKeyID is the "kid" header for tokens signed with SigningKey
+	// (default: derived from a hash of the key).
+	SigningKeyID string
+
+	// PreviousKeys are verification-only keys by key ID, from before a
+	// rotation. Tokens they signed stay valid until they expire.
+	PreviousKeys map[string]string
+
 	// Issuer is the issuer claim for tokens (e.g., "https://api.breatheroute.nl").
 	Issuer string
 
 	// Audience is the audience claim for tokens (e.g., "breatheroute-api").
 	Audience string
+
+	// AccessTokenTTL is how long access tokens are valid (default: 1 hour).
+	AccessTokenTTL time.Duration
+
+	// RefreshTokenTTL is how long refresh tokens are valid (default: 30 days).
+	RefreshTokenTTL time.Duration
 }
 
 // NewJWTService creates a new JWT service.
 func NewJWTService(cfg JWTConfig) *JWTService {
+	accessTTL := cfg.AccessTokenTTL
+	if accessTTL == 0 {
+		accessTTL = AccessTokenExpiry
+	}
+
+	refreshTTL := cfg.RefreshTokenTTL
+	if refreshTTL == 0 {
+		refreshTTL = RefreshTokenExpiry
+	}
+
+	signingKeyID := cfg.SigningKeyID
+	if signingKeyID == "" {
+		signingKeyID = deriveKeyID(cfg.SigningKey)
+	}
+
+	keys := make(map[string][]byte, len(cfg.PreviousKeys)+1)
+	for kid, key := range cfg.PreviousKeys {
+		keys[kid] = []byte(key)
+	}
+	keys[signingKeyID] = []byte(cfg.SigningKey)
+
 	return &JWTService{
-		signingKey: []byte(cfg.SigningKey),
-		issuer:     cfg.Issuer,
-		audience:   cfg.Audience,
+		issuer:       cfg.Issuer,
+		audience:     cfg.Audience,
+		accessTTL:    accessTTL,
+		refreshTTL:   refreshTTL,
+		signingKeyID: signingKeyID,
+		keys:         keys,
+	}
+}
+
+// AccessTokenTTL returns how long issued access tokens are valid.
+func (s *JWTService) AccessTokenTTL() time.Duration {
+	return s.accessTTL
+}
+
+// RefreshTokenTTL returns how long issued refresh tokens are valid.
+func (s *JWTService) RefreshTokenTTL() time.Duration {
+	return s.refreshTTL
+}
+
+// SigningKeyID returns the key ID of the current signing key.
+func (s *JWTService) SigningKeyID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signingKeyID
+}
+
+// RotateSigningKey makes key the signing key for new tokens. The previous
+// signing key is kept for verification only, so tokens it signed remain valid.
+func (s *JWTService) RotateSigningKey(kid, key string) error {
+	if kid == "" || key == "" {
+		return ErrInvalidSigningKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.keys[kid]; exists {
+		return ErrDuplicateKeyID
+	}
+
+	s.keys[kid] = []byte(key)
+	s.signingKeyID = kid
+	return nil
+}
+
+// RetireKey removes a verification-only key. Tokens it signed no longer
+// validate, so call it only after AccessTokenTTL has passed since rotation.
+func (s *JWTService) RetireKey(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if kid == s.signingKeyID {
+		return ErrRetirePrimaryKey
+	}
+	if _, exists := s.keys[kid]; !exists {
+		return ErrUnknownKeyID
 	}
+
+	delete(s.keys, kid)
+	return nil
 }
 
 // GenerateAccessToken creates a new access token for the given user.
 func (s *JWTService) GenerateAccessToken(user *User) (string, time.Time, error) {
 	now := time.Now()
-	expiresAt := now.Add(AccessTokenExpiry)
+	expiresAt := now.Add(s.accessTTL)
 
 	claims := JWTClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -121,10 +238,16 @@ func (s *JWTService) GenerateAccessToken(user *User) (string, time.Time, error)
 			ID:        generateTokenID(),
 		},
 		UserID: user.ID,
+		Role:   user.Role.OrDefault(),
 	}
 
+	s.mu.RLock()
+	kid, signingKey := s.signingKeyID, s.keys[s.signingKeyID]
+	s.mu.RUnlock()
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.signingKey)
+	token.Header["kid"] = kid
+	tokenString, err := token.SignedString(signingKey)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("signing access token: %w", err)
 	}
@@ -139,7 +262,7 @@ func (s *JWTService) ValidateAccessToken(tokenString string) (*JWTClaims, error)
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return s.signingKey, nil
+		return s.verificationKeys(t)
 	}, jwt.WithValidMethods([]string{"HS256"}),
 		jwt.WithIssuer(s.issuer),
 		jwt.WithAudience(s.audience),
@@ -161,13 +284,46 @@ func (s *JWTService) ValidateAccessToken(tokenString string) (*JWTClaims, error)
 	return claims, nil
 }
 
+// verificationKeys returns the key named by the token's kid header. Tokens
+// without a kid (issued before key IDs were added) are tried against every key.
+func (s *JWTService) verificationKeys(t *jwt.Token) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if kid, ok := t.Header["kid"].(string); ok {
+		key, exists := s.keys[kid]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+		}
+		return key, nil
+	}
+
+	set := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, 0, len(s.keys))}
+	for _, key := range s.keys {
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
+
+// deriveKeyID returns a stable key ID for a signing key that does not reveal it.
+func deriveKeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
 // RefreshToken represents a refresh token stored in the database.
 type RefreshToken struct {
-	ID        string
-	Token     string
-	UserID    string
+	ID     string
+	Token  string
+	UserID string
+	// FamilyID links every token rotated from the same sign-in, so a replayed
+	// token can revoke the whole chain.
+	FamilyID  string
 	ExpiresAt time.Time
 	CreatedAt time.Time
+	// UsedAt is set when the token is exchanged for a new pair. A used token
+	// presented again indicates it was stolen.
+	UsedAt    *time.Time
 	RevokedAt *time.Time
 }
 
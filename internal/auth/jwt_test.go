@@ -38,6 +38,14 @@ func TestJWTService_GenerateAndValidateAccessToken(t *testing.T) {
 	assert.Equal(t, user.ID, claims.UserID)
 	assert.Equal(t, user.ID, claims.Subject)
 	assert.Equal(t, "https://api.breatheroute.nl", claims.Issuer)
+	assert.Equal(t, auth.RoleUser, claims.Role, "unset roles are issued as user")
+
+	user.Role = auth.RoleAdmin
+	token, _, err = svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	claims, err = svc.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, claims.Role)
 }
 
 func TestJWTService_InvalidToken(t *testing.T) {
@@ -134,6 +142,103 @@ func TestJWTService_WrongAudience(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestJWTService_ConfigurableTTL(t *testing.T) {
+	svc := auth.NewJWTService(auth.JWTConfig{
+		SigningKey:      "test-key",
+		Issuer:          "https://api.breatheroute.nl",
+		Audience:        "breatheroute-api",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+	})
+
+	_, expiresAt, err := svc.GenerateAccessToken(&auth.User{ID: "usr_test123"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, 5*time.Second)
+	assert.Equal(t, 7*24*time.Hour, svc.RefreshTokenTTL())
+
+	defaults := auth.NewJWTService(auth.JWTConfig{SigningKey: "test-key"})
+	assert.Equal(t, auth.AccessTokenExpiry, defaults.AccessTokenTTL())
+	assert.Equal(t, auth.RefreshTokenExpiry, defaults.RefreshTokenTTL())
+}
+
+func TestJWTService_RotateSigningKey(t *testing.T) {
+	svc := auth.NewJWTService(auth.JWTConfig{
+		SigningKey:   "old-key",
+		SigningKeyID: "2026-01",
+		Issuer:       "https://api.breatheroute.nl",
+		Audience:     "breatheroute-api",
+	})
+
+	user := &auth.User{ID: "usr_test123"}
+	oldToken, _, err := svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RotateSigningKey("2026-04", "new-key"))
+	assert.Equal(t, "2026-04", svc.SigningKeyID())
+
+	// Tokens signed with the old key still verify after rotation
+	claims, err := svc.ValidateAccessToken(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+
+	// New tokens are signed with the new key
+	newToken, _, err := svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	_, err = svc.ValidateAccessToken(newToken)
+	require.NoError(t, err)
+
+	// A service that only knows the old key cannot verify new tokens
+	oldOnly := auth.NewJWTService(auth.JWTConfig{
+		SigningKey:   "old-key",
+		SigningKeyID: "2026-01",
+		Issuer:       "https://api.breatheroute.nl",
+		Audience:     "breatheroute-api",
+	})
+	_, err = oldOnly.ValidateAccessToken(newToken)
+	assert.ErrorIs(t, err, auth.ErrInvalidAccessToken)
+
+	// Retiring the old key invalidates its tokens
+	assert.ErrorIs(t, svc.RetireKey("2026-04"), auth.ErrRetirePrimaryKey)
+	require.NoError(t, svc.RetireKey("2026-01"))
+	_, err = svc.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, auth.ErrInvalidAccessToken)
+}
+
+func TestJWTService_RotateSigningKey_Errors(t *testing.T) {
+	svc := auth.NewJWTService(auth.JWTConfig{
+		SigningKey:   "key",
+		SigningKeyID: "primary",
+	})
+
+	assert.ErrorIs(t, svc.RotateSigningKey("", "new-key"), auth.ErrInvalidSigningKey)
+	assert.ErrorIs(t, svc.RotateSigningKey("next", ""), auth.ErrInvalidSigningKey)
+	assert.ErrorIs(t, svc.RotateSigningKey("primary", "new-key"), auth.ErrDuplicateKeyID)
+	assert.ErrorIs(t, svc.RetireKey("missing"), auth.ErrUnknownKeyID)
+}
+
+func TestJWTService_PreviousKeysFromConfig(t *testing.T) {
+	before := auth.NewJWTService(auth.JWTConfig{
+		SigningKey:   "old-key",
+		SigningKeyID: "2026-01",
+		Issuer:       "https://api.breatheroute.nl",
+		Audience:     "breatheroute-api",
+	})
+	oldToken, _, err := before.GenerateAccessToken(&auth.User{ID: "usr_test123"})
+	require.NoError(t, err)
+
+	// After a deploy that rotated the key, the old key is configured as previous
+	after := auth.NewJWTService(auth.JWTConfig{
+		SigningKey:   "new-key",
+		SigningKeyID: "2026-04",
+		PreviousKeys: map[string]string{"2026-01": "old-key"},
+		Issuer:       "https://api.breatheroute.nl",
+		Audience:     "breatheroute-api",
+	})
+
+	_, err = after.ValidateAccessToken(oldToken)
+	require.NoError(t, err)
+}
+
 func TestGenerateRefreshToken(t *testing.T) {
 	token1, err := auth.GenerateRefreshToken()
 	require.NoError(t, err)
@@ -9,10 +9,55 @@ type User struct {
 	AppleSub  string    `json:"-"` // Apple's user identifier (never exposed in API)
 	Email     string    `json:"email,omitempty"`
 	Locale    string    `json:"locale"`
+	Role      Role      `json:"role"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// Role is a user's authorization level.
+type Role string
+
+// User roles.
+const (
+	// RoleUser is the role of every regular app user.
+	RoleUser Role = "user"
+
+	// RoleAdmin may use operator endpoints such as cache management.
+	RoleAdmin Role = "admin"
+)
+
+// OrDefault returns the role, or RoleUser if it is unset (as for users and
+// tokens created before roles were introduced).
+func (r Role) OrDefault() Role {
+	if r == "" {
+		return RoleUser
+	}
+	return r
+}
+
+// Identity providers.
+const (
+	// ProviderApple is Sign in with Apple.
+	ProviderApple = "apple"
+)
+
+// Identity is an external sign-in identity linked to a user. A user normally
+// has one, but may gain more when the same person signs in under a new
+// provider subject (e.g., after an app transfer between Apple developer teams).
+type Identity struct {
+	ID       string
+	UserID   string
+	Provider string
+	// Subject is the provider's user identifier (never exposed in API).
+	Subject string
+	Email   string
+	// EmailVerified is whether the provider vouched for Email at last sign-in.
+	EmailVerified  bool
+	IsPrivateEmail bool
+	CreatedAt      time.Time
+	LastUsedAt     time.Time
+}
+
 // SIWATokenRequest represents the request body for Sign in with Apple authentication.
 type SIWATokenRequest struct {
 	// IdentityToken is the JWT identity token received from Apple on the iOS device.
@@ -6,9 +6,13 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// pgUniqueViolation is the PostgreSQL error code for unique constraint violations.
+const pgUniqueViolation = "23505"
+
 // PostgresUserRepository is a PostgreSQL implementation of UserRepository.
 type PostgresUserRepository struct {
 	pool *pgxpool.Pool
@@ -22,7 +26,7 @@ func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
 // FindByAppleSub finds a user by their Apple subject identifier.
 func (r *PostgresUserRepository) FindByAppleSub(ctx context.Context, appleSub string) (*User, error) {
 	query := `
-		SELECT id, apple_sub, email, locale, created_at, updated_at
+		SELECT id, apple_sub, email, locale, role, created_at, updated_at
 		FROM users
 		WHERE apple_sub = $1
 	`
@@ -33,6 +37,7 @@ func (r *PostgresUserRepository) FindByAppleSub(ctx context.Context, appleSub st
 		&user.AppleSub,
 		&user.Email,
 		&user.Locale,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -49,8 +54,8 @@ func (r *PostgresUserRepository) FindByAppleSub(ctx context.Context, appleSub st
 // Create creates a new user.
 func (r *PostgresUserRepository) Create(ctx context.Context, user *User) error {
 	query := `
-		INSERT INTO users (id, apple_sub, email, locale, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, apple_sub, email, locale, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -58,6 +63,7 @@ func (r *PostgresUserRepository) Create(ctx context.Context, user *User) error {
 		user.AppleSub,
 		user.Email,
 		user.Locale,
+		user.Role.OrDefault(),
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -67,7 +73,7 @@ func (r *PostgresUserRepository) Create(ctx context.Context, user *User) error {
 // FindByID finds a user by their internal ID.
 func (r *PostgresUserRepository) FindByID(ctx context.Context, id string) (*User, error) {
 	query := `
-		SELECT id, apple_sub, email, locale, created_at, updated_at
+		SELECT id, apple_sub, email, locale, role, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -78,6 +84,7 @@ func (r *PostgresUserRepository) FindByID(ctx context.Context, id string) (*User
 		&user.AppleSub,
 		&user.Email,
 		&user.Locale,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -91,6 +98,38 @@ func (r *PostgresUserRepository) FindByID(ctx context.Context, id string) (*User
 	return &user, nil
 }
 
+// UpdateRole sets a user's role.
+func (r *PostgresUserRepository) UpdateRole(ctx context.Context, id string, role Role) error {
+	query := `
+		UPDATE users
+		SET role = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, role)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// Delete deletes a user. Their profile, identities, refresh tokens,
+// commutes, devices, webhooks, exposures and stored routes are deleted with
+// them by cascading foreign keys.
+func (r *PostgresUserRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
 // PostgresRefreshTokenRepository is a PostgreSQL implementation of RefreshTokenRepository.
 type PostgresRefreshTokenRepository struct {
 	pool *pgxpool.Pool
@@ -104,16 +143,18 @@ func NewPostgresRefreshTokenRepository(pool *pgxpool.Pool) *PostgresRefreshToken
 // Create stores a new refresh token.
 func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, token *RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, token, user_id, expires_at, created_at, revoked_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO refresh_tokens (id, token, user_id, family_id, expires_at, created_at, used_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
 		token.ID,
 		token.Token,
 		token.UserID,
+		token.FamilyID,
 		token.ExpiresAt,
 		token.CreatedAt,
+		token.UsedAt,
 		token.RevokedAt,
 	)
 	return err
@@ -122,7 +163,7 @@ func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, token *Refr
 // FindByToken finds a refresh token by its value.
 func (r *PostgresRefreshTokenRepository) FindByToken(ctx context.Context, tokenValue string) (*RefreshToken, error) {
 	query := `
-		SELECT id, token, user_id, expires_at, created_at, revoked_at
+		SELECT id, token, user_id, family_id, expires_at, created_at, used_at, revoked_at
 		FROM refresh_tokens
 		WHERE token = $1
 	`
@@ -132,8 +173,10 @@ func (r *PostgresRefreshTokenRepository) FindByToken(ctx context.Context, tokenV
 		&token.ID,
 		&token.Token,
 		&token.UserID,
+		&token.FamilyID,
 		&token.ExpiresAt,
 		&token.CreatedAt,
+		&token.UsedAt,
 		&token.RevokedAt,
 	)
 	if err != nil {
@@ -146,6 +189,22 @@ func (r *PostgresRefreshTokenRepository) FindByToken(ctx context.Context, tokenV
 	return &token, nil
 }
 
+// MarkUsed marks a refresh token as exchanged, reporting false if it already was.
+// The conditional update makes this safe against concurrent refreshes.
+func (r *PostgresRefreshTokenRepository) MarkUsed(ctx context.Context, tokenValue string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = $1
+		WHERE token = $2 AND used_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, time.Now(), tokenValue)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
 // Revoke marks a refresh token as revoked.
 func (r *PostgresRefreshTokenRepository) Revoke(ctx context.Context, tokenValue string) error {
 	query := `
@@ -158,6 +217,18 @@ func (r *PostgresRefreshTokenRepository) Revoke(ctx context.Context, tokenValue
 	return err
 }
 
+// RevokeFamily revokes every refresh token in a token family.
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, time.Now(), familyID)
+	return err
+}
+
 // RevokeAllForUser revokes all refresh tokens for a user.
 func (r *PostgresRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
 	query := `
@@ -169,3 +240,148 @@ func (r *PostgresRefreshTokenRepository) RevokeAllForUser(ctx context.Context, u
 	_, err := r.pool.Exec(ctx, query, time.Now(), userID)
 	return err
 }
+
+// PostgresIdentityRepository is a PostgreSQL implementation of IdentityRepository.
+type PostgresIdentityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresIdentityRepository creates a new PostgreSQL identity repository.
+func NewPostgresIdentityRepository(pool *pgxpool.Pool) *PostgresIdentityRepository {
+	return &PostgresIdentityRepository{pool: pool}
+}
+
+const identityColumns = `id, user_id, provider, subject, COALESCE(email, ''), email_verified, is_private_email, created_at, last_used_at`
+
+// FindBySubject finds an identity by provider and subject.
+func (r *PostgresIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*Identity, error) {
+	query := `
+		SELECT ` + identityColumns + `
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	identity, err := scanIdentity(r.pool.QueryRow(ctx, query, provider, subject))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// FindByVerifiedEmail finds identities of a provider with a matching verified email.
+func (r *PostgresIdentityRepository) FindByVerifiedEmail(ctx context.Context, provider, email string) ([]*Identity, error) {
+	query := `
+		SELECT ` + identityColumns + `
+		FROM user_identities
+		WHERE provider = $1 AND email_verified AND LOWER(email) = LOWER($2)
+	`
+
+	return r.query(ctx, query, provider, email)
+}
+
+// ListByUser lists all identities linked to a user, oldest first.
+func (r *PostgresIdentityRepository) ListByUser(ctx context.Context, userID string) ([]*Identity, error) {
+	query := `
+		SELECT ` + identityColumns + `
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+// Create links a new identity.
+func (r *PostgresIdentityRepository) Create(ctx context.Context, identity *Identity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, email_verified, is_private_email, created_at, last_used_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.EmailVerified,
+		identity.IsPrivateEmail,
+		identity.CreatedAt,
+		identity.LastUsedAt,
+	)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return ErrIdentityExists
+	}
+	return err
+}
+
+// RecordLogin updates the email details and last-used time after a sign-in.
+func (r *PostgresIdentityRepository) RecordLogin(ctx context.Context, identity *Identity) error {
+	query := `
+		UPDATE user_identities
+		SET email = NULLIF($1, ''), email_verified = $2, is_private_email = $3, last_used_at = $4
+		WHERE provider = $5 AND subject = $6
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		identity.Email,
+		identity.EmailVerified,
+		identity.IsPrivateEmail,
+		identity.LastUsedAt,
+		identity.Provider,
+		identity.Subject,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
+
+// query runs an identity query and scans all rows.
+func (r *PostgresIdentityRepository) query(ctx context.Context, query string, args ...any) ([]*Identity, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []*Identity
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+// scanIdentity scans an identity row selected with identityColumns.
+func scanIdentity(row pgx.Row) (*Identity, error) {
+	var identity Identity
+	err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.EmailVerified,
+		&identity.IsPrivateEmail,
+		&identity.CreatedAt,
+		&identity.LastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 )
@@ -70,6 +71,34 @@ func (r *InMemoryUserRepository) FindByID(_ context.Context, id string) (*User,
 	return &userCopy, nil
 }
 
+// UpdateRole sets a user's role.
+func (r *InMemoryUserRepository) UpdateRole(_ context.Context, id string, role Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete deletes a user.
+func (r *InMemoryUserRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	delete(r.byApple, user.AppleSub)
+	delete(r.users, id)
+	return nil
+}
+
 // InMemoryRefreshTokenRepository is an in-memory implementation of RefreshTokenRepository.
 // This is intended for MVP/testing. Production should use a database-backed implementation.
 type InMemoryRefreshTokenRepository struct {
@@ -112,6 +141,22 @@ func (r *InMemoryRefreshTokenRepository) FindByToken(_ context.Context, tokenVal
 	return &tokenCopy, nil
 }
 
+// MarkUsed marks a refresh token as exchanged, reporting false if it already was.
+func (r *InMemoryRefreshTokenRepository) MarkUsed(_ context.Context, tokenValue string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[tokenValue]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	token.UsedAt = &now
+
+	return true, nil
+}
+
 // Revoke marks a refresh token as revoked.
 func (r *InMemoryRefreshTokenRepository) Revoke(_ context.Context, tokenValue string) error {
 	r.mu.Lock()
@@ -128,6 +173,21 @@ func (r *InMemoryRefreshTokenRepository) Revoke(_ context.Context, tokenValue st
 	return nil
 }
 
+// RevokeFamily revokes every refresh token in a token family.
+func (r *InMemoryRefreshTokenRepository) RevokeFamily(_ context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
+
 // RevokeAllForUser revokes all refresh tokens for a user.
 func (r *InMemoryRefreshTokenRepository) RevokeAllForUser(_ context.Context, userID string) error {
 	r.mu.Lock()
@@ -147,3 +207,102 @@ func (r *InMemoryRefreshTokenRepository) RevokeAllForUser(_ context.Context, use
 
 	return nil
 }
+
+// InMemoryIdentityRepository is an in-memory implementation of IdentityRepository.
+// This is intended for MVP/testing. Production should use a database-backed implementation.
+type InMemoryIdentityRepository struct {
+	mu         sync.RWMutex
+	identities map[string]*Identity // keyed by provider + ":" + subject
+}
+
+// NewInMemoryIdentityRepository creates a new in-memory identity repository.
+func NewInMemoryIdentityRepository() *InMemoryIdentityRepository {
+	return &InMemoryIdentityRepository{
+		identities: make(map[string]*Identity),
+	}
+}
+
+// FindBySubject finds an identity by provider and subject.
+func (r *InMemoryIdentityRepository) FindBySubject(_ context.Context, provider, subject string) (*Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identity, ok := r.identities[provider+":"+subject]
+	if !ok {
+		return nil, ErrIdentityNotFound
+	}
+
+	identityCopy := *identity
+	return &identityCopy, nil
+}
+
+// FindByVerifiedEmail finds identities of a provider with a matching verified email.
+func (r *InMemoryIdentityRepository) FindByVerifiedEmail(_ context.Context, provider, email string) ([]*Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	email = normalizeEmail(email)
+	var result []*Identity
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.EmailVerified && normalizeEmail(identity.Email) == email {
+			identityCopy := *identity
+			result = append(result, &identityCopy)
+		}
+	}
+
+	return result, nil
+}
+
+// ListByUser lists all identities linked to a user, oldest first.
+func (r *InMemoryIdentityRepository) ListByUser(_ context.Context, userID string) ([]*Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*Identity, 0, 1)
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identityCopy := *identity
+			result = append(result, &identityCopy)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// Create links a new identity.
+func (r *InMemoryIdentityRepository) Create(_ context.Context, identity *Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := identity.Provider + ":" + identity.Subject
+	if _, exists := r.identities[key]; exists {
+		return ErrIdentityExists
+	}
+
+	identityCopy := *identity
+	r.identities[key] = &identityCopy
+
+	return nil
+}
+
+// RecordLogin updates the email details and last-used time after a sign-in.
+func (r *InMemoryIdentityRepository) RecordLogin(_ context.Context, identity *Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.identities[identity.Provider+":"+identity.Subject]
+	if !ok {
+		return ErrIdentityNotFound
+	}
+
+	stored.Email = identity.Email
+	stored.EmailVerified = identity.EmailVerified
+	stored.IsPrivateEmail = identity.IsPrivateEmail
+	stored.LastUsedAt = identity.LastUsedAt
+
+	return nil
+}
@@ -11,7 +11,9 @@ import (
 
 // Predefined service errors.
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrIdentityExists   = errors.New("identity already linked")
 )
 
 // UserRepository defines the interface for user data operations.
@@ -24,6 +26,29 @@ type UserRepository interface {
 
 	// FindByID finds a user by their internal ID.
 	FindByID(ctx context.Context, id string) (*User, error)
+
+	// UpdateRole sets a user's role.
+	UpdateRole(ctx context.Context, id string, role Role) error
+}
+
+// IdentityRepository defines the interface for linked identity operations.
+type IdentityRepository interface {
+	// FindBySubject finds an identity by provider and subject.
+	FindBySubject(ctx context.Context, provider, subject string) (*Identity, error)
+
+	// FindByVerifiedEmail finds identities of a provider whose verified email
+	// matches (case-insensitively).
+	FindByVerifiedEmail(ctx context.Context, provider, email string) ([]*Identity, error)
+
+	// ListByUser lists all identities linked to a user, oldest first.
+	ListByUser(ctx context.Context, userID string) ([]*Identity, error)
+
+	// Create links a new identity. Returns ErrIdentityExists if the provider
+	// subject is already linked.
+	Create(ctx context.Context, identity *Identity) error
+
+	// RecordLogin updates the email details and last-used time after a sign-in.
+	RecordLogin(ctx context.Context, identity *Identity) error
 }
 
 // RefreshTokenRepository defines the interface for refresh token operations.
@@ -34,9 +59,16 @@ type RefreshTokenRepository interface {
 	// FindByToken finds a refresh token by its value.
 	FindByToken(ctx context.Context, token string) (*RefreshToken, error)
 
+	// MarkUsed marks a refresh token as exchanged. It reports false if the
+	// token was already used, so concurrent refreshes cannot both succeed.
+	MarkUsed(ctx context.Context, token string) (bool, error)
+
 	// Revoke marks a refresh token as revoked.
 	Revoke(ctx context.Context, token string) error
 
+	// RevokeFamily revokes every refresh token in a token family.
+	RevokeFamily(ctx context.Context, familyID string) error
+
 	// RevokeAllForUser revokes all refresh tokens for a user.
 	RevokeAllForUser(ctx context.Context, userID string) error
 }
@@ -47,16 +79,24 @@ type Service struct {
 	jwtService    *JWTService
 	userRepo      UserRepository
 	refreshRepo   RefreshTokenRepository
+	identityRepo  IdentityRepository
 	defaultLocale string
+	adminSubs     map[string]bool
 }
 
 // ServiceConfig holds configuration for the auth service.
 type ServiceConfig struct {
-	SIWAVerifier  *SIWAVerifier
-	JWTService    *JWTService
-	UserRepo      UserRepository
-	RefreshRepo   RefreshTokenRepository
+	SIWAVerifier *SIWAVerifier
+	JWTService   *JWTService
+	UserRepo     UserRepository
+	RefreshRepo  RefreshTokenRepository
+	// IdentityRepo stores linked sign-in identities (default: in-memory).
+	IdentityRepo  IdentityRepository
 	DefaultLocale string
+	// AdminAppleSubs are Apple subjects whose users are promoted to
+	// RoleAdmin when they sign in. An admin whose subject is removed is
+	// demoted at their next sign-in.
+	AdminAppleSubs []string
 }
 
 // NewService creates a new auth service.
@@ -66,12 +106,26 @@ func NewService(cfg ServiceConfig) *Service {
 		locale = "nl-NL"
 	}
 
+	identityRepo := cfg.IdentityRepo
+	if identityRepo == nil {
+		identityRepo = NewInMemoryIdentityRepository()
+	}
+
+	adminSubs := make(map[string]bool, len(cfg.AdminAppleSubs))
+	for _, sub := range cfg.AdminAppleSubs {
+		if sub != "" {
+			adminSubs[sub] = true
+		}
+	}
+
 	return &Service{
 		siwaVerifier:  cfg.SIWAVerifier,
 		jwtService:    cfg.JWTService,
 		userRepo:      cfg.UserRepo,
 		refreshRepo:   cfg.RefreshRepo,
+		identityRepo:  identityRepo,
 		defaultLocale: locale,
+		adminSubs:     adminSubs,
 	}
 }
 
@@ -90,16 +144,19 @@ func (s *Service) AuthenticateWithApple(ctx context.Context, req *SIWATokenReque
 	}
 
 	// Find or create user
-	user, err := s.findOrCreateUser(ctx, claims)
+	user, err := s.FindOrCreateByAppleSub(ctx, claims)
 	if err != nil {
 		return nil, fmt.Errorf("finding or creating user: %w", err)
 	}
 
-	// Generate tokens
-	return s.generateTokens(ctx, user)
+	// Generate tokens, starting a new token family
+	return s.generateTokens(ctx, user, uuid.New().String())
 }
 
 // RefreshAccessToken refreshes an access token using a refresh token.
+// The refresh token is rotated: it is marked used and a new one from the same
+// family is issued. If a used token is presented again, it has been replayed,
+// so the whole family is revoked and ErrRefreshTokenReused is returned.
 func (s *Service) RefreshAccessToken(ctx context.Context, refreshTokenStr string) (*TokenResponse, error) {
 	// Find the refresh token
 	refreshToken, err := s.refreshRepo.FindByToken(ctx, refreshTokenStr)
@@ -107,6 +164,10 @@ func (s *Service) RefreshAccessToken(ctx context.Context, refreshTokenStr string
 		return nil, ErrInvalidRefreshToken
 	}
 
+	if refreshToken.UsedAt != nil {
+		return nil, s.handleReuse(ctx, refreshToken)
+	}
+
 	// Check if token is valid
 	if refreshToken.RevokedAt != nil {
 		return nil, ErrInvalidRefreshToken
@@ -122,24 +183,48 @@ func (s *Service) RefreshAccessToken(ctx context.Context, refreshTokenStr string
 		return nil, ErrUserNotFound
 	}
 
-	// Revoke the old refresh token (rotation)
-	if err := s.refreshRepo.Revoke(ctx, refreshTokenStr); err != nil {
-		return nil, fmt.Errorf("revoking old refresh token: %w", err)
+	// Mark the old refresh token used (rotation). Losing this race means the
+	// same token was presented twice concurrently.
+	marked, err := s.refreshRepo.MarkUsed(ctx, refreshTokenStr)
+	if err != nil {
+		return nil, fmt.Errorf("marking refresh token used: %w", err)
+	}
+	if !marked {
+		return nil, s.handleReuse(ctx, refreshToken)
 	}
 
-	// Generate new tokens
-	return s.generateTokens(ctx, user)
+	// Generate new tokens in the same family
+	return s.generateTokens(ctx, user, refreshToken.FamilyID)
+}
+
+// handleReuse revokes the family of a replayed refresh token.
+func (s *Service) handleReuse(ctx context.Context, refreshToken *RefreshToken) error {
+	if err := s.refreshRepo.RevokeFamily(ctx, refreshToken.FamilyID); err != nil {
+		return fmt.Errorf("revoking refresh token family: %w", err)
+	}
+	return ErrRefreshTokenReused
 }
 
 // ValidateAccessToken validates an access token and returns the user ID.
 func (s *Service) ValidateAccessToken(tokenString string) (string, error) {
-	claims, err := s.jwtService.ValidateAccessToken(tokenString)
+	claims, err := s.ValidateAccessTokenClaims(tokenString)
 	if err != nil {
 		return "", err
 	}
 	return claims.UserID, nil
 }
 
+// ValidateAccessTokenClaims validates an access token and returns its claims.
+// Tokens without a role claim are given RoleUser.
+func (s *Service) ValidateAccessTokenClaims(tokenString string) (*JWTClaims, error) {
+	claims, err := s.jwtService.ValidateAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	claims.Role = claims.Role.OrDefault()
+	return claims, nil
+}
+
 // GetUser retrieves a user by ID.
 func (s *Service) GetUser(ctx context.Context, userID string) (*User, error) {
 	return s.userRepo.FindByID(ctx, userID)
@@ -155,38 +240,9 @@ func (s *Service) RevokeAllTokens(ctx context.Context, userID string) error {
 	return s.refreshRepo.RevokeAllForUser(ctx, userID)
 }
 
-// findOrCreateUser finds an existing user or creates a new one.
-func (s *Service) findOrCreateUser(ctx context.Context, claims *AppleClaims) (*User, error) {
-	// Try to find existing user
-	user, err := s.userRepo.FindByAppleSub(ctx, claims.Subject)
-	if err == nil {
-		return user, nil
-	}
-
-	if !errors.Is(err, ErrUserNotFound) {
-		return nil, err
-	}
-
-	// Create new user
-	now := time.Now()
-	user = &User{
-		ID:        generateUserID(),
-		AppleSub:  claims.Subject,
-		Email:     claims.Email,
-		Locale:    s.defaultLocale,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("creating user: %w", err)
-	}
-
-	return user, nil
-}
-
 // generateTokens generates both access and refresh tokens for a user.
-func (s *Service) generateTokens(ctx context.Context, user *User) (*TokenResponse, error) {
+// The refresh token joins the given token family.
+func (s *Service) generateTokens(ctx context.Context, user *User, familyID string) (*TokenResponse, error) {
 	// Generate access token
 	accessToken, expiresAt, err := s.jwtService.GenerateAccessToken(user)
 	if err != nil {
@@ -204,7 +260,8 @@ func (s *Service) generateTokens(ctx context.Context, user *User) (*TokenRespons
 		ID:        uuid.New().String(),
 		Token:     refreshTokenStr,
 		UserID:    user.ID,
-		ExpiresAt: time.Now().Add(RefreshTokenExpiry),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.jwtService.RefreshTokenTTL()),
 		CreatedAt: time.Now(),
 	}
 
@@ -262,6 +319,7 @@ func (s *Service) DevAuthenticate(ctx context.Context, req *DevAuthenticateReque
 			AppleSub:  testSub,
 			Email:     email,
 			Locale:    s.defaultLocale,
+			Role:      RoleUser,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
@@ -271,6 +329,6 @@ func (s *Service) DevAuthenticate(ctx context.Context, req *DevAuthenticateReque
 		}
 	}
 
-	// Generate tokens
-	return s.generateTokens(ctx, user)
+	// Generate tokens, starting a new token family
+	return s.generateTokens(ctx, user, uuid.New().String())
 }
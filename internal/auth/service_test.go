@@ -0,0 +1,322 @@
+package auth_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/auth"
+)
+
+func newTestAuthService() *auth.Service {
+	return auth.NewService(auth.ServiceConfig{
+		JWTService: auth.NewJWTService(auth.JWTConfig{
+			SigningKey: "test-secret-key-for-testing-only",
+			Issuer:     "https://api.breatheroute.nl",
+			Audience:   "breatheroute-api",
+		}),
+		UserRepo:    auth.NewInMemoryUserRepository(),
+		RefreshRepo: auth.NewInMemoryRefreshTokenRepository(),
+	})
+}
+
+func TestService_RefreshAccessToken_Rotates(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	login, err := svc.DevAuthenticate(ctx, &auth.DevAuthenticateRequest{})
+	require.NoError(t, err)
+
+	refreshed, err := svc.RefreshAccessToken(ctx, login.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	// The rotated token keeps working
+	_, err = svc.RefreshAccessToken(ctx, refreshed.RefreshToken)
+	require.NoError(t, err)
+}
+
+func TestService_RefreshAccessToken_ReplayRevokesFamily(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	login, err := svc.DevAuthenticate(ctx, &auth.DevAuthenticateRequest{})
+	require.NoError(t, err)
+
+	// Legitimate client rotates its token
+	rotated, err := svc.RefreshAccessToken(ctx, login.RefreshToken)
+	require.NoError(t, err)
+
+	// An attacker replays the original token
+	_, err = svc.RefreshAccessToken(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	// The whole family is revoked, including the legitimate client's token
+	_, err = svc.RefreshAccessToken(ctx, rotated.RefreshToken)
+	assert.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+}
+
+func TestService_RefreshAccessToken_ReplayLeavesOtherSessions(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	phone, err := svc.DevAuthenticate(ctx, &auth.DevAuthenticateRequest{})
+	require.NoError(t, err)
+	tablet, err := svc.DevAuthenticate(ctx, &auth.DevAuthenticateRequest{UserID: phone.User.ID})
+	require.NoError(t, err)
+
+	_, err = svc.RefreshAccessToken(ctx, phone.RefreshToken)
+	require.NoError(t, err)
+	_, err = svc.RefreshAccessToken(ctx, phone.RefreshToken)
+	require.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	// A separate sign-in is a separate family and is unaffected
+	_, err = svc.RefreshAccessToken(ctx, tablet.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestService_RefreshAccessToken_ConcurrentReplay(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	login, err := svc.DevAuthenticate(ctx, &auth.DevAuthenticateRequest{})
+	require.NoError(t, err)
+
+	const attempts = 10
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.RefreshAccessToken(ctx, login.RefreshToken); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, successes, 1, "a refresh token can be exchanged at most once")
+}
+
+func appleClaims(sub, email, verified string) *auth.AppleClaims {
+	return &auth.AppleClaims{Subject: sub, Email: email, EmailVerified: verified}
+}
+
+func TestService_FindOrCreateByAppleSub_ReturnsSameUser(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	first, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.1", "jan@example.com", "true"))
+	require.NoError(t, err)
+
+	// Apple may omit the email on later sign-ins
+	again, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.1", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+
+	identities, err := svc.ListIdentities(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, "jan@example.com", identities[0].Email)
+	assert.True(t, identities[0].EmailVerified)
+}
+
+func TestService_FindOrCreateByAppleSub_LinksNewSubWithVerifiedEmail(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	original, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.old", "Jan@Example.com", "true"))
+	require.NoError(t, err)
+
+	// Same person appears under a new subject, e.g. after an app transfer
+	migrated, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.new", "jan@example.com", "true"))
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, migrated.ID)
+
+	identities, err := svc.ListIdentities(ctx, original.ID)
+	require.NoError(t, err)
+	require.Len(t, identities, 2)
+	assert.Equal(t, "sub.old", identities[0].Subject)
+	assert.Equal(t, "sub.new", identities[1].Subject)
+}
+
+func TestService_FindOrCreateByAppleSub_DoesNotMergeDistinctPeople(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []*auth.AppleClaims
+		incoming *auth.AppleClaims
+	}{
+		{
+			name:     "unverified email",
+			existing: []*auth.AppleClaims{appleClaims("sub.a", "jan@example.com", "true")},
+			incoming: appleClaims("sub.b", "jan@example.com", "false"),
+		},
+		{
+			name:     "no email",
+			existing: []*auth.AppleClaims{appleClaims("sub.a", "jan@example.com", "true")},
+			incoming: appleClaims("sub.b", "", ""),
+		},
+		{
+			name:     "existing identity email not verified",
+			existing: []*auth.AppleClaims{appleClaims("sub.a", "jan@example.com", "false")},
+			incoming: appleClaims("sub.b", "jan@example.com", "true"),
+		},
+		{
+			name:     "different email",
+			existing: []*auth.AppleClaims{appleClaims("sub.a", "jan@example.com", "true")},
+			incoming: appleClaims("sub.b", "piet@example.com", "true"),
+		},
+		{
+			name: "email verified for several users",
+			existing: []*auth.AppleClaims{
+				appleClaims("sub.a", "shared@example.com", "true"),
+				appleClaims("sub.c", "other@example.com", "true"),
+				// The second user later verifies the same address
+				appleClaims("sub.c", "shared@example.com", "true"),
+			},
+			incoming: appleClaims("sub.b", "shared@example.com", "true"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestAuthService()
+			ctx := context.Background()
+
+			existingIDs := make(map[string]bool)
+			for _, claims := range tt.existing {
+				user, err := svc.FindOrCreateByAppleSub(ctx, claims)
+				require.NoError(t, err)
+				existingIDs[user.ID] = true
+			}
+			user, err := svc.FindOrCreateByAppleSub(ctx, tt.incoming)
+			require.NoError(t, err)
+			assert.False(t, existingIDs[user.ID], "must not link to an existing user")
+		})
+	}
+}
+
+func TestService_FindOrCreateByAppleSub_LinksLegacyUser(t *testing.T) {
+	userRepo := auth.NewInMemoryUserRepository()
+	svc := auth.NewService(auth.ServiceConfig{
+		JWTService:  auth.NewJWTService(auth.JWTConfig{SigningKey: "test-key"}),
+		UserRepo:    userRepo,
+		RefreshRepo: auth.NewInMemoryRefreshTokenRepository(),
+	})
+	ctx := context.Background()
+
+	// User created before identities were tracked
+	legacy := &auth.User{ID: "usr_legacy", AppleSub: "sub.legacy", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, legacy))
+
+	user, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.legacy", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, legacy.ID, user.ID)
+
+	identities, err := svc.ListIdentities(ctx, legacy.ID)
+	require.NoError(t, err)
+	assert.Len(t, identities, 1)
+}
+
+func TestService_FindOrCreateByAppleSub_SeedsAdmins(t *testing.T) {
+	userRepo := auth.NewInMemoryUserRepository()
+	svc := auth.NewService(auth.ServiceConfig{
+		JWTService:     auth.NewJWTService(auth.JWTConfig{SigningKey: "test-key"}),
+		UserRepo:       userRepo,
+		RefreshRepo:    auth.NewInMemoryRefreshTokenRepository(),
+		AdminAppleSubs: []string{"sub.admin"},
+	})
+	ctx := context.Background()
+
+	regular, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.regular", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleUser, regular.Role)
+
+	admin, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.admin", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, admin.Role)
+
+	stored, err := userRepo.FindByID(ctx, admin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, stored.Role)
+
+	// Existing users are promoted on their next sign-in
+	legacy := &auth.User{ID: "usr_legacy", AppleSub: "sub.admin.legacy", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, legacy))
+	svc = auth.NewService(auth.ServiceConfig{
+		JWTService:     auth.NewJWTService(auth.JWTConfig{SigningKey: "test-key"}),
+		UserRepo:       userRepo,
+		RefreshRepo:    auth.NewInMemoryRefreshTokenRepository(),
+		AdminAppleSubs: []string{"sub.admin.legacy"},
+	})
+	promoted, err := svc.FindOrCreateByAppleSub(ctx, appleClaims("sub.admin.legacy", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, promoted.Role)
+}
+
+func TestService_FindOrCreateByAppleSub_DemotesRemovedAdmins(t *testing.T) {
+	userRepo := auth.NewInMemoryUserRepository()
+	identityRepo := auth.NewInMemoryIdentityRepository()
+	newService := func(adminSubs ...string) *auth.Service {
+		return auth.NewService(auth.ServiceConfig{
+			JWTService:     auth.NewJWTService(auth.JWTConfig{SigningKey: "test-key"}),
+			UserRepo:       userRepo,
+			RefreshRepo:    auth.NewInMemoryRefreshTokenRepository(),
+			IdentityRepo:   identityRepo,
+			AdminAppleSubs: adminSubs,
+		})
+	}
+	ctx := context.Background()
+
+	admin, err := newService("sub.admin").FindOrCreateByAppleSub(ctx, appleClaims("sub.admin", "", ""))
+	require.NoError(t, err)
+	require.Equal(t, auth.RoleAdmin, admin.Role)
+
+	// Still listed: the role is kept
+	kept, err := newService("sub.admin", "sub.other").FindOrCreateByAppleSub(ctx, appleClaims("sub.admin", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, kept.Role)
+
+	// Removed from the allowlist: demoted at the next sign-in
+	demoted, err := newService("sub.other").FindOrCreateByAppleSub(ctx, appleClaims("sub.admin", "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleUser, demoted.Role)
+
+	stored, err := userRepo.FindByID(ctx, admin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleUser, stored.Role)
+}
+
+func TestService_RefreshAccessToken_CarriesRole(t *testing.T) {
+	userRepo := auth.NewInMemoryUserRepository()
+	svc := auth.NewService(auth.ServiceConfig{
+		JWTService:  auth.NewJWTService(auth.JWTConfig{SigningKey: "test-key"}),
+		UserRepo:    userRepo,
+		RefreshRepo: auth.NewInMemoryRefreshTokenRepository(),
+	})
+	ctx := context.Background()
+
+	login, err := svc.DevAuthenticate(ctx, &auth.DevAuthenticateRequest{})
+	require.NoError(t, err)
+	claims, err := svc.ValidateAccessTokenClaims(login.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleUser, claims.Role)
+
+	// A role change applies from the next refresh
+	require.NoError(t, userRepo.UpdateRole(ctx, login.User.ID, auth.RoleAdmin))
+	refreshed, err := svc.RefreshAccessToken(ctx, login.RefreshToken)
+	require.NoError(t, err)
+	claims, err = svc.ValidateAccessTokenClaims(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, claims.Role)
+}
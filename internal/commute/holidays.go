@@ -0,0 +1,81 @@
+package commute
+
+import (
+	"sort"
+	"time"
+)
+
+// dateLayout is the format of commute exception dates.
+const dateLayout = "2006-01-02"
+
+// Holiday is a public holiday on a calendar date.
+type Holiday struct {
+	Date string // YYYY-MM-DD
+	Name string
+}
+
+// DutchPublicHolidays returns the Dutch public holidays in year, in date
+// order. Liberation Day is included every year, although employers only
+// have to give it off in lustrum years.
+func DutchPublicHolidays(year int) []Holiday {
+	easter := easterSunday(year)
+	date := func(t time.Time) string { return t.Format(dateLayout) }
+	on := func(month time.Month, day int) string {
+		return date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	}
+
+	// King's Day moves to the 26th when the 27th falls on a Sunday
+	kingsDay := time.Date(year, time.April, 27, 0, 0, 0, 0, time.UTC)
+	if kingsDay.Weekday() == time.Sunday {
+		kingsDay = kingsDay.AddDate(0, 0, -1)
+	}
+
+	holidays := []Holiday{
+		{on(time.January, 1), "Nieuwjaarsdag"},
+		{date(easter), "Eerste Paasdag"},
+		{date(easter.AddDate(0, 0, 1)), "Tweede Paasdag"},
+		{date(kingsDay), "Koningsdag"},
+		{on(time.May, 5), "Bevrijdingsdag"},
+		{date(easter.AddDate(0, 0, 39)), "Hemelvaartsdag"},
+		{date(easter.AddDate(0, 0, 49)), "Eerste Pinksterdag"},
+		{date(easter.AddDate(0, 0, 50)), "Tweede Pinksterdag"},
+		{on(time.December, 25), "Eerste Kerstdag"},
+		{on(time.December, 26), "Tweede Kerstdag"},
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date < holidays[j].Date
+	})
+	return holidays
+}
+
+// IsDutchPublicHoliday reports whether t's calendar date is a Dutch public
+// holiday.
+func IsDutchPublicHoliday(t time.Time) bool {
+	day := t.Format(dateLayout)
+	for _, h := range DutchPublicHolidays(t.Year()) {
+		if h.Date == day {
+			return true
+		}
+	}
+	return false
+}
+
+// easterSunday returns the date of Easter Sunday in the Gregorian calendar,
+// using the anonymous Gregorian (Meeus/Jones/Butcher) algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
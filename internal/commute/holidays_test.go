@@ -0,0 +1,45 @@
+package commute
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDutchPublicHolidays(t *testing.T) {
+	tests := []struct {
+		year int
+		name string
+		want string
+	}{
+		{2026, "Tweede Paasdag", "2026-04-06"},
+		{2026, "Koningsdag", "2026-04-27"},
+		{2025, "Koningsdag", "2025-04-26"}, // 27 April 2025 is a Sunday
+		{2026, "Hemelvaartsdag", "2026-05-14"},
+		{2026, "Tweede Pinksterdag", "2026-05-25"},
+		{2024, "Tweede Paasdag", "2024-04-01"},
+		{2027, "Eerste Paasdag", "2027-03-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			for _, h := range DutchPublicHolidays(tt.year) {
+				if h.Name == tt.name {
+					got = h.Date
+				}
+			}
+			if got != tt.want {
+				t.Errorf("%s %d = %q, want %q", tt.name, tt.year, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDutchPublicHoliday(t *testing.T) {
+	if !IsDutchPublicHoliday(time.Date(2026, time.December, 25, 8, 0, 0, 0, time.UTC)) {
+		t.Error("Christmas Day should be a holiday")
+	}
+	if IsDutchPublicHoliday(time.Date(2026, time.April, 28, 8, 0, 0, 0, time.UTC)) {
+		t.Error("the day after Koningsdag should not be a holiday")
+	}
+}
@@ -2,6 +2,7 @@ package commute
 
 import (
 	"context"
+	"sort"
 	"sync"
 )
 
@@ -83,6 +84,39 @@ func (r *InMemoryRepository) List(_ context.Context, userID string, opts ListOpt
 	return result, nil
 }
 
+// ListAll retrieves the commutes of all users in ID order, with pagination.
+func (r *InMemoryRepository) ListAll(_ context.Context, opts ListOptions) (*ListResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var commutes []*Commute
+	for _, c := range r.commutes {
+		if c.ID > opts.Cursor {
+			cpy := *c
+			commutes = append(commutes, &cpy)
+		}
+	}
+	sort.Slice(commutes, func(i, j int) bool {
+		return commutes[i].ID < commutes[j].ID
+	})
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	result := &ListResult{
+		Items: commutes,
+	}
+
+	if len(commutes) > limit {
+		result.Items = commutes[:limit]
+		result.NextCursor = commutes[limit-1].ID
+	}
+
+	return result, nil
+}
+
 // Create creates a new commute.
 func (r *InMemoryRepository) Create(_ context.Context, c *Commute) error {
 	r.mu.Lock()
@@ -98,10 +132,15 @@ func (r *InMemoryRepository) Update(_ context.Context, c *Commute) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.commutes[c.ID]; !ok {
+	stored, ok := r.commutes[c.ID]
+	if !ok {
 		return ErrCommuteNotFound
 	}
+	if stored.Version != c.Version {
+		return ErrVersionConflict
+	}
 
+	c.Version++
 	cpy := *c
 	r.commutes[c.ID] = &cpy
 	return nil
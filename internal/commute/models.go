@@ -9,6 +9,7 @@ import (
 // Repository errors.
 var (
 	ErrCommuteNotFound = errors.New("commute not found")
+	ErrVersionConflict = errors.New("commute was modified concurrently")
 )
 
 // Commute represents a saved commute.
@@ -18,14 +19,26 @@ type Commute struct {
 	Label                     string
 	Origin                    Location
 	Destination               Location
+	Waypoints                 []Point // Ordered stops between origin and destination
 	DaysOfWeek                []int
-	PreferredArrivalTimeLocal string // HH:mm format in the specified timezone
-	Timezone                  string // IANA timezone identifier (e.g., "Europe/Amsterdam")
+	PreferredArrivalTimeLocal string   // HH:mm format in the specified timezone
+	Timezone                  string   // IANA timezone identifier (e.g., "Europe/Amsterdam")
+	Exceptions                []string // YYYY-MM-DD dates on which the commute does not run
+	SkipPublicHolidays        bool     // Also skip Dutch public holidays
 	Notes                     *string
+	Version                   int64 // Incremented on each update, for optimistic concurrency
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
 }
 
+// Stops returns the commute's origin, waypoints and destination in order.
+func (c *Commute) Stops() []Point {
+	stops := make([]Point, 0, len(c.Waypoints)+2)
+	stops = append(stops, c.Origin.Point)
+	stops = append(stops, c.Waypoints...)
+	return append(stops, c.Destination.Point)
+}
+
 // Location represents a geographic location.
 type Location struct {
 	Point   Point
@@ -34,6 +47,6 @@ type Location struct {
 
 // Point represents a geographic point.
 type Point struct {
-	Lat float64
-	Lon float64
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
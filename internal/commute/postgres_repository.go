@@ -6,11 +6,14 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/breatheroute/breatheroute/internal/database"
 )
 
 // PostgresRepository is a PostgreSQL implementation of Repository.
 type PostgresRepository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	reader *pgxpool.Pool
 }
 
 // NewPostgresRepository creates a new PostgreSQL commute repository.
@@ -18,15 +21,30 @@ func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
 	return &PostgresRepository{pool: pool}
 }
 
+// WithReader sets a read replica pool for SELECTs in requests marked with
+// database.WithReplicaReads. Mutations and all other reads use the primary.
+func (r *PostgresRepository) WithReader(reader *pgxpool.Pool) *PostgresRepository {
+	r.reader = reader
+	return r
+}
+
+// readPool returns the pool to read from for ctx.
+func (r *PostgresRepository) readPool(ctx context.Context) *pgxpool.Pool {
+	if r.reader != nil && database.UseReplica(ctx) {
+		return r.reader
+	}
+	return r.pool
+}
+
 // Get retrieves a commute by ID.
 func (r *PostgresRepository) Get(ctx context.Context, id string) (*Commute, error) {
 	query := `
 		SELECT
 			id, user_id, label,
 			origin_lat, origin_lon, origin_geohash,
-			destination_lat, destination_lon, destination_geohash,
-			days_of_week, preferred_arrival_time_local, timezone, notes,
-			created_at, updated_at
+			destination_lat, destination_lon, destination_geohash, waypoints,
+			days_of_week, preferred_arrival_time_local, timezone, exceptions, skip_public_holidays, notes,
+			version, created_at, updated_at
 		FROM commutes
 		WHERE id = $1
 	`
@@ -40,9 +58,9 @@ func (r *PostgresRepository) GetByUserAndID(ctx context.Context, userID, commute
 		SELECT
 			id, user_id, label,
 			origin_lat, origin_lon, origin_geohash,
-			destination_lat, destination_lon, destination_geohash,
-			days_of_week, preferred_arrival_time_local, timezone, notes,
-			created_at, updated_at
+			destination_lat, destination_lon, destination_geohash, waypoints,
+			days_of_week, preferred_arrival_time_local, timezone, exceptions, skip_public_holidays, notes,
+			version, created_at, updated_at
 		FROM commutes
 		WHERE id = $1 AND user_id = $2
 	`
@@ -54,7 +72,7 @@ func (r *PostgresRepository) GetByUserAndID(ctx context.Context, userID, commute
 func (r *PostgresRepository) scanCommute(ctx context.Context, query string, args ...interface{}) (*Commute, error) {
 	var commute Commute
 
-	err := r.pool.QueryRow(ctx, query, args...).Scan(
+	err := r.readPool(ctx).QueryRow(ctx, query, args...).Scan(
 		&commute.ID,
 		&commute.UserID,
 		&commute.Label,
@@ -64,10 +82,14 @@ func (r *PostgresRepository) scanCommute(ctx context.Context, query string, args
 		&commute.Destination.Point.Lat,
 		&commute.Destination.Point.Lon,
 		&commute.Destination.Geohash,
+		&commute.Waypoints,
 		&commute.DaysOfWeek,
 		&commute.PreferredArrivalTimeLocal,
 		&commute.Timezone,
+		&commute.Exceptions,
+		&commute.SkipPublicHolidays,
 		&commute.Notes,
+		&commute.Version,
 		&commute.CreatedAt,
 		&commute.UpdatedAt,
 	)
@@ -94,16 +116,73 @@ func (r *PostgresRepository) List(ctx context.Context, userID string, opts ListO
 		SELECT
 			id, user_id, label,
 			origin_lat, origin_lon, origin_geohash,
-			destination_lat, destination_lon, destination_geohash,
-			days_of_week, preferred_arrival_time_local, timezone, notes,
-			created_at, updated_at
+			destination_lat, destination_lon, destination_geohash, waypoints,
+			days_of_week, preferred_arrival_time_local, timezone, exceptions, skip_public_holidays, notes,
+			version, created_at, updated_at
 		FROM commutes
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2
 	`
 
-	rows, err := r.pool.Query(ctx, query, userID, fetchLimit)
+	commutes, err := r.queryCommutes(ctx, query, userID, fetchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{
+		Items: commutes,
+	}
+
+	// If we got more results than the limit, there are more pages
+	if len(commutes) > limit {
+		result.Items = commutes[:limit]
+		// Use the last item's ID as the cursor for the next page
+		result.NextCursor = commutes[limit-1].ID
+	}
+
+	return result, nil
+}
+
+// ListAll retrieves the commutes of all users in ID order, with pagination.
+func (r *PostgresRepository) ListAll(ctx context.Context, opts ListOptions) (*ListResult, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT
+			id, user_id, label,
+			origin_lat, origin_lon, origin_geohash,
+			destination_lat, destination_lon, destination_geohash, waypoints,
+			days_of_week, preferred_arrival_time_local, timezone, exceptions, skip_public_holidays, notes,
+			version, created_at, updated_at
+		FROM commutes
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	commutes, err := r.queryCommutes(ctx, query, opts.Cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{
+		Items: commutes,
+	}
+	if len(commutes) > limit {
+		result.Items = commutes[:limit]
+		result.NextCursor = commutes[limit-1].ID
+	}
+
+	return result, nil
+}
+
+// queryCommutes scans all commutes returned by a query.
+func (r *PostgresRepository) queryCommutes(ctx context.Context, query string, args ...interface{}) ([]*Commute, error) {
+	rows, err := r.readPool(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -122,10 +201,14 @@ func (r *PostgresRepository) List(ctx context.Context, userID string, opts ListO
 			&commute.Destination.Point.Lat,
 			&commute.Destination.Point.Lon,
 			&commute.Destination.Geohash,
+			&commute.Waypoints,
 			&commute.DaysOfWeek,
 			&commute.PreferredArrivalTimeLocal,
 			&commute.Timezone,
+			&commute.Exceptions,
+			&commute.SkipPublicHolidays,
 			&commute.Notes,
+			&commute.Version,
 			&commute.CreatedAt,
 			&commute.UpdatedAt,
 		)
@@ -139,18 +222,7 @@ func (r *PostgresRepository) List(ctx context.Context, userID string, opts ListO
 		return nil, err
 	}
 
-	result := &ListResult{
-		Items: commutes,
-	}
-
-	// If we got more results than the limit, there are more pages
-	if len(commutes) > limit {
-		result.Items = commutes[:limit]
-		// Use the last item's ID as the cursor for the next page
-		result.NextCursor = commutes[limit-1].ID
-	}
-
-	return result, nil
+	return commutes, nil
 }
 
 // Create creates a new commute.
@@ -159,10 +231,10 @@ func (r *PostgresRepository) Create(ctx context.Context, commute *Commute) error
 		INSERT INTO commutes (
 			id, user_id, label,
 			origin_lat, origin_lon, origin_geohash,
-			destination_lat, destination_lon, destination_geohash,
-			days_of_week, preferred_arrival_time_local, timezone, notes,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			destination_lat, destination_lon, destination_geohash, waypoints,
+			days_of_week, preferred_arrival_time_local, timezone, exceptions, skip_public_holidays, notes,
+			version, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -175,13 +247,18 @@ func (r *PostgresRepository) Create(ctx context.Context, commute *Commute) error
 		commute.Destination.Point.Lat,
 		commute.Destination.Point.Lon,
 		commute.Destination.Geohash,
+		commute.Waypoints,
 		commute.DaysOfWeek,
 		commute.PreferredArrivalTimeLocal,
 		commute.Timezone,
+		commute.Exceptions,
+		commute.SkipPublicHolidays,
 		commute.Notes,
+		commute.Version,
 		commute.CreatedAt,
 		commute.UpdatedAt,
 	)
+	database.MarkWrite(ctx)
 	return err
 }
 
@@ -196,12 +273,16 @@ func (r *PostgresRepository) Update(ctx context.Context, commute *Commute) error
 			destination_lat = $6,
 			destination_lon = $7,
 			destination_geohash = $8,
-			days_of_week = $9,
-			preferred_arrival_time_local = $10,
-			timezone = $11,
-			notes = $12,
-			updated_at = $13
-		WHERE id = $1
+			waypoints = $9,
+			days_of_week = $10,
+			preferred_arrival_time_local = $11,
+			timezone = $12,
+			exceptions = $13,
+			skip_public_holidays = $14,
+			notes = $15,
+			updated_at = $16,
+			version = version + 1
+		WHERE id = $1 AND version = $17
 	`
 
 	result, err := r.pool.Exec(ctx, query,
@@ -213,20 +294,35 @@ func (r *PostgresRepository) Update(ctx context.Context, commute *Commute) error
 		commute.Destination.Point.Lat,
 		commute.Destination.Point.Lon,
 		commute.Destination.Geohash,
+		commute.Waypoints,
 		commute.DaysOfWeek,
 		commute.PreferredArrivalTimeLocal,
 		commute.Timezone,
+		commute.Exceptions,
+		commute.SkipPublicHolidays,
 		commute.Notes,
 		commute.UpdatedAt,
+		commute.Version,
 	)
+	database.MarkWrite(ctx)
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
+		// Distinguish a missing commute from a concurrent update
+		var exists bool
+		err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM commutes WHERE id = $1)`, commute.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrVersionConflict
+		}
 		return ErrCommuteNotFound
 	}
 
+	commute.Version++
 	return nil
 }
 
@@ -234,6 +330,7 @@ func (r *PostgresRepository) Update(ctx context.Context, commute *Commute) error
 func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM commutes WHERE id = $1`
 	_, err := r.pool.Exec(ctx, query, id)
+	database.MarkWrite(ctx)
 	return err
 }
 
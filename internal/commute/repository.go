@@ -26,10 +26,16 @@ type Repository interface {
 	// List retrieves all commutes for a user with pagination.
 	List(ctx context.Context, userID string, opts ListOptions) (*ListResult, error)
 
+	// ListAll retrieves the commutes of all users in ID order, with
+	// pagination, for background jobs.
+	ListAll(ctx context.Context, opts ListOptions) (*ListResult, error)
+
 	// Create creates a new commute.
 	Create(ctx context.Context, commute *Commute) error
 
-	// Update updates an existing commute.
+	// Update updates an existing commute if its stored version equals
+	// commute.Version, then increments commute.Version.
+	// Returns ErrVersionConflict if the stored version differs.
 	Update(ctx context.Context, commute *Commute) error
 
 	// Delete deletes a commute by ID.
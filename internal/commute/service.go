@@ -3,8 +3,11 @@ package commute
 import (
 	"context"
 	"errors"
+	"fmt"
 	"regexp"
+	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
@@ -20,9 +23,15 @@ var (
 const (
 	MaxLabelLength  = 80
 	MaxNotesLength  = 500
+	MaxExceptions   = 366
+	MaxWaypoints    = 5
 	DefaultTimezone = "Europe/Amsterdam"
 )
 
+// upcomingLookaheadDays bounds how far ahead UpcomingArrivals searches, so
+// commutes that rarely run still return promptly.
+const upcomingLookaheadDays = 56
+
 // dayNames maps ISO weekday numbers (1=Monday, 7=Sunday) to day names.
 var dayNames = map[int]string{
 	1: "Monday",
@@ -39,12 +48,19 @@ var timeHHMMRegex = regexp.MustCompile(`^([01]?\d|2[0-3]):[0-5]\d$`)
 
 // Service provides commute operations.
 type Service struct {
-	repo Repository
+	repo            Repository
+	resolveTimezone TimezoneResolver
+	now             func() time.Time
 }
 
-// NewService creates a new commute service.
+// NewService creates a new commute service. Commutes created without a
+// timezone take it from their origin using LookupTimezone.
 func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+	return &Service{
+		repo:            repo,
+		resolveTimezone: LookupTimezone,
+		now:             time.Now,
+	}
 }
 
 // List retrieves all commutes for a user.
@@ -87,6 +103,17 @@ func (s *Service) Get(ctx context.Context, userID, commuteID string) (*models.Co
 	return &result, nil
 }
 
+// Upcoming retrieves a user's commute with its next n scheduled arrivals.
+func (s *Service) Upcoming(ctx context.Context, userID, commuteID string, n int) (*models.Commute, []time.Time, error) {
+	commute, err := s.repo.GetByUserAndID(ctx, userID, commuteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := s.toAPICommute(commute)
+	return &result, commute.UpcomingArrivals(s.now(), n), nil
+}
+
 // Create creates a new commute for a user.
 func (s *Service) Create(ctx context.Context, userID string, input *models.CommuteCreateRequest) (*models.Commute, error) {
 	// Validate input
@@ -94,13 +121,13 @@ func (s *Service) Create(ctx context.Context, userID string, input *models.Commu
 		return nil, &ValidationError{Errors: fieldErrors}
 	}
 
-	// Determine timezone (use default if not provided)
-	timezone := DefaultTimezone
+	// Determine timezone (resolve from the origin if not provided)
+	timezone := s.originTimezone(input.Origin.Point)
 	if input.Timezone != nil && *input.Timezone != "" {
 		timezone = *input.Timezone
 	}
 
-	now := time.Now()
+	now := s.now()
 	commuteID := "cmt_" + uuid.New().String()[:22]
 
 	commute := &Commute{
@@ -115,10 +142,14 @@ func (s *Service) Create(ctx context.Context, userID string, input *models.Commu
 			Point:   Point{Lat: input.Destination.Point.Lat, Lon: input.Destination.Point.Lon},
 			Geohash: input.Destination.Geohash,
 		},
+		Waypoints:                 toDomainWaypoints(input.Waypoints),
 		DaysOfWeek:                input.DaysOfWeek,
 		PreferredArrivalTimeLocal: input.PreferredArrivalTimeLocal,
 		Timezone:                  timezone,
+		Exceptions:                normalizeExceptions(input.Exceptions),
+		SkipPublicHolidays:        input.SkipPublicHolidays,
 		Notes:                     input.Notes,
+		Version:                   1,
 		CreatedAt:                 now,
 		UpdatedAt:                 now,
 	}
@@ -131,8 +162,96 @@ func (s *Service) Create(ctx context.Context, userID string, input *models.Commu
 	return &result, nil
 }
 
-// Update updates an existing commute for a user.
-func (s *Service) Update(ctx context.Context, userID, commuteID string, input *models.CommuteUpdateRequest) (*models.Commute, error) {
+// CloneLabelSuffix is appended to the label of a cloned commute.
+const CloneLabelSuffix = " (copy)"
+
+// Clone copies an existing commute into a new one for the same user. The
+// clone is labelled with CloneLabelSuffix unless overrides sets a label, and
+// any other fields set in overrides replace the source's values. The result
+// is validated like a newly created commute.
+func (s *Service) Clone(ctx context.Context, userID, commuteID string, overrides *models.CommuteUpdateRequest) (*models.Commute, error) {
+	source, err := s.repo.GetByUserAndID(ctx, userID, commuteID)
+	if err != nil {
+		if errors.Is(err, ErrCommuteNotFound) {
+			return nil, ErrCommuteNotFound
+		}
+		return nil, err
+	}
+
+	timezone := source.Timezone
+	input := &models.CommuteCreateRequest{
+		Label: cloneLabel(source.Label),
+		Origin: models.CommuteLocation{
+			Point:   models.Point{Lat: source.Origin.Point.Lat, Lon: source.Origin.Point.Lon},
+			Geohash: source.Origin.Geohash,
+		},
+		Destination: models.CommuteLocation{
+			Point:   models.Point{Lat: source.Destination.Point.Lat, Lon: source.Destination.Point.Lon},
+			Geohash: source.Destination.Geohash,
+		},
+		Waypoints:                 toAPIWaypoints(source.Waypoints),
+		DaysOfWeek:                append([]int(nil), source.DaysOfWeek...),
+		PreferredArrivalTimeLocal: source.PreferredArrivalTimeLocal,
+		Timezone:                  &timezone,
+		Exceptions:                append([]string(nil), source.Exceptions...),
+		SkipPublicHolidays:        source.SkipPublicHolidays,
+	}
+	if source.Notes != nil {
+		notes := *source.Notes
+		input.Notes = &notes
+	}
+
+	if overrides != nil {
+		if overrides.Label != nil {
+			input.Label = *overrides.Label
+		}
+		if overrides.Origin != nil {
+			input.Origin = *overrides.Origin
+		}
+		if overrides.Destination != nil {
+			input.Destination = *overrides.Destination
+		}
+		if overrides.Waypoints != nil {
+			input.Waypoints = overrides.Waypoints
+		}
+		if overrides.DaysOfWeek != nil {
+			input.DaysOfWeek = overrides.DaysOfWeek
+		}
+		if overrides.PreferredArrivalTimeLocal != nil {
+			input.PreferredArrivalTimeLocal = *overrides.PreferredArrivalTimeLocal
+		}
+		if overrides.Timezone != nil {
+			input.Timezone = overrides.Timezone
+		}
+		if overrides.Exceptions != nil {
+			input.Exceptions = overrides.Exceptions
+		}
+		if overrides.SkipPublicHolidays != nil {
+			input.SkipPublicHolidays = *overrides.SkipPublicHolidays
+		}
+		if overrides.Notes != nil {
+			input.Notes = overrides.Notes
+		}
+	}
+
+	return s.Create(ctx, userID, input)
+}
+
+// cloneLabel appends CloneLabelSuffix to label, shortening the original so
+// the result stays within MaxLabelLength.
+func cloneLabel(label string) string {
+	maxBase := MaxLabelLength - len(CloneLabelSuffix)
+	for len(label) > maxBase {
+		_, size := utf8.DecodeLastRuneInString(label)
+		label = label[:len(label)-size]
+	}
+	return label + CloneLabelSuffix
+}
+
+// Update updates an existing commute for a user. If expectedVersion is
+// non-zero, the update only applies when the stored version matches;
+// otherwise ErrVersionConflict is returned.
+func (s *Service) Update(ctx context.Context, userID, commuteID string, expectedVersion int64, input *models.CommuteUpdateRequest) (*models.Commute, error) {
 	// Get existing commute
 	commute, err := s.repo.GetByUserAndID(ctx, userID, commuteID)
 	if err != nil {
@@ -141,6 +260,9 @@ func (s *Service) Update(ctx context.Context, userID, commuteID string, input *m
 		}
 		return nil, err
 	}
+	if expectedVersion != 0 && commute.Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
 
 	// Validate input
 	if fieldErrors := s.validateUpdateInput(input); len(fieldErrors) > 0 {
@@ -163,6 +285,9 @@ func (s *Service) Update(ctx context.Context, userID, commuteID string, input *m
 			Geohash: input.Destination.Geohash,
 		}
 	}
+	if input.Waypoints != nil {
+		commute.Waypoints = toDomainWaypoints(input.Waypoints)
+	}
 	if input.DaysOfWeek != nil {
 		commute.DaysOfWeek = input.DaysOfWeek
 	}
@@ -172,10 +297,16 @@ func (s *Service) Update(ctx context.Context, userID, commuteID string, input *m
 	if input.Timezone != nil {
 		commute.Timezone = *input.Timezone
 	}
+	if input.Exceptions != nil {
+		commute.Exceptions = normalizeExceptions(input.Exceptions)
+	}
+	if input.SkipPublicHolidays != nil {
+		commute.SkipPublicHolidays = *input.SkipPublicHolidays
+	}
 	if input.Notes != nil {
 		commute.Notes = input.Notes
 	}
-	commute.UpdatedAt = time.Now()
+	commute.UpdatedAt = s.now()
 
 	if err := s.repo.Update(ctx, commute); err != nil {
 		return nil, err
@@ -205,9 +336,9 @@ func (s *Service) validateCreateInput(input *models.CommuteCreateRequest) []mode
 
 	// Validate label
 	if input.Label == "" {
-		errs = append(errs, models.FieldError{Field: "label", Message: "is required"})
+		errs = append(errs, models.FieldError{Field: "label", Message: "is required", Code: models.FieldCodeRequired})
 	} else if len(input.Label) > MaxLabelLength {
-		errs = append(errs, models.FieldError{Field: "label", Message: "must be at most 80 characters"})
+		errs = append(errs, models.FieldError{Field: "label", Message: "must be at most 80 characters", Code: models.FieldCodeTooLong})
 	}
 
 	// Validate origin coordinates
@@ -216,26 +347,32 @@ func (s *Service) validateCreateInput(input *models.CommuteCreateRequest) []mode
 	// Validate destination coordinates
 	errs = append(errs, s.validateLocation(&input.Destination, "destination")...)
 
+	// Validate waypoints (optional)
+	errs = append(errs, s.validateWaypoints(input.Waypoints)...)
+
 	// Validate days of week
 	errs = append(errs, s.validateDaysOfWeek(input.DaysOfWeek, true)...)
 
 	// Validate preferred arrival time
 	if input.PreferredArrivalTimeLocal == "" {
-		errs = append(errs, models.FieldError{Field: "preferredArrivalTimeLocal", Message: "is required"})
+		errs = append(errs, models.FieldError{Field: "preferredArrivalTimeLocal", Message: "is required", Code: models.FieldCodeRequired})
 	} else if !timeHHMMRegex.MatchString(input.PreferredArrivalTimeLocal) {
-		errs = append(errs, models.FieldError{Field: "preferredArrivalTimeLocal", Message: "must be in HH:mm format"})
+		errs = append(errs, models.FieldError{Field: "preferredArrivalTimeLocal", Message: "must be in HH:mm format", Code: models.FieldCodeInvalidFormat})
 	}
 
 	// Validate timezone (optional)
 	if input.Timezone != nil && *input.Timezone != "" {
 		if _, err := time.LoadLocation(*input.Timezone); err != nil {
-			errs = append(errs, models.FieldError{Field: "timezone", Message: "must be a valid IANA timezone identifier"})
+			errs = append(errs, models.FieldError{Field: "timezone", Message: "must be a valid IANA timezone identifier", Code: models.FieldCodeInvalidTimezone})
 		}
 	}
 
+	// Validate exceptions (optional)
+	errs = append(errs, s.validateExceptions(input.Exceptions)...)
+
 	// Validate notes (optional)
 	if input.Notes != nil && len(*input.Notes) > MaxNotesLength {
-		errs = append(errs, models.FieldError{Field: "notes", Message: "must be at most 500 characters"})
+		errs = append(errs, models.FieldError{Field: "notes", Message: "must be at most 500 characters", Code: models.FieldCodeTooLong})
 	}
 
 	return errs
@@ -260,6 +397,9 @@ func (s *Service) validateUpdateInput(input *models.CommuteUpdateRequest) []mode
 		errs = append(errs, s.validateLocation(input.Destination, "destination")...)
 	}
 
+	// Validate waypoints (optional)
+	errs = append(errs, s.validateWaypoints(input.Waypoints)...)
+
 	// Validate days of week (optional)
 	if input.DaysOfWeek != nil {
 		errs = append(errs, s.validateDaysOfWeek(input.DaysOfWeek, false)...)
@@ -273,25 +413,56 @@ func (s *Service) validateUpdateInput(input *models.CommuteUpdateRequest) []mode
 	// Validate timezone (optional)
 	if input.Timezone != nil && *input.Timezone != "" {
 		if _, err := time.LoadLocation(*input.Timezone); err != nil {
-			errs = append(errs, models.FieldError{Field: "timezone", Message: "must be a valid IANA timezone identifier"})
+			errs = append(errs, models.FieldError{Field: "timezone", Message: "must be a valid IANA timezone identifier", Code: models.FieldCodeInvalidTimezone})
 		}
 	}
 
+	// Validate exceptions (optional)
+	errs = append(errs, s.validateExceptions(input.Exceptions)...)
+
 	// Validate notes (optional)
 	if input.Notes != nil && len(*input.Notes) > MaxNotesLength {
-		errs = append(errs, models.FieldError{Field: "notes", Message: "must be at most 500 characters"})
+		errs = append(errs, models.FieldError{Field: "notes", Message: "must be at most 500 characters", Code: models.FieldCodeTooLong})
 	}
 
 	return errs
 }
 
+// validateExceptions validates exception dates.
+func (s *Service) validateExceptions(dates []string) []models.FieldError {
+	if len(dates) > MaxExceptions {
+		return []models.FieldError{{Field: "exceptions", Message: "must contain at most 366 dates", Code: models.FieldCodeTooMany}}
+	}
+	for _, d := range dates {
+		if _, err := time.Parse(dateLayout, d); err != nil {
+			return []models.FieldError{{Field: "exceptions", Message: "must contain dates in YYYY-MM-DD format", Code: models.FieldCodeInvalidFormat}}
+		}
+	}
+	return nil
+}
+
+// normalizeExceptions sorts and deduplicates exception dates. It never
+// returns nil, so an empty list is stored rather than NULL.
+func normalizeExceptions(dates []string) []string {
+	result := make([]string, 0, len(dates))
+	seen := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		if !seen[d] {
+			seen[d] = true
+			result = append(result, d)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 // validateOptionalLabel validates an optional label field (for updates).
 func (s *Service) validateOptionalLabel(label string) []models.FieldError {
 	if label == "" {
-		return []models.FieldError{{Field: "label", Message: "cannot be empty"}}
+		return []models.FieldError{{Field: "label", Message: "cannot be empty", Code: models.FieldCodeRequired}}
 	}
 	if len(label) > MaxLabelLength {
-		return []models.FieldError{{Field: "label", Message: "must be at most 80 characters"}}
+		return []models.FieldError{{Field: "label", Message: "must be at most 80 characters", Code: models.FieldCodeTooLong}}
 	}
 	return nil
 }
@@ -300,13 +471,13 @@ func (s *Service) validateOptionalLabel(label string) []models.FieldError {
 func (s *Service) validateDaysOfWeek(days []int, required bool) []models.FieldError {
 	if len(days) == 0 {
 		if required {
-			return []models.FieldError{{Field: "daysOfWeek", Message: "is required"}}
+			return []models.FieldError{{Field: "daysOfWeek", Message: "is required", Code: models.FieldCodeRequired}}
 		}
-		return []models.FieldError{{Field: "daysOfWeek", Message: "cannot be empty"}}
+		return []models.FieldError{{Field: "daysOfWeek", Message: "cannot be empty", Code: models.FieldCodeRequired}}
 	}
 	for _, day := range days {
 		if day < 1 || day > 7 {
-			return []models.FieldError{{Field: "daysOfWeek", Message: "must contain values between 1 and 7"}}
+			return []models.FieldError{{Field: "daysOfWeek", Message: "must contain values between 1 and 7", Code: models.FieldCodeOutOfRange}}
 		}
 	}
 	return nil
@@ -315,10 +486,10 @@ func (s *Service) validateDaysOfWeek(days []int, required bool) []models.FieldEr
 // validateOptionalArrivalTime validates an optional arrival time (for updates).
 func (s *Service) validateOptionalArrivalTime(t string) []models.FieldError {
 	if t == "" {
-		return []models.FieldError{{Field: "preferredArrivalTimeLocal", Message: "cannot be empty"}}
+		return []models.FieldError{{Field: "preferredArrivalTimeLocal", Message: "cannot be empty", Code: models.FieldCodeRequired}}
 	}
 	if !timeHHMMRegex.MatchString(t) {
-		return []models.FieldError{{Field: "preferredArrivalTimeLocal", Message: "must be in HH:mm format"}}
+		return []models.FieldError{{Field: "preferredArrivalTimeLocal", Message: "must be in HH:mm format", Code: models.FieldCodeInvalidFormat}}
 	}
 	return nil
 }
@@ -331,6 +502,7 @@ func (s *Service) validateLocation(loc *models.CommuteLocation, prefix string) [
 		errs = append(errs, models.FieldError{
 			Field:   prefix + ".point.lat",
 			Message: "must be between -90 and 90",
+			Code:    models.FieldCodeOutOfRange,
 		})
 	}
 
@@ -338,12 +510,44 @@ func (s *Service) validateLocation(loc *models.CommuteLocation, prefix string) [
 		errs = append(errs, models.FieldError{
 			Field:   prefix + ".point.lon",
 			Message: "must be between -180 and 180",
+			Code:    models.FieldCodeOutOfRange,
 		})
 	}
 
 	return errs
 }
 
+// validateWaypoints validates the waypoint count and coordinates.
+func (s *Service) validateWaypoints(waypoints []models.Point) []models.FieldError {
+	if len(waypoints) > MaxWaypoints {
+		return []models.FieldError{{Field: "waypoints", Message: "must contain at most 5 waypoints", Code: models.FieldCodeTooMany}}
+	}
+	var errs []models.FieldError
+	for i, p := range waypoints {
+		errs = append(errs, s.validateLocation(&models.CommuteLocation{Point: p}, fmt.Sprintf("waypoints[%d]", i))...)
+	}
+	return errs
+}
+
+// toDomainWaypoints converts API waypoints to domain points. It never
+// returns nil, so an empty list is stored rather than NULL.
+func toDomainWaypoints(points []models.Point) []Point {
+	result := make([]Point, len(points))
+	for i, p := range points {
+		result[i] = Point{Lat: p.Lat, Lon: p.Lon}
+	}
+	return result
+}
+
+// toAPIWaypoints converts domain waypoints to API points.
+func toAPIWaypoints(points []Point) []models.Point {
+	result := make([]models.Point, len(points))
+	for i, p := range points {
+		result[i] = models.Point{Lat: p.Lat, Lon: p.Lon}
+	}
+	return result
+}
+
 // toAPICommute converts a domain Commute to an API Commute.
 func (s *Service) toAPICommute(c *Commute) models.Commute {
 	schedule := s.buildSchedule(c)
@@ -359,8 +563,10 @@ func (s *Service) toAPICommute(c *Commute) models.Commute {
 			Point:   models.Point{Lat: c.Destination.Point.Lat, Lon: c.Destination.Point.Lon},
 			Geohash: c.Destination.Geohash,
 		},
+		Waypoints: toAPIWaypoints(c.Waypoints),
 		Schedule:  schedule,
 		Notes:     c.Notes,
+		Version:   c.Version,
 		CreatedAt: models.Timestamp(c.CreatedAt),
 		UpdatedAt: models.Timestamp(c.UpdatedAt),
 	}
@@ -376,11 +582,18 @@ func (s *Service) buildSchedule(c *Commute) models.CommuteSchedule {
 		}
 	}
 
+	exceptions := c.Exceptions
+	if exceptions == nil {
+		exceptions = []string{}
+	}
+
 	schedule := models.CommuteSchedule{
-		DaysOfWeek:  c.DaysOfWeek,
-		DayNames:    names,
-		ArrivalTime: c.PreferredArrivalTimeLocal,
-		Timezone:    c.Timezone,
+		DaysOfWeek:         c.DaysOfWeek,
+		DayNames:           names,
+		ArrivalTime:        c.PreferredArrivalTimeLocal,
+		Timezone:           c.Timezone,
+		Exceptions:         exceptions,
+		SkipPublicHolidays: c.SkipPublicHolidays,
 	}
 
 	// Load timezone for calculations
@@ -391,9 +604,9 @@ func (s *Service) buildSchedule(c *Commute) models.CommuteSchedule {
 	}
 
 	// Calculate IsActiveToday and NextOccurrence
-	now := time.Now().In(loc)
+	now := s.now().In(loc)
 	todayWeekday := isoWeekday(now.Weekday())
-	schedule.IsActiveToday = containsDay(c.DaysOfWeek, todayWeekday)
+	schedule.IsActiveToday = containsDay(c.DaysOfWeek, todayWeekday) && !skipsDate(c, now)
 
 	// Find next occurrence within 7 days
 	if next := s.findNextOccurrence(c, loc, now); next != nil {
@@ -404,6 +617,19 @@ func (s *Service) buildSchedule(c *Commute) models.CommuteSchedule {
 	return schedule
 }
 
+// originTimezone returns the timezone at an origin, or DefaultTimezone if the
+// lookup fails or returns a zone this host cannot load.
+func (s *Service) originTimezone(origin models.Point) string {
+	tz, ok := s.resolveTimezone(origin.Lat, origin.Lon)
+	if !ok {
+		return DefaultTimezone
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return DefaultTimezone
+	}
+	return tz
+}
+
 // findNextOccurrence finds the next scheduled commute time within 7 days.
 func (s *Service) findNextOccurrence(c *Commute, loc *time.Location, now time.Time) *time.Time {
 	if len(c.DaysOfWeek) == 0 {
@@ -422,12 +648,9 @@ func (s *Service) findNextOccurrence(c *Commute, loc *time.Location, now time.Ti
 		checkDate := now.AddDate(0, 0, i)
 		checkWeekday := isoWeekday(checkDate.Weekday())
 
-		if containsDay(c.DaysOfWeek, checkWeekday) {
+		if containsDay(c.DaysOfWeek, checkWeekday) && !skipsDate(c, checkDate) {
 			// Create the candidate time on this day
-			candidate := time.Date(
-				checkDate.Year(), checkDate.Month(), checkDate.Day(),
-				hour, minute, 0, 0, loc,
-			)
+			candidate := localArrival(checkDate, hour, minute, loc)
 
 			// If it's today but the time has passed, skip to next occurrence
 			if i == 0 && candidate.Before(now) {
@@ -441,6 +664,99 @@ func (s *Service) findNextOccurrence(c *Commute, loc *time.Location, now time.Ti
 	return nil
 }
 
+// ArrivalOn returns the commute's scheduled arrival on date's calendar day,
+// in the commute's timezone, and false if the commute does not run that day.
+func (c *Commute) ArrivalOn(date time.Time) (time.Time, bool) {
+	parts := parseTimeHHMM(c.PreferredArrivalTimeLocal)
+	if parts == nil {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	arrival := localArrival(date, parts[0], parts[1], loc)
+	if !containsDay(c.DaysOfWeek, isoWeekday(arrival.Weekday())) || skipsDate(c, arrival) {
+		return time.Time{}, false
+	}
+	return arrival, true
+}
+
+// UpcomingArrivals returns the commute's next n scheduled arrivals after
+// now, in date order, skipping days the commute does not run. Today's
+// arrival is included if it has not passed yet.
+func (c *Commute) UpcomingArrivals(now time.Time, n int) []time.Time {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var arrivals []time.Time
+	for i := 0; i < upcomingLookaheadDays && len(arrivals) < n; i++ {
+		arrival, ok := c.ArrivalOn(today.AddDate(0, 0, i))
+		if ok && !arrival.Before(now) {
+			arrivals = append(arrivals, arrival)
+		}
+	}
+	return arrivals
+}
+
+// localArrival returns hour:minute on date's calendar day in loc, handling
+// DST transitions:
+//   - Spring forward: a wall time in the gap (e.g. 02:30 in Europe/Amsterdam
+//     on the last Sunday of March) does not exist, so the next valid instant
+//     is used, which is the moment clocks jump forward (03:00).
+//   - Fall back: a wall time that occurs twice (e.g. 02:30 on the last Sunday
+//     of October) resolves to its first occurrence, before clocks go back.
+func localArrival(date time.Time, hour, minute int, loc *time.Location) time.Time {
+	t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc)
+
+	// time.Date normalizes gap times using the offset before the transition,
+	// which lands after the gap; the zone it lands in starts at the transition
+	if t.Hour() != hour || t.Minute() != minute {
+		if start, _ := t.ZoneBounds(); !start.IsZero() {
+			return start
+		}
+		return t
+	}
+
+	// If the previous zone had a larger offset, the same wall time may also
+	// have occurred in it, just before clocks went back
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return t
+	}
+	_, prevOffset := start.Add(-time.Nanosecond).Zone()
+	_, offset := t.Zone()
+	if diff := prevOffset - offset; diff > 0 {
+		earlier := t.Add(-time.Duration(diff) * time.Second)
+		if earlier.Hour() == hour && earlier.Minute() == minute && earlier.Day() == t.Day() {
+			return earlier
+		}
+	}
+	return t
+}
+
+// skipsDate reports whether the commute does not run on t's calendar date
+// because of an exception or a public holiday.
+func skipsDate(c *Commute, t time.Time) bool {
+	if c.SkipPublicHolidays && IsDutchPublicHoliday(t) {
+		return true
+	}
+	day := t.Format(dateLayout)
+	for _, d := range c.Exceptions {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
 // isoWeekday converts Go's time.Weekday (0=Sunday) to ISO weekday (1=Monday, 7=Sunday).
 func isoWeekday(w time.Weekday) int {
 	if w == time.Sunday {
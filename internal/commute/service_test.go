@@ -0,0 +1,336 @@
+package commute
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+func TestFindNextOccurrence_DST(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	// Clocks go forward at 02:00 on Sunday 29 March 2026 and back at 03:00
+	// on Sunday 25 October 2026
+	spring := time.Date(2026, time.March, 28, 12, 0, 0, 0, amsterdam)
+	autumn := time.Date(2026, time.October, 24, 12, 0, 0, 0, amsterdam)
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		days    []int
+		arrival string
+		want    string
+	}{
+		{
+			name:    "spring forward, regular time",
+			now:     spring,
+			days:    []int{7},
+			arrival: "09:00",
+			want:    "2026-03-29T09:00:00+02:00",
+		},
+		{
+			name:    "spring forward, time in gap moves to transition",
+			now:     spring,
+			days:    []int{7},
+			arrival: "02:30",
+			want:    "2026-03-29T03:00:00+02:00",
+		},
+		{
+			name:    "spring forward, gap start",
+			now:     spring,
+			days:    []int{7},
+			arrival: "02:00",
+			want:    "2026-03-29T03:00:00+02:00",
+		},
+		{
+			name:    "spring forward, later days are unaffected",
+			now:     spring,
+			days:    []int{1},
+			arrival: "02:30",
+			want:    "2026-03-30T02:30:00+02:00",
+		},
+		{
+			name:    "fall back, regular time",
+			now:     autumn,
+			days:    []int{7},
+			arrival: "09:00",
+			want:    "2026-10-25T09:00:00+01:00",
+		},
+		{
+			name:    "fall back, repeated time uses first occurrence",
+			now:     autumn,
+			days:    []int{7},
+			arrival: "02:30",
+			want:    "2026-10-25T02:30:00+02:00",
+		},
+		{
+			name:    "fall back, end of repeated hour",
+			now:     autumn,
+			days:    []int{7},
+			arrival: "03:00",
+			want:    "2026-10-25T03:00:00+01:00",
+		},
+	}
+
+	s := NewService(NewInMemoryRepository())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commute{DaysOfWeek: tt.days, PreferredArrivalTimeLocal: tt.arrival}
+			got := s.findNextOccurrence(c, amsterdam, tt.now)
+			if got == nil {
+				t.Fatal("findNextOccurrence() = nil")
+			}
+			if formatted := got.Format(time.RFC3339); formatted != tt.want {
+				t.Errorf("findNextOccurrence() = %s, want %s", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchedule_Exceptions(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	// Monday 27 April 2026 is Koningsdag
+	koningsdag := time.Date(2026, time.April, 27, 7, 0, 0, 0, amsterdam)
+
+	tests := []struct {
+		name       string
+		skip       bool
+		exceptions []string
+		wantActive bool
+		wantNext   string
+	}{
+		{
+			name:       "runs on holidays by default",
+			wantActive: true,
+			wantNext:   "2026-04-27T09:00:00+02:00",
+		},
+		{
+			name:       "skips Koningsdag",
+			skip:       true,
+			wantActive: false,
+			wantNext:   "2026-04-28T09:00:00+02:00",
+		},
+		{
+			name:       "skips exception dates",
+			exceptions: []string{"2026-04-27", "2026-04-28"},
+			wantActive: false,
+			wantNext:   "2026-04-29T09:00:00+02:00",
+		},
+	}
+
+	s := NewService(NewInMemoryRepository())
+	s.now = func() time.Time { return koningsdag }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commute{
+				DaysOfWeek:                []int{1, 2, 3, 4, 5},
+				PreferredArrivalTimeLocal: "09:00",
+				Timezone:                  "Europe/Amsterdam",
+				Exceptions:                tt.exceptions,
+				SkipPublicHolidays:        tt.skip,
+			}
+			schedule := s.buildSchedule(c)
+			if schedule.IsActiveToday != tt.wantActive {
+				t.Errorf("IsActiveToday = %v, want %v", schedule.IsActiveToday, tt.wantActive)
+			}
+			if schedule.NextOccurrence == nil || *schedule.NextOccurrence != tt.wantNext {
+				t.Errorf("NextOccurrence = %v, want %s", schedule.NextOccurrence, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestCommute_ArrivalOn(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	c := &Commute{
+		DaysOfWeek:                []int{1, 2, 3, 4, 5},
+		PreferredArrivalTimeLocal: "08:45",
+		Timezone:                  "Europe/Amsterdam",
+		Exceptions:                []string{"2026-04-28"},
+		SkipPublicHolidays:        true,
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{name: "weekday", date: time.Date(2026, time.April, 29, 0, 0, 0, 0, amsterdam), want: "2026-04-29T08:45:00+02:00"},
+		{name: "weekend", date: time.Date(2026, time.April, 25, 0, 0, 0, 0, amsterdam)},
+		{name: "public holiday", date: time.Date(2026, time.April, 27, 0, 0, 0, 0, amsterdam)},
+		{name: "exception", date: time.Date(2026, time.April, 28, 0, 0, 0, 0, amsterdam)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arrival, ok := c.ArrivalOn(tt.date)
+			if ok != (tt.want != "") {
+				t.Fatalf("ArrivalOn() ok = %v, want %v", ok, tt.want != "")
+			}
+			if ok && arrival.Format(time.RFC3339) != tt.want {
+				t.Errorf("ArrivalOn() = %s, want %s", arrival.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestInMemoryRepository_ListAll(t *testing.T) {
+	repo := NewInMemoryRepository()
+	for _, c := range []*Commute{
+		{ID: "cmt_c", UserID: "usr_1"},
+		{ID: "cmt_a", UserID: "usr_2"},
+		{ID: "cmt_b", UserID: "usr_1"},
+	} {
+		if err := repo.Create(context.Background(), c); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	var ids []string
+	opts := ListOptions{Limit: 2}
+	for {
+		page, err := repo.ListAll(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ListAll() error = %v", err)
+		}
+		for _, c := range page.Items {
+			ids = append(ids, c.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	if got := strings.Join(ids, ","); got != "cmt_a,cmt_b,cmt_c" {
+		t.Errorf("ListAll() IDs = %s, want cmt_a,cmt_b,cmt_c", got)
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestValidateCreateInput_FieldCodes(t *testing.T) {
+	s := NewService(NewInMemoryRepository())
+
+	tests := []struct {
+		name      string
+		modify    func(*models.CommuteCreateRequest)
+		wantField string
+		wantCode  string
+	}{
+		{"label missing", func(in *models.CommuteCreateRequest) { in.Label = "" }, "label", models.FieldCodeRequired},
+		{"label too long", func(in *models.CommuteCreateRequest) { in.Label = strings.Repeat("a", MaxLabelLength+1) }, "label", models.FieldCodeTooLong},
+		{"origin lat", func(in *models.CommuteCreateRequest) { in.Origin.Point.Lat = 91 }, "origin.point.lat", models.FieldCodeOutOfRange},
+		{"origin lon", func(in *models.CommuteCreateRequest) { in.Origin.Point.Lon = -181 }, "origin.point.lon", models.FieldCodeOutOfRange},
+		{"destination lat", func(in *models.CommuteCreateRequest) { in.Destination.Point.Lat = -91 }, "destination.point.lat", models.FieldCodeOutOfRange},
+		{"destination lon", func(in *models.CommuteCreateRequest) { in.Destination.Point.Lon = 181 }, "destination.point.lon", models.FieldCodeOutOfRange},
+		{"too many waypoints", func(in *models.CommuteCreateRequest) { in.Waypoints = make([]models.Point, MaxWaypoints+1) }, "waypoints", models.FieldCodeTooMany},
+		{"waypoint lat", func(in *models.CommuteCreateRequest) { in.Waypoints = []models.Point{{Lat: 95}} }, "waypoints[0].point.lat", models.FieldCodeOutOfRange},
+		{"days missing", func(in *models.CommuteCreateRequest) { in.DaysOfWeek = nil }, "daysOfWeek", models.FieldCodeRequired},
+		{"day out of range", func(in *models.CommuteCreateRequest) { in.DaysOfWeek = []int{0} }, "daysOfWeek", models.FieldCodeOutOfRange},
+		{"arrival time missing", func(in *models.CommuteCreateRequest) { in.PreferredArrivalTimeLocal = "" }, "preferredArrivalTimeLocal", models.FieldCodeRequired},
+		{"arrival time format", func(in *models.CommuteCreateRequest) { in.PreferredArrivalTimeLocal = "9am" }, "preferredArrivalTimeLocal", models.FieldCodeInvalidFormat},
+		{"timezone", func(in *models.CommuteCreateRequest) { in.Timezone = strPtr("Mars/Olympus") }, "timezone", models.FieldCodeInvalidTimezone},
+		{"too many exceptions", func(in *models.CommuteCreateRequest) { in.Exceptions = make([]string, MaxExceptions+1) }, "exceptions", models.FieldCodeTooMany},
+		{"exception format", func(in *models.CommuteCreateRequest) { in.Exceptions = []string{"27-04-2026"} }, "exceptions", models.FieldCodeInvalidFormat},
+		{"notes too long", func(in *models.CommuteCreateRequest) { in.Notes = strPtr(strings.Repeat("a", MaxNotesLength+1)) }, "notes", models.FieldCodeTooLong},
+	}
+
+	if errs := s.validateCreateInput(mondayCommute(52.37, 4.90)); len(errs) != 0 {
+		t.Fatalf("valid input: unexpected errors %+v", errs)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := mondayCommute(52.37, 4.90)
+			tt.modify(input)
+			assertFieldCode(t, s.validateCreateInput(input), tt.wantField, tt.wantCode)
+		})
+	}
+}
+
+func TestValidateUpdateInput_FieldCodes(t *testing.T) {
+	s := NewService(NewInMemoryRepository())
+
+	tests := []struct {
+		name      string
+		input     models.CommuteUpdateRequest
+		wantField string
+		wantCode  string
+	}{
+		{"label empty", models.CommuteUpdateRequest{Label: strPtr("")}, "label", models.FieldCodeRequired},
+		{"label too long", models.CommuteUpdateRequest{Label: strPtr(strings.Repeat("a", MaxLabelLength+1))}, "label", models.FieldCodeTooLong},
+		{"origin lat", models.CommuteUpdateRequest{Origin: &models.CommuteLocation{Point: models.Point{Lat: 91}}}, "origin.point.lat", models.FieldCodeOutOfRange},
+		{"destination lon", models.CommuteUpdateRequest{Destination: &models.CommuteLocation{Point: models.Point{Lon: 181}}}, "destination.point.lon", models.FieldCodeOutOfRange},
+		{"too many waypoints", models.CommuteUpdateRequest{Waypoints: make([]models.Point, MaxWaypoints+1)}, "waypoints", models.FieldCodeTooMany},
+		{"days empty", models.CommuteUpdateRequest{DaysOfWeek: []int{}}, "daysOfWeek", models.FieldCodeRequired},
+		{"day out of range", models.CommuteUpdateRequest{DaysOfWeek: []int{8}}, "daysOfWeek", models.FieldCodeOutOfRange},
+		{"arrival time empty", models.CommuteUpdateRequest{PreferredArrivalTimeLocal: strPtr("")}, "preferredArrivalTimeLocal", models.FieldCodeRequired},
+		{"arrival time format", models.CommuteUpdateRequest{PreferredArrivalTimeLocal: strPtr("25:00")}, "preferredArrivalTimeLocal", models.FieldCodeInvalidFormat},
+		{"timezone", models.CommuteUpdateRequest{Timezone: strPtr("Mars/Olympus")}, "timezone", models.FieldCodeInvalidTimezone},
+		{"exception format", models.CommuteUpdateRequest{Exceptions: []string{"2026-13-01"}}, "exceptions", models.FieldCodeInvalidFormat},
+		{"notes too long", models.CommuteUpdateRequest{Notes: strPtr(strings.Repeat("a", MaxNotesLength+1))}, "notes", models.FieldCodeTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFieldCode(t, s.validateUpdateInput(&tt.input), tt.wantField, tt.wantCode)
+		})
+	}
+}
+
+// assertFieldCode checks errs contains exactly one error, for field with code.
+func assertFieldCode(t *testing.T, errs []models.FieldError, field, code string) {
+	t.Helper()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors %+v, want 1", len(errs), errs)
+	}
+	if errs[0].Field != field || errs[0].Code != code {
+		t.Errorf("got %s/%s, want %s/%s", errs[0].Field, errs[0].Code, field, code)
+	}
+	if errs[0].Message == "" {
+		t.Error("Message is empty")
+	}
+}
+
+func TestCommute_UpcomingArrivals(t *testing.T) {
+	c := &Commute{
+		DaysOfWeek:                []int{1, 2, 3, 4, 5},
+		PreferredArrivalTimeLocal: "08:45",
+		Timezone:                  "Europe/Amsterdam",
+		Exceptions:                []string{"2026-04-29"},
+		SkipPublicHolidays:        true,
+	}
+
+	// Friday 24 April 2026, after the day's arrival has passed
+	now := time.Date(2026, time.April, 24, 9, 0, 0, 0, time.UTC)
+
+	got := c.UpcomingArrivals(now, 3)
+	want := []string{
+		"2026-04-28T08:45:00+02:00", // Weekend and King's Day skipped
+		"2026-04-30T08:45:00+02:00", // Exception skipped
+		"2026-05-01T08:45:00+02:00",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("UpcomingArrivals() returned %d arrivals, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Format(time.RFC3339) != want[i] {
+			t.Errorf("UpcomingArrivals()[%d] = %s, want %s", i, got[i].Format(time.RFC3339), want[i])
+		}
+	}
+}
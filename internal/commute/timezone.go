@@ -0,0 +1,59 @@
+package commute
+
+// TimezoneResolver returns the IANA timezone at a coordinate. ok is false if
+// the coordinate is not covered.
+type TimezoneResolver func(lat, lon float64) (timezone string, ok bool)
+
+// tzRegion is a bounding box mapped to a timezone.
+type tzRegion struct {
+	timezone       string
+	minLat, minLon float64
+	maxLat, maxLon float64
+}
+
+// tzRegions is a coarse, bundled timezone boundary table. Boxes are checked
+// in order, so smaller regions come before the larger ones that overlap
+// them. Near borders a point may resolve to a neighbouring zone; within the
+// EU those share the same offsets and DST rules, so schedules are unaffected.
+var tzRegions = []tzRegion{
+	// Benelux
+	{"Europe/Luxembourg", 49.44, 5.73, 50.19, 6.53},
+	{"Europe/Brussels", 49.49, 2.54, 51.30, 6.41},
+	{"Europe/Amsterdam", 50.75, 3.35, 53.56, 7.23},
+
+	// Central Europe
+	{"Europe/Zurich", 45.82, 5.96, 47.81, 10.49},
+	{"Europe/Copenhagen", 54.56, 8.07, 57.75, 15.20},
+	{"Europe/Prague", 48.55, 12.09, 51.06, 18.86},
+	{"Europe/Berlin", 47.27, 5.86, 55.06, 15.04},
+	{"Europe/Vienna", 46.37, 9.53, 49.02, 17.16},
+	{"Europe/Warsaw", 49.00, 14.12, 54.84, 24.15},
+
+	// British Isles; Northern Ireland before the Republic
+	{"Europe/London", 54.02, -8.18, 55.31, -5.43},
+	{"Europe/Dublin", 51.42, -10.48, 55.39, -6.00},
+	{"Europe/London", 49.86, -8.65, 60.86, 1.77},
+
+	// Western and southern Europe; France's Mediterranean coast is split
+	// out so northern Spain is not matched
+	{"Europe/Paris", 43.40, -5.14, 51.09, 8.23},
+	{"Europe/Paris", 42.33, 2.90, 43.40, 7.70},
+	{"Europe/Lisbon", 36.96, -9.50, 42.15, -6.19},
+	{"Europe/Madrid", 35.95, -9.39, 43.79, 4.33},
+	{"Europe/Rome", 36.62, 6.63, 47.09, 18.52},
+
+	// Nordics
+	{"Europe/Stockholm", 55.34, 11.03, 69.06, 24.17},
+	{"Europe/Helsinki", 59.81, 20.55, 70.09, 31.59},
+	{"Europe/Oslo", 57.96, 4.65, 71.19, 31.29},
+}
+
+// LookupTimezone resolves a coordinate using the bundled boundary table.
+func LookupTimezone(lat, lon float64) (string, bool) {
+	for _, r := range tzRegions {
+		if lat >= r.minLat && lat <= r.maxLat && lon >= r.minLon && lon <= r.maxLon {
+			return r.timezone, true
+		}
+	}
+	return "", false
+}
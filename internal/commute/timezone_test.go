@@ -0,0 +1,153 @@
+package commute
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+func TestLookupTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     string
+	}{
+		{"Amsterdam", 52.37, 4.90, "Europe/Amsterdam"},
+		{"Rotterdam", 51.92, 4.48, "Europe/Amsterdam"},
+		{"Brussels", 50.85, 4.35, "Europe/Brussels"},
+		{"Luxembourg", 49.61, 6.13, "Europe/Luxembourg"},
+		{"Berlin", 52.52, 13.40, "Europe/Berlin"},
+		{"Munich", 48.14, 11.58, "Europe/Berlin"},
+		{"Zurich", 47.37, 8.54, "Europe/Zurich"},
+		{"Vienna", 48.21, 16.37, "Europe/Vienna"},
+		{"Prague", 50.08, 14.43, "Europe/Prague"},
+		{"Warsaw", 52.23, 21.01, "Europe/Warsaw"},
+		{"Copenhagen", 55.68, 12.57, "Europe/Copenhagen"},
+		{"London", 51.51, -0.13, "Europe/London"},
+		{"Belfast", 54.60, -5.93, "Europe/London"},
+		{"Dublin", 53.35, -6.26, "Europe/Dublin"},
+		{"Paris", 48.86, 2.35, "Europe/Paris"},
+		{"Marseille", 43.30, 5.37, "Europe/Paris"},
+		{"Bilbao", 43.26, -2.93, "Europe/Madrid"},
+		{"Barcelona", 41.39, 2.17, "Europe/Madrid"},
+		{"Lisbon", 38.72, -9.14, "Europe/Lisbon"},
+		{"Milan", 45.46, 9.19, "Europe/Rome"},
+		{"Rome", 41.90, 12.50, "Europe/Rome"},
+		{"Oslo", 59.91, 10.75, "Europe/Oslo"},
+		{"Stockholm", 59.33, 18.07, "Europe/Stockholm"},
+		{"Helsinki", 60.17, 24.94, "Europe/Helsinki"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupTimezone(tt.lat, tt.lon)
+			if !ok || got != tt.want {
+				t.Errorf("LookupTimezone(%v, %v) = %q, %v; want %q", tt.lat, tt.lon, got, ok, tt.want)
+			}
+		})
+	}
+
+	if tz, ok := LookupTimezone(40.71, -74.01); ok {
+		t.Errorf("LookupTimezone(New York) = %q, want no match", tz)
+	}
+}
+
+func TestLookupTimezone_RegionsLoad(t *testing.T) {
+	for _, r := range tzRegions {
+		if _, err := time.LoadLocation(r.timezone); err != nil {
+			t.Errorf("region timezone %q does not load: %v", r.timezone, err)
+		}
+	}
+}
+
+func newTestService(now time.Time) *Service {
+	s := NewService(NewInMemoryRepository())
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func mondayCommute(lat, lon float64) *models.CommuteCreateRequest {
+	return &models.CommuteCreateRequest{
+		Label:                     "Work",
+		Origin:                    models.CommuteLocation{Point: models.Point{Lat: lat, Lon: lon}},
+		Destination:               models.CommuteLocation{Point: models.Point{Lat: lat + 0.05, Lon: lon + 0.05}},
+		DaysOfWeek:                []int{1},
+		PreferredArrivalTimeLocal: "09:00",
+	}
+}
+
+func TestService_Create_ResolvesTimezoneFromOrigin(t *testing.T) {
+	// Saturday before EU clocks go forward (Sunday 29 March 2026, 01:00 UTC)
+	now := time.Date(2026, time.March, 28, 12, 0, 0, 0, time.UTC)
+	s := newTestService(now)
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		wantTZ   string
+		wantNext string
+	}{
+		// Monday 09:00 falls after the switch to summer time
+		{"London", 51.51, -0.13, "Europe/London", "2026-03-30T09:00:00+01:00"},
+		{"Amsterdam", 52.37, 4.90, "Europe/Amsterdam", "2026-03-30T09:00:00+02:00"},
+		{"Helsinki", 60.17, 24.94, "Europe/Helsinki", "2026-03-30T09:00:00+03:00"},
+		// Not covered by the boundary table
+		{"New York", 40.71, -74.01, DefaultTimezone, "2026-03-30T09:00:00+02:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := s.Create(context.Background(), "usr_1", mondayCommute(tt.lat, tt.lon))
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if c.Schedule.Timezone != tt.wantTZ {
+				t.Errorf("Timezone = %q, want %q", c.Schedule.Timezone, tt.wantTZ)
+			}
+			if c.Schedule.NextOccurrence == nil {
+				t.Fatal("NextOccurrence is nil")
+			}
+			if *c.Schedule.NextOccurrence != tt.wantNext {
+				t.Errorf("NextOccurrence = %s, want %s", *c.Schedule.NextOccurrence, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestService_Create_ExplicitTimezoneWins(t *testing.T) {
+	// US clocks go back on Sunday 1 November 2026; Monday is after the switch
+	now := time.Date(2026, time.October, 31, 12, 0, 0, 0, time.UTC)
+	s := newTestService(now)
+
+	input := mondayCommute(52.37, 4.90)
+	tz := "America/New_York"
+	input.Timezone = &tz
+
+	c, err := s.Create(context.Background(), "usr_1", input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if c.Schedule.Timezone != tz {
+		t.Errorf("Timezone = %q, want %q", c.Schedule.Timezone, tz)
+	}
+	if c.Schedule.NextOccurrence == nil || *c.Schedule.NextOccurrence != "2026-11-02T09:00:00-05:00" {
+		t.Errorf("NextOccurrence = %v, want 2026-11-02T09:00:00-05:00", c.Schedule.NextOccurrence)
+	}
+}
+
+func TestService_Create_FallsBackWhenLookupFails(t *testing.T) {
+	s := newTestService(time.Date(2026, time.March, 28, 12, 0, 0, 0, time.UTC))
+	s.resolveTimezone = func(lat, lon float64) (string, bool) {
+		return "Not/AZone", true
+	}
+
+	c, err := s.Create(context.Background(), "usr_1", mondayCommute(51.51, -0.13))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if c.Schedule.Timezone != DefaultTimezone {
+		t.Errorf("Timezone = %q, want %q", c.Schedule.Timezone, DefaultTimezone)
+	}
+}
@@ -3,7 +3,9 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"time"
@@ -13,23 +15,41 @@ import (
 
 // Config holds database connection configuration.
 type Config struct {
-	Host            string
-	Port            int
-	User            string
-	Password        string
-	Database        string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	SSLMode  string
+
+	// Pool sizing. MinConns connections are kept open even when idle.
+	MaxConns        int
+	MinConns        int
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+
+	// ReadHost is an optional read replica. When set, Open also connects to
+	// it with the same credentials and pool settings. ReadPort defaults to
+	// Port.
+	ReadHost string
+	ReadPort int
 }
 
+// ErrInvalidPoolConfig is returned by Connect when the pool settings are
+// inconsistent.
+var ErrInvalidPoolConfig = errors.New("invalid connection pool configuration")
+
 // ConfigFromEnv creates a Config from environment variables.
+// DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME are still
+// read when the newer DB_MAX_CONNS, DB_MIN_CONNS and DB_MAX_CONN_LIFETIME are
+// not set.
 func ConfigFromEnv() Config {
 	port, _ := strconv.Atoi(getEnvOrDefault("DB_PORT", "5432"))
-	maxOpen, _ := strconv.Atoi(getEnvOrDefault("DB_MAX_OPEN_CONNS", "10"))
-	maxIdle, _ := strconv.Atoi(getEnvOrDefault("DB_MAX_IDLE_CONNS", "5"))
-	lifetime, _ := time.ParseDuration(getEnvOrDefault("DB_CONN_MAX_LIFETIME", "5m"))
+	maxConns, _ := strconv.Atoi(getEnvOrDefault("DB_MAX_CONNS", getEnvOrDefault("DB_MAX_OPEN_CONNS", "10")))
+	minConns, _ := strconv.Atoi(getEnvOrDefault("DB_MIN_CONNS", getEnvOrDefault("DB_MAX_IDLE_CONNS", "2")))
+	lifetime, _ := time.ParseDuration(getEnvOrDefault("DB_MAX_CONN_LIFETIME", getEnvOrDefault("DB_CONN_MAX_LIFETIME", "5m")))
+	idleTime, _ := time.ParseDuration(getEnvOrDefault("DB_MAX_CONN_IDLE_TIME", "30m"))
+	readPort, _ := strconv.Atoi(getEnvOrDefault("DB_READ_PORT", strconv.Itoa(port)))
 
 	return Config{
 		Host:            getEnvOrDefault("DB_HOST", "localhost"),
@@ -38,10 +58,27 @@ func ConfigFromEnv() Config {
 		Password:        getEnvOrDefault("DB_PASSWORD", "localdev"),
 		Database:        getEnvOrDefault("DB_NAME", "breatheroute"),
 		SSLMode:         getEnvOrDefault("DB_SSL_MODE", "disable"),
-		MaxOpenConns:    maxOpen,
-		MaxIdleConns:    maxIdle,
-		ConnMaxLifetime: lifetime,
+		MaxConns:        maxConns,
+		MinConns:        minConns,
+		MaxConnLifetime: lifetime,
+		MaxConnIdleTime: idleTime,
+		ReadHost:        os.Getenv("DB_READ_HOST"),
+		ReadPort:        readPort,
+	}
+}
+
+// Validate checks the pool settings are usable.
+func (c Config) Validate() error {
+	if c.MaxConns < 1 || c.MaxConns > math.MaxInt32 {
+		return fmt.Errorf("%w: max conns must be at least 1, got %d", ErrInvalidPoolConfig, c.MaxConns)
+	}
+	if c.MinConns < 0 || c.MinConns > c.MaxConns {
+		return fmt.Errorf("%w: min conns must be between 0 and max conns (%d), got %d", ErrInvalidPoolConfig, c.MaxConns, c.MinConns)
+	}
+	if c.MaxConnLifetime < 0 || c.MaxConnIdleTime < 0 {
+		return fmt.Errorf("%w: connection lifetime and idle time must not be negative", ErrInvalidPoolConfig)
 	}
+	return nil
 }
 
 // ConnectionString returns the PostgreSQL connection string.
@@ -52,16 +89,39 @@ func (c Config) ConnectionString() string {
 	)
 }
 
+// ReadConfig returns the configuration for the read replica, and false if
+// no replica is configured.
+func (c Config) ReadConfig() (Config, bool) {
+	if c.ReadHost == "" {
+		return Config{}, false
+	}
+	replica := c
+	replica.Host = c.ReadHost
+	if c.ReadPort != 0 {
+		replica.Port = c.ReadPort
+	}
+	replica.ReadHost = ""
+	replica.ReadPort = 0
+	return replica, true
+}
+
 // Connect creates a new database connection pool.
 func Connect(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	poolConfig, err := pgxpool.ParseConfig(cfg.ConnectionString())
 	if err != nil {
 		return nil, fmt.Errorf("parse connection string: %w", err)
 	}
 
-	poolConfig.MaxConns = int32(cfg.MaxOpenConns) //nolint:gosec // MaxOpenConns is bounded by config validation
-	poolConfig.MinConns = int32(cfg.MaxIdleConns) //nolint:gosec // MaxIdleConns is bounded by config validation
-	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolConfig.MaxConns = int32(cfg.MaxConns) //nolint:gosec // MaxConns is bounded by Validate
+	poolConfig.MinConns = int32(cfg.MinConns) //nolint:gosec // MinConns is bounded by Validate
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -0,0 +1,95 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/database"
+)
+
+func TestConfigFromEnv_PoolSettings(t *testing.T) {
+	t.Setenv("DB_MAX_CONNS", "25")
+	t.Setenv("DB_MIN_CONNS", "4")
+	t.Setenv("DB_MAX_CONN_LIFETIME", "1h")
+	t.Setenv("DB_MAX_CONN_IDLE_TIME", "10m")
+
+	cfg := database.ConfigFromEnv()
+
+	assert.Equal(t, 25, cfg.MaxConns)
+	assert.Equal(t, 4, cfg.MinConns)
+	assert.Equal(t, time.Hour, cfg.MaxConnLifetime)
+	assert.Equal(t, 10*time.Minute, cfg.MaxConnIdleTime)
+}
+
+func TestConfigFromEnv_LegacyPoolSettings(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "20")
+	t.Setenv("DB_MAX_IDLE_CONNS", "3")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "15m")
+
+	cfg := database.ConfigFromEnv()
+
+	assert.Equal(t, 20, cfg.MaxConns)
+	assert.Equal(t, 3, cfg.MinConns)
+	assert.Equal(t, 15*time.Minute, cfg.MaxConnLifetime)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := database.Config{MaxConns: 10, MinConns: 2, MaxConnLifetime: 5 * time.Minute}
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*database.Config)
+	}{
+		{"zero max", func(c *database.Config) { c.MaxConns = 0 }},
+		{"negative min", func(c *database.Config) { c.MinConns = -1 }},
+		{"min above max", func(c *database.Config) { c.MinConns = 11 }},
+		{"negative lifetime", func(c *database.Config) { c.MaxConnLifetime = -time.Second }},
+		{"negative idle time", func(c *database.Config) { c.MaxConnIdleTime = -time.Second }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			assert.ErrorIs(t, cfg.Validate(), database.ErrInvalidPoolConfig)
+		})
+	}
+}
+
+func TestConfig_ReadConfig(t *testing.T) {
+	cfg := database.Config{Host: "primary", Port: 5432, Database: "breatheroute", MaxConns: 10}
+
+	_, ok := cfg.ReadConfig()
+	assert.False(t, ok, "no replica without a read host")
+
+	cfg.ReadHost = "replica"
+	readCfg, ok := cfg.ReadConfig()
+	require.True(t, ok)
+	assert.Equal(t, "replica", readCfg.Host)
+	assert.Equal(t, 5432, readCfg.Port)
+	assert.Equal(t, "breatheroute", readCfg.Database)
+	assert.Equal(t, 10, readCfg.MaxConns)
+
+	cfg.ReadPort = 6432
+	readCfg, _ = cfg.ReadConfig()
+	assert.Equal(t, 6432, readCfg.Port)
+}
+
+func TestUseReplica(t *testing.T) {
+	assert.False(t, database.UseReplica(context.Background()), "unmarked contexts read from the primary")
+
+	ctx := database.WithReplicaReads(context.Background())
+	assert.True(t, database.UseReplica(ctx))
+
+	// Reads after a write in the same request go to the primary
+	database.MarkWrite(ctx)
+	assert.False(t, database.UseReplica(ctx))
+
+	// Marking a write without replica reads is a no-op
+	database.MarkWrite(context.Background())
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/breatheroute/breatheroute/internal/database"
+
+// RegisterPoolMetrics reports the connection pool's statistics as
+// OpenTelemetry observable instruments on the global meter provider. Values
+// are read from pool.Stat at each collection. Unregister the returned
+// registration before closing the pool.
+func RegisterPoolMetrics(pool *pgxpool.Pool) (metric.Registration, error) {
+	meter := otel.Meter(meterName)
+
+	acquired, err := meter.Int64ObservableGauge(
+		"db.pool.acquired_conns",
+		metric.WithDescription("Number of connections currently in use"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	idle, err := meter.Int64ObservableGauge(
+		"db.pool.idle_conns",
+		metric.WithDescription("Number of idle connections in the pool"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := meter.Int64ObservableGauge(
+		"db.pool.total_conns",
+		metric.WithDescription("Total number of connections in the pool, including those being established"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConns, err := meter.Int64ObservableGauge(
+		"db.pool.max_conns",
+		metric.WithDescription("Maximum size of the pool"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	acquireCount, err := meter.Int64ObservableCounter(
+		"db.pool.acquires",
+		metric.WithDescription("Cumulative number of successful connection acquires"),
+		metric.WithUnit("{acquire}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyAcquireCount, err := meter.Int64ObservableCounter(
+		"db.pool.empty_acquires",
+		metric.WithDescription("Cumulative number of acquires that waited because the pool was empty"),
+		metric.WithUnit("{acquire}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	acquireDuration, err := meter.Float64ObservableCounter(
+		"db.pool.acquire_duration",
+		metric.WithDescription("Cumulative time spent waiting for connections in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stat := pool.Stat()
+		o.ObserveInt64(acquired, int64(stat.AcquiredConns()))
+		o.ObserveInt64(idle, int64(stat.IdleConns()))
+		o.ObserveInt64(total, int64(stat.TotalConns()))
+		o.ObserveInt64(maxConns, int64(stat.MaxConns()))
+		o.ObserveInt64(acquireCount, stat.AcquireCount())
+		o.ObserveInt64(emptyAcquireCount, stat.EmptyAcquireCount())
+		o.ObserveFloat64(acquireDuration, stat.AcquireDuration().Seconds())
+		return nil
+	}, acquired, idle, total, maxConns, acquireCount, emptyAcquireCount, acquireDuration)
+}
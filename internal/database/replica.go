@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DB holds the primary connection pool and an optional read replica pool.
+type DB struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+}
+
+// Open connects to the primary and, if cfg has a ReadHost, to the read
+// replica.
+func Open(ctx context.Context, cfg Config) (*DB, error) {
+	primary, err := Connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{primary: primary}
+	if readCfg, ok := cfg.ReadConfig(); ok {
+		replica, err := Connect(ctx, readCfg)
+		if err != nil {
+			primary.Close()
+			return nil, fmt.Errorf("connect read replica: %w", err)
+		}
+		db.replica = replica
+	}
+	return db, nil
+}
+
+// Pool returns the primary pool, used for writes and for reads that must
+// see them.
+func (db *DB) Pool() *pgxpool.Pool {
+	return db.primary
+}
+
+// ReadPool returns the read replica pool, or the primary pool if no
+// replica is configured.
+func (db *DB) ReadPool() *pgxpool.Pool {
+	if db.replica != nil {
+		return db.replica
+	}
+	return db.primary
+}
+
+// HasReplica reports whether a read replica is configured.
+func (db *DB) HasReplica() bool {
+	return db.replica != nil
+}
+
+// Close closes both pools.
+func (db *DB) Close() {
+	if db.replica != nil {
+		db.replica.Close()
+	}
+	db.primary.Close()
+}
+
+// readRoutingKey is the context key for the request's read routing.
+type readRoutingKey struct{}
+
+// readRouting records whether a request may read from the replica and
+// whether it has written to the primary.
+type readRouting struct {
+	wrote atomic.Bool
+}
+
+// WithReplicaReads marks ctx as allowed to read from the replica. Until
+// MarkWrite is called with it, UseReplica reports true.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readRoutingKey{}, &readRouting{})
+}
+
+// MarkWrite records a write in ctx, so later reads in the same request go
+// to the primary and see it despite replica lag.
+func MarkWrite(ctx context.Context) {
+	if routing, ok := ctx.Value(readRoutingKey{}).(*readRouting); ok {
+		routing.wrote.Store(true)
+	}
+}
+
+// UseReplica reports whether reads for ctx may go to the replica. Contexts
+// not marked with WithReplicaReads always read from the primary.
+func UseReplica(ctx context.Context) bool {
+	routing, ok := ctx.Value(readRoutingKey{}).(*readRouting)
+	return ok && !routing.wrote.Load()
+}
@@ -0,0 +1,72 @@
+package exposure
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryRepository is an in-memory implementation of Repository.
+// This is intended for testing. Production should use the PostgreSQL implementation.
+type InMemoryRepository struct {
+	mu    sync.RWMutex
+	trips map[tripKey]*Trip
+}
+
+// tripKey identifies a trip by commute and date.
+type tripKey struct {
+	commuteID string
+	date      time.Time
+}
+
+// NewInMemoryRepository creates a new in-memory exposure repository.
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		trips: make(map[tripKey]*Trip),
+	}
+}
+
+// Exists reports whether a trip is stored for the commute on date.
+func (r *InMemoryRepository) Exists(_ context.Context, commuteID string, date time.Time) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.trips[tripKey{commuteID, TripDate(date)}]
+	return ok, nil
+}
+
+// Create stores a trip unless one is already stored for its commute and date.
+func (r *InMemoryRepository) Create(_ context.Context, trip *Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := tripKey{trip.CommuteID, TripDate(trip.Date)}
+	if _, ok := r.trips[key]; ok {
+		return nil
+	}
+	tripCopy := *trip
+	r.trips[key] = &tripCopy
+	return nil
+}
+
+// ListByUser retrieves a user's trips dated on or after since, oldest first.
+func (r *InMemoryRepository) ListByUser(_ context.Context, userID string, since time.Time) ([]*Trip, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var trips []*Trip
+	for _, trip := range r.trips {
+		if trip.UserID == userID && !trip.Date.Before(since) {
+			tripCopy := *trip
+			trips = append(trips, &tripCopy)
+		}
+	}
+	sort.Slice(trips, func(i, j int) bool {
+		if !trips[i].Date.Equal(trips[j].Date) {
+			return trips[i].Date.Before(trips[j].Date)
+		}
+		return trips[i].CommuteID < trips[j].CommuteID
+	})
+	return trips, nil
+}
@@ -0,0 +1,32 @@
+// Package exposure stores the estimated air pollution exposure on users'
+// completed commute trips and aggregates it by week.
+package exposure
+
+import "time"
+
+// Trip is the estimated exposure on one completed commute trip. There is at
+// most one trip per commute and calendar date.
+type Trip struct {
+	CommuteID string
+	UserID    string
+
+	// Date is the trip's calendar date in the commute's timezone, at
+	// midnight UTC.
+	Date time.Time
+
+	// ArrivalAt is the scheduled arrival the estimate was made for.
+	ArrivalAt time.Time
+
+	// Average concentrations in µg/m³ over the commute's stops.
+	NO2  float64
+	PM25 float64
+	O3   float64
+
+	CreatedAt time.Time
+}
+
+// TripDate returns the calendar date of t, at midnight UTC, as stored in
+// Trip.Date.
+func TripDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
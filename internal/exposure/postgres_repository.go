@@ -0,0 +1,90 @@
+package exposure
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository is a PostgreSQL implementation of Repository.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgreSQL exposure repository.
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// Exists reports whether a trip is stored for the commute on date.
+func (r *PostgresRepository) Exists(ctx context.Context, commuteID string, date time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM commute_exposures WHERE commute_id = $1 AND trip_date = $2
+		)
+	`
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, query, commuteID, TripDate(date)).Scan(&exists)
+	return exists, err
+}
+
+// Create stores a trip unless one is already stored for its commute and date.
+func (r *PostgresRepository) Create(ctx context.Context, trip *Trip) error {
+	query := `
+		INSERT INTO commute_exposures (
+			commute_id, trip_date, user_id, arrival_at, no2_ugm3, pm25_ugm3, o3_ugm3, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (commute_id, trip_date) DO NOTHING
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		trip.CommuteID,
+		TripDate(trip.Date),
+		trip.UserID,
+		trip.ArrivalAt,
+		trip.NO2,
+		trip.PM25,
+		trip.O3,
+		trip.CreatedAt,
+	)
+	return err
+}
+
+// ListByUser retrieves a user's trips dated on or after since, oldest first.
+func (r *PostgresRepository) ListByUser(ctx context.Context, userID string, since time.Time) ([]*Trip, error) {
+	query := `
+		SELECT commute_id, trip_date, user_id, arrival_at, no2_ugm3, pm25_ugm3, o3_ugm3, created_at
+		FROM commute_exposures
+		WHERE user_id = $1 AND trip_date >= $2
+		ORDER BY trip_date, commute_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, TripDate(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trips []*Trip
+	for rows.Next() {
+		var trip Trip
+		if err := rows.Scan(
+			&trip.CommuteID,
+			&trip.Date,
+			&trip.UserID,
+			&trip.ArrivalAt,
+			&trip.NO2,
+			&trip.PM25,
+			&trip.O3,
+			&trip.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		trips = append(trips, &trip)
+	}
+
+	return trips, rows.Err()
+}
@@ -0,0 +1,19 @@
+package exposure
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for trip exposure persistence.
+type Repository interface {
+	// Exists reports whether a trip is stored for the commute on date.
+	Exists(ctx context.Context, commuteID string, date time.Time) (bool, error)
+
+	// Create stores a trip. If a trip is already stored for the same commute
+	// and date, it is kept and trip is discarded.
+	Create(ctx context.Context, trip *Trip) error
+
+	// ListByUser retrieves a user's trips dated on or after since, oldest first.
+	ListByUser(ctx context.Context, userID string, since time.Time) ([]*Trip, error)
+}
@@ -0,0 +1,111 @@
+package exposure
+
+import (
+	"context"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Limits for the weeks of exposure history returned.
+const (
+	DefaultHistoryWeeks = 8
+	MaxHistoryWeeks     = 52
+)
+
+// ServiceConfig holds configuration for the exposure service.
+type ServiceConfig struct {
+	// Repository stores trip exposures (required).
+	Repository Repository
+
+	// Location decides the calendar day, and so the current week, at the
+	// time of a request (default: Europe/Amsterdam).
+	Location *time.Location
+}
+
+// Service reads users' exposure history.
+type Service struct {
+	repo     Repository
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewService creates a new exposure service.
+func NewService(cfg ServiceConfig) *Service {
+	location := cfg.Location
+	if location == nil {
+		var err error
+		if location, err = time.LoadLocation("Europe/Amsterdam"); err != nil {
+			location = time.UTC
+		}
+	}
+
+	return &Service{
+		repo:     cfg.Repository,
+		location: location,
+		now:      time.Now,
+	}
+}
+
+// WeeklyHistory returns a user's exposure aggregated per ISO week (Monday to
+// Sunday) for the current week and the weeks before it, newest first. The
+// number of weeks is clamped to MaxHistoryWeeks; zero or less uses
+// DefaultHistoryWeeks.
+func (s *Service) WeeklyHistory(ctx context.Context, userID string, weeks int) (*models.ExposureHistory, error) {
+	if weeks <= 0 {
+		weeks = DefaultHistoryWeeks
+	}
+	weeks = min(weeks, MaxHistoryWeeks)
+
+	currentWeek := weekStart(TripDate(s.now().In(s.location)))
+	since := currentWeek.AddDate(0, 0, -7*(weeks-1))
+
+	trips, err := s.repo.ListByUser(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	byWeek := make(map[time.Time][]*Trip)
+	for _, trip := range trips {
+		start := weekStart(trip.Date)
+		byWeek[start] = append(byWeek[start], trip)
+	}
+
+	history := &models.ExposureHistory{Weeks: make([]models.ExposureWeek, 0, weeks)}
+	for start := currentWeek; !start.Before(since); start = start.AddDate(0, 0, -7) {
+		history.Weeks = append(history.Weeks, aggregateWeek(start, byWeek[start]))
+	}
+	return history, nil
+}
+
+// aggregateWeek averages the exposure of a week's trips.
+func aggregateWeek(start time.Time, trips []*Trip) models.ExposureWeek {
+	week := models.ExposureWeek{
+		WeekStart: start.Format("2006-01-02"),
+		Trips:     len(trips),
+	}
+	if len(trips) == 0 {
+		return week
+	}
+
+	var no2, pm25, o3 float64
+	for _, trip := range trips {
+		no2 += trip.NO2
+		pm25 += trip.PM25
+		o3 += trip.O3
+	}
+	n := float64(len(trips))
+	no2, pm25, o3 = no2/n, pm25/n, o3/n
+	week.Averages = &models.ExposureRawAverages{
+		NO2Ugm3:  &no2,
+		PM25Ugm3: &pm25,
+		O3Ugm3:   &o3,
+	}
+	return week
+}
+
+// weekStart returns the Monday of date's ISO week.
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7 // Days since Monday
+	return date.AddDate(0, 0, -offset)
+}
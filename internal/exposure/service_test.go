@@ -0,0 +1,89 @@
+package exposure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/exposure"
+)
+
+const testUserID = "usr_testuser123"
+
+func TestService_WeeklyHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := exposure.NewInMemoryRepository()
+	service := exposure.NewService(exposure.ServiceConfig{Repository: repo, Location: time.UTC})
+
+	today := exposure.TripDate(time.Now().UTC())
+	trips := []*exposure.Trip{
+		{CommuteID: "cmt_1", UserID: testUserID, Date: today, NO2: 20, PM25: 8, O3: 40},
+		{CommuteID: "cmt_2", UserID: testUserID, Date: today, NO2: 30, PM25: 12, O3: 50},
+		{CommuteID: "cmt_1", UserID: testUserID, Date: today.AddDate(0, 0, -7), NO2: 40, PM25: 10, O3: 30},
+		{CommuteID: "cmt_1", UserID: testUserID, Date: today.AddDate(0, 0, -70), NO2: 99, PM25: 99, O3: 99},
+		{CommuteID: "cmt_3", UserID: "usr_other", Date: today, NO2: 99, PM25: 99, O3: 99},
+	}
+	for _, trip := range trips {
+		require.NoError(t, repo.Create(ctx, trip))
+	}
+
+	history, err := service.WeeklyHistory(ctx, testUserID, 3)
+	require.NoError(t, err)
+	require.Len(t, history.Weeks, 3)
+
+	thisWeek, lastWeek, before := history.Weeks[0], history.Weeks[1], history.Weeks[2]
+	assert.Equal(t, time.Monday, mustParseDate(t, thisWeek.WeekStart).Weekday())
+	assert.Equal(t, mustParseDate(t, thisWeek.WeekStart).AddDate(0, 0, -7).Format("2006-01-02"), lastWeek.WeekStart)
+
+	assert.Equal(t, 2, thisWeek.Trips)
+	require.NotNil(t, thisWeek.Averages)
+	assert.InDelta(t, 25.0, *thisWeek.Averages.NO2Ugm3, 1e-9)
+	assert.InDelta(t, 10.0, *thisWeek.Averages.PM25Ugm3, 1e-9)
+	assert.InDelta(t, 45.0, *thisWeek.Averages.O3Ugm3, 1e-9)
+
+	assert.Equal(t, 1, lastWeek.Trips)
+	assert.InDelta(t, 40.0, *lastWeek.Averages.NO2Ugm3, 1e-9)
+
+	assert.Equal(t, 0, before.Trips)
+	assert.Nil(t, before.Averages)
+}
+
+func TestService_WeeklyHistory_Limits(t *testing.T) {
+	service := exposure.NewService(exposure.ServiceConfig{Repository: exposure.NewInMemoryRepository()})
+
+	history, err := service.WeeklyHistory(context.Background(), testUserID, 0)
+	require.NoError(t, err)
+	assert.Len(t, history.Weeks, exposure.DefaultHistoryWeeks)
+
+	history, err = service.WeeklyHistory(context.Background(), testUserID, 1000)
+	require.NoError(t, err)
+	assert.Len(t, history.Weeks, exposure.MaxHistoryWeeks)
+}
+
+func TestInMemoryRepository_CreateKeepsFirstTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := exposure.NewInMemoryRepository()
+	date := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.Create(ctx, &exposure.Trip{CommuteID: "cmt_1", UserID: testUserID, Date: date, NO2: 20}))
+	require.NoError(t, repo.Create(ctx, &exposure.Trip{CommuteID: "cmt_1", UserID: testUserID, Date: date, NO2: 30}))
+
+	exists, err := repo.Exists(ctx, "cmt_1", date.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	trips, err := repo.ListByUser(ctx, testUserID, date)
+	require.NoError(t, err)
+	require.Len(t, trips, 1)
+	assert.Equal(t, 20.0, trips[0].NO2)
+}
+
+func mustParseDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	date, err := time.Parse("2006-01-02", s)
+	require.NoError(t, err)
+	return date
+}
@@ -8,8 +8,9 @@ import (
 
 // InMemoryRepository is an in-memory implementation of Repository for testing.
 type InMemoryRepository struct {
-	mu    sync.RWMutex
-	flags map[string]*Flag
+	mu      sync.RWMutex
+	flags   map[string]*Flag
+	changes []*FlagChange
 }
 
 // NewInMemoryRepository creates a new in-memory repository.
@@ -59,8 +60,7 @@ func (r *InMemoryRepository) SetFlag(ctx context.Context, flag *Flag) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	flag.UpdatedAt = time.Now()
-	r.flags[flag.Key] = flag
+	r.set(flag, time.Now())
 	return nil
 }
 
@@ -71,20 +71,48 @@ func (r *InMemoryRepository) SetFlags(ctx context.Context, flags []*Flag) error
 
 	now := time.Now()
 	for _, flag := range flags {
-		flag.UpdatedAt = now
-		r.flags[flag.Key] = flag
+		r.set(flag, now)
 	}
 	return nil
 }
 
+// set stores flag and records the change. The caller must hold r.mu.
+func (r *InMemoryRepository) set(flag *Flag, now time.Time) {
+	change := &FlagChange{Key: flag.Key, NewValue: flag.Value, ChangedBy: flag.UpdatedBy, ChangedAt: now}
+	if previous, ok := r.flags[flag.Key]; ok {
+		change.PreviousValue = previous.Value
+	}
+	r.changes = append(r.changes, change)
+
+	flag.UpdatedAt = now
+	r.flags[flag.Key] = flag
+}
+
 // DeleteFlag removes a feature flag by key.
 func (r *InMemoryRepository) DeleteFlag(ctx context.Context, key string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.flags, key)
+	if previous, ok := r.flags[key]; ok {
+		r.changes = append(r.changes, &FlagChange{Key: key, PreviousValue: previous.Value, ChangedAt: time.Now()})
+		delete(r.flags, key)
+	}
 	return nil
 }
 
+// ListChanges returns up to limit of the flag's changes, newest first.
+func (r *InMemoryRepository) ListChanges(ctx context.Context, key string, limit int) ([]*FlagChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var changes []*FlagChange
+	for i := len(r.changes) - 1; i >= 0 && len(changes) < limit; i-- {
+		if r.changes[i].Key == key {
+			changes = append(changes, r.changes[i])
+		}
+	}
+	return changes, nil
+}
+
 // Ensure InMemoryRepository implements Repository interface.
 var _ Repository = (*InMemoryRepository)(nil)
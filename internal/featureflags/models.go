@@ -3,19 +3,46 @@ package featureflags
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
-// ErrFlagNotFound is returned when a feature flag is not found.
-var ErrFlagNotFound = errors.New("feature flag not found")
+var (
+	// ErrFlagNotFound is returned when a feature flag is not found.
+	ErrFlagNotFound = errors.New("feature flag not found")
 
-// Feature flag keys.
+	// ErrNoRepository is returned when flags are changed or their history
+	// read on a service without a repository.
+	ErrNoRepository = errors.New("feature flags are not configured")
+)
+
+// Feature flag keys. Each known flag is also declared with its type and
+// default in the registry; read them through the typed accessors.
 const (
 	// FlagDisablePollenFactor disables pollen factor in route calculations.
 	FlagDisablePollenFactor = "pollen_factor_disabled"
+
+	// FlagEnableTimeShift enables forecast-based (time-shifted) air quality for alerts.
+	FlagEnableTimeShift = "enable_time_shift"
+
+	// FlagRoutingCacheTTLSeconds overrides the routing service's cache TTL.
+	FlagRoutingCacheTTLSeconds = "routing_cache_ttl_seconds"
+
+	// FlagRoutingCacheGridSize overrides the routing service's cache grid size in degrees.
+	FlagRoutingCacheGridSize = "routing_cache_grid_size"
+
+	// FlagWeatherCacheTTLSeconds overrides the weather service's cache TTL.
+	FlagWeatherCacheTTLSeconds = "weather_cache_ttl_seconds"
+
+	// FlagWeatherCacheGridSize overrides the weather service's cache grid size in degrees.
+	FlagWeatherCacheGridSize = "weather_cache_grid_size"
+
+	// FlagMaintenanceMode rejects API writes while reads keep working, e.g. during migrations.
+	FlagMaintenanceMode = "maintenance_mode"
 )
 
 // Flag represents a feature flag.
@@ -23,6 +50,60 @@ type Flag struct {
 	Key       string
 	Value     interface{}
 	UpdatedAt time.Time
+
+	// UpdatedBy is the ID of the admin who last set the flag, empty for
+	// seeded flags and changes made outside the API.
+	UpdatedBy string
+}
+
+// FlagChange is an entry in a flag's append-only change history.
+type FlagChange struct {
+	Key string
+
+	// PreviousValue is nil when the change created the flag, and NewValue
+	// nil when it deleted it.
+	PreviousValue interface{}
+	NewValue      interface{}
+
+	// ChangedBy is the ID of the admin who made the change, empty for
+	// changes made outside the API.
+	ChangedBy string
+	ChangedAt time.Time
+}
+
+// Flag history list limits.
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 200
+)
+
+// Float returns the flag value as a float64. Values decoded from JSON are
+// float64; integer values set in code are converted.
+func (f *Flag) Float() (float64, bool) {
+	switch v := f.Value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Int returns the flag value as an int64. Fractional values are truncated.
+func (f *Flag) Int() (int64, bool) {
+	n, ok := f.Float()
+	if !ok {
+		return 0, false
+	}
+	return int64(n), true
 }
 
 // Repository defines the interface for feature flag storage.
@@ -32,6 +113,10 @@ type Repository interface {
 	SetFlag(ctx context.Context, flag *Flag) error
 	SetFlags(ctx context.Context, flags []*Flag) error
 	DeleteFlag(ctx context.Context, key string) error
+
+	// ListChanges returns up to limit of the flag's changes, newest first.
+	// Every SetFlag, SetFlags and DeleteFlag appends one change per flag.
+	ListChanges(ctx context.Context, key string, limit int) ([]*FlagChange, error)
 }
 
 // ServiceConfig holds configuration for the feature flags service.
@@ -61,17 +146,51 @@ func NewService(cfg ServiceConfig) *Service {
 	}
 }
 
-// IsPollenFactorDisabled checks if the pollen factor is disabled.
-func (s *Service) IsPollenFactorDisabled(ctx context.Context) bool {
+// SetFlags sets the flags in values, keyed by flag key, recording updatedBy
+// as the admin who changed them. All flags are set or none are; a known
+// flag set to a value of the wrong type returns ErrInvalidFlagValue.
+func (s *Service) SetFlags(ctx context.Context, values map[string]interface{}, updatedBy string) error {
 	if s == nil || s.repo == nil {
-		return false
+		return ErrNoRepository
 	}
-	flag, err := s.repo.GetFlag(ctx, "pollen_factor_disabled")
-	if err != nil {
-		return false
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
 	}
-	if disabled, ok := flag.Value.(bool); ok {
-		return disabled
+	sort.Strings(keys)
+
+	flags := make([]*Flag, 0, len(keys))
+	for _, key := range keys {
+		if err := validate(key, values[key]); err != nil {
+			return err
+		}
+		flags = append(flags, &Flag{Key: key, Value: values[key], UpdatedBy: updatedBy})
 	}
-	return false
+	return s.repo.SetFlags(ctx, flags)
+}
+
+// History returns up to limit of the flag's most recent changes, newest
+// first. limit is clamped to [1, MaxHistoryLimit].
+func (s *Service) History(ctx context.Context, key string, limit int) ([]*FlagChange, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrNoRepository
+	}
+	return s.repo.ListChanges(ctx, key, min(max(limit, 1), MaxHistoryLimit))
+}
+
+// IsPollenFactorDisabled checks if the pollen factor is disabled.
+func (s *Service) IsPollenFactorDisabled(ctx context.Context) bool {
+	return s.Bool(ctx, disablePollenFactor)
+}
+
+// IsTimeShiftEnabled checks if time-shifted air quality forecasting is enabled.
+// Defaults to enabled when the flag is unset, matching the seeded default.
+func (s *Service) IsTimeShiftEnabled(ctx context.Context) bool {
+	return s.Bool(ctx, enableTimeShift)
+}
+
+// IsMaintenanceMode checks if the API is in maintenance mode. Defaults to
+// off when the flag is unset or cannot be read.
+func (s *Service) IsMaintenanceMode(ctx context.Context) bool {
+	return s.Bool(ctx, maintenanceMode)
 }
@@ -0,0 +1,84 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// NumberOverride is a numeric flag that overrides a value fixed at service
+// construction, so operators can tune it without a redeploy.
+// The flag is read at most once per feature flag cache TTL.
+type NumberOverride struct {
+	service *Service
+	key     string
+	min     float64
+	max     float64
+
+	mu     sync.Mutex
+	value  float64
+	ok     bool
+	readAt time.Time
+}
+
+// NumberOverride returns an override backed by the numeric flag key, clamped
+// to [min, max]. It is safe to call on a nil Service; the override is then
+// never set.
+func (s *Service) NumberOverride(key string, minValue, maxValue float64) *NumberOverride {
+	return &NumberOverride{
+		service: s,
+		key:     key,
+		min:     minValue,
+		max:     maxValue,
+	}
+}
+
+// Value returns the clamped flag value, or false if the flag is absent or
+// not a number, in which case callers keep their constructed default.
+// It may query the repository, so it should not be called while holding
+// locks on a request's hot path.
+func (o *NumberOverride) Value(ctx context.Context) (float64, bool) {
+	if o == nil || o.service == nil || o.service.repo == nil {
+		return 0, false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.readAt.IsZero() && time.Since(o.readAt) < o.service.cacheTTL {
+		return o.value, o.ok
+	}
+
+	flag, err := o.service.repo.GetFlag(ctx, o.key)
+	switch {
+	case errors.Is(err, ErrFlagNotFound):
+		o.value, o.ok = 0, false
+	case err != nil:
+		// Keep the last known value rather than dropping an override mid-incident
+		o.service.logger.Warn().Err(err).Str("flag", o.key).Msg("failed to read override flag")
+	default:
+		o.value, o.ok = o.clamp(flag)
+	}
+	o.readAt = time.Now()
+	return o.value, o.ok
+}
+
+// clamp converts the flag value to a number within the override's range.
+func (o *NumberOverride) clamp(flag *Flag) (float64, bool) {
+	value, ok := flag.Float()
+	if !ok {
+		o.service.logger.Warn().Str("flag", o.key).Msg("ignoring non-numeric override flag")
+		return 0, false
+	}
+
+	clamped := min(max(value, o.min), o.max)
+	if clamped != value {
+		o.service.logger.Warn().
+			Str("flag", o.key).
+			Float64("value", value).
+			Float64("clamped", clamped).
+			Msg("override flag out of range, clamping")
+	}
+	return clamped, true
+}
@@ -23,7 +23,7 @@ func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
 // GetFlag retrieves a single feature flag by key.
 func (r *PostgresRepository) GetFlag(ctx context.Context, key string) (*Flag, error) {
 	query := `
-		SELECT key, value, updated_at
+		SELECT key, value, updated_at, COALESCE(updated_by, '')
 		FROM feature_flags
 		WHERE key = $1
 	`
@@ -37,6 +37,7 @@ func (r *PostgresRepository) GetFlag(ctx context.Context, key string) (*Flag, er
 		&flag.Key,
 		&valueJSON,
 		&flag.UpdatedAt,
+		&flag.UpdatedBy,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -56,7 +57,7 @@ func (r *PostgresRepository) GetFlag(ctx context.Context, key string) (*Flag, er
 // GetAllFlags retrieves all feature flags.
 func (r *PostgresRepository) GetAllFlags(ctx context.Context) (map[string]*Flag, error) {
 	query := `
-		SELECT key, value, updated_at
+		SELECT key, value, updated_at, COALESCE(updated_by, '')
 		FROM feature_flags
 		ORDER BY key
 	`
@@ -78,6 +79,7 @@ func (r *PostgresRepository) GetAllFlags(ctx context.Context) (map[string]*Flag,
 			&flag.Key,
 			&valueJSON,
 			&flag.UpdatedAt,
+			&flag.UpdatedBy,
 		)
 		if err != nil {
 			return nil, err
@@ -98,26 +100,13 @@ func (r *PostgresRepository) GetAllFlags(ctx context.Context) (map[string]*Flag,
 	return flags, nil
 }
 
-// SetFlag creates or updates a feature flag.
+// SetFlag creates or updates a feature flag and records the change.
 func (r *PostgresRepository) SetFlag(ctx context.Context, flag *Flag) error {
-	query := `
-		INSERT INTO feature_flags (key, value, updated_at)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (key) DO UPDATE SET
-			value = EXCLUDED.value,
-			updated_at = EXCLUDED.updated_at
-	`
-
-	valueJSON, err := json.Marshal(flag.Value)
-	if err != nil {
-		return err
-	}
-
-	_, err = r.pool.Exec(ctx, query, flag.Key, valueJSON, time.Now())
-	return err
+	return r.SetFlags(ctx, []*Flag{flag})
 }
 
-// SetFlags creates or updates multiple feature flags atomically.
+// SetFlags creates or updates multiple feature flags atomically, recording
+// a change for each.
 func (r *PostgresRepository) SetFlags(ctx context.Context, flags []*Flag) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
@@ -125,12 +114,15 @@ func (r *PostgresRepository) SetFlags(ctx context.Context, flags []*Flag) error
 	}
 	defer tx.Rollback(ctx) //nolint:errcheck // rollback error is not critical
 
-	query := `
-		INSERT INTO feature_flags (key, value, updated_at)
-		VALUES ($1, $2, $3)
+	// Lock the current row so concurrent changes record the right previous value
+	selectQuery := `SELECT value FROM feature_flags WHERE key = $1 FOR UPDATE`
+	upsertQuery := `
+		INSERT INTO feature_flags (key, value, updated_at, updated_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
 		ON CONFLICT (key) DO UPDATE SET
 			value = EXCLUDED.value,
-			updated_at = EXCLUDED.updated_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
 	`
 
 	now := time.Now()
@@ -140,8 +132,16 @@ func (r *PostgresRepository) SetFlags(ctx context.Context, flags []*Flag) error
 			return err
 		}
 
-		_, err = tx.Exec(ctx, query, flag.Key, valueJSON, now)
-		if err != nil {
+		var previousJSON []byte
+		err = tx.QueryRow(ctx, selectQuery, flag.Key).Scan(&previousJSON)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, upsertQuery, flag.Key, valueJSON, now, flag.UpdatedBy); err != nil {
+			return err
+		}
+		if err := insertChange(ctx, tx, flag.Key, previousJSON, valueJSON, flag.UpdatedBy, now); err != nil {
 			return err
 		}
 	}
@@ -149,12 +149,85 @@ func (r *PostgresRepository) SetFlags(ctx context.Context, flags []*Flag) error
 	return tx.Commit(ctx)
 }
 
-// DeleteFlag removes a feature flag by key.
+// DeleteFlag removes a feature flag by key and records the change.
 func (r *PostgresRepository) DeleteFlag(ctx context.Context, key string) error {
-	query := `DELETE FROM feature_flags WHERE key = $1`
-	_, err := r.pool.Exec(ctx, query, key)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // rollback error is not critical
+
+	var previousJSON []byte
+	query := `DELETE FROM feature_flags WHERE key = $1 RETURNING value`
+	err = tx.QueryRow(ctx, query, key).Scan(&previousJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertChange(ctx, tx, key, previousJSON, nil, "", time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// insertChange appends a change to the flag history. Nil values are stored
+// as NULL.
+func insertChange(ctx context.Context, tx pgx.Tx, key string, previousJSON, newJSON []byte, changedBy string, at time.Time) error {
+	query := `
+		INSERT INTO feature_flag_changes (key, previous_value, new_value, changed_by, changed_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+	`
+	_, err := tx.Exec(ctx, query, key, previousJSON, newJSON, changedBy, at)
 	return err
 }
 
+// ListChanges returns up to limit of the flag's changes, newest first.
+func (r *PostgresRepository) ListChanges(ctx context.Context, key string, limit int) ([]*FlagChange, error) {
+	query := `
+		SELECT key, previous_value, new_value, COALESCE(changed_by, ''), changed_at
+		FROM feature_flag_changes
+		WHERE key = $1
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*FlagChange
+	for rows.Next() {
+		var (
+			change                FlagChange
+			previousJSON, newJSON []byte
+		)
+		if err := rows.Scan(&change.Key, &previousJSON, &newJSON, &change.ChangedBy, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if previousJSON != nil {
+			if err := json.Unmarshal(previousJSON, &change.PreviousValue); err != nil {
+				return nil, err
+			}
+		}
+		if newJSON != nil {
+			if err := json.Unmarshal(newJSON, &change.NewValue); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
 // Ensure PostgresRepository implements Repository interface.
 var _ Repository = (*PostgresRepository)(nil)
@@ -0,0 +1,135 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidFlagValue is returned when a known flag is set to a value of
+// the wrong type.
+var ErrInvalidFlagValue = errors.New("invalid feature flag value")
+
+// FlagType is the value type of a known flag.
+type FlagType string
+
+// Flag value types.
+const (
+	// FlagTypeBool flags hold a boolean or targeting rules.
+	FlagTypeBool FlagType = "bool"
+
+	// FlagTypeNumber flags hold a number.
+	FlagTypeNumber FlagType = "number"
+)
+
+// Definition describes a known flag: its type and the value used when it
+// is unset.
+type Definition struct {
+	Key     string
+	Type    FlagType
+	Default interface{}
+}
+
+// BoolFlag is a known boolean flag. Read it with Service.Bool or its typed
+// accessor, never by key.
+type BoolFlag struct {
+	key string
+	def bool
+}
+
+// Key returns the flag's key.
+func (f BoolFlag) Key() string { return f.key }
+
+// registry holds every known flag by key.
+var registry = map[string]Definition{}
+
+// boolFlag declares a known boolean flag.
+func boolFlag(key string, def bool) BoolFlag {
+	registerFlag(Definition{Key: key, Type: FlagTypeBool, Default: def})
+	return BoolFlag{key: key, def: def}
+}
+
+// numberFlag declares a known numeric flag, read through NumberOverride.
+func numberFlag(key string) string {
+	registerFlag(Definition{Key: key, Type: FlagTypeNumber})
+	return key
+}
+
+// registerFlag adds def to the registry. Declaring a key twice is a
+// programming error.
+func registerFlag(def Definition) {
+	if _, ok := registry[def.Key]; ok {
+		panic("featureflags: flag declared twice: " + def.Key)
+	}
+	registry[def.Key] = def
+}
+
+// Known flags.
+var (
+	disablePollenFactor = boolFlag(FlagDisablePollenFactor, false)
+	enableTimeShift     = boolFlag(FlagEnableTimeShift, true)
+	maintenanceMode     = boolFlag(FlagMaintenanceMode, false)
+
+	_ = numberFlag(FlagRoutingCacheTTLSeconds)
+	_ = numberFlag(FlagRoutingCacheGridSize)
+	_ = numberFlag(FlagWeatherCacheTTLSeconds)
+	_ = numberFlag(FlagWeatherCacheGridSize)
+)
+
+// Lookup returns the definition of a known flag.
+func Lookup(key string) (Definition, bool) {
+	def, ok := registry[key]
+	return def, ok
+}
+
+// Definitions returns every known flag, sorted by key.
+func Definitions() []Definition {
+	defs := make([]Definition, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+	return defs
+}
+
+// validate checks value against the type of a known flag. Unknown flags
+// accept any value, so dynamic flags can still be set.
+func validate(key string, value interface{}) error {
+	def, ok := registry[key]
+	if !ok {
+		return nil
+	}
+
+	flag := &Flag{Key: key, Value: value}
+	switch def.Type {
+	case FlagTypeBool:
+		if _, ok := flag.Targeting(); ok {
+			return nil
+		}
+	case FlagTypeNumber:
+		if _, ok := flag.Float(); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s must be a %s", ErrInvalidFlagValue, key, def.Type)
+}
+
+// Bool reads a known boolean flag. A targeted flag counts as on only if it
+// is on for everyone. Unset flags, unreadable flags and values of the wrong
+// type give the flag's default.
+func (s *Service) Bool(ctx context.Context, f BoolFlag) bool {
+	if s == nil || s.repo == nil {
+		return f.def
+	}
+	flag, err := s.repo.GetFlag(ctx, f.key)
+	if err != nil {
+		return f.def
+	}
+	t, ok := flag.Targeting()
+	if !ok {
+		s.logger.Warn().Str("flag", f.key).Msg("ignoring non-boolean flag value")
+		return f.def
+	}
+	return t.Enabled
+}
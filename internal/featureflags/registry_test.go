@@ -0,0 +1,81 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBool_Defaults(t *testing.T) {
+	ctx := context.Background()
+	var nilService *Service
+	empty := NewService(ServiceConfig{Repository: NewInMemoryRepository()})
+
+	for _, svc := range []*Service{nilService, empty} {
+		if !svc.IsTimeShiftEnabled(ctx) {
+			t.Error("time shift should default to enabled")
+		}
+		if svc.IsPollenFactorDisabled(ctx) || svc.IsMaintenanceMode(ctx) {
+			t.Error("switches should default to off")
+		}
+	}
+}
+
+func TestBool_WrongTypeUsesDefault(t *testing.T) {
+	svc := NewService(ServiceConfig{Repository: NewInMemoryRepositoryWithFlags(map[string]*Flag{
+		FlagEnableTimeShift:     {Key: FlagEnableTimeShift, Value: "false"},
+		FlagMaintenanceMode:     {Key: FlagMaintenanceMode, Value: 1.0},
+		FlagDisablePollenFactor: {Key: FlagDisablePollenFactor, Value: true},
+	})})
+	ctx := context.Background()
+
+	if !svc.IsTimeShiftEnabled(ctx) {
+		t.Error("a string value should not switch time shift off")
+	}
+	if svc.IsMaintenanceMode(ctx) {
+		t.Error("a number value should not enable maintenance mode")
+	}
+	if !svc.IsPollenFactorDisabled(ctx) {
+		t.Error("a boolean value should be read")
+	}
+}
+
+func TestSetFlags_ValidatesKnownFlags(t *testing.T) {
+	repo := NewInMemoryRepository()
+	svc := NewService(ServiceConfig{Repository: repo})
+	ctx := context.Background()
+
+	err := svc.SetFlags(ctx, map[string]interface{}{
+		FlagMaintenanceMode:        true,
+		FlagRoutingCacheTTLSeconds: "600",
+	}, "usr_admin")
+	if !errors.Is(err, ErrInvalidFlagValue) {
+		t.Fatalf("err = %v, want ErrInvalidFlagValue", err)
+	}
+	if _, err := repo.GetFlag(ctx, FlagMaintenanceMode); !errors.Is(err, ErrFlagNotFound) {
+		t.Error("no flag should be set when one is invalid")
+	}
+
+	err = svc.SetFlags(ctx, map[string]interface{}{
+		FlagMaintenanceMode:        map[string]interface{}{"users": []interface{}{"usr_1"}},
+		FlagRoutingCacheTTLSeconds: 600.0,
+		"experimental_thing":       "anything",
+	}, "usr_admin")
+	if err != nil {
+		t.Fatalf("SetFlags() error = %v", err)
+	}
+}
+
+func TestRegistry_KnowsEveryFlagKey(t *testing.T) {
+	for _, key := range []string{
+		FlagDisablePollenFactor, FlagEnableTimeShift, FlagMaintenanceMode,
+		FlagRoutingCacheTTLSeconds, FlagRoutingCacheGridSize, FlagWeatherCacheTTLSeconds, FlagWeatherCacheGridSize,
+	} {
+		if _, ok := Lookup(key); !ok {
+			t.Errorf("flag %q is not in the registry", key)
+		}
+	}
+	if got, want := len(Definitions()), 7; got != want {
+		t.Errorf("registry has %d flags, want %d", got, want)
+	}
+}
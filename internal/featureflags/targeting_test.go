@@ -0,0 +1,94 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestRolloutBucket_Deterministic(t *testing.T) {
+	for _, userID := range []string{"usr_1", "usr_2", "usr_abc"} {
+		first := rolloutBucket("new_scoring", userID)
+		if first >= rolloutBuckets {
+			t.Fatalf("bucket %d out of range", first)
+		}
+		for i := 0; i < 5; i++ {
+			if got := rolloutBucket("new_scoring", userID); got != first {
+				t.Errorf("bucket for %s changed: %d != %d", userID, got, first)
+			}
+		}
+	}
+}
+
+func TestTargeting_Percentage(t *testing.T) {
+	const users = 10000
+	count := func(rules Targeting, key string) int {
+		n := 0
+		for i := 0; i < users; i++ {
+			if rules.EnabledFor(key, fmt.Sprintf("usr_%d", i)) {
+				n++
+			}
+		}
+		return n
+	}
+
+	if n := count(Targeting{Percentage: 0}, "new_scoring"); n != 0 {
+		t.Errorf("0%% rollout enabled %d users", n)
+	}
+	if n := count(Targeting{Percentage: 100}, "new_scoring"); n != users {
+		t.Errorf("100%% rollout enabled %d of %d users", n, users)
+	}
+	if n := count(Targeting{Percentage: 20}, "new_scoring"); n < 1800 || n > 2200 {
+		t.Errorf("20%% rollout enabled %d of %d users, want about 2000", n, users)
+	}
+
+	// Growing a rollout keeps the users already in it
+	for i := 0; i < users; i++ {
+		id := fmt.Sprintf("usr_%d", i)
+		if (Targeting{Percentage: 10}).EnabledFor("new_scoring", id) && !(Targeting{Percentage: 30}).EnabledFor("new_scoring", id) {
+			t.Fatalf("%s dropped out when the rollout grew", id)
+		}
+	}
+
+	// Different flags reach different users
+	same := 0
+	for i := 0; i < users; i++ {
+		id := fmt.Sprintf("usr_%d", i)
+		if (Targeting{Percentage: 50}).EnabledFor("a", id) == (Targeting{Percentage: 50}).EnabledFor("b", id) {
+			same++
+		}
+	}
+	if same == users {
+		t.Error("rollouts of different flags should not bucket users identically")
+	}
+}
+
+func TestService_IsEnabledForUser(t *testing.T) {
+	var rules interface{}
+	if err := json.Unmarshal([]byte(`{"users": ["usr_beta"]}`), &rules); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(ServiceConfig{Repository: NewInMemoryRepositoryWithFlags(map[string]*Flag{
+		"beta":    {Key: "beta", Value: rules},
+		"global":  {Key: "global", Value: true},
+		"invalid": {Key: "invalid", Value: "yes"},
+	})})
+	ctx := context.Background()
+
+	if !svc.IsEnabledForUser(ctx, "beta", "usr_beta") {
+		t.Error("allowlisted user should have the flag")
+	}
+	if svc.IsEnabledForUser(ctx, "beta", "usr_other") {
+		t.Error("other users should not have the flag")
+	}
+	if svc.IsEnabled(ctx, "beta") {
+		t.Error("targeted flag should be off without a user")
+	}
+	if !svc.IsEnabled(ctx, "global") || !svc.IsEnabledForUser(ctx, "global", "usr_other") {
+		t.Error("boolean flag should be on for everyone")
+	}
+	if svc.IsEnabledForUser(ctx, "invalid", "usr_beta") || svc.IsEnabledForUser(ctx, "missing", "usr_beta") {
+		t.Error("invalid and missing flags should be off")
+	}
+}
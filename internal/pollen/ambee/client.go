@@ -33,6 +33,10 @@ type ClientConfig struct {
 	// If nil, uses a resilient client with defaults.
 	HTTPClient *resilience.Client
 
+	// Registry is the provider registry the default client registers with
+	// for health tracking (optional).
+	Registry *resilience.Registry
+
 	// Logger for client operations.
 	Logger zerolog.Logger
 }
@@ -54,7 +58,9 @@ func NewClient(cfg ClientConfig) *Client {
 
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
-		httpClient = resilience.NewClient(resilience.DefaultClientConfig("ambee"))
+		clientCfg := resilience.DefaultClientConfig("ambee")
+		clientCfg.Registry = cfg.Registry
+		httpClient = resilience.NewClient(clientCfg)
 	}
 
 	return &Client{
@@ -90,7 +96,7 @@ func (c *Client) GetRegionalPollen(ctx context.Context, lat, lon float64) (*poll
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &resilience.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var ambeeResp pollenResponse
@@ -125,7 +131,7 @@ func (c *Client) GetForecast(ctx context.Context, lat, lon float64) (*pollen.For
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &resilience.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var ambeeResp forecastResponse
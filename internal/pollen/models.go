@@ -2,6 +2,10 @@ package pollen
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,6 +15,12 @@ var (
 	ErrNoDataForRegion     = errors.New("no pollen data for region")
 	ErrInvalidCoordinates  = errors.New("invalid coordinates")
 	ErrPollenDisabled      = errors.New("pollen factor disabled by feature flag")
+	ErrNoForecastForDate   = errors.New("no pollen forecast for date")
+
+	// ErrInvalidExposureFactor is returned for an exposure factor mapping
+	// with an unknown risk level or a factor that is not a finite number of
+	// at least 1.0.
+	ErrInvalidExposureFactor = errors.New("invalid pollen exposure factor")
 )
 
 // Type represents a category of pollen.
@@ -38,6 +48,15 @@ const (
 	RiskVeryHigh RiskLevel = "VERY_HIGH"
 )
 
+// riskOrder ranks risk levels from lowest to highest.
+var riskOrder = map[RiskLevel]int{
+	RiskNone:     0,
+	RiskLow:      1,
+	RiskModerate: 2,
+	RiskHigh:     3,
+	RiskVeryHigh: 4,
+}
+
 // RiskLevelFromIndex converts a numeric index (0-5 scale) to RiskLevel.
 func RiskLevelFromIndex(index float64) RiskLevel {
 	switch {
@@ -108,23 +127,114 @@ func (r *RegionalPollen) GetReading(pollenType Type) *Reading {
 	return r.Readings[pollenType]
 }
 
-// ExposureFactor returns a multiplier (1.0-1.5) for exposure scoring.
-// Higher pollen means slightly worse conditions for sensitive users.
+// ExposureFactor returns a multiplier (1.0-1.5) for exposure scoring, using
+// DefaultExposureFactors. Higher pollen means slightly worse conditions for
+// sensitive users.
 func (r *RegionalPollen) ExposureFactor() float64 {
-	switch r.OverallRisk {
-	case RiskNone:
-		return 1.0
-	case RiskLow:
-		return 1.05
-	case RiskModerate:
-		return 1.1
-	case RiskHigh:
-		return 1.2
-	case RiskVeryHigh:
-		return 1.3
-	default:
-		return 1.0
+	return DefaultExposureFactors().For(r.OverallRisk)
+}
+
+// RiskFor returns the highest risk among the readings a user with the given
+// sensitivities reacts to. A sensitivity matches a reading if it names the
+// reading's type (e.g. "tree") or one of its species (e.g. "birch"),
+// ignoring case. Readings nobody is sensitive to are ignored, so the result
+// is RiskNone if none match. Without sensitivities, OverallRisk is returned.
+func (r *RegionalPollen) RiskFor(sensitivities []string) RiskLevel {
+	return riskFor(r.Readings, r.OverallRisk, sensitivities)
+}
+
+// riskFor returns the highest risk among the readings matching
+// sensitivities, or overall if there are no sensitivities.
+func riskFor(readings map[Type]*Reading, overall RiskLevel, sensitivities []string) RiskLevel {
+	if len(sensitivities) == 0 {
+		return overall
 	}
+
+	highest := RiskNone
+	for _, reading := range readings {
+		if reading != nil && reading.matches(sensitivities) && riskOrder[reading.Risk] > riskOrder[highest] {
+			highest = reading.Risk
+		}
+	}
+	return highest
+}
+
+// matches reports whether any sensitivity names the reading's type or one
+// of its species.
+func (r *Reading) matches(sensitivities []string) bool {
+	for _, sensitivity := range sensitivities {
+		sensitivity = strings.TrimSpace(sensitivity)
+		if strings.EqualFold(sensitivity, string(r.Type)) {
+			return true
+		}
+		for _, species := range r.Species {
+			if strings.EqualFold(sensitivity, strings.TrimSpace(species)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExposureFactors maps pollen risk levels to the multiplier applied to
+// exposure scores.
+type ExposureFactors map[RiskLevel]float64
+
+// DefaultExposureFactors returns the standard risk-to-factor mapping.
+func DefaultExposureFactors() ExposureFactors {
+	return ExposureFactors{
+		RiskNone:     1.0,
+		RiskLow:      1.05,
+		RiskModerate: 1.1,
+		RiskHigh:     1.2,
+		RiskVeryHigh: 1.3,
+	}
+}
+
+// ParseExposureFactors parses "MODERATE=1.15,HIGH=1.3" into a mapping. It
+// returns ErrInvalidExposureFactor if an entry is malformed or invalid.
+func ParseExposureFactors(raw string) (ExposureFactors, error) {
+	factors := make(ExposureFactors)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		risk, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not RISK=factor", ErrInvalidExposureFactor, pair)
+		}
+		factor, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidExposureFactor, value)
+		}
+		factors[RiskLevel(strings.ToUpper(strings.TrimSpace(risk)))] = factor
+	}
+	return factors, factors.Validate()
+}
+
+// Validate checks every entry names a known risk level and has a finite
+// factor of at least 1.0, so pollen never improves a route's score.
+func (f ExposureFactors) Validate() error {
+	for risk, factor := range f {
+		switch risk {
+		case RiskNone, RiskLow, RiskModerate, RiskHigh, RiskVeryHigh:
+		default:
+			return fmt.Errorf("%w: unknown risk level %q", ErrInvalidExposureFactor, risk)
+		}
+		if math.IsNaN(factor) || math.IsInf(factor, 0) || factor < 1.0 {
+			return fmt.Errorf("%w: %s must be a finite number of at least 1.0, got %v", ErrInvalidExposureFactor, risk, factor)
+		}
+	}
+	return nil
+}
+
+// For returns the factor for risk, or 1.0 (neutral) if it has none.
+func (f ExposureFactors) For(risk RiskLevel) float64 {
+	if factor, ok := f[risk]; ok {
+		return factor
+	}
+	return 1.0
 }
 
 // Forecast represents pollen forecast data.
@@ -153,3 +263,21 @@ type DailyForecast struct {
 	// OverallIndex is the predicted combined pollen index.
 	OverallIndex float64
 }
+
+// RiskFor returns the predicted risk for a user with the given
+// sensitivities, matched as in RegionalPollen.RiskFor.
+func (d *DailyForecast) RiskFor(sensitivities []string) RiskLevel {
+	return riskFor(d.Readings, d.OverallRisk, sensitivities)
+}
+
+// Day returns the forecast for date's calendar day, or nil if the forecast
+// does not cover it.
+func (f *Forecast) Day(date time.Time) *DailyForecast {
+	year, month, day := date.Date()
+	for i := range f.Daily {
+		if y, m, d := f.Daily[i].Date.Date(); y == year && m == month && d == day {
+			return &f.Daily[i]
+		}
+	}
+	return nil
+}
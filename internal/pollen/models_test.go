@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/breatheroute/breatheroute/internal/pollen"
 )
@@ -54,6 +55,52 @@ func TestRegionalPollen_ExposureFactor(t *testing.T) {
 	}
 }
 
+func TestRegionalPollen_RiskFor(t *testing.T) {
+	rp := &pollen.RegionalPollen{
+		Readings: map[pollen.Type]*pollen.Reading{
+			pollen.PollenTree:  {Type: pollen.PollenTree, Risk: pollen.RiskHigh, Species: []string{"Birch", "Alder"}},
+			pollen.PollenGrass: {Type: pollen.PollenGrass, Risk: pollen.RiskLow, Species: []string{"Poaceae"}},
+		},
+		OverallRisk: pollen.RiskHigh,
+	}
+
+	tests := []struct {
+		name          string
+		sensitivities []string
+		expected      pollen.RiskLevel
+	}{
+		{"no sensitivities", nil, pollen.RiskHigh},
+		{"species lower case", []string{"birch"}, pollen.RiskHigh},
+		{"species upper case", []string{"ALDER"}, pollen.RiskHigh},
+		{"species with spaces", []string{" poaceae "}, pollen.RiskLow},
+		{"type", []string{"grass"}, pollen.RiskLow},
+		{"highest match wins", []string{"Poaceae", "Birch"}, pollen.RiskHigh},
+		{"no match", []string{"Ragweed"}, pollen.RiskNone},
+		{"partial name", []string{"Bir"}, pollen.RiskNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rp.RiskFor(tt.sensitivities))
+		})
+	}
+}
+
+func TestParseExposureFactors(t *testing.T) {
+	factors, err := pollen.ParseExposureFactors("moderate=1.15, HIGH=1.4")
+	require.NoError(t, err)
+	assert.Equal(t, pollen.ExposureFactors{pollen.RiskModerate: 1.15, pollen.RiskHigh: 1.4}, factors)
+
+	factors, err = pollen.ParseExposureFactors("")
+	require.NoError(t, err)
+	assert.Empty(t, factors)
+
+	for _, raw := range []string{"HIGH", "HIGH=abc", "HIGH=0.9", "HIGH=NaN", "HIGH=+Inf", "EXTREME=1.5"} {
+		_, err := pollen.ParseExposureFactors(raw)
+		assert.ErrorIs(t, err, pollen.ErrInvalidExposureFactor, raw)
+	}
+}
+
 func TestRegionalPollen_GetReading(t *testing.T) {
 	rp := &pollen.RegionalPollen{
 		Readings: map[pollen.Type]*pollen.Reading{
@@ -2,13 +2,17 @@ package pollen
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/telemetry"
 )
 
 // Provider defines the interface for pollen data providers.
@@ -41,6 +45,20 @@ type ServiceConfig struct {
 
 	// StaleIfErrorTTL allows serving stale data on provider errors (default: 6 hours).
 	StaleIfErrorTTL time.Duration
+
+	// StaleWhileRevalidate serves expired pollen data and forecasts still
+	// within StaleIfErrorTTL at once and refreshes them in the background,
+	// instead of making the caller wait for the provider.
+	StaleWhileRevalidate bool
+
+	// MaxCacheEntries bounds each pollen and forecast cache (default: 5000).
+	// Expired entries are evicted first, then the least recently used.
+	MaxCacheEntries int
+
+	// ExposureFactors overrides the multiplier GetExposureFactor returns for
+	// each risk level (default: DefaultExposureFactors). Levels it leaves out
+	// keep their default; an invalid mapping is ignored with a warning.
+	ExposureFactors ExposureFactors
 }
 
 // Service provides pollen data with caching and feature flag control.
@@ -50,10 +68,20 @@ type Service struct {
 	logger          zerolog.Logger
 	cacheTTL        time.Duration
 	staleIfErrorTTL time.Duration
+	revalidate      bool
+	exposureFactors ExposureFactors
+
+	// Cache lookup counters, updated atomically outside mu
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+
+	// Concurrent fetches of the same cache key share one provider call
+	pollenFetches   cache.Coalescer[*RegionalPollen]
+	forecastFetches cache.Coalescer[*Forecast]
 
 	mu              sync.RWMutex
-	cache           map[string]*cachedPollen
-	forecastCache   map[string]*cachedForecast
+	cache           *cache.LRU[string, *cachedPollen]
+	forecastCache   *cache.LRU[string, *cachedForecast]
 	lastCleanup     time.Time
 	cleanupInterval time.Duration
 }
@@ -82,14 +110,30 @@ func NewService(cfg ServiceConfig) *Service {
 		staleIfErrorTTL = 6 * time.Hour
 	}
 
+	maxCacheEntries := cfg.MaxCacheEntries
+	if maxCacheEntries == 0 {
+		maxCacheEntries = 5000
+	}
+
+	exposureFactors := DefaultExposureFactors()
+	if err := cfg.ExposureFactors.Validate(); err != nil {
+		cfg.Logger.Warn().Err(err).Msg("ignoring pollen exposure factors, using defaults")
+	} else {
+		for risk, factor := range cfg.ExposureFactors {
+			exposureFactors[risk] = factor
+		}
+	}
+
 	return &Service{
 		provider:        cfg.Provider,
 		featureFlags:    cfg.FeatureFlags,
 		logger:          cfg.Logger,
 		cacheTTL:        cacheTTL,
 		staleIfErrorTTL: staleIfErrorTTL,
-		cache:           make(map[string]*cachedPollen),
-		forecastCache:   make(map[string]*cachedForecast),
+		revalidate:      cfg.StaleWhileRevalidate,
+		exposureFactors: exposureFactors,
+		cache:           cache.NewLRU[string, *cachedPollen](maxCacheEntries),
+		forecastCache:   cache.NewLRU[string, *cachedForecast](maxCacheEntries),
 		cleanupInterval: 30 * time.Minute,
 	}
 }
@@ -99,7 +143,7 @@ func NewService(cfg ServiceConfig) *Service {
 func (s *Service) GetRegionalPollen(ctx context.Context, lat, lon float64) (*RegionalPollen, error) {
 	// Check feature flag
 	if s.isPollenDisabled(ctx) {
-		s.logger.Debug().Msg("pollen factor disabled by feature flag")
+		telemetry.Logger(ctx, s.logger).Debug().Msg("pollen factor disabled by feature flag")
 		return nil, ErrPollenDisabled
 	}
 
@@ -111,11 +155,19 @@ func (s *Service) GetRegionalPollen(ctx context.Context, lat, lon float64) (*Reg
 
 	// Check cache
 	s.mu.RLock()
-	if cached, ok := s.cache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
-		s.mu.RUnlock()
+	cached, ok := s.cache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
+		return cached.data, nil
+	}
+	if ok && s.serveStale(cached.fetchedAt) {
+		s.cacheHits.Add(1)
+		s.pollenFetches.Go(ctx, cacheKey, func(ctx context.Context) (*RegionalPollen, error) {
+			return s.fetchPollenAndCache(ctx, lat, lon, cacheKey)
+		})
 		return cached.data, nil
 	}
-	s.mu.RUnlock()
 
 	// Fetch from provider
 	return s.fetchPollen(ctx, lat, lon, cacheKey)
@@ -126,7 +178,7 @@ func (s *Service) GetRegionalPollen(ctx context.Context, lat, lon float64) (*Reg
 func (s *Service) GetForecast(ctx context.Context, lat, lon float64) (*Forecast, error) {
 	// Check feature flag
 	if s.isPollenDisabled(ctx) {
-		s.logger.Debug().Msg("pollen factor disabled by feature flag")
+		telemetry.Logger(ctx, s.logger).Debug().Msg("pollen factor disabled by feature flag")
 		return nil, ErrPollenDisabled
 	}
 
@@ -138,24 +190,76 @@ func (s *Service) GetForecast(ctx context.Context, lat, lon float64) (*Forecast,
 
 	// Check cache
 	s.mu.RLock()
-	if cached, ok := s.forecastCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
-		s.mu.RUnlock()
+	cached, ok := s.forecastCache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
+		return cached.data, nil
+	}
+	if ok && s.serveStale(cached.fetchedAt) {
+		s.cacheHits.Add(1)
+		s.forecastFetches.Go(ctx, cacheKey, func(ctx context.Context) (*Forecast, error) {
+			return s.fetchForecastAndCache(ctx, lat, lon, cacheKey)
+		})
 		return cached.data, nil
 	}
-	s.mu.RUnlock()
 
 	// Fetch from provider
 	return s.fetchForecast(ctx, lat, lon, cacheKey)
 }
 
-// GetExposureFactor returns the pollen exposure factor for a location.
+// GetExposureFactor returns the pollen exposure factor for a location,
+// counting only the pollen types and species in sensitivities (see
+// RegionalPollen.RiskFor), or the overall risk if there are none.
 // Returns 1.0 (neutral) if pollen is disabled or data is unavailable.
-func (s *Service) GetExposureFactor(ctx context.Context, lat, lon float64) float64 {
+func (s *Service) GetExposureFactor(ctx context.Context, lat, lon float64, sensitivities []string) float64 {
 	data, err := s.GetRegionalPollen(ctx, lat, lon)
 	if err != nil || data == nil {
 		return 1.0
 	}
-	return data.ExposureFactor()
+	return s.exposureFactors.For(data.RiskFor(sensitivities))
+}
+
+// GetForecastExposureFactor returns the predicted pollen exposure factor
+// for a location on date's calendar day, for the given sensitivities.
+// Returns ErrNoForecastForDate if the forecast does not cover the day, and
+// ErrPollenDisabled if pollen is disabled.
+func (s *Service) GetForecastExposureFactor(ctx context.Context, lat, lon float64, date time.Time, sensitivities []string) (float64, RiskLevel, error) {
+	forecast, err := s.GetForecast(ctx, lat, lon)
+	if err != nil {
+		return 0, "", err
+	}
+
+	day := forecast.Day(date)
+	if day == nil {
+		return 0, "", ErrNoForecastForDate
+	}
+	risk := day.RiskFor(sensitivities)
+	return s.exposureFactors.For(risk), risk, nil
+}
+
+// WarmCache pre-fetches regional pollen and forecasts for the given points so
+// the first requests after startup are served from cache. Does nothing when
+// pollen is disabled; failed points are skipped and reported in the returned error.
+func (s *Service) WarmCache(ctx context.Context, points []struct{ Lat, Lon float64 }) error {
+	if s.isPollenDisabled(ctx) {
+		return nil
+	}
+
+	var errs []error
+	for _, p := range points {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := s.GetRegionalPollen(ctx, p.Lat, p.Lon); err != nil {
+			errs = append(errs, fmt.Errorf("pollen at %.4f,%.4f: %w", p.Lat, p.Lon, err))
+			continue
+		}
+		if _, err := s.GetForecast(ctx, p.Lat, p.Lon); err != nil {
+			errs = append(errs, fmt.Errorf("pollen forecast at %.4f,%.4f: %w", p.Lat, p.Lon, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // IsEnabled returns true if pollen factor is enabled.
@@ -171,17 +275,35 @@ func (s *Service) isPollenDisabled(ctx context.Context) bool {
 	return s.featureFlags.IsPollenFactorDisabled(ctx)
 }
 
-// fetchPollen fetches pollen data from provider and updates cache.
+// serveStale reports whether an expired entry fetched at fetchedAt may be
+// served while it is refreshed in the background.
+func (s *Service) serveStale(fetchedAt time.Time) bool {
+	return s.revalidate && time.Now().Before(fetchedAt.Add(s.staleIfErrorTTL))
+}
+
+// fetchPollen fetches pollen data from provider and updates cache. Concurrent
+// misses on the same key share a single provider call.
 func (s *Service) fetchPollen(ctx context.Context, lat, lon float64, cacheKey string) (*RegionalPollen, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	data, _, err := s.pollenFetches.Do(ctx, cacheKey, func(ctx context.Context) (*RegionalPollen, error) {
+		return s.fetchPollenAndCache(ctx, lat, lon, cacheKey)
+	})
+	return data, err
+}
 
-	// Double-check cache
-	if cached, ok := s.cache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
+// fetchPollenAndCache fetches the pollen data from the provider, unless a
+// fetch that just finished has cached it, and stores it in the cache.
+func (s *Service) fetchPollenAndCache(ctx context.Context, lat, lon float64, cacheKey string) (*RegionalPollen, error) {
+	// Double-check cache: a previous fetch may have completed meanwhile
+	s.mu.RLock()
+	cached, ok := s.cache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
 		return cached.data, nil
 	}
+	s.cacheMisses.Add(1)
 
-	s.logger.Debug().
+	telemetry.Logger(ctx, s.logger).Debug().
 		Float64("lat", lat).
 		Float64("lon", lon).
 		Str("provider", s.provider.Name()).
@@ -189,49 +311,60 @@ func (s *Service) fetchPollen(ctx context.Context, lat, lon float64, cacheKey st
 
 	data, err := s.provider.GetRegionalPollen(ctx, lat, lon)
 	if err != nil {
-		s.logger.Error().Err(err).
+		telemetry.Logger(ctx, s.logger).Error().Err(err).
 			Float64("lat", lat).
 			Float64("lon", lon).
 			Msg("failed to fetch pollen data")
 
 		// Check for stale data
-		if cached, ok := s.cache[cacheKey]; ok {
-			if time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-				s.logger.Warn().
-					Time("fetched_at", cached.fetchedAt).
-					Msg("serving stale pollen data due to provider error")
-				return cached.data, nil
-			}
+		if ok && time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
+			telemetry.Logger(ctx, s.logger).Warn().
+				Time("fetched_at", cached.fetchedAt).
+				Msg("serving stale pollen data due to provider error")
+			return cached.data, nil
 		}
 
-		return nil, ErrProviderUnavailable
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
 	}
 
 	// Update cache
 	now := time.Now()
-	s.cache[cacheKey] = &cachedPollen{
+	entry := &cachedPollen{
 		data:      data,
 		fetchedAt: now,
 		expiresAt: now.Add(s.cacheTTL),
 	}
-
-	// Periodic cleanup
+	s.mu.Lock()
+	s.cache.Set(cacheKey, entry, entry.expiresAt)
 	s.cleanupIfNeeded()
+	s.mu.Unlock()
 
 	return data, nil
 }
 
-// fetchForecast fetches forecast from provider and updates cache.
+// fetchForecast fetches forecast from provider and updates cache. Concurrent
+// misses on the same key share a single provider call.
 func (s *Service) fetchForecast(ctx context.Context, lat, lon float64, cacheKey string) (*Forecast, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	data, _, err := s.forecastFetches.Do(ctx, cacheKey, func(ctx context.Context) (*Forecast, error) {
+		return s.fetchForecastAndCache(ctx, lat, lon, cacheKey)
+	})
+	return data, err
+}
 
-	// Double-check cache
-	if cached, ok := s.forecastCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
+// fetchForecastAndCache fetches the forecast from the provider, unless a
+// fetch that just finished has cached it, and stores it in the cache.
+func (s *Service) fetchForecastAndCache(ctx context.Context, lat, lon float64, cacheKey string) (*Forecast, error) {
+	// Double-check cache: a previous fetch may have completed meanwhile
+	s.mu.RLock()
+	cached, ok := s.forecastCache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
 		return cached.data, nil
 	}
+	s.cacheMisses.Add(1)
 
-	s.logger.Debug().
+	telemetry.Logger(ctx, s.logger).Debug().
 		Float64("lat", lat).
 		Float64("lon", lon).
 		Str("provider", s.provider.Name()).
@@ -239,34 +372,33 @@ func (s *Service) fetchForecast(ctx context.Context, lat, lon float64, cacheKey
 
 	data, err := s.provider.GetForecast(ctx, lat, lon)
 	if err != nil {
-		s.logger.Error().Err(err).
+		telemetry.Logger(ctx, s.logger).Error().Err(err).
 			Float64("lat", lat).
 			Float64("lon", lon).
 			Msg("failed to fetch pollen forecast")
 
 		// Check for stale data
-		if cached, ok := s.forecastCache[cacheKey]; ok {
-			if time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-				s.logger.Warn().
-					Time("fetched_at", cached.fetchedAt).
-					Msg("serving stale pollen forecast due to provider error")
-				return cached.data, nil
-			}
+		if ok && time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
+			telemetry.Logger(ctx, s.logger).Warn().
+				Time("fetched_at", cached.fetchedAt).
+				Msg("serving stale pollen forecast due to provider error")
+			return cached.data, nil
 		}
 
-		return nil, ErrProviderUnavailable
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
 	}
 
 	// Update cache
 	now := time.Now()
-	s.forecastCache[cacheKey] = &cachedForecast{
+	entry := &cachedForecast{
 		data:      data,
 		fetchedAt: now,
 		expiresAt: now.Add(s.cacheTTL),
 	}
-
-	// Periodic cleanup
+	s.mu.Lock()
+	s.forecastCache.Set(cacheKey, entry, entry.expiresAt)
 	s.cleanupIfNeeded()
+	s.mu.Unlock()
 
 	return data, nil
 }
@@ -290,19 +422,13 @@ func (s *Service) cleanupIfNeeded() {
 	s.lastCleanup = now
 	expired := 0
 
-	for key, cached := range s.cache {
-		if now.After(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-			delete(s.cache, key)
-			expired++
-		}
-	}
+	expired += s.cache.DeleteFunc(func(_ string, cached *cachedPollen) bool {
+		return now.After(cached.fetchedAt.Add(s.staleIfErrorTTL))
+	})
 
-	for key, cached := range s.forecastCache {
-		if now.After(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-			delete(s.forecastCache, key)
-			expired++
-		}
-	}
+	expired += s.forecastCache.DeleteFunc(func(_ string, cached *cachedForecast) bool {
+		return now.After(cached.fetchedAt.Add(s.staleIfErrorTTL))
+	})
 
 	if expired > 0 {
 		s.logger.Debug().
@@ -315,8 +441,8 @@ func (s *Service) cleanupIfNeeded() {
 func (s *Service) InvalidateCache() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.cache = make(map[string]*cachedPollen)
-	s.forecastCache = make(map[string]*cachedForecast)
+	s.cache.Clear()
+	s.forecastCache.Clear()
 }
 
 // CacheStats returns cache statistics.
@@ -325,27 +451,29 @@ func (s *Service) CacheStats() CacheStats {
 	defer s.mu.RUnlock()
 
 	now := time.Now()
-	pollenFresh := 0
-	forecastFresh := 0
+	stats := CacheStats{
+		PollenEntries:   s.cache.Len(),
+		ForecastEntries: s.forecastCache.Len(),
+		Provider:        s.provider.Name(),
+		Hits:            s.cacheHits.Load(),
+		Misses:          s.cacheMisses.Load(),
+		Evictions:       s.cache.Evictions() + s.forecastCache.Evictions(),
+	}
 
-	for _, c := range s.cache {
-		if now.Before(c.expiresAt) {
-			pollenFresh++
+	s.cache.Range(func(_ string, c *cachedPollen) bool {
+		if stats.observe(now, c.fetchedAt, c.expiresAt) {
+			stats.PollenFreshEntries++
 		}
-	}
-	for _, c := range s.forecastCache {
-		if now.Before(c.expiresAt) {
-			forecastFresh++
+		return true
+	})
+	s.forecastCache.Range(func(_ string, c *cachedForecast) bool {
+		if stats.observe(now, c.fetchedAt, c.expiresAt) {
+			stats.ForecastFreshEntries++
 		}
-	}
+		return true
+	})
 
-	return CacheStats{
-		PollenEntries:        len(s.cache),
-		PollenFreshEntries:   pollenFresh,
-		ForecastEntries:      len(s.forecastCache),
-		ForecastFreshEntries: forecastFresh,
-		Provider:             s.provider.Name(),
-	}
+	return stats
 }
 
 // CacheStats contains cache statistics.
@@ -355,6 +483,44 @@ type CacheStats struct {
 	ForecastEntries      int
 	ForecastFreshEntries int
 	Provider             string
+
+	// OldestFreshAt is when the oldest unexpired entry was fetched, and
+	// NewestFetchedAt when the newest entry was. Both are zero if there is
+	// no such entry.
+	OldestFreshAt   time.Time
+	NewestFetchedAt time.Time
+
+	// Hits and Misses count cache lookups since the service started.
+	Hits   uint64
+	Misses uint64
+
+	// Evictions counts entries dropped to stay within MaxCacheEntries.
+	Evictions uint64
+}
+
+// observe adds an entry's fetch time to the stats, reporting whether the
+// entry is still fresh.
+func (c *CacheStats) observe(now, fetchedAt, expiresAt time.Time) bool {
+	if fetchedAt.After(c.NewestFetchedAt) {
+		c.NewestFetchedAt = fetchedAt
+	}
+	if !now.Before(expiresAt) {
+		return false
+	}
+	if c.OldestFreshAt.IsZero() || fetchedAt.Before(c.OldestFreshAt) {
+		c.OldestFreshAt = fetchedAt
+	}
+	return true
+}
+
+// HitRatio returns the fraction of cache lookups served from the cache,
+// or 0 if there have been none.
+func (c CacheStats) HitRatio() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
 }
 
 // validateCoordinates checks if coordinates are valid.
@@ -22,6 +22,7 @@ type mockProvider struct {
 	data      *pollen.RegionalPollen
 	forecast  *pollen.Forecast
 	err       error
+	delay     time.Duration // simulates a slow provider
 }
 
 func newMockProvider() *mockProvider {
@@ -38,9 +39,10 @@ func newMockProvider() *mockProvider {
 					Risk:  pollen.RiskModerate,
 				},
 				pollen.PollenTree: {
-					Type:  pollen.PollenTree,
-					Index: 1.0,
-					Risk:  pollen.RiskLow,
+					Type:    pollen.PollenTree,
+					Index:   1.0,
+					Risk:    pollen.RiskLow,
+					Species: []string{"Birch", "Oak"},
 				},
 			},
 			OverallRisk:  pollen.RiskModerate,
@@ -75,6 +77,7 @@ func (m *mockProvider) Name() string {
 }
 
 func (m *mockProvider) GetRegionalPollen(_ context.Context, _, _ float64) (*pollen.RegionalPollen, error) {
+	time.Sleep(m.delay)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.callCount++
@@ -293,15 +296,46 @@ func TestService_GetExposureFactor(t *testing.T) {
 	})
 
 	// Normal case - should return factor based on risk
-	factor := service.GetExposureFactor(context.Background(), 52.370, 4.895)
+	factor := service.GetExposureFactor(context.Background(), 52.370, 4.895, nil)
 	assert.Equal(t, 1.1, factor) // RiskModerate = 1.1
 
 	// Error case - should return 1.0
 	provider.setError(errors.New("api error"))
-	factor = service.GetExposureFactor(context.Background(), 53.0, 5.0) // Different location
+	factor = service.GetExposureFactor(context.Background(), 53.0, 5.0, nil) // Different location
 	assert.Equal(t, 1.0, factor)
 }
 
+func TestService_GetExposureFactor_Sensitivities(t *testing.T) {
+	service := pollen.NewService(pollen.ServiceConfig{
+		Provider: newMockProvider(),
+		Logger:   zerolog.Nop(),
+	})
+	ctx := context.Background()
+
+	// Only the low tree reading counts for a birch allergy
+	assert.Equal(t, 1.05, service.GetExposureFactor(ctx, 52.370, 4.895, []string{"birch"}))
+	assert.Equal(t, 1.1, service.GetExposureFactor(ctx, 52.370, 4.895, []string{"Grass"}))
+	assert.Equal(t, 1.0, service.GetExposureFactor(ctx, 52.370, 4.895, []string{"Ragweed"}))
+	assert.Equal(t, 1.1, service.GetExposureFactor(ctx, 52.370, 4.895, []string{}))
+}
+
+func TestService_GetExposureFactor_CustomMapping(t *testing.T) {
+	service := pollen.NewService(pollen.ServiceConfig{
+		Provider:        newMockProvider(),
+		Logger:          zerolog.Nop(),
+		ExposureFactors: pollen.ExposureFactors{pollen.RiskModerate: 1.4},
+	})
+	assert.Equal(t, 1.4, service.GetExposureFactor(context.Background(), 52.370, 4.895, nil))
+
+	// An invalid mapping is ignored in favour of the defaults
+	service = pollen.NewService(pollen.ServiceConfig{
+		Provider:        newMockProvider(),
+		Logger:          zerolog.Nop(),
+		ExposureFactors: pollen.ExposureFactors{pollen.RiskModerate: 0.8},
+	})
+	assert.Equal(t, 1.1, service.GetExposureFactor(context.Background(), 52.370, 4.895, nil))
+}
+
 func TestService_IsEnabled(t *testing.T) {
 	provider := newMockProvider()
 
@@ -380,3 +414,97 @@ func TestService_CacheStats(t *testing.T) {
 	assert.Equal(t, 1, stats.PollenFreshEntries)
 	assert.Equal(t, 1, stats.ForecastFreshEntries)
 }
+
+func TestService_CacheStats_HitsAndMisses(t *testing.T) {
+	provider := newMockProvider()
+	service := pollen.NewService(pollen.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: 1 * time.Hour,
+	})
+
+	assert.Zero(t, service.CacheStats().HitRatio())
+
+	for i := 0; i < 3; i++ {
+		_, err := service.GetRegionalPollen(context.Background(), 52.370, 4.895)
+		require.NoError(t, err)
+	}
+
+	stats := service.CacheStats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.InDelta(t, 2.0/3.0, stats.HitRatio(), 0.0001)
+}
+
+func TestService_GetRegionalPollen_ConcurrentRequests(t *testing.T) {
+	provider := newMockProvider()
+	provider.delay = 50 * time.Millisecond
+	service := pollen.NewService(pollen.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.GetRegionalPollen(context.Background(), 52.370, 4.895)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Concurrent misses on the same key share a single provider call
+	assert.Equal(t, 1, provider.getCallCount())
+}
+
+func TestService_GetRegionalPollen_StaleWhileRevalidate(t *testing.T) {
+	provider := newMockProvider()
+	service := pollen.NewService(pollen.ServiceConfig{
+		Provider:             provider,
+		Logger:               zerolog.Nop(),
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Hour,
+		StaleWhileRevalidate: true,
+	})
+
+	first, err := service.GetRegionalPollen(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+
+	time.Sleep(70 * time.Millisecond)
+	provider.delay = 100 * time.Millisecond
+	provider.setError(errors.New("api error"))
+
+	// Expired data is served at once; the failed refresh does not replace it
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		data, err := service.GetRegionalPollen(context.Background(), 52.370, 4.895)
+		require.NoError(t, err)
+		assert.Same(t, first, data)
+	}
+	assert.Less(t, time.Since(start), provider.delay)
+	assert.Eventually(t, func() bool { return provider.getCallCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	data, err := service.GetRegionalPollen(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+	assert.Same(t, first, data)
+}
+
+func TestService_WarmCache(t *testing.T) {
+	provider := newMockProvider()
+	service := pollen.NewService(pollen.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	err := service.WarmCache(context.Background(), []struct{ Lat, Lon float64 }{{52.3676, 4.9041}})
+	require.NoError(t, err)
+	calls := provider.getCallCount()
+	assert.Equal(t, 2, calls)
+
+	// Subsequent requests are served from cache
+	_, err = service.GetRegionalPollen(context.Background(), 52.3676, 4.9041)
+	require.NoError(t, err)
+	assert.Equal(t, calls, provider.getCallCount())
+}
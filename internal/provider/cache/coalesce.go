@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Coalescer merges concurrent fetches of the same key into a single call,
+// whose result every waiting caller shares. It keeps a cold or expired cache
+// entry from sending one provider request per concurrent caller.
+//
+// The shared call runs with a context detached from the first caller's
+// cancellation, so one client giving up does not fail the others; provider
+// timeouts still bound it. Each caller stops waiting when its own context
+// ends. The zero value is ready to use.
+type Coalescer[V any] struct {
+	group singleflight.Group
+}
+
+// Do returns the result of fetch for key, calling it only if no call for key
+// is already in flight. shared reports whether the result was also given to
+// other callers.
+func (c *Coalescer[V]) Do(ctx context.Context, key string, fetch func(ctx context.Context) (V, error)) (v V, shared bool, err error) {
+	detached := context.WithoutCancel(ctx)
+	ch := c.group.DoChan(key, func() (any, error) {
+		return fetch(detached)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return v, res.Shared, res.Err
+		}
+		return res.Val.(V), res.Shared, nil
+	case <-ctx.Done():
+		return v, false, ctx.Err()
+	}
+}
+
+// Go starts fetch for key in the background, unless a call for key is
+// already in flight, so a cache entry can be refreshed without making the
+// caller wait. The call is detached from ctx's cancellation.
+func (c *Coalescer[V]) Go(ctx context.Context, key string, fetch func(ctx context.Context) (V, error)) {
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		_, _, _ = c.Do(detached, key, fetch)
+	}()
+}
@@ -0,0 +1,119 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+)
+
+func TestCoalescer_SharesConcurrentCalls(t *testing.T) {
+	var c cache.Coalescer[string]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _, err := c.Do(context.Background(), "key", func(context.Context) (string, error) {
+				calls.Add(1)
+				<-release
+				return "value", nil
+			})
+			assert.NoError(t, err)
+			results <- v
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond) // let every caller join the in-flight call
+	close(release)
+	wg.Wait()
+	close(results)
+
+	assert.Equal(t, int32(1), calls.Load())
+	for v := range results {
+		assert.Equal(t, "value", v)
+	}
+}
+
+func TestCoalescer_SeparateKeys(t *testing.T) {
+	var c cache.Coalescer[int]
+	for i, key := range []string{"a", "b"} {
+		v, shared, err := c.Do(context.Background(), key, func(context.Context) (int, error) {
+			return i, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+		assert.False(t, shared)
+	}
+}
+
+func TestCoalescer_Error(t *testing.T) {
+	var c cache.Coalescer[*int]
+	errFetch := errors.New("provider down")
+
+	v, _, err := c.Do(context.Background(), "key", func(context.Context) (*int, error) {
+		return nil, errFetch
+	})
+	assert.ErrorIs(t, err, errFetch)
+	assert.Nil(t, v)
+}
+
+func TestCoalescer_CallerCancellationDoesNotCancelFetch(t *testing.T) {
+	var c cache.Coalescer[string]
+	fetchErr := make(chan error, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := c.Do(ctx, "key", func(ctx context.Context) (string, error) {
+		time.Sleep(60 * time.Millisecond)
+		fetchErr <- ctx.Err()
+		return "value", nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "caller should stop waiting at its deadline")
+
+	assert.NoError(t, <-fetchErr, "the shared fetch should not see the caller's cancellation")
+}
+
+func TestCoalescer_GoRunsInBackground(t *testing.T) {
+	var c cache.Coalescer[string]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Go(ctx, "key", fetch)
+	cancel()
+	time.Sleep(20 * time.Millisecond) // let the background call start
+
+	// A later call joins the background one, which survived the cancellation
+	c.Go(context.Background(), "key", fetch)
+	done := make(chan string)
+	go func() {
+		v, _, err := c.Do(context.Background(), "key", fetch)
+		assert.NoError(t, err)
+		done <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, "value", <-done)
+	assert.Equal(t, int32(1), calls.Load())
+}
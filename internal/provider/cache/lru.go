@@ -0,0 +1,189 @@
+// Package cache provides in-memory caches for provider data.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a concurrency-safe map bounded to a maximum number of entries.
+//
+// When a new entry would exceed the bound, expired entries are evicted first
+// (oldest expiry first), and only if none have expired is the least recently
+// used entry evicted. Fresh entries are therefore never evicted while stale
+// ones remain.
+type LRU[K comparable, V any] struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Front is most recently used
+	items      map[K]*list.Element
+	evictions  uint64
+
+	// earliestExpiry is a lower bound on the expiry of all entries; scanning
+	// for expired entries is skipped until it has passed.
+	earliestExpiry time.Time
+}
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU creates an LRU cache holding at most maxEntries entries.
+// A maxEntries of zero or less means the cache is unbounded.
+func NewLRU[K comparable, V any](maxEntries int) *LRU[K, V] {
+	return &LRU[K, V]{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value for key and marks it as recently used.
+// Expired entries are returned too; callers decide whether stale data is usable.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// Set stores value under key with the time it expires, evicting entries if
+// the cache is full.
+func (c *LRU[K, V]) Set(key K, value V, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.earliestExpiry.IsZero() || expiresAt.Before(c.earliestExpiry) {
+		c.earliestExpiry = expiresAt
+	}
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.maxEntries > 0 && len(c.items) >= c.maxEntries {
+		c.evict(time.Now())
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+}
+
+// Delete removes key from the cache.
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// DeleteFunc removes every entry for which fn returns true and returns the
+// number removed. fn must not call methods on the cache.
+func (c *LRU[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		entry := elem.Value.(*lruEntry[K, V])
+		if fn(entry.key, entry.value) {
+			c.remove(elem)
+			removed++
+		}
+		elem = prev
+	}
+	return removed
+}
+
+// Range calls fn for each entry, most recently used first, until fn returns
+// false. It does not change recency. fn must not call methods on the cache.
+func (c *LRU[K, V]) Range(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*lruEntry[K, V])
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// Len returns the number of entries.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Clear removes all entries. The eviction count is kept.
+func (c *LRU[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = make(map[K]*list.Element)
+	c.earliestExpiry = time.Time{}
+}
+
+// Evictions returns the number of entries evicted to stay within the bound.
+func (c *LRU[K, V]) Evictions() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
+
+// evict makes room for one entry: the entry that expired longest ago if any
+// have expired, otherwise the least recently used. Caller must hold c.mu.
+func (c *LRU[K, V]) evict(now time.Time) {
+	victim := c.order.Back()
+
+	if !c.earliestExpiry.IsZero() && !now.Before(c.earliestExpiry) {
+		var oldest *list.Element
+		var earliest time.Time
+		for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+			expiresAt := elem.Value.(*lruEntry[K, V]).expiresAt
+			if now.Before(expiresAt) {
+				// Track the earliest remaining expiry for the next scan
+				if earliest.IsZero() || expiresAt.Before(earliest) {
+					earliest = expiresAt
+				}
+				continue
+			}
+			if oldest == nil || expiresAt.Before(oldest.Value.(*lruEntry[K, V]).expiresAt) {
+				oldest = elem
+			}
+		}
+		if oldest != nil {
+			victim = oldest
+		} else {
+			c.earliestExpiry = earliest
+		}
+	}
+
+	if victim != nil {
+		c.remove(victim)
+		c.evictions++
+	}
+}
+
+// remove unlinks elem from the cache. Caller must hold c.mu.
+func (c *LRU[K, V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry[K, V]).key)
+}
@@ -0,0 +1,129 @@
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU[string, int](2)
+	fresh := time.Now().Add(time.Hour)
+
+	c.Set("a", 1, fresh)
+	c.Set("b", 2, fresh)
+
+	// Touch "a" so "b" becomes least recently used
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	c.Set("c", 3, fresh)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, uint64(1), c.Evictions())
+}
+
+func TestLRU_EvictsExpiredBeforeFresh(t *testing.T) {
+	c := cache.NewLRU[string, int](3)
+	now := time.Now()
+
+	c.Set("fresh-old", 1, now.Add(time.Hour))
+	c.Set("stale", 2, now.Add(-time.Minute))
+	c.Set("staler", 3, now.Add(-time.Hour))
+
+	// "fresh-old" is least recently used, but stale entries go first,
+	// oldest expiry first
+	c.Set("new", 4, now.Add(time.Hour))
+	_, ok := c.Get("staler")
+	assert.False(t, ok)
+
+	c.Set("newer", 5, now.Add(time.Hour))
+	_, ok = c.Get("stale")
+	assert.False(t, ok)
+
+	_, ok = c.Get("fresh-old")
+	assert.True(t, ok, "fresh entry should survive while stale entries remain")
+	assert.Equal(t, uint64(2), c.Evictions())
+}
+
+func TestLRU_UpdateDoesNotEvict(t *testing.T) {
+	c := cache.NewLRU[string, int](1)
+	fresh := time.Now().Add(time.Hour)
+
+	c.Set("a", 1, fresh)
+	c.Set("a", 2, fresh)
+
+	v, ok := c.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Zero(t, c.Evictions())
+}
+
+func TestLRU_Unbounded(t *testing.T) {
+	c := cache.NewLRU[int, int](0)
+	fresh := time.Now().Add(time.Hour)
+
+	for i := 0; i < 100; i++ {
+		c.Set(i, i, fresh)
+	}
+
+	assert.Equal(t, 100, c.Len())
+	assert.Zero(t, c.Evictions())
+}
+
+func TestLRU_DeleteFuncAndClear(t *testing.T) {
+	c := cache.NewLRU[int, int](0)
+	fresh := time.Now().Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		c.Set(i, i, fresh)
+	}
+
+	removed := c.DeleteFunc(func(_ int, v int) bool { return v%2 == 0 })
+	assert.Equal(t, 5, removed)
+	assert.Equal(t, 5, c.Len())
+
+	visited := 0
+	c.Range(func(_ int, v int) bool {
+		assert.Equal(t, 1, v%2)
+		visited++
+		return true
+	})
+	assert.Equal(t, 5, visited)
+
+	c.Clear()
+	assert.Zero(t, c.Len())
+}
+
+func TestLRU_ConcurrentAccess(t *testing.T) {
+	c := cache.NewLRU[string, int](50)
+	fresh := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("%d-%d", g, i)
+				c.Set(key, i, fresh)
+				c.Get(key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, c.Len())
+	assert.Equal(t, uint64(8*500-50), c.Evictions())
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var (
+	// ErrSnapshotNotFound is returned by SnapshotStore.Load when nothing is
+	// stored under the key.
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrSnapshotStale is returned by services restoring a snapshot that is
+	// too old to serve, even as stale-if-error data.
+	ErrSnapshotStale = errors.New("snapshot too old to restore")
+
+	// ErrInvalidSnapshotKey is returned for keys that are not a plain name.
+	ErrInvalidSnapshotKey = errors.New("invalid snapshot key")
+)
+
+// snapshotKeyPattern restricts keys to names that are safe as file names.
+var snapshotKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// SnapshotStore persists provider snapshots so that in-memory caches can be
+// restored after a restart instead of starting cold. Values are stored as
+// JSON, so only exported fields survive a round trip.
+type SnapshotStore interface {
+	// Load decodes the snapshot stored under key into v. It returns
+	// ErrSnapshotNotFound if there is none.
+	Load(ctx context.Context, key string, v any) error
+
+	// Save stores v under key, replacing any previous snapshot.
+	Save(ctx context.Context, key string, v any) error
+}
+
+// FileStore is a SnapshotStore keeping one JSON file per key in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a FileStore in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load reads the snapshot stored under key.
+func (s *FileStore) Load(_ context.Context, key string, v any) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is built from a validated key
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrSnapshotNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("reading snapshot %s: %w", key, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+// Save writes the snapshot to a temporary file and renames it into place,
+// so a crash mid-write never leaves a truncated snapshot behind.
+func (s *FileStore) Save(_ context.Context, key string, v any) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing snapshot %s: %w", key, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() // fails harmlessly once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing snapshot %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing snapshot %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+// path returns the file for key.
+func (s *FileStore) path(key string) (string, error) {
+	if !snapshotKeyPattern.MatchString(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSnapshotKey, key)
+	}
+	return filepath.Join(s.dir, key+".json"), nil
+}
+
+// NopStore is a SnapshotStore that stores nothing.
+type NopStore struct{}
+
+// Load always returns ErrSnapshotNotFound.
+func (NopStore) Load(context.Context, string, any) error {
+	return ErrSnapshotNotFound
+}
+
+// Save discards the snapshot.
+func (NopStore) Save(context.Context, string, any) error {
+	return nil
+}
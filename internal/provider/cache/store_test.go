@@ -0,0 +1,76 @@
+package cache_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+)
+
+type storedValue struct {
+	Name      string
+	Count     int
+	FetchedAt time.Time
+}
+
+func TestFileStore_SaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	store, err := cache.NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	fetchedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(ctx, "airquality-snapshot", storedValue{Name: "first", Count: 1, FetchedAt: fetchedAt}))
+	require.NoError(t, store.Save(ctx, "airquality-snapshot", storedValue{Name: "second", Count: 2, FetchedAt: fetchedAt}))
+
+	var got storedValue
+	require.NoError(t, store.Load(ctx, "airquality-snapshot", &got))
+	assert.Equal(t, "second", got.Name)
+	assert.Equal(t, 2, got.Count)
+	assert.True(t, fetchedAt.Equal(got.FetchedAt))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestFileStore_LoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	store, err := cache.NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var v storedValue
+	assert.ErrorIs(t, store.Load(ctx, "missing", &v), cache.ErrSnapshotNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{not json"), 0o600))
+	err = store.Load(ctx, "corrupt", &v)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, cache.ErrSnapshotNotFound)
+}
+
+func TestFileStore_InvalidKey(t *testing.T) {
+	store, err := cache.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape", "a/b", "Upper", ".hidden"} {
+		assert.ErrorIs(t, store.Save(ctx, key, storedValue{}), cache.ErrInvalidSnapshotKey, key)
+		assert.ErrorIs(t, store.Load(ctx, key, &storedValue{}), cache.ErrInvalidSnapshotKey, key)
+	}
+}
+
+func TestNopStore(t *testing.T) {
+	var store cache.SnapshotStore = cache.NopStore{}
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "key", storedValue{Name: "discarded"}))
+	assert.ErrorIs(t, store.Load(ctx, "key", &storedValue{}), cache.ErrSnapshotNotFound)
+}
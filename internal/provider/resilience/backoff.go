@@ -0,0 +1,50 @@
+package resilience
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// JitterBackOff is an exponential backoff with full jitter: each retry waits a
+// random delay between zero and a ceiling that starts at the base interval and
+// grows by a multiplier up to a maximum. Randomizing the whole delay spreads
+// out clients that failed together, so they do not retry in lockstep against
+// a recovering provider.
+type JitterBackOff struct {
+	base       time.Duration
+	max        time.Duration
+	multiplier float64
+	ceiling    time.Duration
+}
+
+// NewJitterBackOff creates a JitterBackOff. A multiplier below 1 is treated
+// as 1, keeping the ceiling at base.
+func NewJitterBackOff(base, maxInterval time.Duration, multiplier float64) *JitterBackOff {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	b := &JitterBackOff{base: base, max: maxInterval, multiplier: multiplier}
+	b.Reset()
+	return b
+}
+
+// NextBackOff returns a random delay in [0, ceiling] and raises the ceiling
+// for the next retry.
+func (b *JitterBackOff) NextBackOff() time.Duration {
+	ceiling := b.ceiling
+	if next := time.Duration(float64(b.ceiling) * b.multiplier); next < b.max {
+		b.ceiling = next
+	} else {
+		b.ceiling = b.max
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1) //nolint:gosec // jitter needs no cryptographic randomness
+}
+
+// Reset returns the ceiling to the base interval.
+func (b *JitterBackOff) Reset() {
+	b.ceiling = min(b.base, b.max)
+}
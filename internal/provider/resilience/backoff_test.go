@@ -0,0 +1,90 @@
+package resilience_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+)
+
+func TestJitterBackOff_IntervalsWithinBounds(t *testing.T) {
+	ceilings := []time.Duration{
+		100 * time.Millisecond,
+		300 * time.Millisecond,
+		900 * time.Millisecond,
+		time.Second, // capped at max
+		time.Second,
+	}
+
+	seen := make(map[time.Duration]bool)
+	for run := 0; run < 200; run++ {
+		bo := resilience.NewJitterBackOff(100*time.Millisecond, time.Second, 3)
+		for i, ceiling := range ceilings {
+			d := bo.NextBackOff()
+			assert.GreaterOrEqual(t, d, time.Duration(0), "retry %d", i+1)
+			assert.LessOrEqual(t, d, ceiling, "retry %d", i+1)
+			seen[d] = true
+		}
+	}
+	assert.Greater(t, len(seen), 100, "delays should be randomized, not fixed")
+}
+
+func TestJitterBackOff_Reset(t *testing.T) {
+	bo := resilience.NewJitterBackOff(10*time.Millisecond, time.Second, 2)
+	for i := 0; i < 10; i++ {
+		bo.NextBackOff()
+	}
+
+	bo.Reset()
+	for i := 0; i < 50; i++ {
+		bo.Reset()
+		assert.LessOrEqual(t, bo.NextBackOff(), 10*time.Millisecond)
+	}
+}
+
+func TestJitterBackOff_MultiplierBelowOne(t *testing.T) {
+	bo := resilience.NewJitterBackOff(10*time.Millisecond, time.Second, 0.5)
+	for i := 0; i < 20; i++ {
+		assert.LessOrEqual(t, bo.NextBackOff(), 10*time.Millisecond)
+	}
+}
+
+func TestClient_JitteredRetriesRespectMaxAttempts(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cbConfig := resilience.DefaultCircuitBreakerConfig("test-jitter")
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:            "test-jitter",
+		MaxRetries:      4,
+		InitialInterval: 20 * time.Millisecond,
+		MaxInterval:     40 * time.Millisecond,
+		Multiplier:      2,
+		CircuitBreaker:  &cbConfig,
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(5), attempts.Load(), "initial attempt plus MaxRetries")
+	// Ceilings are 20ms, 40ms, 40ms, 40ms, so the waits total at most 140ms
+	assert.Less(t, time.Since(start), time.Second)
+}
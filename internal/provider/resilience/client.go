@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/rs/zerolog"
 	"github.com/sony/gobreaker/v2"
 )
 
@@ -17,6 +20,14 @@ var (
 
 	// ErrMaxRetriesExceeded is returned when all retry attempts have been exhausted.
 	ErrMaxRetriesExceeded = errors.New("max retries exceeded")
+
+	// ErrRateLimited marks an attempt that got a 429 response. It is retried,
+	// and the last 429 response is returned once retries are exhausted.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrBulkheadFull is returned when MaxConcurrent requests are already in
+	// flight and the client fails fast instead of queueing.
+	ErrBulkheadFull = errors.New("too many concurrent requests")
 )
 
 // ClientConfig holds configuration for the resilient HTTP client.
@@ -32,14 +43,39 @@ type ClientConfig struct {
 	// Default: 3
 	MaxRetries uint64
 
-	// InitialInterval is the initial retry backoff interval.
+	// InitialInterval is the backoff ceiling for the first retry. Each retry
+	// waits a random delay up to the current ceiling (full jitter).
 	// Default: 100ms
 	InitialInterval time.Duration
 
-	// MaxInterval is the maximum retry backoff interval.
+	// MaxInterval is the maximum retry backoff ceiling.
 	// Default: 5 seconds
 	MaxInterval time.Duration
 
+	// Multiplier is the factor the backoff ceiling grows by after each retry.
+	// Default: 2
+	Multiplier float64
+
+	// MaxRetryAfter caps the delay honored from a Retry-After header on 429
+	// and 503 responses. A longer Retry-After is waited out for this long.
+	// Default: 10 seconds
+	MaxRetryAfter time.Duration
+
+	// MaxConcurrent caps the requests in flight to the provider at once (the
+	// bulkhead), so a burst of traffic cannot open hundreds of connections.
+	// Requests over the limit wait for a free slot until their context ends,
+	// or fail with ErrBulkheadFull if FailFast is set. Each retry attempt
+	// takes its own slot, released while backing off.
+	// Default: 0 (unlimited)
+	MaxConcurrent int
+
+	// FailFast rejects requests over MaxConcurrent at once instead of
+	// queueing them.
+	FailFast bool
+
+	// Logger receives a message whenever a retry is delayed by Retry-After.
+	Logger zerolog.Logger
+
 	// CircuitBreaker is the circuit breaker configuration.
 	// If nil, uses DefaultCircuitBreakerConfig.
 	CircuitBreaker *CircuitBreakerConfig
@@ -58,6 +94,8 @@ func DefaultClientConfig(name string) ClientConfig {
 		MaxRetries:      3,
 		InitialInterval: 100 * time.Millisecond,
 		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+		MaxRetryAfter:   10 * time.Second,
 		CircuitBreaker:  &cbConfig,
 	}
 }
@@ -68,6 +106,10 @@ type Client struct {
 	circuitBreaker *gobreaker.CircuitBreaker[*http.Response]
 	config         ClientConfig
 	registry       *Registry
+
+	// slots holds a token per in-flight request; nil when unlimited
+	slots    chan struct{}
+	inFlight atomic.Int64
 }
 
 // NewClient creates a new resilient HTTP client.
@@ -85,6 +127,12 @@ func NewClient(cfg ClientConfig) *Client {
 	if cfg.MaxInterval == 0 {
 		cfg.MaxInterval = 5 * time.Second
 	}
+	if cfg.Multiplier == 0 {
+		cfg.Multiplier = 2
+	}
+	if cfg.MaxRetryAfter == 0 {
+		cfg.MaxRetryAfter = 10 * time.Second
+	}
 
 	// Create circuit breaker
 	var cb *gobreaker.CircuitBreaker[*http.Response]
@@ -103,6 +151,9 @@ func NewClient(cfg ClientConfig) *Client {
 		config:         cfg,
 		registry:       cfg.Registry,
 	}
+	if cfg.MaxConcurrent > 0 {
+		client.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
 
 	// Register with registry if provided
 	if cfg.Registry != nil {
@@ -113,27 +164,49 @@ func NewClient(cfg ClientConfig) *Client {
 }
 
 // Do executes an HTTP request with circuit breaker protection and retry logic.
-// The request is retried on transient failures (5xx, network errors) with exponential backoff.
-// Returns immediately with ErrCircuitOpen if the circuit breaker is open.
+// The request is retried on transient failures (5xx, 429, network errors) with
+// jittered exponential backoff, or after the delay in a Retry-After header if the
+// response has one (up to MaxRetryAfter). 429s do not count against the
+// circuit breaker. Returns immediately with ErrCircuitOpen if the circuit
+// breaker is open.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return c.DoWithContext(req.Context(), req)
 }
 
 // DoWithContext executes an HTTP request with the given context.
+// Each call is traced as one client span covering all retry attempts.
 func (c *Client) DoWithContext(ctx context.Context, req *http.Request) (*http.Response, error) {
-	// Create exponential backoff
-	bo := backoff.NewExponentialBackOff()
-	bo.InitialInterval = c.config.InitialInterval
-	bo.MaxInterval = c.config.MaxInterval
-	bo.MaxElapsedTime = 0 // Unlimited, we control retries via WithMaxRetries
-
-	// Wrap with max retries and context
-	backoffWithRetries := backoff.WithMaxRetries(bo, c.config.MaxRetries)
+	ctx, span := c.startSpan(ctx, req)
+
+	// Create jittered exponential backoff; retries are limited by WithMaxRetries
+	bo := NewJitterBackOff(c.config.InitialInterval, c.config.MaxInterval, c.config.Multiplier)
+
+	// Wrap with Retry-After, max retries and context
+	retryAfter := &retryAfterBackOff{BackOff: bo}
+	backoffWithRetries := backoff.WithMaxRetries(retryAfter, c.config.MaxRetries)
 	backoffWithContext := backoff.WithContext(backoffWithRetries, ctx)
 
 	var lastResp *http.Response
+	attempts := 0
+
+	// keep replaces the last response, closing the one it supersedes
+	keep := func(resp *http.Response) {
+		if lastResp != nil && lastResp != resp {
+			_ = lastResp.Body.Close()
+		}
+		lastResp = resp
+	}
 
 	operation := func() error {
+		attempts++
+		retryAfter.next = 0
+
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		defer release()
+
 		// Execute through circuit breaker
 		// Note: 5xx errors are returned as errors to trip the circuit breaker
 		resp, err := c.circuitBreaker.Execute(func() (*http.Response, error) { //nolint:bodyclose // caller is responsible for closing
@@ -163,13 +236,22 @@ func (c *Client) DoWithContext(ctx context.Context, req *http.Request) (*http.Re
 
 			// Store response if available (5xx case)
 			if resp != nil {
-				lastResp = resp
+				keep(resp)
+				if resp.StatusCode == http.StatusServiceUnavailable {
+					c.honorRetryAfter(retryAfter, resp, attempts)
+				}
 			}
 			// Network and server errors are retryable
 			return err
 		}
 
-		lastResp = resp
+		keep(resp)
+
+		// Rate limited: retryable, but the provider is healthy
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.honorRetryAfter(retryAfter, resp, attempts)
+			return ErrRateLimited
+		}
 
 		// Success or client error (not retryable)
 		return nil
@@ -177,14 +259,16 @@ func (c *Client) DoWithContext(ctx context.Context, req *http.Request) (*http.Re
 
 	err := backoff.Retry(operation, backoffWithContext)
 	if err != nil {
-		// Record failure in registry
-		if c.registry != nil {
+		// Record failure in registry; a full bulkhead is our limit, not the provider's
+		if c.registry != nil && !errors.Is(err, ErrBulkheadFull) {
 			c.registry.RecordFailure(c.config.Name, err)
 		}
 		// If we have a last response (e.g., 5xx that exhausted retries), return it
 		if lastResp != nil {
+			endSpan(span, lastResp, attempts, nil)
 			return lastResp, nil
 		}
+		endSpan(span, nil, attempts, err)
 		return nil, err
 	}
 
@@ -193,9 +277,107 @@ func (c *Client) DoWithContext(ctx context.Context, req *http.Request) (*http.Re
 		c.registry.RecordSuccess(c.config.Name)
 	}
 
+	endSpan(span, lastResp, attempts, nil)
 	return lastResp, nil
 }
 
+// acquire takes a request slot, waiting for one to free up unless FailFast is
+// set. The returned func releases the slot.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.slots != nil {
+		if c.config.FailFast {
+			select {
+			case c.slots <- struct{}{}:
+			default:
+				return nil, ErrBulkheadFull
+			}
+		} else {
+			select {
+			case c.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	c.inFlight.Add(1)
+	return func() {
+		c.inFlight.Add(-1)
+		if c.slots != nil {
+			<-c.slots
+		}
+	}, nil
+}
+
+// InFlight returns the number of requests currently awaiting a provider
+// response.
+func (c *Client) InFlight() int64 {
+	return c.inFlight.Load()
+}
+
+// MaxConcurrent returns the in-flight request limit, or 0 if unlimited.
+func (c *Client) MaxConcurrent() int {
+	return c.config.MaxConcurrent
+}
+
+// honorRetryAfter schedules the next retry after the delay in resp's
+// Retry-After header, capped at MaxRetryAfter. Responses without a valid
+// header keep the exponential backoff.
+func (c *Client) honorRetryAfter(bo *retryAfterBackOff, resp *http.Response, attempt int) {
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return
+	}
+
+	capped := min(delay, c.config.MaxRetryAfter)
+	bo.next = capped
+	c.config.Logger.Info().
+		Str("client", c.config.Name).
+		Int("status", resp.StatusCode).
+		Int("attempt", attempt).
+		Dur("retry_after", delay).
+		Dur("delay", capped).
+		Msg("honoring Retry-After before retrying")
+}
+
+// parseRetryAfter parses a Retry-After header, given either as seconds or as
+// an HTTP date, into a delay from now. Dates in the past give a zero delay.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryAfterBackOff is a backoff whose next interval can be
+// replaced by a server-requested Retry-After delay.
+type retryAfterBackOff struct {
+	backoff.BackOff
+
+	// next is the Retry-After delay for the upcoming retry, or 0 to use the
+	// exponential interval.
+	next time.Duration
+}
+
+// NextBackOff returns the Retry-After delay if one is set, advancing the
+// exponential backoff either way so later retries keep growing.
+func (b *retryAfterBackOff) NextBackOff() time.Duration {
+	interval := b.BackOff.NextBackOff()
+	if b.next > 0 && interval != backoff.Stop {
+		interval = b.next
+	}
+	return interval
+}
+
 // ServerError represents an HTTP 5xx server error.
 type ServerError struct {
 	StatusCode int
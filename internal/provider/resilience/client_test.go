@@ -1,13 +1,16 @@
 package resilience_test
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/sony/gobreaker/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -218,6 +221,249 @@ func TestClient_4xxNotRetried(t *testing.T) {
 	assert.Equal(t, int32(1), attempts.Load(), "should not retry 4xx errors")
 }
 
+func TestClient_RetryAfterOn429(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:            "test-retry-after",
+		MaxRetries:      3,
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     50 * time.Millisecond,
+		Logger:          zerolog.New(&logs),
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "should wait out Retry-After, not the backoff")
+	assert.Contains(t, logs.String(), "honoring Retry-After")
+	assert.Contains(t, logs.String(), `"delay":1000`)
+}
+
+func TestClient_RetryAfterIsCapped(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			// HTTP-date form, an hour away
+			w.Header().Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:            "test-retry-after-cap",
+		MaxRetries:      3,
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     50 * time.Millisecond,
+		MaxRetryAfter:   100 * time.Millisecond,
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	elapsed := time.Since(start)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestClient_429RetriesExhausted(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	cbConfig := resilience.DefaultCircuitBreakerConfig("test-429")
+	cbConfig.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:            "test-429",
+		MaxRetries:      2,
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     50 * time.Millisecond,
+		CircuitBreaker:  &cbConfig,
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "last 429 is returned to the caller")
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, gobreaker.StateClosed, client.CircuitBreakerState(), "429s should not trip the breaker")
+}
+
+// blockingServer holds every request until release is closed, tracking the
+// peak number of concurrent requests.
+func blockingServer(release <-chan struct{}, current, peak *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestClient_BulkheadQueues(t *testing.T) {
+	release := make(chan struct{})
+	var current, peak atomic.Int32
+	server := blockingServer(release, &current, &peak)
+	defer server.Close()
+
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:          "test-bulkhead",
+		MaxConcurrent: 2,
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			errs <- err
+		}()
+	}
+
+	require.Eventually(t, func() bool { return client.InFlight() == 2 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // give queued requests a chance to slip through
+	assert.Equal(t, int64(2), client.InFlight())
+	assert.Equal(t, 2, client.MaxConcurrent())
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), peak.Load(), "no more than MaxConcurrent requests should reach the provider")
+	assert.Equal(t, int64(0), client.InFlight())
+}
+
+func TestClient_BulkheadFailFast(t *testing.T) {
+	release := make(chan struct{})
+	var current, peak atomic.Int32
+	server := blockingServer(release, &current, &peak)
+	defer server.Close()
+
+	registry := resilience.NewRegistry()
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:          "test-bulkhead-fail-fast",
+		MaxConcurrent: 1,
+		FailFast:      true,
+		Registry:      registry,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	require.Eventually(t, func() bool { return current.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	assert.ErrorIs(t, err, resilience.ErrBulkheadFull)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "should not wait or retry")
+
+	health := registry.GetHealth("test-bulkhead-fail-fast")
+	assert.Equal(t, int64(1), health.InFlight)
+	assert.Equal(t, 1, health.MaxConcurrent)
+	assert.Nil(t, health.LastFailureAt, "a full bulkhead is not a provider failure")
+
+	close(release)
+	<-done
+}
+
+func TestClient_BulkheadQueueHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	var current, peak atomic.Int32
+	server := blockingServer(release, &current, &peak)
+	defer server.Close()
+	defer close(release)
+
+	client := resilience.NewClient(resilience.ClientConfig{
+		Name:          "test-bulkhead-context",
+		MaxConcurrent: 1,
+	})
+
+	go func() {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	require.Eventually(t, func() bool { return current.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestDefaultCircuitBreakerConfig(t *testing.T) {
 	cfg := resilience.DefaultCircuitBreakerConfig("test")
 
@@ -271,6 +517,8 @@ func TestDefaultClientConfig(t *testing.T) {
 	assert.Equal(t, uint64(3), cfg.MaxRetries)
 	assert.Equal(t, 100*time.Millisecond, cfg.InitialInterval)
 	assert.Equal(t, 5*time.Second, cfg.MaxInterval)
+	assert.Equal(t, 2.0, cfg.Multiplier)
+	assert.Equal(t, 10*time.Second, cfg.MaxRetryAfter)
 	assert.NotNil(t, cfg.CircuitBreaker)
 }
 
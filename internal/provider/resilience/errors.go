@@ -0,0 +1,49 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// StatusError is returned by provider clients for a response with a status
+// code they cannot use, so callers can tell transient failures from
+// permanent ones.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return "unexpected status code: " + strconv.Itoa(e.StatusCode)
+}
+
+// Transient reports whether the status may succeed if retried: a 5xx or a
+// 429.
+func (e *StatusError) Transient() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsTransient reports whether err is a provider failure that may succeed if
+// retried: a 5xx or 429 response, a timeout, a network error, or a request
+// refused because the bulkhead was full. Client errors, decoding errors and
+// an open circuit breaker are not transient.
+func IsTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Transient()
+	}
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		return true
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrBulkheadFull) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,39 @@
+package resilience_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"server error status", &resilience.StatusError{StatusCode: 503}, true},
+		{"rate limited status", &resilience.StatusError{StatusCode: 429}, true},
+		{"client error status", &resilience.StatusError{StatusCode: 401}, false},
+		{"wrapped status", fmt.Errorf("stations endpoint: %w", &resilience.StatusError{StatusCode: 502}), true},
+		{"wrapped client status", fmt.Errorf("stations endpoint: %w", &resilience.StatusError{StatusCode: 404}), false},
+		{"timeout", fmt.Errorf("request failed: %w", context.DeadlineExceeded), true},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"bulkhead full", resilience.ErrBulkheadFull, true},
+		{"circuit open", resilience.ErrCircuitOpen, false},
+		{"decoding error", errors.New("failed to decode response"), false},
+		{"canceled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resilience.IsTransient(tt.err))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package resilience
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/breatheroute/breatheroute/internal/provider/resilience"
+
+// RegisterMetrics reports each registered provider's in-flight requests and
+// concurrency limit as OpenTelemetry observable gauges on the global meter
+// provider, with a "provider" attribute. Values are read at each collection,
+// so providers registered later are included. Unregister the returned
+// registration on shutdown.
+func RegisterMetrics(registry *Registry) (metric.Registration, error) {
+	meter := otel.Meter(meterName)
+
+	inFlight, err := meter.Int64ObservableGauge(
+		"provider.requests.in_flight",
+		metric.WithDescription("Number of provider requests awaiting a response"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrent, err := meter.Int64ObservableGauge(
+		"provider.requests.max_concurrent",
+		metric.WithDescription("Maximum concurrent provider requests, 0 if unlimited"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, health := range registry.GetAllHealth() {
+			attrs := metric.WithAttributes(attribute.String("provider", health.Name))
+			o.ObserveInt64(inFlight, health.InFlight, attrs)
+			o.ObserveInt64(maxConcurrent, int64(health.MaxConcurrent), attrs)
+		}
+		return nil
+	}, inFlight, maxConcurrent)
+}
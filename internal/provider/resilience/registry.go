@@ -1,6 +1,7 @@
 package resilience
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -26,6 +27,21 @@ type ProviderHealth struct {
 
 	// LastError is the most recent error message, if any.
 	LastError string
+
+	// InFlight is the number of requests awaiting a response.
+	InFlight int64
+
+	// MaxConcurrent is the in-flight request limit, or 0 if unlimited.
+	MaxConcurrent int
+}
+
+// ErrorRate returns the share of requests that failed in the circuit
+// breaker's current counting interval, or 0 if none were made.
+func (h *ProviderHealth) ErrorRate() float64 {
+	if h.Counts.Requests == 0 {
+		return 0
+	}
+	return float64(h.Counts.TotalFailures) / float64(h.Counts.Requests)
 }
 
 // IsHealthy returns true if the provider is considered healthy.
@@ -115,14 +131,7 @@ func (r *Registry) GetHealth(name string) *ProviderHealth {
 		return nil
 	}
 
-	return &ProviderHealth{
-		Name:          name,
-		CircuitState:  p.client.CircuitBreakerState(),
-		Counts:        p.client.CircuitBreakerCounts(),
-		LastSuccessAt: p.lastSuccessAt,
-		LastFailureAt: p.lastFailureAt,
-		LastError:     p.lastError,
-	}
+	return p.health(name)
 }
 
 // GetAllHealth returns the health status of all registered providers.
@@ -132,19 +141,68 @@ func (r *Registry) GetAllHealth() []*ProviderHealth {
 
 	health := make([]*ProviderHealth, 0, len(r.providers))
 	for name, p := range r.providers {
-		health = append(health, &ProviderHealth{
-			Name:          name,
-			CircuitState:  p.client.CircuitBreakerState(),
-			Counts:        p.client.CircuitBreakerCounts(),
-			LastSuccessAt: p.lastSuccessAt,
-			LastFailureAt: p.lastFailureAt,
-			LastError:     p.lastError,
-		})
+		health = append(health, p.health(name))
 	}
 
 	return health
 }
 
+// Snapshot returns the health of every registered provider, sorted by name.
+// Unlike GetAllHealth, the entries are copies taken under a single lock, so
+// they describe one point in time.
+func (r *Registry) Snapshot() []ProviderHealth {
+	r.mu.RLock()
+	snapshot := make([]ProviderHealth, 0, len(r.providers))
+	for name, p := range r.providers {
+		snapshot = append(snapshot, *p.health(name))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Name < snapshot[j].Name
+	})
+	return snapshot
+}
+
+// SnapshotAll aggregates the snapshots of registries, sorted by name. A
+// provider registered with more than one registry is reported once, from
+// the first registry it appears in. Nil registries are skipped.
+func SnapshotAll(registries ...*Registry) []ProviderHealth {
+	seen := make(map[string]bool)
+	var all []ProviderHealth
+	for _, r := range registries {
+		if r == nil {
+			continue
+		}
+		for _, h := range r.Snapshot() {
+			if seen[h.Name] {
+				continue
+			}
+			seen[h.Name] = true
+			all = append(all, h)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
+
+// health reports the provider's current status. Caller must hold r.mu.
+func (p *registeredProvider) health(name string) *ProviderHealth {
+	return &ProviderHealth{
+		Name:          name,
+		CircuitState:  p.client.CircuitBreakerState(),
+		Counts:        p.client.CircuitBreakerCounts(),
+		LastSuccessAt: p.lastSuccessAt,
+		LastFailureAt: p.lastFailureAt,
+		LastError:     p.lastError,
+		InFlight:      p.client.InFlight(),
+		MaxConcurrent: p.client.MaxConcurrent(),
+	}
+}
+
 // GetProviderNames returns the names of all registered providers.
 func (r *Registry) GetProviderNames() []string {
 	r.mu.RLock()
@@ -1,6 +1,8 @@
 package resilience_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -119,6 +121,74 @@ func TestRegistry_GetAllHealth(t *testing.T) {
 	assert.True(t, names["provider-c"])
 }
 
+func TestRegistry_Snapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	registry := resilience.NewRegistry()
+	clients := make(map[string]*resilience.Client)
+	for _, name := range []string{"provider-b", "provider-a", "provider-c"} {
+		cfg := resilience.DefaultClientConfig(name)
+		cfg.InitialInterval = time.Millisecond
+		cfg.MaxInterval = time.Millisecond
+		cfg.Registry = registry
+		clients[name] = resilience.NewClient(cfg)
+	}
+
+	do := func(client *resilience.Client, path string) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, http.NoBody)
+		require.NoError(t, err)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+	do(clients["provider-a"], "/ok")
+	do(clients["provider-b"], "/fail")
+
+	snapshot := registry.Snapshot()
+	require.Len(t, snapshot, 3)
+	assert.Equal(t, "provider-a", snapshot[0].Name)
+	assert.Equal(t, "provider-b", snapshot[1].Name)
+	assert.Equal(t, "provider-c", snapshot[2].Name)
+
+	require.NotNil(t, snapshot[0].LastSuccessAt)
+	assert.Zero(t, snapshot[0].ErrorRate())
+	assert.Equal(t, 1.0, snapshot[1].ErrorRate())
+	assert.NotNil(t, snapshot[1].LastFailureAt)
+	assert.Zero(t, snapshot[2].ErrorRate(), "no requests made")
+}
+
+func TestSnapshotAll(t *testing.T) {
+	register := func(registry *resilience.Registry, names ...string) {
+		for _, name := range names {
+			cfg := resilience.DefaultClientConfig(name)
+			cfg.Registry = registry
+			_ = resilience.NewClient(cfg)
+		}
+	}
+
+	first := resilience.NewRegistry()
+	register(first, "ns", "openrouteservice")
+	second := resilience.NewRegistry()
+	register(second, "ambee", "ns")
+	second.RecordFailure("ns", assert.AnError)
+
+	all := resilience.SnapshotAll(first, nil, second)
+	require.Len(t, all, 3)
+	assert.Equal(t, "ambee", all[0].Name)
+	assert.Equal(t, "ns", all[1].Name)
+	assert.Equal(t, "openrouteservice", all[2].Name)
+	assert.Empty(t, all[1].LastError, "first registry wins for duplicate names")
+
+	assert.Empty(t, resilience.SnapshotAll())
+}
+
 func TestRegistry_GetProviderNames(t *testing.T) {
 	registry := resilience.NewRegistry()
 
@@ -0,0 +1,58 @@
+package resilience
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/breatheroute/breatheroute/internal/provider/resilience"
+
+// startSpan starts a client span for a provider request. It uses the global
+// tracer provider, so spans are no-ops when telemetry is disabled.
+// Only the URL path is recorded: some providers take API keys in the query.
+func (c *Client) startSpan(ctx context.Context, req *http.Request) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, c.config.Name+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("provider.name", c.config.Name),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+}
+
+// endSpan records the outcome of a provider request on its span and ends it.
+func endSpan(span trace.Span, resp *http.Response, attempts int, err error) {
+	span.SetAttributes(attribute.Int("provider.attempts", attempts))
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// RecordCacheResult annotates the span in ctx with a cache lookup for a
+// provider's data, so traces show whether a provider call was skipped.
+// It does nothing when ctx carries no recording span.
+func RecordCacheResult(ctx context.Context, provider string, hit bool) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent("cache.lookup", trace.WithAttributes(
+		attribute.String("provider.name", provider),
+		attribute.Bool("cache.hit", hit),
+	))
+}
@@ -0,0 +1,120 @@
+package resilience_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+)
+
+// useSpanRecorder installs a recording tracer provider for the test.
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+// spanAttributes returns a span's attributes keyed by name.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestClient_CreatesSpan(t *testing.T) {
+	recorder := useSpanRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := resilience.NewClient(resilience.DefaultClientConfig("ors"))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/v2/directions?api_key=secret", http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "ors GET", spans[0].Name())
+
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "ors", attrs["provider.name"].AsString())
+	assert.Equal(t, "/v2/directions", attrs["url.path"].AsString())
+	assert.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(1), attrs["provider.attempts"].AsInt64())
+	for _, kv := range spans[0].Attributes() {
+		assert.NotContains(t, kv.Value.Emit(), "secret")
+	}
+}
+
+func TestClient_SpanRecordsServerError(t *testing.T) {
+	recorder := useSpanRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cfg := resilience.DefaultClientConfig("ns")
+	cfg.MaxRetries = 2
+	cfg.InitialInterval = time.Millisecond
+	cfg.MaxInterval = time.Millisecond
+	client := resilience.NewClient(cfg)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, int64(http.StatusBadGateway), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(3), attrs["provider.attempts"].AsInt64())
+}
+
+func TestRecordCacheResult(t *testing.T) {
+	recorder := useSpanRecorder(t)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "compute")
+	resilience.RecordCacheResult(ctx, "ors", true)
+	span.End()
+
+	// Without a recording span this must not panic
+	resilience.RecordCacheResult(context.Background(), "ors", false)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "cache.lookup", events[0].Name)
+	assert.Contains(t, events[0].Attributes, attribute.Bool("cache.hit", true))
+}
@@ -0,0 +1,87 @@
+package routestore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryRepository is an in-memory implementation of Repository.
+// This is intended for testing. Production should use the PostgreSQL implementation.
+type InMemoryRepository struct {
+	mu     sync.RWMutex
+	routes map[string]*StoredRoute
+	shares map[string]*Share
+}
+
+// NewInMemoryRepository creates a new in-memory route repository.
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		routes: make(map[string]*StoredRoute),
+		shares: make(map[string]*Share),
+	}
+}
+
+// Create stores a route, dropping routes that have expired.
+func (r *InMemoryRepository) Create(_ context.Context, route *StoredRoute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, stored := range r.routes {
+		if !stored.ExpiresAt.After(route.CreatedAt) {
+			delete(r.routes, id)
+		}
+	}
+	routeCopy := *route
+	r.routes[route.ID] = &routeCopy
+	return nil
+}
+
+// Get retrieves a route by ID that has not expired at now.
+func (r *InMemoryRepository) Get(_ context.Context, id string, now time.Time) (*StoredRoute, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, ok := r.routes[id]
+	if !ok || !route.ExpiresAt.After(now) {
+		return nil, ErrRouteNotFound
+	}
+	routeCopy := *route
+	return &routeCopy, nil
+}
+
+// CreateShare stores a route share.
+func (r *InMemoryRepository) CreateShare(_ context.Context, share *Share) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shareCopy := *share
+	r.shares[share.Token] = &shareCopy
+	return nil
+}
+
+// GetShare retrieves an unrevoked share by token that has not expired at now.
+func (r *InMemoryRepository) GetShare(_ context.Context, token string, now time.Time) (*Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	share, ok := r.shares[token]
+	if !ok || share.RevokedAt != nil || !share.ExpiresAt.After(now) {
+		return nil, ErrShareNotFound
+	}
+	shareCopy := *share
+	return &shareCopy, nil
+}
+
+// RevokeShare marks a user's share as revoked.
+func (r *InMemoryRepository) RevokeShare(_ context.Context, userID, token string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	share, ok := r.shares[token]
+	if !ok || share.UserID != userID || share.RevokedAt != nil {
+		return ErrShareNotFound
+	}
+	share.RevokedAt = &at
+	return nil
+}
@@ -0,0 +1,48 @@
+// Package routestore keeps computed route responses for a limited time under
+// an unguessable ID, so that clients can fetch, export or share them without
+// recomputing. Shares are public, revocable links to a copy of a route.
+package routestore
+
+import (
+	"errors"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Route store errors.
+var (
+	// ErrRouteNotFound is returned for routes that do not exist, have
+	// expired or belong to another user.
+	ErrRouteNotFound = errors.New("route not found")
+
+	// ErrShareNotFound is returned for shares that do not exist, have
+	// expired, were revoked or belong to another user.
+	ErrShareNotFound = errors.New("route share not found")
+)
+
+// StoredRoute is a computed route response.
+type StoredRoute struct {
+	ID string
+
+	// UserID is the user who computed the route, or empty for anonymous
+	// requests. Routes with an owner are only served to that user; anonymous
+	// routes to anyone holding the ID.
+	UserID string
+
+	Response models.RouteComputeResponse
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Option returns the route option with the given ID, or the top-ranked
+// option if optionID is empty.
+func (r *StoredRoute) Option(optionID string) (models.RouteOption, bool) {
+	for _, option := range r.Response.Options {
+		if optionID == "" || option.ID == optionID {
+			return option, true
+		}
+	}
+	return models.RouteOption{}, false
+}
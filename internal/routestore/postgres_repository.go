@@ -0,0 +1,137 @@
+package routestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository is a PostgreSQL implementation of Repository.
+// Responses are stored as JSONB in their API encoding.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgreSQL route repository.
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// Create stores a route.
+func (r *PostgresRepository) Create(ctx context.Context, route *StoredRoute) error {
+	query := `
+		INSERT INTO stored_routes (id, user_id, response, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	response, err := json.Marshal(route.Response)
+	if err != nil {
+		return err
+	}
+
+	var userID *string
+	if route.UserID != "" {
+		userID = &route.UserID
+	}
+
+	_, err = r.pool.Exec(ctx, query, route.ID, userID, response, route.CreatedAt, route.ExpiresAt)
+	return err
+}
+
+// Get retrieves a route by ID that has not expired at now.
+func (r *PostgresRepository) Get(ctx context.Context, id string, now time.Time) (*StoredRoute, error) {
+	query := `
+		SELECT id, user_id, response, created_at, expires_at
+		FROM stored_routes
+		WHERE id = $1 AND expires_at > $2
+	`
+
+	var route StoredRoute
+	var userID *string
+	var response []byte
+	err := r.pool.QueryRow(ctx, query, id, now).Scan(&route.ID, &userID, &response, &route.CreatedAt, &route.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRouteNotFound
+		}
+		return nil, err
+	}
+
+	if userID != nil {
+		route.UserID = *userID
+	}
+	if err := json.Unmarshal(response, &route.Response); err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+// CreateShare stores a route share.
+func (r *PostgresRepository) CreateShare(ctx context.Context, share *Share) error {
+	query := `
+		INSERT INTO route_shares (token, route_id, user_id, route, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	route, err := json.Marshal(share.Route)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, query, share.Token, share.RouteID, share.UserID, route, share.CreatedAt, share.ExpiresAt)
+	return err
+}
+
+// GetShare retrieves an unrevoked share by token that has not expired at now.
+func (r *PostgresRepository) GetShare(ctx context.Context, token string, now time.Time) (*Share, error) {
+	query := `
+		SELECT token, route_id, user_id, route, created_at, expires_at, revoked_at
+		FROM route_shares
+		WHERE token = $1 AND expires_at > $2 AND revoked_at IS NULL
+	`
+
+	var share Share
+	var route []byte
+	err := r.pool.QueryRow(ctx, query, token, now).Scan(
+		&share.Token,
+		&share.RouteID,
+		&share.UserID,
+		&route,
+		&share.CreatedAt,
+		&share.ExpiresAt,
+		&share.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShareNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(route, &share.Route); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// RevokeShare marks a user's share as revoked.
+func (r *PostgresRepository) RevokeShare(ctx context.Context, userID, token string, at time.Time) error {
+	query := `
+		UPDATE route_shares
+		SET revoked_at = $3
+		WHERE token = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, token, userID, at)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
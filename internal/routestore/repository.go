@@ -0,0 +1,27 @@
+package routestore
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for stored route persistence.
+type Repository interface {
+	// Create stores a route.
+	Create(ctx context.Context, route *StoredRoute) error
+
+	// Get retrieves a route by ID that has not expired at now. It returns
+	// ErrRouteNotFound if there is none.
+	Get(ctx context.Context, id string, now time.Time) (*StoredRoute, error)
+
+	// CreateShare stores a route share.
+	CreateShare(ctx context.Context, share *Share) error
+
+	// GetShare retrieves a share by token that has not expired at now and
+	// is not revoked. It returns ErrShareNotFound if there is none.
+	GetShare(ctx context.Context, token string, now time.Time) (*Share, error)
+
+	// RevokeShare marks a user's share as revoked at the given time. It
+	// returns ErrShareNotFound if the user has no such unrevoked share.
+	RevokeShare(ctx context.Context, userID, token string, at time.Time) error
+}
@@ -0,0 +1,164 @@
+package routestore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Default lifetimes of stored routes and shares.
+const (
+	// DefaultTTL is how long computed routes are kept.
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultShareTTL is how long share links work unless revoked.
+	DefaultShareTTL = 7 * 24 * time.Hour
+)
+
+// ServiceConfig holds configuration for the route store service.
+type ServiceConfig struct {
+	// Repository stores computed routes (required).
+	Repository Repository
+
+	// TTL is how long routes can be fetched after they are computed
+	// (default: DefaultTTL).
+	TTL time.Duration
+
+	// ShareTTL is how long share links work after they are created
+	// (default: DefaultShareTTL).
+	ShareTTL time.Duration
+}
+
+// Service saves computed routes and serves them to the user who computed
+// them.
+type Service struct {
+	repo     Repository
+	ttl      time.Duration
+	shareTTL time.Duration
+	now      func() time.Time
+}
+
+// NewService creates a new route store service.
+func NewService(cfg ServiceConfig) *Service {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	shareTTL := cfg.ShareTTL
+	if shareTTL <= 0 {
+		shareTTL = DefaultShareTTL
+	}
+
+	return &Service{
+		repo:     cfg.Repository,
+		ttl:      ttl,
+		shareTTL: shareTTL,
+		now:      time.Now,
+	}
+}
+
+// Save stores a computed response for userID, which may be empty for
+// anonymous requests. It returns the stored route with its generated ID and
+// expiry; the response itself is stored with both set.
+func (s *Service) Save(ctx context.Context, userID string, resp models.RouteComputeResponse) (*StoredRoute, error) {
+	id, err := newRouteID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.ttl)
+	resp.ID = id
+	resp.ExpiresAt = timestampPtr(expiresAt)
+
+	route := &StoredRoute{
+		ID:        id,
+		UserID:    userID,
+		Response:  resp,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(ctx, route); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// Get retrieves a route for userID, which is empty for anonymous requests.
+// Routes computed by a signed-in user are only returned to that user; other
+// callers get ErrRouteNotFound, so the ID's existence is not revealed.
+func (s *Service) Get(ctx context.Context, id, userID string) (*StoredRoute, error) {
+	route, err := s.repo.Get(ctx, id, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if route.UserID != "" && route.UserID != userID {
+		return nil, ErrRouteNotFound
+	}
+	return route, nil
+}
+
+// Share creates a public link to a route userID can fetch. The share keeps
+// a copy of the route's options and works until it expires or is revoked,
+// even after the stored route itself has expired.
+func (s *Service) Share(ctx context.Context, routeID, userID string) (*Share, error) {
+	route, err := s.Get(ctx, routeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newToken("shr_")
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.shareTTL)
+	share := &Share{
+		Token:     token,
+		RouteID:   route.ID,
+		UserID:    userID,
+		Route:     publicRoute(route.Response, expiresAt),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// GetShare retrieves a share by its public token. Expired and revoked shares
+// return ErrShareNotFound.
+func (s *Service) GetShare(ctx context.Context, token string) (*Share, error) {
+	return s.repo.GetShare(ctx, token, s.now())
+}
+
+// RevokeShare revokes one of userID's shares, so its link stops working.
+func (s *Service) RevokeShare(ctx context.Context, userID, token string) error {
+	return s.repo.RevokeShare(ctx, userID, token, s.now())
+}
+
+// newRouteID returns a route ID. Like share tokens, it cannot be guessed and
+// so is enough to protect anonymous routes.
+func newRouteID() (string, error) {
+	return newToken("rte_")
+}
+
+// newToken returns prefix followed by 128 random bits in hex.
+func newToken(prefix string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
+
+func timestampPtr(t time.Time) *models.Timestamp {
+	ts := models.Timestamp(t)
+	return &ts
+}
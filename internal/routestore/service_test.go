@@ -0,0 +1,171 @@
+package routestore_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/routestore"
+)
+
+const testUserID = "usr_testuser123"
+
+func testResponse() models.RouteComputeResponse {
+	return models.RouteComputeResponse{
+		GeneratedAt: models.Timestamp(time.Now()),
+		Options:     []models.RouteOption{{ID: "opt_fast"}, {ID: "opt_clean"}},
+	}
+}
+
+func TestService_SaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	service := routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()})
+
+	saved, err := service.Save(ctx, testUserID, testResponse())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(saved.ID, "rte_"))
+	assert.Len(t, saved.ID, len("rte_")+32, "IDs carry 128 random bits")
+	assert.Equal(t, saved.ID, saved.Response.ID)
+	require.NotNil(t, saved.Response.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(routestore.DefaultTTL), time.Time(*saved.Response.ExpiresAt), time.Minute)
+
+	got, err := service.Get(ctx, saved.ID, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, saved.Response.Options, got.Response.Options)
+
+	other, err := service.Save(ctx, testUserID, testResponse())
+	require.NoError(t, err)
+	assert.NotEqual(t, saved.ID, other.ID)
+}
+
+func TestService_Get_Scoping(t *testing.T) {
+	ctx := context.Background()
+	service := routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()})
+
+	owned, err := service.Save(ctx, testUserID, testResponse())
+	require.NoError(t, err)
+	anonymous, err := service.Save(ctx, "", testResponse())
+	require.NoError(t, err)
+
+	t.Run("owned routes are hidden from other users", func(t *testing.T) {
+		_, err := service.Get(ctx, owned.ID, "usr_other")
+		assert.ErrorIs(t, err, routestore.ErrRouteNotFound)
+	})
+
+	t.Run("owned routes are hidden from anonymous callers", func(t *testing.T) {
+		_, err := service.Get(ctx, owned.ID, "")
+		assert.ErrorIs(t, err, routestore.ErrRouteNotFound)
+	})
+
+	t.Run("anonymous routes are served to anyone with the ID", func(t *testing.T) {
+		_, err := service.Get(ctx, anonymous.ID, "")
+		assert.NoError(t, err)
+		_, err = service.Get(ctx, anonymous.ID, "usr_other")
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown IDs are not found", func(t *testing.T) {
+		_, err := service.Get(ctx, "rte_unknown", testUserID)
+		assert.ErrorIs(t, err, routestore.ErrRouteNotFound)
+	})
+}
+
+func TestService_Get_Expired(t *testing.T) {
+	ctx := context.Background()
+	service := routestore.NewService(routestore.ServiceConfig{
+		Repository: routestore.NewInMemoryRepository(),
+		TTL:        time.Millisecond,
+	})
+
+	saved, err := service.Save(ctx, "", testResponse())
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = service.Get(ctx, saved.ID, "")
+	assert.ErrorIs(t, err, routestore.ErrRouteNotFound)
+}
+
+func TestStoredRoute_Option(t *testing.T) {
+	route := &routestore.StoredRoute{Response: testResponse()}
+
+	top, ok := route.Option("")
+	require.True(t, ok)
+	assert.Equal(t, "opt_fast", top.ID)
+
+	clean, ok := route.Option("opt_clean")
+	require.True(t, ok)
+	assert.Equal(t, "opt_clean", clean.ID)
+
+	_, ok = route.Option("opt_missing")
+	assert.False(t, ok)
+}
+
+func TestService_Share(t *testing.T) {
+	ctx := context.Background()
+	service := routestore.NewService(routestore.ServiceConfig{
+		Repository: routestore.NewInMemoryRepository(),
+		TTL:        time.Millisecond,
+	})
+
+	resp := testResponse()
+	resp.Warnings = []models.Warning{{Code: "MODE_FAILED", Message: "walking unavailable"}}
+	saved, err := service.Save(ctx, testUserID, resp)
+	require.NoError(t, err)
+
+	share, err := service.Share(ctx, saved.ID, testUserID)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(share.Token, "shr_"))
+	assert.Equal(t, saved.ID, share.RouteID)
+	assert.Equal(t, saved.Response.Options, share.Route.Options)
+	assert.Equal(t, models.Timestamp(share.ExpiresAt), share.Route.ExpiresAt)
+
+	// The share keeps its own copy once the stored route expires
+	time.Sleep(5 * time.Millisecond)
+	_, err = service.Get(ctx, saved.ID, testUserID)
+	require.ErrorIs(t, err, routestore.ErrRouteNotFound)
+	got, err := service.GetShare(ctx, share.Token)
+	require.NoError(t, err)
+	assert.Equal(t, share.Route.Options, got.Route.Options)
+
+	_, err = service.Share(ctx, saved.ID, testUserID)
+	assert.ErrorIs(t, err, routestore.ErrRouteNotFound, "expired routes cannot be shared")
+}
+
+func TestService_RevokeShare(t *testing.T) {
+	ctx := context.Background()
+	service := routestore.NewService(routestore.ServiceConfig{Repository: routestore.NewInMemoryRepository()})
+
+	saved, err := service.Save(ctx, testUserID, testResponse())
+	require.NoError(t, err)
+	share, err := service.Share(ctx, saved.ID, testUserID)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.RevokeShare(ctx, "usr_other", share.Token), routestore.ErrShareNotFound)
+	require.NoError(t, service.RevokeShare(ctx, testUserID, share.Token))
+
+	_, err = service.GetShare(ctx, share.Token)
+	assert.ErrorIs(t, err, routestore.ErrShareNotFound)
+	assert.ErrorIs(t, service.RevokeShare(ctx, testUserID, share.Token), routestore.ErrShareNotFound)
+}
+
+func TestService_GetShare_Expired(t *testing.T) {
+	ctx := context.Background()
+	service := routestore.NewService(routestore.ServiceConfig{
+		Repository: routestore.NewInMemoryRepository(),
+		ShareTTL:   time.Millisecond,
+	})
+
+	saved, err := service.Save(ctx, "", testResponse())
+	require.NoError(t, err)
+	share, err := service.Share(ctx, saved.ID, testUserID)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = service.GetShare(ctx, share.Token)
+	assert.ErrorIs(t, err, routestore.ErrShareNotFound)
+}
@@ -0,0 +1,36 @@
+package routestore
+
+import (
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// Share is a public, read-only link to a stored route. It holds its own copy
+// of the route's options, so it outlives the stored route.
+type Share struct {
+	Token   string
+	RouteID string
+
+	// UserID is the user who shared the route, who may revoke the share.
+	UserID string
+
+	// Route is the shared copy of the route, without anything identifying
+	// the user or the stored route.
+	Route models.PublicRoute
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// publicRoute returns the parts of a computed response that are safe to
+// publish. The route ID is left out, since it grants access to an anonymous
+// stored route, and so are warnings, which describe the request.
+func publicRoute(resp models.RouteComputeResponse, expiresAt time.Time) models.PublicRoute {
+	return models.PublicRoute{
+		GeneratedAt: resp.GeneratedAt,
+		ExpiresAt:   models.Timestamp(expiresAt),
+		Options:     resp.Options,
+	}
+}
@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultMaxDistanceMeters is the default straight-line distance limit per
+// profile. Profiles without an entry are unlimited.
+var DefaultMaxDistanceMeters = map[RouteProfile]float64{
+	ProfileWalk: 50_000,
+	ProfileBike: 150_000,
+}
+
+// DefaultMinSeparationMeters is the default straight-line distance origin and
+// destination must be apart to be routed between.
+const DefaultMinSeparationMeters = 10.0
+
+const earthRadiusMeters = 6371000
+
+// MaxDistanceMeters returns the straight-line distance limit for profile, or
+// 0 if it is unlimited.
+func (s *Service) MaxDistanceMeters(profile RouteProfile) float64 {
+	return s.maxDistance[profile]
+}
+
+// CheckDistance returns an *Error wrapping ErrRouteTooLong if the straight-line
+// path through points exceeds the profile's limit. Invalid coordinates are
+// not reported here; GetDirections validates them.
+func (s *Service) CheckDistance(profile RouteProfile, points ...Coordinate) error {
+	limit := s.maxDistance[profile]
+	if limit <= 0 {
+		return nil
+	}
+
+	var total float64
+	for i := 1; i < len(points); i++ {
+		total += straightLineDistance(points[i-1], points[i])
+	}
+	if total <= limit {
+		return nil
+	}
+
+	return &Error{
+		Provider: s.provider.Name(),
+		Code:     "ROUTE_TOO_LONG",
+		Message: fmt.Sprintf("straight-line distance of %.1f km exceeds the %.0f km limit for %s",
+			total/1000, limit/1000, profile),
+		Err: ErrRouteTooLong,
+	}
+}
+
+// CheckSeparation returns an *Error wrapping ErrSameOriginDestination if
+// origin and destination are closer than the minimum separation, since the
+// provider could only return a degenerate route.
+func (s *Service) CheckSeparation(origin, destination Coordinate) error {
+	if s.minSeparation < 0 {
+		return nil
+	}
+
+	distance := straightLineDistance(origin, destination)
+	if distance >= s.minSeparation {
+		return nil
+	}
+
+	return &Error{
+		Provider: s.provider.Name(),
+		Code:     "SAME_ORIGIN_DESTINATION",
+		Message: fmt.Sprintf("origin and destination are %.1f m apart, less than the %.0f m minimum",
+			distance, s.minSeparation),
+		Err: ErrSameOriginDestination,
+	}
+}
+
+// straightLineDistance returns the great-circle distance between a and b in
+// meters.
+func straightLineDistance(a, b Coordinate) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	sinDLat := math.Sin(dLat / 2)
+	sinDLon := math.Sin(dLon / 2)
+
+	h := sinDLat*sinDLat + math.Cos(lat1)*math.Cos(lat2)*sinDLon*sinDLon
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
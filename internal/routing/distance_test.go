@@ -0,0 +1,152 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// northOf returns the point meters due north of c.
+func northOf(c Coordinate, meters float64) Coordinate {
+	return Coordinate{Lat: c.Lat + meters/earthRadiusMeters*180/math.Pi, Lon: c.Lon}
+}
+
+func TestService_GetDirections_MaxDistance(t *testing.T) {
+	origin := Coordinate{Lat: 52.3676, Lon: 4.9041}
+
+	tests := []struct {
+		name    string
+		profile RouteProfile
+		meters  float64
+		wantErr bool
+	}{
+		{"walk just under", ProfileWalk, DefaultMaxDistanceMeters[ProfileWalk] - 10, false},
+		{"walk just over", ProfileWalk, DefaultMaxDistanceMeters[ProfileWalk] + 10, true},
+		{"bike just under", ProfileBike, DefaultMaxDistanceMeters[ProfileBike] - 10, false},
+		{"bike just over", ProfileBike, DefaultMaxDistanceMeters[ProfileBike] + 10, true},
+		{"transit unlimited", ProfileTransit, 500_000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mockProvider{
+				name:     "test-provider",
+				response: &DirectionsResponse{Routes: []Route{{DistanceMeters: int(tt.meters)}}, FetchedAt: time.Now()},
+			}
+			service := NewService(ServiceConfig{Provider: provider})
+
+			_, err := service.GetDirections(context.Background(), DirectionsRequest{
+				Origin:      origin,
+				Destination: northOf(origin, tt.meters),
+				Profile:     tt.profile,
+			})
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrRouteTooLong) {
+				t.Fatalf("expected ErrRouteTooLong, got %v", err)
+			}
+			var routingErr *Error
+			if !errors.As(err, &routingErr) || routingErr.Code != "ROUTE_TOO_LONG" {
+				t.Errorf("expected routing error with code ROUTE_TOO_LONG, got %v", err)
+			}
+			if provider.callCount.Load() != 0 {
+				t.Errorf("expected no provider calls, got %d", provider.callCount.Load())
+			}
+		})
+	}
+}
+
+func TestService_MaxDistanceOverrides(t *testing.T) {
+	service := NewService(ServiceConfig{
+		Provider: &mockProvider{name: "test-provider"},
+		MaxDistanceMeters: map[RouteProfile]float64{
+			ProfileWalk: 5_000,
+			ProfileBike: -1,
+		},
+	})
+	origin := Coordinate{Lat: 52.3676, Lon: 4.9041}
+
+	if got := service.MaxDistanceMeters(ProfileWalk); got != 5_000 {
+		t.Errorf("walk limit = %v, want 5000", got)
+	}
+	if err := service.CheckDistance(ProfileWalk, origin, northOf(origin, 5_010)); !errors.Is(err, ErrRouteTooLong) {
+		t.Errorf("expected ErrRouteTooLong for walk, got %v", err)
+	}
+	if err := service.CheckDistance(ProfileBike, origin, northOf(origin, 1_000_000)); err != nil {
+		t.Errorf("expected bike to be unlimited, got %v", err)
+	}
+}
+
+func TestService_GetDirections_SameOriginDestination(t *testing.T) {
+	origin := Coordinate{Lat: 52.3676, Lon: 4.9041}
+
+	tests := []struct {
+		name          string
+		meters        float64
+		minSeparation float64
+		wantErr       bool
+	}{
+		{"coincident", 0, 0, true},
+		{"near-coincident", 4, 0, true},
+		{"just over the default", DefaultMinSeparationMeters + 1, 0, false},
+		{"custom minimum", 40, 50, true},
+		{"check disabled", 0, -1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mockProvider{
+				name:     "test-provider",
+				response: &DirectionsResponse{Routes: []Route{{DistanceMeters: int(tt.meters)}}, FetchedAt: time.Now()},
+			}
+			service := NewService(ServiceConfig{Provider: provider, MinSeparationMeters: tt.minSeparation})
+
+			_, err := service.GetDirections(context.Background(), DirectionsRequest{
+				Origin:      origin,
+				Destination: northOf(origin, tt.meters),
+				Profile:     ProfileWalk,
+			})
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrSameOriginDestination) {
+				t.Fatalf("expected ErrSameOriginDestination, got %v", err)
+			}
+			var routingErr *Error
+			if !errors.As(err, &routingErr) || routingErr.Code != "SAME_ORIGIN_DESTINATION" {
+				t.Errorf("expected SAME_ORIGIN_DESTINATION error, got %v", err)
+			}
+			if provider.callCount.Load() != 0 {
+				t.Error("provider should not be called")
+			}
+		})
+	}
+}
+
+func TestService_GetDirectionsVia_MaxDistance(t *testing.T) {
+	service := NewService(ServiceConfig{Provider: &mockProvider{name: "test-provider"}})
+	limit := DefaultMaxDistanceMeters[ProfileWalk]
+
+	// Each leg is within the limit, but the whole route is not
+	origin := Coordinate{Lat: 52.3676, Lon: 4.9041}
+	via := northOf(origin, limit*0.6)
+	destination := northOf(via, limit*0.6)
+
+	_, err := service.GetDirectionsVia(context.Background(), []Coordinate{origin, via, destination}, DirectionsRequest{Profile: ProfileWalk})
+	if !errors.Is(err, ErrRouteTooLong) {
+		t.Fatalf("expected ErrRouteTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package routing
+
+// detourFactor is the typical ratio of network distance to straight-line
+// distance in Dutch cities, used to turn a crow-flies distance into a route
+// length.
+const detourFactor = 1.3
+
+// estimateSpeeds are the average travel speeds in meters per second used for
+// estimated routes. Profiles without an entry cannot be estimated.
+var estimateSpeeds = map[RouteProfile]float64{
+	ProfileWalk: 5.0 / 3.6,
+	ProfileBike: 15.0 / 3.6,
+}
+
+// EstimateRoute returns an "as the crow flies" estimate of the route from
+// origin to destination, for use when no provider route is available. The
+// distance is the straight-line distance scaled by a typical detour factor
+// and the duration assumes an average speed for the profile. The route has no
+// geometry or instructions. ok is false for profiles that cannot be
+// estimated, such as transit.
+func EstimateRoute(profile RouteProfile, origin, destination Coordinate) (route Route, ok bool) {
+	speed, ok := estimateSpeeds[profile]
+	if !ok {
+		return Route{}, false
+	}
+
+	distance := straightLineDistance(origin, destination) * detourFactor
+	return Route{
+		DistanceMeters:  int(distance),
+		DurationSeconds: int(distance / speed),
+	}, true
+}
@@ -0,0 +1,39 @@
+package routing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateRoute(t *testing.T) {
+	// About 1 km apart
+	origin := Coordinate{Lat: 52.3700, Lon: 4.8900}
+	destination := Coordinate{Lat: 52.3790, Lon: 4.8900}
+	straight := straightLineDistance(origin, destination)
+
+	walk, ok := EstimateRoute(ProfileWalk, origin, destination)
+	if !ok {
+		t.Fatal("expected walking route to be estimated")
+	}
+	if want := straight * detourFactor; math.Abs(float64(walk.DistanceMeters)-want) > 1 {
+		t.Errorf("distance = %d, want about %.0f", walk.DistanceMeters, want)
+	}
+	if walk.GeometryPolyline != "" || len(walk.Instructions) > 0 {
+		t.Error("estimated route should have no geometry or instructions")
+	}
+
+	bike, ok := EstimateRoute(ProfileBike, origin, destination)
+	if !ok {
+		t.Fatal("expected cycling route to be estimated")
+	}
+	if bike.DistanceMeters != walk.DistanceMeters {
+		t.Errorf("distance should not depend on profile: %d != %d", bike.DistanceMeters, walk.DistanceMeters)
+	}
+	if bike.DurationSeconds*2 >= walk.DurationSeconds {
+		t.Errorf("cycling (%ds) should be much faster than walking (%ds)", bike.DurationSeconds, walk.DurationSeconds)
+	}
+
+	if _, ok := EstimateRoute(ProfileTransit, origin, destination); ok {
+		t.Error("transit routes should not be estimated")
+	}
+}
@@ -0,0 +1,280 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+)
+
+// cachedCost is a cached travel cost for a single origin-destination grid pair.
+type cachedCost struct {
+	cost      RouteCost
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// matrixPair identifies a cell in the distance matrix.
+type matrixPair struct {
+	origin      int
+	destination int
+}
+
+// DistanceMatrix returns the travel cost between every origin and destination,
+// indexed as [origin][destination]. Pairs with a cached cost are served from
+// the cost cache; the rest are fetched in a single batch from providers
+// implementing MatrixProvider, or with one directions call per pair otherwise.
+// Unreachable pairs have Reachable set to false. Pairs are checked before
+// either path: an origin and destination closer than the minimum separation
+// cost nothing, and a pair beyond the profile's distance limit is
+// unreachable, without failing the rest of the matrix.
+func (s *Service) DistanceMatrix(
+	ctx context.Context,
+	origins, destinations []Coordinate,
+	profile RouteProfile,
+) ([][]RouteCost, error) {
+	for _, c := range origins {
+		if err := validateCoordinates(c); err != nil {
+			return nil, &Error{
+				Provider: s.provider.Name(),
+				Code:     "INVALID_ORIGIN",
+				Message:  "invalid origin coordinates",
+				Err:      ErrInvalidCoordinates,
+			}
+		}
+	}
+	for _, c := range destinations {
+		if err := validateCoordinates(c); err != nil {
+			return nil, &Error{
+				Provider: s.provider.Name(),
+				Code:     "INVALID_DESTINATION",
+				Message:  "invalid destination coordinates",
+				Err:      ErrInvalidCoordinates,
+			}
+		}
+	}
+
+	s.applyOverrides(ctx)
+
+	matrix := make([][]RouteCost, len(origins))
+	for o := range matrix {
+		matrix[o] = make([]RouteCost, len(destinations))
+	}
+
+	// Fill from cache (read lock)
+	var missing []matrixPair
+	now := time.Now()
+	s.mu.RLock()
+	for o := range origins {
+		for d := range destinations {
+			if cost, ok := s.precheckedCost(origins[o], destinations[d], profile); ok {
+				matrix[o][d] = cost
+				continue
+			}
+			key := s.matrixCacheKey(origins[o], destinations[d], profile)
+			if cost, ok := s.cachedCost(key, now); ok {
+				matrix[o][d] = cost
+				continue
+			}
+			missing = append(missing, matrixPair{origin: o, destination: d})
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return matrix, nil
+	}
+
+	telemetry.Logger(ctx, s.logger).Debug().
+		Int("origins", len(origins)).
+		Int("destinations", len(destinations)).
+		Int("uncached_pairs", len(missing)).
+		Str("profile", string(profile)).
+		Msg("computing distance matrix")
+
+	var err error
+	if mp, ok := s.provider.(MatrixProvider); ok {
+		err = s.fetchMatrix(ctx, mp, origins, destinations, profile, missing, matrix)
+	} else {
+		err = s.fetchPairs(ctx, origins, destinations, profile, missing, matrix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return matrix, nil
+}
+
+// matrixCacheKey returns the cost cache key for an origin-destination pair.
+// Unlike directions, costs are keyed on the exact coordinates: matrix
+// candidates are often close together, and two sharing a grid cell would
+// be given each other's cost.
+func (s *Service) matrixCacheKey(origin, destination Coordinate, profile RouteProfile) string {
+	key := fmt.Sprintf("%s:%.6f,%.6f:%.6f,%.6f",
+		profile,
+		origin.Lat, origin.Lon,
+		destination.Lat, destination.Lon,
+	)
+	if profile.TimeDependent() {
+		key += fmt.Sprintf(":t%d", time.Now().Truncate(s.departureBucket).Unix())
+	}
+	return key
+}
+
+// precheckedCost returns the cost of a pair that needs no provider call: zero
+// and reachable for points closer than the minimum separation, or unreachable
+// for points beyond the profile's distance limit.
+func (s *Service) precheckedCost(origin, destination Coordinate, profile RouteProfile) (RouteCost, bool) {
+	if s.CheckSeparation(origin, destination) != nil {
+		return RouteCost{Reachable: true}, true
+	}
+	if s.CheckDistance(profile, origin, destination) != nil {
+		return RouteCost{}, true
+	}
+	return RouteCost{}, false
+}
+
+// cachedCost looks up a fresh cost for the cache key. Grid-cached directions
+// are not used, since they may be for nearby points. Caller must hold s.mu.
+func (s *Service) cachedCost(key string, now time.Time) (RouteCost, bool) {
+	if cached, ok := s.costs.Get(key); ok && now.Before(cached.expiresAt) {
+		return cached.cost, true
+	}
+	return RouteCost{}, false
+}
+
+// fetchMatrix fetches the uncached pairs from a native matrix provider in one request.
+// Only the rows and columns containing uncached pairs are requested.
+func (s *Service) fetchMatrix(
+	ctx context.Context,
+	mp MatrixProvider,
+	origins, destinations []Coordinate,
+	profile RouteProfile,
+	missing []matrixPair,
+	matrix [][]RouteCost,
+) error {
+	rowIndex := make(map[int]int)
+	colIndex := make(map[int]int)
+	req := MatrixRequest{Profile: profile}
+	for _, p := range missing {
+		if _, ok := rowIndex[p.origin]; !ok {
+			rowIndex[p.origin] = len(req.Origins)
+			req.Origins = append(req.Origins, origins[p.origin])
+		}
+		if _, ok := colIndex[p.destination]; !ok {
+			colIndex[p.destination] = len(req.Destinations)
+			req.Destinations = append(req.Destinations, destinations[p.destination])
+		}
+	}
+
+	costs, err := mp.GetMatrix(ctx, req)
+	if err != nil {
+		telemetry.Logger(ctx, s.logger).Error().Err(err).
+			Int("origins", len(req.Origins)).
+			Int("destinations", len(req.Destinations)).
+			Str("profile", string(profile)).
+			Msg("failed to fetch distance matrix")
+		return err
+	}
+	if !matrixHasShape(costs, len(req.Origins), len(req.Destinations)) {
+		return &Error{
+			Provider: s.provider.Name(),
+			Code:     "INVALID_MATRIX",
+			Message:  "routing provider returned a malformed distance matrix",
+			Err:      ErrProviderUnavailable,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, p := range missing {
+		cost := costs[rowIndex[p.origin]][colIndex[p.destination]]
+		matrix[p.origin][p.destination] = cost
+		s.storeCost(s.matrixCacheKey(origins[p.origin], destinations[p.destination], profile), cost, now)
+	}
+	s.cleanupIfNeeded()
+
+	return nil
+}
+
+// fetchPairs computes uncached pairs with one provider directions call each,
+// for providers without a native matrix API. The calls bypass the grid cache
+// so each pair's cost is for its exact coordinates.
+func (s *Service) fetchPairs(
+	ctx context.Context,
+	origins, destinations []Coordinate,
+	profile RouteProfile,
+	missing []matrixPair,
+	matrix [][]RouteCost,
+) error {
+	for _, p := range missing {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		req := DirectionsRequest{
+			Origin:          origins[p.origin],
+			Destination:     destinations[p.destination],
+			Profile:         profile,
+			MaxAlternatives: 1,
+		}
+		// Unreachable pairs are cached too, so they are not retried until expiry
+		var cost RouteCost
+		resp, err := s.provider.GetDirections(ctx, req)
+		switch {
+		case err == nil:
+			cost = routeCost(resp)
+		case !errors.Is(err, ErrNoRouteFound):
+			telemetry.Logger(ctx, s.logger).Error().Err(err).
+				Str("profile", string(profile)).
+				Msg("failed to fetch directions for distance matrix")
+			return err
+		}
+
+		matrix[p.origin][p.destination] = cost
+		s.mu.Lock()
+		s.storeCost(s.matrixCacheKey(req.Origin, req.Destination, profile), cost, time.Now())
+		s.cleanupIfNeeded()
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// storeCost caches a matrix cost. Caller must hold s.mu for writing.
+func (s *Service) storeCost(key string, cost RouteCost, now time.Time) {
+	entry := &cachedCost{
+		cost:      cost,
+		fetchedAt: now,
+		expiresAt: now.Add(s.currentCacheTTL()),
+	}
+	s.costs.Set(key, entry, entry.expiresAt)
+}
+
+// matrixHasShape reports whether the matrix has the given number of rows and columns.
+func matrixHasShape(matrix [][]RouteCost, rows, cols int) bool {
+	if len(matrix) != rows {
+		return false
+	}
+	for _, row := range matrix {
+		if len(row) != cols {
+			return false
+		}
+	}
+	return true
+}
+
+// routeCost returns the cost of the primary route in a directions response.
+func routeCost(resp *DirectionsResponse) RouteCost {
+	if resp == nil || len(resp.Routes) == 0 {
+		return RouteCost{}
+	}
+	return RouteCost{
+		DurationSeconds: resp.Routes[0].DurationSeconds,
+		DistanceMeters:  resp.Routes[0].DistanceMeters,
+		Reachable:       true,
+	}
+}
@@ -0,0 +1,252 @@
+package routing
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// mockMatrixProvider is a mock provider with a native matrix API.
+type mockMatrixProvider struct {
+	mockProvider
+	matrixCalls atomic.Int32
+	lastRequest MatrixRequest
+}
+
+func (m *mockMatrixProvider) GetMatrix(_ context.Context, req MatrixRequest) ([][]RouteCost, error) {
+	m.matrixCalls.Add(1)
+	m.lastRequest = req
+	costs := make([][]RouteCost, len(req.Origins))
+	for o := range costs {
+		costs[o] = make([]RouteCost, len(req.Destinations))
+		for d := range costs[o] {
+			costs[o][d] = RouteCost{DurationSeconds: 100 * (o + 1), DistanceMeters: 10 * (d + 1), Reachable: true}
+		}
+	}
+	return costs, nil
+}
+
+// Candidate stations ~2km apart so each falls in its own cache grid cell.
+var (
+	matrixOrigins = []Coordinate{
+		{Lat: 52.3676, Lon: 4.9041},
+		{Lat: 52.3876, Lon: 4.8741},
+	}
+	matrixDestinations = []Coordinate{
+		{Lat: 52.0907, Lon: 5.1214},
+		{Lat: 52.1107, Lon: 5.0914},
+		{Lat: 52.0707, Lon: 5.1514},
+	}
+)
+
+func TestService_DistanceMatrix_FallbackToDirections(t *testing.T) {
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileWalk},
+		response: &DirectionsResponse{
+			Routes: []Route{
+				{DistanceMeters: 1200, DurationSeconds: 900},
+				{DistanceMeters: 1500, DurationSeconds: 1000},
+			},
+		},
+	}
+	service := NewService(ServiceConfig{Provider: provider})
+
+	matrix, err := service.DistanceMatrix(context.Background(), matrixOrigins, matrixDestinations, ProfileWalk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := provider.callCount.Load(); got != 6 {
+		t.Errorf("expected 6 provider calls, got %d", got)
+	}
+	if len(matrix) != 2 || len(matrix[0]) != 3 {
+		t.Fatalf("expected 2x3 matrix, got %dx%d", len(matrix), len(matrix[0]))
+	}
+	if cost := matrix[1][2]; cost.DurationSeconds != 900 || cost.DistanceMeters != 1200 || !cost.Reachable {
+		t.Errorf("expected primary route cost, got %+v", cost)
+	}
+
+	// Second call is served entirely from the cost cache
+	if _, err := service.DistanceMatrix(context.Background(), matrixOrigins, matrixDestinations, ProfileWalk); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := provider.callCount.Load(); got != 6 {
+		t.Errorf("expected no additional provider calls, got %d", got)
+	}
+}
+
+func TestService_DistanceMatrix_NativeMatrixFetchesOnlyUncachedPairs(t *testing.T) {
+	provider := &mockMatrixProvider{
+		mockProvider: mockProvider{
+			name:     "test-provider",
+			profiles: []RouteProfile{ProfileWalk},
+		},
+	}
+	service := NewService(ServiceConfig{Provider: provider})
+
+	// Warm the cache for origin 0 to every destination
+	if _, err := service.DistanceMatrix(context.Background(), matrixOrigins[:1], matrixDestinations, ProfileWalk); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	matrix, err := service.DistanceMatrix(context.Background(), matrixOrigins, matrixDestinations, ProfileWalk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := provider.matrixCalls.Load(); got != 2 {
+		t.Fatalf("expected 2 matrix calls, got %d", got)
+	}
+	if len(provider.lastRequest.Origins) != 1 || len(provider.lastRequest.Destinations) != 3 {
+		t.Errorf("expected 1x3 matrix request for uncached pairs, got %dx%d",
+			len(provider.lastRequest.Origins), len(provider.lastRequest.Destinations))
+	}
+	if cost := matrix[0][1]; cost.DurationSeconds != 100 || cost.DistanceMeters != 20 {
+		t.Errorf("expected cached cost for [0][1], got %+v", cost)
+	}
+	if cost := matrix[1][2]; cost.DurationSeconds != 100 || cost.DistanceMeters != 30 {
+		t.Errorf("expected matrix cost for [1][2], got %+v", cost)
+	}
+
+	// Every pair is cached now
+	if _, err := service.DistanceMatrix(context.Background(), matrixOrigins, matrixDestinations, ProfileWalk); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := provider.matrixCalls.Load(); got != 2 {
+		t.Errorf("expected no additional matrix calls, got %d", got)
+	}
+}
+
+func TestService_DistanceMatrix_NearbyPointsNotShared(t *testing.T) {
+	provider := &mockMatrixProvider{
+		mockProvider: mockProvider{
+			name:     "test-provider",
+			profiles: []RouteProfile{ProfileWalk},
+			response: &DirectionsResponse{
+				Routes: []Route{{DistanceMeters: 1200, DurationSeconds: 900}},
+			},
+		},
+	}
+	service := NewService(ServiceConfig{Provider: provider})
+
+	// Two candidates ~100m apart share a directions grid cell
+	origins := []Coordinate{{Lat: 52.3676, Lon: 4.9041}, {Lat: 52.3685, Lon: 4.9041}}
+	if service.cacheKey(DirectionsRequest{Origin: origins[0], Destination: matrixDestinations[0], Profile: ProfileWalk}) !=
+		service.cacheKey(DirectionsRequest{Origin: origins[1], Destination: matrixDestinations[0], Profile: ProfileWalk}) {
+		t.Fatal("test origins should share a grid cell")
+	}
+
+	// Cached directions for the cell are not used for the matrix
+	if _, err := service.GetDirections(context.Background(), DirectionsRequest{
+		Origin: origins[0], Destination: matrixDestinations[0], Profile: ProfileWalk,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.DistanceMatrix(context.Background(), origins[:1], matrixDestinations[:1], ProfileWalk); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := provider.matrixCalls.Load(); got != 1 {
+		t.Fatalf("expected 1 matrix call, got %d", got)
+	}
+
+	// The neighbour gets its own cost instead of the cached one
+	if _, err := service.DistanceMatrix(context.Background(), origins[1:], matrixDestinations[:1], ProfileWalk); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := provider.matrixCalls.Load(); got != 2 {
+		t.Errorf("expected a matrix call for the neighbouring origin, got %d calls", got)
+	}
+}
+
+func TestService_DistanceMatrix_UnreachablePair(t *testing.T) {
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileWalk},
+		err:      &Error{Provider: "test-provider", Code: "NO_ROUTE", Err: ErrNoRouteFound},
+	}
+	service := NewService(ServiceConfig{Provider: provider})
+
+	matrix, err := service.DistanceMatrix(context.Background(), matrixOrigins[:1], matrixDestinations[:1], ProfileWalk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matrix[0][0].Reachable {
+		t.Error("expected pair to be unreachable")
+	}
+}
+
+func TestService_DistanceMatrix_PrecheckedPairs(t *testing.T) {
+	// Points on a meridian, about 1.1 km apart per 0.01°
+	origins := []Coordinate{{Lat: 52.00, Lon: 5.0}, {Lat: 52.02, Lon: 5.0}}
+
+	tests := []struct {
+		name           string
+		destinations   []Coordinate
+		maxDistance    float64
+		badDestination int
+		bad            RouteCost
+	}{
+		{
+			name:           "coincident pair costs nothing",
+			destinations:   []Coordinate{{Lat: 52.00, Lon: 5.0}, {Lat: 52.04, Lon: 5.0}},
+			badDestination: 0,
+			bad:            RouteCost{Reachable: true},
+		},
+		{
+			name:           "over-long pair is unreachable",
+			destinations:   []Coordinate{{Lat: 52.01, Lon: 5.0}, {Lat: 52.06, Lon: 5.0}},
+			maxDistance:    5000,
+			badDestination: 1,
+			bad:            RouteCost{},
+		},
+	}
+
+	for _, tt := range tests {
+		providers := map[string]Provider{
+			"directions": &mockProvider{
+				name:     "test-provider",
+				profiles: []RouteProfile{ProfileWalk},
+				response: &DirectionsResponse{Routes: []Route{{DistanceMeters: 1200, DurationSeconds: 900}}},
+			},
+			"native matrix": &mockMatrixProvider{mockProvider: mockProvider{name: "test-provider"}},
+		}
+		for kind, provider := range providers {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				cfg := ServiceConfig{Provider: provider}
+				if tt.maxDistance > 0 {
+					cfg.MaxDistanceMeters = map[RouteProfile]float64{ProfileWalk: tt.maxDistance}
+				}
+				service := NewService(cfg)
+
+				matrix, err := service.DistanceMatrix(context.Background(), origins, tt.destinations, ProfileWalk)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				for o := range matrix {
+					for d, cost := range matrix[o] {
+						switch {
+						case o == 0 && d == tt.badDestination:
+							if cost != tt.bad {
+								t.Errorf("expected bad pair cost %+v, got %+v", tt.bad, cost)
+							}
+						case cost.DurationSeconds == 0 || !cost.Reachable:
+							t.Errorf("expected pair %d,%d to be fetched, got %+v", o, d, cost)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestService_DistanceMatrix_InvalidCoordinates(t *testing.T) {
+	service := NewService(ServiceConfig{Provider: &mockProvider{name: "test-provider"}})
+
+	_, err := service.DistanceMatrix(context.Background(),
+		[]Coordinate{{Lat: 91, Lon: 0}}, matrixDestinations, ProfileWalk)
+	if err == nil {
+		t.Fatal("expected error for invalid origin")
+	}
+}
@@ -4,6 +4,7 @@ package routing
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,10 @@ var (
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 	// ErrInvalidCoordinates indicates the provided coordinates are invalid or out of range.
 	ErrInvalidCoordinates = errors.New("invalid coordinates")
+	// ErrRouteTooLong indicates the points are further apart than the profile allows.
+	ErrRouteTooLong = errors.New("route exceeds maximum distance")
+	// ErrSameOriginDestination indicates the origin and destination are too close to route between.
+	ErrSameOriginDestination = errors.New("origin and destination are the same")
 )
 
 // Provider defines the interface for routing providers.
@@ -30,6 +35,14 @@ type Provider interface {
 	SupportedProfiles() []RouteProfile
 }
 
+// MatrixProvider is implemented by providers with a native distance matrix API.
+// Providers without it are served by one GetDirections call per pair.
+type MatrixProvider interface {
+	// GetMatrix returns the travel cost for every origin-destination pair,
+	// indexed as [origin][destination].
+	GetMatrix(ctx context.Context, req MatrixRequest) ([][]RouteCost, error)
+}
+
 // RouteProfile represents a routing profile (mode of transport).
 type RouteProfile string
 
@@ -38,8 +51,17 @@ const (
 	ProfileWalk RouteProfile = "foot-walking"
 	// ProfileBike is the cycling-regular profile for bike routing.
 	ProfileBike RouteProfile = "cycling-regular"
+	// ProfileTransit is the public-transport profile. Transit routes follow
+	// timetables, so they depend on the departure time.
+	ProfileTransit RouteProfile = "public-transport"
 )
 
+// TimeDependent reports whether routes for this profile change with the
+// departure time. Walk and bike routes do not.
+func (p RouteProfile) TimeDependent() bool {
+	return p == ProfileTransit
+}
+
 // Coordinate represents a geographic point.
 type Coordinate struct {
 	Lat float64
@@ -51,7 +73,72 @@ type DirectionsRequest struct {
 	Origin          Coordinate
 	Destination     Coordinate
 	Profile         RouteProfile
-	MaxAlternatives int // Maximum number of alternative routes to return (default: 2)
+	MaxAlternatives int  // Maximum number of alternative routes to return (default: 2)
+	FullGeometry    bool // Return full-resolution geometry instead of simplified (cached separately)
+	// DepartureTime is when the trip starts (zero means now). Only used by
+	// time-dependent profiles such as ProfileTransit.
+	DepartureTime time.Time
+	// Constraints are the features the route should avoid. Providers ignore
+	// the ones a profile does not support.
+	Constraints RouteConstraints
+
+	InstructionOptions
+}
+
+// RouteConstraints are features a route should avoid where the provider
+// supports it for the profile. The zero value avoids nothing.
+type RouteConstraints struct {
+	AvoidMajorRoads bool
+	AvoidFerries    bool
+	AvoidUnpaved    bool
+
+	// AvoidCongestionZones keeps routes out of the busy city centres in the
+	// provider's zones, by default LowEmissionZones. Traffic bans usually
+	// make the zones cleaner inside, so this can raise exposure:
+	// lowest-exposure ranking only chooses among routes that stay out.
+	AvoidCongestionZones bool
+}
+
+// IsZero reports whether no constraint is set.
+func (c RouteConstraints) IsZero() bool {
+	return c == RouteConstraints{}
+}
+
+// InstructionOptions selects the turn-by-turn instructions returned with a
+// route. The zero value returns none, which keeps responses small for
+// callers that only need geometry or travel costs.
+type InstructionOptions struct {
+	// IncludeInstructions requests turn-by-turn instructions.
+	IncludeInstructions bool
+
+	// Language is the language of the instructions, as a BCP 47 tag or ISO
+	// 639-1 code (e.g., "nl-NL" or "nl"). Providers fall back to English when
+	// it is empty or unsupported.
+	Language string
+}
+
+// LanguageCode returns the lower-case primary language subtag of a BCP 47
+// tag (e.g., "nl" for "nl-NL"), or "" if tag is empty.
+func LanguageCode(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
+
+// MatrixRequest is the request for computing travel costs between many points.
+type MatrixRequest struct {
+	Origins      []Coordinate
+	Destinations []Coordinate
+	Profile      RouteProfile
+}
+
+// RouteCost is the travel cost for a single origin-destination pair.
+type RouteCost struct {
+	DurationSeconds int  // Travel duration in seconds
+	DistanceMeters  int  // Travel distance in meters
+	Reachable       bool // False if no route exists between the pair
 }
 
 // DirectionsResponse is the response containing route alternatives.
@@ -66,9 +153,12 @@ type Route struct {
 	GeometryPolyline string        // Encoded polyline (precision 5)
 	DistanceMeters   int           // Total distance in meters
 	DurationSeconds  int           // Total duration in seconds
+	AscentMeters     int           // Total elevation gain in meters (0 if unavailable)
+	DescentMeters    int           // Total elevation loss in meters (0 if unavailable)
 	Summary          string        // Human-readable route summary
 	BoundingBox      *BoundingBox  // Geographic bounding box
 	Instructions     []Instruction // Turn-by-turn instructions
+	RoadNames        []string      // Significant road/path names in travel order (de-duplicated)
 }
 
 // BoundingBox represents a geographic bounding box.
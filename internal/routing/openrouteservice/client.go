@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/breatheroute/breatheroute/internal/provider/resilience"
 	"github.com/breatheroute/breatheroute/internal/routing"
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
 )
 
 const (
@@ -25,8 +28,99 @@ const (
 
 	// DefaultTimeout is the default request timeout.
 	DefaultTimeout = 10 * time.Second
+
+	// DefaultMaxConcurrent is the default cap on in-flight ORS requests.
+	DefaultMaxConcurrent = 20
+
+	// minRoadNameDistance is the minimum step length (in meters) for a road
+	// name to be considered significant enough to include in Route.RoadNames.
+	minRoadNameDistance = 200.0
+
+	// unnamedRoad is the placeholder ORS uses for steps without a road name.
+	unnamedRoad = "-"
+
+	// defaultLanguage is the instruction language used when the requested
+	// one is empty or not supported by ORS.
+	defaultLanguage = "en"
+
+	// zoneMarginDegrees is how far beyond the origin-destination bounding
+	// box (about 5 km) avoided zones are still sent.
+	zoneMarginDegrees = 0.05
 )
 
+// supportedLanguages are the instruction languages ORS accepts.
+var supportedLanguages = map[string]bool{
+	"cs": true, "da": true, "de": true, "en": true, "eo": true, "es": true,
+	"fi": true, "fr": true, "gr": true, "he": true, "hu": true, "id": true,
+	"it": true, "ja": true, "nb": true, "ne": true, "nl": true, "pl": true,
+	"pt": true, "ro": true, "ru": true, "tr": true, "zh": true,
+}
+
+// instructionLanguage returns the ORS language code for a BCP 47 tag,
+// falling back to English if ORS does not support it.
+func instructionLanguage(tag string) string {
+	if code := routing.LanguageCode(tag); supportedLanguages[code] {
+		return code
+	}
+	return defaultLanguage
+}
+
+// routeOptions maps constraints to ORS options for profile, avoiding zones
+// if asked to. Constraints the profile does not support are returned by name
+// so the caller can warn about them; options is nil when nothing is sent.
+func routeOptions(profile routing.RouteProfile, c routing.RouteConstraints, zones []routing.Zone) (options *orsOptions, unsupported []string) {
+	walking := profile == routing.ProfileWalk
+	cycling := profile == routing.ProfileBike
+
+	opts := &orsOptions{}
+	if c.AvoidFerries {
+		if walking || cycling {
+			opts.AvoidFeatures = append(opts.AvoidFeatures, "ferries")
+		} else {
+			unsupported = append(unsupported, "avoidFerries")
+		}
+	}
+	if c.AvoidMajorRoads {
+		// ORS only avoids highways for driving; foot routes can prefer
+		// quiet ways instead
+		if walking {
+			opts.ProfileParams = &orsProfileParams{Weightings: &orsWeightings{Quiet: 1}}
+		} else {
+			unsupported = append(unsupported, "avoidMajorRoads")
+		}
+	}
+	if c.AvoidUnpaved {
+		// Surface restrictions are only available for wheelchair routing
+		unsupported = append(unsupported, "avoidUnpaved")
+	}
+	if c.AvoidCongestionZones && len(zones) > 0 {
+		if walking || cycling {
+			opts.AvoidPolygons = zonePolygons(zones)
+		} else {
+			unsupported = append(unsupported, "avoidCongestionZones")
+		}
+	}
+
+	if len(opts.AvoidFeatures) == 0 && opts.AvoidPolygons == nil && opts.ProfileParams == nil {
+		return nil, unsupported
+	}
+	return opts, unsupported
+}
+
+// zonePolygons converts zones to a GeoJSON MultiPolygon, closing each ring.
+func zonePolygons(zones []routing.Zone) *geoJSONPolygons {
+	polygons := &geoJSONPolygons{Type: "MultiPolygon"}
+	for _, z := range zones {
+		ring := make([][]float64, 0, len(z.Polygon)+1)
+		for _, c := range z.Polygon {
+			ring = append(ring, []float64{c.Lon, c.Lat})
+		}
+		ring = append(ring, ring[0])
+		polygons.Coordinates = append(polygons.Coordinates, [][][]float64{ring})
+	}
+	return polygons
+}
+
 // HTTPDoer is an interface for executing HTTP requests.
 type HTTPDoer interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -50,6 +144,14 @@ type ClientConfig struct {
 	// Registry is the provider registry for health tracking (optional).
 	Registry *resilience.Registry
 
+	// MaxConcurrent caps in-flight ORS requests; further requests queue
+	// (optional, defaults to 20).
+	MaxConcurrent int
+
+	// AvoidZones are the zones routes avoiding congestion zones stay out of
+	// (optional, defaults to routing.LowEmissionZones).
+	AvoidZones []routing.Zone
+
 	// Logger for client operations.
 	Logger zerolog.Logger
 }
@@ -59,6 +161,7 @@ type Client struct {
 	apiKey     string
 	baseURL    string
 	httpClient HTTPDoer
+	avoidZones []routing.Zone
 	logger     zerolog.Logger
 }
 
@@ -78,16 +181,27 @@ func NewClient(cfg ClientConfig) *Client {
 	if httpClient == nil {
 		clientCfg := resilience.DefaultClientConfig(ProviderName)
 		clientCfg.Timeout = timeout
+		clientCfg.Logger = cfg.Logger
+		clientCfg.MaxConcurrent = cfg.MaxConcurrent
+		if clientCfg.MaxConcurrent == 0 {
+			clientCfg.MaxConcurrent = DefaultMaxConcurrent
+		}
 		if cfg.Registry != nil {
 			clientCfg.Registry = cfg.Registry
 		}
 		httpClient = resilience.NewClient(clientCfg)
 	}
 
+	avoidZones := cfg.AvoidZones
+	if avoidZones == nil {
+		avoidZones = routing.LowEmissionZones
+	}
+
 	return &Client{
 		apiKey:     cfg.APIKey,
 		baseURL:    baseURL,
 		httpClient: httpClient,
+		avoidZones: avoidZones,
 		logger:     cfg.Logger,
 	}
 }
@@ -141,19 +255,117 @@ func (c *Client) GetDirections(ctx context.Context, req routing.DirectionsReques
 		AlternativeRoutes: &alternativeRoutesOpts{
 			TargetCount: maxAlts + 1, // +1 because the first route is not counted as alternative
 		},
-		Instructions: true,
+		Instructions: req.IncludeInstructions,
 		Geometry:     true,
+		Elevation:    true,
 		Units:        "m",
-		Language:     "en",
+		Language:     instructionLanguage(req.Language),
 	}
 
-	body, err := json.Marshal(orsReq)
+	var zones []routing.Zone
+	if req.Constraints.AvoidCongestionZones {
+		zones = routing.ZonesNear(c.avoidZones, zoneMarginDegrees, req.Origin, req.Destination)
+	}
+	options, unsupported := routeOptions(req.Profile, req.Constraints, zones)
+	orsReq.Options = options
+	if len(unsupported) > 0 {
+		telemetry.Logger(ctx, c.logger).Warn().
+			Str("profile", string(req.Profile)).
+			Strs("constraints", unsupported).
+			Msg("ignoring route constraints not supported by profile")
+	}
+
+	telemetry.Logger(ctx, c.logger).Debug().
+		Str("profile", string(req.Profile)).
+		Float64("origin_lat", req.Origin.Lat).
+		Float64("origin_lon", req.Origin.Lon).
+		Float64("dest_lat", req.Destination.Lat).
+		Float64("dest_lon", req.Destination.Lon).
+		Msg("requesting directions from ORS")
+
+	respBody, err := c.post(ctx, fmt.Sprintf("%s/v2/directions/%s", c.baseURL, req.Profile), orsReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse successful response
+	var orsResp orsResponse
+	if err := json.Unmarshal(respBody, &orsResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	// Convert to domain model
+	result := c.toDirectionsResponse(&orsResp)
+
+	telemetry.Logger(ctx, c.logger).Debug().
+		Int("route_count", len(result.Routes)).
+		Msg("received directions from ORS")
+
+	return result, nil
+}
+
+// GetMatrix retrieves travel durations and distances between every origin and
+// destination in a single request to the ORS matrix API.
+func (c *Client) GetMatrix(ctx context.Context, req routing.MatrixRequest) ([][]routing.RouteCost, error) {
+	orsReq := orsMatrixRequest{
+		Locations:    make([][]float64, 0, len(req.Origins)+len(req.Destinations)),
+		Sources:      make([]int, 0, len(req.Origins)),
+		Destinations: make([]int, 0, len(req.Destinations)),
+		Metrics:      []string{"duration", "distance"},
+		Units:        "m",
+	}
+	for _, o := range req.Origins {
+		if err := validateCoordinates(o); err != nil {
+			return nil, &routing.Error{
+				Provider: ProviderName,
+				Code:     "INVALID_ORIGIN",
+				Message:  "invalid origin coordinates",
+				Err:      routing.ErrInvalidCoordinates,
+			}
+		}
+		orsReq.Sources = append(orsReq.Sources, len(orsReq.Locations))
+		orsReq.Locations = append(orsReq.Locations, []float64{o.Lon, o.Lat})
+	}
+	for _, d := range req.Destinations {
+		if err := validateCoordinates(d); err != nil {
+			return nil, &routing.Error{
+				Provider: ProviderName,
+				Code:     "INVALID_DESTINATION",
+				Message:  "invalid destination coordinates",
+				Err:      routing.ErrInvalidCoordinates,
+			}
+		}
+		orsReq.Destinations = append(orsReq.Destinations, len(orsReq.Locations))
+		orsReq.Locations = append(orsReq.Locations, []float64{d.Lon, d.Lat})
+	}
+
+	telemetry.Logger(ctx, c.logger).Debug().
+		Str("profile", string(req.Profile)).
+		Int("origins", len(req.Origins)).
+		Int("destinations", len(req.Destinations)).
+		Msg("requesting matrix from ORS")
+
+	respBody, err := c.post(ctx, fmt.Sprintf("%s/v2/matrix/%s", c.baseURL, req.Profile), orsReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var orsResp orsMatrixResponse
+	if err := json.Unmarshal(respBody, &orsResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return toRouteCosts(&orsResp, len(req.Origins), len(req.Destinations)), nil
+}
+
+// post sends a JSON request to the ORS API and returns the response body.
+// Non-200 responses are mapped to domain errors.
+func (c *Client) post(ctx context.Context, url string, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling request: %w", err)
 	}
 
-	// Build HTTP request
-	url := fmt.Sprintf("%s/v2/directions/%s", c.baseURL, req.Profile)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -163,14 +375,6 @@ func (c *Client) GetDirections(ctx context.Context, req routing.DirectionsReques
 	httpReq.Header.Set("Authorization", c.apiKey)
 	httpReq.Header.Set("Accept", "application/json, application/geo+json")
 
-	c.logger.Debug().
-		Str("profile", string(req.Profile)).
-		Float64("origin_lat", req.Origin.Lat).
-		Float64("origin_lon", req.Origin.Lon).
-		Float64("dest_lat", req.Destination.Lat).
-		Float64("dest_lon", req.Destination.Lon).
-		Msg("requesting directions from ORS")
-
 	// Execute request
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -194,20 +398,7 @@ func (c *Client) GetDirections(ctx context.Context, req routing.DirectionsReques
 		return nil, c.handleErrorResponse(resp.StatusCode, respBody)
 	}
 
-	// Parse successful response
-	var orsResp orsResponse
-	if err := json.Unmarshal(respBody, &orsResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	// Convert to domain model
-	result := c.toDirectionsResponse(&orsResp)
-
-	c.logger.Debug().
-		Int("route_count", len(result.Routes)).
-		Msg("received directions from ORS")
-
-	return result, nil
+	return respBody, nil
 }
 
 // handleErrorResponse maps ORS error responses to domain errors.
@@ -279,6 +470,12 @@ func (c *Client) handleErrorResponse(statusCode int, body []byte) error {
 	}
 }
 
+// flattenGeometry converts the 3D polyline ORS returns when elevation is
+// requested into the 2D precision 5 polyline used by the domain model.
+func flattenGeometry(geometry string) string {
+	return polyline.Encode(polyline.Flatten(polyline.Decode3D(geometry)))
+}
+
 // toDirectionsResponse converts ORS response to domain model.
 func (c *Client) toDirectionsResponse(resp *orsResponse) *routing.DirectionsResponse {
 	routes := make([]routing.Route, 0, len(resp.Routes))
@@ -286,19 +483,30 @@ func (c *Client) toDirectionsResponse(resp *orsResponse) *routing.DirectionsResp
 	for i := range resp.Routes {
 		orsRoute := &resp.Routes[i]
 		route := routing.Route{
-			GeometryPolyline: orsRoute.Geometry,
+			GeometryPolyline: flattenGeometry(orsRoute.Geometry),
 			DistanceMeters:   int(orsRoute.Summary.Distance),
 			DurationSeconds:  int(orsRoute.Summary.Duration),
+			AscentMeters:     int(orsRoute.Summary.Ascent),
+			DescentMeters:    int(orsRoute.Summary.Descent),
 		}
 
-		// Extract bounding box if available
-		if len(orsRoute.BBox) >= 4 {
+		// Extract bounding box if available. With elevation it is
+		// [minLon, minLat, minEle, maxLon, maxLat, maxEle].
+		switch len(orsRoute.BBox) {
+		case 4:
 			route.BoundingBox = &routing.BoundingBox{
 				MinLon: orsRoute.BBox[0],
 				MinLat: orsRoute.BBox[1],
 				MaxLon: orsRoute.BBox[2],
 				MaxLat: orsRoute.BBox[3],
 			}
+		case 6:
+			route.BoundingBox = &routing.BoundingBox{
+				MinLon: orsRoute.BBox[0],
+				MinLat: orsRoute.BBox[1],
+				MaxLon: orsRoute.BBox[3],
+				MaxLat: orsRoute.BBox[4],
+			}
 		}
 
 		// Extract instructions from segments
@@ -315,6 +523,8 @@ func (c *Client) toDirectionsResponse(resp *orsResponse) *routing.DirectionsResp
 			}
 		}
 
+		route.RoadNames = extractRoadNames(orsRoute.Segments)
+
 		// Generate summary from first and last instruction
 		if len(route.Instructions) > 0 {
 			route.Summary = generateRouteSummary(route.Instructions)
@@ -355,6 +565,61 @@ func generateRouteSummary(instructions []routing.Instruction) string {
 	return ""
 }
 
+// extractRoadNames returns the significant road names along a route in travel order.
+// Steps shorter than minRoadNameDistance and unnamed steps are skipped, and each
+// name is included only once.
+func extractRoadNames(segments []routeSegment) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for i := range segments {
+		for j := range segments[i].Steps {
+			step := &segments[i].Steps[j]
+			name := strings.TrimSpace(step.Name)
+			if name == "" || name == unnamedRoad || step.Distance < minRoadNameDistance {
+				continue
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// toRouteCosts converts an ORS matrix response to route costs.
+// Null or missing entries are reported as unreachable.
+func toRouteCosts(resp *orsMatrixResponse, origins, destinations int) [][]routing.RouteCost {
+	costs := make([][]routing.RouteCost, origins)
+	for o := range costs {
+		costs[o] = make([]routing.RouteCost, destinations)
+		for d := range costs[o] {
+			duration := matrixValue(resp.Durations, o, d)
+			distance := matrixValue(resp.Distances, o, d)
+			if duration == nil || distance == nil {
+				continue
+			}
+			costs[o][d] = routing.RouteCost{
+				DurationSeconds: int(*duration),
+				DistanceMeters:  int(*distance),
+				Reachable:       true,
+			}
+		}
+	}
+	return costs
+}
+
+// matrixValue returns the matrix entry at [row][col], or nil if absent.
+func matrixValue(matrix [][]*float64, row, col int) *float64 {
+	if row >= len(matrix) || col >= len(matrix[row]) {
+		return nil
+	}
+	return matrix[row][col]
+}
+
 // validateCoordinates checks if coordinates are within valid ranges.
 func validateCoordinates(c routing.Coordinate) error {
 	if c.Lat < -90 || c.Lat > 90 {
@@ -365,3 +630,9 @@ func validateCoordinates(c routing.Coordinate) error {
 	}
 	return nil
 }
+
+// Ensure Client implements the routing provider interfaces.
+var (
+	_ routing.Provider       = (*Client)(nil)
+	_ routing.MatrixProvider = (*Client)(nil)
+)
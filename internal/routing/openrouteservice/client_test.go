@@ -2,15 +2,18 @@ package openrouteservice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/rs/zerolog"
 
 	"github.com/breatheroute/breatheroute/internal/routing"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
 )
 
 func TestClient_GetDirections_Success(t *testing.T) {
@@ -92,6 +95,109 @@ func TestClient_GetDirections_Success(t *testing.T) {
 	}
 }
 
+func TestClient_GetDirections_ElevationGeometry(t *testing.T) {
+	respBody, err := os.ReadFile("testdata/directions_elevation_response.json")
+	if err != nil {
+		t.Fatalf("failed to load test fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(respBody)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		APIKey:     "mock123",
+		BaseURL:    server.URL,
+		HTTPClient: &mockHTTPClient{client: server.Client()},
+		Logger:     zerolog.Nop(),
+	})
+
+	resp, err := client.GetDirections(context.Background(), routing.DirectionsRequest{
+		Origin:      routing.Coordinate{Lat: 52.37891, Lon: 4.90023},
+		Destination: routing.Coordinate{Lat: 52.35800, Lon: 4.86850},
+		Profile:     routing.ProfileBike,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	route := resp.Routes[0]
+
+	// The 3D geometry is returned as a 2D polyline ending at the destination
+	coords := polyline.Decode(route.GeometryPolyline)
+	if len(coords) != 7 {
+		t.Fatalf("expected 7 coordinates, got %d", len(coords))
+	}
+	first, last := coords[0], coords[len(coords)-1]
+	if first.Lat != 52.37891 || first.Lon != 4.90023 {
+		t.Errorf("expected first coordinate at the origin, got %+v", first)
+	}
+	if last.Lat != 52.358 || last.Lon != 4.8685 {
+		t.Errorf("expected last coordinate at the destination, got %+v", last)
+	}
+	if length := polyline.Length(coords); length < 2500 || length > 3500 {
+		t.Errorf("expected geometry length near the route distance, got %.0f m", length)
+	}
+
+	if route.AscentMeters != 2 || route.DescentMeters != 3 {
+		t.Errorf("expected ascent 2 and descent 3, got %d and %d", route.AscentMeters, route.DescentMeters)
+	}
+
+	// The 3D bounding box skips the elevation values
+	want := routing.BoundingBox{MinLon: 4.8685, MinLat: 52.358, MaxLon: 4.90023, MaxLat: 52.37891}
+	if route.BoundingBox == nil || *route.BoundingBox != want {
+		t.Errorf("expected bounding box %+v, got %+v", want, route.BoundingBox)
+	}
+}
+
+func TestClient_GetDirections_RoadNames(t *testing.T) {
+	respBody, err := os.ReadFile("testdata/named_steps_response.json")
+	if err != nil {
+		t.Fatalf("failed to load test fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(respBody)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		APIKey:     "mock123",
+		BaseURL:    server.URL,
+		HTTPClient: &mockHTTPClient{client: server.Client()},
+		Logger:     zerolog.Nop(),
+	})
+
+	resp, err := client.GetDirections(context.Background(), routing.DirectionsRequest{
+		Origin:      routing.Coordinate{Lat: 52.3637, Lon: 4.8828},
+		Destination: routing.Coordinate{Lat: 52.3445, Lon: 4.8585},
+		Profile:     routing.ProfileBike,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Routes) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(resp.Routes))
+	}
+
+	// Short steps (Leidseplein, arrival) and unnamed steps are dropped,
+	// and the second Overtoom step is not repeated.
+	expected := []string{"Overtoom", "Vondelpark", "Amstelveenseweg"}
+	got := resp.Routes[0].RoadNames
+	if len(got) != len(expected) {
+		t.Fatalf("expected road names %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("road name %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+}
+
 func TestClient_GetDirections_NoRouteFound(t *testing.T) {
 	// Load test fixture
 	respBody, err := os.ReadFile("testdata/error_response.json")
@@ -132,6 +238,199 @@ func TestClient_GetDirections_NoRouteFound(t *testing.T) {
 	}
 }
 
+func TestClient_GetDirections_Instructions(t *testing.T) {
+	respBody, err := os.ReadFile("testdata/directions_response.json")
+	if err != nil {
+		t.Fatalf("failed to load test fixture: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		opts             routing.InstructionOptions
+		wantInstructions bool
+		wantLanguage     string
+	}{
+		{"dutch locale", routing.InstructionOptions{IncludeInstructions: true, Language: "nl-NL"}, true, "nl"},
+		{"language code", routing.InstructionOptions{IncludeInstructions: true, Language: "DE"}, true, "de"},
+		{"unsupported language", routing.InstructionOptions{IncludeInstructions: true, Language: "fy-NL"}, true, "en"},
+		{"no language", routing.InstructionOptions{IncludeInstructions: true}, true, "en"},
+		{"instructions skipped", routing.InstructionOptions{}, false, "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req orsRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("failed to decode request: %v", err)
+				}
+				if req.Instructions != tt.wantInstructions {
+					t.Errorf("instructions = %v, want %v", req.Instructions, tt.wantInstructions)
+				}
+				if req.Language != tt.wantLanguage {
+					t.Errorf("language = %q, want %q", req.Language, tt.wantLanguage)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(respBody)
+			}))
+			defer server.Close()
+
+			client := NewClient(ClientConfig{
+				APIKey:     "mock123",
+				BaseURL:    server.URL,
+				HTTPClient: &mockHTTPClient{client: server.Client()},
+				Logger:     zerolog.Nop(),
+			})
+
+			_, err := client.GetDirections(context.Background(), routing.DirectionsRequest{
+				Origin:             routing.Coordinate{Lat: 52.3676, Lon: 4.9041},
+				Destination:        routing.Coordinate{Lat: 52.0907, Lon: 5.1214},
+				Profile:            routing.ProfileBike,
+				InstructionOptions: tt.opts,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_GetDirections_Constraints(t *testing.T) {
+	respBody, err := os.ReadFile("testdata/directions_response.json")
+	if err != nil {
+		t.Fatalf("failed to load test fixture: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		profile       routing.RouteProfile
+		constraints   routing.RouteConstraints
+		wantOptions   bool
+		wantFeatures  []string
+		wantQuietness float64
+	}{
+		{"none", routing.ProfileWalk, routing.RouteConstraints{}, false, nil, 0},
+		{"walk avoiding ferries and major roads", routing.ProfileWalk,
+			routing.RouteConstraints{AvoidFerries: true, AvoidMajorRoads: true}, true, []string{"ferries"}, 1},
+		{"bike avoiding ferries", routing.ProfileBike,
+			routing.RouteConstraints{AvoidFerries: true, AvoidMajorRoads: true}, true, []string{"ferries"}, 0},
+		{"unsupported only", routing.ProfileBike,
+			routing.RouteConstraints{AvoidMajorRoads: true, AvoidUnpaved: true}, false, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req orsRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("failed to decode request: %v", err)
+				}
+				if (req.Options != nil) != tt.wantOptions {
+					t.Fatalf("options = %+v, want present %v", req.Options, tt.wantOptions)
+				}
+				if req.Options != nil {
+					if !reflect.DeepEqual(req.Options.AvoidFeatures, tt.wantFeatures) {
+						t.Errorf("avoid_features = %v, want %v", req.Options.AvoidFeatures, tt.wantFeatures)
+					}
+					var quiet float64
+					if params := req.Options.ProfileParams; params != nil && params.Weightings != nil {
+						quiet = params.Weightings.Quiet
+					}
+					if quiet != tt.wantQuietness {
+						t.Errorf("quiet weighting = %v, want %v", quiet, tt.wantQuietness)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(respBody)
+			}))
+			defer server.Close()
+
+			client := NewClient(ClientConfig{
+				APIKey:     "mock123",
+				BaseURL:    server.URL,
+				HTTPClient: &mockHTTPClient{client: server.Client()},
+				Logger:     zerolog.Nop(),
+			})
+
+			_, err := client.GetDirections(context.Background(), routing.DirectionsRequest{
+				Origin:      routing.Coordinate{Lat: 52.3676, Lon: 4.9041},
+				Destination: routing.Coordinate{Lat: 52.0907, Lon: 5.1214},
+				Profile:     tt.profile,
+				Constraints: tt.constraints,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_GetDirections_AvoidZones(t *testing.T) {
+	respBody, err := os.ReadFile("testdata/directions_response.json")
+	if err != nil {
+		t.Fatalf("failed to load test fixture: %v", err)
+	}
+
+	covering := routing.Zone{Name: "route", Polygon: []routing.Coordinate{
+		{Lat: 52.0, Lon: 4.8}, {Lat: 52.4, Lon: 4.8}, {Lat: 52.4, Lon: 5.2}, {Lat: 52.0, Lon: 5.2},
+	}}
+	distant := routing.Zone{Name: "Maastricht", Polygon: []routing.Coordinate{
+		{Lat: 50.84, Lon: 5.68}, {Lat: 50.86, Lon: 5.68}, {Lat: 50.86, Lon: 5.71},
+	}}
+
+	var got *orsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = &orsRequest{}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(respBody)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		APIKey:     "mock123",
+		BaseURL:    server.URL,
+		HTTPClient: &mockHTTPClient{client: server.Client()},
+		AvoidZones: []routing.Zone{covering, distant},
+		Logger:     zerolog.Nop(),
+	})
+
+	req := routing.DirectionsRequest{
+		Origin:      routing.Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: routing.Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     routing.ProfileBike,
+		Constraints: routing.RouteConstraints{AvoidCongestionZones: true},
+	}
+	if _, err := client.GetDirections(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Options == nil || got.Options.AvoidPolygons == nil {
+		t.Fatalf("expected avoid_polygons, got options %+v", got.Options)
+	}
+	polygons := got.Options.AvoidPolygons
+	if polygons.Type != "MultiPolygon" {
+		t.Errorf("type = %q, want MultiPolygon", polygons.Type)
+	}
+	want := [][][][]float64{{{{4.8, 52.0}, {4.8, 52.4}, {5.2, 52.4}, {5.2, 52.0}, {4.8, 52.0}}}}
+	if !reflect.DeepEqual(polygons.Coordinates, want) {
+		t.Errorf("coordinates = %v, want only the covering zone %v", polygons.Coordinates, want)
+	}
+
+	// Without the constraint no zones are sent
+	req.Constraints = routing.RouteConstraints{}
+	if _, err := client.GetDirections(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Options != nil {
+		t.Errorf("expected no options, got %+v", got.Options)
+	}
+}
+
 func TestClient_GetDirections_RateLimited(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -439,3 +738,68 @@ func TestError_IsRetryable(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetMatrix_Success(t *testing.T) {
+	respBody, err := os.ReadFile("testdata/matrix_response.json")
+	if err != nil {
+		t.Fatalf("failed to load test fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/v2/matrix/foot-walking"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		var req orsMatrixRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Locations) != 4 {
+			t.Errorf("expected 4 locations, got %d", len(req.Locations))
+		}
+		if len(req.Sources) != 2 || req.Sources[0] != 0 || req.Sources[1] != 1 {
+			t.Errorf("expected sources [0 1], got %v", req.Sources)
+		}
+		if len(req.Destinations) != 2 || req.Destinations[0] != 2 || req.Destinations[1] != 3 {
+			t.Errorf("expected destinations [2 3], got %v", req.Destinations)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(respBody)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		APIKey:     "mock123",
+		BaseURL:    server.URL,
+		HTTPClient: &mockHTTPClient{client: server.Client()},
+		Logger:     zerolog.Nop(),
+	})
+
+	costs, err := client.GetMatrix(context.Background(), routing.MatrixRequest{
+		Origins: []routing.Coordinate{
+			{Lat: 52.3791, Lon: 4.9003},
+			{Lat: 52.3680, Lon: 4.8836},
+		},
+		Destinations: []routing.Coordinate{
+			{Lat: 52.3604, Lon: 4.9180},
+			{Lat: 52.3389, Lon: 4.9400},
+		},
+		Profile: routing.ProfileWalk,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(costs) != 2 || len(costs[0]) != 2 {
+		t.Fatalf("expected 2x2 matrix, got %v", costs)
+	}
+	if costs[0][1].DurationSeconds != 1305 || costs[0][1].DistanceMeters != 5110 || !costs[0][1].Reachable {
+		t.Errorf("unexpected cost for [0][1]: %+v", costs[0][1])
+	}
+	if costs[1][1].Reachable {
+		t.Error("expected null entry to be unreachable")
+	}
+}
@@ -6,8 +6,53 @@ type orsRequest struct {
 	AlternativeRoutes *alternativeRoutesOpts `json:"alternative_routes,omitempty"`
 	Instructions      bool                   `json:"instructions"`
 	Geometry          bool                   `json:"geometry"`
+	Elevation         bool                   `json:"elevation"`
 	Units             string                 `json:"units"`
 	Language          string                 `json:"language"`
+	Options           *orsOptions            `json:"options,omitempty"`
+}
+
+// orsOptions holds the ORS route options: features to avoid and
+// profile-specific weightings.
+type orsOptions struct {
+	AvoidFeatures []string          `json:"avoid_features,omitempty"`
+	AvoidPolygons *geoJSONPolygons  `json:"avoid_polygons,omitempty"`
+	ProfileParams *orsProfileParams `json:"profile_params,omitempty"`
+}
+
+// geoJSONPolygons is a GeoJSON MultiPolygon. Coordinates holds one polygon
+// per zone, each a single closed [lon, lat] ring.
+type geoJSONPolygons struct {
+	Type        string          `json:"type"`
+	Coordinates [][][][]float64 `json:"coordinates"`
+}
+
+// orsProfileParams holds profile-specific routing parameters.
+type orsProfileParams struct {
+	Weightings *orsWeightings `json:"weightings,omitempty"`
+}
+
+// orsWeightings biases route selection; values range from 0 to 1.
+type orsWeightings struct {
+	// Quiet favours quiet ways over busy roads. Foot profiles only.
+	Quiet float64 `json:"quiet,omitempty"`
+}
+
+// orsMatrixRequest represents the ORS matrix API request body.
+// Locations holds origins followed by destinations; Sources and Destinations index into it.
+type orsMatrixRequest struct {
+	Locations    [][]float64 `json:"locations"`
+	Sources      []int       `json:"sources"`
+	Destinations []int       `json:"destinations"`
+	Metrics      []string    `json:"metrics"`
+	Units        string      `json:"units"`
+}
+
+// orsMatrixResponse represents the ORS matrix API response.
+// Unreachable pairs are returned as null.
+type orsMatrixResponse struct {
+	Durations [][]*float64 `json:"durations"`
+	Distances [][]*float64 `json:"distances"`
 }
 
 // alternativeRoutesOpts configures alternative route generation.
@@ -42,8 +87,10 @@ type orsRoute struct {
 
 // routeSummary contains summary information for a route.
 type routeSummary struct {
-	Distance float64 `json:"distance"` // Distance in meters
-	Duration float64 `json:"duration"` // Duration in seconds
+	Distance float64 `json:"distance"`          // Distance in meters
+	Duration float64 `json:"duration"`          // Duration in seconds
+	Ascent   float64 `json:"ascent,omitempty"`  // Total ascent in meters (requires elevation)
+	Descent  float64 `json:"descent,omitempty"` // Total descent in meters (requires elevation)
 }
 
 // routeSegment represents a segment of the route.
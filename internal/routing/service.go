@@ -5,9 +5,32 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
+)
+
+// DefaultSimplifyToleranceMeters is the default Douglas-Peucker tolerance applied to route geometry.
+const DefaultSimplifyToleranceMeters = 5.0
+
+// DefaultDepartureTimeBucket is the default granularity of departure times
+// in cache keys for time-dependent profiles.
+const DefaultDepartureTimeBucket = 15 * time.Minute
+
+// Safe ranges for feature flag cache overrides. Grid sizes below 0.01° would
+// collide in cache keys, which are formatted to two decimals.
+const (
+	minCacheTTLSeconds = 30
+	maxCacheTTLSeconds = 3600
+	minCacheGridSize   = 0.01
+	maxCacheGridSize   = 0.1
 )
 
 // ServiceConfig holds configuration for the routing service.
@@ -28,8 +51,44 @@ type ServiceConfig struct {
 	// StaleIfErrorTTL allows serving stale data on provider errors (default: 15 minutes).
 	StaleIfErrorTTL time.Duration
 
+	// StaleWhileRevalidate serves expired directions still within
+	// StaleIfErrorTTL at once and refreshes them in the background, instead
+	// of making the caller wait for the provider.
+	StaleWhileRevalidate bool
+
 	// CleanupInterval is how often to clean up expired entries (default: 5 minutes).
 	CleanupInterval time.Duration
+
+	// SimplifyToleranceMeters is the Douglas-Peucker tolerance used to simplify
+	// route geometry before caching (default: 5 meters). Set negative to disable.
+	SimplifyToleranceMeters float64
+
+	// DepartureTimeBucket is the departure-time granularity used in cache keys
+	// for time-dependent profiles such as transit (default: 15 minutes).
+	// Requests departing within the same bucket share cached routes.
+	DepartureTimeBucket time.Duration
+
+	// MaxCacheEntries bounds the directions and matrix cost caches each
+	// (default: 10000). Expired entries are evicted first, then the least
+	// recently used.
+	MaxCacheEntries int
+
+	// MaxDistanceMeters overrides DefaultMaxDistanceMeters per profile.
+	// Requests whose straight-line distance exceeds the limit fail with
+	// ErrRouteTooLong before reaching the provider. A negative value removes
+	// the limit for that profile.
+	MaxDistanceMeters map[RouteProfile]float64
+
+	// MinSeparationMeters is how far apart in a straight line the origin and
+	// destination must be; closer requests fail with ErrSameOriginDestination
+	// before reaching the provider (default: DefaultMinSeparationMeters). A
+	// negative value disables the check.
+	MinSeparationMeters float64
+
+	// FeatureFlags is the feature flag service (optional).
+	// If provided, FlagRoutingCacheTTLSeconds and FlagRoutingCacheGridSize
+	// override CacheTTL and CacheGridSize without a redeploy.
+	FeatureFlags *featureflags.Service
 }
 
 // Service provides routing data with caching.
@@ -39,10 +98,29 @@ type Service struct {
 	cacheTTL        time.Duration
 	cacheGridSize   float64
 	staleIfErrorTTL time.Duration
+	revalidate      bool
 	cleanupInterval time.Duration
+	simplifyTol     float64
+	departureBucket time.Duration
+	maxDistance     map[RouteProfile]float64
+	minSeparation   float64
+
+	// Live cache tuning via feature flags; zero values mean no override
+	ttlOverride  *featureflags.NumberOverride
+	gridOverride *featureflags.NumberOverride
+	ttlInEffect  atomic.Int64  // nanoseconds
+	gridInEffect atomic.Uint64 // math.Float64bits
+
+	// Cache lookup counters, updated atomically outside mu
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+
+	// fetches coalesces concurrent provider fetches of the same cache key
+	fetches cache.Coalescer[*DirectionsResponse]
 
 	mu          sync.RWMutex
-	cache       map[string]*cachedDirections
+	cache       *cache.LRU[string, *cachedDirections]
+	costs       *cache.LRU[string, *cachedCost]
 	lastCleanup time.Time
 }
 
@@ -74,14 +152,52 @@ func NewService(cfg ServiceConfig) *Service {
 		cleanupInterval = 5 * time.Minute
 	}
 
+	simplifyTol := cfg.SimplifyToleranceMeters
+	if simplifyTol == 0 {
+		simplifyTol = DefaultSimplifyToleranceMeters
+	}
+
+	departureBucket := cfg.DepartureTimeBucket
+	if departureBucket == 0 {
+		departureBucket = DefaultDepartureTimeBucket
+	}
+
+	maxCacheEntries := cfg.MaxCacheEntries
+	if maxCacheEntries == 0 {
+		maxCacheEntries = 10000
+	}
+
+	maxDistance := make(map[RouteProfile]float64, len(DefaultMaxDistanceMeters))
+	for profile, limit := range DefaultMaxDistanceMeters {
+		maxDistance[profile] = limit
+	}
+	for profile, limit := range cfg.MaxDistanceMeters {
+		maxDistance[profile] = limit
+	}
+
+	minSeparation := cfg.MinSeparationMeters
+	if minSeparation == 0 {
+		minSeparation = DefaultMinSeparationMeters
+	}
+
 	return &Service{
 		provider:        cfg.Provider,
 		logger:          cfg.Logger,
 		cacheTTL:        cacheTTL,
 		cacheGridSize:   cacheGridSize,
 		staleIfErrorTTL: staleIfErrorTTL,
+		revalidate:      cfg.StaleWhileRevalidate,
 		cleanupInterval: cleanupInterval,
-		cache:           make(map[string]*cachedDirections),
+		simplifyTol:     simplifyTol,
+		departureBucket: departureBucket,
+		maxDistance:     maxDistance,
+		minSeparation:   minSeparation,
+		ttlOverride: cfg.FeatureFlags.NumberOverride(
+			featureflags.FlagRoutingCacheTTLSeconds, minCacheTTLSeconds, maxCacheTTLSeconds),
+		gridOverride: cfg.FeatureFlags.NumberOverride(
+			featureflags.FlagRoutingCacheGridSize, minCacheGridSize, maxCacheGridSize),
+		cache: cache.NewLRU[string, *cachedDirections](maxCacheEntries),
+		costs: cache.NewLRU[string, *cachedCost](maxCacheEntries),
 	}
 }
 
@@ -105,38 +221,85 @@ func (s *Service) GetDirections(ctx context.Context, req DirectionsRequest) (*Di
 			Err:      ErrInvalidCoordinates,
 		}
 	}
+	if err := s.CheckSeparation(req.Origin, req.Destination); err != nil {
+		return nil, err
+	}
+	if err := s.CheckDistance(req.Profile, req.Origin, req.Destination); err != nil {
+		return nil, err
+	}
 
+	s.applyOverrides(ctx)
 	cacheKey := s.cacheKey(req)
 
 	// Check cache (read lock)
 	s.mu.RLock()
-	if cached, ok := s.cache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
-		s.mu.RUnlock()
-		s.logger.Debug().
+	cached, ok := s.cache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
+		telemetry.Logger(ctx, s.logger).Debug().
 			Str("cache_key", cacheKey).
 			Msg("cache hit for directions")
+		resilience.RecordCacheResult(ctx, s.provider.Name(), true)
+		return cached.response, nil
+	}
+	if ok && s.revalidate && time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
+		s.cacheHits.Add(1)
+		telemetry.Logger(ctx, s.logger).Debug().
+			Str("cache_key", cacheKey).
+			Msg("serving stale directions while revalidating")
+		resilience.RecordCacheResult(ctx, s.provider.Name(), true)
+		s.revalidateDirections(ctx, req, cacheKey)
 		return cached.response, nil
 	}
-	s.mu.RUnlock()
 
 	// Fetch from provider
 	return s.fetchDirections(ctx, req, cacheKey)
 }
 
 // fetchDirections fetches directions from provider and updates cache.
+// Concurrent misses on the same key share a single provider call; a caller
+// whose context ends stops waiting without cancelling it for the others.
 func (s *Service) fetchDirections(ctx context.Context, req DirectionsRequest, cacheKey string) (*DirectionsResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	resp, shared, err := s.fetches.Do(ctx, cacheKey, func(ctx context.Context) (*DirectionsResponse, error) {
+		return s.fetchAndCache(ctx, req, cacheKey)
+	})
+	if shared {
+		telemetry.Logger(ctx, s.logger).Debug().
+			Str("cache_key", cacheKey).
+			Msg("shared in-flight directions fetch")
+	}
+	return resp, err
+}
 
-	// Double-check cache (prevents thundering herd)
-	if cached, ok := s.cache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
-		s.logger.Debug().
+// revalidateDirections refreshes cached directions in the background. It
+// shares the in-flight fetch for the key, if any, so at most one refresh runs
+// per key; a failed refresh leaves the stale entry in place.
+func (s *Service) revalidateDirections(ctx context.Context, req DirectionsRequest, cacheKey string) {
+	s.fetches.Go(ctx, cacheKey, func(ctx context.Context) (*DirectionsResponse, error) {
+		return s.fetchAndCache(ctx, req, cacheKey)
+	})
+}
+
+// fetchAndCache fetches directions from the provider, unless a fetch that
+// just finished has cached them, and stores the response in the cache.
+func (s *Service) fetchAndCache(ctx context.Context, req DirectionsRequest, cacheKey string) (*DirectionsResponse, error) {
+	// Double-check cache: a previous fetch may have completed meanwhile
+	s.mu.RLock()
+	cached, ok := s.cache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
+		telemetry.Logger(ctx, s.logger).Debug().
 			Str("cache_key", cacheKey).
 			Msg("cache hit after double-check")
+		resilience.RecordCacheResult(ctx, s.provider.Name(), true)
 		return cached.response, nil
 	}
+	resilience.RecordCacheResult(ctx, s.provider.Name(), false)
+	s.cacheMisses.Add(1)
 
-	s.logger.Debug().
+	telemetry.Logger(ctx, s.logger).Debug().
 		Float64("origin_lat", req.Origin.Lat).
 		Float64("origin_lon", req.Origin.Lon).
 		Float64("dest_lat", req.Destination.Lat).
@@ -147,7 +310,7 @@ func (s *Service) fetchDirections(ctx context.Context, req DirectionsRequest, ca
 
 	resp, err := s.provider.GetDirections(ctx, req)
 	if err != nil {
-		s.logger.Error().Err(err).
+		telemetry.Logger(ctx, s.logger).Error().Err(err).
 			Float64("origin_lat", req.Origin.Lat).
 			Float64("origin_lon", req.Origin.Lon).
 			Float64("dest_lat", req.Destination.Lat).
@@ -156,9 +319,12 @@ func (s *Service) fetchDirections(ctx context.Context, req DirectionsRequest, ca
 			Msg("failed to fetch directions")
 
 		// Check for stale data (stale-if-error pattern)
-		if cached, ok := s.cache[cacheKey]; ok {
+		s.mu.RLock()
+		cached, ok := s.cache.Get(cacheKey)
+		s.mu.RUnlock()
+		if ok {
 			if time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-				s.logger.Warn().
+				telemetry.Logger(ctx, s.logger).Warn().
 					Time("fetched_at", cached.fetchedAt).
 					Str("cache_key", cacheKey).
 					Msg("serving stale directions data due to provider error")
@@ -169,39 +335,97 @@ func (s *Service) fetchDirections(ctx context.Context, req DirectionsRequest, ca
 		return nil, err
 	}
 
+	if !req.FullGeometry {
+		resp = s.simplify(resp)
+	}
+
 	// Update cache
 	now := time.Now()
-	s.cache[cacheKey] = &cachedDirections{
+	entry := &cachedDirections{
 		response:  resp,
 		fetchedAt: now,
-		expiresAt: now.Add(s.cacheTTL),
+		expiresAt: now.Add(s.currentCacheTTL()),
 	}
+	s.mu.Lock()
+	s.cache.Set(cacheKey, entry, entry.expiresAt)
+	s.cleanupIfNeeded()
+	s.mu.Unlock()
 
-	s.logger.Debug().
+	telemetry.Logger(ctx, s.logger).Debug().
 		Str("cache_key", cacheKey).
 		Int("route_count", len(resp.Routes)).
 		Msg("cached directions response")
 
-	// Periodic cleanup
-	s.cleanupIfNeeded()
-
 	return resp, nil
 }
 
 // cacheKey generates a cache key for a routing request.
 // Uses grid-based quantization for both origin and destination.
 // Format: {profile}:{gridOriginLat},{gridOriginLon}:{gridDestLat},{gridDestLon}.
+// Time-dependent profiles append ":t{bucketStartUnix}" so routes for different
+// departure times do not collide; walk and bike keys ignore departure time.
 func (s *Service) cacheKey(req DirectionsRequest) string {
-	gridOriginLat := math.Floor(req.Origin.Lat/s.cacheGridSize) * s.cacheGridSize
-	gridOriginLon := math.Floor(req.Origin.Lon/s.cacheGridSize) * s.cacheGridSize
-	gridDestLat := math.Floor(req.Destination.Lat/s.cacheGridSize) * s.cacheGridSize
-	gridDestLon := math.Floor(req.Destination.Lon/s.cacheGridSize) * s.cacheGridSize
+	gridSize := s.currentGridSize()
+	gridOriginLat := math.Floor(req.Origin.Lat/gridSize) * gridSize
+	gridOriginLon := math.Floor(req.Origin.Lon/gridSize) * gridSize
+	gridDestLat := math.Floor(req.Destination.Lat/gridSize) * gridSize
+	gridDestLon := math.Floor(req.Destination.Lon/gridSize) * gridSize
 
-	return fmt.Sprintf("%s:%.2f,%.2f:%.2f,%.2f",
+	key := fmt.Sprintf("%s:%.2f,%.2f:%.2f,%.2f",
 		req.Profile,
 		gridOriginLat, gridOriginLon,
 		gridDestLat, gridDestLon,
 	)
+	if req.Profile.TimeDependent() {
+		departure := req.DepartureTime
+		if departure.IsZero() {
+			departure = time.Now()
+		}
+		key += fmt.Sprintf(":t%d", departure.Truncate(s.departureBucket).Unix())
+	}
+	if req.FullGeometry {
+		key += ":full"
+	}
+	if c := req.Constraints; !c.IsZero() {
+		key += ":avoid"
+		if c.AvoidMajorRoads {
+			key += "-major"
+		}
+		if c.AvoidFerries {
+			key += "-ferries"
+		}
+		if c.AvoidUnpaved {
+			key += "-unpaved"
+		}
+		if c.AvoidCongestionZones {
+			key += "-zones"
+		}
+	}
+	if !req.IncludeInstructions {
+		key += ":noinstr"
+	} else if lang := LanguageCode(req.Language); lang != "" && lang != "en" {
+		key += ":" + lang
+	}
+	return key
+}
+
+// simplify returns a copy of the response with each route's geometry reduced
+// by Douglas-Peucker simplification. The provider's response is not modified.
+func (s *Service) simplify(resp *DirectionsResponse) *DirectionsResponse {
+	if s.simplifyTol < 0 {
+		return resp
+	}
+
+	simplified := *resp
+	simplified.Routes = make([]Route, len(resp.Routes))
+	for i, route := range resp.Routes {
+		if route.GeometryPolyline != "" {
+			coords := polyline.Decode(route.GeometryPolyline)
+			route.GeometryPolyline = polyline.Encode(polyline.Simplify(coords, s.simplifyTol))
+		}
+		simplified.Routes[i] = route
+	}
+	return &simplified
 }
 
 // cleanupIfNeeded removes expired entries if cleanup interval has passed.
@@ -214,13 +438,13 @@ func (s *Service) cleanupIfNeeded() {
 	s.lastCleanup = now
 	expired := 0
 
-	for key, cached := range s.cache {
-		// Remove entries that are past the stale-if-error window
-		if now.After(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-			delete(s.cache, key)
-			expired++
-		}
-	}
+	// Remove entries that are past the stale-if-error window
+	expired += s.cache.DeleteFunc(func(_ string, cached *cachedDirections) bool {
+		return now.After(cached.fetchedAt.Add(s.staleIfErrorTTL))
+	})
+	expired += s.costs.DeleteFunc(func(_ string, cached *cachedCost) bool {
+		return now.After(cached.fetchedAt.Add(s.staleIfErrorTTL))
+	})
 
 	if expired > 0 {
 		s.logger.Debug().
@@ -233,7 +457,8 @@ func (s *Service) cleanupIfNeeded() {
 func (s *Service) InvalidateCache() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.cache = make(map[string]*cachedDirections)
+	s.cache.Clear()
+	s.costs.Clear()
 }
 
 // CacheStats returns cache statistics.
@@ -242,23 +467,30 @@ func (s *Service) CacheStats() CacheStats {
 	defer s.mu.RUnlock()
 
 	now := time.Now()
-	fresh := 0
-	stale := 0
+	stats := CacheStats{
+		TotalEntries: s.cache.Len(),
+		Evictions:    s.cache.Evictions() + s.costs.Evictions(),
+		Provider:     s.provider.Name(),
+		Hits:         s.cacheHits.Load(),
+		Misses:       s.cacheMisses.Load(),
+	}
 
-	for _, c := range s.cache {
+	s.cache.Range(func(_ string, c *cachedDirections) bool {
 		if now.Before(c.expiresAt) {
-			fresh++
+			stats.FreshEntries++
+			if stats.OldestFreshAt.IsZero() || c.fetchedAt.Before(stats.OldestFreshAt) {
+				stats.OldestFreshAt = c.fetchedAt
+			}
 		} else if now.Before(c.fetchedAt.Add(s.staleIfErrorTTL)) {
-			stale++
+			stats.StaleEntries++
 		}
-	}
+		if c.fetchedAt.After(stats.NewestFetchedAt) {
+			stats.NewestFetchedAt = c.fetchedAt
+		}
+		return true
+	})
 
-	return CacheStats{
-		TotalEntries: len(s.cache),
-		FreshEntries: fresh,
-		StaleEntries: stale,
-		Provider:     s.provider.Name(),
-	}
+	return stats
 }
 
 // CacheStats contains cache statistics.
@@ -267,6 +499,61 @@ type CacheStats struct {
 	FreshEntries int
 	StaleEntries int
 	Provider     string
+
+	// OldestFreshAt is when the oldest unexpired entry was fetched, and
+	// NewestFetchedAt when the newest entry was. Both are zero if there is
+	// no such entry.
+	OldestFreshAt   time.Time
+	NewestFetchedAt time.Time
+
+	// Hits and Misses count cache lookups since the service started.
+	Hits   uint64
+	Misses uint64
+
+	// Evictions counts entries dropped to stay within MaxCacheEntries.
+	Evictions uint64
+}
+
+// HitRatio returns the fraction of cache lookups served from the cache,
+// or 0 if there have been none.
+func (c CacheStats) HitRatio() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
+}
+
+// applyOverrides refreshes the cache TTL and grid size in effect from
+// feature flags. Call it before taking s.mu: it may query the flag store.
+func (s *Service) applyOverrides(ctx context.Context) {
+	if seconds, ok := s.ttlOverride.Value(ctx); ok {
+		s.ttlInEffect.Store(int64(seconds * float64(time.Second)))
+	} else {
+		s.ttlInEffect.Store(0)
+	}
+
+	if gridSize, ok := s.gridOverride.Value(ctx); ok {
+		s.gridInEffect.Store(math.Float64bits(gridSize))
+	} else {
+		s.gridInEffect.Store(0)
+	}
+}
+
+// currentCacheTTL returns the overridden cache TTL, or the constructed one.
+func (s *Service) currentCacheTTL() time.Duration {
+	if ttl := s.ttlInEffect.Load(); ttl > 0 {
+		return time.Duration(ttl)
+	}
+	return s.cacheTTL
+}
+
+// currentGridSize returns the overridden cache grid size, or the constructed one.
+func (s *Service) currentGridSize() float64 {
+	if bits := s.gridInEffect.Load(); bits != 0 {
+		return math.Float64frombits(bits)
+	}
+	return s.cacheGridSize
 }
 
 // ProviderName returns the name of the underlying provider.
@@ -7,6 +7,9 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/pkg/polyline"
 )
 
 // mockProvider is a mock routing provider for testing.
@@ -128,6 +131,57 @@ func TestService_GetDirections_CacheHit(t *testing.T) {
 	}
 }
 
+func TestService_GetDirections_CacheOverrides(t *testing.T) {
+	ffRepo := featureflags.NewInMemoryRepositoryWithFlags(map[string]*featureflags.Flag{
+		featureflags.FlagRoutingCacheGridSize: {
+			Key:   featureflags.FlagRoutingCacheGridSize,
+			Value: 0.1,
+		},
+		featureflags.FlagRoutingCacheTTLSeconds: {
+			Key:   featureflags.FlagRoutingCacheTTLSeconds,
+			Value: 1e6, // Clamped to an hour
+		},
+	})
+	ffService := featureflags.NewService(featureflags.ServiceConfig{
+		Repository: ffRepo,
+	})
+
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileBike},
+		response: &DirectionsResponse{
+			Routes:    []Route{{DistanceMeters: 12345}},
+			Provider:  "test-provider",
+			FetchedAt: time.Now(),
+		},
+	}
+
+	service := NewService(ServiceConfig{
+		Provider:     provider,
+		CacheTTL:     5 * time.Minute,
+		FeatureFlags: ffService,
+	})
+
+	// Different 0.01 cells, same 0.1 cell
+	for _, lat := range []float64{52.31, 52.35, 52.39} {
+		_, err := service.GetDirections(context.Background(), DirectionsRequest{
+			Origin:      Coordinate{Lat: lat, Lon: 4.91},
+			Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+			Profile:     ProfileBike,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if calls := provider.callCount.Load(); calls != 1 {
+		t.Errorf("expected 1 provider call with overridden grid size, got %d", calls)
+	}
+	if ttl := service.currentCacheTTL(); ttl != time.Hour {
+		t.Errorf("expected clamped TTL of 1h, got %s", ttl)
+	}
+}
+
 func TestService_GetDirections_GridCaching(t *testing.T) {
 	provider := &mockProvider{
 		name:     "test-provider",
@@ -205,6 +259,132 @@ func TestService_GetDirections_DifferentProfilesNotCached(t *testing.T) {
 	}
 }
 
+func TestService_GetDirections_DepartureTimeBuckets(t *testing.T) {
+	amsterdam := time.FixedZone("CET", 3600)
+	morning := time.Date(2026, 3, 10, 8, 0, 0, 0, amsterdam)
+	evening := time.Date(2026, 3, 10, 18, 0, 0, 0, amsterdam)
+
+	tests := []struct {
+		name          string
+		profile       RouteProfile
+		expectedCalls int32
+	}{
+		{"transit routes are cached per departure time", ProfileTransit, 2},
+		{"bike routes ignore departure time", ProfileBike, 1},
+		{"walk routes ignore departure time", ProfileWalk, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mockProvider{
+				name:     "test-provider",
+				profiles: []RouteProfile{ProfileBike, ProfileWalk, ProfileTransit},
+				response: &DirectionsResponse{
+					Routes:    []Route{{DistanceMeters: 1000, DurationSeconds: 200}},
+					Provider:  "test-provider",
+					FetchedAt: time.Now(),
+				},
+			}
+			service := NewService(ServiceConfig{Provider: provider})
+
+			for _, departure := range []time.Time{morning, evening} {
+				_, err := service.GetDirections(context.Background(), DirectionsRequest{
+					Origin:        Coordinate{Lat: 52.3676, Lon: 4.9041},
+					Destination:   Coordinate{Lat: 52.0907, Lon: 5.1214},
+					Profile:       tt.profile,
+					DepartureTime: departure,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if got := provider.callCount.Load(); got != tt.expectedCalls {
+				t.Errorf("expected %d provider calls, got %d", tt.expectedCalls, got)
+			}
+		})
+	}
+}
+
+func TestService_CacheKey_DepartureTimeBucket(t *testing.T) {
+	service := &Service{
+		cacheGridSize:   0.01,
+		departureBucket: 15 * time.Minute,
+	}
+
+	base := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	req := DirectionsRequest{
+		Origin:        Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination:   Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:       ProfileTransit,
+		DepartureTime: base.Add(3 * time.Minute),
+	}
+	key := service.cacheKey(req)
+
+	req.DepartureTime = base.Add(14 * time.Minute)
+	if got := service.cacheKey(req); got != key {
+		t.Errorf("departures in the same bucket should share a key: %q != %q", got, key)
+	}
+
+	req.DepartureTime = base.Add(15 * time.Minute)
+	if got := service.cacheKey(req); got == key {
+		t.Errorf("departures in different buckets should not share a key: %q", got)
+	}
+}
+
+func TestService_CacheKey_Instructions(t *testing.T) {
+	service := &Service{cacheGridSize: 0.01}
+	req := DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     ProfileBike,
+	}
+	withoutInstructions := service.cacheKey(req)
+
+	req.IncludeInstructions = true
+	english := service.cacheKey(req)
+	if english == withoutInstructions {
+		t.Errorf("requests with and without instructions should not share a key: %q", english)
+	}
+
+	req.Language = "en-GB"
+	if got := service.cacheKey(req); got != english {
+		t.Errorf("English variants should share a key: %q != %q", got, english)
+	}
+
+	req.Language = "nl-NL"
+	dutch := service.cacheKey(req)
+	if dutch == english {
+		t.Errorf("Dutch and English instructions should not share a key: %q", dutch)
+	}
+
+	req.IncludeInstructions = false
+	if got := service.cacheKey(req); got != withoutInstructions {
+		t.Errorf("language should not matter without instructions: %q != %q", got, withoutInstructions)
+	}
+}
+
+func TestService_CacheKey_Constraints(t *testing.T) {
+	service := &Service{cacheGridSize: 0.01}
+	req := DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     ProfileWalk,
+	}
+	unconstrained := service.cacheKey(req)
+
+	req.Constraints = RouteConstraints{AvoidFerries: true}
+	ferries := service.cacheKey(req)
+	if ferries == unconstrained {
+		t.Errorf("constrained and unconstrained requests should not share a key: %q", ferries)
+	}
+
+	req.Constraints = RouteConstraints{AvoidMajorRoads: true}
+	if got := service.cacheKey(req); got == ferries {
+		t.Errorf("different constraints should not share a key: %q", got)
+	}
+}
+
 func TestService_GetDirections_StaleIfError(t *testing.T) {
 	callCount := atomic.Int32{}
 	provider := &mockProvider{
@@ -255,6 +435,124 @@ func TestService_GetDirections_StaleIfError(t *testing.T) {
 	}
 }
 
+func TestService_GetDirections_StaleWhileRevalidate(t *testing.T) {
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileBike},
+		response: &DirectionsResponse{
+			Routes:   []Route{{DistanceMeters: 12345}},
+			Provider: "test-provider",
+		},
+	}
+
+	service := NewService(ServiceConfig{
+		Provider:             provider,
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Minute,
+		StaleWhileRevalidate: true,
+	})
+
+	req := DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     ProfileBike,
+	}
+
+	if _, err := service.GetDirections(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	provider.delay = 100 * time.Millisecond
+	provider.response = &DirectionsResponse{
+		Routes:   []Route{{DistanceMeters: 999}},
+		Provider: "test-provider",
+	}
+
+	// Expired directions are served at once while a single refresh runs
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := service.GetDirections(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Routes[0].DistanceMeters != 12345 {
+			t.Errorf("expected stale distance 12345, got %d", resp.Routes[0].DistanceMeters)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= provider.delay {
+		t.Errorf("expected stale directions without waiting for the provider, took %v", elapsed)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := service.GetDirections(context.Background(), req)
+		if err == nil && resp.Routes[0].DistanceMeters == 999 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected background refresh to replace the stale directions")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if provider.callCount.Load() != 2 {
+		t.Errorf("expected 2 provider calls, got %d", provider.callCount.Load())
+	}
+}
+
+func TestService_GetDirections_FailedRevalidationKeepsStale(t *testing.T) {
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileBike},
+		response: &DirectionsResponse{
+			Routes:   []Route{{DistanceMeters: 12345}},
+			Provider: "test-provider",
+		},
+	}
+
+	service := NewService(ServiceConfig{
+		Provider:             provider,
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Minute,
+		StaleWhileRevalidate: true,
+	})
+
+	req := DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     ProfileBike,
+	}
+
+	if _, err := service.GetDirections(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	provider.err = errors.New("provider error")
+
+	if _, err := service.GetDirections(context.Background(), req); err != nil {
+		t.Fatalf("expected stale data to be served, got error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for provider.callCount.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a background refresh")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The failed refresh left the stale entry in place
+	resp, err := service.GetDirections(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected stale data to be served, got error: %v", err)
+	}
+	if resp.Routes[0].DistanceMeters != 12345 {
+		t.Errorf("expected stale distance 12345, got %d", resp.Routes[0].DistanceMeters)
+	}
+}
+
 func TestService_GetDirections_InvalidCoordinates(t *testing.T) {
 	provider := &mockProvider{
 		name: "test-provider",
@@ -343,11 +641,54 @@ func TestService_GetDirections_ConcurrentRequests(t *testing.T) {
 
 	wg.Wait()
 
-	// With double-check locking, only a few calls should reach the provider
-	// (not all 10)
-	calls := provider.callCount.Load()
-	if calls > 3 {
-		t.Errorf("expected <= 3 provider calls with double-check locking, got %d", calls)
+	// Concurrent misses on the same key share a single provider call
+	if calls := provider.callCount.Load(); calls != 1 {
+		t.Errorf("expected exactly 1 provider call, got %d", calls)
+	}
+}
+
+func TestService_GetDirections_WaitingRequestHonorsCancellation(t *testing.T) {
+	provider := &mockProvider{
+		name:     "test-provider",
+		delay:    500 * time.Millisecond, // Slow provider keeps the fetch in flight
+		response: &DirectionsResponse{Routes: []Route{{DistanceMeters: 12345}}},
+	}
+	service := NewService(ServiceConfig{Provider: provider})
+
+	go func() {
+		_, _ = service.GetDirections(context.Background(), DirectionsRequest{
+			Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+			Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+			Profile:     ProfileBike,
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// The same route waits for the shared fetch, but only until its deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := service.GetDirections(ctx, DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     ProfileBike,
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("expected waiting request to give up at its deadline, waited %v", elapsed)
+	}
+
+	// The shared fetch is not cancelled by the waiter giving up
+	time.Sleep(600 * time.Millisecond)
+	if calls := provider.callCount.Load(); calls != 1 {
+		t.Errorf("expected 1 provider call, got %d", calls)
+	}
+	if stats := service.CacheStats(); stats.TotalEntries != 1 {
+		t.Errorf("expected the shared fetch to be cached, got %d entries", stats.TotalEntries)
 	}
 }
 
@@ -394,6 +735,46 @@ func TestService_CacheStats(t *testing.T) {
 	}
 }
 
+func TestService_CacheStats_HitsAndMisses(t *testing.T) {
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileBike},
+		response: &DirectionsResponse{
+			Routes:    []Route{{DistanceMeters: 12345}},
+			Provider:  "test-provider",
+			FetchedAt: time.Now(),
+		},
+	}
+
+	service := NewService(ServiceConfig{
+		Provider: provider,
+		CacheTTL: 5 * time.Minute,
+	})
+
+	if ratio := service.CacheStats().HitRatio(); ratio != 0 {
+		t.Errorf("expected hit ratio 0 without lookups, got %f", ratio)
+	}
+
+	req := DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.0907, Lon: 5.1214},
+		Profile:     ProfileBike,
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := service.GetDirections(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats := service.CacheStats()
+	if stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("expected 3 hits and 1 miss, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+	if ratio := stats.HitRatio(); ratio != 0.75 {
+		t.Errorf("expected hit ratio 0.75, got %f", ratio)
+	}
+}
+
 func TestService_InvalidateCache(t *testing.T) {
 	provider := &mockProvider{
 		name:     "test-provider",
@@ -471,3 +852,57 @@ func TestService_ProviderName(t *testing.T) {
 		t.Errorf("expected 'my-routing-provider', got '%s'", service.ProviderName())
 	}
 }
+
+func TestService_GetDirections_SimplifiesGeometry(t *testing.T) {
+	// Straight line with a point every ~11m
+	coords := make([]polyline.Coordinate, 0, 101)
+	for i := 0; i <= 100; i++ {
+		coords = append(coords, polyline.Coordinate{Lat: 52.3676 + float64(i)*0.0001, Lon: 4.9041})
+	}
+	full := polyline.Encode(coords)
+
+	provider := &mockProvider{
+		name:     "test-provider",
+		profiles: []RouteProfile{ProfileBike},
+		response: &DirectionsResponse{
+			Routes:    []Route{{GeometryPolyline: full, DistanceMeters: 1113}},
+			Provider:  "test-provider",
+			FetchedAt: time.Now(),
+		},
+	}
+
+	service := NewService(ServiceConfig{Provider: provider})
+
+	req := DirectionsRequest{
+		Origin:      Coordinate{Lat: 52.3676, Lon: 4.9041},
+		Destination: Coordinate{Lat: 52.3776, Lon: 4.9041},
+		Profile:     ProfileBike,
+	}
+
+	resp, err := service.GetDirections(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	simplified := polyline.Decode(resp.Routes[0].GeometryPolyline)
+	if len(simplified) != 2 {
+		t.Errorf("expected simplified geometry with 2 points, got %d", len(simplified))
+	}
+	if provider.response.Routes[0].GeometryPolyline != full {
+		t.Error("provider response should not be modified")
+	}
+
+	// Full geometry is fetched and cached separately
+	req.FullGeometry = true
+	resp, err = service.GetDirections(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Routes[0].GeometryPolyline != full {
+		t.Error("expected full-resolution geometry")
+	}
+	if provider.callCount.Load() != 2 {
+		t.Errorf("expected 2 provider calls, got %d", provider.callCount.Load())
+	}
+}
@@ -0,0 +1,91 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxWaypoints is the maximum number of intermediate stops between the
+// origin and destination of a multi-leg route.
+const MaxWaypoints = 5
+
+// MultiLegRoute is a route through ordered points, with one leg per
+// consecutive pair. Totals are summed across legs.
+type MultiLegRoute struct {
+	Legs            []Route
+	DistanceMeters  int
+	DurationSeconds int
+	AscentMeters    int
+	DescentMeters   int
+}
+
+// GetDirectionsVia returns a route from points[0] through each intermediate
+// point to the last point. Each leg is the provider's best route between its
+// endpoints and is cached like a GetDirections request. Legs are requested
+// with opts' profile, geometry, constraints and instruction options; opts'
+// Origin, Destination and MaxAlternatives are ignored. With a departure time,
+// each leg departs when the previous one arrives.
+// Between 2 and MaxWaypoints+2 points are required.
+func (s *Service) GetDirectionsVia(
+	ctx context.Context,
+	points []Coordinate,
+	opts DirectionsRequest,
+) (*MultiLegRoute, error) {
+	if len(points) < 2 || len(points) > MaxWaypoints+2 {
+		return nil, &Error{
+			Provider: s.provider.Name(),
+			Code:     "INVALID_WAYPOINTS",
+			Message:  fmt.Sprintf("between 2 and %d points are required", MaxWaypoints+2),
+			Err:      ErrInvalidCoordinates,
+		}
+	}
+	for i, c := range points {
+		if err := validateCoordinates(c); err != nil {
+			return nil, &Error{
+				Provider: s.provider.Name(),
+				Code:     "INVALID_WAYPOINTS",
+				Message:  fmt.Sprintf("invalid coordinates for point %d", i),
+				Err:      ErrInvalidCoordinates,
+			}
+		}
+	}
+	if err := s.CheckDistance(opts.Profile, points...); err != nil {
+		return nil, err
+	}
+
+	result := &MultiLegRoute{Legs: make([]Route, 0, len(points)-1)}
+	for i := 1; i < len(points); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		req := opts
+		req.Origin, req.Destination = points[i-1], points[i]
+		req.MaxAlternatives = 1
+		if !opts.DepartureTime.IsZero() {
+			req.DepartureTime = opts.DepartureTime.Add(time.Duration(result.DurationSeconds) * time.Second)
+		}
+		resp, err := s.GetDirections(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i, err)
+		}
+		if len(resp.Routes) == 0 {
+			return nil, &Error{
+				Provider: s.provider.Name(),
+				Code:     "NO_ROUTE",
+				Message:  fmt.Sprintf("no route found for leg %d", i),
+				Err:      ErrNoRouteFound,
+			}
+		}
+
+		leg := resp.Routes[0]
+		result.Legs = append(result.Legs, leg)
+		result.DistanceMeters += leg.DistanceMeters
+		result.DurationSeconds += leg.DurationSeconds
+		result.AscentMeters += leg.AscentMeters
+		result.DescentMeters += leg.DescentMeters
+	}
+
+	return result, nil
+}
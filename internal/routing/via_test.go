@@ -0,0 +1,142 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// legProvider returns a single route per request whose distance and
+// duration are derived from the destination, so legs can be told apart.
+type legProvider struct {
+	callCount atomic.Int32
+	noRouteTo *Coordinate
+	requests  []DirectionsRequest
+}
+
+func (p *legProvider) GetDirections(ctx context.Context, req DirectionsRequest) (*DirectionsResponse, error) {
+	p.callCount.Add(1)
+	p.requests = append(p.requests, req)
+	if p.noRouteTo != nil && req.Destination == *p.noRouteTo {
+		return &DirectionsResponse{Provider: p.Name()}, nil
+	}
+	meters := int(req.Destination.Lon * 1000)
+	return &DirectionsResponse{
+		Routes: []Route{{
+			DistanceMeters:  meters,
+			DurationSeconds: meters / 4,
+			AscentMeters:    1,
+		}},
+		Provider: p.Name(),
+	}, nil
+}
+
+func (p *legProvider) Name() string { return "leg-provider" }
+
+func (p *legProvider) SupportedProfiles() []RouteProfile {
+	return []RouteProfile{ProfileBike, ProfileWalk}
+}
+
+func TestService_GetDirectionsVia(t *testing.T) {
+	provider := &legProvider{}
+	service := NewService(ServiceConfig{Provider: provider})
+
+	points := []Coordinate{
+		{Lat: 52.37, Lon: 4.90}, // Origin
+		{Lat: 52.36, Lon: 4.88}, // School
+		{Lat: 52.34, Lon: 4.86}, // Gym
+		{Lat: 52.31, Lon: 4.76}, // Destination
+	}
+
+	route, err := service.GetDirectionsVia(context.Background(), points, DirectionsRequest{Profile: ProfileBike})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(route.Legs) != 3 {
+		t.Fatalf("expected 3 legs, got %d", len(route.Legs))
+	}
+	if provider.callCount.Load() != 3 {
+		t.Errorf("expected 3 provider calls, got %d", provider.callCount.Load())
+	}
+
+	wantDistance := 4880 + 4860 + 4760
+	if route.DistanceMeters != wantDistance {
+		t.Errorf("expected distance %d, got %d", wantDistance, route.DistanceMeters)
+	}
+	wantDuration := 4880/4 + 4860/4 + 4760/4
+	if route.DurationSeconds != wantDuration {
+		t.Errorf("expected duration %d, got %d", wantDuration, route.DurationSeconds)
+	}
+	if route.AscentMeters != 3 {
+		t.Errorf("expected ascent 3, got %d", route.AscentMeters)
+	}
+	if route.Legs[1].DistanceMeters != 4860 {
+		t.Errorf("expected second leg to end at the gym, got distance %d", route.Legs[1].DistanceMeters)
+	}
+}
+
+func TestService_GetDirectionsVia_LegOptions(t *testing.T) {
+	provider := &legProvider{}
+	service := NewService(ServiceConfig{Provider: provider})
+	departure := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	_, err := service.GetDirectionsVia(context.Background(), []Coordinate{
+		{Lat: 52.37, Lon: 4.90}, {Lat: 52.36, Lon: 4.88}, {Lat: 52.31, Lon: 4.76},
+	}, DirectionsRequest{Profile: ProfileBike, FullGeometry: true, DepartureTime: departure, MaxAlternatives: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(provider.requests) != 2 {
+		t.Fatalf("expected 2 leg requests, got %d", len(provider.requests))
+	}
+	for i, req := range provider.requests {
+		if !req.FullGeometry || req.MaxAlternatives != 1 {
+			t.Errorf("leg %d: expected full geometry and one route, got %+v", i, req)
+		}
+	}
+	if !provider.requests[0].DepartureTime.Equal(departure) {
+		t.Errorf("expected first leg to depart at %v, got %v", departure, provider.requests[0].DepartureTime)
+	}
+	// The first leg ends at lon 4.88: 4880 m at 4 m/s
+	if want := departure.Add(1220 * time.Second); !provider.requests[1].DepartureTime.Equal(want) {
+		t.Errorf("expected second leg to depart at %v, got %v", want, provider.requests[1].DepartureTime)
+	}
+}
+
+func TestService_GetDirectionsVia_InvalidPoints(t *testing.T) {
+	service := NewService(ServiceConfig{Provider: &legProvider{}})
+
+	tests := []struct {
+		name   string
+		points []Coordinate
+	}{
+		{"single point", []Coordinate{{Lat: 52.37, Lon: 4.90}}},
+		{"invalid waypoint", []Coordinate{{Lat: 52.37, Lon: 4.90}, {Lat: 95, Lon: 4.88}, {Lat: 52.31, Lon: 4.76}}},
+		{"too many waypoints", make([]Coordinate, MaxWaypoints+3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetDirectionsVia(context.Background(), tt.points, DirectionsRequest{Profile: ProfileBike})
+			if !errors.Is(err, ErrInvalidCoordinates) {
+				t.Errorf("expected ErrInvalidCoordinates, got %v", err)
+			}
+		})
+	}
+}
+
+func TestService_GetDirectionsVia_NoRouteForLeg(t *testing.T) {
+	gym := Coordinate{Lat: 52.34, Lon: 4.86}
+	service := NewService(ServiceConfig{Provider: &legProvider{noRouteTo: &gym}})
+
+	_, err := service.GetDirectionsVia(context.Background(), []Coordinate{
+		{Lat: 52.37, Lon: 4.90}, gym, {Lat: 52.31, Lon: 4.76},
+	}, DirectionsRequest{Profile: ProfileBike})
+	if !errors.Is(err, ErrNoRouteFound) {
+		t.Errorf("expected ErrNoRouteFound, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package routing
+
+// Zone is a named area routes can be asked to stay out of.
+type Zone struct {
+	Name string
+
+	// Polygon is the zone's outer ring. The first point is not repeated at
+	// the end.
+	Polygon []Coordinate
+}
+
+// LowEmissionZones are the bundled environmental zones (milieuzones) where
+// polluting vehicles are banned. Their outlines are simplified and slightly
+// generous; they are meant for steering routes, not for enforcement.
+var LowEmissionZones = []Zone{
+	{
+		// Inside the A10 ring road
+		Name: "Amsterdam",
+		Polygon: []Coordinate{
+			{Lat: 52.3880, Lon: 4.8360},
+			{Lat: 52.4040, Lon: 4.8800},
+			{Lat: 52.4040, Lon: 4.9300},
+			{Lat: 52.3920, Lon: 4.9680},
+			{Lat: 52.3560, Lon: 4.9550},
+			{Lat: 52.3380, Lon: 4.9300},
+			{Lat: 52.3330, Lon: 4.8850},
+			{Lat: 52.3370, Lon: 4.8450},
+			{Lat: 52.3620, Lon: 4.8320},
+		},
+	},
+	{
+		// City centre and the ring around it
+		Name: "Utrecht",
+		Polygon: []Coordinate{
+			{Lat: 52.0980, Lon: 5.0950},
+			{Lat: 52.0990, Lon: 5.1300},
+			{Lat: 52.0830, Lon: 5.1420},
+			{Lat: 52.0780, Lon: 5.1200},
+			{Lat: 52.0840, Lon: 5.0980},
+		},
+	},
+}
+
+// bounds returns the south-west and north-east corners of the zone.
+func (z Zone) bounds() (sw, ne Coordinate) {
+	for i, c := range z.Polygon {
+		if i == 0 {
+			sw, ne = c, c
+			continue
+		}
+		sw.Lat, sw.Lon = min(sw.Lat, c.Lat), min(sw.Lon, c.Lon)
+		ne.Lat, ne.Lon = max(ne.Lat, c.Lat), max(ne.Lon, c.Lon)
+	}
+	return sw, ne
+}
+
+// ZonesNear returns the zones whose bounds come within margin degrees of
+// the bounding box of points. Routes rarely stray far from that box, so
+// zones outside it need not be sent to the provider.
+func ZonesNear(zones []Zone, margin float64, points ...Coordinate) []Zone {
+	if len(points) == 0 {
+		return nil
+	}
+
+	lo, hi := points[0], points[0]
+	for _, p := range points[1:] {
+		lo.Lat, lo.Lon = min(lo.Lat, p.Lat), min(lo.Lon, p.Lon)
+		hi.Lat, hi.Lon = max(hi.Lat, p.Lat), max(hi.Lon, p.Lon)
+	}
+
+	var near []Zone
+	for _, z := range zones {
+		if len(z.Polygon) < 3 {
+			continue
+		}
+		sw, ne := z.bounds()
+		if ne.Lat < lo.Lat-margin || sw.Lat > hi.Lat+margin ||
+			ne.Lon < lo.Lon-margin || sw.Lon > hi.Lon+margin {
+			continue
+		}
+		near = append(near, z)
+	}
+	return near
+}
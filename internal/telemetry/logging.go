@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDKey is the context key for the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID, so services
+// can log it without depending on the HTTP layer.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// Logger returns base with the request ID and the trace and span IDs of
+// the span in ctx added as request_id, trace_id and span_id, so the logs of
+// one request can be followed from the router through services to provider
+// clients. Fields missing from ctx are left out.
+func Logger(ctx context.Context, base zerolog.Logger) *zerolog.Logger {
+	requestID := RequestID(ctx)
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if requestID == "" && !spanCtx.IsValid() {
+		return &base
+	}
+
+	logCtx := base.With()
+	if requestID != "" {
+		logCtx = logCtx.Str("request_id", requestID)
+	}
+	if spanCtx.IsValid() {
+		logCtx = logCtx.
+			Str("trace_id", spanCtx.TraceID().String()).
+			Str("span_id", spanCtx.SpanID().String())
+	}
+	logger := logCtx.Logger()
+	return &logger
+}
@@ -0,0 +1,56 @@
+package telemetry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+)
+
+// logLine logs a message with logger and returns the decoded entry.
+func logLine(t *testing.T, buf *bytes.Buffer, logger *zerolog.Logger) map[string]interface{} {
+	t.Helper()
+	buf.Reset()
+	logger.Info().Msg("test")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := zerolog.New(&buf)
+
+	// Nothing to add
+	entry := logLine(t, &buf, telemetry.Logger(context.Background(), base))
+	assert.NotContains(t, entry, "request_id")
+	assert.NotContains(t, entry, "trace_id")
+
+	ctx := telemetry.WithRequestID(context.Background(), "req_abc123")
+	assert.Equal(t, "req_abc123", telemetry.RequestID(ctx))
+	entry = logLine(t, &buf, telemetry.Logger(ctx, base))
+	assert.Equal(t, "req_abc123", entry["request_id"])
+	assert.NotContains(t, entry, "trace_id")
+
+	tp := sdktrace.NewTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	ctx, span := tp.Tracer("test").Start(ctx, "compute")
+	defer span.End()
+
+	entry = logLine(t, &buf, telemetry.Logger(ctx, base))
+	assert.Equal(t, "req_abc123", entry["request_id"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+
+	// The base logger is unchanged
+	entry = logLine(t, &buf, &base)
+	assert.NotContains(t, entry, "request_id")
+}
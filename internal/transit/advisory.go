@@ -0,0 +1,227 @@
+package transit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxAdvisoryStations is the number of station names listed per disruption
+// before the remainder is summarized as a count.
+const maxAdvisoryStations = 3
+
+// Supported advisory locales (ISO 639-1 language codes).
+const (
+	LocaleEnglish = "en"
+	LocaleDutch   = "nl"
+)
+
+// Advisory is a structured, user-facing summary of disruptions on a route.
+type Advisory struct {
+	// Headline is a one-sentence summary based on the overall impact.
+	Headline string
+
+	// Details has one line per disruption, most severe first, with the
+	// affected stations, expected duration and alternative transport.
+	Details []string
+
+	// Severity is the highest impact among the disruptions.
+	Severity Impact
+
+	// Locale is the language the advisory is written in.
+	Locale string
+}
+
+// Text renders the advisory as a single message.
+func (a Advisory) Text() string {
+	if a.Headline == "" {
+		return ""
+	}
+	return strings.Join(append([]string{a.Headline}, a.Details...), " ")
+}
+
+// advisoryMessages holds the message templates for one locale.
+type advisoryMessages struct {
+	headlines map[Impact]string
+
+	disruption    string // title used when a disruption has none
+	atStations    string // title, stations
+	delayUntil    string // minutes, end time
+	delay         string // minutes
+	until         string // end time
+	alternative   string // alternative transport
+	and           string
+	otherStation  string // listed stations
+	otherStations string // listed stations, remaining count
+}
+
+// messageTables maps supported locales to their advisory messages.
+var messageTables = map[string]advisoryMessages{
+	LocaleEnglish: {
+		headlines: map[Impact]string{
+			ImpactSevere:   "Severe disruptions on your route. No train service available. Please use alternative transport.",
+			ImpactMajor:    "Major disruptions expected. Significant delays or cancellations possible. Plan extra travel time.",
+			ImpactModerate: "Moderate disruptions on your route. Some delays expected. Check departure times before traveling.",
+			ImpactMinor:    "Minor disruptions reported. Slight delays possible.",
+		},
+		disruption:    "Disruption",
+		atStations:    "%s at %s.",
+		delayUntil:    "Expect around %d minutes of delay, until %s.",
+		delay:         "Expect around %d minutes of delay.",
+		until:         "Expected to last until %s.",
+		alternative:   "Alternative transport: %s.",
+		and:           "and",
+		otherStation:  "%s and 1 other station",
+		otherStations: "%s and %d other stations",
+	},
+	LocaleDutch: {
+		headlines: map[Impact]string{
+			ImpactSevere:   "Ernstige verstoringen op je route. Er rijden geen treinen. Gebruik alternatief vervoer.",
+			ImpactMajor:    "Grote verstoringen verwacht. Flinke vertragingen of uitval mogelijk. Houd rekening met extra reistijd.",
+			ImpactModerate: "Verstoringen op je route. Enige vertraging verwacht. Controleer de vertrektijden voordat je reist.",
+			ImpactMinor:    "Kleine verstoringen gemeld. Lichte vertraging mogelijk.",
+		},
+		disruption:    "Verstoring",
+		atStations:    "%s bij %s.",
+		delayUntil:    "Reken op ongeveer %d minuten vertraging, tot %s.",
+		delay:         "Reken op ongeveer %d minuten vertraging.",
+		until:         "Naar verwachting tot %s.",
+		alternative:   "Alternatief vervoer: %s.",
+		and:           "en",
+		otherStation:  "%s en 1 ander station",
+		otherStations: "%s en %d andere stations",
+	},
+}
+
+// ResolveLocale maps a BCP 47 tag (e.g., "nl-NL", "en_GB") to a supported
+// advisory locale, falling back to English for unknown or empty tags.
+func ResolveLocale(tag string) string {
+	if locale, ok := supportedLocale(tag); ok {
+		return locale
+	}
+	return LocaleEnglish
+}
+
+// SupportsLocale reports whether advisories are available in the tag's language.
+func SupportsLocale(tag string) bool {
+	_, ok := supportedLocale(tag)
+	return ok
+}
+
+// supportedLocale returns the primary language subtag of tag if it has a message table.
+func supportedLocale(tag string) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	_, ok := messageTables[lang]
+	return lang, ok
+}
+
+// GenerateAdvisory composes an English advisory from the given disruptions.
+// Returns the zero Advisory when there are no disruptions.
+func GenerateAdvisory(disruptions []*Disruption) Advisory {
+	return GenerateLocalizedAdvisory(disruptions, LocaleEnglish)
+}
+
+// GenerateLocalizedAdvisory composes an advisory from the given disruptions
+// in the requested locale. Unsupported locales fall back to English.
+// Returns the zero Advisory when there are no disruptions.
+func GenerateLocalizedAdvisory(disruptions []*Disruption, locale string) Advisory {
+	if len(disruptions) == 0 {
+		return Advisory{}
+	}
+
+	locale = ResolveLocale(locale)
+	messages := messageTables[locale]
+
+	severity := CalculateOverallImpact(disruptions)
+	advisory := Advisory{
+		Headline: messages.headline(severity),
+		Severity: severity,
+		Locale:   locale,
+	}
+
+	ordered := append([]*Disruption(nil), disruptions...)
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return impactRank(ordered[a].Impact) > impactRank(ordered[b].Impact)
+	})
+	for _, d := range ordered {
+		advisory.Details = append(advisory.Details, messages.detail(d))
+	}
+
+	return advisory
+}
+
+// headline returns the generic summary sentence for an impact level.
+func (m advisoryMessages) headline(impact Impact) string {
+	if headline, ok := m.headlines[impact]; ok {
+		return headline
+	}
+	return m.headlines[ImpactMinor]
+}
+
+// detail composes a detail line for a single disruption.
+func (m advisoryMessages) detail(d *Disruption) string {
+	title := strings.TrimSuffix(strings.TrimSpace(d.Title), ".")
+	if title == "" {
+		title = m.disruption
+	}
+
+	var sentences []string
+	if stations := d.stationNames(); len(stations) > 0 {
+		sentences = append(sentences, fmt.Sprintf(m.atStations, title, m.joinStations(stations)))
+	} else {
+		sentences = append(sentences, title+".")
+	}
+
+	switch {
+	case d.ExpectedDuration > 0 && !d.End.IsZero():
+		sentences = append(sentences, fmt.Sprintf(m.delayUntil, d.ExpectedDuration, d.End.Format("15:04")))
+	case d.ExpectedDuration > 0:
+		sentences = append(sentences, fmt.Sprintf(m.delay, d.ExpectedDuration))
+	case !d.End.IsZero():
+		sentences = append(sentences, fmt.Sprintf(m.until, d.End.Format("15:04")))
+	}
+
+	if d.AlternativeTransport != "" {
+		sentences = append(sentences, fmt.Sprintf(m.alternative, strings.TrimSuffix(d.AlternativeTransport, ".")))
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// joinStations lists station names in prose, summarizing long lists.
+func (m advisoryMessages) joinStations(names []string) string {
+	if len(names) > maxAdvisoryStations {
+		listed := strings.Join(names[:maxAdvisoryStations], ", ")
+		if remaining := len(names) - maxAdvisoryStations; remaining > 1 {
+			return fmt.Sprintf(m.otherStations, listed, remaining)
+		}
+		return fmt.Sprintf(m.otherStation, listed)
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + m.and + " " + names[len(names)-1]
+}
+
+// stationNames returns the distinct affected station names, falling back to
+// codes when names are not available.
+func (d *Disruption) stationNames() []string {
+	source := d.AffectedStationNames
+	if len(source) == 0 {
+		source = d.AffectedStations
+	}
+
+	seen := make(map[string]bool, len(source))
+	names := make([]string, 0, len(source))
+	for _, name := range source {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,129 @@
+package transit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/transit"
+)
+
+func TestGenerateAdvisory_Empty(t *testing.T) {
+	advisory := transit.GenerateAdvisory(nil)
+
+	assert.Empty(t, advisory.Headline)
+	assert.Empty(t, advisory.Details)
+	assert.Empty(t, advisory.Text())
+}
+
+func TestGenerateAdvisory_Details(t *testing.T) {
+	end := time.Date(2024, 6, 1, 18, 30, 0, 0, time.UTC)
+	disruptions := []*transit.Disruption{
+		{
+			Title:                "Track work",
+			Impact:               transit.ImpactMinor,
+			AffectedStations:     []string{"DT"},
+			AffectedStationNames: []string{"Delft"},
+		},
+		{
+			Title:                "Signal failure",
+			Impact:               transit.ImpactSevere,
+			AffectedStations:     []string{"ASD", "ASD", "UT"},
+			AffectedStationNames: []string{"Amsterdam Centraal", "Amsterdam Centraal", "Utrecht Centraal"},
+			ExpectedDuration:     45,
+			End:                  end,
+			AlternativeTransport: "Bus replacement between Amsterdam and Utrecht.",
+		},
+	}
+
+	advisory := transit.GenerateAdvisory(disruptions)
+
+	assert.Equal(t, transit.ImpactSevere, advisory.Severity)
+	assert.Equal(t, transit.LocaleEnglish, advisory.Locale)
+	assert.Contains(t, advisory.Headline, "Severe disruptions")
+	require.Len(t, advisory.Details, 2)
+
+	// Most severe first, duplicate stations collapsed
+	assert.Equal(t,
+		"Signal failure at Amsterdam Centraal and Utrecht Centraal. "+
+			"Expect around 45 minutes of delay, until 18:30. "+
+			"Alternative transport: Bus replacement between Amsterdam and Utrecht.",
+		advisory.Details[0])
+	assert.Equal(t, "Track work at Delft.", advisory.Details[1])
+}
+
+func TestGenerateAdvisory_FallsBackToStationCodes(t *testing.T) {
+	advisory := transit.GenerateAdvisory([]*transit.Disruption{
+		{Impact: transit.ImpactModerate, AffectedStations: []string{"ASD", "ASS", "UT", "RTD", "GVC"}},
+	})
+
+	require.Len(t, advisory.Details, 1)
+	assert.Equal(t, "Disruption at ASD, ASS, UT and 2 other stations.", advisory.Details[0])
+}
+
+func TestAdvisory_Text(t *testing.T) {
+	advisory := transit.GenerateAdvisory([]*transit.Disruption{
+		{Title: "Delays", Impact: transit.ImpactMinor},
+	})
+
+	assert.Equal(t, "Minor disruptions reported. Slight delays possible. Delays.", advisory.Text())
+}
+
+func TestGenerateLocalizedAdvisory_DutchHeadlines(t *testing.T) {
+	tests := []struct {
+		impact   transit.Impact
+		expected string
+	}{
+		{transit.ImpactSevere, "Ernstige verstoringen op je route. Er rijden geen treinen. Gebruik alternatief vervoer."},
+		{transit.ImpactMajor, "Grote verstoringen verwacht. Flinke vertragingen of uitval mogelijk. Houd rekening met extra reistijd."},
+		{transit.ImpactModerate, "Verstoringen op je route. Enige vertraging verwacht. Controleer de vertrektijden voordat je reist."},
+		{transit.ImpactMinor, "Kleine verstoringen gemeld. Lichte vertraging mogelijk."},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.impact), func(t *testing.T) {
+			advisory := transit.GenerateLocalizedAdvisory([]*transit.Disruption{{Impact: tt.impact}}, "nl-NL")
+
+			assert.Equal(t, tt.expected, advisory.Headline)
+			assert.Equal(t, transit.LocaleDutch, advisory.Locale)
+		})
+	}
+}
+
+func TestGenerateLocalizedAdvisory_DutchDetails(t *testing.T) {
+	advisory := transit.GenerateLocalizedAdvisory([]*transit.Disruption{
+		{
+			Title:                "Seinstoring",
+			Impact:               transit.ImpactMajor,
+			AffectedStationNames: []string{"Amsterdam Centraal", "Utrecht Centraal"},
+			ExpectedDuration:     30,
+			AlternativeTransport: "Bussen tussen Amsterdam en Utrecht",
+		},
+	}, "nl")
+
+	require.Len(t, advisory.Details, 1)
+	assert.Equal(t,
+		"Seinstoring bij Amsterdam Centraal en Utrecht Centraal. "+
+			"Reken op ongeveer 30 minuten vertraging. "+
+			"Alternatief vervoer: Bussen tussen Amsterdam en Utrecht.",
+		advisory.Details[0])
+}
+
+func TestGenerateLocalizedAdvisory_UnknownLocaleFallsBackToEnglish(t *testing.T) {
+	advisory := transit.GenerateLocalizedAdvisory([]*transit.Disruption{{Impact: transit.ImpactMinor}}, "fr-FR")
+
+	assert.Equal(t, transit.LocaleEnglish, advisory.Locale)
+	assert.Equal(t, "Minor disruptions reported. Slight delays possible.", advisory.Headline)
+}
+
+func TestResolveLocale(t *testing.T) {
+	assert.Equal(t, transit.LocaleDutch, transit.ResolveLocale("nl-NL"))
+	assert.Equal(t, transit.LocaleDutch, transit.ResolveLocale("NL_be"))
+	assert.Equal(t, transit.LocaleEnglish, transit.ResolveLocale("en-GB"))
+	assert.Equal(t, transit.LocaleEnglish, transit.ResolveLocale("de"))
+	assert.Equal(t, transit.LocaleEnglish, transit.ResolveLocale(""))
+	assert.True(t, transit.SupportsLocale("nl-NL"))
+	assert.False(t, transit.SupportsLocale("de-DE"))
+}
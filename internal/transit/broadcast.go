@@ -0,0 +1,71 @@
+package transit
+
+import (
+	"sync"
+)
+
+// subscriberBuffer is how many change sets a subscriber may fall behind by
+// before it is dropped.
+const subscriberBuffer = 8
+
+// broadcaster fans disruption changes out to in-process subscribers.
+type broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan DisruptionChanges]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{
+		subscribers: make(map[chan DisruptionChanges]struct{}),
+	}
+}
+
+// subscribe registers a subscriber and returns its channel and a function
+// that unsubscribes it.
+func (b *broadcaster) subscribe() (<-chan DisruptionChanges, func()) {
+	ch := make(chan DisruptionChanges, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { b.remove(ch) })
+	}
+}
+
+// publish delivers changes to every subscriber without blocking. A subscriber
+// whose buffer is full has missed changes, so its channel is closed instead;
+// it should reconnect and start again from a fresh summary.
+func (b *broadcaster) publish(changes DisruptionChanges) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- changes:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// count returns the number of subscribers.
+func (b *broadcaster) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove unsubscribes ch and closes it if it is still registered.
+func (b *broadcaster) remove(ch chan DisruptionChanges) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
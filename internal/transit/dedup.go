@@ -0,0 +1,124 @@
+package transit
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDedupWindow is how far apart the start times of two reports of the
+// same incident may be for them to be merged.
+const DefaultDedupWindow = 30 * time.Minute
+
+// disruptionFingerprint identifies reports that may describe the same incident:
+// same type and exactly the same set of affected stations.
+func disruptionFingerprint(d *Disruption) string {
+	stations := make([]string, 0, len(d.AffectedStations))
+	seen := make(map[string]bool, len(d.AffectedStations))
+	for _, code := range d.AffectedStations {
+		code = strings.ToUpper(code)
+		if !seen[code] {
+			seen[code] = true
+			stations = append(stations, code)
+		}
+	}
+	sort.Strings(stations)
+	return string(d.Type) + "|" + strings.Join(stations, ",")
+}
+
+// DeduplicateDisruptions merges reports of the same incident, such as one
+// published as planned and again as actual. Reports are merged only when they
+// share a fingerprint (type and full station set) and their start times are
+// within window, so distinct disruptions that merely share a station are kept.
+// The merged disruption keeps the most severe impact. Order of first
+// appearance is preserved.
+func DeduplicateDisruptions(disruptions []*Disruption, window time.Duration) []*Disruption {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+
+	groups := make(map[string][]*Disruption)
+	result := make([]*Disruption, 0, len(disruptions))
+
+	for _, d := range disruptions {
+		if d == nil {
+			continue
+		}
+		key := disruptionFingerprint(d)
+
+		merged := false
+		for _, existing := range groups[key] {
+			if withinWindow(existing.Start, d.Start, window) {
+				mergeDisruption(existing, d)
+				merged = true
+				break
+			}
+		}
+		if merged {
+			continue
+		}
+
+		// Copy so merging never modifies provider-owned data
+		clone := *d
+		clone.AffectedRoutes = append([]string(nil), d.AffectedRoutes...)
+		groups[key] = append(groups[key], &clone)
+		result = append(result, &clone)
+	}
+
+	return result
+}
+
+// withinWindow reports whether two start times are at most window apart.
+func withinWindow(a, b time.Time, window time.Duration) bool {
+	diff := a.Sub(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= window
+}
+
+// mergeDisruption folds a duplicate report into target. The more severe
+// report's descriptive fields win; gaps are filled from the other.
+func mergeDisruption(target, duplicate *Disruption) {
+	if impactRank(duplicate.Impact) > impactRank(target.Impact) {
+		target.ID = duplicate.ID
+		target.Title = duplicate.Title
+		target.Description = duplicate.Description
+		target.Impact = duplicate.Impact
+		target.IsPlanned = duplicate.IsPlanned
+		if duplicate.Cause != "" {
+			target.Cause = duplicate.Cause
+		}
+	}
+
+	if target.Description == "" {
+		target.Description = duplicate.Description
+	}
+	if target.Cause == "" {
+		target.Cause = duplicate.Cause
+	}
+	if target.AlternativeTransport == "" {
+		target.AlternativeTransport = duplicate.AlternativeTransport
+	}
+	if len(target.AffectedStationNames) == 0 {
+		target.AffectedStationNames = duplicate.AffectedStationNames
+	}
+	if duplicate.ExpectedDuration > target.ExpectedDuration {
+		target.ExpectedDuration = duplicate.ExpectedDuration
+	}
+	if !duplicate.Start.IsZero() && (target.Start.IsZero() || duplicate.Start.Before(target.Start)) {
+		target.Start = duplicate.Start
+	}
+	if duplicate.End.After(target.End) {
+		target.End = duplicate.End
+	}
+	if duplicate.LastUpdated.After(target.LastUpdated) {
+		target.LastUpdated = duplicate.LastUpdated
+	}
+
+	for _, route := range duplicate.AffectedRoutes {
+		if !target.AffectsRoute(route) {
+			target.AffectedRoutes = append(target.AffectedRoutes, route)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package transit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/transit"
+)
+
+func TestDeduplicateDisruptions_MergesPlannedAndActual(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	planned := &transit.Disruption{
+		ID:               "planned-1",
+		Type:             transit.DisruptionMaintenance,
+		Title:            "Track work",
+		Impact:           transit.ImpactMinor,
+		AffectedStations: []string{"UT", "ASD"},
+		AffectedRoutes:   []string{"Amsterdam - Utrecht"},
+		Start:            start,
+		End:              start.Add(2 * time.Hour),
+		IsPlanned:        true,
+	}
+	actual := &transit.Disruption{
+		ID:                   "actual-1",
+		Type:                 transit.DisruptionMaintenance,
+		Title:                "Track work, no trains",
+		Impact:               transit.ImpactSevere,
+		AffectedStations:     []string{"ASD", "UT"},
+		AffectedRoutes:       []string{"Utrecht - Amsterdam"},
+		Start:                start.Add(10 * time.Minute),
+		End:                  start.Add(3 * time.Hour),
+		AlternativeTransport: "Bus replacement",
+	}
+
+	result := transit.DeduplicateDisruptions([]*transit.Disruption{planned, actual}, 0)
+
+	require.Len(t, result, 1)
+	merged := result[0]
+	assert.Equal(t, "actual-1", merged.ID)
+	assert.Equal(t, transit.ImpactSevere, merged.Impact)
+	assert.False(t, merged.IsPlanned)
+	assert.Equal(t, start, merged.Start)
+	assert.Equal(t, start.Add(3*time.Hour), merged.End)
+	assert.Equal(t, "Bus replacement", merged.AlternativeTransport)
+	assert.ElementsMatch(t, []string{"Amsterdam - Utrecht", "Utrecht - Amsterdam"}, merged.AffectedRoutes)
+
+	// Inputs are not modified
+	assert.Equal(t, transit.ImpactMinor, planned.Impact)
+	assert.Len(t, planned.AffectedRoutes, 1)
+}
+
+func TestDeduplicateDisruptions_KeepsDistinctDisruptions(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	disruptions := []*transit.Disruption{
+		{ID: "a", Type: transit.DisruptionDisturbance, Impact: transit.ImpactMajor, AffectedStations: []string{"ASD", "UT"}, Start: start},
+		// Shares a station but affects a different stretch
+		{ID: "b", Type: transit.DisruptionDisturbance, Impact: transit.ImpactMinor, AffectedStations: []string{"ASD", "HLM"}, Start: start},
+		// Same stations, different type
+		{ID: "c", Type: transit.DisruptionMaintenance, Impact: transit.ImpactMinor, AffectedStations: []string{"ASD", "UT"}, Start: start},
+		// Same stations and type, but a separate incident hours later
+		{ID: "d", Type: transit.DisruptionDisturbance, Impact: transit.ImpactMinor, AffectedStations: []string{"ASD", "UT"}, Start: start.Add(4 * time.Hour)},
+	}
+
+	result := transit.DeduplicateDisruptions(disruptions, 30*time.Minute)
+
+	require.Len(t, result, 4)
+	for i, d := range result {
+		assert.Equal(t, disruptions[i].ID, d.ID)
+	}
+}
+
+func TestService_GetDisruptionSummary_DedupCounts(t *testing.T) {
+	provider := newMockProvider()
+	duplicate := *provider.disruptions[0]
+	duplicate.ID = "d1-actual"
+	duplicate.Impact = transit.ImpactMajor
+	duplicate.IsPlanned = false
+	provider.disruptions = append(provider.disruptions, &duplicate)
+
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	summary, err := service.GetDisruptionSummary(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, summary.OriginalCount)
+	assert.Equal(t, 2, summary.DedupedCount)
+	assert.Equal(t, 2, summary.TotalDisruptions)
+	assert.Equal(t, 2, summary.ByImpact[transit.ImpactMajor])
+}
@@ -0,0 +1,109 @@
+package transit
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+)
+
+// DisruptionChanges describes how disruptions changed between two cache refreshes.
+// Each list is sorted by disruption ID so identical data always yields the same diff.
+type DisruptionChanges struct {
+	// Added are disruptions not present in the previous snapshot.
+	Added []*Disruption
+
+	// Removed are disruptions that have cleared since the previous snapshot.
+	Removed []*Disruption
+
+	// Changed are disruptions whose user-visible details changed (new version).
+	Changed []*Disruption
+
+	// DetectedAt is when the change was detected.
+	DetectedAt time.Time
+}
+
+// IsEmpty reports whether no disruptions were added, removed or changed.
+func (c DisruptionChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// ChangeNotifier receives disruption changes detected on cache refresh.
+type ChangeNotifier interface {
+	NotifyChanges(ctx context.Context, changes DisruptionChanges)
+}
+
+// DiffDisruptions compares two snapshots by disruption ID. The comparison
+// ignores ordering, both of the snapshots and of station and route lists.
+func DiffDisruptions(previous, current []*Disruption) DisruptionChanges {
+	before := indexByID(previous)
+	after := indexByID(current)
+
+	var changes DisruptionChanges
+	for id, d := range after {
+		old, ok := before[id]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, d)
+		case !sameDisruption(old, d):
+			changes.Changed = append(changes.Changed, d)
+		}
+	}
+	for id, d := range before {
+		if _, ok := after[id]; !ok {
+			changes.Removed = append(changes.Removed, d)
+		}
+	}
+
+	sortByID(changes.Added)
+	sortByID(changes.Removed)
+	sortByID(changes.Changed)
+	return changes
+}
+
+// indexByID maps disruptions by ID; later duplicates win.
+func indexByID(disruptions []*Disruption) map[string]*Disruption {
+	index := make(map[string]*Disruption, len(disruptions))
+	for _, d := range disruptions {
+		if d != nil {
+			index[d.ID] = d
+		}
+	}
+	return index
+}
+
+// sortByID sorts disruptions by ID in place.
+func sortByID(disruptions []*Disruption) {
+	sort.Slice(disruptions, func(a, b int) bool {
+		return disruptions[a].ID < disruptions[b].ID
+	})
+}
+
+// sameDisruption compares the fields subscribers care about. Bookkeeping
+// fields such as LastUpdated are ignored so a refetch alone is not a change.
+func sameDisruption(a, b *Disruption) bool {
+	return a.Type == b.Type &&
+		a.Title == b.Title &&
+		a.Description == b.Description &&
+		a.Impact == b.Impact &&
+		a.ExpectedDuration == b.ExpectedDuration &&
+		a.Start.Equal(b.Start) &&
+		a.End.Equal(b.End) &&
+		a.IsPlanned == b.IsPlanned &&
+		a.AlternativeTransport == b.AlternativeTransport &&
+		a.Cause == b.Cause &&
+		sameSet(a.AffectedStations, b.AffectedStations) &&
+		sameSet(a.AffectedRoutes, b.AffectedRoutes)
+}
+
+// sameSet reports whether two string slices hold the same elements, ignoring order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := slices.Clone(a)
+	sortedB := slices.Clone(b)
+	slices.Sort(sortedA)
+	slices.Sort(sortedB)
+	return slices.Equal(sortedA, sortedB)
+}
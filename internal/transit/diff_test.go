@@ -0,0 +1,176 @@
+package transit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/transit"
+)
+
+func ids(disruptions []*transit.Disruption) []string {
+	result := make([]string, 0, len(disruptions))
+	for _, d := range disruptions {
+		result = append(result, d.ID)
+	}
+	return result
+}
+
+func TestDiffDisruptions(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	previous := []*transit.Disruption{
+		{ID: "a", Impact: transit.ImpactMinor, AffectedStations: []string{"ASD"}, Start: start},
+		{ID: "b", Impact: transit.ImpactMinor, AffectedStations: []string{"UT"}, Start: start},
+		{ID: "c", Impact: transit.ImpactMinor, AffectedStations: []string{"RTD"}, Start: start},
+	}
+	current := []*transit.Disruption{
+		{ID: "d", Impact: transit.ImpactMajor, AffectedStations: []string{"GVC"}, Start: start},
+		{ID: "b", Impact: transit.ImpactSevere, AffectedStations: []string{"UT"}, Start: start},
+		{ID: "a", Impact: transit.ImpactMinor, AffectedStations: []string{"ASD"}, Start: start, LastUpdated: time.Now()},
+	}
+
+	changes := transit.DiffDisruptions(previous, current)
+
+	assert.Equal(t, []string{"d"}, ids(changes.Added))
+	assert.Equal(t, []string{"c"}, ids(changes.Removed))
+	assert.Equal(t, []string{"b"}, ids(changes.Changed))
+	assert.False(t, changes.IsEmpty())
+}
+
+func TestDiffDisruptions_StableAcrossOrdering(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	snapshot := []*transit.Disruption{
+		{ID: "a", AffectedStations: []string{"ASD", "UT"}, AffectedRoutes: []string{"r1", "r2"}, Start: start},
+		{ID: "b", AffectedStations: []string{"RTD"}, Start: start},
+	}
+	reordered := []*transit.Disruption{
+		{ID: "b", AffectedStations: []string{"RTD"}, Start: start},
+		{ID: "a", AffectedStations: []string{"UT", "ASD"}, AffectedRoutes: []string{"r2", "r1"}, Start: start},
+	}
+
+	assert.True(t, transit.DiffDisruptions(snapshot, reordered).IsEmpty())
+}
+
+// recordingNotifier collects change notifications.
+type recordingNotifier struct {
+	changes chan transit.DisruptionChanges
+}
+
+func (n *recordingNotifier) NotifyChanges(_ context.Context, changes transit.DisruptionChanges) {
+	n.changes <- changes
+}
+
+func TestService_NotifiesChangesOnRefresh(t *testing.T) {
+	provider := newMockProvider()
+	notifier := &recordingNotifier{changes: make(chan transit.DisruptionChanges, 4)}
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: time.Nanosecond,
+		Notifier: notifier,
+	})
+
+	// First fetch establishes the baseline without notifying
+	_, err := service.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	// Same data in a different order is not a change
+	provider.mu.Lock()
+	provider.disruptions = []*transit.Disruption{provider.disruptions[1], provider.disruptions[0]}
+	provider.mu.Unlock()
+	_, err = service.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	// A cleared disruption is reported as removed
+	provider.mu.Lock()
+	provider.disruptions = provider.disruptions[1:]
+	provider.mu.Unlock()
+	_, err = service.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	select {
+	case changes := <-notifier.changes:
+		assert.Empty(t, changes.Added)
+		assert.Equal(t, []string{"d2"}, ids(changes.Removed))
+		assert.False(t, changes.DetectedAt.IsZero())
+	case <-time.After(time.Second):
+		t.Fatal("expected change notification")
+	}
+
+	select {
+	case changes := <-notifier.changes:
+		t.Fatalf("unexpected extra notification: %+v", changes)
+	default:
+	}
+}
+
+func TestService_SubscribeChanges(t *testing.T) {
+	provider := newMockProvider()
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: time.Nanosecond,
+	})
+
+	_, err := service.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	changes, unsubscribe := service.SubscribeChanges()
+
+	provider.mu.Lock()
+	provider.disruptions = provider.disruptions[1:]
+	provider.mu.Unlock()
+	_, err = service.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	select {
+	case delta := <-changes:
+		assert.Equal(t, []string{"d1"}, ids(delta.Removed))
+	default:
+		t.Fatal("expected changes to be published to subscriber")
+	}
+
+	unsubscribe()
+	_, ok := <-changes
+	assert.False(t, ok, "channel should be closed after unsubscribe")
+	unsubscribe() // safe to call twice
+}
+
+func TestService_SubscribeChanges_DropsSlowSubscriber(t *testing.T) {
+	provider := newMockProvider()
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: time.Nanosecond,
+	})
+
+	_, err := service.GetAllDisruptions(context.Background())
+	require.NoError(t, err)
+
+	changes, unsubscribe := service.SubscribeChanges()
+	defer unsubscribe()
+
+	// Flip between two states more often than the subscriber buffer holds
+	all := provider.disruptions
+	for i := 0; i < 20; i++ {
+		provider.mu.Lock()
+		if i%2 == 0 {
+			provider.disruptions = all[1:]
+		} else {
+			provider.disruptions = all
+		}
+		provider.mu.Unlock()
+		_, err = service.GetAllDisruptions(context.Background())
+		require.NoError(t, err)
+	}
+
+	received := 0
+	for range changes {
+		received++
+	}
+	assert.Less(t, received, 20, "a subscriber that falls behind is closed")
+}
@@ -0,0 +1,66 @@
+package transit
+
+import (
+	"context"
+	"errors"
+)
+
+// GetDisruptionsForLeg returns the disruptions affecting a train leg between
+// two stations, each given as a station code or name. Endpoints are resolved
+// against the station list, so a leg described by names matches disruptions
+// reported by code and vice versa. Disruptions are matched by the provider's
+// route filter and additionally by station name, since NS does not always
+// report a code for every affected section. The advisory is in locale.
+func (s *Service) GetDisruptionsForLeg(ctx context.Context, origin, destination, locale string) (*RouteDisruptions, error) {
+	from := s.legStation(ctx, origin)
+	to := s.legStation(ctx, destination)
+
+	data, err := s.GetDisruptionsForRouteInLocale(ctx, from.Code, to.Code, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	// Disruptions reported only by station name are missed by code matching.
+	// On error the route result still stands, without name matches.
+	active, _ := s.GetActiveDisruptions(ctx)
+
+	seen := make(map[string]bool, len(data.Disruptions))
+	for _, d := range data.Disruptions {
+		seen[d.ID] = true
+	}
+	var extra []*Disruption
+	for _, d := range active {
+		if seen[d.ID] {
+			continue
+		}
+		if d.AffectsStationName(from.Name) || d.AffectsStationName(to.Name) {
+			extra = append(extra, d)
+			seen[d.ID] = true
+		}
+	}
+	if len(extra) == 0 {
+		return data, nil
+	}
+
+	// Copy so the cached result is not modified
+	merged := *data
+	merged.Disruptions = append(append([]*Disruption(nil), data.Disruptions...), extra...)
+	merged.HasDisruptions = true
+	merged.OverallImpact = CalculateOverallImpact(merged.Disruptions)
+	merged.Advisory = GenerateLocalizedAdvisory(merged.Disruptions, merged.Locale)
+	merged.AdvisoryMessage = merged.Advisory.Text()
+	return &merged, nil
+}
+
+// legStation resolves a leg endpoint to a station. If the station list is
+// unavailable or has no match, the raw endpoint is used as both code and name.
+func (s *Service) legStation(ctx context.Context, codeOrName string) Station {
+	station, err := s.ResolveStation(ctx, codeOrName)
+	if err == nil {
+		return *station
+	}
+	if !errors.Is(err, ErrStationNotFound) {
+		s.logger.Warn().Err(err).Str("station", codeOrName).Msg("station list unavailable, matching leg by raw value")
+	}
+	return Station{Code: normalizeStationCode(codeOrName), Name: codeOrName}
+}
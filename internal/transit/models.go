@@ -2,6 +2,7 @@ package transit
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -9,6 +10,7 @@ import (
 var (
 	ErrProviderUnavailable = errors.New("transit provider unavailable")
 	ErrNoDisruptions       = errors.New("no disruptions found")
+	ErrStationNotFound     = errors.New("station not found")
 )
 
 // DisruptionType represents the type of transit disruption.
@@ -56,6 +58,10 @@ type Disruption struct {
 	// AffectedStations lists station codes affected (e.g., "ASD", "RTD").
 	AffectedStations []string
 
+	// AffectedStationNames lists the names of the affected stations, in the
+	// same order as AffectedStations (e.g., "Amsterdam Centraal").
+	AffectedStationNames []string
+
 	// ExpectedDuration is the estimated delay in minutes (0 if unknown).
 	ExpectedDuration int
 
@@ -94,15 +100,46 @@ func (d *Disruption) IsActive() bool {
 }
 
 // AffectsStation returns true if the disruption affects the given station code.
+// Codes are compared case-insensitively; NS feeds are not consistent.
 func (d *Disruption) AffectsStation(stationCode string) bool {
+	code := normalizeStationCode(stationCode)
+	if code == "" {
+		return false
+	}
 	for _, s := range d.AffectedStations {
-		if s == stationCode {
+		if normalizeStationCode(s) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// AffectsStationName returns true if the disruption affects a station with
+// the given name, ignoring case and spacing.
+func (d *Disruption) AffectsStationName(name string) bool {
+	want := normalizeStationName(name)
+	if want == "" {
+		return false
+	}
+	for _, n := range d.AffectedStationNames {
+		if normalizeStationName(n) == want {
 			return true
 		}
 	}
 	return false
 }
 
+// normalizeStationCode returns a station code in its canonical upper-case form.
+func normalizeStationCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// normalizeStationName returns a station name in lower case with runs of
+// whitespace collapsed, for matching names from different feeds.
+func normalizeStationName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
 // AffectsRoute returns true if the disruption affects the given route.
 func (d *Disruption) AffectsRoute(route string) bool {
 	for _, r := range d.AffectedRoutes {
@@ -127,6 +164,13 @@ type DisruptionSummary struct {
 	// MostSevere is the highest impact level among active disruptions.
 	MostSevere Impact
 
+	// OriginalCount is the number of disruptions reported by the provider,
+	// including duplicate reports of the same incident.
+	OriginalCount int
+
+	// DedupedCount is the number of disruptions after merging duplicates.
+	DedupedCount int
+
 	// FetchedAt is when this summary was generated.
 	FetchedAt time.Time
 
@@ -167,9 +211,15 @@ type RouteDisruptions struct {
 	// HasDisruptions indicates if any disruptions affect this route.
 	HasDisruptions bool
 
-	// AdvisoryMessage is a user-friendly summary.
+	// Advisory is a structured summary with per-disruption details.
+	Advisory Advisory
+
+	// AdvisoryMessage is a user-friendly summary (Advisory.Text()).
 	AdvisoryMessage string
 
+	// Locale is the language of the advisory (e.g., "nl").
+	Locale string
+
 	// FetchedAt is when this was retrieved.
 	FetchedAt time.Time
 }
@@ -180,19 +230,28 @@ func CalculateOverallImpact(disruptions []*Disruption) Impact {
 		return ""
 	}
 
-	impactOrder := map[Impact]int{
-		ImpactMinor:    1,
-		ImpactModerate: 2,
-		ImpactMajor:    3,
-		ImpactSevere:   4,
-	}
-
 	highest := ImpactMinor
 	for _, d := range disruptions {
-		if impactOrder[d.Impact] > impactOrder[highest] {
+		if impactRank(d.Impact) > impactRank(highest) {
 			highest = d.Impact
 		}
 	}
 
 	return highest
 }
+
+// impactRank orders impact levels from least (1) to most (4) severe.
+func impactRank(impact Impact) int {
+	switch impact {
+	case ImpactMinor:
+		return 1
+	case ImpactModerate:
+		return 2
+	case ImpactMajor:
+		return 3
+	case ImpactSevere:
+		return 4
+	default:
+		return 0
+	}
+}
@@ -34,6 +34,10 @@ type ClientConfig struct {
 	// If nil, uses a resilient client with defaults.
 	HTTPClient *resilience.Client
 
+	// Registry is the provider registry the default client registers with
+	// for health tracking (optional).
+	Registry *resilience.Registry
+
 	// Logger for client operations.
 	Logger zerolog.Logger
 }
@@ -55,7 +59,9 @@ func NewClient(cfg ClientConfig) *Client {
 
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
-		httpClient = resilience.NewClient(resilience.DefaultClientConfig("ns"))
+		clientCfg := resilience.DefaultClientConfig("ns")
+		clientCfg.Registry = cfg.Registry
+		httpClient = resilience.NewClient(clientCfg)
 	}
 
 	return &Client{
@@ -89,7 +95,7 @@ func (c *Client) GetAllDisruptions(ctx context.Context) ([]*transit.Disruption,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &resilience.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var nsResp disruptionsResponse
@@ -137,11 +143,13 @@ func (c *Client) GetDisruptionsForRoute(ctx context.Context, origin, destination
 		Disruptions:    relevant,
 		HasDisruptions: len(relevant) > 0,
 		FetchedAt:      time.Now(),
+		Locale:         transit.LocaleEnglish,
 	}
 
 	if len(relevant) > 0 {
 		result.OverallImpact = transit.CalculateOverallImpact(relevant)
-		result.AdvisoryMessage = c.generateAdvisory(relevant)
+		result.Advisory = transit.GenerateAdvisory(relevant)
+		result.AdvisoryMessage = result.Advisory.Text()
 	}
 
 	return result, nil
@@ -165,7 +173,7 @@ func (c *Client) GetStations(ctx context.Context) ([]*transit.Station, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &resilience.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var nsResp stationsResponse
@@ -218,6 +226,7 @@ func (c *Client) toDisruption(d *nsDisruption) *transit.Disruption {
 		routeName := fmt.Sprintf("%s - %s", section.Station.Name, section.Direction)
 		disruption.AffectedRoutes = append(disruption.AffectedRoutes, routeName)
 		disruption.AffectedStations = append(disruption.AffectedStations, section.Station.Code)
+		disruption.AffectedStationNames = append(disruption.AffectedStationNames, section.Station.Name)
 	}
 
 	// Alternative transport
@@ -278,28 +287,6 @@ func mapImpact(nsImpact string) transit.Impact {
 	}
 }
 
-// generateAdvisory creates a user-friendly advisory message.
-func (c *Client) generateAdvisory(disruptions []*transit.Disruption) string {
-	if len(disruptions) == 0 {
-		return ""
-	}
-
-	impact := transit.CalculateOverallImpact(disruptions)
-
-	switch impact {
-	case transit.ImpactSevere:
-		return "Severe disruptions on your route. No train service available. Please use alternative transport."
-	case transit.ImpactMajor:
-		return "Major disruptions expected. Significant delays or cancellations possible. Plan extra travel time."
-	case transit.ImpactModerate:
-		return "Moderate disruptions on your route. Some delays expected. Check departure times before traveling."
-	case transit.ImpactMinor:
-		return "Minor disruptions reported. Slight delays possible."
-	}
-
-	return ""
-}
-
 // NS API response structures.
 
 type disruptionsResponse []nsDisruption
@@ -180,6 +180,10 @@ func TestClient_GetDisruptionsForRoute(t *testing.T) {
 	assert.Len(t, result.Disruptions, 1) // Only disruption-1 affects ASD
 	assert.Equal(t, transit.ImpactMajor, result.OverallImpact)
 	assert.NotEmpty(t, result.AdvisoryMessage)
+	assert.Equal(t, transit.ImpactMajor, result.Advisory.Severity)
+	require.Len(t, result.Advisory.Details, 1)
+	assert.Equal(t, "Signal failure at Amsterdam Centraal.", result.Advisory.Details[0])
+	assert.Equal(t, result.Advisory.Text(), result.AdvisoryMessage)
 }
 
 func TestClient_GetDisruptionsForRoute_NoDisruptions(t *testing.T) {
@@ -3,12 +3,19 @@ package transit
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
 )
 
+// stationsStoreKey is the key of the persisted station list in a SnapshotStore.
+const stationsStoreKey = "transit-stations"
+
 // Provider defines the interface for transit disruption data providers.
 type Provider interface {
 	// GetAllDisruptions fetches all current disruptions.
@@ -42,6 +49,18 @@ type ServiceConfig struct {
 
 	// StaleIfErrorTTL allows serving stale data on provider errors (default: 30 minutes).
 	StaleIfErrorTTL time.Duration
+
+	// DedupWindow is how far apart start times of duplicate reports of the
+	// same incident may be (default: 30 minutes).
+	DedupWindow time.Duration
+
+	// Notifier, if set, receives the disruptions added, removed or changed
+	// on each cache refresh. It is called asynchronously.
+	Notifier ChangeNotifier
+
+	// Store persists each refreshed station list so RestoreStations can load
+	// it after a restart (optional; default: nothing is persisted).
+	Store cache.SnapshotStore
 }
 
 // Service provides transit disruption data with caching.
@@ -51,6 +70,14 @@ type Service struct {
 	cacheTTL        time.Duration
 	stationCacheTTL time.Duration
 	staleIfErrorTTL time.Duration
+	dedupWindow     time.Duration
+	notifier        ChangeNotifier
+	broadcaster     *broadcaster
+	store           cache.SnapshotStore
+
+	// Cache lookup counters, updated atomically outside mu
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
 
 	mu              sync.RWMutex
 	disruptionCache *cachedDisruptions
@@ -61,14 +88,16 @@ type Service struct {
 }
 
 type cachedDisruptions struct {
-	disruptions []*Disruption
-	fetchedAt   time.Time
-	expiresAt   time.Time
+	disruptions   []*Disruption
+	originalCount int // before deduplication
+	fetchedAt     time.Time
+	expiresAt     time.Time
 }
 
 type cachedStations struct {
 	stations   []*Station
-	stationMap map[string]*Station // code -> station
+	stationMap map[string]*Station // normalized code -> station
+	byName     map[string]*Station // normalized name -> station
 	fetchedAt  time.Time
 	expiresAt  time.Time
 }
@@ -96,12 +125,26 @@ func NewService(cfg ServiceConfig) *Service {
 		staleIfErrorTTL = 30 * time.Minute
 	}
 
+	dedupWindow := cfg.DedupWindow
+	if dedupWindow == 0 {
+		dedupWindow = DefaultDedupWindow
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = cache.NopStore{}
+	}
+
 	return &Service{
 		provider:        cfg.Provider,
 		logger:          cfg.Logger,
 		cacheTTL:        cacheTTL,
 		stationCacheTTL: stationCacheTTL,
 		staleIfErrorTTL: staleIfErrorTTL,
+		dedupWindow:     dedupWindow,
+		notifier:        cfg.Notifier,
+		broadcaster:     newBroadcaster(),
+		store:           store,
 		routeCache:      make(map[string]*cachedRouteDisruptions),
 		cleanupInterval: 10 * time.Minute,
 	}
@@ -111,6 +154,7 @@ func NewService(cfg ServiceConfig) *Service {
 func (s *Service) GetAllDisruptions(ctx context.Context) ([]*Disruption, error) {
 	s.mu.RLock()
 	if s.disruptionCache != nil && time.Now().Before(s.disruptionCache.expiresAt) {
+		s.cacheHits.Add(1)
 		disruptions := s.disruptionCache.disruptions
 		s.mu.RUnlock()
 		return disruptions, nil
@@ -143,6 +187,7 @@ func (s *Service) GetDisruptionsForRoute(ctx context.Context, origin, destinatio
 
 	s.mu.RLock()
 	if cached, ok := s.routeCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
 		s.mu.RUnlock()
 		return cached.data, nil
 	}
@@ -151,6 +196,26 @@ func (s *Service) GetDisruptionsForRoute(ctx context.Context, origin, destinatio
 	return s.fetchRouteDisruptions(ctx, origin, destination, cacheKey)
 }
 
+// GetDisruptionsForRouteInLocale returns disruptions affecting a route with the
+// advisory written in the given locale (BCP 47, e.g., "nl-NL").
+// Unsupported locales fall back to English.
+func (s *Service) GetDisruptionsForRouteInLocale(ctx context.Context, origin, destination, locale string) (*RouteDisruptions, error) {
+	data, err := s.GetDisruptionsForRoute(ctx, origin, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy so the cached (provider-locale) result is not modified
+	localized := *data
+	localized.Locale = ResolveLocale(locale)
+	if len(localized.Disruptions) > 0 {
+		localized.Advisory = GenerateLocalizedAdvisory(localized.Disruptions, localized.Locale)
+		localized.AdvisoryMessage = localized.Advisory.Text()
+	}
+
+	return &localized, nil
+}
+
 // GetDisruptionSummary returns a summary of current disruptions.
 func (s *Service) GetDisruptionSummary(ctx context.Context) (*DisruptionSummary, error) {
 	disruptions, err := s.GetActiveDisruptions(ctx)
@@ -173,19 +238,57 @@ func (s *Service) GetDisruptionSummary(ctx context.Context) (*DisruptionSummary,
 
 	summary.MostSevere = CalculateOverallImpact(disruptions)
 
+	s.mu.RLock()
+	if s.disruptionCache != nil {
+		summary.OriginalCount = s.disruptionCache.originalCount
+		summary.DedupedCount = len(s.disruptionCache.disruptions)
+	}
+	s.mu.RUnlock()
+
 	return summary, nil
 }
 
-// GetStation returns station info by code.
+// SubscribeChanges returns a channel that receives the disruptions added,
+// removed or changed on each cache refresh, and a function to unsubscribe.
+// Subscribing does not trigger provider calls; changes arrive when the cache
+// is refreshed by other requests or by WatchChanges. The channel is closed
+// on unsubscribe, or early if the subscriber falls too far behind and has
+// missed changes.
+func (s *Service) SubscribeChanges() (<-chan DisruptionChanges, func()) {
+	return s.broadcaster.subscribe()
+}
+
+// GetStation returns station info by code. Codes are case-insensitive.
 func (s *Service) GetStation(ctx context.Context, code string) (*Station, error) {
+	return s.lookupStation(ctx, code, func(c *cachedStations) *Station {
+		return c.stationMap[normalizeStationCode(code)]
+	})
+}
+
+// ResolveStation returns the station identified by codeOrName, which may be
+// a station code ("ASD", "asd") or a station name ("Amsterdam Centraal").
+// Codes take precedence over names.
+func (s *Service) ResolveStation(ctx context.Context, codeOrName string) (*Station, error) {
+	return s.lookupStation(ctx, codeOrName, func(c *cachedStations) *Station {
+		if station, ok := c.stationMap[normalizeStationCode(codeOrName)]; ok {
+			return station
+		}
+		return c.byName[normalizeStationName(codeOrName)]
+	})
+}
+
+// lookupStation finds a station in the station cache, refreshing the cache
+// first if it has expired.
+func (s *Service) lookupStation(ctx context.Context, key string, find func(*cachedStations) *Station) (*Station, error) {
 	s.mu.RLock()
 	if s.stationCache != nil && time.Now().Before(s.stationCache.expiresAt) {
-		if station, ok := s.stationCache.stationMap[code]; ok {
-			s.mu.RUnlock()
-			return station, nil
-		}
+		s.cacheHits.Add(1)
+		station := find(s.stationCache)
 		s.mu.RUnlock()
-		return nil, fmt.Errorf("station not found: %s", code)
+		if station == nil {
+			return nil, fmt.Errorf("%w: %s", ErrStationNotFound, key)
+		}
+		return station, nil
 	}
 	s.mu.RUnlock()
 
@@ -196,10 +299,10 @@ func (s *Service) GetStation(ctx context.Context, code string) (*Station, error)
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	if station, ok := s.stationCache.stationMap[code]; ok {
+	if station := find(s.stationCache); station != nil {
 		return station, nil
 	}
-	return nil, fmt.Errorf("station not found: %s", code)
+	return nil, fmt.Errorf("%w: %s", ErrStationNotFound, key)
 }
 
 // fetchDisruptions fetches from provider and updates cache.
@@ -209,8 +312,10 @@ func (s *Service) fetchDisruptions(ctx context.Context) ([]*Disruption, error) {
 
 	// Double-check cache
 	if s.disruptionCache != nil && time.Now().Before(s.disruptionCache.expiresAt) {
+		s.cacheHits.Add(1)
 		return s.disruptionCache.disruptions, nil
 	}
+	s.cacheMisses.Add(1)
 
 	s.logger.Debug().
 		Str("provider", s.provider.Name()).
@@ -233,16 +338,35 @@ func (s *Service) fetchDisruptions(ctx context.Context) ([]*Disruption, error) {
 		return nil, ErrProviderUnavailable
 	}
 
+	// Sort first so deduplication, and therefore the change diff, does not
+	// depend on the order the provider returned disruptions in.
+	originalCount := len(disruptions)
+	disruptions = slices.Clone(disruptions)
+	sortByID(disruptions)
+	disruptions = DeduplicateDisruptions(disruptions, s.dedupWindow)
+
+	if s.disruptionCache != nil && (s.notifier != nil || s.broadcaster.count() > 0) {
+		if changes := DiffDisruptions(s.disruptionCache.disruptions, disruptions); !changes.IsEmpty() {
+			changes.DetectedAt = time.Now()
+			s.broadcaster.publish(changes)
+			if s.notifier != nil {
+				go s.notifier.NotifyChanges(context.WithoutCancel(ctx), changes)
+			}
+		}
+	}
+
 	// Update cache
 	now := time.Now()
 	s.disruptionCache = &cachedDisruptions{
-		disruptions: disruptions,
-		fetchedAt:   now,
-		expiresAt:   now.Add(s.cacheTTL),
+		disruptions:   disruptions,
+		originalCount: originalCount,
+		fetchedAt:     now,
+		expiresAt:     now.Add(s.cacheTTL),
 	}
 
 	s.logger.Info().
 		Int("disruptions", len(disruptions)).
+		Int("duplicates_merged", originalCount-len(disruptions)).
 		Msg("disruptions cache refreshed")
 
 	return disruptions, nil
@@ -255,8 +379,10 @@ func (s *Service) fetchRouteDisruptions(ctx context.Context, origin, destination
 
 	// Double-check cache
 	if cached, ok := s.routeCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
 		return cached.data, nil
 	}
+	s.cacheMisses.Add(1)
 
 	s.logger.Debug().
 		Str("origin", origin).
@@ -305,8 +431,10 @@ func (s *Service) fetchStations(ctx context.Context) ([]*Station, error) {
 
 	// Double-check cache
 	if s.stationCache != nil && time.Now().Before(s.stationCache.expiresAt) {
+		s.cacheHits.Add(1)
 		return s.stationCache.stations, nil
 	}
+	s.cacheMisses.Add(1)
 
 	s.logger.Debug().
 		Str("provider", s.provider.Name()).
@@ -329,19 +457,12 @@ func (s *Service) fetchStations(ctx context.Context) ([]*Station, error) {
 		return nil, ErrProviderUnavailable
 	}
 
-	// Build station map
-	stationMap := make(map[string]*Station, len(stations))
-	for _, s := range stations {
-		stationMap[s.Code] = s
-	}
-
 	// Update cache
 	now := time.Now()
-	s.stationCache = &cachedStations{
-		stations:   stations,
-		stationMap: stationMap,
-		fetchedAt:  now,
-		expiresAt:  now.Add(s.stationCacheTTL),
+	s.stationCache = s.newCachedStations(stations, now)
+
+	if err := s.store.Save(ctx, stationsStoreKey, storedStations{Stations: stations, FetchedAt: now}); err != nil {
+		s.logger.Warn().Err(err).Msg("failed to persist station list")
 	}
 
 	s.logger.Info().
@@ -351,6 +472,61 @@ func (s *Service) fetchStations(ctx context.Context) ([]*Station, error) {
 	return stations, nil
 }
 
+// storedStations is the persisted form of the station cache.
+type storedStations struct {
+	Stations  []*Station `json:"stations"`
+	FetchedAt time.Time  `json:"fetchedAt"`
+}
+
+// RestoreStations loads the station list persisted by a previous process. It
+// is restored while it is still fresh or within StaleIfErrorTTL, otherwise
+// cache.ErrSnapshotStale is returned; if nothing is stored, cache.ErrSnapshotNotFound
+// is returned. A station list already fetched by this process is never
+// replaced.
+func (s *Service) RestoreStations(ctx context.Context) error {
+	var stored storedStations
+	if err := s.store.Load(ctx, stationsStoreKey, &stored); err != nil {
+		return err
+	}
+
+	usableFor := max(s.stationCacheTTL, s.staleIfErrorTTL)
+	if len(stored.Stations) == 0 || time.Now().After(stored.FetchedAt.Add(usableFor)) {
+		return cache.ErrSnapshotStale
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stationCache != nil {
+		return nil
+	}
+	s.stationCache = s.newCachedStations(stored.Stations, stored.FetchedAt)
+
+	s.logger.Info().
+		Int("stations", len(stored.Stations)).
+		Time("fetched_at", stored.FetchedAt).
+		Msg("station list restored")
+
+	return nil
+}
+
+// newCachedStations indexes stations by normalized code and name.
+func (s *Service) newCachedStations(stations []*Station, fetchedAt time.Time) *cachedStations {
+	stationMap := make(map[string]*Station, len(stations))
+	byName := make(map[string]*Station, len(stations))
+	for _, station := range stations {
+		stationMap[normalizeStationCode(station.Code)] = station
+		byName[normalizeStationName(station.Name)] = station
+	}
+
+	return &cachedStations{
+		stations:   stations,
+		stationMap: stationMap,
+		byName:     byName,
+		fetchedAt:  fetchedAt,
+		expiresAt:  fetchedAt.Add(s.stationCacheTTL),
+	}
+}
+
 // routeCacheKey generates a cache key for a route.
 func (s *Service) routeCacheKey(origin, destination string) string {
 	return origin + ":" + destination
@@ -398,20 +574,26 @@ func (s *Service) CacheStats() CacheStats {
 	stats := CacheStats{
 		Provider:          s.provider.Name(),
 		RouteCacheEntries: len(s.routeCache),
+		Hits:              s.cacheHits.Load(),
+		Misses:            s.cacheMisses.Load(),
 	}
 
 	if s.disruptionCache != nil {
 		stats.HasDisruptionCache = true
-		stats.DisruptionCacheFresh = now.Before(s.disruptionCache.expiresAt)
+		stats.DisruptionCacheFresh = stats.observe(now, s.disruptionCache.fetchedAt, s.disruptionCache.expiresAt)
 		stats.DisruptionCount = len(s.disruptionCache.disruptions)
 	}
 
 	if s.stationCache != nil {
 		stats.HasStationCache = true
-		stats.StationCacheFresh = now.Before(s.stationCache.expiresAt)
+		stats.StationCacheFresh = stats.observe(now, s.stationCache.fetchedAt, s.stationCache.expiresAt)
 		stats.StationCount = len(s.stationCache.stations)
 	}
 
+	for _, c := range s.routeCache {
+		stats.observe(now, c.fetchedAt, c.expiresAt)
+	}
+
 	return stats
 }
 
@@ -425,4 +607,39 @@ type CacheStats struct {
 	StationCacheFresh    bool
 	StationCount         int
 	RouteCacheEntries    int
+
+	// OldestFreshAt is when the oldest unexpired entry was fetched, and
+	// NewestFetchedAt when the newest entry was. Both are zero if there is
+	// no such entry.
+	OldestFreshAt   time.Time
+	NewestFetchedAt time.Time
+
+	// Hits and Misses count cache lookups since the service started.
+	Hits   uint64
+	Misses uint64
+}
+
+// observe adds an entry's fetch time to the stats, reporting whether the
+// entry is still fresh.
+func (c *CacheStats) observe(now, fetchedAt, expiresAt time.Time) bool {
+	if fetchedAt.After(c.NewestFetchedAt) {
+		c.NewestFetchedAt = fetchedAt
+	}
+	if !now.Before(expiresAt) {
+		return false
+	}
+	if c.OldestFreshAt.IsZero() || fetchedAt.Before(c.OldestFreshAt) {
+		c.OldestFreshAt = fetchedAt
+	}
+	return true
+}
+
+// HitRatio returns the fraction of cache lookups served from the cache,
+// or 0 if there have been none.
+func (c CacheStats) HitRatio() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
 }
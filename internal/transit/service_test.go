@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
 	"github.com/breatheroute/breatheroute/internal/transit"
 )
 
@@ -242,6 +243,30 @@ func TestService_GetDisruptionsForRoute_Caching(t *testing.T) {
 	assert.Equal(t, 2, provider.getCallCount())
 }
 
+func TestService_GetDisruptionsForRouteInLocale(t *testing.T) {
+	provider := newMockProvider()
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: 1 * time.Hour,
+	})
+
+	dutch, err := service.GetDisruptionsForRouteInLocale(context.Background(), "ASD", "UT", "nl-NL")
+	require.NoError(t, err)
+	assert.Equal(t, transit.LocaleDutch, dutch.Locale)
+	assert.Equal(t, transit.LocaleDutch, dutch.Advisory.Locale)
+	assert.Equal(t, "Verstoringen op je route. Enige vertraging verwacht. Controleer de vertrektijden voordat je reist.",
+		dutch.Advisory.Headline)
+	assert.Equal(t, dutch.Advisory.Text(), dutch.AdvisoryMessage)
+
+	// Localizing must not change the cached result shared by other locales
+	english, err := service.GetDisruptionsForRouteInLocale(context.Background(), "ASD", "UT", "fr-FR")
+	require.NoError(t, err)
+	assert.Equal(t, transit.LocaleEnglish, english.Locale)
+	assert.Contains(t, english.Advisory.Headline, "Moderate disruptions")
+	assert.Equal(t, 1, provider.getCallCount())
+}
+
 func TestService_GetDisruptionSummary(t *testing.T) {
 	provider := newMockProvider()
 	service := transit.NewService(transit.ServiceConfig{
@@ -309,6 +334,57 @@ func TestService_GetStation_Caching(t *testing.T) {
 	assert.Equal(t, 1, provider.getCallCount())
 }
 
+func TestService_RestoreStations(t *testing.T) {
+	store, err := cache.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	// A previous process fetches and persists the station list
+	previous := transit.NewService(transit.ServiceConfig{
+		Provider: newMockProvider(),
+		Logger:   zerolog.Nop(),
+		Store:    store,
+	})
+	_, err = previous.GetStation(context.Background(), "ASD")
+	require.NoError(t, err)
+
+	provider := newMockProvider()
+	provider.setError(errors.New("api error"))
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		Store:    store,
+	})
+	require.NoError(t, service.RestoreStations(context.Background()))
+
+	station, err := service.GetStation(context.Background(), "asd")
+	require.NoError(t, err)
+	assert.Equal(t, "Amsterdam Centraal", station.Name)
+	assert.Equal(t, 0, provider.getCallCount())
+}
+
+func TestService_RestoreStations_Unusable(t *testing.T) {
+	store, err := cache.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: newMockProvider(),
+		Logger:   zerolog.Nop(),
+		Store:    store,
+	})
+
+	assert.ErrorIs(t, service.RestoreStations(context.Background()), cache.ErrSnapshotNotFound)
+
+	old := map[string]any{
+		"stations":  []*transit.Station{{Code: "ASD", Name: "Amsterdam Centraal"}},
+		"fetchedAt": time.Now().Add(-48 * time.Hour),
+	}
+	require.NoError(t, store.Save(context.Background(), "transit-stations", old))
+	assert.ErrorIs(t, service.RestoreStations(context.Background()), cache.ErrSnapshotStale)
+
+	empty := map[string]any{"stations": []*transit.Station{}, "fetchedAt": time.Now()}
+	require.NoError(t, store.Save(context.Background(), "transit-stations", empty))
+	assert.ErrorIs(t, service.RestoreStations(context.Background()), cache.ErrSnapshotStale)
+}
+
 func TestService_ProviderError(t *testing.T) {
 	provider := newMockProvider()
 	provider.setError(errors.New("api error"))
@@ -400,6 +476,25 @@ func TestService_CacheStats(t *testing.T) {
 	assert.Equal(t, 1, stats.RouteCacheEntries)
 }
 
+func TestService_CacheStats_HitsAndMisses(t *testing.T) {
+	provider := newMockProvider()
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: 1 * time.Hour,
+	})
+
+	_, _ = service.GetAllDisruptions(context.Background())
+	_, _ = service.GetAllDisruptions(context.Background())
+	_, _ = service.GetStation(context.Background(), "ASD")
+	_, _ = service.GetStation(context.Background(), "UT")
+
+	stats := service.CacheStats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.InDelta(t, 0.5, stats.HitRatio(), 0.0001)
+}
+
 func TestDisruption_IsActive(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -451,7 +546,22 @@ func TestDisruption_AffectsStation(t *testing.T) {
 
 	assert.True(t, d.AffectsStation("ASD"))
 	assert.True(t, d.AffectsStation("UT"))
+	assert.True(t, d.AffectsStation("asd"))
+	assert.True(t, d.AffectsStation(" rtd "))
 	assert.False(t, d.AffectsStation("DH"))
+	assert.False(t, d.AffectsStation(""))
+}
+
+func TestDisruption_AffectsStationName(t *testing.T) {
+	d := &transit.Disruption{
+		AffectedStations:     []string{"ASD", ""},
+		AffectedStationNames: []string{"Amsterdam Centraal", "Utrecht  Centraal"},
+	}
+
+	assert.True(t, d.AffectsStationName("Amsterdam Centraal"))
+	assert.True(t, d.AffectsStationName("utrecht centraal"))
+	assert.False(t, d.AffectsStationName("Utrecht"))
+	assert.False(t, d.AffectsStationName(""))
 }
 
 func TestDisruption_AffectsRoute(t *testing.T) {
@@ -506,3 +616,89 @@ func TestCalculateOverallImpact(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ResolveStation(t *testing.T) {
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: newMockProvider(),
+		Logger:   zerolog.Nop(),
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		input string
+		code  string
+	}{
+		{"ASD", "ASD"},
+		{"asd", "ASD"},
+		{"Amsterdam Centraal", "ASD"},
+		{"utrecht  centraal", "UT"},
+	}
+	for _, tt := range tests {
+		station, err := service.ResolveStation(ctx, tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.code, station.Code, tt.input)
+	}
+
+	_, err := service.ResolveStation(ctx, "Den Bosch")
+	assert.ErrorIs(t, err, transit.ErrStationNotFound)
+
+	station, err := service.GetStation(ctx, "rtd")
+	require.NoError(t, err)
+	assert.Equal(t, "Rotterdam Centraal", station.Name)
+}
+
+func TestService_GetDisruptionsForLeg(t *testing.T) {
+	provider := newMockProvider()
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	// The leg is described by station names; the disruption is reported by code
+	result, err := service.GetDisruptionsForLeg(context.Background(), "Amsterdam Centraal", "Utrecht Centraal", "en")
+	require.NoError(t, err)
+
+	assert.Equal(t, "ASD", result.Origin)
+	assert.Equal(t, "UT", result.Destination)
+	require.True(t, result.HasDisruptions)
+	require.Len(t, result.Disruptions, 1)
+	assert.Equal(t, "d1", result.Disruptions[0].ID)
+	assert.Equal(t, transit.ImpactModerate, result.OverallImpact)
+}
+
+func TestService_GetDisruptionsForLeg_NameOnlyDisruption(t *testing.T) {
+	provider := newMockProvider()
+	// A section reported without a station code, only a name
+	provider.disruptions = append(provider.disruptions, &transit.Disruption{
+		ID:                   "d3",
+		Type:                 transit.DisruptionDisturbance,
+		Title:                "No trains",
+		Impact:               transit.ImpactSevere,
+		AffectedStations:     []string{""},
+		AffectedStationNames: []string{"Utrecht Centraal"},
+		Start:                time.Now().Add(-10 * time.Minute),
+		Provider:             "mock",
+	})
+	service := transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	result, err := service.GetDisruptionsForLeg(context.Background(), "asd", "UT", "nl")
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.Disruptions))
+	for _, d := range result.Disruptions {
+		ids = append(ids, d.ID)
+	}
+	assert.ElementsMatch(t, []string{"d1", "d3"}, ids)
+	assert.Equal(t, transit.ImpactSevere, result.OverallImpact)
+	assert.Equal(t, transit.ImpactSevere, result.Advisory.Severity)
+	assert.Equal(t, "nl", result.Locale)
+	assert.Equal(t, result.Advisory.Text(), result.AdvisoryMessage)
+
+	// The cached route result is not modified
+	again, err := service.GetDisruptionsForRoute(context.Background(), "ASD", "UT")
+	require.NoError(t, err)
+	assert.Len(t, again.Disruptions, 1)
+}
@@ -0,0 +1,50 @@
+package transit
+
+import (
+	"context"
+	"time"
+)
+
+// RecipientChecker is implemented by a ChangeNotifier that can report
+// whether a notification would reach anyone, such as a webhook service with
+// no active webhooks. WatchChanges skips refreshes nobody would hear about.
+type RecipientChecker interface {
+	HasRecipients(ctx context.Context) bool
+}
+
+// WatchChanges refreshes disruptions once per cache TTL until ctx is
+// canceled, so subscribers and the notifier receive changes even when no
+// requests refresh the cache. A refresh is skipped while there are no
+// subscribers and the notifier has no recipients.
+func (s *Service) WatchChanges(ctx context.Context) {
+	ticker := time.NewTicker(s.cacheTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.hasListeners(ctx) {
+				continue
+			}
+			if _, err := s.GetAllDisruptions(ctx); err != nil {
+				s.logger.Warn().Err(err).Msg("failed to refresh disruptions for change notifications")
+			}
+		}
+	}
+}
+
+// hasListeners reports whether a disruption change would reach anyone: an
+// SSE subscriber, or the notifier's recipients. A notifier that cannot tell
+// counts as listening.
+func (s *Service) hasListeners(ctx context.Context) bool {
+	if s.broadcaster.count() > 0 {
+		return true
+	}
+	if s.notifier == nil {
+		return false
+	}
+	checker, ok := s.notifier.(RecipientChecker)
+	return !ok || checker.HasRecipients(ctx)
+}
@@ -0,0 +1,103 @@
+package transit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/transit"
+)
+
+// recipientNotifier is a ChangeNotifier that reports whether it has
+// recipients.
+type recipientNotifier struct {
+	recipients bool
+}
+
+func (n *recipientNotifier) NotifyChanges(context.Context, transit.DisruptionChanges) {}
+
+func (n *recipientNotifier) HasRecipients(context.Context) bool {
+	return n.recipients
+}
+
+func newWatchedService(provider *mockProvider, notifier transit.ChangeNotifier) *transit.Service {
+	return transit.NewService(transit.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: 10 * time.Millisecond,
+		Notifier: notifier,
+	})
+}
+
+func TestService_WatchChanges_PublishesToSubscribers(t *testing.T) {
+	provider := newMockProvider()
+	service := newWatchedService(provider, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := service.GetAllDisruptions(ctx)
+	require.NoError(t, err)
+	changes, unsubscribe := service.SubscribeChanges()
+	defer unsubscribe()
+	go service.WatchChanges(ctx)
+
+	// Without any requests, the watch picks up the resolved disruption
+	provider.mu.Lock()
+	provider.disruptions = provider.disruptions[:1]
+	provider.mu.Unlock()
+
+	select {
+	case got := <-changes:
+		require.Len(t, got.Removed, 1)
+		assert.Equal(t, "d2", got.Removed[0].ID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a change from the watch")
+	}
+}
+
+func TestService_WatchChanges_SkipsWithoutListeners(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier transit.ChangeNotifier
+	}{
+		{"no notifier", nil},
+		{"notifier without recipients", &recipientNotifier{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newMockProvider()
+			service := newWatchedService(provider, tt.notifier)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			_, err := service.GetAllDisruptions(ctx)
+			require.NoError(t, err)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				service.WatchChanges(ctx)
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			<-done
+
+			assert.Equal(t, 1, provider.getCallCount(), "idle watch should not call the provider")
+		})
+	}
+}
+
+func TestService_WatchChanges_RefreshesForRecipients(t *testing.T) {
+	provider := newMockProvider()
+	service := newWatchedService(provider, &recipientNotifier{recipients: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.WatchChanges(ctx)
+
+	assert.Eventually(t, func() bool { return provider.getCallCount() >= 2 }, 2*time.Second, 5*time.Millisecond)
+}
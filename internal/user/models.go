@@ -84,6 +84,11 @@ type Profile struct {
 	// ExposureSensitivity is the user's sensitivity to air quality exposure (LOW, MEDIUM, HIGH).
 	ExposureSensitivity ExposureSensitivity
 
+	// PollenSensitivities are the pollen types or species (e.g. "TREE",
+	// "Birch") the user is allergic to. Empty means the overall pollen risk
+	// applies.
+	PollenSensitivities []string
+
 	// CreatedAt is when the profile was created.
 	CreatedAt time.Time
 
@@ -91,6 +96,15 @@ type Profile struct {
 	UpdatedAt time.Time
 }
 
+// pollenSensitivities returns the profile's sensitivities as a non-nil
+// slice, for the NOT NULL column and an empty JSON array.
+func pollenSensitivities(profile *Profile) []string {
+	if profile.PollenSensitivities == nil {
+		return []string{}
+	}
+	return profile.PollenSensitivities
+}
+
 // ExposureWeights represents the relative importance of pollutant factors.
 // All values should be in the range [0, 1].
 type ExposureWeights struct {
@@ -100,12 +114,36 @@ type ExposureWeights struct {
 	Pollen float64
 }
 
+// total returns the sum of all weights.
+func (w ExposureWeights) total() float64 {
+	return w.NO2 + w.PM25 + w.O3 + w.Pollen
+}
+
+// normalized returns the weights scaled to sum to 1.0.
+// Weights summing to zero are returned unchanged.
+func (w ExposureWeights) normalized() ExposureWeights {
+	total := w.total()
+	if total == 0 {
+		return w
+	}
+	return ExposureWeights{
+		NO2:    w.NO2 / total,
+		PM25:   w.PM25 / total,
+		O3:     w.O3 / total,
+		Pollen: w.Pollen / total,
+	}
+}
+
 // RouteConstraints represents route generation preferences.
 type RouteConstraints struct {
 	AvoidMajorRoads          bool
+	AvoidFerries             bool
+	AvoidUnpaved             bool
+	AvoidCongestionZones     bool
 	PreferParks              *bool
 	MaxExtraMinutesVsFastest *int
 	MaxTransfers             *int
+	EffortWeight             *float64
 }
 
 // Consents represents the user's privacy consent states.
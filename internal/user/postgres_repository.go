@@ -27,8 +27,8 @@ func (r *PostgresRepository) Get(ctx context.Context, id string) (*User, error)
 		SELECT
 			user_id, locale, units,
 			weight_no2, weight_pm25, weight_o3, weight_pollen,
-			avoid_major_roads, prefer_parks, max_extra_minutes_vs_fastest, max_transfers,
-			preferred_mode, exposure_sensitivity,
+			avoid_major_roads, avoid_ferries, avoid_unpaved, avoid_congestion_zones, prefer_parks, max_extra_minutes_vs_fastest, max_transfers, effort_weight,
+			preferred_mode, exposure_sensitivity, pollen_sensitivities,
 			consent_analytics, consent_marketing, consent_push_notifications, consents_updated_at,
 			created_at, updated_at
 		FROM user_profiles
@@ -44,11 +44,16 @@ func (r *PostgresRepository) Get(ctx context.Context, id string) (*User, error)
 		weightO3                 float64
 		weightPollen             float64
 		avoidMajorRoads          bool
+		avoidFerries             bool
+		avoidUnpaved             bool
+		avoidCongestionZones     bool
 		preferParks              *bool
 		maxExtraMinutesVsFastest *int
 		maxTransfers             *int
+		effortWeight             *float64
 		preferredMode            TransportMode
 		exposureSensitivity      ExposureSensitivity
+		pollenSensitivities      []string
 		consentAnalytics         bool
 		consentMarketing         bool
 		consentPushNotifications bool
@@ -66,11 +71,16 @@ func (r *PostgresRepository) Get(ctx context.Context, id string) (*User, error)
 		&weightO3,
 		&weightPollen,
 		&avoidMajorRoads,
+		&avoidFerries,
+		&avoidUnpaved,
+		&avoidCongestionZones,
 		&preferParks,
 		&maxExtraMinutesVsFastest,
 		&maxTransfers,
+		&effortWeight,
 		&preferredMode,
 		&exposureSensitivity,
+		&pollenSensitivities,
 		&consentAnalytics,
 		&consentMarketing,
 		&consentPushNotifications,
@@ -98,12 +108,17 @@ func (r *PostgresRepository) Get(ctx context.Context, id string) (*User, error)
 			},
 			Constraints: RouteConstraints{
 				AvoidMajorRoads:          avoidMajorRoads,
+				AvoidFerries:             avoidFerries,
+				AvoidUnpaved:             avoidUnpaved,
+				AvoidCongestionZones:     avoidCongestionZones,
 				PreferParks:              preferParks,
 				MaxExtraMinutesVsFastest: maxExtraMinutesVsFastest,
 				MaxTransfers:             maxTransfers,
+				EffortWeight:             effortWeight,
 			},
 			PreferredMode:       preferredMode,
 			ExposureSensitivity: exposureSensitivity,
+			PollenSensitivities: pollenSensitivities,
 			CreatedAt:           createdAt,
 			UpdatedAt:           updatedAt,
 		},
@@ -126,11 +141,11 @@ func (r *PostgresRepository) Create(ctx context.Context, user *User) error {
 		INSERT INTO user_profiles (
 			user_id, locale, units,
 			weight_no2, weight_pm25, weight_o3, weight_pollen,
-			avoid_major_roads, prefer_parks, max_extra_minutes_vs_fastest, max_transfers,
-			preferred_mode, exposure_sensitivity,
+			avoid_major_roads, avoid_ferries, avoid_unpaved, avoid_congestion_zones, prefer_parks, max_extra_minutes_vs_fastest, max_transfers, effort_weight,
+			preferred_mode, exposure_sensitivity, pollen_sensitivities,
 			consent_analytics, consent_marketing, consent_push_notifications, consents_updated_at,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	`
 
 	profile := user.Profile
@@ -151,11 +166,16 @@ func (r *PostgresRepository) Create(ctx context.Context, user *User) error {
 		profile.Weights.O3,
 		profile.Weights.Pollen,
 		profile.Constraints.AvoidMajorRoads,
+		profile.Constraints.AvoidFerries,
+		profile.Constraints.AvoidUnpaved,
+		profile.Constraints.AvoidCongestionZones,
 		profile.Constraints.PreferParks,
 		profile.Constraints.MaxExtraMinutesVsFastest,
 		profile.Constraints.MaxTransfers,
+		profile.Constraints.EffortWeight,
 		profile.PreferredMode,
 		profile.ExposureSensitivity,
+		pollenSensitivities(profile),
 		consents.Analytics,
 		consents.Marketing,
 		consents.PushNotifications,
@@ -177,16 +197,21 @@ func (r *PostgresRepository) Update(ctx context.Context, user *User) error {
 			weight_o3 = $6,
 			weight_pollen = $7,
 			avoid_major_roads = $8,
-			prefer_parks = $9,
-			max_extra_minutes_vs_fastest = $10,
-			max_transfers = $11,
-			preferred_mode = $12,
-			exposure_sensitivity = $13,
-			consent_analytics = $14,
-			consent_marketing = $15,
-			consent_push_notifications = $16,
-			consents_updated_at = $17,
-			updated_at = $18
+			avoid_ferries = $9,
+			avoid_unpaved = $10,
+			avoid_congestion_zones = $11,
+			prefer_parks = $12,
+			max_extra_minutes_vs_fastest = $13,
+			max_transfers = $14,
+			effort_weight = $15,
+			preferred_mode = $16,
+			exposure_sensitivity = $17,
+			pollen_sensitivities = $18,
+			consent_analytics = $19,
+			consent_marketing = $20,
+			consent_push_notifications = $21,
+			consents_updated_at = $22,
+			updated_at = $23
 		WHERE user_id = $1
 	`
 
@@ -208,11 +233,16 @@ func (r *PostgresRepository) Update(ctx context.Context, user *User) error {
 		profile.Weights.O3,
 		profile.Weights.Pollen,
 		profile.Constraints.AvoidMajorRoads,
+		profile.Constraints.AvoidFerries,
+		profile.Constraints.AvoidUnpaved,
+		profile.Constraints.AvoidCongestionZones,
 		profile.Constraints.PreferParks,
 		profile.Constraints.MaxExtraMinutesVsFastest,
 		profile.Constraints.MaxTransfers,
+		profile.Constraints.EffortWeight,
 		profile.PreferredMode,
 		profile.ExposureSensitivity,
+		pollenSensitivities(profile),
 		consents.Analytics,
 		consents.Marketing,
 		consents.PushNotifications,
@@ -244,11 +274,11 @@ func (r *PostgresRepository) CreateOrUpdate(ctx context.Context, user *User) err
 		INSERT INTO user_profiles (
 			user_id, locale, units,
 			weight_no2, weight_pm25, weight_o3, weight_pollen,
-			avoid_major_roads, prefer_parks, max_extra_minutes_vs_fastest, max_transfers,
-			preferred_mode, exposure_sensitivity,
+			avoid_major_roads, avoid_ferries, avoid_unpaved, avoid_congestion_zones, prefer_parks, max_extra_minutes_vs_fastest, max_transfers, effort_weight,
+			preferred_mode, exposure_sensitivity, pollen_sensitivities,
 			consent_analytics, consent_marketing, consent_push_notifications, consents_updated_at,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 		ON CONFLICT (user_id) DO UPDATE SET
 			locale = EXCLUDED.locale,
 			units = EXCLUDED.units,
@@ -257,11 +287,16 @@ func (r *PostgresRepository) CreateOrUpdate(ctx context.Context, user *User) err
 			weight_o3 = EXCLUDED.weight_o3,
 			weight_pollen = EXCLUDED.weight_pollen,
 			avoid_major_roads = EXCLUDED.avoid_major_roads,
+			avoid_ferries = EXCLUDED.avoid_ferries,
+			avoid_unpaved = EXCLUDED.avoid_unpaved,
+			avoid_congestion_zones = EXCLUDED.avoid_congestion_zones,
 			prefer_parks = EXCLUDED.prefer_parks,
 			max_extra_minutes_vs_fastest = EXCLUDED.max_extra_minutes_vs_fastest,
 			max_transfers = EXCLUDED.max_transfers,
+			effort_weight = EXCLUDED.effort_weight,
 			preferred_mode = EXCLUDED.preferred_mode,
 			exposure_sensitivity = EXCLUDED.exposure_sensitivity,
+			pollen_sensitivities = EXCLUDED.pollen_sensitivities,
 			consent_analytics = EXCLUDED.consent_analytics,
 			consent_marketing = EXCLUDED.consent_marketing,
 			consent_push_notifications = EXCLUDED.consent_push_notifications,
@@ -287,11 +322,16 @@ func (r *PostgresRepository) CreateOrUpdate(ctx context.Context, user *User) err
 		profile.Weights.O3,
 		profile.Weights.Pollen,
 		profile.Constraints.AvoidMajorRoads,
+		profile.Constraints.AvoidFerries,
+		profile.Constraints.AvoidUnpaved,
+		profile.Constraints.AvoidCongestionZones,
 		profile.Constraints.PreferParks,
 		profile.Constraints.MaxExtraMinutesVsFastest,
 		profile.Constraints.MaxTransfers,
+		profile.Constraints.EffortWeight,
 		profile.PreferredMode,
 		profile.ExposureSensitivity,
+		pollenSensitivities(profile),
 		consents.Analytics,
 		consents.Marketing,
 		consents.PushNotifications,
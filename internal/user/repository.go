@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 )
 
@@ -101,10 +102,13 @@ func copyUser(u *User) *User {
 
 	if u.Profile != nil {
 		userCopy.Profile = &Profile{
-			Weights:     u.Profile.Weights,
-			Constraints: u.Profile.Constraints,
-			CreatedAt:   u.Profile.CreatedAt,
-			UpdatedAt:   u.Profile.UpdatedAt,
+			Weights:             u.Profile.Weights,
+			Constraints:         u.Profile.Constraints,
+			PreferredMode:       u.Profile.PreferredMode,
+			ExposureSensitivity: u.Profile.ExposureSensitivity,
+			PollenSensitivities: slices.Clone(u.Profile.PollenSensitivities),
+			CreatedAt:           u.Profile.CreatedAt,
+			UpdatedAt:           u.Profile.UpdatedAt,
 		}
 		// Copy pointer fields
 		if u.Profile.Constraints.PreferParks != nil {
@@ -119,6 +123,10 @@ func copyUser(u *User) *User {
 			val := *u.Profile.Constraints.MaxTransfers
 			userCopy.Profile.Constraints.MaxTransfers = &val
 		}
+		if u.Profile.Constraints.EffortWeight != nil {
+			val := *u.Profile.Constraints.EffortWeight
+			userCopy.Profile.Constraints.EffortWeight = &val
+		}
 	}
 
 	if u.Consents != nil {
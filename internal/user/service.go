@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/breatheroute/breatheroute/internal/api/models"
@@ -95,18 +96,30 @@ func (s *Service) UpsertProfile(ctx context.Context, userID string, input *model
 		user.Profile.CreatedAt = now
 	}
 
-	// Update profile
-	user.Profile.Weights = ExposureWeights{
+	weights := ExposureWeights{
 		NO2:    input.Weights.NO2,
 		PM25:   input.Weights.PM25,
 		O3:     input.Weights.O3,
 		Pollen: input.Weights.Pollen,
 	}
+	if fieldErrors := validateWeights(weights); len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+	if input.Normalize {
+		weights = weights.normalized()
+	}
+
+	// Update profile
+	user.Profile.Weights = weights
 	user.Profile.Constraints = RouteConstraints{
 		AvoidMajorRoads:          input.Constraints.AvoidMajorRoads,
+		AvoidFerries:             input.Constraints.AvoidFerries,
+		AvoidUnpaved:             input.Constraints.AvoidUnpaved,
+		AvoidCongestionZones:     input.Constraints.AvoidCongestionZones,
 		PreferParks:              input.Constraints.PreferParks,
 		MaxExtraMinutesVsFastest: input.Constraints.MaxExtraMinutesVsFastest,
 		MaxTransfers:             input.Constraints.MaxTransfers,
+		EffortWeight:             input.Constraints.EffortWeight,
 	}
 
 	// Update routing preferences if provided
@@ -116,6 +129,56 @@ func (s *Service) UpsertProfile(ctx context.Context, userID string, input *model
 	if input.ExposureSensitivity != nil {
 		user.Profile.ExposureSensitivity = ExposureSensitivity(*input.ExposureSensitivity)
 	}
+	if input.PollenSensitivities != nil {
+		user.Profile.PollenSensitivities = normalizeSensitivities(input.PollenSensitivities)
+	}
+
+	user.Profile.UpdatedAt = now
+	user.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.toAPIProfile(user.Profile), nil
+}
+
+// PatchProfile applies a partial update to the user's sensitivity profile.
+// Fields omitted from the patch keep their current values.
+func (s *Service) PatchProfile(ctx context.Context, userID string, patch *models.ProfilePatch) (*models.Profile, error) {
+	user, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if user.Profile == nil {
+		user.Profile = DefaultProfile()
+		user.Profile.CreatedAt = now
+	}
+
+	if patch.Weights != nil {
+		applyWeightsPatch(&user.Profile.Weights, patch.Weights)
+		if fieldErrors := validateWeights(user.Profile.Weights); len(fieldErrors) > 0 {
+			return nil, &ValidationError{Errors: fieldErrors}
+		}
+	}
+	if patch.Normalize {
+		user.Profile.Weights = user.Profile.Weights.normalized()
+	}
+	if patch.Constraints != nil {
+		applyConstraintsPatch(&user.Profile.Constraints, patch.Constraints)
+	}
+	if patch.PreferredMode != nil {
+		user.Profile.PreferredMode = TransportMode(*patch.PreferredMode)
+	}
+	if patch.ExposureSensitivity != nil {
+		user.Profile.ExposureSensitivity = ExposureSensitivity(*patch.ExposureSensitivity)
+	}
+	if patch.PollenSensitivities != nil {
+		user.Profile.PollenSensitivities = normalizeSensitivities(patch.PollenSensitivities)
+	}
 
 	user.Profile.UpdatedAt = now
 	user.UpdatedAt = now
@@ -127,6 +190,99 @@ func (s *Service) UpsertProfile(ctx context.Context, userID string, input *model
 	return s.toAPIProfile(user.Profile), nil
 }
 
+// normalizeSensitivities trims pollen sensitivities and drops blanks and
+// case-insensitive duplicates, keeping the first spelling.
+func normalizeSensitivities(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, value)
+	}
+	return normalized
+}
+
+// applyWeightsPatch overwrites the weights present in the patch.
+func applyWeightsPatch(weights *ExposureWeights, patch *models.ExposureWeightsPatch) {
+	if patch.NO2 != nil {
+		weights.NO2 = *patch.NO2
+	}
+	if patch.PM25 != nil {
+		weights.PM25 = *patch.PM25
+	}
+	if patch.O3 != nil {
+		weights.O3 = *patch.O3
+	}
+	if patch.Pollen != nil {
+		weights.Pollen = *patch.Pollen
+	}
+}
+
+// applyConstraintsPatch overwrites the constraints present in the patch.
+func applyConstraintsPatch(constraints *RouteConstraints, patch *models.RouteConstraintsPatch) {
+	if patch.AvoidMajorRoads != nil {
+		constraints.AvoidMajorRoads = *patch.AvoidMajorRoads
+	}
+	if patch.AvoidFerries != nil {
+		constraints.AvoidFerries = *patch.AvoidFerries
+	}
+	if patch.AvoidUnpaved != nil {
+		constraints.AvoidUnpaved = *patch.AvoidUnpaved
+	}
+	if patch.AvoidCongestionZones != nil {
+		constraints.AvoidCongestionZones = *patch.AvoidCongestionZones
+	}
+	if patch.PreferParks != nil {
+		constraints.PreferParks = patch.PreferParks
+	}
+	if patch.MaxExtraMinutesVsFastest != nil {
+		constraints.MaxExtraMinutesVsFastest = patch.MaxExtraMinutesVsFastest
+	}
+	if patch.MaxTransfers != nil {
+		constraints.MaxTransfers = patch.MaxTransfers
+	}
+	if patch.EffortWeight != nil {
+		constraints.EffortWeight = patch.EffortWeight
+	}
+}
+
+// validateWeights checks that no exposure weight is negative and that at
+// least one is positive, so scoring always has something to rank by.
+func validateWeights(weights ExposureWeights) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	for _, w := range []struct {
+		field string
+		value float64
+	}{
+		{"weights.no2", weights.NO2},
+		{"weights.pm25", weights.PM25},
+		{"weights.o3", weights.O3},
+		{"weights.pollen", weights.Pollen},
+	} {
+		if w.value < 0 {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   w.field,
+				Message: "must not be negative",
+			})
+		}
+	}
+
+	if len(fieldErrors) == 0 && weights.total() == 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{
+			Field:   "weights",
+			Message: "at least one weight must be greater than zero",
+		})
+	}
+
+	return fieldErrors
+}
+
 // GetConsents retrieves the user's consent states.
 func (s *Service) GetConsents(ctx context.Context, userID string) (*models.Consents, error) {
 	user, err := s.repo.Get(ctx, userID)
@@ -225,13 +381,27 @@ func (s *Service) toAPIProfile(p *Profile) *models.Profile {
 		},
 		Constraints: models.RouteConstraints{
 			AvoidMajorRoads:          p.Constraints.AvoidMajorRoads,
+			AvoidFerries:             p.Constraints.AvoidFerries,
+			AvoidUnpaved:             p.Constraints.AvoidUnpaved,
+			AvoidCongestionZones:     p.Constraints.AvoidCongestionZones,
 			PreferParks:              p.Constraints.PreferParks,
 			MaxExtraMinutesVsFastest: p.Constraints.MaxExtraMinutesVsFastest,
 			MaxTransfers:             p.Constraints.MaxTransfers,
+			EffortWeight:             p.Constraints.EffortWeight,
 		},
 		PreferredMode:       models.TransportMode(p.PreferredMode),
 		ExposureSensitivity: models.ExposureSensitivity(p.ExposureSensitivity),
+		PollenSensitivities: pollenSensitivities(p),
 		CreatedAt:           models.Timestamp(p.CreatedAt),
 		UpdatedAt:           models.Timestamp(p.UpdatedAt),
 	}
 }
+
+// ValidationError represents profile validation errors.
+type ValidationError struct {
+	Errors []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed"
+}
@@ -0,0 +1,140 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/user"
+)
+
+func newTestService(t *testing.T) *user.Service {
+	t.Helper()
+	svc := user.NewService(user.NewInMemoryRepository())
+	_, err := svc.CreateUser(context.Background(), "usr_test", "en-GB")
+	require.NoError(t, err)
+	return svc
+}
+
+func TestService_UpsertProfile_WeightValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		weights     models.ExposureWeights
+		wantFields  []string
+		wantSuccess bool
+	}{
+		{
+			name:       "all zero",
+			weights:    models.ExposureWeights{},
+			wantFields: []string{"weights"},
+		},
+		{
+			name:       "negative weights",
+			weights:    models.ExposureWeights{NO2: -0.2, PM25: 0.5, O3: 0.3, Pollen: -1},
+			wantFields: []string{"weights.no2", "weights.pollen"},
+		},
+		{
+			name:        "valid",
+			weights:     models.ExposureWeights{NO2: 0.5, PM25: 0.3, O3: 0.1, Pollen: 0.1},
+			wantSuccess: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+
+			_, err := svc.UpsertProfile(context.Background(), "usr_test", &models.ProfileInput{Weights: tt.weights})
+			if tt.wantSuccess {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *user.ValidationError
+			require.True(t, errors.As(err, &validationErr))
+
+			fields := make([]string, 0, len(validationErr.Errors))
+			for _, fe := range validationErr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestService_UpsertProfile_Normalize(t *testing.T) {
+	svc := newTestService(t)
+
+	input := &models.ProfileInput{
+		Weights:   models.ExposureWeights{NO2: 1, PM25: 1, O3: 0.5, Pollen: 0.5},
+		Normalize: true,
+	}
+	profile, err := svc.UpsertProfile(context.Background(), "usr_test", input)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.333, profile.Weights.NO2, 0.001)
+	assert.InDelta(t, 0.333, profile.Weights.PM25, 0.001)
+	assert.InDelta(t, 0.167, profile.Weights.O3, 0.001)
+	assert.InDelta(t, 0.167, profile.Weights.Pollen, 0.001)
+
+	sum := profile.Weights.NO2 + profile.Weights.PM25 + profile.Weights.O3 + profile.Weights.Pollen
+	assert.InDelta(t, 1.0, sum, 1e-9)
+}
+
+func TestService_UpsertProfile_WithoutNormalizeKeepsWeights(t *testing.T) {
+	svc := newTestService(t)
+
+	input := &models.ProfileInput{
+		Weights: models.ExposureWeights{NO2: 1, PM25: 1, O3: 0.5, Pollen: 0.5},
+	}
+	profile, err := svc.UpsertProfile(context.Background(), "usr_test", input)
+	require.NoError(t, err)
+
+	assert.Equal(t, input.Weights, profile.Weights)
+}
+
+func TestService_PatchProfile_NormalizeExistingWeights(t *testing.T) {
+	svc := newTestService(t)
+
+	pollen := 1.1
+	profile, err := svc.PatchProfile(context.Background(), "usr_test", &models.ProfilePatch{
+		Weights:   &models.ExposureWeightsPatch{Pollen: &pollen},
+		Normalize: true,
+	})
+	require.NoError(t, err)
+
+	// Defaults are 0.4/0.3/0.2 plus the patched pollen weight of 1.1, summing to 2.0
+	assert.InDelta(t, 0.2, profile.Weights.NO2, 1e-9)
+	assert.InDelta(t, 0.55, profile.Weights.Pollen, 1e-9)
+}
+
+func TestService_PollenSensitivities(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	profile, err := svc.GetProfile(ctx, "usr_test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, profile.PollenSensitivities)
+
+	profile, err = svc.PatchProfile(ctx, "usr_test", &models.ProfilePatch{
+		PollenSensitivities: []string{" Birch", "birch", "GRASS", ""},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Birch", "GRASS"}, profile.PollenSensitivities)
+
+	// Patches without the field keep the list; an empty list clears it
+	noTransfers := 0
+	profile, err = svc.PatchProfile(ctx, "usr_test", &models.ProfilePatch{
+		Constraints: &models.RouteConstraintsPatch{MaxTransfers: &noTransfers},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Birch", "GRASS"}, profile.PollenSensitivities)
+
+	profile, err = svc.PatchProfile(ctx, "usr_test", &models.ProfilePatch{PollenSensitivities: []string{}})
+	require.NoError(t, err)
+	assert.Empty(t, profile.PollenSensitivities)
+}
@@ -90,7 +90,12 @@ func (o *Observation) GetWindCategory() WindCategory {
 // DispersionFactor returns a multiplier (0.5-1.5) indicating how wind affects
 // air quality dispersion. Lower values mean pollutants disperse faster.
 func (o *Observation) DispersionFactor() float64 {
-	switch o.GetWindCategory() {
+	return dispersionFactor(o.GetWindCategory())
+}
+
+// dispersionFactor returns the dispersion multiplier for a wind category.
+func dispersionFactor(category WindCategory) float64 {
+	switch category {
 	case WindCalm:
 		return 1.3 // Pollutants accumulate - worse AQ
 	case WindLight:
@@ -164,3 +169,26 @@ func (h *HourlyForecast) GetWindCategory() WindCategory {
 		return WindStrong
 	}
 }
+
+// DispersionFactor returns a multiplier (0.5-1.5) indicating how the
+// forecast wind affects air quality dispersion, as for an Observation.
+func (h *HourlyForecast) DispersionFactor() float64 {
+	return dispersionFactor(h.GetWindCategory())
+}
+
+// NearestHour returns the forecast hour closest to t, or nil if t is more
+// than an hour outside the forecast.
+func (f *Forecast) NearestHour(t time.Time) *HourlyForecast {
+	var nearest *HourlyForecast
+	var best time.Duration
+	for i := range f.Hourly {
+		d := f.Hourly[i].Time.Sub(t).Abs()
+		if nearest == nil || d < best {
+			nearest, best = &f.Hourly[i], d
+		}
+	}
+	if nearest == nil || best > time.Hour {
+		return nil
+	}
+	return nearest
+}
@@ -2,6 +2,7 @@ package weather_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -75,6 +76,20 @@ func TestHourlyForecast_GetWindCategory(t *testing.T) {
 	}
 }
 
+func TestForecast_NearestHour(t *testing.T) {
+	start := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	forecast := &weather.Forecast{Hourly: []weather.HourlyForecast{
+		{Time: start, WindSpeed: 2},
+		{Time: start.Add(time.Hour), WindSpeed: 4},
+	}}
+
+	assert.Equal(t, 2.0, forecast.NearestHour(start.Add(20*time.Minute)).WindSpeed)
+	assert.Equal(t, 4.0, forecast.NearestHour(start.Add(40*time.Minute)).WindSpeed)
+	assert.Equal(t, 4.0, forecast.NearestHour(start.Add(2*time.Hour)).WindSpeed)
+	assert.Nil(t, forecast.NearestHour(start.Add(3*time.Hour)), "beyond the forecast")
+	assert.Nil(t, (&weather.Forecast{}).NearestHour(start))
+}
+
 func TestBoundingBox_Contains(t *testing.T) {
 	box := weather.BoundingBox{
 		MinLat: 52.0,
@@ -39,6 +39,10 @@ type ClientConfig struct {
 	// If nil, uses a resilient client with defaults.
 	HTTPClient *resilience.Client
 
+	// Registry is the provider registry the default client registers with
+	// for health tracking (optional).
+	Registry *resilience.Registry
+
 	// Logger for client operations.
 	Logger zerolog.Logger
 }
@@ -66,7 +70,9 @@ func NewClient(cfg ClientConfig) *Client {
 
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
-		httpClient = resilience.NewClient(resilience.DefaultClientConfig("openweathermap"))
+		clientCfg := resilience.DefaultClientConfig("openweathermap")
+		clientCfg.Registry = cfg.Registry
+		httpClient = resilience.NewClient(clientCfg)
 	}
 
 	return &Client{
@@ -100,7 +106,7 @@ func (c *Client) GetCurrentWeather(ctx context.Context, lat, lon float64) (*weat
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &resilience.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var owmResp currentWeatherResponse
@@ -128,7 +134,7 @@ func (c *Client) GetForecast(ctx context.Context, lat, lon float64) (*weather.Fo
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &resilience.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var owmResp oneCallResponse
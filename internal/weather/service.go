@@ -2,12 +2,27 @@ package weather
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+	"github.com/breatheroute/breatheroute/internal/telemetry"
+)
+
+// Safe ranges for feature flag cache overrides. Grid sizes below 0.01° would
+// collide in cache keys, which are formatted to two decimals.
+const (
+	minCacheTTLSeconds = 60
+	maxCacheTTLSeconds = 6 * 3600
+	minCacheGridSize   = 0.01
+	maxCacheGridSize   = 1.0
 )
 
 // Provider defines the interface for weather data providers.
@@ -40,6 +55,20 @@ type ServiceConfig struct {
 
 	// StaleIfErrorTTL allows serving stale data on provider errors (default: 1 hour).
 	StaleIfErrorTTL time.Duration
+
+	// StaleWhileRevalidate serves expired weather and forecasts still within
+	// StaleIfErrorTTL at once and refreshes them in the background, instead
+	// of making the caller wait for the provider.
+	StaleWhileRevalidate bool
+
+	// MaxCacheEntries bounds each weather and forecast cache (default: 5000).
+	// Expired entries are evicted first, then the least recently used.
+	MaxCacheEntries int
+
+	// FeatureFlags is the feature flag service (optional).
+	// If provided, FlagWeatherCacheTTLSeconds and FlagWeatherCacheGridSize
+	// override CacheTTL and CacheGridSize without a redeploy.
+	FeatureFlags *featureflags.Service
 }
 
 // Service provides weather data with caching.
@@ -49,10 +78,25 @@ type Service struct {
 	cacheTTL        time.Duration
 	cacheGridSize   float64
 	staleIfErrorTTL time.Duration
+	revalidate      bool
+
+	// Live cache tuning via feature flags; zero values mean no override
+	ttlOverride  *featureflags.NumberOverride
+	gridOverride *featureflags.NumberOverride
+	ttlInEffect  atomic.Int64  // nanoseconds
+	gridInEffect atomic.Uint64 // math.Float64bits
+
+	// Cache lookup counters, updated atomically outside mu
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+
+	// Concurrent fetches of the same cache key share one provider call
+	weatherFetches  cache.Coalescer[*Observation]
+	forecastFetches cache.Coalescer[*Forecast]
 
 	mu              sync.RWMutex
-	weatherCache    map[string]*cachedObservation
-	forecastCache   map[string]*cachedForecast
+	weatherCache    *cache.LRU[string, *cachedObservation]
+	forecastCache   *cache.LRU[string, *cachedForecast]
 	lastCleanup     time.Time
 	cleanupInterval time.Duration
 }
@@ -86,14 +130,24 @@ func NewService(cfg ServiceConfig) *Service {
 		staleIfErrorTTL = 1 * time.Hour
 	}
 
+	maxCacheEntries := cfg.MaxCacheEntries
+	if maxCacheEntries == 0 {
+		maxCacheEntries = 5000
+	}
+
 	return &Service{
 		provider:        cfg.Provider,
 		logger:          cfg.Logger,
 		cacheTTL:        cacheTTL,
 		cacheGridSize:   cacheGridSize,
 		staleIfErrorTTL: staleIfErrorTTL,
-		weatherCache:    make(map[string]*cachedObservation),
-		forecastCache:   make(map[string]*cachedForecast),
+		revalidate:      cfg.StaleWhileRevalidate,
+		ttlOverride: cfg.FeatureFlags.NumberOverride(
+			featureflags.FlagWeatherCacheTTLSeconds, minCacheTTLSeconds, maxCacheTTLSeconds),
+		gridOverride: cfg.FeatureFlags.NumberOverride(
+			featureflags.FlagWeatherCacheGridSize, minCacheGridSize, maxCacheGridSize),
+		weatherCache:    cache.NewLRU[string, *cachedObservation](maxCacheEntries),
+		forecastCache:   cache.NewLRU[string, *cachedForecast](maxCacheEntries),
 		cleanupInterval: 5 * time.Minute,
 	}
 }
@@ -105,15 +159,24 @@ func (s *Service) GetCurrentWeather(ctx context.Context, lat, lon float64) (*Obs
 		return nil, err
 	}
 
+	s.applyOverrides(ctx)
 	cacheKey := s.cacheKey(lat, lon)
 
 	// Check cache
 	s.mu.RLock()
-	if cached, ok := s.weatherCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
-		s.mu.RUnlock()
+	cached, ok := s.weatherCache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
+		return cached.observation, nil
+	}
+	if ok && s.serveStale(cached.fetchedAt) {
+		s.cacheHits.Add(1)
+		s.weatherFetches.Go(ctx, cacheKey, func(ctx context.Context) (*Observation, error) {
+			return s.fetchWeatherAndCache(ctx, lat, lon, cacheKey)
+		})
 		return cached.observation, nil
 	}
-	s.mu.RUnlock()
 
 	// Fetch from provider
 	return s.fetchWeather(ctx, lat, lon, cacheKey)
@@ -125,15 +188,24 @@ func (s *Service) GetForecast(ctx context.Context, lat, lon float64) (*Forecast,
 		return nil, err
 	}
 
+	s.applyOverrides(ctx)
 	cacheKey := s.cacheKey(lat, lon)
 
 	// Check cache
 	s.mu.RLock()
-	if cached, ok := s.forecastCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
-		s.mu.RUnlock()
+	cached, ok := s.forecastCache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
+		return cached.forecast, nil
+	}
+	if ok && s.serveStale(cached.fetchedAt) {
+		s.cacheHits.Add(1)
+		s.forecastFetches.Go(ctx, cacheKey, func(ctx context.Context) (*Forecast, error) {
+			return s.fetchForecastAndCache(ctx, lat, lon, cacheKey)
+		})
 		return cached.forecast, nil
 	}
-	s.mu.RUnlock()
 
 	// Fetch from provider
 	return s.fetchForecast(ctx, lat, lon, cacheKey)
@@ -147,7 +219,7 @@ func (s *Service) GetWeatherForPoints(ctx context.Context, points []struct{ Lat,
 	for i, p := range points {
 		obs, err := s.GetCurrentWeather(ctx, p.Lat, p.Lon)
 		if err != nil {
-			s.logger.Warn().
+			telemetry.Logger(ctx, s.logger).Warn().
 				Float64("lat", p.Lat).
 				Float64("lon", p.Lon).
 				Err(err).
@@ -161,6 +233,26 @@ func (s *Service) GetWeatherForPoints(ctx context.Context, points []struct{ Lat,
 	return results, nil
 }
 
+// WarmCache pre-fetches current weather and forecasts for the given points so
+// the first requests after startup are served from cache. Failed points are
+// skipped and reported in the returned error.
+func (s *Service) WarmCache(ctx context.Context, points []struct{ Lat, Lon float64 }) error {
+	var errs []error
+	for _, p := range points {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := s.GetCurrentWeather(ctx, p.Lat, p.Lon); err != nil {
+			errs = append(errs, fmt.Errorf("weather at %.4f,%.4f: %w", p.Lat, p.Lon, err))
+			continue
+		}
+		if _, err := s.GetForecast(ctx, p.Lat, p.Lon); err != nil {
+			errs = append(errs, fmt.Errorf("forecast at %.4f,%.4f: %w", p.Lat, p.Lon, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // GetWeatherForBoundingBox returns weather for a bounding box.
 // Samples the center point of the box for simplicity.
 func (s *Service) GetWeatherForBoundingBox(ctx context.Context, box BoundingBox) (*Observation, error) {
@@ -168,17 +260,35 @@ func (s *Service) GetWeatherForBoundingBox(ctx context.Context, box BoundingBox)
 	return s.GetCurrentWeather(ctx, centerLat, centerLon)
 }
 
-// fetchWeather fetches weather from provider and updates cache.
+// serveStale reports whether an expired entry fetched at fetchedAt may be
+// served while it is refreshed in the background.
+func (s *Service) serveStale(fetchedAt time.Time) bool {
+	return s.revalidate && time.Now().Before(fetchedAt.Add(s.staleIfErrorTTL))
+}
+
+// fetchWeather fetches weather from provider and updates cache. Concurrent
+// misses on the same key share a single provider call.
 func (s *Service) fetchWeather(ctx context.Context, lat, lon float64, cacheKey string) (*Observation, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	obs, _, err := s.weatherFetches.Do(ctx, cacheKey, func(ctx context.Context) (*Observation, error) {
+		return s.fetchWeatherAndCache(ctx, lat, lon, cacheKey)
+	})
+	return obs, err
+}
 
-	// Double-check cache
-	if cached, ok := s.weatherCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
+// fetchWeatherAndCache fetches the weather from the provider, unless a
+// fetch that just finished has cached it, and stores it in the cache.
+func (s *Service) fetchWeatherAndCache(ctx context.Context, lat, lon float64, cacheKey string) (*Observation, error) {
+	// Double-check cache: a previous fetch may have completed meanwhile
+	s.mu.RLock()
+	cached, ok := s.weatherCache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
 		return cached.observation, nil
 	}
+	s.cacheMisses.Add(1)
 
-	s.logger.Debug().
+	telemetry.Logger(ctx, s.logger).Debug().
 		Float64("lat", lat).
 		Float64("lon", lon).
 		Str("provider", s.provider.Name()).
@@ -186,49 +296,60 @@ func (s *Service) fetchWeather(ctx context.Context, lat, lon float64, cacheKey s
 
 	obs, err := s.provider.GetCurrentWeather(ctx, lat, lon)
 	if err != nil {
-		s.logger.Error().Err(err).
+		telemetry.Logger(ctx, s.logger).Error().Err(err).
 			Float64("lat", lat).
 			Float64("lon", lon).
 			Msg("failed to fetch weather")
 
 		// Check for stale data
-		if cached, ok := s.weatherCache[cacheKey]; ok {
-			if time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-				s.logger.Warn().
-					Time("fetched_at", cached.fetchedAt).
-					Msg("serving stale weather data due to provider error")
-				return cached.observation, nil
-			}
+		if ok && time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
+			telemetry.Logger(ctx, s.logger).Warn().
+				Time("fetched_at", cached.fetchedAt).
+				Msg("serving stale weather data due to provider error")
+			return cached.observation, nil
 		}
 
-		return nil, ErrProviderUnavailable
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
 	}
 
 	// Update cache
 	now := time.Now()
-	s.weatherCache[cacheKey] = &cachedObservation{
+	entry := &cachedObservation{
 		observation: obs,
 		fetchedAt:   now,
-		expiresAt:   now.Add(s.cacheTTL),
+		expiresAt:   now.Add(s.currentCacheTTL()),
 	}
-
-	// Periodic cleanup
+	s.mu.Lock()
+	s.weatherCache.Set(cacheKey, entry, entry.expiresAt)
 	s.cleanupIfNeeded()
+	s.mu.Unlock()
 
 	return obs, nil
 }
 
-// fetchForecast fetches forecast from provider and updates cache.
+// fetchForecast fetches forecast from provider and updates cache. Concurrent
+// misses on the same key share a single provider call.
 func (s *Service) fetchForecast(ctx context.Context, lat, lon float64, cacheKey string) (*Forecast, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	forecast, _, err := s.forecastFetches.Do(ctx, cacheKey, func(ctx context.Context) (*Forecast, error) {
+		return s.fetchForecastAndCache(ctx, lat, lon, cacheKey)
+	})
+	return forecast, err
+}
 
-	// Double-check cache
-	if cached, ok := s.forecastCache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
+// fetchForecastAndCache fetches the forecast from the provider, unless a
+// fetch that just finished has cached it, and stores it in the cache.
+func (s *Service) fetchForecastAndCache(ctx context.Context, lat, lon float64, cacheKey string) (*Forecast, error) {
+	// Double-check cache: a previous fetch may have completed meanwhile
+	s.mu.RLock()
+	cached, ok := s.forecastCache.Get(cacheKey)
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		s.cacheHits.Add(1)
 		return cached.forecast, nil
 	}
+	s.cacheMisses.Add(1)
 
-	s.logger.Debug().
+	telemetry.Logger(ctx, s.logger).Debug().
 		Float64("lat", lat).
 		Float64("lon", lon).
 		Str("provider", s.provider.Name()).
@@ -236,34 +357,33 @@ func (s *Service) fetchForecast(ctx context.Context, lat, lon float64, cacheKey
 
 	forecast, err := s.provider.GetForecast(ctx, lat, lon)
 	if err != nil {
-		s.logger.Error().Err(err).
+		telemetry.Logger(ctx, s.logger).Error().Err(err).
 			Float64("lat", lat).
 			Float64("lon", lon).
 			Msg("failed to fetch forecast")
 
 		// Check for stale data
-		if cached, ok := s.forecastCache[cacheKey]; ok {
-			if time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-				s.logger.Warn().
-					Time("fetched_at", cached.fetchedAt).
-					Msg("serving stale forecast data due to provider error")
-				return cached.forecast, nil
-			}
+		if ok && time.Now().Before(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
+			telemetry.Logger(ctx, s.logger).Warn().
+				Time("fetched_at", cached.fetchedAt).
+				Msg("serving stale forecast data due to provider error")
+			return cached.forecast, nil
 		}
 
-		return nil, ErrProviderUnavailable
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
 	}
 
 	// Update cache
 	now := time.Now()
-	s.forecastCache[cacheKey] = &cachedForecast{
+	entry := &cachedForecast{
 		forecast:  forecast,
 		fetchedAt: now,
-		expiresAt: now.Add(s.cacheTTL),
+		expiresAt: now.Add(s.currentCacheTTL()),
 	}
-
-	// Periodic cleanup
+	s.mu.Lock()
+	s.forecastCache.Set(cacheKey, entry, entry.expiresAt)
 	s.cleanupIfNeeded()
+	s.mu.Unlock()
 
 	return forecast, nil
 }
@@ -272,8 +392,9 @@ func (s *Service) fetchForecast(ctx context.Context, lat, lon float64, cacheKey
 // Groups nearby points into grid cells to reduce API calls.
 func (s *Service) cacheKey(lat, lon float64) string {
 	// Round to grid cell
-	gridLat := math.Floor(lat/s.cacheGridSize) * s.cacheGridSize
-	gridLon := math.Floor(lon/s.cacheGridSize) * s.cacheGridSize
+	gridSize := s.currentGridSize()
+	gridLat := math.Floor(lat/gridSize) * gridSize
+	gridLon := math.Floor(lon/gridSize) * gridSize
 	return fmt.Sprintf("%.2f:%.2f", gridLat, gridLon)
 }
 
@@ -287,19 +408,13 @@ func (s *Service) cleanupIfNeeded() {
 	s.lastCleanup = now
 	expired := 0
 
-	for key, cached := range s.weatherCache {
-		if now.After(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-			delete(s.weatherCache, key)
-			expired++
-		}
-	}
+	expired += s.weatherCache.DeleteFunc(func(_ string, cached *cachedObservation) bool {
+		return now.After(cached.fetchedAt.Add(s.staleIfErrorTTL))
+	})
 
-	for key, cached := range s.forecastCache {
-		if now.After(cached.fetchedAt.Add(s.staleIfErrorTTL)) {
-			delete(s.forecastCache, key)
-			expired++
-		}
-	}
+	expired += s.forecastCache.DeleteFunc(func(_ string, cached *cachedForecast) bool {
+		return now.After(cached.fetchedAt.Add(s.staleIfErrorTTL))
+	})
 
 	if expired > 0 {
 		s.logger.Debug().
@@ -312,8 +427,8 @@ func (s *Service) cleanupIfNeeded() {
 func (s *Service) InvalidateCache() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.weatherCache = make(map[string]*cachedObservation)
-	s.forecastCache = make(map[string]*cachedForecast)
+	s.weatherCache.Clear()
+	s.forecastCache.Clear()
 }
 
 // CacheStats returns cache statistics.
@@ -322,27 +437,29 @@ func (s *Service) CacheStats() CacheStats {
 	defer s.mu.RUnlock()
 
 	now := time.Now()
-	weatherFresh := 0
-	forecastFresh := 0
+	stats := CacheStats{
+		WeatherEntries:  s.weatherCache.Len(),
+		ForecastEntries: s.forecastCache.Len(),
+		Provider:        s.provider.Name(),
+		Hits:            s.cacheHits.Load(),
+		Misses:          s.cacheMisses.Load(),
+		Evictions:       s.weatherCache.Evictions() + s.forecastCache.Evictions(),
+	}
 
-	for _, c := range s.weatherCache {
-		if now.Before(c.expiresAt) {
-			weatherFresh++
+	s.weatherCache.Range(func(_ string, c *cachedObservation) bool {
+		if stats.observe(now, c.fetchedAt, c.expiresAt) {
+			stats.WeatherFreshEntries++
 		}
-	}
-	for _, c := range s.forecastCache {
-		if now.Before(c.expiresAt) {
-			forecastFresh++
+		return true
+	})
+	s.forecastCache.Range(func(_ string, c *cachedForecast) bool {
+		if stats.observe(now, c.fetchedAt, c.expiresAt) {
+			stats.ForecastFreshEntries++
 		}
-	}
+		return true
+	})
 
-	return CacheStats{
-		WeatherEntries:       len(s.weatherCache),
-		WeatherFreshEntries:  weatherFresh,
-		ForecastEntries:      len(s.forecastCache),
-		ForecastFreshEntries: forecastFresh,
-		Provider:             s.provider.Name(),
-	}
+	return stats
 }
 
 // CacheStats contains cache statistics.
@@ -352,6 +469,76 @@ type CacheStats struct {
 	ForecastEntries      int
 	ForecastFreshEntries int
 	Provider             string
+
+	// OldestFreshAt is when the oldest unexpired entry was fetched, and
+	// NewestFetchedAt when the newest entry was. Both are zero if there is
+	// no such entry.
+	OldestFreshAt   time.Time
+	NewestFetchedAt time.Time
+
+	// Hits and Misses count cache lookups since the service started.
+	Hits   uint64
+	Misses uint64
+
+	// Evictions counts entries dropped to stay within MaxCacheEntries.
+	Evictions uint64
+}
+
+// observe adds an entry's fetch time to the stats, reporting whether the
+// entry is still fresh.
+func (c *CacheStats) observe(now, fetchedAt, expiresAt time.Time) bool {
+	if fetchedAt.After(c.NewestFetchedAt) {
+		c.NewestFetchedAt = fetchedAt
+	}
+	if !now.Before(expiresAt) {
+		return false
+	}
+	if c.OldestFreshAt.IsZero() || fetchedAt.Before(c.OldestFreshAt) {
+		c.OldestFreshAt = fetchedAt
+	}
+	return true
+}
+
+// HitRatio returns the fraction of cache lookups served from the cache,
+// or 0 if there have been none.
+func (c CacheStats) HitRatio() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
+}
+
+// applyOverrides refreshes the cache TTL and grid size in effect from
+// feature flags. Call it before taking s.mu: it may query the flag store.
+func (s *Service) applyOverrides(ctx context.Context) {
+	if seconds, ok := s.ttlOverride.Value(ctx); ok {
+		s.ttlInEffect.Store(int64(seconds * float64(time.Second)))
+	} else {
+		s.ttlInEffect.Store(0)
+	}
+
+	if gridSize, ok := s.gridOverride.Value(ctx); ok {
+		s.gridInEffect.Store(math.Float64bits(gridSize))
+	} else {
+		s.gridInEffect.Store(0)
+	}
+}
+
+// currentCacheTTL returns the overridden cache TTL, or the constructed one.
+func (s *Service) currentCacheTTL() time.Duration {
+	if ttl := s.ttlInEffect.Load(); ttl > 0 {
+		return time.Duration(ttl)
+	}
+	return s.cacheTTL
+}
+
+// currentGridSize returns the overridden cache grid size, or the constructed one.
+func (s *Service) currentGridSize() float64 {
+	if bits := s.gridInEffect.Load(); bits != 0 {
+		return math.Float64frombits(bits)
+	}
+	return s.cacheGridSize
 }
 
 // validateCoordinates checks if coordinates are valid.
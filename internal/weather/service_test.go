@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/breatheroute/breatheroute/internal/featureflags"
 	"github.com/breatheroute/breatheroute/internal/weather"
 )
 
@@ -21,6 +22,7 @@ type mockProvider struct {
 	observations map[string]*weather.Observation
 	forecasts    map[string]*weather.Forecast
 	err          error
+	delay        time.Duration // simulates a slow provider
 }
 
 func newMockProvider() *mockProvider {
@@ -35,6 +37,7 @@ func (m *mockProvider) Name() string {
 }
 
 func (m *mockProvider) GetCurrentWeather(_ context.Context, lat, lon float64) (*weather.Observation, error) {
+	time.Sleep(m.delay)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.callCount++
@@ -174,6 +177,46 @@ func TestService_GetCurrentWeather_CacheGriding(t *testing.T) {
 	assert.Equal(t, 2, provider.getCallCount())
 }
 
+func TestService_GetCurrentWeather_GridSizeOverride(t *testing.T) {
+	ffRepo := featureflags.NewInMemoryRepository()
+	ffService := featureflags.NewService(featureflags.ServiceConfig{
+		Repository: ffRepo,
+		Logger:     zerolog.Nop(),
+		CacheTTL:   time.Nanosecond, // Re-read flags on every request
+	})
+
+	provider := newMockProvider()
+	service := weather.NewService(weather.ServiceConfig{
+		Provider:      provider,
+		Logger:        zerolog.Nop(),
+		CacheTTL:      5 * time.Minute,
+		CacheGridSize: 0.1,
+		FeatureFlags:  ffService,
+	})
+
+	// Below the safe minimum, so clamped to 0.01
+	require.NoError(t, ffRepo.SetFlag(context.Background(), &featureflags.Flag{
+		Key:   featureflags.FlagWeatherCacheGridSize,
+		Value: 0.0001,
+	}))
+
+	// Same 0.1 cell, but different 0.01 cells
+	_, err := service.GetCurrentWeather(context.Background(), 52.371, 4.891)
+	require.NoError(t, err)
+	_, err = service.GetCurrentWeather(context.Background(), 52.389, 4.891)
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.getCallCount())
+
+	// Removing the flag restores the constructed grid size
+	require.NoError(t, ffRepo.DeleteFlag(context.Background(), featureflags.FlagWeatherCacheGridSize))
+
+	_, err = service.GetCurrentWeather(context.Background(), 52.371, 4.891)
+	require.NoError(t, err)
+	_, err = service.GetCurrentWeather(context.Background(), 52.389, 4.891)
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.getCallCount())
+}
+
 func TestService_GetCurrentWeather_InvalidCoordinates(t *testing.T) {
 	provider := newMockProvider()
 	service := weather.NewService(weather.ServiceConfig{
@@ -241,6 +284,68 @@ func TestService_GetCurrentWeather_StaleOnError(t *testing.T) {
 	require.NotNil(t, obs2)
 }
 
+func TestService_GetCurrentWeather_StaleWhileRevalidate(t *testing.T) {
+	provider := newMockProvider()
+	service := weather.NewService(weather.ServiceConfig{
+		Provider:             provider,
+		Logger:               zerolog.Nop(),
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Hour,
+		StaleWhileRevalidate: true,
+	})
+
+	obs1, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+
+	time.Sleep(70 * time.Millisecond)
+	provider.delay = 100 * time.Millisecond
+	provider.mu.Lock()
+	provider.observations[cacheKey(52.370, 4.895)] = &weather.Observation{Temperature: 25.0, FetchedAt: time.Now()}
+	provider.mu.Unlock()
+
+	// Expired entries are served at once while one refresh runs
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		obs, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+		require.NoError(t, err)
+		assert.Same(t, obs1, obs)
+	}
+	assert.Less(t, time.Since(start), provider.delay)
+
+	assert.Eventually(t, func() bool {
+		obs, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+		return err == nil && obs.Temperature == 25.0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, provider.getCallCount())
+}
+
+func TestService_GetCurrentWeather_FailedRevalidationKeepsStale(t *testing.T) {
+	provider := newMockProvider()
+	service := weather.NewService(weather.ServiceConfig{
+		Provider:             provider,
+		Logger:               zerolog.Nop(),
+		CacheTTL:             50 * time.Millisecond,
+		StaleIfErrorTTL:      time.Hour,
+		StaleWhileRevalidate: true,
+	})
+
+	obs1, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+
+	time.Sleep(70 * time.Millisecond)
+	provider.setError(errors.New("api error"))
+
+	obs, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+	assert.Same(t, obs1, obs)
+	assert.Eventually(t, func() bool { return provider.getCallCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	// The failed refresh left the stale entry in place
+	obs, err = service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+	assert.Same(t, obs1, obs)
+}
+
 func TestService_GetForecast(t *testing.T) {
 	provider := newMockProvider()
 	service := weather.NewService(weather.ServiceConfig{
@@ -303,6 +408,29 @@ func TestService_GetWeatherForBoundingBox(t *testing.T) {
 	assert.InDelta(t, centerLon, obs.Lon, 0.1)
 }
 
+func TestService_GetCurrentWeather_ConcurrentRequests(t *testing.T) {
+	provider := newMockProvider()
+	provider.delay = 50 * time.Millisecond
+	service := weather.NewService(weather.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Concurrent misses on the same key share a single provider call
+	assert.Equal(t, 1, provider.getCallCount())
+}
+
 func TestService_InvalidateCache(t *testing.T) {
 	provider := newMockProvider()
 	service := weather.NewService(weather.ServiceConfig{
@@ -348,3 +476,82 @@ func TestService_CacheStats(t *testing.T) {
 	assert.Equal(t, 1, stats.WeatherFreshEntries)
 	assert.Equal(t, 1, stats.ForecastFreshEntries)
 }
+
+func TestService_CacheStats_HitsAndMisses(t *testing.T) {
+	provider := newMockProvider()
+	service := weather.NewService(weather.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+		CacheTTL: 5 * time.Minute,
+	})
+
+	// The second point falls in the same grid cell as the first
+	_, err := service.GetCurrentWeather(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+	_, err = service.GetCurrentWeather(context.Background(), 52.371, 4.896)
+	require.NoError(t, err)
+	_, err = service.GetForecast(context.Background(), 52.370, 4.895)
+	require.NoError(t, err)
+
+	stats := service.CacheStats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.InDelta(t, 1.0/3.0, stats.HitRatio(), 0.0001)
+}
+
+func TestService_CacheStats_Evictions(t *testing.T) {
+	provider := newMockProvider()
+	service := weather.NewService(weather.ServiceConfig{
+		Provider:        provider,
+		Logger:          zerolog.Nop(),
+		MaxCacheEntries: 2,
+	})
+
+	// Three distinct grid cells overflow a two-entry cache
+	for _, lat := range []float64{51.0, 52.0, 53.0} {
+		_, err := service.GetCurrentWeather(context.Background(), lat, 4.9)
+		require.NoError(t, err)
+	}
+
+	stats := service.CacheStats()
+	assert.Equal(t, 2, stats.WeatherEntries)
+	assert.Equal(t, uint64(1), stats.Evictions)
+}
+
+func TestService_WarmCache(t *testing.T) {
+	provider := newMockProvider()
+	service := weather.NewService(weather.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	points := []struct{ Lat, Lon float64 }{
+		{52.3676, 4.9041},
+		{51.9244, 4.4777},
+	}
+
+	err := service.WarmCache(context.Background(), points)
+	require.NoError(t, err)
+
+	// Current weather and forecast for each point
+	assert.Equal(t, 4, provider.getCallCount())
+
+	// Subsequent requests are served from cache
+	_, err = service.GetCurrentWeather(context.Background(), 52.3676, 4.9041)
+	require.NoError(t, err)
+	_, err = service.GetForecast(context.Background(), 51.9244, 4.4777)
+	require.NoError(t, err)
+	assert.Equal(t, 4, provider.getCallCount())
+}
+
+func TestService_WarmCache_ReportsFailures(t *testing.T) {
+	provider := newMockProvider()
+	provider.setError(errors.New("provider down"))
+	service := weather.NewService(weather.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	err := service.WarmCache(context.Background(), []struct{ Lat, Lon float64 }{{52.3676, 4.9041}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/breatheroute/breatheroute/internal/transit"
+)
+
+// Delivery headers.
+const (
+	HeaderSignature = "X-BreatheRoute-Signature"
+	HeaderEventID   = "X-BreatheRoute-Event-Id"
+	HeaderEventType = "X-BreatheRoute-Event"
+)
+
+// EventDisruptionsChanged is the event type for transit disruption changes.
+const EventDisruptionsChanged = "transit.disruptions.changed"
+
+// maxErrorLength caps the stored last-error message.
+const maxErrorLength = 500
+
+// errPermanent marks delivery errors that retrying will not fix.
+var errPermanent = errors.New("permanent delivery failure")
+
+// Event is the JSON body POSTed to webhook endpoints.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      EventData `json:"data"`
+}
+
+// EventData lists the disruptions that changed since the previous refresh.
+type EventData struct {
+	Added   []EventDisruption `json:"added"`
+	Removed []EventDisruption `json:"removed"`
+	Changed []EventDisruption `json:"changed"`
+}
+
+// EventDisruption is a disruption as published to webhooks.
+type EventDisruption struct {
+	ID                      string     `json:"id"`
+	Type                    string     `json:"type"`
+	Title                   string     `json:"title"`
+	Impact                  string     `json:"impact"`
+	AffectedStations        []string   `json:"affectedStations"`
+	IsPlanned               bool       `json:"isPlanned"`
+	ExpectedDurationMinutes int        `json:"expectedDurationMinutes,omitempty"`
+	AlternativeTransport    string     `json:"alternativeTransport,omitempty"`
+	Start                   *time.Time `json:"start,omitempty"`
+	End                     *time.Time `json:"end,omitempty"`
+}
+
+// Ensure Service implements the transit.ChangeNotifier and
+// transit.RecipientChecker interfaces.
+var (
+	_ transit.ChangeNotifier   = (*Service)(nil)
+	_ transit.RecipientChecker = (*Service)(nil)
+)
+
+// HasRecipients reports whether any webhook is active, so idle disruption
+// watches skip the provider call. Errors listing webhooks count as active.
+func (s *Service) HasRecipients(ctx context.Context) bool {
+	webhooks, err := s.repo.ListActive(ctx)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("failed to list webhooks, assuming some are active")
+		return true
+	}
+	return len(webhooks) > 0
+}
+
+// NotifyChanges delivers a disruption change event to every active webhook.
+// It blocks until all deliveries, including retries, have finished.
+func (s *Service) NotifyChanges(ctx context.Context, changes transit.DisruptionChanges) {
+	webhooks, err := s.repo.ListActive(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list webhooks for disruption changes")
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	event := newEvent(changes)
+	body, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode webhook event")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		wg.Add(1)
+		go func(webhook *Webhook) {
+			defer wg.Done()
+			s.deliver(ctx, webhook, event, body)
+		}(webhook)
+	}
+	wg.Wait()
+}
+
+// deliver sends an event with retries and records the outcome.
+func (s *Service) deliver(ctx context.Context, webhook *Webhook, event *Event, body []byte) {
+	var err error
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		err = s.send(ctx, webhook, event, body)
+		if err == nil || errors.Is(err, errPermanent) {
+			break
+		}
+		if attempt == s.maxAttempts {
+			break
+		}
+
+		wait := s.initialBackoff << (attempt - 1)
+		s.logger.Debug().
+			Err(err).
+			Str("webhook_id", webhook.ID).
+			Int("attempt", attempt).
+			Dur("retry_in", wait).
+			Msg("webhook delivery failed, retrying")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.recordResult(ctx, webhook, ctx.Err())
+			return
+		case <-timer.C:
+		}
+	}
+
+	s.recordResult(ctx, webhook, err)
+}
+
+// send performs a single signed delivery attempt.
+func (s *Service) send(ctx context.Context, webhook *Webhook, event *Event, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: creating request: %v", errPermanent, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, event.ID)
+	req.Header.Set(HeaderEventType, event.Type)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, time.Now().Unix(), body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return fmt.Errorf("%w: %w", errPermanent, err)
+		}
+		return fmt.Errorf("executing request: %w", err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: unexpected status code: %d", errPermanent, resp.StatusCode)
+	}
+}
+
+// recordResult updates the webhook's delivery status, disabling it after
+// too many consecutive failed events.
+func (s *Service) recordResult(ctx context.Context, webhook *Webhook, deliveryErr error) {
+	now := time.Now()
+	webhook.LastDeliveryAt = &now
+
+	if deliveryErr == nil {
+		webhook.ConsecutiveFailures = 0
+		webhook.LastError = nil
+	} else {
+		webhook.ConsecutiveFailures++
+		message := deliveryErr.Error()
+		if len(message) > maxErrorLength {
+			message = message[:maxErrorLength]
+		}
+		webhook.LastError = &message
+
+		if webhook.ConsecutiveFailures >= s.maxConsecutiveFailures {
+			webhook.Active = false
+			webhook.DisabledAt = &now
+			s.logger.Warn().
+				Str("webhook_id", webhook.ID).
+				Int("consecutive_failures", webhook.ConsecutiveFailures).
+				Msg("webhook disabled after repeated delivery failures")
+		} else {
+			s.logger.Warn().
+				Err(deliveryErr).
+				Str("webhook_id", webhook.ID).
+				Msg("webhook delivery failed")
+		}
+	}
+
+	// Use a fresh context so the status is saved even if delivery was canceled
+	if err := s.repo.UpdateStatus(context.WithoutCancel(ctx), webhook); err != nil {
+		s.logger.Error().Err(err).Str("webhook_id", webhook.ID).Msg("failed to update webhook status")
+	}
+}
+
+// Sign computes the signature header value for a delivery body.
+// Receivers verify it by recomputing HMAC-SHA256 over "<timestamp>.<body>"
+// with their secret and comparing it to v1.
+func Sign(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEvent builds the webhook event for a set of changes.
+func newEvent(changes transit.DisruptionChanges) *Event {
+	createdAt := changes.DetectedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	return &Event{
+		ID:        "evt_" + uuid.New().String()[:22],
+		Type:      EventDisruptionsChanged,
+		CreatedAt: createdAt.UTC(),
+		Data: EventData{
+			Added:   toEventDisruptions(changes.Added),
+			Removed: toEventDisruptions(changes.Removed),
+			Changed: toEventDisruptions(changes.Changed),
+		},
+	}
+}
+
+// toEventDisruptions converts disruptions to their event representation.
+func toEventDisruptions(disruptions []*transit.Disruption) []EventDisruption {
+	result := make([]EventDisruption, 0, len(disruptions))
+	for _, d := range disruptions {
+		event := EventDisruption{
+			ID:                      d.ID,
+			Type:                    string(d.Type),
+			Title:                   d.Title,
+			Impact:                  string(d.Impact),
+			AffectedStations:        d.AffectedStations,
+			IsPlanned:               d.IsPlanned,
+			ExpectedDurationMinutes: d.ExpectedDuration,
+			AlternativeTransport:    d.AlternativeTransport,
+		}
+		if !d.Start.IsZero() {
+			start := d.Start
+			event.Start = &start
+		}
+		if !d.End.IsZero() {
+			end := d.End
+			event.End = &end
+		}
+		result = append(result, event)
+	}
+	return result
+}
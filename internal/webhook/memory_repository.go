@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryRepository is an in-memory implementation of Repository.
+// This is intended for testing. Production should use the PostgreSQL implementation.
+type InMemoryRepository struct {
+	mu       sync.RWMutex
+	webhooks map[string]*Webhook // keyed by webhook ID
+}
+
+// NewInMemoryRepository creates a new in-memory webhook repository.
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		webhooks: make(map[string]*Webhook),
+	}
+}
+
+// Get retrieves a webhook by user ID and webhook ID.
+func (r *InMemoryRepository) Get(_ context.Context, userID, webhookID string) (*Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return nil, ErrWebhookNotFound
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+// ListByUser retrieves all webhooks for a user, oldest first.
+func (r *InMemoryRepository) ListByUser(_ context.Context, userID string) ([]*Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var items []*Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.UserID == userID {
+			items = append(items, copyWebhook(webhook))
+		}
+	}
+	sortByCreated(items)
+
+	return items, nil
+}
+
+// ListActive retrieves all active webhooks across users.
+func (r *InMemoryRepository) ListActive(_ context.Context) ([]*Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var items []*Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.Active {
+			items = append(items, copyWebhook(webhook))
+		}
+	}
+	sortByCreated(items)
+
+	return items, nil
+}
+
+// Create creates a new webhook.
+func (r *InMemoryRepository) Create(_ context.Context, webhook *Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// UpdateStatus persists the delivery status fields.
+func (r *InMemoryRepository) UpdateStatus(_ context.Context, webhook *Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.webhooks[webhook.ID]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+
+	existing.Active = webhook.Active
+	existing.ConsecutiveFailures = webhook.ConsecutiveFailures
+	existing.LastDeliveryAt = copyTime(webhook.LastDeliveryAt)
+	existing.LastError = copyString(webhook.LastError)
+	existing.DisabledAt = copyTime(webhook.DisabledAt)
+	existing.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete deletes a webhook.
+func (r *InMemoryRepository) Delete(_ context.Context, userID, webhookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return ErrWebhookNotFound
+	}
+
+	delete(r.webhooks, webhookID)
+	return nil
+}
+
+// copyWebhook creates a deep copy of a webhook.
+func copyWebhook(w *Webhook) *Webhook {
+	c := *w
+	c.LastDeliveryAt = copyTime(w.LastDeliveryAt)
+	c.LastError = copyString(w.LastError)
+	c.DisabledAt = copyTime(w.DisabledAt)
+	return &c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// sortByCreated orders webhooks by creation time, then ID.
+func sortByCreated(items []*Webhook) {
+	sort.Slice(items, func(a, b int) bool {
+		if !items[a].CreatedAt.Equal(items[b].CreatedAt) {
+			return items[a].CreatedAt.Before(items[b].CreatedAt)
+		}
+		return items[a].ID < items[b].ID
+	})
+}
@@ -0,0 +1,39 @@
+// Package webhook provides webhook subscriptions that push transit disruption
+// changes to integrators.
+package webhook
+
+import (
+	"errors"
+	"time"
+)
+
+// Webhook errors.
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidURL      = errors.New("webhook URL must be an absolute https URL")
+	ErrLimitReached    = errors.New("webhook limit reached")
+	ErrPrivateAddress  = errors.New("webhook URL must not point to a private or internal address")
+)
+
+// Webhook is a registered endpoint that receives disruption change events.
+type Webhook struct {
+	ID     string
+	UserID string
+	URL    string
+
+	// Secret is the HMAC-SHA256 signing key shared with the receiver.
+	Secret string
+
+	// Active is false once the endpoint has been disabled after repeated failures.
+	Active bool
+
+	// ConsecutiveFailures counts deliveries that failed after all retries.
+	// It is reset by a successful delivery.
+	ConsecutiveFailures int
+
+	LastDeliveryAt *time.Time
+	LastError      *string
+	DisabledAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository is a PostgreSQL implementation of Repository.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgreSQL webhook repository.
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+const webhookColumns = `id, user_id, url, secret, active, consecutive_failures,
+	last_delivery_at, last_error, disabled_at, created_at, updated_at`
+
+// Get retrieves a webhook by user ID and webhook ID.
+func (r *PostgresRepository) Get(ctx context.Context, userID, webhookID string) (*Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`
+
+	webhook, err := scanWebhook(r.pool.QueryRow(ctx, query, webhookID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// ListByUser retrieves all webhooks for a user, oldest first.
+func (r *PostgresRepository) ListByUser(ctx context.Context, userID string) ([]*Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+	return r.list(ctx, query, userID)
+}
+
+// ListActive retrieves all active webhooks across users.
+func (r *PostgresRepository) ListActive(ctx context.Context) ([]*Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE active
+		ORDER BY created_at, id
+	`
+
+	return r.list(ctx, query)
+}
+
+// list runs a query returning webhook rows.
+func (r *PostgresRepository) list(ctx context.Context, query string, args ...interface{}) ([]*Webhook, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// scanWebhook scans a single webhook row.
+func scanWebhook(row pgx.Row) (*Webhook, error) {
+	var webhook Webhook
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.ConsecutiveFailures,
+		&webhook.LastDeliveryAt,
+		&webhook.LastError,
+		&webhook.DisabledAt,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Create creates a new webhook.
+func (r *PostgresRepository) Create(ctx context.Context, webhook *Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, active, consecutive_failures, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Active,
+		webhook.ConsecutiveFailures,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	)
+	return err
+}
+
+// UpdateStatus persists the delivery status fields.
+func (r *PostgresRepository) UpdateStatus(ctx context.Context, webhook *Webhook) error {
+	query := `
+		UPDATE webhooks SET
+			active = $2,
+			consecutive_failures = $3,
+			last_delivery_at = $4,
+			last_error = $5,
+			disabled_at = $6,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		webhook.ID,
+		webhook.Active,
+		webhook.ConsecutiveFailures,
+		webhook.LastDeliveryAt,
+		webhook.LastError,
+		webhook.DisabledAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook.
+func (r *PostgresRepository) Delete(ctx context.Context, userID, webhookID string) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, webhookID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package webhook
+
+import "context"
+
+// Repository defines the interface for webhook persistence.
+type Repository interface {
+	// Get retrieves a webhook by user ID and webhook ID.
+	Get(ctx context.Context, userID, webhookID string) (*Webhook, error)
+
+	// ListByUser retrieves all webhooks for a user.
+	ListByUser(ctx context.Context, userID string) ([]*Webhook, error)
+
+	// ListActive retrieves all active webhooks across users.
+	ListActive(ctx context.Context) ([]*Webhook, error)
+
+	// Create creates a new webhook.
+	Create(ctx context.Context, webhook *Webhook) error
+
+	// UpdateStatus persists the delivery status fields (Active,
+	// ConsecutiveFailures, LastDeliveryAt, LastError, DisabledAt).
+	UpdateStatus(ctx context.Context, webhook *Webhook) error
+
+	// Delete deletes a webhook.
+	Delete(ctx context.Context, userID, webhookID string) error
+}
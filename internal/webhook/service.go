@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+)
+
+// ServiceConfig holds configuration for the webhook service.
+type ServiceConfig struct {
+	// Repository stores webhook registrations (required).
+	Repository Repository
+
+	// Logger for service operations.
+	Logger zerolog.Logger
+
+	// HTTPClient is used for deliveries (default: 10 second timeout, no
+	// redirects, and no connections to private or internal addresses).
+	// A custom client is used as is, without those protections.
+	HTTPClient *http.Client
+
+	// MaxAttempts is the number of delivery attempts per event (default: 4).
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry; it doubles for
+	// each subsequent retry (default: 1 second).
+	InitialBackoff time.Duration
+
+	// MaxConsecutiveFailures disables an endpoint after this many events in a
+	// row could not be delivered (default: 5).
+	MaxConsecutiveFailures int
+
+	// MaxWebhooksPerUser limits registrations per user (default: 5).
+	MaxWebhooksPerUser int
+
+	// AllowInsecureURLs permits http:// endpoints. Only for local development.
+	AllowInsecureURLs bool
+
+	// AllowPrivateAddresses permits endpoints on loopback, private,
+	// link-local and metadata addresses. Only for local development.
+	AllowPrivateAddresses bool
+}
+
+// Service manages webhook registrations and delivers change events.
+type Service struct {
+	repo                   Repository
+	logger                 zerolog.Logger
+	httpClient             *http.Client
+	maxAttempts            int
+	initialBackoff         time.Duration
+	maxConsecutiveFailures int
+	maxWebhooksPerUser     int
+	allowInsecureURLs      bool
+	allowPrivateAddresses  bool
+}
+
+// NewService creates a new webhook service.
+func NewService(cfg ServiceConfig) *Service {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = newDeliveryClient(cfg.AllowPrivateAddresses)
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 4
+	}
+
+	initialBackoff := cfg.InitialBackoff
+	if initialBackoff == 0 {
+		initialBackoff = time.Second
+	}
+
+	maxConsecutiveFailures := cfg.MaxConsecutiveFailures
+	if maxConsecutiveFailures == 0 {
+		maxConsecutiveFailures = 5
+	}
+
+	maxWebhooksPerUser := cfg.MaxWebhooksPerUser
+	if maxWebhooksPerUser == 0 {
+		maxWebhooksPerUser = 5
+	}
+
+	return &Service{
+		repo:                   cfg.Repository,
+		logger:                 cfg.Logger,
+		httpClient:             httpClient,
+		maxAttempts:            maxAttempts,
+		initialBackoff:         initialBackoff,
+		maxConsecutiveFailures: maxConsecutiveFailures,
+		maxWebhooksPerUser:     maxWebhooksPerUser,
+		allowInsecureURLs:      cfg.AllowInsecureURLs,
+		allowPrivateAddresses:  cfg.AllowPrivateAddresses,
+	}
+}
+
+// Register registers a new webhook endpoint for a user.
+// The returned secret is used to verify delivery signatures and is not shown again.
+func (s *Service) Register(ctx context.Context, userID string, input *models.WebhookCreateRequest) (*models.WebhookCreated, error) {
+	if err := s.validateURL(input.URL); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= s.maxWebhooksPerUser {
+		return nil, ErrLimitReached
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	webhook := &Webhook{
+		ID:        "whk_" + uuid.New().String()[:22],
+		UserID:    userID,
+		URL:       input.URL,
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookCreated{
+		Webhook: toAPIWebhook(webhook),
+		Secret:  secret,
+	}, nil
+}
+
+// List retrieves all webhooks for a user.
+func (s *Service) List(ctx context.Context, userID string) (*models.WebhookList, error) {
+	webhooks, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		items = append(items, toAPIWebhook(w))
+	}
+
+	return &models.WebhookList{Items: items}, nil
+}
+
+// Delete removes a user's webhook.
+func (s *Service) Delete(ctx context.Context, userID, webhookID string) error {
+	return s.repo.Delete(ctx, userID, webhookID)
+}
+
+// validateURL checks the endpoint is an absolute https (or permitted http) URL
+// whose host is not a private or internal address.
+func (s *Service) validateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return ErrInvalidURL
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !s.allowInsecureURLs {
+			return ErrInvalidURL
+		}
+	default:
+		return ErrInvalidURL
+	}
+
+	if s.allowPrivateAddresses {
+		return nil
+	}
+	return checkHost(parsed.Hostname())
+}
+
+// generateSecret creates a random signing secret.
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// toAPIWebhook converts a domain webhook to an API model.
+func toAPIWebhook(w *Webhook) models.Webhook {
+	result := models.Webhook{
+		ID:                  w.ID,
+		URL:                 w.URL,
+		Active:              w.Active,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		LastError:           w.LastError,
+		CreatedAt:           models.Timestamp(w.CreatedAt),
+	}
+
+	if w.LastDeliveryAt != nil {
+		ts := models.Timestamp(*w.LastDeliveryAt)
+		result.LastDeliveryAt = &ts
+	}
+	if w.DisabledAt != nil {
+		ts := models.Timestamp(*w.DisabledAt)
+		result.DisabledAt = &ts
+	}
+
+	return result
+}
@@ -0,0 +1,298 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/api/models"
+	"github.com/breatheroute/breatheroute/internal/transit"
+	"github.com/breatheroute/breatheroute/internal/webhook"
+)
+
+const testUserID = "usr_testuser123"
+
+func newTestService(repo webhook.Repository) *webhook.Service {
+	return webhook.NewService(webhook.ServiceConfig{
+		Repository:             repo,
+		Logger:                 zerolog.Nop(),
+		MaxAttempts:            3,
+		InitialBackoff:         time.Millisecond,
+		MaxConsecutiveFailures: 2,
+		AllowInsecureURLs:      true, // httptest servers are plain http
+		AllowPrivateAddresses:  true, // and listen on loopback
+	})
+}
+
+func testChanges() transit.DisruptionChanges {
+	return transit.DisruptionChanges{
+		Added: []*transit.Disruption{
+			{ID: "d1", Type: transit.DisruptionDisturbance, Title: "Signal failure", Impact: transit.ImpactMajor, AffectedStations: []string{"ASD"}},
+		},
+		DetectedAt: time.Now(),
+	}
+}
+
+func TestService_Register(t *testing.T) {
+	service := newTestService(webhook.NewInMemoryRepository())
+
+	created, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: "https://example.com/hook"})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(created.ID, "whk_"))
+	assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+	assert.True(t, created.Active)
+
+	list, err := service.List(context.Background(), testUserID)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "https://example.com/hook", list.Items[0].URL)
+}
+
+func TestService_HasRecipients(t *testing.T) {
+	service := newTestService(webhook.NewInMemoryRepository())
+	ctx := context.Background()
+
+	assert.False(t, service.HasRecipients(ctx))
+
+	_, err := service.Register(ctx, testUserID, &models.WebhookCreateRequest{URL: "https://example.com/hook"})
+	require.NoError(t, err)
+	assert.True(t, service.HasRecipients(ctx))
+}
+
+func TestService_Register_InvalidURL(t *testing.T) {
+	service := webhook.NewService(webhook.ServiceConfig{
+		Repository: webhook.NewInMemoryRepository(),
+		Logger:     zerolog.Nop(),
+	})
+
+	for _, url := range []string{"not a url", "/relative", "http://example.com/hook", "ftp://example.com"} {
+		_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: url})
+		assert.ErrorIs(t, err, webhook.ErrInvalidURL, url)
+	}
+}
+
+func TestService_NotifyChanges_SignedDelivery(t *testing.T) {
+	repo := webhook.NewInMemoryRepository()
+	service := newTestService(repo)
+
+	var (
+		gotBody      []byte
+		gotSignature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(webhook.HeaderSignature)
+		assert.Equal(t, webhook.EventDisruptionsChanged, r.Header.Get(webhook.HeaderEventType))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	created, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: server.URL})
+	require.NoError(t, err)
+
+	service.NotifyChanges(context.Background(), testChanges())
+
+	// Verify the signature the way a receiver would
+	parts := strings.Split(gotSignature, ",")
+	require.Len(t, parts, 2)
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, hmac.Equal([]byte(gotSignature), []byte(webhook.Sign(created.Secret, timestamp, gotBody))))
+
+	var event webhook.Event
+	require.NoError(t, json.Unmarshal(gotBody, &event))
+	assert.Equal(t, webhook.EventDisruptionsChanged, event.Type)
+	require.Len(t, event.Data.Added, 1)
+	assert.Equal(t, "d1", event.Data.Added[0].ID)
+	assert.Empty(t, event.Data.Removed)
+
+	stored, err := repo.Get(context.Background(), testUserID, created.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastDeliveryAt)
+	assert.Zero(t, stored.ConsecutiveFailures)
+}
+
+func TestService_NotifyChanges_RetriesTransientFailures(t *testing.T) {
+	service := newTestService(webhook.NewInMemoryRepository())
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: server.URL})
+	require.NoError(t, err)
+
+	service.NotifyChanges(context.Background(), testChanges())
+
+	assert.Equal(t, int32(3), calls.Load())
+	list, err := service.List(context.Background(), testUserID)
+	require.NoError(t, err)
+	assert.Zero(t, list.Items[0].ConsecutiveFailures)
+	assert.Nil(t, list.Items[0].LastError)
+}
+
+func TestService_NotifyChanges_DoesNotRetryClientErrors(t *testing.T) {
+	service := newTestService(webhook.NewInMemoryRepository())
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: server.URL})
+	require.NoError(t, err)
+
+	service.NotifyChanges(context.Background(), testChanges())
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestService_NotifyChanges_DisablesAfterRepeatedFailures(t *testing.T) {
+	service := newTestService(webhook.NewInMemoryRepository())
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: server.URL})
+	require.NoError(t, err)
+
+	service.NotifyChanges(context.Background(), testChanges())
+	list, err := service.List(context.Background(), testUserID)
+	require.NoError(t, err)
+	assert.True(t, list.Items[0].Active)
+	assert.Equal(t, 1, list.Items[0].ConsecutiveFailures)
+	require.NotNil(t, list.Items[0].LastError)
+	assert.Contains(t, *list.Items[0].LastError, "500")
+
+	service.NotifyChanges(context.Background(), testChanges())
+	list, err = service.List(context.Background(), testUserID)
+	require.NoError(t, err)
+	assert.False(t, list.Items[0].Active)
+	assert.NotNil(t, list.Items[0].DisabledAt)
+
+	// Disabled endpoints receive no further deliveries
+	before := calls.Load()
+	service.NotifyChanges(context.Background(), testChanges())
+	assert.Equal(t, before, calls.Load())
+}
+
+func TestService_Register_PrivateAddress(t *testing.T) {
+	service := webhook.NewService(webhook.ServiceConfig{
+		Repository: webhook.NewInMemoryRepository(),
+		Logger:     zerolog.Nop(),
+	})
+
+	urls := []string{
+		"https://127.0.0.1/hook",             // Loopback
+		"https://127.8.9.10:8443/hook",       // Loopback
+		"https://[::1]/hook",                 // IPv6 loopback
+		"https://localhost/hook",             // Loopback name
+		"https://api.localhost/hook",         // Loopback name
+		"https://10.1.2.3/hook",              // RFC 1918
+		"https://172.16.0.1/hook",            // RFC 1918
+		"https://172.31.255.254/hook",        // RFC 1918
+		"https://192.168.1.1/hook",           // RFC 1918
+		"https://169.254.169.254/latest",     // Link-local cloud metadata
+		"https://169.254.1.1/hook",           // Link-local
+		"https://[fe80::1]/hook",             // IPv6 link-local
+		"https://[fd00:ec2::254]/latest",     // IPv6 unique local metadata
+		"https://[::ffff:127.0.0.1]/hook",    // IPv4-mapped loopback
+		"https://[::ffff:169.254.169.254]/x", // IPv4-mapped metadata
+		"https://100.64.0.1/hook",            // Carrier-grade NAT
+		"https://0.0.0.0/hook",               // Unspecified
+	}
+	for _, url := range urls {
+		_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: url})
+		assert.ErrorIs(t, err, webhook.ErrPrivateAddress, url)
+	}
+
+	_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: "https://93.184.216.34/hook"})
+	assert.NoError(t, err, "public addresses are allowed")
+}
+
+func TestService_NotifyChanges_RefusesPrivateAddressAtDial(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// A name that resolves to loopback passes registration, as DNS can
+	// change after it, but is refused when dialing
+	repo := webhook.NewInMemoryRepository()
+	service := webhook.NewService(webhook.ServiceConfig{
+		Repository:        repo,
+		Logger:            zerolog.Nop(),
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		AllowInsecureURLs: true,
+	})
+	hook := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	require.NoError(t, repo.Create(context.Background(), &webhook.Webhook{
+		ID: "whk_rebind", UserID: testUserID, URL: hook, Secret: "whsec_test", Active: true,
+	}))
+
+	service.NotifyChanges(context.Background(), testChanges())
+
+	assert.Zero(t, calls.Load())
+	stored, err := repo.Get(context.Background(), testUserID, "whk_rebind")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.ConsecutiveFailures)
+	require.NotNil(t, stored.LastError)
+	assert.Contains(t, *stored.LastError, "private or internal address")
+}
+
+func TestService_NotifyChanges_DoesNotFollowRedirects(t *testing.T) {
+	var internalCalls atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		internalCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer internal.Close()
+
+	var calls atomic.Int32
+	redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirecting.Close()
+
+	service := newTestService(webhook.NewInMemoryRepository())
+	_, err := service.Register(context.Background(), testUserID, &models.WebhookCreateRequest{URL: redirecting.URL})
+	require.NoError(t, err)
+
+	service.NotifyChanges(context.Background(), testChanges())
+
+	assert.Equal(t, int32(1), calls.Load(), "redirects are permanent failures")
+	assert.Zero(t, internalCalls.Load())
+	list, err := service.List(context.Background(), testUserID)
+	require.NoError(t, err)
+	require.NotNil(t, list.Items[0].LastError)
+	assert.Contains(t, *list.Items[0].LastError, "307")
+}
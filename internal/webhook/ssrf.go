@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// blockedPrefixes are address ranges deliveries must never reach: internal
+// networks and cloud metadata endpoints.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This" network
+	netip.MustParsePrefix("10.0.0.0/8"),     // RFC 1918
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),    // Loopback
+	netip.MustParsePrefix("169.254.0.0/16"), // Link-local, including 169.254.169.254 metadata
+	netip.MustParsePrefix("172.16.0.0/12"),  // RFC 1918
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("192.168.0.0/16"), // RFC 1918
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+	netip.MustParsePrefix("224.0.0.0/4"),    // Multicast
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved and broadcast
+	netip.MustParsePrefix("::/128"),         // Unspecified
+	netip.MustParsePrefix("::1/128"),        // Loopback
+	netip.MustParsePrefix("fc00::/7"),       // Unique local, including fd00:ec2::254 metadata
+	netip.MustParsePrefix("fe80::/10"),      // Link-local
+	netip.MustParsePrefix("ff00::/8"),       // Multicast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, can embed any IPv4 address
+}
+
+// isBlockedAddr reports whether deliveries to addr are refused.
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHost rejects URL hosts that are blocked addresses or localhost names,
+// so obvious internal endpoints fail at registration. Names that resolve to
+// blocked addresses are caught when dialing.
+func checkHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && isBlockedAddr(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// dialControl refuses connections to blocked addresses. It runs after DNS
+// resolution for every connection, so names that resolve or rebind to an
+// internal address are refused too.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, address)
+	}
+	if isBlockedAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// newDeliveryClient creates the HTTP client used for deliveries. Unless
+// allowPrivate is set, it refuses to connect to blocked addresses. It never
+// follows redirects, so a public endpoint cannot redirect a delivery to an
+// internal one; a redirect response counts as a failed delivery.
+func newDeliveryClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivate {
+		dialer.Control = dialControl
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would dial internal addresses on our behalf
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
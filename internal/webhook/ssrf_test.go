@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsBlockedAddr(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1", "127.255.255.254", "::1", // Loopback
+		"10.0.0.1", "172.16.0.1", "172.31.255.255", "192.168.0.1", // RFC 1918
+		"169.254.169.254", "169.254.0.1", "fe80::1", // Link-local and metadata
+		"fd00:ec2::254", "fc00::1", // Unique local
+		"100.64.0.1", "0.0.0.0", "::", "224.0.0.1", "255.255.255.255", "ff02::1",
+		"::ffff:10.0.0.1", "64:ff9b::a9fe:a9fe",
+	}
+	for _, s := range blocked {
+		if !isBlockedAddr(netip.MustParseAddr(s)) {
+			t.Errorf("expected %s to be blocked", s)
+		}
+	}
+
+	allowed := []string{"93.184.216.34", "8.8.8.8", "172.32.0.1", "192.169.0.1", "2606:4700::1111"}
+	for _, s := range allowed {
+		if isBlockedAddr(netip.MustParseAddr(s)) {
+			t.Errorf("expected %s to be allowed", s)
+		}
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	if err := dialControl("tcp4", "169.254.169.254:80", nil); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("expected metadata address to be refused, got %v", err)
+	}
+	if err := dialControl("tcp6", "[fe80::1]:443", nil); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("expected link-local address to be refused, got %v", err)
+	}
+	if err := dialControl("tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("expected public address to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package worker
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/commute"
+)
+
+// CommuteTargetName is the name of the refresh target derived from commutes.
+const CommuteTargetName = "Commutes"
+
+// Commute point defaults.
+const (
+	// DefaultCommuteCellSize is the grid cell size in degrees, about 1.1 km
+	// north-south and 0.7 km east-west in the Netherlands. Provider data is
+	// no finer than that, so one point per cell is enough.
+	DefaultCommuteCellSize = 0.01
+
+	// DefaultCommuteSampleSpacing is the distance in meters between points
+	// sampled along each leg of a commute.
+	DefaultCommuteSampleSpacing = 2000
+
+	// DefaultCommuteMaxPoints bounds the points refreshed for commutes.
+	DefaultCommuteMaxPoints = 100
+
+	// DefaultCommuteReloadInterval is how often the points are derived from
+	// the commutes again.
+	DefaultCommuteReloadInterval = time.Hour
+)
+
+// metersPerDegreeLat is the length of a degree of latitude.
+const metersPerDegreeLat = 111_320
+
+// CommutePointsConfig holds configuration for creating a CommutePoints.
+type CommutePointsConfig struct {
+	// Commutes is the commute repository (required).
+	Commutes commute.Repository
+
+	// Logger for load operations.
+	Logger zerolog.Logger
+
+	// CellSize is the grid cell size in degrees used to de-duplicate points.
+	// Default: DefaultCommuteCellSize
+	CellSize float64
+
+	// SampleSpacing is the distance in meters between points sampled
+	// between a commute's stops.
+	// Default: DefaultCommuteSampleSpacing
+	SampleSpacing float64
+
+	// MaxPoints bounds the number of points; the cells crossed by the most
+	// commutes are kept.
+	// Default: DefaultCommuteMaxPoints
+	MaxPoints int
+
+	// ReloadInterval is how long loaded points are reused before the
+	// commutes are read again.
+	// Default: DefaultCommuteReloadInterval
+	ReloadInterval time.Duration
+}
+
+// CommutePoints derives refresh points from users' saved commutes: their
+// origins, waypoints, destinations and points sampled along the legs in
+// between, de-duplicated to one point per grid cell. Caches are then warm
+// where users actually query.
+type CommutePoints struct {
+	commutes       commute.Repository
+	logger         zerolog.Logger
+	cellSize       float64
+	sampleSpacing  float64
+	maxPoints      int
+	reloadInterval time.Duration
+
+	mu       sync.Mutex
+	points   []Point
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+// NewCommutePoints creates a commute point source. No commutes are read
+// until the first call to Target.
+func NewCommutePoints(cfg CommutePointsConfig) *CommutePoints {
+	cellSize := cfg.CellSize
+	if cellSize <= 0 {
+		cellSize = DefaultCommuteCellSize
+	}
+	sampleSpacing := cfg.SampleSpacing
+	if sampleSpacing <= 0 {
+		sampleSpacing = DefaultCommuteSampleSpacing
+	}
+	maxPoints := cfg.MaxPoints
+	if maxPoints <= 0 {
+		maxPoints = DefaultCommuteMaxPoints
+	}
+	reloadInterval := cfg.ReloadInterval
+	if reloadInterval <= 0 {
+		reloadInterval = DefaultCommuteReloadInterval
+	}
+
+	return &CommutePoints{
+		commutes:       cfg.Commutes,
+		logger:         cfg.Logger,
+		cellSize:       cellSize,
+		sampleSpacing:  sampleSpacing,
+		maxPoints:      maxPoints,
+		reloadInterval: reloadInterval,
+		now:            time.Now,
+	}
+}
+
+// Target returns the commute refresh target, reloading the points from the
+// commutes if they are older than the reload interval. It reports false if
+// there are no commutes, so only the configured targets are refreshed.
+func (c *CommutePoints) Target(ctx context.Context) (RefreshTarget, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.loadedAt.IsZero() || now.Sub(c.loadedAt) >= c.reloadInterval {
+		points, err := c.load(ctx)
+		if err != nil {
+			// Keep refreshing the points loaded last time
+			c.logger.Warn().Err(err).Int("points", len(c.points)).Msg("failed to load commute refresh points")
+		} else {
+			c.points = points
+			c.loadedAt = now
+			c.logger.Info().Int("points", len(points)).Msg("loaded commute refresh points")
+		}
+	}
+
+	return c.targetLocked()
+}
+
+// cachedTarget returns the commute target from the points loaded last,
+// without reading the commutes.
+func (c *CommutePoints) cachedTarget() (RefreshTarget, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.targetLocked()
+}
+
+// targetLocked builds the target from c.points. The caller must hold c.mu.
+func (c *CommutePoints) targetLocked() (RefreshTarget, bool) {
+	if len(c.points) == 0 {
+		return RefreshTarget{}, false
+	}
+	return RefreshTarget{
+		Name:     CommuteTargetName,
+		Priority: 1,
+		Points:   append([]Point(nil), c.points...),
+	}, true
+}
+
+// gridCell identifies a grid cell by its row and column.
+type gridCell struct {
+	row, col int64
+}
+
+// load reads every commute and returns the centers of the cells they cross,
+// most-crossed first, up to maxPoints.
+func (c *CommutePoints) load(ctx context.Context) ([]Point, error) {
+	counts := make(map[gridCell]int)
+	opts := commute.ListOptions{Limit: exposurePageSize}
+	for {
+		page, err := c.commutes.ListAll(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range page.Items {
+			for cell := range c.commuteCells(cm) {
+				counts[cell]++
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	cells := make([]gridCell, 0, len(counts))
+	for cell := range counts {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if counts[cells[i]] != counts[cells[j]] {
+			return counts[cells[i]] > counts[cells[j]]
+		}
+		if cells[i].row != cells[j].row {
+			return cells[i].row < cells[j].row
+		}
+		return cells[i].col < cells[j].col
+	})
+	if len(cells) > c.maxPoints {
+		cells = cells[:c.maxPoints]
+	}
+
+	points := make([]Point, len(cells))
+	for i, cell := range cells {
+		points[i] = Point{
+			Lat: (float64(cell.row) + 0.5) * c.cellSize,
+			Lon: (float64(cell.col) + 0.5) * c.cellSize,
+		}
+	}
+	return points, nil
+}
+
+// commuteCells returns the cells crossed by a commute's stops and the legs
+// between them.
+func (c *CommutePoints) commuteCells(cm *commute.Commute) map[gridCell]bool {
+	cells := make(map[gridCell]bool)
+	stops := cm.Stops()
+	for i, stop := range stops {
+		cells[c.cell(stop)] = true
+		if i == 0 {
+			continue
+		}
+		for _, p := range c.sampleLeg(stops[i-1], stop) {
+			cells[c.cell(p)] = true
+		}
+	}
+	return cells
+}
+
+// sampleLeg returns points spaced about sampleSpacing apart strictly
+// between a and b, along the straight line.
+func (c *CommutePoints) sampleLeg(a, b commute.Point) []commute.Point {
+	dLat := (b.Lat - a.Lat) * metersPerDegreeLat
+	dLon := (b.Lon - a.Lon) * metersPerDegreeLat * math.Cos((a.Lat+b.Lat)/2*math.Pi/180)
+	n := int(math.Hypot(dLat, dLon) / c.sampleSpacing)
+
+	points := make([]commute.Point, 0, n)
+	for i := 1; i <= n; i++ {
+		f := float64(i) / float64(n+1)
+		points = append(points, commute.Point{
+			Lat: a.Lat + (b.Lat-a.Lat)*f,
+			Lon: a.Lon + (b.Lon-a.Lon)*f,
+		})
+	}
+	return points
+}
+
+// cell returns the grid cell containing p.
+func (c *CommutePoints) cell(p commute.Point) gridCell {
+	return gridCell{
+		row: int64(math.Floor(p.Lat / c.cellSize)),
+		col: int64(math.Floor(p.Lon / c.cellSize)),
+	}
+}
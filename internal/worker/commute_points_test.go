@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/commute"
+)
+
+// createCommute stores a commute between two points.
+func createCommute(t *testing.T, repo *commute.InMemoryRepository, id string, from, to commute.Point) {
+	t.Helper()
+	require.NoError(t, repo.Create(context.Background(), &commute.Commute{
+		ID:          id,
+		UserID:      "usr_testuser123",
+		Origin:      commute.Location{Point: from},
+		Destination: commute.Location{Point: to},
+	}))
+}
+
+func TestCommutePoints_Target(t *testing.T) {
+	centraal := commute.Point{Lat: 52.3791, Lon: 4.9003}
+	zuid := commute.Point{Lat: 52.3389, Lon: 4.8730} // About 4.8 km from Centraal
+	sloterdijk := commute.Point{Lat: 52.3889, Lon: 4.8378}
+
+	repo := commute.NewInMemoryRepository()
+	createCommute(t, repo, "cmt_1", centraal, zuid)
+	createCommute(t, repo, "cmt_2", centraal, sloterdijk)
+	// Same cells as the first commute
+	createCommute(t, repo, "cmt_3", commute.Point{Lat: 52.3792, Lon: 4.9004}, zuid)
+
+	points := NewCommutePoints(CommutePointsConfig{Commutes: repo, Logger: zerolog.Nop()})
+	target, ok := points.Target(context.Background())
+	require.True(t, ok)
+	assert.Equal(t, CommuteTargetName, target.Name)
+	assert.Equal(t, 1, target.Priority)
+
+	// Centraal is shared by all three commutes, so it comes first
+	assert.Equal(t, points.cell(centraal), points.cell(commute.Point(target.Points[0])))
+
+	// Origins, destinations and samples along the legs, one per cell
+	cells := make(map[gridCell]bool)
+	for _, p := range target.Points {
+		cell := points.cell(commute.Point(p))
+		assert.False(t, cells[cell], "cell %v refreshed twice", cell)
+		cells[cell] = true
+	}
+	assert.True(t, cells[points.cell(zuid)])
+	assert.True(t, cells[points.cell(sloterdijk)])
+	assert.Greater(t, len(target.Points), 3, "legs should be sampled")
+
+	bounded := NewCommutePoints(CommutePointsConfig{Commutes: repo, Logger: zerolog.Nop(), MaxPoints: 2})
+	target, ok = bounded.Target(context.Background())
+	require.True(t, ok)
+	assert.Len(t, target.Points, 2)
+}
+
+func TestCommutePoints_Reload(t *testing.T) {
+	repo := commute.NewInMemoryRepository()
+	points := NewCommutePoints(CommutePointsConfig{Commutes: repo, Logger: zerolog.Nop()})
+	now := time.Date(2026, time.October, 16, 8, 0, 0, 0, time.UTC)
+	points.now = func() time.Time { return now }
+
+	// No users yet: only the configured targets are refreshed
+	_, ok := points.Target(context.Background())
+	assert.False(t, ok)
+
+	job := NewRefreshJob(RefreshJobConfig{
+		Config:        RefreshConfig{Targets: DefaultRefreshTargets(), Concurrency: 1, Timeout: time.Second},
+		Logger:        zerolog.Nop(),
+		CommutePoints: points,
+	})
+	assert.Equal(t, DefaultRefreshConfig().TotalPoints(), job.Run(context.Background()).TotalPoints)
+
+	// New commutes are picked up after the reload interval
+	createCommute(t, repo, "cmt_1", commute.Point{Lat: 52.3791, Lon: 4.9003}, commute.Point{Lat: 52.3799, Lon: 4.9010})
+	_, ok = points.Target(context.Background())
+	assert.False(t, ok, "points should not be reloaded within the interval")
+
+	now = now.Add(DefaultCommuteReloadInterval)
+	target, ok := points.Target(context.Background())
+	require.True(t, ok)
+	assert.Len(t, target.Points, 1)
+	assert.Equal(t, DefaultRefreshConfig().TotalPoints()+1, job.Run(context.Background()).TotalPoints)
+}
@@ -8,20 +8,20 @@ import (
 // RefreshTarget represents a geographic region to refresh.
 type RefreshTarget struct {
 	// Name is the human-readable name of the target.
-	Name string
+	Name string `json:"name" yaml:"name"`
 
 	// Points are the lat/lon coordinates to refresh.
 	// Typically the centers of major cities or commuter hubs.
-	Points []Point
+	Points []Point `json:"points" yaml:"points"`
 
 	// Priority determines refresh order (lower = higher priority).
-	Priority int
+	Priority int `json:"priority" yaml:"priority"`
 }
 
 // Point represents a geographic coordinate.
 type Point struct {
-	Lat float64
-	Lon float64
+	Lat float64 `json:"lat" yaml:"lat"`
+	Lon float64 `json:"lon" yaml:"lon"`
 }
 
 // RefreshConfig holds configuration for the provider refresh job.
@@ -38,6 +38,24 @@ type RefreshConfig struct {
 	// Default: 30 seconds
 	Timeout time.Duration
 
+	// TimeoutByProvider overrides Timeout for individual provider calls, so
+	// a slow upstream does not share a deadline tuned for a fast one. Keys
+	// are the Provider* names; providers without an entry use Timeout.
+	// Default: see DefaultTimeoutByProvider
+	TimeoutByProvider map[string]time.Duration
+
+	// RetryAttempts is the number of attempts per provider call when it
+	// fails with a transient error, such as the provider being unavailable
+	// or timing out. Zero or one disables retries.
+	// Default: 3
+	RetryAttempts int
+
+	// RetryBackoff is the base delay before retrying a provider call. Each
+	// retry waits a random delay up to a ceiling that starts here and
+	// doubles, capped at 8 times the base.
+	// Default: 2 seconds
+	RetryBackoff time.Duration
+
 	// RefreshAirQuality enables air quality refresh.
 	// Default: true
 	RefreshAirQuality bool
@@ -53,18 +71,89 @@ type RefreshConfig struct {
 	// RefreshTransit enables transit disruption refresh.
 	// Default: true
 	RefreshTransit bool
+
+	// PriorityIntervals maps target priority to how often the Scheduler refreshes it.
+	// Priorities not listed use DefaultPriorityInterval.
+	// Default: priority 1 every 5 minutes, 2 every 10 minutes, 3 every 20 minutes
+	PriorityIntervals map[int]time.Duration
+
+	// ProviderRateLimits caps requests per second to each provider, shared
+	// across all workers. Keys are the Provider* names; providers without an
+	// entry are not limited. Air quality refreshes served from a fresh
+	// snapshot do not count against the limit.
+	// Default: see DefaultProviderRateLimits
+	ProviderRateLimits map[string]float64
+
+	// DryRun logs the provider calls each point would make and reports them
+	// as successful without invoking services, so new targets can be checked
+	// without hitting providers or mutating caches.
+	// Default: false
+	DryRun bool
+}
+
+// Provider names used in rate limits and refresh errors.
+const (
+	ProviderAirQuality = "airquality"
+	ProviderWeather    = "weather"
+	ProviderPollen     = "pollen"
+	ProviderTransit    = "transit"
+)
+
+// DefaultProviderRateLimits returns the default requests-per-second limit per provider.
+// Weather and pollen are separate upstreams, so each has its own budget.
+func DefaultProviderRateLimits() map[string]float64 {
+	return map[string]float64{
+		ProviderAirQuality: 2,
+		ProviderWeather:    1,
+		ProviderPollen:     0.5,
+		ProviderTransit:    1,
+	}
+}
+
+// DefaultTimeoutByProvider returns the default per-provider refresh timeouts.
+// Ambee pollen responses are markedly slower than the other providers.
+func DefaultTimeoutByProvider() map[string]time.Duration {
+	return map[string]time.Duration{
+		ProviderPollen: 60 * time.Second,
+	}
+}
+
+// TimeoutFor returns the refresh timeout for a provider.
+func (c RefreshConfig) TimeoutFor(provider string) time.Duration {
+	if timeout, ok := c.TimeoutByProvider[provider]; ok && timeout > 0 {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// DefaultPriorityInterval is the refresh interval for priorities without an
+// entry in PriorityIntervals.
+const DefaultPriorityInterval = 20 * time.Minute
+
+// DefaultPriorityIntervals returns the default refresh interval per target priority.
+func DefaultPriorityIntervals() map[int]time.Duration {
+	return map[int]time.Duration{
+		1: 5 * time.Minute,
+		2: 10 * time.Minute,
+		3: 20 * time.Minute,
+	}
 }
 
 // DefaultRefreshConfig returns the default refresh configuration.
 func DefaultRefreshConfig() RefreshConfig {
 	return RefreshConfig{
-		Targets:           DefaultRefreshTargets(),
-		Concurrency:       3,
-		Timeout:           30 * time.Second,
-		RefreshAirQuality: true,
-		RefreshWeather:    true,
-		RefreshPollen:     true,
-		RefreshTransit:    true,
+		Targets:            DefaultRefreshTargets(),
+		Concurrency:        3,
+		Timeout:            30 * time.Second,
+		TimeoutByProvider:  DefaultTimeoutByProvider(),
+		RetryAttempts:      3,
+		RetryBackoff:       2 * time.Second,
+		RefreshAirQuality:  true,
+		RefreshWeather:     true,
+		RefreshPollen:      true,
+		RefreshTransit:     true,
+		PriorityIntervals:  DefaultPriorityIntervals(),
+		ProviderRateLimits: DefaultProviderRateLimits(),
 	}
 }
 
@@ -171,3 +260,11 @@ func (c RefreshConfig) TotalPoints() int {
 	}
 	return total
 }
+
+// IntervalFor returns the refresh interval for targets of the given priority.
+func (c RefreshConfig) IntervalFor(priority int) time.Duration {
+	if interval, ok := c.PriorityIntervals[priority]; ok && interval > 0 {
+		return interval
+	}
+	return DefaultPriorityInterval
+}
@@ -0,0 +1,338 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Job types dispatched from the "job_type" field of a message.
+const (
+	JobTypeProviderRefresh = "provider_refresh"
+	JobTypeHealthCheck     = "health_check"
+	JobTypeAlertEvaluation = "alert_evaluation"
+	JobTypeGDPRExport      = "gdpr_export"
+	JobTypeGDPRDeletion    = "gdpr_deletion"
+)
+
+// jobTypes are the declared job types. A worker without a handler for one
+// dead-letters its messages with ErrNoHandler, so they are kept for replay
+// rather than redelivered in a loop.
+var jobTypes = map[string]bool{
+	JobTypeProviderRefresh: true,
+	JobTypeHealthCheck:     true,
+	JobTypeAlertEvaluation: true,
+	JobTypeGDPRExport:      true,
+	JobTypeGDPRDeletion:    true,
+}
+
+// IdempotencyKeyAttribute is the message attribute that identifies a job
+// across publishes. Messages without it are deduplicated by message ID.
+const IdempotencyKeyAttribute = "idempotency_key"
+
+// Dispatcher defaults.
+const (
+	// DefaultMaxDeliveryAttempts matches the dead letter policy of the
+	// worker's Pub/Sub subscriptions.
+	DefaultMaxDeliveryAttempts = 5
+
+	// DefaultIdempotencyTTL is how long completed jobs are remembered.
+	// Pub/Sub redeliveries arrive well within it.
+	DefaultIdempotencyTTL = time.Hour
+)
+
+var (
+	// ErrMalformedMessage is the dead letter reason for messages that are
+	// not a JSON job message.
+	ErrMalformedMessage = errors.New("malformed job message")
+
+	// ErrUnknownJobType is the dead letter reason for messages whose job
+	// type is not one of the declared job types.
+	ErrUnknownJobType = errors.New("unknown job type")
+
+	// ErrNoHandler is the dead letter reason for messages of a declared job
+	// type that this worker has no handler for.
+	ErrNoHandler = errors.New("no handler for job type")
+)
+
+// JobHandler processes the data of one job message. Returning an error has
+// the message redelivered, until it reaches the maximum delivery attempts.
+// Errors wrapping ErrMalformedMessage are dead-lettered without retries.
+type JobHandler func(ctx context.Context, data []byte) error
+
+// DispatcherConfig holds configuration for creating a Dispatcher.
+type DispatcherConfig struct {
+	Logger zerolog.Logger
+
+	// DeadLetter keeps messages that failed MaxAttempts times or can never
+	// be processed (default: log and drop them).
+	DeadLetter DeadLetterSink
+
+	// MaxAttempts is how many deliveries a failing message gets before it
+	// is dead-lettered (default: DefaultMaxDeliveryAttempts).
+	MaxAttempts int
+
+	// IdempotencyTTL is how long completed jobs are remembered so
+	// redeliveries are acknowledged without running them again (default:
+	// DefaultIdempotencyTTL).
+	IdempotencyTTL time.Duration
+}
+
+// Dispatcher routes job messages to handlers by job type, with
+// at-least-once delivery: a message is acknowledged only once its job has
+// completed or been dead-lettered.
+type Dispatcher struct {
+	handlers    map[string]JobHandler
+	deadLetter  DeadLetterSink
+	maxAttempts int
+	ttl         time.Duration
+	logger      zerolog.Logger
+
+	// runCtx is passed to handlers. It outlives Run's context so shutdown
+	// can drain jobs in flight; CancelRuns cancels it.
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+
+	mu         sync.Mutex
+	completed  map[string]time.Time
+	inProgress map[string]bool
+	failures   map[string]int
+	prunedAt   time.Time
+}
+
+// jobEnvelope is the part of a job message the dispatcher reads.
+type jobEnvelope struct {
+	JobType string `json:"job_type"`
+}
+
+// NewDispatcher creates a dispatcher with no handlers.
+func NewDispatcher(cfg DispatcherConfig) *Dispatcher {
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxDeliveryAttempts
+	}
+	ttl := cfg.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	deadLetter := cfg.DeadLetter
+	if deadLetter == nil {
+		deadLetter = logDeadLetter{logger: cfg.Logger}
+	}
+
+	d := &Dispatcher{
+		handlers:    make(map[string]JobHandler),
+		deadLetter:  deadLetter,
+		maxAttempts: maxAttempts,
+		ttl:         ttl,
+		logger:      cfg.Logger,
+		completed:   make(map[string]time.Time),
+		inProgress:  make(map[string]bool),
+		failures:    make(map[string]int),
+	}
+	d.runCtx, d.cancelRuns = context.WithCancel(context.Background())
+	return d
+}
+
+// Handled returns the job types with a registered handler.
+func (d *Dispatcher) Handled() []string {
+	types := make([]string, 0, len(d.handlers))
+	for jobType := range d.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Handle registers the handler for a job type.
+func (d *Dispatcher) Handle(jobType string, handler JobHandler) *Dispatcher {
+	d.handlers[jobType] = handler
+	return d
+}
+
+// Run dispatches messages from source until ctx is canceled or the source
+// fails. Canceling ctx stops receiving messages but not the jobs in flight,
+// so a refresh triggered by a message is not cut off halfway; call
+// CancelRuns if they take too long to drain.
+func (d *Dispatcher) Run(ctx context.Context, source MessageSource) error {
+	return source.Receive(ctx, func(_ context.Context, msg *Message) {
+		d.dispatch(d.runCtx, msg)
+	})
+}
+
+// CancelRuns cancels the jobs in flight. Call it when they have not
+// finished within the drain timeout after Run's context was canceled.
+func (d *Dispatcher) CancelRuns() {
+	d.cancelRuns()
+}
+
+// dispatch processes a single delivery and acknowledges it.
+func (d *Dispatcher) dispatch(ctx context.Context, msg *Message) {
+	key := idempotencyKey(msg)
+	logger := d.logger.With().
+		Str("message_id", msg.ID).
+		Str("idempotency_key", key).
+		Int("delivery_attempt", msg.DeliveryAttempt).
+		Logger()
+
+	switch d.begin(key) {
+	case jobCompleted:
+		logger.Info().Msg("job already completed, acknowledging duplicate")
+		msg.Ack()
+		return
+	case jobInProgress:
+		// Redeliver later; by then the job has completed or failed
+		msg.Nack()
+		return
+	}
+
+	var envelope jobEnvelope
+	if err := json.Unmarshal(msg.Data, &envelope); err != nil || envelope.JobType == "" {
+		d.reject(ctx, logger, key, msg, ErrMalformedMessage)
+		return
+	}
+	logger = logger.With().Str("job_type", envelope.JobType).Logger()
+
+	handler, ok := d.handlers[envelope.JobType]
+	if !ok {
+		if jobTypes[envelope.JobType] {
+			// A declared job this worker cannot run yet. Redelivering it
+			// would never succeed, so keep it in the dead letter topic for
+			// replay once a handler exists.
+			d.reject(ctx, logger, key, msg, fmt.Errorf("%w: %s", ErrNoHandler, envelope.JobType))
+			return
+		}
+		d.reject(ctx, logger, key, msg, fmt.Errorf("%w: %s", ErrUnknownJobType, envelope.JobType))
+		return
+	}
+
+	start := time.Now()
+	if err := handler(ctx, msg.Data); err != nil {
+		if errors.Is(err, ErrMalformedMessage) {
+			d.reject(ctx, logger, key, msg, err)
+			return
+		}
+		attempts := d.recordFailure(key, msg.DeliveryAttempt)
+		if attempts >= d.maxAttempts {
+			d.reject(ctx, logger, key, msg, fmt.Errorf("failed after %d attempts: %w", attempts, err))
+			return
+		}
+		logger.Warn().Err(err).Int("attempts", attempts).Msg("job failed, will be retried")
+		d.end(key, false)
+		msg.Nack()
+		return
+	}
+
+	logger.Info().Dur("duration", time.Since(start)).Msg("job completed")
+	d.end(key, true)
+	msg.Ack()
+}
+
+// reject dead-letters msg and acknowledges it. If the dead letter sink
+// fails, the message is redelivered instead so it is not lost.
+func (d *Dispatcher) reject(ctx context.Context, logger zerolog.Logger, key string, msg *Message, reason error) {
+	if err := d.deadLetter.DeadLetter(ctx, msg, reason); err != nil {
+		logger.Error().Err(err).AnErr("reason", reason).Msg("failed to dead-letter message, will be retried")
+		d.end(key, false)
+		msg.Nack()
+		return
+	}
+	logger.Warn().Err(reason).Msg("message dead-lettered")
+	d.end(key, true)
+	msg.Ack()
+}
+
+// jobState is the state of a job when a delivery arrives.
+type jobState int
+
+const (
+	jobNew jobState = iota
+	jobInProgress
+	jobCompleted
+)
+
+// begin marks the job as in progress unless it already is or has
+// completed.
+func (d *Dispatcher) begin(key string) jobState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	d.pruneLocked(now)
+
+	if at, ok := d.completed[key]; ok && now.Sub(at) < d.ttl {
+		return jobCompleted
+	}
+	if d.inProgress[key] {
+		return jobInProgress
+	}
+	d.inProgress[key] = true
+	return jobNew
+}
+
+// end clears the in-progress mark, remembering the job if it is done.
+func (d *Dispatcher) end(key string, done bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.inProgress, key)
+	if done {
+		delete(d.failures, key)
+		d.completed[key] = time.Now()
+	}
+}
+
+// recordFailure counts a failed delivery and returns the number of attempts
+// so far. The source's delivery attempt is used when it counts higher, such
+// as after a worker restart.
+func (d *Dispatcher) recordFailure(key string, deliveryAttempt int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	attempts := max(d.failures[key]+1, deliveryAttempt)
+	d.failures[key] = attempts
+	return attempts
+}
+
+// pruneLocked forgets completed jobs older than the TTL, at most once a
+// minute. The caller must hold d.mu.
+func (d *Dispatcher) pruneLocked(now time.Time) {
+	if now.Sub(d.prunedAt) < time.Minute {
+		return
+	}
+	d.prunedAt = now
+	for key, at := range d.completed {
+		if now.Sub(at) >= d.ttl {
+			delete(d.completed, key)
+		}
+	}
+}
+
+// idempotencyKey returns the key identifying the job of msg.
+func idempotencyKey(msg *Message) string {
+	if key := msg.Attributes[IdempotencyKeyAttribute]; key != "" {
+		return key
+	}
+	return msg.ID
+}
+
+// logDeadLetter is the default DeadLetterSink, which only logs.
+type logDeadLetter struct {
+	logger zerolog.Logger
+}
+
+// DeadLetter logs msg and drops it.
+func (l logDeadLetter) DeadLetter(_ context.Context, msg *Message, reason error) error {
+	l.logger.Error().
+		Err(reason).
+		Str("message_id", msg.ID).
+		Bytes("data", msg.Data).
+		Msg("dropping message without a dead letter topic")
+	return nil
+}
@@ -0,0 +1,228 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runDispatcher dispatches published messages until the source is drained.
+func runDispatcher(t *testing.T, d *Dispatcher, source *MemorySource) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx, source) }()
+
+	require.Eventually(t, func() bool { return source.Pending() == 0 }, 2*time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestDispatcher_RoutesByJobType(t *testing.T) {
+	source := NewMemorySource()
+	var refreshes, checks int
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop()}).
+		Handle(JobTypeProviderRefresh, func(context.Context, []byte) error { refreshes++; return nil }).
+		Handle(JobTypeHealthCheck, func(context.Context, []byte) error { checks++; return nil })
+
+	source.Publish([]byte(`{"job_type":"provider_refresh"}`), nil)
+	source.Publish([]byte(`{"job_type":"health_check"}`), nil)
+	runDispatcher(t, d, source)
+
+	assert.Equal(t, 1, refreshes)
+	assert.Equal(t, 1, checks)
+	assert.Len(t, source.Acked(), 2)
+}
+
+func TestDispatcher_Idempotency(t *testing.T) {
+	source := NewMemorySource()
+	runs := 0
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop()}).
+		Handle(JobTypeProviderRefresh, func(context.Context, []byte) error { runs++; return nil })
+
+	// The same job published twice, as after a publisher retry
+	attrs := map[string]string{IdempotencyKeyAttribute: "refresh-2026-10-16T10"}
+	source.Publish([]byte(`{"job_type":"provider_refresh"}`), attrs)
+	source.Publish([]byte(`{"job_type":"provider_refresh"}`), attrs)
+	runDispatcher(t, d, source)
+
+	assert.Equal(t, 1, runs, "duplicate job should not run again")
+	assert.Len(t, source.Acked(), 2, "duplicate should be acknowledged")
+}
+
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	source := NewMemorySource()
+	deadLetter := &MemoryDeadLetter{}
+	failing := errors.New("provider down")
+	attempts := 0
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop(), DeadLetter: deadLetter, MaxAttempts: 3}).
+		Handle(JobTypeProviderRefresh, func(context.Context, []byte) error { attempts++; return failing })
+
+	id := source.Publish([]byte(`{"job_type":"provider_refresh"}`), nil)
+	runDispatcher(t, d, source)
+
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []string{id}, source.Acked(), "dead-lettered message should be acknowledged")
+
+	dead := deadLetter.Messages()
+	require.Len(t, dead, 1)
+	assert.Equal(t, id, dead[0].Message.ID)
+	assert.Equal(t, 3, dead[0].Message.DeliveryAttempt)
+	assert.ErrorIs(t, dead[0].Reason, failing)
+}
+
+func TestDispatcher_RetrySucceeds(t *testing.T) {
+	source := NewMemorySource()
+	deadLetter := &MemoryDeadLetter{}
+	attempts := 0
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop(), DeadLetter: deadLetter}).
+		Handle(JobTypeProviderRefresh, func(context.Context, []byte) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("timeout")
+			}
+			return nil
+		})
+
+	source.Publish([]byte(`{"job_type":"provider_refresh"}`), nil)
+	runDispatcher(t, d, source)
+
+	assert.Equal(t, 2, attempts)
+	assert.Empty(t, deadLetter.Messages())
+}
+
+func TestDispatcher_UnprocessableMessages(t *testing.T) {
+	source := NewMemorySource()
+	deadLetter := &MemoryDeadLetter{}
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop(), DeadLetter: deadLetter})
+
+	source.Publish([]byte(`not json`), nil)
+	source.Publish([]byte(`{"job_type":"invoice_run"}`), nil)
+	runDispatcher(t, d, source)
+
+	dead := deadLetter.Messages()
+	require.Len(t, dead, 2, "unprocessable messages should be dead-lettered without retries")
+	assert.ErrorIs(t, dead[0].Reason, ErrMalformedMessage)
+	assert.ErrorIs(t, dead[1].Reason, ErrUnknownJobType)
+	assert.Len(t, source.Acked(), 2)
+}
+
+func TestDispatcher_RoutesEveryJobType(t *testing.T) {
+	source := NewMemorySource()
+	handled := make(map[string]int)
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop()})
+	for jobType := range jobTypes {
+		d.Handle(jobType, func(context.Context, []byte) error { handled[jobType]++; return nil })
+	}
+
+	for _, jobType := range []string{JobTypeProviderRefresh, JobTypeHealthCheck, JobTypeAlertEvaluation, JobTypeGDPRExport, JobTypeGDPRDeletion} {
+		source.Publish([]byte(`{"job_type":"`+jobType+`"}`), nil)
+	}
+	runDispatcher(t, d, source)
+
+	assert.Equal(t, map[string]int{
+		JobTypeProviderRefresh: 1,
+		JobTypeHealthCheck:     1,
+		JobTypeAlertEvaluation: 1,
+		JobTypeGDPRExport:      1,
+		JobTypeGDPRDeletion:    1,
+	}, handled)
+	assert.Len(t, source.Acked(), 5)
+}
+
+func TestDispatcher_DeadLettersUnhandledJobTypes(t *testing.T) {
+	source := NewMemorySource()
+	deadLetter := &MemoryDeadLetter{}
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop(), DeadLetter: deadLetter}).
+		Handle(JobTypeProviderRefresh, func(context.Context, []byte) error { return nil })
+
+	source.Publish([]byte(`{"job_type":"alert_evaluation"}`), nil)
+	source.Publish([]byte(`{"job_type":"gdpr_export"}`), nil)
+	runDispatcher(t, d, source)
+
+	dead := deadLetter.Messages()
+	require.Len(t, dead, 2, "declared job types without a handler should be dead-lettered on first delivery")
+	for _, m := range dead {
+		assert.ErrorIs(t, m.Reason, ErrNoHandler)
+		assert.NotErrorIs(t, m.Reason, ErrUnknownJobType)
+	}
+	assert.Contains(t, dead[0].Reason.Error(), JobTypeAlertEvaluation)
+	assert.Contains(t, dead[1].Reason.Error(), JobTypeGDPRExport)
+	assert.Len(t, source.Acked(), 2, "unhandled jobs should be acknowledged, not redelivered")
+}
+
+func TestDispatcher_MalformedJobNotRetried(t *testing.T) {
+	source := NewMemorySource()
+	deadLetter := &MemoryDeadLetter{}
+	attempts := 0
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop(), DeadLetter: deadLetter}).
+		Handle(JobTypeGDPRDeletion, func(context.Context, []byte) error {
+			attempts++
+			return fmt.Errorf("%w: missing user_id", ErrMalformedMessage)
+		})
+
+	source.Publish([]byte(`{"job_type":"gdpr_deletion"}`), nil)
+	runDispatcher(t, d, source)
+
+	assert.Equal(t, 1, attempts)
+	dead := deadLetter.Messages()
+	require.Len(t, dead, 1)
+	assert.ErrorIs(t, dead[0].Reason, ErrMalformedMessage)
+}
+
+func TestDispatcher_DrainsJobsAfterRunStops(t *testing.T) {
+	source := NewMemorySource()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var jobErr error
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop()}).
+		Handle(JobTypeProviderRefresh, func(ctx context.Context, _ []byte) error {
+			close(started)
+			<-release
+			jobErr = ctx.Err()
+			return nil
+		})
+
+	source.Publish([]byte(`{"job_type":"provider_refresh"}`), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx, source) }()
+
+	<-started
+	cancel()
+	close(release)
+	require.NoError(t, <-done)
+
+	assert.NoError(t, jobErr, "stopping Run should not cancel the job in flight")
+	assert.Len(t, source.Acked(), 1)
+}
+
+func TestDispatcher_CancelRuns(t *testing.T) {
+	source := NewMemorySource()
+	started := make(chan struct{})
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop()}).
+		Handle(JobTypeProviderRefresh, func(ctx context.Context, _ []byte) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	source.Publish([]byte(`{"job_type":"provider_refresh"}`), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx, source) }()
+
+	<-started
+	cancel()
+	d.CancelRuns()
+	require.NoError(t, <-done)
+
+	assert.Empty(t, source.Acked(), "canceled job should be redelivered")
+}
@@ -0,0 +1,257 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/commute"
+	"github.com/breatheroute/breatheroute/internal/exposure"
+)
+
+// exposureScheduleName is the schedule entry name used for the exposure job.
+const exposureScheduleName = "exposure"
+
+// exposurePageSize is the number of commutes loaded per repository call.
+const exposurePageSize = 100
+
+// errInsufficientAirQuality marks trips skipped for lack of air quality data.
+var errInsufficientAirQuality = errors.New("insufficient air quality data")
+
+// exposurePollutants are the pollutants every trip estimate must include.
+var exposurePollutants = []airquality.Pollutant{
+	airquality.PollutantNO2,
+	airquality.PollutantPM25,
+	airquality.PollutantO3,
+}
+
+// ExposureJobConfig holds configuration for creating an ExposureJob.
+type ExposureJobConfig struct {
+	// Commutes is the commute repository (required).
+	Commutes commute.Repository
+
+	// Trips stores the estimated trip exposures (required).
+	Trips exposure.Repository
+
+	// History provides the air quality at past hours (required).
+	History *airquality.History
+
+	// Logger for job operations.
+	Logger zerolog.Logger
+
+	// Lookback is how long after its scheduled arrival a trip is still
+	// estimated, so trips missed while the worker was down are caught up.
+	// It cannot exceed airquality.HistoryRetention.
+	// Default: 48 hours
+	Lookback time.Duration
+
+	// Interval is how often the Scheduler runs the job.
+	// Default: 1 hour
+	Interval time.Duration
+
+	// Interpolation configures the interpolation of station measurements
+	// at the commute's stops.
+	// Default: airquality.DefaultInterpolationConfig()
+	Interpolation airquality.InterpolationConfig
+}
+
+// ExposureJob estimates the exposure on users' completed commute trips from
+// archived air quality snapshots and stores one estimate per commute and
+// date. Runs are idempotent: trips already stored are skipped.
+//
+// A trip is estimated with the air quality of its scheduled arrival hour,
+// averaged over the commute's origin, waypoints and destination. Trips are
+// skipped if that hour was not archived or any stop lacks NO2, PM2.5 or O3.
+type ExposureJob struct {
+	commutes     commute.Repository
+	trips        exposure.Repository
+	history      *airquality.History
+	logger       zerolog.Logger
+	lookback     time.Duration
+	interval     time.Duration
+	interpolator *airquality.Interpolator
+	now          func() time.Time
+}
+
+// ExposureResult summarizes an exposure job run.
+type ExposureResult struct {
+	Commutes int
+
+	// Recorded is the number of trips estimated and stored by this run.
+	Recorded int
+
+	// Existing is the number of trips already stored by an earlier run.
+	Existing int
+
+	// InsufficientData is the number of trips skipped for lack of air
+	// quality data.
+	InsufficientData int
+
+	// Failed is the number of trips that could not be read or stored.
+	Failed int
+}
+
+// NewExposureJob creates a new exposure job.
+func NewExposureJob(cfg ExposureJobConfig) *ExposureJob {
+	lookback := cfg.Lookback
+	if lookback == 0 {
+		lookback = 48 * time.Hour
+	}
+	lookback = min(lookback, airquality.HistoryRetention)
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = time.Hour
+	}
+
+	interpolation := cfg.Interpolation
+	if interpolation.MaxDistance == 0 {
+		interpolation = airquality.DefaultInterpolationConfig()
+	}
+
+	return &ExposureJob{
+		commutes:     cfg.Commutes,
+		trips:        cfg.Trips,
+		history:      cfg.History,
+		logger:       cfg.Logger,
+		lookback:     lookback,
+		interval:     interval,
+		interpolator: airquality.NewInterpolator(interpolation),
+		now:          time.Now,
+	}
+}
+
+// Run estimates every completed trip within the lookback window that is not
+// stored yet.
+func (j *ExposureJob) Run(ctx context.Context) *ExposureResult {
+	start := time.Now()
+	now := j.now()
+	result := &ExposureResult{}
+
+	opts := commute.ListOptions{Limit: exposurePageSize}
+	for {
+		page, err := j.commutes.ListAll(ctx, opts)
+		if err != nil {
+			j.logger.Error().Err(err).Msg("failed to list commutes for exposure estimates")
+			result.Failed++
+			break
+		}
+
+		for _, c := range page.Items {
+			result.Commutes++
+			j.runCommute(ctx, c, now, result)
+		}
+
+		if page.NextCursor == "" || ctx.Err() != nil {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	j.logger.Info().
+		Int("commutes", result.Commutes).
+		Int("recorded", result.Recorded).
+		Int("existing", result.Existing).
+		Int("insufficient_data", result.InsufficientData).
+		Int("failed", result.Failed).
+		Dur("duration", time.Since(start)).
+		Msg("exposure estimates completed")
+
+	return result
+}
+
+// runCommute estimates the commute's completed trips within the lookback window.
+func (j *ExposureJob) runCommute(ctx context.Context, c *commute.Commute, now time.Time, result *ExposureResult) {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	from := now.Add(-j.lookback).In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	for ; !day.After(now); day = day.AddDate(0, 0, 1) {
+		arrival, ok := c.ArrivalOn(day)
+		if !ok || arrival.After(now) || arrival.Before(from) {
+			continue
+		}
+
+		logger := j.logger.With().
+			Str("commute_id", c.ID).
+			Str("date", day.Format("2006-01-02")).
+			Logger()
+
+		date := exposure.TripDate(day)
+		exists, err := j.trips.Exists(ctx, c.ID, date)
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to check for trip exposure")
+			result.Failed++
+			continue
+		}
+		if exists {
+			result.Existing++
+			continue
+		}
+
+		trip, err := j.estimate(ctx, c, arrival)
+		if errors.Is(err, errInsufficientAirQuality) {
+			logger.Debug().Err(err).Msg("skipping trip exposure")
+			result.InsufficientData++
+			continue
+		}
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to estimate trip exposure")
+			result.Failed++
+			continue
+		}
+		trip.Date = date
+
+		if err := j.trips.Create(ctx, trip); err != nil {
+			logger.Warn().Err(err).Msg("failed to store trip exposure")
+			result.Failed++
+			continue
+		}
+		result.Recorded++
+	}
+}
+
+// estimate averages the air quality at the commute's stops in the hour of
+// arrival.
+func (j *ExposureJob) estimate(ctx context.Context, c *commute.Commute, arrival time.Time) (*exposure.Trip, error) {
+	snapshot, err := j.history.At(ctx, arrival)
+	if errors.Is(err, airquality.ErrNoHistory) {
+		return nil, errInsufficientAirQuality
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[airquality.Pollutant]float64, len(exposurePollutants))
+	stops := c.Stops()
+	for _, stop := range stops {
+		point, err := j.interpolator.Interpolate(stop.Lat, stop.Lon, snapshot)
+		if err != nil {
+			return nil, errInsufficientAirQuality
+		}
+		for _, pollutant := range exposurePollutants {
+			value, ok := point.Values[pollutant]
+			if !ok {
+				return nil, errInsufficientAirQuality
+			}
+			sums[pollutant] += value.Value
+		}
+	}
+
+	n := float64(len(stops))
+	return &exposure.Trip{
+		CommuteID: c.ID,
+		UserID:    c.UserID,
+		ArrivalAt: arrival,
+		NO2:       sums[airquality.PollutantNO2] / n,
+		PM25:      sums[airquality.PollutantPM25] / n,
+		O3:        sums[airquality.PollutantO3] / n,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/commute"
+	"github.com/breatheroute/breatheroute/internal/exposure"
+	"github.com/breatheroute/breatheroute/internal/provider/cache"
+)
+
+// exposureSnapshot returns a snapshot of two Amsterdam stations measured at t.
+func exposureSnapshot(t time.Time, pollutants ...airquality.Pollutant) *airquality.AQSnapshot {
+	snapshot := airquality.NewAQSnapshot("test")
+	for i, station := range []*airquality.Station{
+		{ID: "NL49014", Lat: 52.359, Lon: 4.866},
+		{ID: "NL49017", Lat: 52.389, Lon: 4.887},
+	} {
+		station.Pollutants = pollutants
+		snapshot.Stations[station.ID] = station
+		for _, pollutant := range pollutants {
+			snapshot.SetMeasurement(&airquality.Measurement{
+				StationID:  station.ID,
+				Pollutant:  pollutant,
+				Value:      float64(20 + 10*i),
+				Unit:       airquality.UnitMicrogramsPerCubicMeter,
+				MeasuredAt: t,
+			})
+		}
+	}
+	return snapshot
+}
+
+func newTestExposureJob(t *testing.T, now time.Time) (*ExposureJob, *airquality.History, *exposure.InMemoryRepository) {
+	t.Helper()
+
+	store, err := cache.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	history := airquality.NewHistory(store)
+
+	commutes := commute.NewInMemoryRepository()
+	require.NoError(t, commutes.Create(context.Background(), &commute.Commute{
+		ID:                        "cmt_1",
+		UserID:                    "usr_testuser123",
+		Origin:                    commute.Location{Point: commute.Point{Lat: 52.37, Lon: 4.89}},
+		Destination:               commute.Location{Point: commute.Point{Lat: 52.36, Lon: 4.87}},
+		DaysOfWeek:                []int{1, 2, 3, 4, 5, 6, 7},
+		PreferredArrivalTimeLocal: "08:30",
+		Timezone:                  "Europe/Amsterdam",
+	}))
+
+	trips := exposure.NewInMemoryRepository()
+	job := NewExposureJob(ExposureJobConfig{
+		Commutes: commutes,
+		Trips:    trips,
+		History:  history,
+		Logger:   zerolog.Nop(),
+	})
+	job.now = func() time.Time { return now }
+	return job, history, trips
+}
+
+func TestExposureJob_Run(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, amsterdam)
+
+	job, history, trips := newTestExposureJob(t, now)
+
+	// Only Wednesday's arrival hour was archived; Tuesday's is missing and
+	// Monday's trip is outside the 48 hour lookback
+	require.NoError(t, history.Record(context.Background(), exposureSnapshot(
+		time.Date(2026, time.October, 14, 8, 0, 0, 0, amsterdam),
+		airquality.PollutantNO2, airquality.PollutantPM25, airquality.PollutantO3,
+	)))
+
+	result := job.Run(context.Background())
+	assert.Equal(t, 1, result.Commutes)
+	assert.Equal(t, 1, result.Recorded)
+	assert.Equal(t, 1, result.InsufficientData)
+	assert.Zero(t, result.Failed)
+
+	stored, err := trips.ListByUser(context.Background(), "usr_testuser123", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC), stored[0].Date)
+	assert.True(t, stored[0].ArrivalAt.Equal(time.Date(2026, time.October, 14, 8, 30, 0, 0, amsterdam)))
+	assert.Greater(t, stored[0].NO2, 20.0)
+	assert.Less(t, stored[0].NO2, 30.0)
+	assert.Equal(t, stored[0].NO2, stored[0].PM25)
+
+	// Running again stores nothing new
+	result = job.Run(context.Background())
+	assert.Zero(t, result.Recorded)
+	assert.Equal(t, 1, result.Existing)
+	assert.Equal(t, 1, result.InsufficientData)
+}
+
+func TestExposureJob_SkipsMissingPollutants(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	now := time.Date(2026, time.October, 14, 9, 0, 0, 0, amsterdam)
+
+	job, history, trips := newTestExposureJob(t, now)
+	require.NoError(t, history.Record(context.Background(), exposureSnapshot(
+		time.Date(2026, time.October, 14, 8, 0, 0, 0, amsterdam),
+		airquality.PollutantNO2, airquality.PollutantPM25,
+	)))
+
+	result := job.Run(context.Background())
+	assert.Zero(t, result.Recorded)
+	assert.Equal(t, 2, result.InsufficientData)
+
+	exists, err := trips.Exists(context.Background(), "cmt_1", time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestScheduler_SchedulesExposureJob(t *testing.T) {
+	job := NewRefreshJob(RefreshJobConfig{Config: DefaultRefreshConfig(), Logger: zerolog.Nop()})
+	exposureJob := NewExposureJob(ExposureJobConfig{Logger: zerolog.Nop(), Interval: 2 * time.Hour})
+
+	s := NewScheduler(SchedulerConfig{Job: job, Exposure: exposureJob, Logger: zerolog.Nop()})
+
+	entry := s.entries[len(s.entries)-1]
+	assert.Equal(t, exposureScheduleName, entry.name)
+	assert.Equal(t, 2*time.Hour, entry.interval)
+}
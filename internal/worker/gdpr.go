@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/breatheroute/breatheroute/internal/auth"
+)
+
+// GDPRDeletionMessage represents a GDPR deletion job message.
+type GDPRDeletionMessage struct {
+	JobType   string `json:"job_type"`
+	UserID    string `json:"user_id"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// AccountDeleter deletes a user account, returning auth.ErrUserNotFound if
+// it does not exist.
+type AccountDeleter interface {
+	Delete(ctx context.Context, userID string) error
+}
+
+// GDPRDeletionHandler returns the handler for gdpr_deletion jobs, which
+// delete a user's account and, through it, all their data. A user that no
+// longer exists counts as deleted, so redeliveries succeed.
+func GDPRDeletionHandler(accounts AccountDeleter, logger zerolog.Logger) JobHandler {
+	return func(ctx context.Context, data []byte) error {
+		var msg GDPRDeletionMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+		}
+		if msg.UserID == "" {
+			return fmt.Errorf("%w: missing user_id", ErrMalformedMessage)
+		}
+
+		logger := logger.With().
+			Str("user_id", msg.UserID).
+			Str("deletion_request_id", msg.RequestID).
+			Logger()
+
+		err := accounts.Delete(ctx, msg.UserID)
+		switch {
+		case errors.Is(err, auth.ErrUserNotFound):
+			logger.Info().Msg("user already deleted")
+			return nil
+		case err != nil:
+			return fmt.Errorf("deleting user: %w", err)
+		}
+
+		logger.Info().Msg("user data deleted")
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/auth"
+)
+
+func TestGDPRDeletionHandler(t *testing.T) {
+	ctx := context.Background()
+	accounts := auth.NewInMemoryUserRepository()
+	require.NoError(t, accounts.Create(ctx, &auth.User{ID: "usr_testuser123", AppleSub: "apple-sub"}))
+
+	source := NewMemorySource()
+	deadLetter := &MemoryDeadLetter{}
+	d := NewDispatcher(DispatcherConfig{Logger: zerolog.Nop(), DeadLetter: deadLetter}).
+		Handle(JobTypeGDPRDeletion, GDPRDeletionHandler(accounts, zerolog.Nop()))
+
+	data := []byte(`{"job_type":"gdpr_deletion","user_id":"usr_testuser123","request_id":"del_1"}`)
+	source.Publish(data, nil)
+	// A redelivery after the deletion committed
+	source.Publish(data, nil)
+	runDispatcher(t, d, source)
+
+	_, err := accounts.FindByID(ctx, "usr_testuser123")
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+	assert.Len(t, source.Acked(), 2)
+	assert.Empty(t, deadLetter.Messages())
+}
+
+func TestGDPRDeletionHandler_Errors(t *testing.T) {
+	handler := GDPRDeletionHandler(auth.NewInMemoryUserRepository(), zerolog.Nop())
+	assert.ErrorIs(t, handler(context.Background(), []byte(`{"job_type":"gdpr_deletion"}`)), ErrMalformedMessage)
+
+	failing := GDPRDeletionHandler(failingAccounts{}, zerolog.Nop())
+	err := failing(context.Background(), []byte(`{"job_type":"gdpr_deletion","user_id":"usr_testuser123"}`))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrMalformedMessage, "database errors should be retried")
+}
+
+// failingAccounts fails every deletion.
+type failingAccounts struct{}
+
+func (failingAccounts) Delete(context.Context, string) error {
+	return errors.New("connection refused")
+}
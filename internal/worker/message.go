@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Message is a job message received from a MessageSource. Every message
+// must be acknowledged with Ack, or with Nack to have it redelivered.
+type Message struct {
+	ID         string
+	Data       []byte
+	Attributes map[string]string
+
+	// DeliveryAttempt counts deliveries of the message, starting at 1. It is
+	// 0 when the source does not track attempts.
+	DeliveryAttempt int
+
+	PublishTime time.Time
+
+	ack  func()
+	nack func()
+}
+
+// Ack acknowledges the message so it is not delivered again.
+func (m *Message) Ack() {
+	if m.ack != nil {
+		m.ack()
+	}
+}
+
+// Nack rejects the message so it is delivered again later.
+func (m *Message) Nack() {
+	if m.nack != nil {
+		m.nack()
+	}
+}
+
+// MessageSource delivers job messages at least once.
+type MessageSource interface {
+	// Receive calls handle for each message until ctx is canceled or the
+	// source fails. Messages may be handled concurrently and delivered more
+	// than once.
+	Receive(ctx context.Context, handle func(ctx context.Context, msg *Message)) error
+}
+
+// DeadLetterSink keeps messages that could not be processed, for later
+// inspection or replay.
+type DeadLetterSink interface {
+	DeadLetter(ctx context.Context, msg *Message, reason error) error
+}
+
+// MemorySource is an in-memory MessageSource for tests and local runs. It
+// handles one message at a time and redelivers nacked messages with their
+// delivery attempt incremented.
+type MemorySource struct {
+	mu      sync.Mutex
+	queue   []*Message
+	nextID  int
+	acked   []string
+	notify  chan struct{}
+	pending int
+}
+
+// NewMemorySource creates an empty in-memory message source.
+func NewMemorySource() *MemorySource {
+	return &MemorySource{notify: make(chan struct{}, 1)}
+}
+
+// Publish queues a message and returns its ID.
+func (s *MemorySource) Publish(data []byte, attributes map[string]string) string {
+	s.mu.Lock()
+	s.nextID++
+	msg := &Message{
+		ID:          strconv.Itoa(s.nextID),
+		Data:        data,
+		Attributes:  attributes,
+		PublishTime: time.Now(),
+	}
+	s.mu.Unlock()
+
+	s.enqueue(msg)
+	return msg.ID
+}
+
+// enqueue adds a delivery of msg to the queue.
+func (s *MemorySource) enqueue(msg *Message) {
+	s.mu.Lock()
+	s.queue = append(s.queue, msg)
+	s.pending++
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Acked returns the IDs of acknowledged messages, in order.
+func (s *MemorySource) Acked() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.acked...)
+}
+
+// Pending returns the number of messages queued or being handled.
+func (s *MemorySource) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// Receive handles queued messages until ctx is canceled.
+func (s *MemorySource) Receive(ctx context.Context, handle func(ctx context.Context, msg *Message)) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		s.mu.Lock()
+		var queued *Message
+		if len(s.queue) > 0 {
+			queued, s.queue = s.queue[0], s.queue[1:]
+		}
+		s.mu.Unlock()
+
+		if queued == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-s.notify:
+				continue
+			}
+		}
+
+		delivery := *queued
+		delivery.DeliveryAttempt = queued.DeliveryAttempt + 1
+		var once sync.Once
+		delivery.ack = func() {
+			once.Do(func() {
+				s.mu.Lock()
+				s.acked = append(s.acked, delivery.ID)
+				s.pending--
+				s.mu.Unlock()
+			})
+		}
+		delivery.nack = func() {
+			once.Do(func() {
+				redelivery := delivery
+				s.mu.Lock()
+				s.pending--
+				s.mu.Unlock()
+				s.enqueue(&redelivery)
+			})
+		}
+		handle(ctx, &delivery)
+	}
+}
+
+// DeadLetteredMessage is a message kept by a MemoryDeadLetter.
+type DeadLetteredMessage struct {
+	Message *Message
+	Reason  error
+}
+
+// MemoryDeadLetter is an in-memory DeadLetterSink for tests.
+type MemoryDeadLetter struct {
+	mu       sync.Mutex
+	messages []DeadLetteredMessage
+}
+
+// DeadLetter keeps msg.
+func (d *MemoryDeadLetter) DeadLetter(_ context.Context, msg *Message, reason error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, DeadLetteredMessage{Message: msg, Reason: reason})
+	return nil
+}
+
+// Messages returns the dead-lettered messages, in order.
+func (d *MemoryDeadLetter) Messages() []DeadLetteredMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DeadLetteredMessage(nil), d.messages...)
+}
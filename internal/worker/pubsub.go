@@ -10,20 +10,26 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// PubSubHandler handles Pub/Sub messages for the worker.
-type PubSubHandler struct {
+// Dead letter attributes added to messages published by PubSubDeadLetter.
+const (
+	DeadLetterReasonAttribute       = "dead_letter_reason"
+	DeadLetterMessageIDAttribute    = "original_message_id"
+	DeadLetterAttemptsAttribute     = "delivery_attempts"
+	DeadLetterSubscriptionAttribute = "subscription"
+)
+
+// PubSubSource is a MessageSource that receives from a Pub/Sub subscription.
+type PubSubSource struct {
 	client           *pubsub.Client
 	subscriber       *pubsub.Subscriber
 	subscriptionName string
-	refreshJob       *RefreshJob
 	logger           zerolog.Logger
 }
 
-// PubSubConfig holds configuration for the Pub/Sub handler.
+// PubSubConfig holds configuration for the Pub/Sub source.
 type PubSubConfig struct {
 	ProjectID        string
 	SubscriptionName string
-	RefreshJob       *RefreshJob
 	Logger           zerolog.Logger
 }
 
@@ -34,8 +40,8 @@ type RefreshMessage struct {
 	CheckOnly  bool   `json:"check_only,omitempty"`
 }
 
-// NewPubSubHandler creates a new Pub/Sub handler.
-func NewPubSubHandler(ctx context.Context, cfg PubSubConfig) (*PubSubHandler, error) {
+// NewPubSubSource creates a source for a Pub/Sub subscription.
+func NewPubSubSource(ctx context.Context, cfg PubSubConfig) (*PubSubSource, error) {
 	client, err := pubsub.NewClient(ctx, cfg.ProjectID)
 	if err != nil {
 		return nil, fmt.Errorf("creating pubsub client: %w", err)
@@ -47,143 +53,169 @@ func NewPubSubHandler(ctx context.Context, cfg PubSubConfig) (*PubSubHandler, er
 	subscriber.ReceiveSettings.MaxOutstandingMessages = 10
 	subscriber.ReceiveSettings.MaxExtension = 10 * time.Minute
 
-	return &PubSubHandler{
+	return &PubSubSource{
 		client:           client,
 		subscriber:       subscriber,
 		subscriptionName: cfg.SubscriptionName,
-		refreshJob:       cfg.RefreshJob,
 		logger:           cfg.Logger,
 	}, nil
 }
 
-// Start begins processing Pub/Sub messages.
-func (h *PubSubHandler) Start(ctx context.Context) error {
-	h.logger.Info().
-		Str("subscription", h.subscriptionName).
-		Msg("starting pubsub handler")
-
-	return h.subscriber.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
-		h.handleMessage(ctx, msg)
+// Receive handles messages from the subscription until ctx is canceled.
+func (s *PubSubSource) Receive(ctx context.Context, handle func(ctx context.Context, msg *Message)) error {
+	s.logger.Info().
+		Str("subscription", s.subscriptionName).
+		Msg("starting pubsub source")
+
+	return s.subscriber.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		attempt := 0
+		if msg.DeliveryAttempt != nil {
+			// Only set when the subscription has a dead letter policy
+			attempt = *msg.DeliveryAttempt
+		}
+		handle(ctx, &Message{
+			ID:              msg.ID,
+			Data:            msg.Data,
+			Attributes:      msg.Attributes,
+			DeliveryAttempt: attempt,
+			PublishTime:     msg.PublishTime,
+			ack:             msg.Ack,
+			nack:            msg.Nack,
+		})
 	})
 }
 
 // Close closes the Pub/Sub client.
-func (h *PubSubHandler) Close() error {
-	return h.client.Close()
+func (s *PubSubSource) Close() error {
+	return s.client.Close()
 }
 
-func (h *PubSubHandler) handleMessage(ctx context.Context, msg *pubsub.Message) {
-	startTime := time.Now()
-
-	logger := h.logger.With().
-		Str("message_id", msg.ID).
-		Str("publish_time", msg.PublishTime.Format(time.RFC3339)).
-		Logger()
-
-	logger.Debug().Msg("received pubsub message")
-
-	// Parse message.
-	var refreshMsg RefreshMessage
-	if err := json.Unmarshal(msg.Data, &refreshMsg); err != nil {
-		logger.Error().Err(err).Msg("failed to parse message")
-		msg.Nack()
-		return
-	}
-
-	// Handle based on job type.
-	var err error
-	switch refreshMsg.JobType {
-	case "provider_refresh":
-		err = h.handleProviderRefresh(ctx, refreshMsg)
-	case "health_check":
-		err = h.handleHealthCheck(ctx)
-	default:
-		logger.Warn().Str("job_type", refreshMsg.JobType).Msg("unknown job type")
-		msg.Ack() // Ack unknown messages to prevent redelivery
-		return
-	}
+// PubSubDeadLetter is a DeadLetterSink that publishes messages to a Pub/Sub
+// topic, with the failure reason in their attributes.
+type PubSubDeadLetter struct {
+	client       *pubsub.Client
+	publisher    *pubsub.Publisher
+	subscription string
+}
 
+// NewPubSubDeadLetter creates a dead letter sink publishing to topic.
+// Messages are tagged with the subscription they were received from.
+func NewPubSubDeadLetter(ctx context.Context, projectID, topic, subscription string) (*PubSubDeadLetter, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
 	if err != nil {
-		logger.Error().Err(err).Msg("job failed")
-		msg.Nack()
-		return
+		return nil, fmt.Errorf("creating pubsub client: %w", err)
 	}
 
-	duration := time.Since(startTime)
-	logger.Info().
-		Str("job_type", refreshMsg.JobType).
-		Dur("duration", duration).
-		Msg("job completed successfully")
-
-	msg.Ack()
+	return &PubSubDeadLetter{
+		client:       client,
+		publisher:    client.Publisher(topic),
+		subscription: subscription,
+	}, nil
 }
 
-func (h *PubSubHandler) handleProviderRefresh(ctx context.Context, msg RefreshMessage) error {
-	h.logger.Info().
-		Bool("refresh_all", msg.RefreshAll).
-		Msg("starting provider refresh")
-
-	// Run the refresh job.
-	result := h.refreshJob.Run(ctx)
-
-	// Also refresh transit data.
-	if err := h.refreshJob.RefreshTransit(ctx); err != nil {
-		h.logger.Warn().Err(err).Msg("transit refresh failed")
+// DeadLetter publishes msg to the dead letter topic and waits until it is
+// stored.
+func (d *PubSubDeadLetter) DeadLetter(ctx context.Context, msg *Message, reason error) error {
+	attributes := make(map[string]string, len(msg.Attributes)+4)
+	for k, v := range msg.Attributes {
+		attributes[k] = v
 	}
-
-	// Log summary.
-	h.logger.Info().
-		Dur("duration", result.Duration).
-		Int("successful", result.Successful).
-		Int("failed", result.Failed).
-		Int("total_points", result.TotalPoints).
-		Msg("provider refresh completed")
-
-	// Consider it successful if more than half succeeded.
-	if result.Failed > result.Successful {
-		return fmt.Errorf("too many refresh failures: %d/%d", result.Failed, result.TotalPoints)
+	attributes[DeadLetterReasonAttribute] = reason.Error()
+	attributes[DeadLetterMessageIDAttribute] = msg.ID
+	attributes[DeadLetterAttemptsAttribute] = fmt.Sprint(msg.DeliveryAttempt)
+	attributes[DeadLetterSubscriptionAttribute] = d.subscription
+
+	result := d.publisher.Publish(ctx, &pubsub.Message{Data: msg.Data, Attributes: attributes})
+	if _, err := result.Get(ctx); err != nil {
+		return fmt.Errorf("publishing dead letter: %w", err)
 	}
-
 	return nil
 }
 
-func (h *PubSubHandler) handleHealthCheck(ctx context.Context) error {
-	h.logger.Debug().Msg("running health check")
-
-	// Just refresh a single point to verify provider connectivity.
-	testPoint := Point{Lat: 52.3676, Lon: 4.9041} // Amsterdam
+// Close flushes pending publishes and closes the Pub/Sub client.
+func (d *PubSubDeadLetter) Close() error {
+	d.publisher.Stop()
+	return d.client.Close()
+}
 
-	// Create a single-point config.
-	singlePointConfig := RefreshConfig{
-		Targets: []RefreshTarget{
-			{
-				Name:     "health-check",
-				Priority: 1,
-				Points:   []Point{testPoint},
-			},
-		},
-		Concurrency:       1,
-		Timeout:           10 * time.Second,
-		RefreshAirQuality: true,
-		RefreshWeather:    true,
-		RefreshPollen:     false, // Skip pollen for health check
-		RefreshTransit:    false, // Skip transit for health check
+// ProviderRefreshHandler returns the handler for provider_refresh jobs,
+// which refresh every target of job and transit data.
+func ProviderRefreshHandler(job *RefreshJob, logger zerolog.Logger) JobHandler {
+	return func(ctx context.Context, data []byte) error {
+		var msg RefreshMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+		}
+
+		logger.Info().
+			Bool("refresh_all", msg.RefreshAll).
+			Msg("starting provider refresh")
+
+		// Run the refresh job.
+		result := job.Run(ctx)
+
+		// Also refresh transit data.
+		if err := job.RefreshTransit(ctx); err != nil {
+			logger.Warn().Err(err).Msg("transit refresh failed")
+		}
+
+		// Log summary.
+		logger.Info().
+			Dur("duration", result.Duration).
+			Int("successful", result.Successful).
+			Int("failed", result.Failed).
+			Int("total_points", result.TotalPoints).
+			Msg("provider refresh completed")
+
+		// Consider it successful if more than half succeeded.
+		if result.Failed > result.Successful {
+			return fmt.Errorf("too many refresh failures: %d/%d", result.Failed, result.TotalPoints)
+		}
+
+		return nil
 	}
+}
 
-	// Create a temporary refresh job for health check.
-	healthCheckJob := NewRefreshJob(RefreshJobConfig{
-		Config:            singlePointConfig,
-		Logger:            h.logger,
-		AirQualityService: h.refreshJob.airQualityService,
-		WeatherService:    h.refreshJob.weatherService,
-	})
-
-	result := healthCheckJob.Run(ctx)
-
-	if result.Failed > 0 {
-		return fmt.Errorf("health check failed: %d errors", result.Failed)
+// HealthCheckHandler returns the handler for health_check jobs, which
+// refresh a single point to verify provider connectivity.
+func HealthCheckHandler(job *RefreshJob, logger zerolog.Logger) JobHandler {
+	return func(ctx context.Context, _ []byte) error {
+		logger.Debug().Msg("running health check")
+
+		testPoint := Point{Lat: 52.3676, Lon: 4.9041} // Amsterdam
+
+		// Create a single-point config.
+		singlePointConfig := RefreshConfig{
+			Targets: []RefreshTarget{
+				{
+					Name:     "health-check",
+					Priority: 1,
+					Points:   []Point{testPoint},
+				},
+			},
+			Concurrency:       1,
+			Timeout:           10 * time.Second,
+			RefreshAirQuality: true,
+			RefreshWeather:    true,
+			RefreshPollen:     false, // Skip pollen for health check
+			RefreshTransit:    false, // Skip transit for health check
+		}
+
+		// Create a temporary refresh job for health check.
+		healthCheckJob := NewRefreshJob(RefreshJobConfig{
+			Config:            singlePointConfig,
+			Logger:            logger,
+			AirQualityService: job.airQualityService,
+			WeatherService:    job.weatherService,
+		})
+
+		result := healthCheckJob.Run(ctx)
+
+		if result.Failed > 0 {
+			return fmt.Errorf("health check failed: %d errors", result.Failed)
+		}
+
+		logger.Debug().Msg("health check passed")
+		return nil
 	}
-
-	h.logger.Debug().Msg("health check passed")
-	return nil
 }
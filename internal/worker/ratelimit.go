@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited indicates a refresh gave up waiting for a provider's rate
+// limit because the wait would outlast the refresh timeout.
+var ErrRateLimited = errors.New("provider rate limit wait exceeds deadline")
+
+// providerLimiters holds one rate limiter per provider, shared by all workers.
+type providerLimiters map[string]*rate.Limiter
+
+// newProviderLimiters creates limiters for every provider with a positive limit.
+func newProviderLimiters(limits map[string]float64) providerLimiters {
+	limiters := make(providerLimiters, len(limits))
+	for provider, rps := range limits {
+		if rps <= 0 {
+			continue
+		}
+		limiters[provider] = rate.NewLimiter(rate.Limit(rps), 1)
+	}
+	return limiters
+}
+
+// wait blocks until the provider's limiter admits a request. It reports
+// whether the caller had to wait, and returns ErrRateLimited when the wait
+// would exceed the context deadline or the context is canceled while waiting.
+func (l providerLimiters) wait(ctx context.Context, provider string) (bool, error) {
+	limiter, ok := l[provider]
+	if !ok {
+		return false, nil
+	}
+
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return false, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		reservation.Cancel()
+		return true, ErrRateLimited
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return true, fmt.Errorf("%w: %w", ErrRateLimited, ctx.Err())
+	}
+}
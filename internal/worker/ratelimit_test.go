@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderLimiters_WaitsWhenExhausted(t *testing.T) {
+	limiters := newProviderLimiters(map[string]float64{ProviderWeather: 20})
+
+	waited, err := limiters.wait(context.Background(), ProviderWeather)
+	require.NoError(t, err)
+	assert.False(t, waited, "first request should use the burst token")
+
+	start := time.Now()
+	waited, err = limiters.wait(context.Background(), ProviderWeather)
+	require.NoError(t, err)
+	assert.True(t, waited)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestProviderLimiters_IndependentPerProvider(t *testing.T) {
+	limiters := newProviderLimiters(map[string]float64{
+		ProviderWeather: 0.1,
+		ProviderPollen:  0.1,
+	})
+
+	_, err := limiters.wait(context.Background(), ProviderWeather)
+	require.NoError(t, err)
+
+	// Exhausting the weather budget must not delay pollen
+	waited, err := limiters.wait(context.Background(), ProviderPollen)
+	require.NoError(t, err)
+	assert.False(t, waited)
+}
+
+func TestProviderLimiters_UnlimitedProvider(t *testing.T) {
+	limiters := newProviderLimiters(map[string]float64{ProviderTransit: 0})
+
+	for i := 0; i < 5; i++ {
+		waited, err := limiters.wait(context.Background(), ProviderTransit)
+		require.NoError(t, err)
+		assert.False(t, waited)
+	}
+}
+
+func TestProviderLimiters_DeadlineExceeded(t *testing.T) {
+	limiters := newProviderLimiters(map[string]float64{ProviderPollen: 0.1})
+
+	_, err := limiters.wait(context.Background(), ProviderPollen)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	waited, err := limiters.wait(ctx, ProviderPollen)
+	assert.True(t, waited)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "should give up without waiting out the deadline")
+}
+
+func TestNewRefreshError_RateLimited(t *testing.T) {
+	point := Point{Lat: 52.37, Lon: 4.90}
+
+	limited := newRefreshError(ProviderWeather, point, ErrRateLimited, 1)
+	assert.True(t, limited.RateLimited)
+
+	failed := newRefreshError(ProviderWeather, point, errors.New("upstream 500"), 1)
+	assert.False(t, failed.RateLimited)
+	assert.Equal(t, "upstream 500", failed.Error)
+}
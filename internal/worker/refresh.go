@@ -3,6 +3,8 @@ package worker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,10 +13,14 @@ import (
 
 	"github.com/breatheroute/breatheroute/internal/airquality"
 	"github.com/breatheroute/breatheroute/internal/pollen"
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
 	"github.com/breatheroute/breatheroute/internal/transit"
 	"github.com/breatheroute/breatheroute/internal/weather"
 )
 
+// ErrProviderTimeout indicates a provider refresh exceeded its deadline.
+var ErrProviderTimeout = errors.New("provider refresh timed out")
+
 // RefreshJob handles provider cache refresh operations.
 type RefreshJob struct {
 	config RefreshConfig
@@ -26,10 +32,28 @@ type RefreshJob struct {
 	pollenService     *pollen.Service
 	transitService    *transit.Service
 
+	// Refresh points derived from users' commutes (optional)
+	commutePoints *CommutePoints
+
+	// Per-provider rate limiters shared by all workers
+	limiters providerLimiters
+
+	// In-flight refreshes by target name, so concurrent triggers for a
+	// target share one run instead of hitting providers twice
+	flightsMu sync.Mutex
+	flights   map[string]*refreshFlight
+
 	// Metrics
 	metrics *RefreshMetrics
 }
 
+// refreshFlight is a refresh in progress. result is set before done is
+// closed.
+type refreshFlight struct {
+	done   chan struct{}
+	result *RefreshResult
+}
+
 // RefreshMetrics tracks refresh job statistics.
 type RefreshMetrics struct {
 	mu sync.RWMutex
@@ -51,6 +75,22 @@ type RefreshMetrics struct {
 	// Cache stats
 	CacheHits   int64
 	CacheMisses int64
+
+	// Rate limiting
+	RateLimitWaits       int64
+	RateLimitedRefreshes int64
+
+	// Retries counts provider calls retried after a transient error.
+	Retries int64
+
+	// Scheduling
+	SkippedRuns int64
+	NextRunAt   map[string]time.Time
+
+	// Deduplication
+	CoalescedRuns   int64
+	Running         []string
+	LastCompletedAt map[string]time.Time
 }
 
 // RefreshJobConfig holds configuration for creating a RefreshJob.
@@ -61,6 +101,10 @@ type RefreshJobConfig struct {
 	WeatherService    *weather.Service
 	PollenService     *pollen.Service
 	TransitService    *transit.Service
+
+	// CommutePoints adds a target refreshing the points along users'
+	// commutes. Without commutes only the configured targets are refreshed.
+	CommutePoints *CommutePoints
 }
 
 // NewRefreshJob creates a new refresh job processor.
@@ -77,6 +121,9 @@ func NewRefreshJob(cfg RefreshJobConfig) *RefreshJob {
 		weatherService:    cfg.WeatherService,
 		pollenService:     cfg.PollenService,
 		transitService:    cfg.TransitService,
+		commutePoints:     cfg.CommutePoints,
+		limiters:          newProviderLimiters(config.ProviderRateLimits),
+		flights:           make(map[string]*refreshFlight),
 		metrics:           &RefreshMetrics{},
 	}
 }
@@ -92,6 +139,25 @@ type RefreshResult struct {
 	Errors      []RefreshError
 	CacheHits   int
 	CacheMisses int
+
+	// DryRun is true when no services were invoked; Planned lists the
+	// provider calls that would have been made.
+	DryRun  bool
+	Planned []PlannedRefresh
+
+	// Skipped maps providers not refreshed for any point to the reason,
+	// e.g. "pollen": SkipReasonDisabled.
+	Skipped map[string]string
+}
+
+// SkipReasonDisabled is the RefreshResult.Skipped reason for a provider
+// disabled by feature flag.
+const SkipReasonDisabled = "disabled"
+
+// PlannedRefresh is a provider call skipped by a dry run.
+type PlannedRefresh struct {
+	Provider string
+	Point    Point
 }
 
 // RefreshError represents an error during refresh.
@@ -99,24 +165,197 @@ type RefreshError struct {
 	Provider string
 	Point    Point
 	Error    string
+
+	// RateLimited is true when the refresh was abandoned waiting for the
+	// provider's rate limit rather than failing upstream.
+	RateLimited bool
+
+	// TimedOut is true when the provider call exceeded its own deadline
+	// (see RefreshConfig.TimeoutFor).
+	TimedOut bool
+
+	// Attempts is the number of times the provider call was made; more
+	// than one when transient errors were retried.
+	Attempts int
+
+	// Retryable is true when the last error was transient, so the point
+	// failed because RefreshConfig.RetryAttempts ran out.
+	Retryable bool
+}
+
+// newRefreshError creates a RefreshError, flagging rate-limit waits.
+func newRefreshError(provider string, point Point, err error, attempts int) RefreshError {
+	return RefreshError{
+		Provider:    provider,
+		Point:       point,
+		Error:       err.Error(),
+		RateLimited: errors.Is(err, ErrRateLimited),
+		TimedOut:    errors.Is(err, ErrProviderTimeout),
+		Attempts:    attempts,
+		Retryable:   isRetryable(err),
+	}
 }
 
-// Run executes the refresh job for all configured targets.
+// isRetryable reports whether a provider refresh error is transient, so the
+// call may succeed if retried: a refresh timeout, or a provider failure
+// classified as transient by resilience.IsTransient, such as a 5xx or 429
+// response or a network error. Invalid coordinates, client errors, missing
+// data and abandoned rate limit waits are not.
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return false
+	}
+	return errors.Is(err, ErrProviderTimeout) || resilience.IsTransient(err)
+}
+
+// Run executes the refresh job for all configured targets, and the commute
+// target if loaded. Targets already being refreshed, by the scheduler or
+// another trigger, are not refreshed again: Run waits for their runs and
+// includes their results.
 func (j *RefreshJob) Run(ctx context.Context) *RefreshResult {
+	targets := j.config.Targets
+	if j.commutePoints != nil {
+		if target, ok := j.commutePoints.cachedTarget(); ok {
+			targets = append(append([]RefreshTarget(nil), targets...), target)
+		}
+	}
+	return j.runTargets(ctx, "all", targets)
+}
+
+// RunTarget executes the refresh job for a single target. If the target is
+// already being refreshed, it waits for that run and returns its result.
+func (j *RefreshJob) RunTarget(ctx context.Context, target RefreshTarget) *RefreshResult {
+	return j.runTargets(ctx, target.Name, []RefreshTarget{target})
+}
+
+// runTargets refreshes the targets not already in flight as one run named
+// name, and coalesces with the runs of the others.
+func (j *RefreshJob) runTargets(ctx context.Context, name string, targets []RefreshTarget) *RefreshResult {
+	flight := &refreshFlight{done: make(chan struct{})}
+	var claimed []string
+	var points []Point
+	var joined []*refreshFlight
+
+	j.flightsMu.Lock()
+	for _, target := range targets {
+		if running, ok := j.flights[target.Name]; ok {
+			if !containsFlight(joined, running) {
+				joined = append(joined, running)
+			}
+			continue
+		}
+		j.flights[target.Name] = flight
+		claimed = append(claimed, target.Name)
+		points = append(points, target.Points...)
+	}
+	j.flightsMu.Unlock()
+
+	if len(joined) > 0 {
+		atomic.AddInt64(&j.metrics.CoalescedRuns, 1)
+		j.logger.Info().
+			Str("target", name).
+			Int("coalesced", len(joined)).
+			Msg("refresh already in flight, waiting for it")
+	}
+
+	result := &RefreshResult{StartTime: time.Now()}
+	if len(claimed) > 0 {
+		result = j.runPoints(ctx, name, points)
+		flight.result = result
+		j.finishFlight(claimed, result.EndTime)
+		close(flight.done)
+	}
+
+	for _, running := range joined {
+		select {
+		case <-running.done:
+			mergeResult(result, running.result)
+		case <-ctx.Done():
+		}
+	}
+	if len(claimed) == 0 {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+	}
+	return result
+}
+
+// finishFlight removes the named targets from the in-flight set and
+// records when they completed.
+func (j *RefreshJob) finishFlight(names []string, at time.Time) {
+	j.flightsMu.Lock()
+	for _, name := range names {
+		delete(j.flights, name)
+	}
+	j.flightsMu.Unlock()
+
+	j.metrics.mu.Lock()
+	defer j.metrics.mu.Unlock()
+	if j.metrics.LastCompletedAt == nil {
+		j.metrics.LastCompletedAt = make(map[string]time.Time)
+	}
+	for _, name := range names {
+		j.metrics.LastCompletedAt[name] = at
+	}
+}
+
+// containsFlight reports whether flights includes f.
+func containsFlight(flights []*refreshFlight, f *refreshFlight) bool {
+	for _, existing := range flights {
+		if existing == f {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeResult adds the outcome of a coalesced run to result.
+func mergeResult(result, other *RefreshResult) {
+	result.TotalPoints += other.TotalPoints
+	result.Successful += other.Successful
+	result.Failed += other.Failed
+	result.CacheHits += other.CacheHits
+	result.CacheMisses += other.CacheMisses
+	result.Errors = append(result.Errors, other.Errors...)
+	result.Planned = append(result.Planned, other.Planned...)
+	result.DryRun = result.DryRun || other.DryRun
+	for provider, reason := range other.Skipped {
+		if result.Skipped == nil {
+			result.Skipped = make(map[string]string)
+		}
+		result.Skipped[provider] = reason
+	}
+	if other.EndTime.After(result.EndTime) {
+		result.EndTime = other.EndTime
+		result.Duration = result.EndTime.Sub(result.StartTime)
+	}
+}
+
+// runPoints refreshes the given points using the configured worker pool.
+func (j *RefreshJob) runPoints(ctx context.Context, name string, points []Point) *RefreshResult {
 	startTime := time.Now()
 	result := &RefreshResult{
 		StartTime:   startTime,
-		TotalPoints: j.config.TotalPoints(),
+		TotalPoints: len(points),
+		DryRun:      j.config.DryRun,
+	}
+
+	// Check the flag once rather than calling the pollen service for every
+	// point only to have it refuse
+	pollenEnabled := true
+	if j.config.RefreshPollen && j.pollenService != nil && !j.pollenService.IsEnabled(ctx) {
+		pollenEnabled = false
+		result.Skipped = map[string]string{ProviderPollen: SkipReasonDisabled}
+		j.logger.Info().Str("target", name).Msg("pollen skipped: disabled")
 	}
 
 	j.logger.Info().
+		Str("target", name).
 		Int("total_points", result.TotalPoints).
 		Int("concurrency", j.config.Concurrency).
+		Bool("dry_run", result.DryRun).
 		Msg("starting provider refresh job")
 
-	// Get all points to refresh
-	points := j.config.AllPoints()
-
 	// Create work channels
 	pointsChan := make(chan Point, len(points))
 	resultsChan := make(chan pointResult, len(points))
@@ -127,7 +366,7 @@ func (j *RefreshJob) Run(ctx context.Context) *RefreshResult {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			j.refreshWorker(ctx, workerID, pointsChan, resultsChan)
+			j.refreshWorker(ctx, workerID, pointsChan, resultsChan, pollenEnabled)
 		}(i)
 	}
 
@@ -153,15 +392,20 @@ func (j *RefreshJob) Run(ctx context.Context) *RefreshResult {
 		result.CacheHits += pr.cacheHits
 		result.CacheMisses += pr.cacheMisses
 		result.Errors = append(result.Errors, pr.errors...)
+		result.Planned = append(result.Planned, pr.planned...)
 	}
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(startTime)
 
-	// Update metrics
-	j.updateMetrics(result)
+	// Dry runs refreshed nothing, so they must not skew refresh metrics
+	if !result.DryRun {
+		j.updateMetrics(result)
+	}
 
 	j.logger.Info().
+		Str("target", name).
+		Bool("dry_run", result.DryRun).
 		Dur("duration", result.Duration).
 		Int("successful", result.Successful).
 		Int("failed", result.Failed).
@@ -178,38 +422,37 @@ type pointResult struct {
 	cacheHits   int
 	cacheMisses int
 	errors      []RefreshError
+	planned     []PlannedRefresh
 }
 
-func (j *RefreshJob) refreshWorker(ctx context.Context, _ int, points <-chan Point, results chan<- pointResult) {
+func (j *RefreshJob) refreshWorker(ctx context.Context, _ int, points <-chan Point, results chan<- pointResult, pollenEnabled bool) {
 	for point := range points {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			result := j.refreshPoint(ctx, point)
+			result := j.refreshPoint(ctx, point, pollenEnabled)
 			results <- result
 		}
 	}
 }
 
-func (j *RefreshJob) refreshPoint(ctx context.Context, point Point) pointResult {
+// refreshPoint refreshes the location-based providers for a point. Pollen
+// is skipped unless pollenEnabled.
+func (j *RefreshJob) refreshPoint(ctx context.Context, point Point, pollenEnabled bool) pointResult {
+	if j.config.DryRun {
+		return j.dryRunPoint(point, pollenEnabled)
+	}
+
 	result := pointResult{
 		point:   point,
 		success: true,
 	}
 
-	// Create timeout context for this point
-	pointCtx, cancel := context.WithTimeout(ctx, j.config.Timeout)
-	defer cancel()
-
 	// Refresh air quality
 	if j.config.RefreshAirQuality && j.airQualityService != nil {
-		if err := j.refreshAirQuality(pointCtx, point); err != nil {
-			result.errors = append(result.errors, RefreshError{
-				Provider: "airquality",
-				Point:    point,
-				Error:    err.Error(),
-			})
+		if attempts, err := j.withRetry(ctx, ProviderAirQuality, point, j.refreshAirQuality); err != nil {
+			result.errors = append(result.errors, newRefreshError(ProviderAirQuality, point, err, attempts))
 			result.success = false
 		} else {
 			result.cacheMisses++ // Successful refresh means cache was updated
@@ -219,12 +462,8 @@ func (j *RefreshJob) refreshPoint(ctx context.Context, point Point) pointResult
 
 	// Refresh weather
 	if j.config.RefreshWeather && j.weatherService != nil {
-		if err := j.refreshWeather(pointCtx, point); err != nil {
-			result.errors = append(result.errors, RefreshError{
-				Provider: "weather",
-				Point:    point,
-				Error:    err.Error(),
-			})
+		if attempts, err := j.withRetry(ctx, ProviderWeather, point, j.refreshWeather); err != nil {
+			result.errors = append(result.errors, newRefreshError(ProviderWeather, point, err, attempts))
 			result.success = false
 		} else {
 			result.cacheMisses++
@@ -233,13 +472,9 @@ func (j *RefreshJob) refreshPoint(ctx context.Context, point Point) pointResult
 	}
 
 	// Refresh pollen
-	if j.config.RefreshPollen && j.pollenService != nil {
-		if err := j.refreshPollen(pointCtx, point); err != nil {
-			result.errors = append(result.errors, RefreshError{
-				Provider: "pollen",
-				Point:    point,
-				Error:    err.Error(),
-			})
+	if j.config.RefreshPollen && j.pollenService != nil && pollenEnabled {
+		if attempts, err := j.withRetry(ctx, ProviderPollen, point, j.refreshPollen); err != nil {
+			result.errors = append(result.errors, newRefreshError(ProviderPollen, point, err, attempts))
 			// Pollen errors are non-fatal (feature flag may disable it)
 		} else {
 			result.cacheMisses++
@@ -250,22 +485,148 @@ func (j *RefreshJob) refreshPoint(ctx context.Context, point Point) pointResult
 	return result
 }
 
+// withRetry calls refresh through withProviderTimeout, retrying transient
+// errors with backoff up to RetryAttempts times. It returns the number of
+// attempts made and the last error.
+func (j *RefreshJob) withRetry(
+	ctx context.Context,
+	provider string,
+	point Point,
+	refresh func(context.Context, Point) error,
+) (int, error) {
+	maxAttempts := max(j.config.RetryAttempts, 1)
+	backoff := resilience.NewJitterBackOff(j.config.RetryBackoff, 8*j.config.RetryBackoff, 2)
+
+	for attempt := 1; ; attempt++ {
+		err := j.withProviderTimeout(ctx, provider, point, refresh)
+		if err == nil || attempt >= maxAttempts || !isRetryable(err) {
+			return attempt, err
+		}
+
+		delay := backoff.NextBackOff()
+		j.logger.Debug().
+			Err(err).
+			Str("provider", provider).
+			Int("attempt", attempt).
+			Dur("backoff", delay).
+			Msg("retrying provider refresh")
+		atomic.AddInt64(&j.metrics.Retries, 1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+}
+
+// withProviderTimeout runs a provider refresh under that provider's own
+// deadline. Failures caused by the deadline, rather than by ctx ending, are
+// wrapped in ErrProviderTimeout so the error records which provider was slow.
+func (j *RefreshJob) withProviderTimeout(
+	ctx context.Context,
+	provider string,
+	point Point,
+	refresh func(context.Context, Point) error,
+) error {
+	timeout := j.config.TimeoutFor(provider)
+	providerCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := refresh(providerCtx, point)
+	if err != nil && ctx.Err() == nil && errors.Is(providerCtx.Err(), context.DeadlineExceeded) {
+		j.logger.Warn().
+			Str("provider", provider).
+			Dur("timeout", timeout).
+			Msg("provider refresh timed out")
+		return fmt.Errorf("%w: %s after %s: %v", ErrProviderTimeout, provider, timeout, err)
+	}
+	return err
+}
+
+// dryRunPoint logs the provider calls refreshPoint would make for a point
+// and reports them as successful without invoking any service or rate limiter.
+func (j *RefreshJob) dryRunPoint(point Point, pollenEnabled bool) pointResult {
+	result := pointResult{
+		point:   point,
+		success: true,
+	}
+
+	for _, provider := range j.pointProviders(pollenEnabled) {
+		j.logger.Info().
+			Str("provider", provider).
+			Float64("lat", point.Lat).
+			Float64("lon", point.Lon).
+			Msg("dry run: would refresh provider")
+		result.planned = append(result.planned, PlannedRefresh{Provider: provider, Point: point})
+	}
+
+	return result
+}
+
+// pointProviders returns the location-based providers refreshPoint calls,
+// in call order.
+func (j *RefreshJob) pointProviders(pollenEnabled bool) []string {
+	var providers []string
+	if j.config.RefreshAirQuality && j.airQualityService != nil {
+		providers = append(providers, ProviderAirQuality)
+	}
+	if j.config.RefreshWeather && j.weatherService != nil {
+		providers = append(providers, ProviderWeather)
+	}
+	if j.config.RefreshPollen && j.pollenService != nil && pollenEnabled {
+		providers = append(providers, ProviderPollen)
+	}
+	return providers
+}
+
+// waitForProvider blocks until the provider's rate limit admits a request.
+func (j *RefreshJob) waitForProvider(ctx context.Context, provider string) error {
+	waited, err := j.limiters.wait(ctx, provider)
+	if waited {
+		atomic.AddInt64(&j.metrics.RateLimitWaits, 1)
+	}
+	if err != nil {
+		atomic.AddInt64(&j.metrics.RateLimitedRefreshes, 1)
+		j.logger.Warn().Str("provider", provider).Msg("refresh abandoned waiting for provider rate limit")
+	}
+	return err
+}
+
 func (j *RefreshJob) refreshAirQuality(ctx context.Context, _ Point) error {
 	// Air quality data is station-based, so we just refresh the snapshot
-	// which triggers a fetch from the provider if cache is stale
+	// which triggers a fetch from the provider if cache is stale. Only that
+	// fetch counts against the rate limit; points served from the fresh
+	// snapshot do not wait.
+	if status := j.airQualityService.CacheStatus(); !status.HasData || status.IsExpired {
+		if err := j.waitForProvider(ctx, ProviderAirQuality); err != nil {
+			return err
+		}
+	}
+
 	_, err := j.airQualityService.GetSnapshot(ctx)
 	return err
 }
 
 func (j *RefreshJob) refreshWeather(ctx context.Context, point Point) error {
+	if err := j.waitForProvider(ctx, ProviderWeather); err != nil {
+		return err
+	}
+
 	_, err := j.weatherService.GetCurrentWeather(ctx, point.Lat, point.Lon)
 	return err
 }
 
 func (j *RefreshJob) refreshPollen(ctx context.Context, point Point) error {
+	if err := j.waitForProvider(ctx, ProviderPollen); err != nil {
+		return err
+	}
+
 	_, err := j.pollenService.GetRegionalPollen(ctx, point.Lat, point.Lon)
 	if errors.Is(err, pollen.ErrPollenDisabled) {
-		// Not an error if pollen is disabled by feature flag
+		// Not an error if the feature flag was set during the run
 		return nil
 	}
 	return err
@@ -278,8 +639,17 @@ func (j *RefreshJob) RefreshTransit(ctx context.Context) error {
 		return nil
 	}
 
+	if j.config.DryRun {
+		j.logger.Info().Str("provider", ProviderTransit).Msg("dry run: would refresh provider")
+		return nil
+	}
+
 	j.logger.Debug().Msg("refreshing transit disruptions")
 
+	if err := j.waitForProvider(ctx, ProviderTransit); err != nil {
+		return err
+	}
+
 	_, err := j.transitService.GetAllDisruptions(ctx)
 	if err != nil {
 		j.logger.Error().Err(err).Msg("failed to refresh transit disruptions")
@@ -304,42 +674,135 @@ func (j *RefreshJob) updateMetrics(result *RefreshResult) {
 	j.metrics.CacheMisses += int64(result.CacheMisses)
 }
 
+// recordSkippedRun counts a scheduled run skipped because the previous run was still in flight.
+func (j *RefreshJob) recordSkippedRun() {
+	atomic.AddInt64(&j.metrics.SkippedRuns, 1)
+}
+
+// recordNextRun records when the scheduler will next refresh the named target.
+func (j *RefreshJob) recordNextRun(name string, at time.Time) {
+	j.metrics.mu.Lock()
+	defer j.metrics.mu.Unlock()
+
+	if j.metrics.NextRunAt == nil {
+		j.metrics.NextRunAt = make(map[string]time.Time)
+	}
+	j.metrics.NextRunAt[name] = at
+}
+
 // GetMetrics returns a copy of the current metrics.
 func (j *RefreshJob) GetMetrics() RefreshMetrics {
 	j.metrics.mu.RLock()
 	defer j.metrics.mu.RUnlock()
 
+	var nextRunAt map[string]time.Time
+	if j.metrics.NextRunAt != nil {
+		nextRunAt = make(map[string]time.Time, len(j.metrics.NextRunAt))
+		for name, at := range j.metrics.NextRunAt {
+			nextRunAt[name] = at
+		}
+	}
+	var lastCompletedAt map[string]time.Time
+	if j.metrics.LastCompletedAt != nil {
+		lastCompletedAt = make(map[string]time.Time, len(j.metrics.LastCompletedAt))
+		for name, at := range j.metrics.LastCompletedAt {
+			lastCompletedAt[name] = at
+		}
+	}
+
 	return RefreshMetrics{
-		TotalRefreshes:      j.metrics.TotalRefreshes,
-		SuccessfulRefresh:   j.metrics.SuccessfulRefresh,
-		FailedRefreshes:     j.metrics.FailedRefreshes,
-		AirQualityRefresh:   j.metrics.AirQualityRefresh,
-		WeatherRefresh:      j.metrics.WeatherRefresh,
-		PollenRefresh:       j.metrics.PollenRefresh,
-		TransitRefresh:      j.metrics.TransitRefresh,
-		LastRefreshAt:       j.metrics.LastRefreshAt,
-		LastRefreshDuration: j.metrics.LastRefreshDuration,
-		TotalDuration:       j.metrics.TotalDuration,
-		CacheHits:           j.metrics.CacheHits,
-		CacheMisses:         j.metrics.CacheMisses,
+		TotalRefreshes:       j.metrics.TotalRefreshes,
+		SuccessfulRefresh:    j.metrics.SuccessfulRefresh,
+		FailedRefreshes:      j.metrics.FailedRefreshes,
+		AirQualityRefresh:    j.metrics.AirQualityRefresh,
+		WeatherRefresh:       j.metrics.WeatherRefresh,
+		PollenRefresh:        j.metrics.PollenRefresh,
+		TransitRefresh:       j.metrics.TransitRefresh,
+		LastRefreshAt:        j.metrics.LastRefreshAt,
+		LastRefreshDuration:  j.metrics.LastRefreshDuration,
+		TotalDuration:        j.metrics.TotalDuration,
+		CacheHits:            j.metrics.CacheHits,
+		CacheMisses:          j.metrics.CacheMisses,
+		RateLimitWaits:       atomic.LoadInt64(&j.metrics.RateLimitWaits),
+		RateLimitedRefreshes: atomic.LoadInt64(&j.metrics.RateLimitedRefreshes),
+		Retries:              atomic.LoadInt64(&j.metrics.Retries),
+		SkippedRuns:          atomic.LoadInt64(&j.metrics.SkippedRuns),
+		NextRunAt:            nextRunAt,
+		CoalescedRuns:        atomic.LoadInt64(&j.metrics.CoalescedRuns),
+		Running:              j.running(),
+		LastCompletedAt:      lastCompletedAt,
+	}
+}
+
+// running returns the names of the targets being refreshed, sorted.
+func (j *RefreshJob) running() []string {
+	j.flightsMu.Lock()
+	defer j.flightsMu.Unlock()
+
+	names := make([]string, 0, len(j.flights))
+	for name := range j.flights {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
 
 // MetricsSnapshot returns a snapshot of the current metrics as a map.
 func (j *RefreshJob) MetricsSnapshot() map[string]interface{} {
 	m := j.GetMetrics()
 	return map[string]interface{}{
-		"total_refreshes":       m.TotalRefreshes,
-		"successful_refreshes":  m.SuccessfulRefresh,
-		"failed_refreshes":      m.FailedRefreshes,
-		"airquality_refreshes":  m.AirQualityRefresh,
-		"weather_refreshes":     m.WeatherRefresh,
-		"pollen_refreshes":      m.PollenRefresh,
-		"transit_refreshes":     m.TransitRefresh,
-		"last_refresh_at":       m.LastRefreshAt,
-		"last_refresh_duration": m.LastRefreshDuration.String(),
-		"total_duration":        m.TotalDuration.String(),
-		"cache_hits":            m.CacheHits,
-		"cache_misses":          m.CacheMisses,
+		"total_refreshes":        m.TotalRefreshes,
+		"successful_refreshes":   m.SuccessfulRefresh,
+		"failed_refreshes":       m.FailedRefreshes,
+		"airquality_refreshes":   m.AirQualityRefresh,
+		"weather_refreshes":      m.WeatherRefresh,
+		"pollen_refreshes":       m.PollenRefresh,
+		"transit_refreshes":      m.TransitRefresh,
+		"last_refresh_at":        m.LastRefreshAt,
+		"last_refresh_duration":  m.LastRefreshDuration.String(),
+		"total_duration":         m.TotalDuration.String(),
+		"cache_hits":             m.CacheHits,
+		"cache_misses":           m.CacheMisses,
+		"rate_limit_waits":       m.RateLimitWaits,
+		"rate_limited_refreshes": m.RateLimitedRefreshes,
+		"retries":                m.Retries,
+		"skipped_runs":           m.SkippedRuns,
+		"next_run_at":            m.NextRunAt,
+		"coalesced_runs":         m.CoalescedRuns,
+		"running":                m.Running,
+		"last_completed_at":      m.LastCompletedAt,
+		"service_caches":         j.serviceCacheStats(),
+	}
+}
+
+// serviceCacheStats reports the cumulative hit ratio of each configured
+// service cache, and evictions for bounded caches, keyed by provider domain.
+func (j *RefreshJob) serviceCacheStats() map[string]interface{} {
+	stats := make(map[string]interface{})
+	if j.weatherService != nil {
+		s := j.weatherService.CacheStats()
+		entry := cacheStatsEntry(s.Hits, s.Misses, s.HitRatio())
+		entry["evictions"] = s.Evictions
+		stats[ProviderWeather] = entry
+	}
+	if j.pollenService != nil {
+		s := j.pollenService.CacheStats()
+		entry := cacheStatsEntry(s.Hits, s.Misses, s.HitRatio())
+		entry["evictions"] = s.Evictions
+		stats[ProviderPollen] = entry
+	}
+	if j.transitService != nil {
+		s := j.transitService.CacheStats()
+		stats[ProviderTransit] = cacheStatsEntry(s.Hits, s.Misses, s.HitRatio())
+	}
+	return stats
+}
+
+// cacheStatsEntry formats a service's cache counters for MetricsSnapshot.
+func cacheStatsEntry(hits, misses uint64, hitRatio float64) map[string]interface{} {
+	return map[string]interface{}{
+		"hits":      hits,
+		"misses":    misses,
+		"hit_ratio": hitRatio,
 	}
 }
@@ -3,6 +3,7 @@ package worker_test
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -10,6 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/breatheroute/breatheroute/internal/airquality"
+	"github.com/breatheroute/breatheroute/internal/featureflags"
+	"github.com/breatheroute/breatheroute/internal/pollen"
+	"github.com/breatheroute/breatheroute/internal/provider/resilience"
+	"github.com/breatheroute/breatheroute/internal/weather"
 	"github.com/breatheroute/breatheroute/internal/worker"
 )
 
@@ -18,6 +24,7 @@ func TestDefaultRefreshConfig(t *testing.T) {
 
 	assert.Equal(t, 3, cfg.Concurrency)
 	assert.Equal(t, 30*time.Second, cfg.Timeout)
+	assert.Equal(t, 3, cfg.RetryAttempts)
 	assert.True(t, cfg.RefreshAirQuality)
 	assert.True(t, cfg.RefreshWeather)
 	assert.True(t, cfg.RefreshPollen)
@@ -395,3 +402,388 @@ func TestRefreshJob_ErrorCollection(t *testing.T) {
 	assert.Equal(t, "test", refreshErr.Provider)
 	assert.Equal(t, "test error", refreshErr.Error)
 }
+
+// countingAQProvider counts snapshot fetches so tests can assert it is never called.
+type countingAQProvider struct {
+	calls atomic.Int32
+}
+
+func (p *countingAQProvider) FetchSnapshot(_ context.Context) (*airquality.AQSnapshot, error) {
+	p.calls.Add(1)
+	return airquality.NewAQSnapshot("counting"), nil
+}
+
+func (p *countingAQProvider) FetchStations(_ context.Context) ([]*airquality.Station, error) {
+	p.calls.Add(1)
+	return nil, nil
+}
+
+func (p *countingAQProvider) FetchLatestMeasurements(_ context.Context) ([]*airquality.Measurement, error) {
+	p.calls.Add(1)
+	return nil, nil
+}
+
+func TestRefreshJob_Run_DryRun(t *testing.T) {
+	provider := &countingAQProvider{}
+	aqService := airquality.NewService(airquality.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+
+	points := []worker.Point{{Lat: 52.37, Lon: 4.90}, {Lat: 51.92, Lon: 4.48}, {Lat: 52.09, Lon: 5.12}}
+	job := worker.NewRefreshJob(worker.RefreshJobConfig{
+		Config: worker.RefreshConfig{
+			Targets:           []worker.RefreshTarget{{Name: "Staging", Points: points}},
+			Concurrency:       2,
+			Timeout:           time.Second,
+			RefreshAirQuality: true,
+			RefreshWeather:    true, // No weather service, so not planned
+			DryRun:            true,
+		},
+		Logger:            zerolog.Nop(),
+		AirQualityService: aqService,
+	})
+
+	result := job.Run(context.Background())
+
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 3, result.Successful)
+	assert.Zero(t, result.Failed)
+	require.Len(t, result.Planned, 3)
+	for _, planned := range result.Planned {
+		assert.Equal(t, worker.ProviderAirQuality, planned.Provider)
+		assert.Contains(t, points, planned.Point)
+	}
+
+	assert.Zero(t, provider.calls.Load(), "dry run must not call providers")
+	assert.False(t, aqService.CacheStatus().HasData, "dry run must not populate caches")
+
+	metrics := job.GetMetrics()
+	assert.Zero(t, metrics.TotalRefreshes)
+	assert.Zero(t, metrics.AirQualityRefresh)
+}
+
+func TestRefreshJob_Run_FreshAirQualityNotRateLimited(t *testing.T) {
+	provider := &countingAQProvider{}
+	aqService := airquality.NewService(airquality.ServiceConfig{
+		Provider: provider,
+		Logger:   zerolog.Nop(),
+	})
+	require.NoError(t, aqService.WarmCache(context.Background()))
+
+	points := []worker.Point{{Lat: 52.37, Lon: 4.90}, {Lat: 51.92, Lon: 4.48}, {Lat: 52.09, Lon: 5.12}}
+	job := worker.NewRefreshJob(worker.RefreshJobConfig{
+		Config: worker.RefreshConfig{
+			Targets:            []worker.RefreshTarget{{Name: "Randstad", Points: points}},
+			Concurrency:        1,
+			Timeout:            time.Second,
+			RefreshAirQuality:  true,
+			ProviderRateLimits: map[string]float64{worker.ProviderAirQuality: 0.1},
+		},
+		Logger:            zerolog.Nop(),
+		AirQualityService: aqService,
+	})
+
+	result := job.Run(context.Background())
+
+	// Every point is served from the warm snapshot, so none waits for the
+	// provider's budget
+	assert.Equal(t, 3, result.Successful)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, int32(1), provider.calls.Load())
+	assert.Zero(t, job.GetMetrics().RateLimitWaits)
+}
+
+func TestRefreshConfig_TimeoutFor(t *testing.T) {
+	cfg := worker.RefreshConfig{
+		Timeout: 30 * time.Second,
+		TimeoutByProvider: map[string]time.Duration{
+			worker.ProviderPollen:  time.Minute,
+			worker.ProviderWeather: 0, // Non-positive overrides are ignored
+		},
+	}
+
+	assert.Equal(t, time.Minute, cfg.TimeoutFor(worker.ProviderPollen))
+	assert.Equal(t, 30*time.Second, cfg.TimeoutFor(worker.ProviderWeather))
+	assert.Equal(t, 30*time.Second, cfg.TimeoutFor(worker.ProviderAirQuality))
+}
+
+// slowWeatherProvider blocks until the request context ends.
+type slowWeatherProvider struct{}
+
+func (p *slowWeatherProvider) GetCurrentWeather(ctx context.Context, _, _ float64) (*weather.Observation, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (p *slowWeatherProvider) GetForecast(ctx context.Context, _, _ float64) (*weather.Forecast, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (p *slowWeatherProvider) Name() string {
+	return "slow-weather"
+}
+
+func TestRefreshJob_Run_TimeoutByProvider(t *testing.T) {
+	job := worker.NewRefreshJob(worker.RefreshJobConfig{
+		Config: worker.RefreshConfig{
+			Targets:     []worker.RefreshTarget{{Name: "Test", Points: []worker.Point{{Lat: 52.37, Lon: 4.90}}}},
+			Concurrency: 1,
+			Timeout:     5 * time.Second,
+			TimeoutByProvider: map[string]time.Duration{
+				worker.ProviderWeather: 20 * time.Millisecond,
+			},
+			RefreshAirQuality: true,
+			RefreshWeather:    true,
+		},
+		Logger: zerolog.Nop(),
+		AirQualityService: airquality.NewService(airquality.ServiceConfig{
+			Provider: &countingAQProvider{},
+			Logger:   zerolog.Nop(),
+		}),
+		WeatherService: weather.NewService(weather.ServiceConfig{
+			Provider: &slowWeatherProvider{},
+			Logger:   zerolog.Nop(),
+		}),
+	})
+
+	start := time.Now()
+	result := job.Run(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second, "weather should use its own short deadline")
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, worker.ProviderWeather, result.Errors[0].Provider)
+	assert.True(t, result.Errors[0].TimedOut)
+	assert.False(t, result.Errors[0].RateLimited)
+
+	// Air quality had the global deadline and succeeded
+	assert.Equal(t, int64(1), job.GetMetrics().AirQualityRefresh)
+}
+
+// gatedWeatherProvider counts fetches and blocks them until released.
+type gatedWeatherProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (p *gatedWeatherProvider) GetCurrentWeather(ctx context.Context, _, _ float64) (*weather.Observation, error) {
+	p.calls.Add(1)
+	select {
+	case <-p.release:
+		return &weather.Observation{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *gatedWeatherProvider) GetForecast(_ context.Context, _, _ float64) (*weather.Forecast, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *gatedWeatherProvider) Name() string {
+	return "gated-weather"
+}
+
+func TestRefreshJob_Run_CoalescesConcurrentRuns(t *testing.T) {
+	provider := &gatedWeatherProvider{release: make(chan struct{})}
+	job := worker.NewRefreshJob(worker.RefreshJobConfig{
+		Config: worker.RefreshConfig{
+			Targets:        []worker.RefreshTarget{{Name: "Amsterdam", Points: []worker.Point{{Lat: 52.37, Lon: 4.90}}}},
+			Concurrency:    1,
+			Timeout:        5 * time.Second,
+			RefreshWeather: true,
+		},
+		Logger: zerolog.Nop(),
+		WeatherService: weather.NewService(weather.ServiceConfig{
+			Provider: provider,
+			Logger:   zerolog.Nop(),
+		}),
+	})
+
+	// A scheduled refresh is in flight when a message triggers another
+	results := make(chan *worker.RefreshResult, 2)
+	go func() { results <- job.Run(context.Background()) }()
+	require.Eventually(t, func() bool { return provider.calls.Load() == 1 }, time.Second, time.Millisecond)
+	go func() { results <- job.Run(context.Background()) }()
+	require.Eventually(t, func() bool { return job.GetMetrics().CoalescedRuns == 1 }, time.Second, time.Millisecond)
+
+	assert.Equal(t, []string{"Amsterdam"}, job.GetMetrics().Running)
+	close(provider.release)
+
+	for range 2 {
+		result := <-results
+		assert.Equal(t, 1, result.Successful, "both callers should get the shared result")
+	}
+	assert.Equal(t, int32(1), provider.calls.Load(), "provider should be hit once")
+
+	metrics := job.GetMetrics()
+	assert.Empty(t, metrics.Running)
+	assert.Contains(t, metrics.LastCompletedAt, "Amsterdam")
+	assert.Equal(t, int64(1), metrics.TotalRefreshes)
+}
+
+// countingPollenProvider counts regional pollen fetches.
+type countingPollenProvider struct {
+	calls atomic.Int32
+}
+
+func (p *countingPollenProvider) GetRegionalPollen(_ context.Context, _, _ float64) (*pollen.RegionalPollen, error) {
+	p.calls.Add(1)
+	return &pollen.RegionalPollen{}, nil
+}
+
+func (p *countingPollenProvider) GetForecast(_ context.Context, _, _ float64) (*pollen.Forecast, error) {
+	p.calls.Add(1)
+	return &pollen.Forecast{}, nil
+}
+
+func (p *countingPollenProvider) Name() string {
+	return "counting-pollen"
+}
+
+func TestRefreshJob_Run_PollenDisabled(t *testing.T) {
+	provider := &countingPollenProvider{}
+	// The key as seeded in the database (migrations 005 and 025), not the
+	// constant, so the two cannot drift apart unnoticed
+	const seededKey = "pollen_factor_disabled"
+	flags := featureflags.NewService(featureflags.ServiceConfig{
+		Repository: featureflags.NewInMemoryRepositoryWithFlags(map[string]*featureflags.Flag{
+			seededKey: {Key: seededKey, Value: true},
+		}),
+		Logger: zerolog.Nop(),
+	})
+
+	job := worker.NewRefreshJob(worker.RefreshJobConfig{
+		Config: worker.RefreshConfig{
+			Targets: []worker.RefreshTarget{{Name: "Randstad", Points: []worker.Point{
+				{Lat: 52.37, Lon: 4.90}, {Lat: 51.92, Lon: 4.48}, {Lat: 52.09, Lon: 5.12},
+			}}},
+			Concurrency:   2,
+			Timeout:       time.Second,
+			RefreshPollen: true,
+		},
+		Logger: zerolog.Nop(),
+		PollenService: pollen.NewService(pollen.ServiceConfig{
+			Provider:     provider,
+			FeatureFlags: flags,
+			Logger:       zerolog.Nop(),
+		}),
+	})
+
+	result := job.Run(context.Background())
+
+	assert.Zero(t, provider.calls.Load(), "pollen provider must not be called")
+	assert.Equal(t, map[string]string{worker.ProviderPollen: worker.SkipReasonDisabled}, result.Skipped)
+	assert.Equal(t, 3, result.Successful)
+	assert.Empty(t, result.Errors)
+	assert.Zero(t, job.GetMetrics().PollenRefresh)
+}
+
+// flakyWeatherProvider fails its first failures calls with err, or a 503
+// if err is nil.
+type flakyWeatherProvider struct {
+	calls    atomic.Int32
+	failures int32
+	err      error
+}
+
+func (p *flakyWeatherProvider) GetCurrentWeather(_ context.Context, lat, lon float64) (*weather.Observation, error) {
+	if p.calls.Add(1) <= p.failures {
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, &resilience.StatusError{StatusCode: 503}
+	}
+	return &weather.Observation{Lat: lat, Lon: lon}, nil
+}
+
+func (p *flakyWeatherProvider) GetForecast(_ context.Context, _, _ float64) (*weather.Forecast, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *flakyWeatherProvider) Name() string {
+	return "flaky-weather"
+}
+
+func newRetryTestJob(provider weather.Provider, points ...worker.Point) *worker.RefreshJob {
+	return worker.NewRefreshJob(worker.RefreshJobConfig{
+		Config: worker.RefreshConfig{
+			Targets:        []worker.RefreshTarget{{Name: "Test", Points: points}},
+			Concurrency:    1,
+			Timeout:        time.Second,
+			RetryAttempts:  3,
+			RetryBackoff:   time.Millisecond,
+			RefreshWeather: true,
+		},
+		Logger: zerolog.Nop(),
+		WeatherService: weather.NewService(weather.ServiceConfig{
+			Provider: provider,
+			Logger:   zerolog.Nop(),
+		}),
+	})
+}
+
+func TestRefreshJob_Run_RetriesTransientErrors(t *testing.T) {
+	provider := &flakyWeatherProvider{failures: 1}
+	job := newRetryTestJob(provider, worker.Point{Lat: 52.37, Lon: 4.90})
+
+	result := job.Run(context.Background())
+
+	assert.Equal(t, 1, result.Successful, "blip should be recovered within the run")
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, int32(2), provider.calls.Load())
+	assert.Equal(t, int64(1), job.GetMetrics().Retries)
+}
+
+func TestRefreshJob_Run_RetriesExhausted(t *testing.T) {
+	provider := &flakyWeatherProvider{failures: 10}
+	job := newRetryTestJob(provider, worker.Point{Lat: 52.37, Lon: 4.90})
+
+	result := job.Run(context.Background())
+
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Attempts)
+	assert.True(t, result.Errors[0].Retryable)
+	assert.Equal(t, int32(3), provider.calls.Load())
+}
+
+func TestRefreshJob_Run_PermanentProviderErrorsNotRetried(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"client error", &resilience.StatusError{StatusCode: 401}},
+		{"decoding error", errors.New("decoding response: unexpected EOF")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &flakyWeatherProvider{failures: 10, err: tt.err}
+			job := newRetryTestJob(provider, worker.Point{Lat: 52.37, Lon: 4.90})
+
+			result := job.Run(context.Background())
+
+			require.Len(t, result.Errors, 1)
+			assert.Equal(t, 1, result.Errors[0].Attempts)
+			assert.False(t, result.Errors[0].Retryable)
+			assert.Equal(t, int32(1), provider.calls.Load())
+			assert.Zero(t, job.GetMetrics().Retries)
+		})
+	}
+}
+
+func TestRefreshJob_Run_InvalidCoordinatesFailFast(t *testing.T) {
+	provider := &flakyWeatherProvider{}
+	job := newRetryTestJob(provider, worker.Point{Lat: 95, Lon: 4.90})
+
+	result := job.Run(context.Background())
+
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 1, result.Errors[0].Attempts)
+	assert.False(t, result.Errors[0].Retryable)
+	assert.Zero(t, provider.calls.Load())
+	assert.Zero(t, job.GetMetrics().Retries)
+}
@@ -0,0 +1,229 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultSchedulerTick is how often the scheduler checks for targets that are due.
+const DefaultSchedulerTick = 30 * time.Second
+
+// drainCancelGrace is how long WaitForCompletion waits for runs to return
+// after canceling them at the drain timeout.
+const drainCancelGrace = time.Second
+
+// transitScheduleName is the schedule entry name used for transit disruption refreshes.
+const transitScheduleName = "transit"
+
+// SchedulerConfig holds configuration for creating a Scheduler.
+type SchedulerConfig struct {
+	// Job is the refresh job that performs the refreshes.
+	Job *RefreshJob
+
+	// Exposure estimates commute trip exposure on its own interval (optional).
+	Exposure *ExposureJob
+
+	// Logger for scheduler operations.
+	Logger zerolog.Logger
+
+	// Tick is how often due targets are checked (default: 30 seconds).
+	// Intervals are effectively rounded up to a multiple of the tick.
+	Tick time.Duration
+}
+
+// Scheduler runs refresh targets on per-priority intervals.
+// A target whose previous run is still in flight is skipped until its next interval.
+type Scheduler struct {
+	job     *RefreshJob
+	logger  zerolog.Logger
+	tick    time.Duration
+	entries []*scheduleEntry
+	wg      sync.WaitGroup
+
+	// runCtx is passed to runs started by Run. It outlives Run's context so
+	// shutdown can drain in-flight runs; WaitForCompletion cancels it.
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+}
+
+// scheduleEntry tracks the schedule of a single refresh target.
+type scheduleEntry struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context)
+
+	// nextRun is only accessed from the scheduling goroutine.
+	nextRun time.Time
+	running atomic.Bool
+}
+
+// NewScheduler creates a scheduler for the job's configured targets.
+// The commute target, if configured, and transit disruptions, when enabled,
+// are refreshed on the priority 1 interval.
+// The exposure job, if configured, runs on its own interval.
+func NewScheduler(cfg SchedulerConfig) *Scheduler {
+	tick := cfg.Tick
+	if tick == 0 {
+		tick = DefaultSchedulerTick
+	}
+
+	s := &Scheduler{
+		job:    cfg.Job,
+		logger: cfg.Logger,
+		tick:   tick,
+	}
+	s.runCtx, s.cancelRuns = context.WithCancel(context.Background())
+
+	for _, target := range cfg.Job.config.Targets {
+		s.entries = append(s.entries, &scheduleEntry{
+			name:     target.Name,
+			interval: cfg.Job.config.IntervalFor(target.Priority),
+			run: func(ctx context.Context) {
+				cfg.Job.RunTarget(ctx, target)
+			},
+		})
+	}
+
+	if cfg.Job.commutePoints != nil {
+		s.entries = append(s.entries, &scheduleEntry{
+			name:     CommuteTargetName,
+			interval: cfg.Job.config.IntervalFor(1),
+			run: func(ctx context.Context) {
+				if target, ok := cfg.Job.commutePoints.Target(ctx); ok {
+					cfg.Job.RunTarget(ctx, target)
+				}
+			},
+		})
+	}
+
+	if cfg.Job.config.RefreshTransit && cfg.Job.transitService != nil {
+		s.entries = append(s.entries, &scheduleEntry{
+			name:     transitScheduleName,
+			interval: cfg.Job.config.IntervalFor(1),
+			run: func(ctx context.Context) {
+				_ = cfg.Job.RefreshTransit(ctx)
+			},
+		})
+	}
+
+	if cfg.Exposure != nil {
+		s.entries = append(s.entries, &scheduleEntry{
+			name:     exposureScheduleName,
+			interval: cfg.Exposure.interval,
+			run: func(ctx context.Context) {
+				cfg.Exposure.Run(ctx)
+			},
+		})
+	}
+
+	return s
+}
+
+// Run starts the scheduler and blocks until ctx is canceled.
+// All targets are due immediately. Canceling ctx stops new runs but not
+// those in flight, so a refresh is not cut off halfway; call
+// WaitForCompletion after Run returns to drain them.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.Info().
+		Int("targets", len(s.entries)).
+		Dur("tick", s.tick).
+		Msg("refresh scheduler started")
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	s.runDue(s.runCtx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().
+				Strs("in_flight", s.inFlight()).
+				Msg("refresh scheduler stopped scheduling")
+			return
+		case now := <-ticker.C:
+			s.runDue(s.runCtx, now)
+		}
+	}
+}
+
+// WaitForCompletion waits up to timeout for in-flight runs to finish, so
+// their results and snapshots are written. If the timeout expires, it logs
+// the runs still in flight, cancels them and gives them a short grace
+// period to return. It then logs the final metrics and reports whether
+// every run completed. Call it once, after Run has returned.
+func (s *Scheduler) WaitForCompletion(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	completed := true
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		completed = false
+		s.logger.Warn().
+			Strs("in_flight", s.inFlight()).
+			Dur("timeout", timeout).
+			Msg("refresh drain timed out, canceling in-flight runs")
+		s.cancelRuns()
+		select {
+		case <-done:
+		case <-time.After(drainCancelGrace):
+			s.logger.Error().
+				Strs("in_flight", s.inFlight()).
+				Msg("in-flight runs did not stop after cancel")
+		}
+	}
+	s.cancelRuns()
+
+	s.logger.Info().
+		Interface("metrics", s.job.MetricsSnapshot()).
+		Bool("drained", completed).
+		Msg("refresh scheduler stopped")
+	return completed
+}
+
+// inFlight returns the names of the targets currently running.
+func (s *Scheduler) inFlight() []string {
+	var names []string
+	for _, entry := range s.entries {
+		if entry.running.Load() {
+			names = append(names, entry.name)
+		}
+	}
+	return names
+}
+
+// runDue starts every target whose next run time has passed.
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	for _, entry := range s.entries {
+		if now.Before(entry.nextRun) {
+			continue
+		}
+
+		entry.nextRun = now.Add(entry.interval)
+		s.job.recordNextRun(entry.name, entry.nextRun)
+
+		if !entry.running.CompareAndSwap(false, true) {
+			s.job.recordSkippedRun()
+			s.logger.Warn().
+				Str("target", entry.name).
+				Time("next_run_at", entry.nextRun).
+				Msg("skipping refresh, previous run still in flight")
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(entry *scheduleEntry) {
+			defer s.wg.Done()
+			defer entry.running.Store(false)
+			entry.run(ctx)
+		}(entry)
+	}
+}
@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduler(t *testing.T) *Scheduler {
+	t.Helper()
+
+	cfg := DefaultRefreshConfig()
+	cfg.Targets = []RefreshTarget{
+		{Name: "Amsterdam", Priority: 1, Points: []Point{{Lat: 52.3676, Lon: 4.9041}}},
+		{Name: "Leiden", Priority: 3, Points: []Point{{Lat: 52.1664, Lon: 4.4819}}},
+	}
+
+	job := NewRefreshJob(RefreshJobConfig{Config: cfg, Logger: zerolog.Nop()})
+	return NewScheduler(SchedulerConfig{Job: job, Logger: zerolog.Nop()})
+}
+
+func TestRefreshConfig_IntervalFor(t *testing.T) {
+	cfg := DefaultRefreshConfig()
+
+	assert.Equal(t, 5*time.Minute, cfg.IntervalFor(1))
+	assert.Equal(t, 10*time.Minute, cfg.IntervalFor(2))
+	assert.Equal(t, 20*time.Minute, cfg.IntervalFor(3))
+	assert.Equal(t, DefaultPriorityInterval, cfg.IntervalFor(7))
+}
+
+func TestScheduler_RunsTargetsOnPriorityIntervals(t *testing.T) {
+	s := newTestScheduler(t)
+	require.Len(t, s.entries, 2)
+
+	var mu sync.Mutex
+	runs := map[string]int{}
+	for _, entry := range s.entries {
+		name := entry.name
+		entry.run = func(context.Context) {
+			mu.Lock()
+			defer mu.Unlock()
+			runs[name]++
+		}
+	}
+
+	// Wait after each tick so counts are deterministic.
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	for minute := 0; minute <= 20; minute++ {
+		s.runDue(context.Background(), start.Add(time.Duration(minute)*time.Minute))
+		s.wg.Wait()
+	}
+
+	assert.Equal(t, 5, runs["Amsterdam"]) // 0, 5, 10, 15, 20
+	assert.Equal(t, 2, runs["Leiden"])    // 0, 20
+
+	next := s.job.GetMetrics().NextRunAt
+	assert.Equal(t, start.Add(25*time.Minute), next["Amsterdam"])
+	assert.Equal(t, start.Add(40*time.Minute), next["Leiden"])
+}
+
+func TestScheduler_SkipsTargetStillInFlight(t *testing.T) {
+	s := newTestScheduler(t)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+	for _, entry := range s.entries {
+		entry.run = func(context.Context) {
+			started <- struct{}{}
+			<-release
+		}
+	}
+
+	start := time.Now()
+	s.runDue(context.Background(), start)
+	<-started
+	<-started
+
+	// Amsterdam is due again but its first run has not finished
+	s.runDue(context.Background(), start.Add(5*time.Minute))
+	assert.Equal(t, int64(1), s.job.GetMetrics().SkippedRuns)
+
+	close(release)
+	s.wg.Wait()
+
+	// Once the run completes the target is scheduled again
+	s.runDue(context.Background(), start.Add(10*time.Minute))
+	<-started
+	s.wg.Wait()
+	assert.Equal(t, int64(1), s.job.GetMetrics().SkippedRuns)
+}
+
+func TestScheduler_RunStopsOnCancel(t *testing.T) {
+	s := newTestScheduler(t)
+	s.tick = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+
+	snapshot := s.job.MetricsSnapshot()
+	assert.Contains(t, snapshot, "next_run_at")
+	assert.Contains(t, snapshot, "skipped_runs")
+}
+
+func TestScheduler_WaitForCompletion_DrainsInFlight(t *testing.T) {
+	s := newTestScheduler(t)
+	s.tick = time.Hour
+
+	release := make(chan struct{})
+	started := make(chan struct{}, len(s.entries))
+	finished := make(chan error, len(s.entries))
+	for _, entry := range s.entries {
+		entry.run = func(ctx context.Context) {
+			started <- struct{}{}
+			<-release
+			finished <- ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	<-started
+	<-started
+
+	// Stopping the scheduler does not cancel runs in flight
+	cancel()
+	<-done
+	assert.ElementsMatch(t, []string{"Amsterdam", "Leiden"}, s.inFlight())
+
+	close(release)
+	assert.True(t, s.WaitForCompletion(time.Second))
+	for range s.entries {
+		assert.NoError(t, <-finished)
+	}
+	assert.Empty(t, s.inFlight())
+}
+
+func TestScheduler_WaitForCompletion_CancelsAfterTimeout(t *testing.T) {
+	s := newTestScheduler(t)
+
+	canceled := make(chan struct{}, len(s.entries))
+	for _, entry := range s.entries {
+		entry.run = func(ctx context.Context) {
+			<-ctx.Done()
+			canceled <- struct{}{}
+		}
+	}
+
+	s.runDue(s.runCtx, time.Now())
+	assert.False(t, s.WaitForCompletion(10*time.Millisecond))
+	for range s.entries {
+		<-canceled
+	}
+}
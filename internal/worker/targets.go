@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRefreshTargets indicates a refresh target list failed validation.
+var ErrInvalidRefreshTargets = errors.New("invalid refresh targets")
+
+// LoadRefreshTargets reads a refresh target list from a file. Files ending
+// in .yaml or .yml are parsed as YAML, anything else as JSON.
+func LoadRefreshTargets(path string) ([]RefreshTarget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading refresh targets: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseRefreshTargetsYAML(data)
+	default:
+		return ParseRefreshTargets(data)
+	}
+}
+
+// ParseRefreshTargets parses and validates a JSON refresh target list, e.g.
+//
+//	[{"name": "Zwolle", "priority": 2, "points": [{"lat": 52.5055, "lon": 6.0919}]}]
+func ParseRefreshTargets(data []byte) ([]RefreshTarget, error) {
+	var targets []RefreshTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRefreshTargets, err)
+	}
+	return targets, ValidateRefreshTargets(targets)
+}
+
+// ParseRefreshTargetsYAML parses and validates a YAML refresh target list
+// with the same fields as ParseRefreshTargets.
+func ParseRefreshTargetsYAML(data []byte) ([]RefreshTarget, error) {
+	var targets []RefreshTarget
+	if err := yaml.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRefreshTargets, err)
+	}
+	return targets, ValidateRefreshTargets(targets)
+}
+
+// ValidateRefreshTargets checks that targets is non-empty, every target has
+// a unique name, a priority of at least 1 and at least one point, and every
+// point is a valid coordinate.
+func ValidateRefreshTargets(targets []RefreshTarget) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: no targets", ErrInvalidRefreshTargets)
+	}
+
+	names := make(map[string]bool, len(targets))
+	for i, target := range targets {
+		if target.Name == "" {
+			return fmt.Errorf("%w: target %d has no name", ErrInvalidRefreshTargets, i)
+		}
+		if names[target.Name] {
+			return fmt.Errorf("%w: duplicate target %q", ErrInvalidRefreshTargets, target.Name)
+		}
+		names[target.Name] = true
+
+		if target.Priority < 1 {
+			return fmt.Errorf("%w: target %q has priority %d, must be at least 1",
+				ErrInvalidRefreshTargets, target.Name, target.Priority)
+		}
+		if len(target.Points) == 0 {
+			return fmt.Errorf("%w: target %q has no points", ErrInvalidRefreshTargets, target.Name)
+		}
+		for _, p := range target.Points {
+			if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+				return fmt.Errorf("%w: target %q has invalid point (%g, %g)",
+					ErrInvalidRefreshTargets, target.Name, p.Lat, p.Lon)
+			}
+			if p.Lat == 0 && p.Lon == 0 {
+				// Almost always a missing or misspelled field
+				return fmt.Errorf("%w: target %q has a point at (0, 0)", ErrInvalidRefreshTargets, target.Name)
+			}
+		}
+	}
+	return nil
+}
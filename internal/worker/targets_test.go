@@ -0,0 +1,66 @@
+package worker_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/breatheroute/breatheroute/internal/worker"
+)
+
+func TestLoadRefreshTargets(t *testing.T) {
+	targets, err := worker.LoadRefreshTargets(filepath.Join("testdata", "refresh_targets.yaml"))
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+	assert.Equal(t, "Zwolle", targets[1].Name)
+	assert.Equal(t, 2, targets[1].Priority)
+
+	cfg := worker.DefaultRefreshConfig()
+	cfg.Targets = targets
+	assert.Equal(t, 4, cfg.TotalPoints())
+	assert.Equal(t, []worker.Point{
+		{Lat: 52.3676, Lon: 4.9041},
+		{Lat: 52.3386, Lon: 4.8919},
+		{Lat: 52.5055, Lon: 6.0919},
+		{Lat: 53.2107, Lon: 6.5641},
+	}, cfg.AllPoints())
+}
+
+func TestLoadRefreshTargets_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	data := `[{"name": "Zwolle", "priority": 2, "points": [{"lat": 52.5055, "lon": 6.0919}]}]`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	targets, err := worker.LoadRefreshTargets(path)
+	require.NoError(t, err)
+	assert.Equal(t, []worker.RefreshTarget{
+		{Name: "Zwolle", Priority: 2, Points: []worker.Point{{Lat: 52.5055, Lon: 6.0919}}},
+	}, targets)
+}
+
+func TestParseRefreshTargets_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"malformed", `[{"name": "Zwolle"`},
+		{"empty", `[]`},
+		{"no name", `[{"priority": 1, "points": [{"lat": 52.5, "lon": 6.1}]}]`},
+		{"duplicate", `[{"name": "Zwolle", "priority": 1, "points": [{"lat": 52.5, "lon": 6.1}]},
+			{"name": "Zwolle", "priority": 2, "points": [{"lat": 52.5, "lon": 6.1}]}]`},
+		{"no priority", `[{"name": "Zwolle", "points": [{"lat": 52.5, "lon": 6.1}]}]`},
+		{"no points", `[{"name": "Zwolle", "priority": 1, "points": []}]`},
+		{"latitude out of range", `[{"name": "Zwolle", "priority": 1, "points": [{"lat": 152.5, "lon": 6.1}]}]`},
+		{"misspelled fields", `[{"name": "Zwolle", "priority": 1, "points": [{"latitude": 52.5, "longitude": 6.1}]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := worker.ParseRefreshTargets([]byte(tt.data))
+			assert.ErrorIs(t, err, worker.ErrInvalidRefreshTargets)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+-- Remove hill effort weighting from user_profiles table
+
+ALTER TABLE user_profiles
+DROP CONSTRAINT IF EXISTS chk_effort_weight;
+
+ALTER TABLE user_profiles
+DROP COLUMN IF EXISTS effort_weight;
@@ -0,0 +1,10 @@
+-- Add hill effort weighting to user_profiles table
+-- Penalizes climbing when ranking routes under the BALANCED objective
+
+ALTER TABLE user_profiles
+ADD COLUMN effort_weight DECIMAL(3,2);
+
+ALTER TABLE user_profiles
+ADD CONSTRAINT chk_effort_weight CHECK (effort_weight IS NULL OR (effort_weight >= 0 AND effort_weight <= 1));
+
+COMMENT ON COLUMN user_profiles.effort_weight IS 'Weight (0-1) of the elevation effort penalty in balanced route ranking';
@@ -0,0 +1,5 @@
+-- Drop webhooks table
+
+DROP INDEX IF EXISTS idx_webhooks_active;
+DROP INDEX IF EXISTS idx_webhooks_user_id;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,25 @@
+-- Create webhooks table for transit disruption change notifications
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id VARCHAR(26) PRIMARY KEY,
+    user_id VARCHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_delivery_at TIMESTAMPTZ,
+    last_error TEXT,
+    disabled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for looking up webhooks by user
+CREATE INDEX idx_webhooks_user_id ON webhooks(user_id);
+
+-- Partial index for fan-out to active endpoints
+CREATE INDEX idx_webhooks_active ON webhooks(created_at) WHERE active;
+
+COMMENT ON TABLE webhooks IS 'Integrator endpoints receiving transit disruption change events';
+COMMENT ON COLUMN webhooks.secret IS 'HMAC-SHA256 signing key for the X-BreatheRoute-Signature header';
+COMMENT ON COLUMN webhooks.consecutive_failures IS 'Deliveries failed after all retries; endpoint is disabled at the configured limit';
@@ -0,0 +1,7 @@
+-- Remove refresh token lineage tracking
+
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+
+ALTER TABLE refresh_tokens
+DROP COLUMN IF EXISTS used_at,
+DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,19 @@
+-- Track refresh token lineage for reuse detection
+-- Tokens rotated from the same sign-in share a family_id; replaying a used
+-- token revokes the whole family
+
+ALTER TABLE refresh_tokens
+ADD COLUMN family_id UUID,
+ADD COLUMN used_at TIMESTAMPTZ;
+
+-- Existing tokens each start their own family
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+
+ALTER TABLE refresh_tokens
+ALTER COLUMN family_id SET NOT NULL;
+
+-- Index for revoking a token family
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Shared by all tokens rotated from the same sign-in';
+COMMENT ON COLUMN refresh_tokens.used_at IS 'When the token was exchanged for a new pair; reuse after this revokes the family';
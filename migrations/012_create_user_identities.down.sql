@@ -0,0 +1,5 @@
+-- Drop user_identities table
+
+DROP INDEX IF EXISTS idx_user_identities_verified_email;
+DROP INDEX IF EXISTS idx_user_identities_user_id;
+DROP TABLE IF EXISTS user_identities;
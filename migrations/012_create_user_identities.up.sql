@@ -0,0 +1,29 @@
+-- Create user_identities table for linked sign-in identities
+-- A user may have several identities, e.g. when the same person signs in
+-- under a new Apple subject after an app transfer
+CREATE TABLE IF NOT EXISTS user_identities (
+    id VARCHAR(26) PRIMARY KEY,
+    user_id VARCHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    is_private_email BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_identities_provider_subject UNIQUE (provider, subject)
+);
+
+-- Index for listing a user's identities
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Index for verified email lookup (used when linking a new subject)
+CREATE INDEX IF NOT EXISTS idx_user_identities_verified_email
+    ON user_identities(provider, LOWER(email)) WHERE email_verified;
+
+-- Backfill one identity per existing user. Email verification is unknown for
+-- existing rows, so it is recorded on the user's next sign-in.
+INSERT INTO user_identities (id, user_id, provider, subject, email, created_at, last_used_at)
+SELECT 'idn_' || SUBSTRING(REPLACE(gen_random_uuid()::text, '-', '') FROM 1 FOR 22), id, 'apple', apple_sub, email, created_at, updated_at
+FROM users
+ON CONFLICT (provider, subject) DO NOTHING;
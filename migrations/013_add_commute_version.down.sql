@@ -0,0 +1,4 @@
+-- Remove version counter from commutes table
+
+ALTER TABLE commutes
+DROP COLUMN IF EXISTS version;
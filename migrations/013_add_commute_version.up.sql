@@ -0,0 +1,7 @@
+-- Add a version counter to commutes for optimistic concurrency control.
+-- Clients send the version back in If-Match; updates only apply when it matches.
+
+ALTER TABLE commutes
+ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN commutes.version IS 'Incremented on each update; exposed as the commute ETag';
@@ -0,0 +1,5 @@
+-- Remove schedule exceptions from commutes table
+
+ALTER TABLE commutes
+DROP COLUMN IF EXISTS skip_public_holidays,
+DROP COLUMN IF EXISTS exceptions;
@@ -0,0 +1,10 @@
+-- Add schedule exceptions to commutes.
+-- exceptions holds YYYY-MM-DD dates on which the commute does not run;
+-- skip_public_holidays additionally skips the bundled Dutch holiday calendar.
+
+ALTER TABLE commutes
+ADD COLUMN exceptions TEXT[] NOT NULL DEFAULT '{}',
+ADD COLUMN skip_public_holidays BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN commutes.exceptions IS 'YYYY-MM-DD dates on which the commute does not run';
+COMMENT ON COLUMN commutes.skip_public_holidays IS 'Skip Dutch public holidays';
@@ -0,0 +1,4 @@
+-- Remove waypoints from commutes table
+
+ALTER TABLE commutes
+DROP COLUMN IF EXISTS waypoints;
@@ -0,0 +1,7 @@
+-- Add ordered waypoints (e.g. a school drop-off) between commute origin and destination.
+-- Stored as a JSON array of {"lat": ..., "lon": ...} objects.
+
+ALTER TABLE commutes
+ADD COLUMN waypoints JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN commutes.waypoints IS 'Ordered stops between origin and destination';
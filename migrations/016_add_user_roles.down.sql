@@ -0,0 +1,4 @@
+-- Remove authorization role from users
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS role;
@@ -0,0 +1,8 @@
+-- Add an authorization role to users
+-- Admins may use operator endpoints; everyone else is a regular user
+
+ALTER TABLE users
+ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user'
+    CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
+
+COMMENT ON COLUMN users.role IS 'Authorization role: user or admin; admins are seeded from ADMIN_APPLE_SUBS at sign-in';
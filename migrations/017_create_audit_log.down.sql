@@ -0,0 +1,3 @@
+-- Drop audit log table
+
+DROP TABLE IF EXISTS audit_log;
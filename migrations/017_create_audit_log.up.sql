@@ -0,0 +1,18 @@
+-- Create audit log of changes to user resources (commutes, devices, profile)
+-- Entries are kept after the user is deleted, as evidence of what was changed
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id VARCHAR(26) PRIMARY KEY,
+    user_id VARCHAR(26) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    resource_type VARCHAR(32) NOT NULL,
+    resource_id VARCHAR(64) NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for reading a user's history, newest first
+CREATE INDEX idx_audit_log_user_id_created_at ON audit_log(user_id, created_at DESC);
+
+COMMENT ON TABLE audit_log IS 'Who created, updated or deleted which user resource, and in which request';
+COMMENT ON COLUMN audit_log.resource_id IS 'ID of the changed resource; the user ID for resources without their own ID';
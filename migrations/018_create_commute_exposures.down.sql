@@ -0,0 +1,3 @@
+-- Drop commute exposures table
+
+DROP TABLE IF EXISTS commute_exposures;
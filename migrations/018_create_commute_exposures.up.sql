@@ -0,0 +1,23 @@
+-- Create estimated exposure on completed commute trips, written by the worker
+-- Rows are kept when the commute is deleted, so weekly history stays intact
+
+CREATE TABLE IF NOT EXISTS commute_exposures (
+    commute_id VARCHAR(26) NOT NULL,
+    trip_date DATE NOT NULL,
+    user_id VARCHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    arrival_at TIMESTAMPTZ NOT NULL,
+    no2_ugm3 DOUBLE PRECISION NOT NULL,
+    pm25_ugm3 DOUBLE PRECISION NOT NULL,
+    o3_ugm3 DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- One estimate per commute and day; makes the worker job idempotent
+    PRIMARY KEY (commute_id, trip_date)
+);
+
+-- Index for reading a user's weekly history
+CREATE INDEX idx_commute_exposures_user_id_trip_date ON commute_exposures(user_id, trip_date);
+
+COMMENT ON TABLE commute_exposures IS 'Estimated air pollution exposure per completed commute trip';
+COMMENT ON COLUMN commute_exposures.trip_date IS 'Calendar date of the trip in the commute timezone';
+COMMENT ON COLUMN commute_exposures.no2_ugm3 IS 'Average NO2 over the commute stops in the hour of scheduled arrival';
@@ -0,0 +1,3 @@
+-- Drop stored routes table
+
+DROP TABLE IF EXISTS stored_routes;
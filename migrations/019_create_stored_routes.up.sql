@@ -0,0 +1,17 @@
+-- Create computed route responses, fetched again by ID for export and sharing
+-- Routes computed anonymously have no user; their random ID is the only secret
+
+CREATE TABLE IF NOT EXISTS stored_routes (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(26) REFERENCES users(id) ON DELETE CASCADE,
+    response JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Expired rows are ignored on read; the index lets them be purged in bulk
+CREATE INDEX idx_stored_routes_expires_at ON stored_routes(expires_at);
+
+COMMENT ON TABLE stored_routes IS 'Computed route responses kept until they expire';
+COMMENT ON COLUMN stored_routes.user_id IS 'User who computed the route, NULL for anonymous requests';
+COMMENT ON COLUMN stored_routes.response IS 'RouteComputeResponse in its API JSON encoding';
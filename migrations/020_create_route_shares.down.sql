@@ -0,0 +1,3 @@
+-- Drop route shares table
+
+DROP TABLE IF EXISTS route_shares;
@@ -0,0 +1,18 @@
+-- Create public, read-only links to stored routes
+-- Each share keeps its own copy of the route, so it outlives stored_routes rows
+
+CREATE TABLE IF NOT EXISTS route_shares (
+    token VARCHAR(36) PRIMARY KEY,
+    route_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    route JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+-- Index for deleting a user's shares along with the user
+CREATE INDEX idx_route_shares_user_id ON route_shares(user_id);
+
+COMMENT ON TABLE route_shares IS 'Public links to stored routes, served until they expire or are revoked';
+COMMENT ON COLUMN route_shares.route IS 'PublicRoute in its API JSON encoding, without user or route identifiers';
@@ -0,0 +1,4 @@
+-- Remove pollen sensitivities from user_profiles table
+
+ALTER TABLE user_profiles
+DROP COLUMN IF EXISTS pollen_sensitivities;
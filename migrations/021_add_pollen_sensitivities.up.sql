@@ -0,0 +1,7 @@
+-- Add pollen sensitivities to user_profiles table
+-- Pollen types or species (e.g. TREE, Birch) the user is allergic to; matched case-insensitively
+
+ALTER TABLE user_profiles
+ADD COLUMN pollen_sensitivities TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN user_profiles.pollen_sensitivities IS 'Pollen types or species the user is sensitive to (empty: overall pollen risk applies)';
@@ -0,0 +1,5 @@
+-- Remove ferry and unpaved road avoidance from user_profiles table
+
+ALTER TABLE user_profiles
+DROP COLUMN IF EXISTS avoid_unpaved,
+DROP COLUMN IF EXISTS avoid_ferries;
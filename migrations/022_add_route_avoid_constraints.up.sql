@@ -0,0 +1,9 @@
+-- Add ferry and unpaved road avoidance to user_profiles table
+-- Sent to the routing provider where the travel profile supports them
+
+ALTER TABLE user_profiles
+ADD COLUMN avoid_ferries BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN avoid_unpaved BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN user_profiles.avoid_ferries IS 'Avoid ferry crossings where the routing profile supports it';
+COMMENT ON COLUMN user_profiles.avoid_unpaved IS 'Avoid unpaved roads where the routing profile supports it';
@@ -0,0 +1,4 @@
+-- Remove congestion zone avoidance from user_profiles table
+
+ALTER TABLE user_profiles
+DROP COLUMN IF EXISTS avoid_congestion_zones;
@@ -0,0 +1,7 @@
+-- Add congestion zone avoidance to user_profiles table
+-- Routes stay out of the bundled low-emission zones when set
+
+ALTER TABLE user_profiles
+ADD COLUMN avoid_congestion_zones BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN user_profiles.avoid_congestion_zones IS 'Keep routes out of city-centre low-emission zones';
@@ -0,0 +1,6 @@
+-- Remove feature flag change history and updated-by tracking
+
+DROP TABLE IF EXISTS feature_flag_changes;
+
+ALTER TABLE feature_flags
+DROP COLUMN IF EXISTS updated_by;
@@ -0,0 +1,23 @@
+-- Track who changed feature flags, with an append-only history of changes
+-- Flags control safety behaviours such as alert sending, so every change is kept
+
+ALTER TABLE feature_flags
+ADD COLUMN updated_by VARCHAR(26);
+
+COMMENT ON COLUMN feature_flags.updated_by IS 'ID of the admin who last set the flag; NULL for seeded flags and changes made outside the API';
+
+CREATE TABLE IF NOT EXISTS feature_flag_changes (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR(100) NOT NULL,
+    previous_value JSONB,
+    new_value JSONB,
+    changed_by VARCHAR(26),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for reading a flag's history, newest first
+CREATE INDEX idx_feature_flag_changes_key_changed_at ON feature_flag_changes(key, changed_at DESC);
+
+COMMENT ON TABLE feature_flag_changes IS 'Append-only history of feature flag changes';
+COMMENT ON COLUMN feature_flag_changes.previous_value IS 'Value before the change; NULL when the change created the flag';
+COMMENT ON COLUMN feature_flag_changes.new_value IS 'Value after the change; NULL when the change deleted the flag';
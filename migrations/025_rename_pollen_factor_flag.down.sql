@@ -0,0 +1,6 @@
+-- Restore the seeded pollen flag key
+
+UPDATE feature_flags
+SET key = 'disable_pollen_factor', updated_at = NOW()
+WHERE key = 'pollen_factor_disabled'
+  AND NOT EXISTS (SELECT 1 FROM feature_flags WHERE key = 'disable_pollen_factor');
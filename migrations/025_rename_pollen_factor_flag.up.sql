@@ -0,0 +1,9 @@
+-- Rename the seeded pollen flag to the key the API reads
+-- Migration 005 seeded disable_pollen_factor, but the service reads pollen_factor_disabled
+
+UPDATE feature_flags
+SET key = 'pollen_factor_disabled', updated_at = NOW()
+WHERE key = 'disable_pollen_factor'
+  AND NOT EXISTS (SELECT 1 FROM feature_flags WHERE key = 'pollen_factor_disabled');
+
+DELETE FROM feature_flags WHERE key = 'disable_pollen_factor';
@@ -44,6 +44,63 @@ func Decode(encoded string) []Coordinate {
 	return coords
 }
 
+// Coordinate3D is a coordinate with an elevation in meters.
+type Coordinate3D struct {
+	Coordinate
+	Elevation float64
+}
+
+// Decode3D decodes a polyline with a third elevation dimension, as returned
+// by openrouteservice when elevation is requested. Latitude and longitude use
+// precision 5 and elevation uses precision 2.
+func Decode3D(encoded string) []Coordinate3D {
+	if encoded == "" {
+		return nil
+	}
+
+	var coords []Coordinate3D
+	index := 0
+	lat := 0
+	lon := 0
+	ele := 0
+
+	for index < len(encoded) {
+		latDelta, newIndex := decodeValue(encoded, index)
+		index = newIndex
+		lat += latDelta
+
+		lonDelta, newIndex := decodeValue(encoded, index)
+		index = newIndex
+		lon += lonDelta
+
+		eleDelta, newIndex := decodeValue(encoded, index)
+		index = newIndex
+		ele += eleDelta
+
+		coords = append(coords, Coordinate3D{
+			Coordinate: Coordinate{
+				Lat: float64(lat) / 1e5,
+				Lon: float64(lon) / 1e5,
+			},
+			Elevation: float64(ele) / 1e2,
+		})
+	}
+
+	return coords
+}
+
+// Flatten drops the elevation from 3D coordinates.
+func Flatten(coords []Coordinate3D) []Coordinate {
+	if coords == nil {
+		return nil
+	}
+	flat := make([]Coordinate, len(coords))
+	for i, c := range coords {
+		flat[i] = c.Coordinate
+	}
+	return flat
+}
+
 // decodeValue decodes a single value from the polyline at the given index.
 // Returns the decoded delta value and the new index position.
 func decodeValue(encoded string, index int) (int, int) {
@@ -124,8 +181,43 @@ func Length(coords []Coordinate) float64 {
 	return total
 }
 
+// PointAt returns the point distanceMeters along the polyline, interpolating
+// within the segment it falls in. Distances beyond either end are clamped to
+// the first or last coordinate.
+func PointAt(coords []Coordinate, distanceMeters float64) Coordinate {
+	if len(coords) == 0 {
+		return Coordinate{}
+	}
+	if distanceMeters <= 0 {
+		return coords[0]
+	}
+
+	var traveled float64
+	for i := 1; i < len(coords); i++ {
+		segment := haversineDistance(coords[i-1], coords[i])
+		if segment > 0 && traveled+segment >= distanceMeters {
+			ratio := (distanceMeters - traveled) / segment
+			return Coordinate{
+				Lat: coords[i-1].Lat + (coords[i].Lat-coords[i-1].Lat)*ratio,
+				Lon: coords[i-1].Lon + (coords[i].Lon-coords[i-1].Lon)*ratio,
+			}
+		}
+		traveled += segment
+	}
+	return coords[len(coords)-1]
+}
+
+// Resample returns points spaced intervalMeters apart along the polyline, for
+// scoring exposure at regular distances whatever the spacing of the decoded
+// vertices. It is the entry point for exposure sampling and behaves like Sample.
+func Resample(points []Coordinate, intervalMeters float64) []Coordinate {
+	return Sample(points, intervalMeters)
+}
+
 // Sample returns coordinates sampled at approximately the specified interval along the polyline.
 // This is useful for sampling points for air quality exposure scoring.
+// The first and last coordinates are always included; the final gap may be
+// shorter than the interval.
 func Sample(coords []Coordinate, intervalMeters float64) []Coordinate {
 	if len(coords) == 0 {
 		return nil
@@ -135,39 +227,103 @@ func Sample(coords []Coordinate, intervalMeters float64) []Coordinate {
 	}
 
 	sampled := []Coordinate{coords[0]}
-	accumulated := 0.0
+	accumulated := 0.0 // distance travelled since the last sample
 
 	for i := 1; i < len(coords); i++ {
 		segmentDist := haversineDistance(coords[i-1], coords[i])
 
-		// Check if we need to add sample points within this segment
-		for accumulated+segmentDist >= intervalMeters {
-			// Calculate how far along this segment we need to go
-			remaining := intervalMeters - accumulated
-			fraction := remaining / segmentDist
-
-			// Interpolate the point
-			newLat := coords[i-1].Lat + fraction*(coords[i].Lat-coords[i-1].Lat)
-			newLon := coords[i-1].Lon + fraction*(coords[i].Lon-coords[i-1].Lon)
-			sampled = append(sampled, Coordinate{Lat: newLat, Lon: newLon})
-
-			// Update for next iteration
-			segmentDist -= remaining
-			accumulated = 0
+		// Add a sample at each interval boundary within this segment, measured
+		// from the segment's start
+		next := intervalMeters - accumulated
+		for ; next <= segmentDist; next += intervalMeters {
+			fraction := next / segmentDist
+			sampled = append(sampled, Coordinate{
+				Lat: coords[i-1].Lat + fraction*(coords[i].Lat-coords[i-1].Lat),
+				Lon: coords[i-1].Lon + fraction*(coords[i].Lon-coords[i-1].Lon),
+			})
 		}
 
-		accumulated += segmentDist
+		accumulated = segmentDist - (next - intervalMeters)
 	}
 
 	// Always include the last point if it's not already included
 	last := coords[len(coords)-1]
-	if len(sampled) == 0 || sampled[len(sampled)-1] != last {
+	if sampled[len(sampled)-1] != last {
 		sampled = append(sampled, last)
 	}
 
 	return sampled
 }
 
+// Simplify reduces the number of points in a polyline using the Douglas-Peucker
+// algorithm. Points deviating less than toleranceMeters from the simplified line
+// are dropped; the first and last points are always kept, as are turns sharp
+// enough to exceed the tolerance. A non-positive tolerance returns coords unchanged.
+func Simplify(coords []Coordinate, toleranceMeters float64) []Coordinate {
+	if len(coords) < 3 || toleranceMeters <= 0 {
+		return coords
+	}
+
+	keep := make([]bool, len(coords))
+	keep[0] = true
+	keep[len(coords)-1] = true
+
+	// Iterative to avoid deep recursion on long routes
+	type span struct{ first, last int }
+	stack := []span{{first: 0, last: len(coords) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		maxDist := 0.0
+		index := -1
+		for i := s.first + 1; i < s.last; i++ {
+			d := perpendicularDistance(coords[i], coords[s.first], coords[s.last])
+			if d > maxDist {
+				maxDist = d
+				index = i
+			}
+		}
+
+		if index >= 0 && maxDist > toleranceMeters {
+			keep[index] = true
+			stack = append(stack, span{first: s.first, last: index}, span{first: index, last: s.last})
+		}
+	}
+
+	simplified := make([]Coordinate, 0, len(coords)/4+2)
+	for i, c := range coords {
+		if keep[i] {
+			simplified = append(simplified, c)
+		}
+	}
+	return simplified
+}
+
+// perpendicularDistance returns the distance in meters from p to the segment a-b,
+// using an equirectangular projection around a (accurate for short segments).
+func perpendicularDistance(p, a, b Coordinate) float64 {
+	cosLat := math.Cos(a.Lat * math.Pi / 180)
+	toXY := func(c Coordinate) (float64, float64) {
+		x := (c.Lon - a.Lon) * math.Pi / 180 * earthRadiusMeters * cosLat
+		y := (c.Lat - a.Lat) * math.Pi / 180 * earthRadiusMeters
+		return x, y
+	}
+
+	px, py := toXY(p)
+	bx, by := toXY(b)
+
+	lengthSq := bx*bx + by*by
+	if lengthSq == 0 {
+		return math.Hypot(px, py)
+	}
+
+	// Project p onto the segment, clamped to its endpoints
+	t := (px*bx + py*by) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-t*bx, py-t*by)
+}
+
 // haversineDistance calculates the distance between two coordinates in meters.
 const earthRadiusMeters = 6371000
 
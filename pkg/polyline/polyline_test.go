@@ -226,6 +226,70 @@ func TestSample_ValidRoute(t *testing.T) {
 	})
 }
 
+func TestSample_EvenSpacing(t *testing.T) {
+	// One long segment followed by short ones, so samples fall both several
+	// to a segment and across segment boundaries
+	coords := []Coordinate{
+		{Lat: 52.0, Lon: 4.0},
+		{Lat: 52.01, Lon: 4.0},   // ~1112m
+		{Lat: 52.0105, Lon: 4.0}, // ~56m
+		{Lat: 52.011, Lon: 4.0},  // ~56m
+		{Lat: 52.02, Lon: 4.0},   // ~1001m
+	}
+	const interval = 200.0
+
+	sampled := Sample(coords, interval)
+
+	total := Length(coords)
+	if want := int(total/interval) + 2; len(sampled) != want {
+		t.Fatalf("expected %d samples for %.0fm, got %d", want, total, len(sampled))
+	}
+	for i := 1; i < len(sampled)-1; i++ {
+		gap := haversineDistance(sampled[i-1], sampled[i])
+		if math.Abs(gap-interval) > 0.5 {
+			t.Errorf("gap %d = %.1fm, want %.0fm", i, gap, interval)
+		}
+	}
+	if !coordsEqual(sampled[len(sampled)-1], coords[len(coords)-1], 0.000001) {
+		t.Errorf("last sample should be last coordinate")
+	}
+}
+
+func TestPointAt(t *testing.T) {
+	coords := []Coordinate{
+		{Lat: 52.0, Lon: 4.0},
+		{Lat: 52.01, Lon: 4.0}, // ~1112m north
+		{Lat: 52.01, Lon: 4.0}, // duplicate point
+		{Lat: 52.02, Lon: 4.0}, // ~1112m more north
+	}
+
+	tests := []struct {
+		name     string
+		distance float64
+		want     Coordinate
+	}{
+		{"start", 0, coords[0]},
+		{"negative clamps to start", -100, coords[0]},
+		{"within first segment", Length(coords) / 4, Coordinate{Lat: 52.005, Lon: 4.0}},
+		{"at vertex", Length(coords) / 2, coords[1]},
+		{"within last segment", Length(coords) * 3 / 4, Coordinate{Lat: 52.015, Lon: 4.0}},
+		{"beyond end clamps to end", Length(coords) + 100, coords[3]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PointAt(coords, tt.distance)
+			if !coordsEqual(got, tt.want, 0.0001) {
+				t.Errorf("PointAt(%v) = %v, want %v", tt.distance, got, tt.want)
+			}
+		})
+	}
+
+	if got := PointAt(nil, 10); got != (Coordinate{}) {
+		t.Errorf("PointAt(nil) = %v, want zero coordinate", got)
+	}
+}
+
 func TestRoundTrip_HighPrecision(t *testing.T) {
 	// Test that encode->decode preserves coordinates to 5 decimal places
 	coords := []Coordinate{
@@ -271,3 +335,216 @@ func BenchmarkEncode(b *testing.B) {
 		_ = Encode(coords)
 	}
 }
+
+func TestSimplify_StraightLineKeepsEndpoints(t *testing.T) {
+	coords := make([]Coordinate, 0, 11)
+	for i := 0; i <= 10; i++ {
+		coords = append(coords, Coordinate{Lat: 52.37 + float64(i)*0.001, Lon: 4.89})
+	}
+
+	simplified := Simplify(coords, 5)
+
+	if len(simplified) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(simplified))
+	}
+	if simplified[0] != coords[0] || simplified[1] != coords[len(coords)-1] {
+		t.Errorf("expected endpoints to be preserved, got %v", simplified)
+	}
+}
+
+func TestSimplify_PreservesTurns(t *testing.T) {
+	// L-shaped route: north for ~1km, then east for ~1km
+	var coords []Coordinate
+	for i := 0; i <= 10; i++ {
+		coords = append(coords, Coordinate{Lat: 52.37 + float64(i)*0.0009, Lon: 4.89})
+	}
+	corner := coords[len(coords)-1]
+	for i := 1; i <= 10; i++ {
+		coords = append(coords, Coordinate{Lat: corner.Lat, Lon: corner.Lon + float64(i)*0.0015})
+	}
+
+	simplified := Simplify(coords, 5)
+
+	if len(simplified) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(simplified))
+	}
+	if simplified[1] != corner {
+		t.Errorf("expected corner %v to be preserved, got %v", corner, simplified[1])
+	}
+}
+
+func TestSimplify_WithinTolerance(t *testing.T) {
+	coords := amsterdamUtrechtRoute()
+	tolerance := 5.0
+
+	simplified := Simplify(coords, tolerance)
+
+	if len(simplified) >= len(coords) {
+		t.Fatalf("expected fewer points, got %d of %d", len(simplified), len(coords))
+	}
+
+	// Every original point must lie within tolerance of the simplified line
+	for _, p := range coords {
+		minDist := math.Inf(1)
+		for i := 1; i < len(simplified); i++ {
+			minDist = math.Min(minDist, perpendicularDistance(p, simplified[i-1], simplified[i]))
+		}
+		if minDist > tolerance+0.5 {
+			t.Fatalf("point %v is %.1fm from simplified line (tolerance %.0fm)", p, minDist, tolerance)
+		}
+	}
+}
+
+func TestSimplify_NonPositiveToleranceUnchanged(t *testing.T) {
+	coords := amsterdamUtrechtRoute()
+
+	if got := Simplify(coords, 0); len(got) != len(coords) {
+		t.Errorf("expected %d points, got %d", len(coords), len(got))
+	}
+}
+
+// amsterdamUtrechtRoute generates a ~40km bike route from Amsterdam Centraal to
+// Utrecht Centraal with a point every ~10m, street-grid turns and GPS-like jitter,
+// approximating the resolution returned by the routing provider.
+func amsterdamUtrechtRoute() []Coordinate {
+	start := Coordinate{Lat: 52.3791, Lon: 4.9003}
+	end := Coordinate{Lat: 52.0894, Lon: 5.1100}
+
+	const steps = 4000
+	coords := make([]Coordinate, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		f := float64(i) / steps
+		// Lateral offset every ~500m models turns between streets and paths
+		offset := 0.0008 * math.Sin(float64(i/50)*1.3)
+		// Deterministic sub-meter jitter
+		jitter := 0.000005 * math.Sin(float64(i)*7.1)
+		coords = append(coords, Coordinate{
+			Lat: start.Lat + f*(end.Lat-start.Lat) + jitter,
+			Lon: start.Lon + f*(end.Lon-start.Lon) + offset,
+		})
+	}
+	return coords
+}
+
+// BenchmarkSimplify reports the encoded payload size for a typical
+// Amsterdam-Utrecht bike route before and after simplification.
+func BenchmarkSimplify(b *testing.B) {
+	coords := amsterdamUtrechtRoute()
+	fullBytes := len(Encode(coords))
+
+	var simplified []Coordinate
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		simplified = Simplify(coords, 5)
+	}
+	b.StopTimer()
+
+	simplifiedBytes := len(Encode(simplified))
+	b.ReportMetric(float64(fullBytes), "full-bytes")
+	b.ReportMetric(float64(simplifiedBytes), "simplified-bytes")
+	b.ReportMetric(100*(1-float64(simplifiedBytes)/float64(fullBytes)), "%reduction")
+}
+
+func TestDecode3D(t *testing.T) {
+	// Lat/lon at precision 5 followed by elevation at precision 2, as sent by ORS
+	encoded := encode3D([]Coordinate3D{
+		{Coordinate: Coordinate{Lat: 52.37021, Lon: 4.89517}, Elevation: 1.5},
+		{Coordinate: Coordinate{Lat: 52.37105, Lon: 4.89610}, Elevation: -2.25},
+		{Coordinate: Coordinate{Lat: 52.36977, Lon: 4.90012}, Elevation: 12},
+	})
+
+	coords := Decode3D(encoded)
+	if len(coords) != 3 {
+		t.Fatalf("expected 3 coordinates, got %d", len(coords))
+	}
+	want := []float64{1.5, -2.25, 12}
+	for i, c := range coords {
+		if math.Abs(c.Elevation-want[i]) > 1e-9 {
+			t.Errorf("coordinate %d: expected elevation %v, got %v", i, want[i], c.Elevation)
+		}
+	}
+	if math.Abs(coords[2].Lat-52.36977) > 1e-9 || math.Abs(coords[2].Lon-4.90012) > 1e-9 {
+		t.Errorf("unexpected last coordinate %+v", coords[2].Coordinate)
+	}
+
+	// The 2D decoder misreads the elevation as coordinates
+	if len(Decode(encoded)) == 3 {
+		t.Error("expected 2D decoding of a 3D polyline to differ")
+	}
+
+	flat := Flatten(coords)
+	if Encode(flat) != Encode([]Coordinate{coords[0].Coordinate, coords[1].Coordinate, coords[2].Coordinate}) {
+		t.Error("expected Flatten to keep lat/lon")
+	}
+	if Decode3D("") != nil {
+		t.Error("expected nil for empty string")
+	}
+}
+
+// encode3D encodes coordinates with elevation in the ORS 3D polyline format.
+func encode3D(coords []Coordinate3D) string {
+	var buf []byte
+	prevLat, prevLon, prevEle := 0, 0, 0
+	for _, c := range coords {
+		lat := int(math.Round(c.Lat * 1e5))
+		lon := int(math.Round(c.Lon * 1e5))
+		ele := int(math.Round(c.Elevation * 1e2))
+		buf = encodeValue(buf, lat-prevLat)
+		buf = encodeValue(buf, lon-prevLon)
+		buf = encodeValue(buf, ele-prevEle)
+		prevLat, prevLon, prevEle = lat, lon, ele
+	}
+	return string(buf)
+}
+
+// orsGeometry is a route geometry as openrouteservice returns it with
+// geometry_format "encodedpolyline": Amsterdam Centraal to Dam square by bike,
+// at ORS's precision of 5 decimal places.
+const orsGeometry = "}fu~Hi`|\\rAd@rCvFlF~G|E`GxE|F~ClDrAhD"
+
+var orsGeometryPoints = []Coordinate{
+	{Lat: 52.37887, Lon: 4.90005},
+	{Lat: 52.37845, Lon: 4.89986},
+	{Lat: 52.37771, Lon: 4.89862},
+	{Lat: 52.37652, Lon: 4.89718},
+	{Lat: 52.37541, Lon: 4.89589},
+	{Lat: 52.37432, Lon: 4.89462},
+	{Lat: 52.37352, Lon: 4.89375},
+	{Lat: 52.37310, Lon: 4.89290},
+}
+
+func TestDecode_ORSGeometry(t *testing.T) {
+	coords := Decode(orsGeometry)
+	if len(coords) != len(orsGeometryPoints) {
+		t.Fatalf("expected %d coordinates, got %d", len(orsGeometryPoints), len(coords))
+	}
+	for i, coord := range coords {
+		if !coordsEqual(coord, orsGeometryPoints[i], 1e-9) {
+			t.Errorf("coordinate %d: expected %+v, got %+v", i, orsGeometryPoints[i], coord)
+		}
+	}
+
+	if encoded := Encode(orsGeometryPoints); encoded != orsGeometry {
+		t.Errorf("expected re-encoding to match ORS output, got %q", encoded)
+	}
+}
+
+func TestResample_ORSGeometry(t *testing.T) {
+	coords := Decode(orsGeometry)
+	resampled := Resample(coords, 100)
+
+	// ~810m of route gives a point every 100m plus both ends
+	length := Length(coords)
+	if want := int(length/100) + 2; len(resampled) != want {
+		t.Fatalf("expected %d points over %.0fm, got %d", want, length, len(resampled))
+	}
+	if resampled[0] != coords[0] || resampled[len(resampled)-1] != coords[len(coords)-1] {
+		t.Error("expected resampling to keep the route's endpoints")
+	}
+	for i := 1; i < len(resampled)-1; i++ {
+		along := PointAt(coords, float64(i)*100)
+		if !coordsEqual(resampled[i], along, 1e-6) {
+			t.Errorf("point %d: expected %+v at %dm, got %+v", i, along, i*100, resampled[i])
+		}
+	}
+}